@@ -0,0 +1,82 @@
+package bloblang
+
+import (
+	"sync"
+
+	"github.com/Jeffail/benthos/v3/internal/bloblang/mapping"
+	"github.com/Jeffail/benthos/v3/internal/bloblang/query"
+)
+
+type sharedMappingKey struct {
+	functions  *query.FunctionSet
+	methods    *query.MethodSet
+	mappingDir string
+	constants  uint64
+	path       string
+	blobl      string
+}
+
+type sharedMapping struct {
+	exec *mapping.Executor
+	refs int
+}
+
+// sharedMappings holds the executors of mappings that are currently in use,
+// allowing components that execute the same mapping across many threads to
+// parse it only once.
+var sharedMappings = struct {
+	mut      sync.Mutex
+	mappings map[sharedMappingKey]*sharedMapping
+}{
+	mappings: map[sharedMappingKey]*sharedMapping{},
+}
+
+// NewSharedMapping parses a Bloblang mapping using the Environment, where the
+// resulting executor is shared with any other callers that have parsed the same
+// mapping within an equivalent environment and are still using it. Executors
+// are safe for concurrent use, and therefore sharing them avoids the cost of
+// parsing and holding the same mapping once per thread. Mappings parsed before
+// the constants were last replaced are not shared.
+//
+// The returned func must be called once the executor is no longer needed, and
+// the executor is discarded once all callers have done so.
+//
+// When a parsing error occurs the error will be the type *parser.Error.
+func (e *Environment) NewSharedMapping(path, blobl string) (*mapping.Executor, func(), error) {
+	if e == nil {
+		e = GlobalEnvironment()
+	}
+	key := sharedMappingKey{
+		functions:  e.functions,
+		methods:    e.methods,
+		mappingDir: e.mappingDir,
+		constants:  query.ConstantsVersion(),
+		path:       path,
+		blobl:      blobl,
+	}
+
+	sharedMappings.mut.Lock()
+	defer sharedMappings.mut.Unlock()
+
+	m, exists := sharedMappings.mappings[key]
+	if !exists {
+		exec, err := e.NewMapping(path, blobl)
+		if err != nil {
+			return nil, nil, err
+		}
+		m = &sharedMapping{exec: exec}
+		sharedMappings.mappings[key] = m
+	}
+	m.refs++
+
+	var releaseOnce sync.Once
+	return m.exec, func() {
+		releaseOnce.Do(func() {
+			sharedMappings.mut.Lock()
+			defer sharedMappings.mut.Unlock()
+			if m.refs--; m.refs == 0 {
+				delete(sharedMappings.mappings, key)
+			}
+		})
+	}, nil
+}
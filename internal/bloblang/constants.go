@@ -0,0 +1,32 @@
+package bloblang
+
+import (
+	"fmt"
+
+	"github.com/Jeffail/benthos/v3/internal/bloblang/query"
+	"github.com/Jeffail/benthos/v3/lib/message"
+)
+
+// SetConstants executes a map of Bloblang queries once each, without a message
+// to query, and sets the results as constants that can be referenced by name
+// from any mapping or interpolation with the function `constant`.
+func SetConstants(queries map[string]string) error {
+	values := make(map[string]interface{}, len(queries))
+	for name, q := range queries {
+		exec, err := NewMapping("", q)
+		if err != nil {
+			return fmt.Errorf("failed to parse constant %v: %w", name, err)
+		}
+		v, err := exec.Exec(query.FunctionContext{
+			Maps:     exec.Maps(),
+			Vars:     map[string]interface{}{},
+			MsgBatch: message.New(nil),
+		})
+		if err != nil {
+			return fmt.Errorf("failed to compute constant %v: %w", name, err)
+		}
+		values[name] = v
+	}
+	query.SetConstants(values)
+	return nil
+}
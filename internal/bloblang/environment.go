@@ -10,8 +10,9 @@ import (
 // Environment provides an isolated Bloblang environment where the available
 // features, functions and methods can be modified.
 type Environment struct {
-	functions *query.FunctionSet
-	methods   *query.MethodSet
+	functions  *query.FunctionSet
+	methods    *query.MethodSet
+	mappingDir string
 }
 
 // GlobalEnvironment returns the global default environment. Modifying this
@@ -49,17 +50,25 @@ func NewEmptyEnvironment() *Environment {
 	}
 }
 
+func (e *Environment) parserContext() parser.Context {
+	pCtx := parser.GlobalContext()
+	if e != nil {
+		pCtx.Functions = e.functions
+		pCtx.Methods = e.methods
+		if e.mappingDir != "" {
+			pCtx = pCtx.WithMappingDir(e.mappingDir)
+		}
+	}
+	return pCtx
+}
+
 // NewField attempts to parse and create a dynamic field expression from a
 // string. If the expression is invalid an error is returned.
 //
 // When a parsing error occurs the returned error will be a *parser.Error type,
 // which allows you to gain positional and structured error messages.
 func (e *Environment) NewField(expr string) (*field.Expression, error) {
-	pCtx := parser.GlobalContext()
-	if e != nil {
-		pCtx.Functions = e.functions
-		pCtx.Methods = e.methods
-	}
+	pCtx := e.parserContext()
 	f, err := parser.ParseField(pCtx, expr)
 	if err != nil {
 		return nil, err
@@ -74,11 +83,7 @@ func (e *Environment) NewField(expr string) (*field.Expression, error) {
 // gives access to the line and column where the error occurred, as well as a
 // method for creating a well formatted error message.
 func (e *Environment) NewMapping(path, blobl string) (*mapping.Executor, error) {
-	pCtx := parser.GlobalContext()
-	if e != nil {
-		pCtx.Functions = e.functions
-		pCtx.Methods = e.methods
-	}
+	pCtx := e.parserContext()
 	exec, err := parser.ParseMapping(pCtx, path, blobl)
 	if err != nil {
 		return nil, err
@@ -86,6 +91,33 @@ func (e *Environment) NewMapping(path, blobl string) (*mapping.Executor, error)
 	return exec, nil
 }
 
+// CheckMapping parses a Bloblang mapping using the Environment and returns any
+// warnings found within it, which describe potential problems that do not
+// prevent the mapping from being executed.
+//
+// When a parsing error occurs the error will be the type *parser.Error.
+func (e *Environment) CheckMapping(path, blobl string) ([]parser.Warning, error) {
+	pCtx := e.parserContext()
+	_, warnings, err := parser.ParseMappingWithWarnings(pCtx, path, blobl)
+	if err != nil {
+		return nil, err
+	}
+	return warnings, nil
+}
+
+// FormatMapping parses a Bloblang mapping using the Environment and returns it
+// formatted in the canonical style.
+//
+// When a parsing error occurs the error will be the type *parser.Error.
+func (e *Environment) FormatMapping(path, blobl string) (string, error) {
+	pCtx := e.parserContext()
+	formatted, err := parser.FormatMapping(pCtx, path, blobl)
+	if err != nil {
+		return "", err
+	}
+	return formatted, nil
+}
+
 // RegisterMethod adds a new Bloblang method to the environment.
 func (e *Environment) RegisterMethod(spec query.MethodSpec, ctor query.MethodCtor) error {
 	return e.methods.Add(spec, ctor)
@@ -96,13 +128,26 @@ func (e *Environment) RegisterFunction(spec query.FunctionSpec, ctor query.Funct
 	return e.functions.Add(spec, ctor)
 }
 
+// RegisterMethodAlias adds a deprecated alias to the environment that forwards
+// to an existing method.
+func (e *Environment) RegisterMethodAlias(alias, target string) error {
+	return e.methods.AddAlias(alias, target)
+}
+
+// RegisterFunctionAlias adds a deprecated alias to the environment that
+// forwards to an existing function.
+func (e *Environment) RegisterFunctionAlias(alias, target string) error {
+	return e.functions.AddAlias(alias, target)
+}
+
 // WithoutMethods returns a copy of the environment but with a variadic list of
 // method names removed. Instantiation of these removed methods within a mapping
 // will cause errors at parse time.
 func (e *Environment) WithoutMethods(names ...string) *Environment {
 	return &Environment{
-		functions: e.functions,
-		methods:   e.methods.Without(names...),
+		functions:  e.functions,
+		methods:    e.methods.Without(names...),
+		mappingDir: e.mappingDir,
 	}
 }
 
@@ -111,7 +156,20 @@ func (e *Environment) WithoutMethods(names ...string) *Environment {
 // mapping will cause errors at parse time.
 func (e *Environment) WithoutFunctions(names ...string) *Environment {
 	return &Environment{
-		functions: e.functions.Without(names...),
-		methods:   e.methods,
+		functions:  e.functions.Without(names...),
+		methods:    e.methods,
+		mappingDir: e.mappingDir,
+	}
+}
+
+// WithMappingDir returns a copy of the environment where relative paths
+// referenced by mappings, such as those of the file function, are resolved
+// from the provided directory. This is used for mappings embedded within a
+// config in order to resolve paths from the directory of the config file.
+func (e *Environment) WithMappingDir(dir string) *Environment {
+	return &Environment{
+		functions:  e.functions,
+		methods:    e.methods,
+		mappingDir: dir,
 	}
 }
@@ -60,6 +60,92 @@ func (v *VarAssignment) Target() TargetPath {
 
 //------------------------------------------------------------------------------
 
+// DestructureAssignment creates variables from the fields of an object or the
+// elements of an array.
+type DestructureAssignment struct {
+	names []string
+	array bool
+}
+
+// NewObjectDestructureAssignment creates a new assignment that assigns each
+// field of an object value to a variable of the same name. Fields that do not
+// exist within the object are assigned null.
+func NewObjectDestructureAssignment(names ...string) *DestructureAssignment {
+	return &DestructureAssignment{
+		names: names,
+	}
+}
+
+// NewArrayDestructureAssignment creates a new assignment that assigns the
+// elements of an array value to variables in order, where an element is
+// skipped when its name is `_`. Elements that do not exist within the array are
+// assigned null.
+func NewArrayDestructureAssignment(names ...string) *DestructureAssignment {
+	return &DestructureAssignment{
+		names: names,
+		array: true,
+	}
+}
+
+// Apply a value to the variables.
+func (d *DestructureAssignment) Apply(value interface{}, ctx AssignmentContext) error {
+	if _, deleted := value.(query.Delete); deleted {
+		for _, name := range d.names {
+			delete(ctx.Vars, name)
+		}
+		return nil
+	}
+	if d.array {
+		arr, ok := value.([]interface{})
+		if !ok {
+			return query.NewTypeError(value, query.ValueArray)
+		}
+		for i, name := range d.names {
+			if name == "_" {
+				continue
+			}
+			var v interface{}
+			if i < len(arr) {
+				v = arr[i]
+			}
+			ctx.Vars[name] = v
+		}
+		return nil
+	}
+	obj, ok := value.(map[string]interface{})
+	if !ok {
+		return query.NewTypeError(value, query.ValueObject)
+	}
+	for _, name := range d.names {
+		ctx.Vars[name] = obj[name]
+	}
+	return nil
+}
+
+// Target returns a representation of what the assignment targets, which is the
+// first variable assigned.
+func (d *DestructureAssignment) Target() TargetPath {
+	targets := d.Targets()
+	if len(targets) == 0 {
+		return NewTargetPath(TargetVariable)
+	}
+	return targets[0]
+}
+
+// Targets returns a representation of all variables that the assignment
+// targets.
+func (d *DestructureAssignment) Targets() []TargetPath {
+	targets := make([]TargetPath, 0, len(d.names))
+	for _, name := range d.names {
+		if name != "_" {
+			targets = append(targets, NewTargetPath(TargetVariable, name))
+		}
+	}
+	return targets
+}
+
+//------------------------------------------------------------------------------
+
 // MetaAssignment assigns a value to a metadata key of a message. If the key is
 // omitted and the value is an object then the metadata of the message is reset
 // to the contents of the value.
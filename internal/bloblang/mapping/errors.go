@@ -0,0 +1,114 @@
+package mapping
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/Jeffail/benthos/v3/internal/bloblang/query"
+)
+
+// StatementError is returned when a statement of a mapping fails, and
+// describes the location within the mapping of the expression that caused it.
+type StatementError struct {
+	// The line and column of the expression that failed, or of the statement
+	// when the expression is unknown. Both are zero when the input of the
+	// mapping is unknown.
+	Line   int
+	Column int
+
+	// Source is the full line of the mapping that the expression resides on.
+	Source string
+
+	// Err is the underlying error returned by the statement.
+	Err error
+
+	desc     string
+	stmtLine int
+}
+
+// Error implements the standard error interface.
+func (s *StatementError) Error() string {
+	return fmt.Sprintf("%v (line %v): %v", s.desc, s.stmtLine, s.Err)
+}
+
+// Unwrap returns the underlying error of the statement.
+func (s *StatementError) Unwrap() error {
+	return s.Err
+}
+
+// ErrorAtPositionStructured returns a human readable error string including the
+// line and character position of the failed expression along with a snippet
+// of the mapping, this message isn't appropriate to write within structured
+// logs as the formatting will be broken.
+func (s *StatementError) ErrorAtPositionStructured(filepath string) string {
+	filepathStr := ""
+	if len(filepath) > 0 {
+		filepathStr = filepath + ": "
+	}
+	if s.Line == 0 {
+		return filepathStr + s.Error()
+	}
+
+	contextLine := []rune(s.Source)
+	if maxLen := s.Column + 59; len(contextLine) > maxLen {
+		contextLine = contextLine[:maxLen]
+	}
+
+	// Statements of nested mappings describe their own lines, which are
+	// redundant since the location describes the expression that failed.
+	err := s.Err
+	for {
+		inner, ok := err.(*StatementError)
+		if !ok {
+			break
+		}
+		err = inner.Err
+	}
+
+	lineStr := strconv.FormatInt(int64(s.Line), 10)
+	linePadding := strings.Repeat(" ", len(lineStr))
+
+	return fmt.Sprintf(`%vline %v char %v: %v
+%v |
+%v | %v
+%v | %v^---`,
+		filepathStr,
+		lineStr, s.Column, err,
+		linePadding,
+		lineStr, string(contextLine),
+		linePadding, strings.Repeat(" ", s.Column-1))
+}
+
+// newStatementError creates a StatementError for a statement of the executor,
+// where the location of the failed expression is resolved from the error when
+// it carries one.
+func (e *Executor) newStatementError(desc string, stmt *Statement, err error) *StatementError {
+	sErr := &StatementError{
+		Err:  err,
+		desc: desc,
+	}
+	if len(e.input) == 0 || len(stmt.input) == 0 {
+		return sErr
+	}
+	sErr.stmtLine, _ = LineAndColOf(e.input, stmt.input)
+
+	at := stmt.input
+	if errInput := query.ErrInput(err); isTailOf(e.input, errInput) {
+		at = errInput
+	}
+	sErr.Line, sErr.Column = LineAndColOf(e.input, at)
+	if lines := strings.Split(string(e.input), "\n"); sErr.Line <= len(lines) {
+		sErr.Source = lines[sErr.Line-1]
+	}
+	return sErr
+}
+
+// isTailOf returns true if a clip is a tail of an input, which is only the case
+// when the clip was taken from the same input.
+func isTailOf(input, clip []rune) bool {
+	if len(clip) == 0 || len(clip) > len(input) {
+		return false
+	}
+	return &input[len(input)-len(clip)] == &clip[0]
+}
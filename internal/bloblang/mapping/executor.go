@@ -45,6 +45,7 @@ type Statement struct {
 	input      []rune
 	assignment Assignment
 	query      query.Function
+	tryCatch   *tryCatch
 }
 
 // NewStatement initialises a new mapping statement from an Assignment and
@@ -52,10 +53,47 @@ type Statement struct {
 // parsed expression that created the statement.
 func NewStatement(input []rune, assignment Assignment, query query.Function) Statement {
 	return Statement{
-		input, assignment, query,
+		input:      input,
+		assignment: assignment,
+		query:      query,
 	}
 }
 
+// Input returns the parsed expression that created the statement, which might
+// be empty.
+func (s *Statement) Input() []rune {
+	return s.input
+}
+
+// Query returns the query function of the statement, or nil if the statement
+// is a try catch block.
+func (s *Statement) Query() query.Function {
+	if s.tryCatch != nil {
+		return nil
+	}
+	return s.query
+}
+
+func (s *Statement) queryTargets(ctx query.TargetsContext) []query.TargetPath {
+	if s.tryCatch != nil {
+		return s.tryCatch.queryTargets(ctx)
+	}
+	_, paths := s.query.QueryTargets(ctx)
+	return paths
+}
+
+// AssignmentTargets returns a slice of all targets assigned to by the
+// statement.
+func (s *Statement) AssignmentTargets() []TargetPath {
+	if s.tryCatch != nil {
+		return s.tryCatch.assignmentTargets()
+	}
+	if d, ok := s.assignment.(*DestructureAssignment); ok {
+		return d.Targets()
+	}
+	return []TargetPath{s.assignment.Target()}
+}
+
 //------------------------------------------------------------------------------
 
 // Executor is a parsed bloblang mapping that can be executed on a Benthos
@@ -157,22 +195,35 @@ func (e *Executor) mapPart(appendTo types.Part, index int, reference Message) (t
 	vars := map[string]interface{}{}
 
 	for _, stmt := range e.statements {
-		res, err := stmt.query.Exec(query.FunctionContext{
+		ctx := query.FunctionContext{
 			Maps:     e.maps,
 			Vars:     vars,
 			Index:    index,
 			MsgBatch: reference,
 			NewMsg:   newPart,
-		}.WithValueFunc(lazyValue))
-		if err != nil {
-			var line int
-			if len(e.input) > 0 && len(stmt.input) > 0 {
-				line, _ = LineAndColOf(e.input, stmt.input)
+		}.WithValueFunc(lazyValue)
+		if stmt.tryCatch != nil {
+			failed, err := stmt.tryCatch.exec(ctx, AssignmentContext{
+				Vars:  vars,
+				Meta:  newPart.Metadata(),
+				Value: &newValue,
+			})
+			if err != nil {
+				sErr := e.newStatementError("failed assignment", failed, err)
+				if parseErr != nil && errors.Is(err, query.ErrNoContext) {
+					sErr.Err = fmt.Errorf("unable to reference message as structured (with 'this'): %w", parseErr)
+				}
+				return nil, sErr
 			}
+			continue
+		}
+		res, err := stmt.query.Exec(ctx)
+		if err != nil {
+			sErr := e.newStatementError("failed assignment", &stmt, err)
 			if parseErr != nil && errors.Is(err, query.ErrNoContext) {
-				err = fmt.Errorf("unable to reference message as structured (with 'this'): %w", parseErr)
+				sErr.Err = fmt.Errorf("unable to reference message as structured (with 'this'): %w", parseErr)
 			}
-			return nil, fmt.Errorf("failed assignment (line %v): %w", line, err)
+			return nil, sErr
 		}
 		if _, isNothing := res.(query.Nothing); isNothing {
 			// Skip assignment entirely
@@ -183,11 +234,7 @@ func (e *Executor) mapPart(appendTo types.Part, index int, reference Message) (t
 			Meta:  newPart.Metadata(),
 			Value: &newValue,
 		}); err != nil {
-			var line int
-			if len(e.input) > 0 && len(stmt.input) > 0 {
-				line, _ = LineAndColOf(e.input, stmt.input)
-			}
-			return nil, fmt.Errorf("failed to assign result (line %v): %w", line, err)
+			return nil, e.newStatementError("failed to assign result", &stmt, err)
 		}
 	}
 
@@ -221,8 +268,7 @@ func (e *Executor) QueryTargets(ctx query.TargetsContext) (query.TargetsContext,
 
 	var paths []query.TargetPath
 	for _, stmt := range e.statements {
-		_, tmpPaths := stmt.query.QueryTargets(childCtx)
-		paths = append(paths, tmpPaths...)
+		paths = append(paths, stmt.queryTargets(childCtx)...)
 	}
 
 	return ctx, paths
@@ -233,7 +279,7 @@ func (e *Executor) QueryTargets(ctx query.TargetsContext) (query.TargetsContext,
 func (e *Executor) AssignmentTargets() []TargetPath {
 	var paths []TargetPath
 	for _, stmt := range e.statements {
-		paths = append(paths, stmt.assignment.Target())
+		paths = append(paths, stmt.AssignmentTargets()...)
 	}
 	return paths
 }
@@ -249,17 +295,23 @@ func (e *Executor) Exec(ctx query.FunctionContext) (interface{}, error) {
 
 	var newObj interface{} = query.Nothing(nil)
 	for _, stmt := range e.statements {
+		if stmt.tryCatch != nil {
+			failed, err := stmt.tryCatch.exec(ctx, AssignmentContext{
+				Vars:  ctx.Vars,
+				Value: &newObj,
+			})
+			if err != nil {
+				return nil, e.newStatementError("failed assignment", failed, err)
+			}
+			continue
+		}
 		res, err := stmt.query.Exec(ctx)
 		if err != nil {
 			// TODO: Do this betterly
 			if strings.HasPrefix(err.Error(), "failed assignment") {
 				return nil, err
 			}
-			var line int
-			if len(e.input) > 0 && len(stmt.input) > 0 {
-				line, _ = LineAndColOf(e.input, stmt.input)
-			}
-			return nil, fmt.Errorf("failed assignment (line %v): %w", line, err)
+			return nil, e.newStatementError("failed assignment", &stmt, err)
 		}
 		if _, isNothing := res.(query.Nothing); isNothing {
 			// Skip assignment entirely
@@ -270,11 +322,7 @@ func (e *Executor) Exec(ctx query.FunctionContext) (interface{}, error) {
 			// Meta: meta, Prevented for now due to .from(int)
 			Value: &newObj,
 		}); err != nil {
-			var line int
-			if len(e.input) > 0 && len(stmt.input) > 0 {
-				line, _ = LineAndColOf(e.input, stmt.input)
-			}
-			return nil, fmt.Errorf("failed to assign result (line %v): %w", line, err)
+			return nil, e.newStatementError("failed to assign result", &stmt, err)
 		}
 	}
 
@@ -284,24 +332,22 @@ func (e *Executor) Exec(ctx query.FunctionContext) (interface{}, error) {
 // ExecOnto a provided assignment context.
 func (e *Executor) ExecOnto(ctx query.FunctionContext, onto AssignmentContext) error {
 	for _, stmt := range e.statements {
+		if stmt.tryCatch != nil {
+			if failed, err := stmt.tryCatch.exec(ctx, onto); err != nil {
+				return e.newStatementError("failed assignment", failed, err)
+			}
+			continue
+		}
 		res, err := stmt.query.Exec(ctx)
 		if err != nil {
-			var line int
-			if len(e.input) > 0 && len(stmt.input) > 0 {
-				line, _ = LineAndColOf(e.input, stmt.input)
-			}
-			return fmt.Errorf("failed assignment (line %v): %w", line, err)
+			return e.newStatementError("failed assignment", &stmt, err)
 		}
 		if _, isNothing := res.(query.Nothing); isNothing {
 			// Skip assignment entirely
 			continue
 		}
 		if err = stmt.assignment.Apply(res, onto); err != nil {
-			var line int
-			if len(e.input) > 0 && len(stmt.input) > 0 {
-				line, _ = LineAndColOf(e.input, stmt.input)
-			}
-			return fmt.Errorf("failed to assign result (line %v): %w", line, err)
+			return e.newStatementError("failed to assign result", &stmt, err)
 		}
 	}
 	return nil
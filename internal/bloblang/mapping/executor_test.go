@@ -100,6 +100,24 @@ func TestAssignments(t *testing.T) {
 			input:  []part{{Content: `{}`}},
 			output: &part{Content: `{"foo":"does exist"}`},
 		},
+		"destructured variable assignment": {
+			mapping: NewExecutor("", nil, nil,
+				NewStatement(nil, NewObjectDestructureAssignment("foo", "bar"), query.NewFieldFunction("")),
+				NewStatement(nil, NewArrayDestructureAssignment("_", "baz"), query.NewFieldFunction("buz")),
+				NewStatement(nil, NewJSONAssignment("foo"), query.NewVarFunction("foo")),
+				NewStatement(nil, NewJSONAssignment("bar"), query.NewVarFunction("bar")),
+				NewStatement(nil, NewJSONAssignment("baz"), query.NewVarFunction("baz")),
+			),
+			input:  []part{{Content: `{"foo":"first","buz":["second","third"]}`}},
+			output: &part{Content: `{"bar":null,"baz":"third","foo":"first"}`},
+		},
+		"destructured variable wrong type": {
+			mapping: NewExecutor("", nil, nil,
+				NewStatement(nil, NewArrayDestructureAssignment("foo"), query.NewFieldFunction("")),
+			),
+			input: []part{{Content: `{"foo":"first"}`}},
+			err:   errors.New("failed to assign result (line 0): expected array value, got object"),
+		},
 		"meta query error": {
 			mapping: NewExecutor("", nil, nil,
 				NewStatement(nil, NewJSONAssignment("foo"), initFunc("meta", "foo")),
@@ -326,6 +344,23 @@ func TestTargets(t *testing.T) {
 				NewTargetPath(TargetVariable, "baz"),
 			},
 		},
+		{
+			mapping: NewExecutor("", nil, nil,
+				NewTryCatchStatement(nil, []Statement{
+					NewStatement(nil, NewJSONAssignment("foo"), query.NewFieldFunction("first")),
+				}, "err", []Statement{
+					NewStatement(nil, NewMetaAssignment(metaKey("bar")), function("meta", "second")),
+				}),
+			),
+			queryTargets: []query.TargetPath{
+				query.NewTargetPath(query.TargetValue, "first"),
+				query.NewTargetPath(query.TargetMetadata, "second"),
+			},
+			assignmentTargets: []TargetPath{
+				NewTargetPath(TargetValue, "foo"),
+				NewTargetPath(TargetMetadata, "bar"),
+			},
+		},
 	}
 
 	for i, test := range tests {
@@ -1,5 +1,9 @@
 package mapping
 
+import (
+	"github.com/Jeffail/benthos/v3/internal/bloblang/query"
+)
+
 // TargetType represents a mapping target type, which is a destination for a
 // query result to be mapped into a message.
 type TargetType int
@@ -26,3 +30,20 @@ func NewTargetPath(t TargetType, path ...string) TargetPath {
 		Path: path,
 	}
 }
+
+// String returns the target path as it would be written in a mapping.
+func (t TargetPath) String() string {
+	switch t.Type {
+	case TargetMetadata:
+		if len(t.Path) == 0 {
+			return "meta"
+		}
+		return "meta " + t.Path[0]
+	case TargetVariable:
+		return "$" + query.SliceToDotPath(t.Path...)
+	}
+	if len(t.Path) == 0 {
+		return "root"
+	}
+	return "root." + query.SliceToDotPath(t.Path...)
+}
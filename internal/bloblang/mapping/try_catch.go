@@ -0,0 +1,100 @@
+package mapping
+
+import (
+	"github.com/Jeffail/benthos/v3/internal/bloblang/query"
+)
+
+// tryCatch is a block of statements that are executed in order until one
+// fails, at which point a block of catch statements are executed with details
+// of the error captured under a name.
+type tryCatch struct {
+	try     []Statement
+	errName string
+	catch   []Statement
+}
+
+// NewTryCatchStatement initialises a new mapping statement that executes a list
+// of statements until one fails, at which point a list of catch statements are
+// executed instead. Within the catch statements the error is available as an
+// object under the provided name, containing the fields `message` and `path`,
+// unless the name is `_`.
+//
+// Assignments made by the try statements prior to the error are kept.
+func NewTryCatchStatement(input []rune, try []Statement, errName string, catch []Statement) Statement {
+	return Statement{
+		input: input,
+		tryCatch: &tryCatch{
+			try:     try,
+			errName: errName,
+			catch:   catch,
+		},
+	}
+}
+
+// exec executes the try catch block, returning an error along with the
+// statement that caused it only if a catch statement fails.
+func (t *tryCatch) exec(ctx query.FunctionContext, onto AssignmentContext) (*Statement, error) {
+	for i := range t.try {
+		failed, err := execStatement(&t.try[i], ctx, onto)
+		if err == nil {
+			continue
+		}
+
+		catchCtx := ctx
+		if t.errName != "_" {
+			catchCtx = ctx.WithNamedValue(t.errName, map[string]interface{}{
+				"message": err.Error(),
+				"path":    failed.assignment.Target().String(),
+			})
+		}
+		for j := range t.catch {
+			if failed, err := execStatement(&t.catch[j], catchCtx, onto); err != nil {
+				return failed, err
+			}
+		}
+		return nil, nil
+	}
+	return nil, nil
+}
+
+// execStatement executes a statement and applies the result to an assignment
+// context, returning an error along with the statement that caused it.
+func execStatement(stmt *Statement, ctx query.FunctionContext, onto AssignmentContext) (*Statement, error) {
+	if stmt.tryCatch != nil {
+		return stmt.tryCatch.exec(ctx, onto)
+	}
+	res, err := stmt.query.Exec(ctx)
+	if err != nil {
+		return stmt, err
+	}
+	if _, isNothing := res.(query.Nothing); isNothing {
+		// Skip assignment entirely
+		return nil, nil
+	}
+	if err = stmt.assignment.Apply(res, onto); err != nil {
+		return stmt, err
+	}
+	return nil, nil
+}
+
+func (t *tryCatch) queryTargets(ctx query.TargetsContext) []query.TargetPath {
+	var paths []query.TargetPath
+	for _, stmt := range t.try {
+		paths = append(paths, stmt.queryTargets(ctx)...)
+	}
+	for _, stmt := range t.catch {
+		paths = append(paths, stmt.queryTargets(ctx)...)
+	}
+	return paths
+}
+
+func (t *tryCatch) assignmentTargets() []TargetPath {
+	var paths []TargetPath
+	for _, stmt := range t.try {
+		paths = append(paths, stmt.AssignmentTargets()...)
+	}
+	for _, stmt := range t.catch {
+		paths = append(paths, stmt.AssignmentTargets()...)
+	}
+	return paths
+}
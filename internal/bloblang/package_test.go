@@ -1,6 +1,8 @@
 package bloblang
 
 import (
+	"io/ioutil"
+	"path/filepath"
 	"sync"
 	"testing"
 
@@ -164,3 +166,146 @@ func TestMappingParallelExecution(t *testing.T) {
 		})
 	}
 }
+
+func TestSetConstants(t *testing.T) {
+	t.Cleanup(func() {
+		query.SetConstants(nil)
+	})
+
+	lateExec, err := NewMapping("", `root = constant("foo")`)
+	require.NoError(t, err)
+
+	require.NoError(t, SetConstants(map[string]string{
+		"foo": `"static foo"`,
+		"bar": `{"a":[1,2]}.a.sum()`,
+	}))
+
+	exec, err := NewMapping("", `root.foo = constant("foo")
+root.bar = constant("bar")
+root.baz = constant("baz").catch("nope")`)
+	require.NoError(t, err)
+
+	res, err := exec.Exec(query.FunctionContext{
+		MsgBatch: message.New(nil),
+		Vars:     map[string]interface{}{},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{
+		"foo": "static foo",
+		"bar": float64(3),
+		"baz": "nope",
+	}, res)
+
+	res, err = lateExec.Exec(query.FunctionContext{
+		MsgBatch: message.New(nil),
+		Vars:     map[string]interface{}{},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "static foo", res)
+
+	err = SetConstants(map[string]string{
+		"foo": `this.foo`,
+	})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "failed to compute constant foo")
+}
+
+func TestSharedMapping(t *testing.T) {
+	env := NewEnvironment()
+
+	execA, releaseA, err := env.NewSharedMapping("", `root = this.foo`)
+	require.NoError(t, err)
+
+	execB, releaseB, err := GlobalEnvironment().NewSharedMapping("", `root = this.foo`)
+	require.NoError(t, err)
+	assert.NotSame(t, execA, execB, "mappings from different environments should not be shared")
+
+	execC, releaseC, err := env.NewSharedMapping("", `root = this.foo`)
+	require.NoError(t, err)
+	assert.Same(t, execA, execC)
+
+	releaseA()
+	releaseA()
+
+	execD, releaseD, err := env.NewSharedMapping("", `root = this.foo`)
+	require.NoError(t, err)
+	assert.Same(t, execA, execD)
+
+	releaseC()
+	releaseD()
+
+	execE, releaseE, err := env.NewSharedMapping("", `root = this.foo`)
+	require.NoError(t, err)
+	assert.NotSame(t, execA, execE, "mapping should be parsed again once released by all callers")
+
+	releaseB()
+	releaseE()
+
+	_, _, err = env.NewSharedMapping("", `root = this.foo.`)
+	require.Error(t, err)
+}
+
+func TestSharedMappingConstants(t *testing.T) {
+	t.Cleanup(func() {
+		query.SetConstants(nil)
+	})
+
+	require.NoError(t, SetConstants(map[string]string{"foo": `"first"`}))
+
+	execA, releaseA, err := GlobalEnvironment().NewSharedMapping("", `root = constant("foo")`)
+	require.NoError(t, err)
+	defer releaseA()
+
+	require.NoError(t, SetConstants(map[string]string{"foo": `"second"`}))
+
+	execB, releaseB, err := GlobalEnvironment().NewSharedMapping("", `root = constant("foo")`)
+	require.NoError(t, err)
+	defer releaseB()
+	assert.NotSame(t, execA, execB, "mappings parsed with different constants should not be shared")
+
+	res, err := execB.Exec(query.FunctionContext{
+		MsgBatch: message.New(nil),
+		Vars:     map[string]interface{}{},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "second", res)
+}
+
+func TestEnvironmentMappingDir(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, ioutil.WriteFile(filepath.Join(dir, "lookup.txt"), []byte("foo"), 0644))
+
+	mapping := `root = file(path: "lookup.txt", relative_to_mapping: true).string()`
+
+	_, err := GlobalEnvironment().NewMapping("", mapping)
+	require.Error(t, err)
+
+	env := GlobalEnvironment().WithMappingDir(dir)
+	exec, err := env.NewMapping("", mapping)
+	require.NoError(t, err)
+
+	res, err := exec.MapPart(0, message.New([][]byte{[]byte("{}")}))
+	require.NoError(t, err)
+	assert.Equal(t, "foo", string(res.Get()))
+
+	// Restricting the environment retains the directory.
+	_, err = env.WithoutFunctions("env").NewMapping("", mapping)
+	require.NoError(t, err)
+
+	// The directory of a mapping file takes precedence over the environment.
+	_, err = env.NewMapping(filepath.Join(t.TempDir(), "mapping.blobl"), mapping)
+	require.Error(t, err)
+
+	// Shared mappings are only shared within environments of the same dir.
+	execA, releaseA, err := env.NewSharedMapping("", mapping)
+	require.NoError(t, err)
+	defer releaseA()
+
+	execB, releaseB, err := GlobalEnvironment().WithMappingDir(dir).NewSharedMapping("", mapping)
+	require.NoError(t, err)
+	defer releaseB()
+	assert.Same(t, execA, execB)
+
+	_, _, err = GlobalEnvironment().NewSharedMapping("", mapping)
+	require.Error(t, err)
+}
@@ -0,0 +1,126 @@
+package parser
+
+import (
+	"sort"
+	"sync/atomic"
+
+	"github.com/Jeffail/benthos/v3/internal/bloblang/mapping"
+	"github.com/Jeffail/benthos/v3/internal/bloblang/query"
+)
+
+// CoveragePoint is a statement or a branch of a mapping along with the number
+// of times it has been executed.
+//
+// The input at the point can be used in order to infer where exactly in the
+// input the point is with len(input) - len(p.Input).
+type CoveragePoint struct {
+	Input []rune
+	Kind  string
+	hits  int64
+}
+
+// Hits returns the number of times the point has been executed.
+func (p *CoveragePoint) Hits() int64 {
+	return atomic.LoadInt64(&p.hits)
+}
+
+// LineAndCol returns the line and column position of the point within the
+// input that was parsed.
+func (p *CoveragePoint) LineAndCol(input []rune) (line, col int) {
+	return LineAndColOf(input, p.Input)
+}
+
+// Coverage records how many times each statement and branch of a mapping has
+// been executed.
+type Coverage struct {
+	points []*CoveragePoint
+}
+
+// Points returns all statements and branches of the mapping in the order that
+// they appear.
+func (c *Coverage) Points() []*CoveragePoint {
+	points := make([]*CoveragePoint, len(c.points))
+	copy(points, c.points)
+	sort.SliceStable(points, func(i, j int) bool {
+		return len(points[i].Input) > len(points[j].Input)
+	})
+	return points
+}
+
+// Uncovered returns the statements and branches of the mapping that have not
+// yet been executed, in the order that they appear.
+func (c *Coverage) Uncovered() []*CoveragePoint {
+	var uncovered []*CoveragePoint
+	for _, p := range c.Points() {
+		if p.Hits() == 0 {
+			uncovered = append(uncovered, p)
+		}
+	}
+	return uncovered
+}
+
+// point returns the coverage point at a given input, creating it if it doesn't
+// yet exist. Parsers may attempt the same input more than once and therefore
+// points are shared by position.
+func (c *Coverage) point(input []rune, kind string) *CoveragePoint {
+	for _, p := range c.points {
+		if len(p.Input) == len(input) && p.Kind == kind {
+			return p
+		}
+	}
+	p := &CoveragePoint{Input: input, Kind: kind}
+	c.points = append(c.points, p)
+	return p
+}
+
+type coveredFunction struct {
+	query.Function
+	point *CoveragePoint
+}
+
+func (c *coveredFunction) Exec(ctx query.FunctionContext) (interface{}, error) {
+	atomic.AddInt64(&c.point.hits, 1)
+	return c.Function.Exec(ctx)
+}
+
+// covered wraps a function so that its executions are recorded as a coverage
+// point at the given input, if the context is collecting coverage.
+func (pCtx Context) covered(input []rune, kind string, fn query.Function) query.Function {
+	if pCtx.coverage == nil {
+		return fn
+	}
+	return &coveredFunction{
+		Function: fn,
+		point:    pCtx.coverage.point(input, kind),
+	}
+}
+
+// coveredQueryParser parses a query that is recorded as a coverage point when
+// the context is collecting coverage.
+func coveredQueryParser(kind string, pCtx Context) Func {
+	return func(input []rune) Result {
+		res := queryParser(pCtx)(input)
+		if res.Err == nil {
+			res.Payload = pCtx.covered(input, kind, res.Payload.(query.Function))
+		}
+		return res
+	}
+}
+
+// ParseMappingWithCoverage parses a bloblang mapping and returns an executor to
+// run it along with a record of which statements and branches of the mapping
+// have been executed, or an error if the parsing fails. Statements and branches
+// of imported files are not recorded.
+//
+// The filepath is optional and used for relative file imports and error
+// messages.
+func ParseMappingWithCoverage(pCtx Context, filepath, expr string) (*mapping.Executor, *Coverage, *Error) {
+	coverage := &Coverage{}
+	pCtx.coverage = coverage
+
+	exec, err := parseMapping(pCtx, filepath, expr)
+	if err != nil {
+		return nil, nil, err
+	}
+	return exec, coverage, nil
+}
@@ -126,6 +126,12 @@ func (e *Error) errorMsg(includeGot bool) string {
 	return msg
 }
 
+// LineAndCol returns the line and column position of the error within the input
+// that was parsed.
+func (e *Error) LineAndCol(input []rune) (line, col int) {
+	return LineAndColOf(input, e.Input)
+}
+
 // Error returns a human readable error string.
 func (e *Error) Error() string {
 	return e.errorMsg(true)
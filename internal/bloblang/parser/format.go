@@ -0,0 +1,441 @@
+package parser
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// FormatMaxLineWidth is the width beyond which a formatted line containing a
+// chain of method calls is wrapped.
+const FormatMaxLineWidth = 80
+
+type fmtTokenKind int
+
+const (
+	fmtWord fmtTokenKind = iota
+	fmtString
+	fmtComment
+	fmtNewline
+	fmtOp
+	fmtOpen
+	fmtClose
+	fmtComma
+	fmtColon
+	fmtDot
+)
+
+type fmtToken struct {
+	kind     fmtTokenKind
+	text     string
+	hadSpace bool
+
+	// Set for operators that are unary (! and -) and braces that delimit a
+	// block (if, match, map, etc) rather than an object literal.
+	unary bool
+	block bool
+
+	// Set for words that begin an expression and are therefore keywords
+	// rather than the segment of a path.
+	keyword bool
+}
+
+var fmtMultiCharOps = []string{"==", "!=", ">=", "<=", "&&", "||", "=>", "->"}
+
+var fmtKeywords = map[string]struct{}{
+	"if": {}, "else": {}, "match": {}, "while": {}, "let": {},
+}
+
+func isFmtWordChar(c rune) bool {
+	return (c >= 'a' && c <= 'z') ||
+		(c >= 'A' && c <= 'Z') ||
+		(c >= '0' && c <= '9') ||
+		c == '_'
+}
+
+// tokenizeForFormat splits a mapping into tokens where all whitespace other
+// than line breaks is discarded.
+func tokenizeForFormat(input []rune) ([]*fmtToken, error) {
+	var tokens []*fmtToken
+	hadSpace := false
+
+	add := func(kind fmtTokenKind, text string) {
+		tokens = append(tokens, &fmtToken{kind: kind, text: text, hadSpace: hadSpace})
+		hadSpace = false
+	}
+
+	for i := 0; i < len(input); {
+		c := input[i]
+		switch {
+		case c == ' ' || c == '\t' || c == '\r':
+			hadSpace = true
+			i++
+		case c == '\n':
+			add(fmtNewline, "\n")
+			i++
+		case c == '#':
+			j := i
+			for j < len(input) && input[j] != '\n' {
+				j++
+			}
+			add(fmtComment, strings.TrimRight(string(input[i:j]), " \t\r"))
+			i = j
+		case c == '"':
+			j := -1
+			if i+2 < len(input) && input[i+1] == '"' && input[i+2] == '"' {
+				if end := strings.Index(string(input[i+3:]), `"""`); end >= 0 {
+					j = i + 3 + len([]rune(string(input[i+3:])[:end])) + 3
+				}
+			}
+			if j < 0 {
+				escaped := false
+				for j = i + 1; j < len(input); j++ {
+					if input[j] == '"' && !escaped {
+						break
+					}
+					if input[j] == '\\' {
+						escaped = !escaped
+					} else {
+						escaped = false
+					}
+				}
+				if j >= len(input) {
+					return nil, errors.New("unterminated quoted string")
+				}
+				j++
+			}
+			add(fmtString, string(input[i:j]))
+			i = j
+		case isFmtWordChar(c) || c == '$':
+			j := i + 1
+			for j < len(input) && isFmtWordChar(input[j]) {
+				j++
+			}
+			add(fmtWord, string(input[i:j]))
+			i = j
+		case c == '(' || c == '[' || c == '{':
+			add(fmtOpen, string(c))
+			i++
+		case c == ')' || c == ']' || c == '}':
+			add(fmtClose, string(c))
+			i++
+		case c == ',':
+			add(fmtComma, ",")
+			i++
+		case c == ':':
+			add(fmtColon, ":")
+			i++
+		case c == '.':
+			add(fmtDot, ".")
+			i++
+		default:
+			op := string(c)
+			for _, m := range fmtMultiCharOps {
+				if strings.HasPrefix(string(input[i:]), m) {
+					op = m
+					break
+				}
+			}
+			add(fmtOp, op)
+			i += len([]rune(op))
+		}
+	}
+
+	classifyFormatTokens(tokens)
+	return tokens, nil
+}
+
+// classifyFormatTokens determines which operators are unary, which braces
+// delimit blocks and which words are keywords, based on the tokens preceding
+// them.
+func classifyFormatTokens(tokens []*fmtToken) {
+	var prev *fmtToken
+	var stack []*fmtToken
+	for _, t := range tokens {
+		switch t.kind {
+		case fmtNewline, fmtComment:
+			continue
+		case fmtWord:
+			if _, exists := fmtKeywords[t.text]; exists {
+				t.keyword = prev == nil || prev.kind != fmtDot
+			}
+		case fmtOp:
+			switch t.text {
+			case "!":
+				t.unary = true
+			case "-":
+				t.unary = prev == nil || prev.keyword || prev.unary ||
+					prev.kind == fmtOp ||
+					prev.kind == fmtOpen ||
+					prev.kind == fmtComma ||
+					prev.kind == fmtColon
+			}
+		case fmtOpen:
+			if t.text == "{" && prev != nil && !(prev.kind == fmtWord && prev.text == "let") {
+				switch prev.kind {
+				case fmtWord, fmtString, fmtClose:
+					t.block = true
+				}
+			}
+			stack = append(stack, t)
+		case fmtClose:
+			if len(stack) > 0 {
+				t.block = stack[len(stack)-1].block
+				stack = stack[:len(stack)-1]
+			}
+		}
+		prev = t
+	}
+}
+
+// fmtSpacing returns the whitespace that belongs between two tokens on the
+// same line.
+func fmtSpacing(a, b *fmtToken) string {
+	switch {
+	case b.kind == fmtComment:
+		return " "
+	case a.kind == fmtOpen:
+		if a.block && b.text != "}" {
+			return " "
+		}
+		return ""
+	case b.kind == fmtClose:
+		if b.block {
+			return " "
+		}
+		return ""
+	case b.kind == fmtComma || b.kind == fmtColon:
+		return ""
+	case a.kind == fmtComma || a.kind == fmtColon:
+		return " "
+	case a.kind == fmtDot || b.kind == fmtDot:
+		return ""
+	case a.kind == fmtOp && a.unary:
+		return ""
+	case a.kind == fmtOp || (b.kind == fmtOp && !b.unary):
+		return " "
+	case b.kind == fmtOpen && b.text == "(":
+		if a.keyword {
+			return " "
+		}
+		if a.kind == fmtWord || a.kind == fmtClose || a.kind == fmtString {
+			return ""
+		}
+	case b.kind == fmtOpen && b.block:
+		return " "
+	case a.kind == fmtClose && a.block && b.kind == fmtWord:
+		return " "
+	case a.kind == fmtWord && b.kind == fmtWord:
+		return " "
+	}
+	if b.hadSpace {
+		return " "
+	}
+	return ""
+}
+
+// isFmtContinuation returns true if a line ending with the token continues
+// onto the next line as part of the same expression.
+func isFmtContinuation(t *fmtToken) bool {
+	if t.kind == fmtDot {
+		return true
+	}
+	if t.kind != fmtOp || t.unary {
+		return false
+	}
+	switch t.text {
+	case "=", "=>", "->", "!":
+		return false
+	}
+	return true
+}
+
+type fmtFrame struct {
+	openIndent int
+	cont       int
+}
+
+type fmtLine struct {
+	indent int
+	tokens []*fmtToken
+}
+
+// layoutFormatLines splits tokens into lines, removing superfluous blank lines,
+// and determines the indentation of each line from the brackets that are open
+// at the beginning of it and whether it continues an expression from the line
+// before.
+func layoutFormatLines(tokens []*fmtToken) []fmtLine {
+	var lines []fmtLine
+	current := fmtLine{}
+	for _, t := range tokens {
+		if t.kind == fmtNewline {
+			if len(current.tokens) > 0 || (len(lines) > 0 && len(lines[len(lines)-1].tokens) > 0) {
+				lines = append(lines, current)
+			}
+			current = fmtLine{}
+			continue
+		}
+		current.tokens = append(current.tokens, t)
+	}
+	if len(current.tokens) > 0 {
+		lines = append(lines, current)
+	}
+	for len(lines) > 0 && len(lines[len(lines)-1].tokens) == 0 {
+		lines = lines[:len(lines)-1]
+	}
+
+	stack := []*fmtFrame{{openIndent: -1, cont: -1}}
+	for i := range lines {
+		line := &lines[i]
+		if len(line.tokens) == 0 {
+			continue
+		}
+
+		top := stack[len(stack)-1]
+		switch {
+		case line.tokens[0].kind == fmtClose && len(stack) > 1:
+			line.indent = top.openIndent
+		case top.cont >= 0:
+			line.indent = top.cont
+		default:
+			line.indent = top.openIndent + 1
+		}
+
+		var last *fmtToken
+		for _, t := range line.tokens {
+			switch t.kind {
+			case fmtOpen:
+				stack = append(stack, &fmtFrame{openIndent: line.indent, cont: -1})
+			case fmtClose:
+				if len(stack) > 1 {
+					stack = stack[:len(stack)-1]
+				}
+			}
+			if t.kind != fmtComment {
+				last = t
+			}
+		}
+		if last == nil || last.kind == fmtOpen {
+			continue
+		}
+
+		top = stack[len(stack)-1]
+		if isFmtContinuation(last) {
+			if top.cont < 0 {
+				top.cont = line.indent + 1
+			}
+		} else {
+			top.cont = -1
+		}
+	}
+	return lines
+}
+
+func renderFormatLine(line fmtLine, includeComments bool) string {
+	var b strings.Builder
+	b.WriteString(strings.Repeat("  ", line.indent))
+	var prev *fmtToken
+	for _, t := range line.tokens {
+		if t.kind == fmtComment && !includeComments {
+			continue
+		}
+		if prev != nil {
+			b.WriteString(fmtSpacing(prev, t))
+		}
+		b.WriteString(t.text)
+		prev = t
+	}
+	return b.String()
+}
+
+// wrapFormatLine returns the tokens of a line with line breaks added after each
+// dot that precedes a method call within the outermost chain of the line, as
+// long as the chain consists of at least two method calls.
+func wrapFormatLine(line fmtLine) []*fmtToken {
+	depth, minDepth := 0, 0
+	depths := make([]int, len(line.tokens))
+	for i, t := range line.tokens {
+		if t.kind == fmtString && strings.Contains(t.text, "\n") {
+			return line.tokens
+		}
+		depths[i] = depth
+		switch t.kind {
+		case fmtOpen:
+			depth++
+		case fmtClose:
+			depth--
+			if depth < minDepth {
+				minDepth = depth
+			}
+		}
+	}
+
+	breaks := map[int]struct{}{}
+	for i, t := range line.tokens {
+		if t.kind != fmtDot || depths[i] != minDepth || i == 0 || i+2 >= len(line.tokens) {
+			continue
+		}
+		if line.tokens[i-1].kind == fmtClose && line.tokens[i-1].text == ")" &&
+			line.tokens[i+1].kind == fmtWord &&
+			line.tokens[i+2].kind == fmtOpen && line.tokens[i+2].text == "(" {
+			breaks[i] = struct{}{}
+		}
+	}
+	if len(breaks) < 2 {
+		return line.tokens
+	}
+
+	var wrapped []*fmtToken
+	for i, t := range line.tokens {
+		wrapped = append(wrapped, t)
+		if _, exists := breaks[i]; exists {
+			wrapped = append(wrapped, &fmtToken{kind: fmtNewline, text: "\n"})
+		}
+	}
+	return wrapped
+}
+
+// FormatMapping returns a mapping formatted in the canonical style, where
+// lines are indented by two spaces for each level of nesting, tokens are
+// consistently spaced, superfluous blank lines are removed, and long chains of
+// method calls are wrapped. The mapping is parsed before being formatted, and
+// an error is returned if the parsing fails.
+//
+// The filepath is optional and used for relative file imports and error
+// messages.
+func FormatMapping(pCtx Context, filepath, expr string) (string, *Error) {
+	if _, err := ParseMapping(pCtx, filepath, expr); err != nil {
+		return "", err
+	}
+
+	tokens, terr := tokenizeForFormat([]rune(expr))
+	if terr != nil {
+		return "", NewFatalError([]rune(expr), terr)
+	}
+
+	var wrapped []*fmtToken
+	for _, line := range layoutFormatLines(tokens) {
+		if len([]rune(renderFormatLine(line, false))) > FormatMaxLineWidth {
+			wrapped = append(wrapped, wrapFormatLine(line)...)
+		} else {
+			wrapped = append(wrapped, line.tokens...)
+		}
+		wrapped = append(wrapped, &fmtToken{kind: fmtNewline, text: "\n"})
+	}
+
+	var b strings.Builder
+	for _, line := range layoutFormatLines(wrapped) {
+		if len(line.tokens) > 0 {
+			b.WriteString(renderFormatLine(line, true))
+		}
+		b.WriteString("\n")
+	}
+	formatted := b.String()
+
+	// Formatting only ever changes whitespace, and so this should never fail,
+	// but we'd rather report an error than produce a broken mapping.
+	if _, err := ParseMapping(pCtx, filepath, formatted); err != nil {
+		return "", NewFatalError([]rune(expr), fmt.Errorf("formatted mapping is invalid: %v", err.ErrorAtPosition([]rune(formatted))))
+	}
+	return formatted, nil
+}
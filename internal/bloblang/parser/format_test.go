@@ -0,0 +1,146 @@
+package parser
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFormatMapping(t *testing.T) {
+	tests := map[string]struct {
+		mapping string
+		output  string
+	}{
+		"already formatted": {
+			mapping: `root.foo = this.bar.uppercase()
+`,
+			output: `root.foo = this.bar.uppercase()
+`,
+		},
+		"spacing": {
+			mapping: `root.a   =  this.a+5-  -2
+root.b = {"a":1,"b":[1,2,-3]}
+root.c = this.c.replace("a","b")
+root.d = (this.a|this.b) # a comment   
+`,
+			output: `root.a = this.a + 5 - -2
+root.b = {"a": 1, "b": [1, 2, -3]}
+root.c = this.c.replace("a", "b")
+root.d = (this.a | this.b) # a comment
+`,
+		},
+		"blocks": {
+			mapping: `root.a = if this.x=={"a":1} {"yes"} else if !this.y{ "maybe" }else{"no"}
+root.b = match this.b{
+      "foo"=>"bar"
+   string s   =>s.uppercase()
+        _ => deleted()
+}
+map thing {
+root.x  = this.x
+}`,
+			output: `root.a = if this.x == {"a": 1} { "yes" } else if !this.y { "maybe" } else { "no" }
+root.b = match this.b {
+  "foo" => "bar"
+  string s => s.uppercase()
+  _ => deleted()
+}
+map thing {
+  root.x = this.x
+}
+`,
+		},
+		"blank lines": {
+			mapping: `
+
+
+root.a = this.a
+
+
+
+# comment
+root.b = this.b
+
+`,
+			output: `root.a = this.a
+
+# comment
+root.b = this.b
+`,
+		},
+		"nested brackets": {
+			mapping: `root = this.items.map_each(item -> {
+"name": item.name.trim(),
+      "value": [
+ item.value,
+ item.value * 2
+  ]
+}).sort_by(ele -> ele.name)`,
+			output: `root = this.items.map_each(item -> {
+  "name": item.name.trim(),
+  "value": [
+    item.value,
+    item.value * 2
+  ]
+}).sort_by(ele -> ele.name)
+`,
+		},
+		"method chain continuations": {
+			mapping: `root = this.locations.
+                filter(loc -> loc.state == "WA").
+                map_each(loc -> loc.name).
+                sort().join(", ")`,
+			output: `root = this.locations.
+  filter(loc -> loc.state == "WA").
+  map_each(loc -> loc.name).
+  sort().join(", ")
+`,
+		},
+		"long method chains are wrapped": {
+			mapping: `root.foo = this.a_long_field_name.another_field.uppercase().trim().replace("foo", "bar").split(",")
+root.bar = this.a_long_field_name.another_long_field_name.yet_another_field.uppercase()`,
+			output: `root.foo = this.a_long_field_name.another_field.uppercase().
+  trim().
+  replace("foo", "bar").
+  split(",")
+root.bar = this.a_long_field_name.another_long_field_name.yet_another_field.uppercase()
+`,
+		},
+		"strings are untouched": {
+			mapping: `root.a = "  spaced  ,  string  "
+root.b = """multi
+  line  string"""`,
+			output: `root.a = "  spaced  ,  string  "
+root.b = """multi
+  line  string"""
+`,
+		},
+		"destructuring and variables": {
+			mapping: `let {a,b} = this
+root."quoted.path" = $a+$b.c`,
+			output: `let {a, b} = this
+root."quoted.path" = $a + $b.c
+`,
+		},
+	}
+
+	for name, test := range tests {
+		test := test
+		t.Run(name, func(t *testing.T) {
+			output, err := FormatMapping(GlobalContext(), "", test.mapping)
+			require.Nil(t, err)
+			assert.Equal(t, test.output, output)
+
+			again, err := FormatMapping(GlobalContext(), "", output)
+			require.Nil(t, err)
+			assert.Equal(t, output, again, "formatting should be idempotent")
+		})
+	}
+}
+
+func TestFormatMappingErrors(t *testing.T) {
+	_, err := FormatMapping(GlobalContext(), "", `root = this.foo.nope()`)
+	require.NotNil(t, err)
+	assert.Equal(t, "line 1 char 17: unrecognised method 'nope'", err.ErrorAtPosition([]rune(`root = this.foo.nope()`)))
+}
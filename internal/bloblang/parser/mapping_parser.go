@@ -4,8 +4,10 @@ import (
 	"errors"
 	"fmt"
 	"io/ioutil"
+	"os"
 	"path"
 	"path/filepath"
+	"sort"
 	"strings"
 
 	"github.com/Jeffail/benthos/v3/internal/bloblang/mapping"
@@ -19,11 +21,45 @@ import (
 // The filepath is optional and used for relative file imports and error
 // messages.
 func ParseMapping(pCtx Context, filepath, expr string) (*mapping.Executor, *Error) {
+	return parseMapping(pCtx, filepath, expr)
+}
+
+// ParseMappingWithWarnings parses a bloblang mapping and returns an executor to
+// run it along with any warnings found within the mapping in the order that
+// they appear, or an error if the parsing fails. Warnings found within imported
+// files are not included.
+//
+// The filepath is optional and used for relative file imports and error
+// messages.
+func ParseMappingWithWarnings(pCtx Context, filepath, expr string) (*mapping.Executor, []Warning, *Error) {
+	warnings := []Warning{}
+	pCtx.warnings = &warnings
+	pCtx.usedVars = map[string]struct{}{}
+	pCtx.varTypes = map[string]query.ValueType{}
+
+	exec, err := parseMapping(pCtx, filepath, expr)
+	if err != nil {
+		return nil, nil, err
+	}
+	sort.SliceStable(warnings, func(i, j int) bool {
+		return len(warnings[i].Input) > len(warnings[j].Input)
+	})
+	return exec, warnings, nil
+}
+
+func parseMapping(pCtx Context, filepath, expr string) (*mapping.Executor, *Error) {
 	in := []rune(expr)
 	dir := ""
 	if len(filepath) > 0 {
 		dir = path.Dir(filepath)
+		pCtx.mappingDir = dir
+
+		var err error
+		if pCtx, err = pCtx.withImport(importKey(filepath)); err != nil {
+			return nil, NewFatalError(in, err)
+		}
 	}
+	pCtx.imports = map[string]importedFile{}
 
 	resDirectImport := singleRootImport(dir, pCtx)(in)
 	if resDirectImport.Err != nil && resDirectImport.Err.IsFatal() {
@@ -49,10 +85,22 @@ func ParseMapping(pCtx Context, filepath, expr string) (*mapping.Executor, *Erro
 //------------------------------------------------------------------------------'
 
 func parseExecutor(baseDir string, pCtx Context) Func {
+	return parseExecutorWithFunctions(baseDir, map[string]*query.UserFunction{}, pCtx)
+}
+
+// parseExecutorWithFunctions parses a mapping where functions declared within
+// it are added to a provided map, allowing them to be imported by other
+// mappings.
+func parseExecutorWithFunctions(baseDir string, funcs map[string]*query.UserFunction, pCtx Context) Func {
 	newline := NewlineAllowComment()
 	whitespace := SpacesAndTabs()
 	allWhitespace := DiscardAll(OneOf(whitespace, newline))
 
+	if baseDir != "" {
+		pCtx.mappingDir = baseDir
+	}
+	pCtx.userFunctions = funcs
+
 	return func(input []rune) Result {
 		maps := map[string]query.Function{}
 		statements := []mapping.Statement{}
@@ -60,6 +108,8 @@ func parseExecutor(baseDir string, pCtx Context) Func {
 		statement := OneOf(
 			importParser(baseDir, maps, pCtx),
 			mapParser(maps, pCtx),
+			funcParser(pCtx),
+			tryCatchStatementParser(false, pCtx),
 			letStatementParser(pCtx),
 			metaStatementParser(false, pCtx),
 			plainMappingStatementParser(pCtx),
@@ -98,6 +148,7 @@ func parseExecutor(baseDir string, pCtx Context) Func {
 				statements = append(statements, mStmt)
 			}
 		}
+		pCtx.warnStatements(statements)
 		return Success(mapping.NewExecutor("", input, maps, statements...), res.Remaining)
 	}
 }
@@ -120,22 +171,12 @@ func singleRootImport(baseDir string, pCtx Context) Func {
 			return res
 		}
 
-		fpath := res.Payload.([]interface{})[3].(string)
-		if !filepath.IsAbs(fpath) {
-			fpath = path.Join(baseDir, fpath)
-		}
-
-		contents, err := ioutil.ReadFile(fpath)
+		fpath := resolveImportPath(baseDir, res.Payload.([]interface{})[3].(string))
+		imported, err := parseImport(input, fpath, pCtx)
 		if err != nil {
-			return Fail(NewFatalError(input, fmt.Errorf("failed to read import: %w", err)), input)
-		}
-
-		importContent := []rune(string(contents))
-		execRes := parseExecutor(path.Dir(fpath), pCtx)(importContent)
-		if execRes.Err != nil {
-			return Fail(NewFatalError(input, NewImportError(fpath, importContent, execRes.Err)), input)
+			return Fail(err, input)
 		}
-		return Success(execRes.Payload.(*mapping.Executor), res.Remaining)
+		return Success(imported.exec, res.Remaining)
 	}
 }
 
@@ -169,7 +210,8 @@ func singleRootMapping(pCtx Context) Func {
 			return Fail(NewError(res.Remaining, expStr), input)
 		}
 
-		stmt := mapping.NewStatement(input, mapping.NewJSONAssignment(), fn)
+		fn = pCtx.traced(input, input[len(assignmentRunes):], "assignment", fn)
+		stmt := mapping.NewStatement(input, mapping.NewJSONAssignment(), pCtx.covered(input, "statement", fn))
 		return Success(mapping.NewExecutor("", input, map[string]query.Function{}, stmt), nil)
 	}
 }
@@ -189,6 +231,94 @@ func varNameParser() Func {
 	)
 }
 
+// ModulesDir is the name of the directory that vendored mapping libraries are
+// stored within, and which imports are resolved from when a path isn't found
+// relative to the importing mapping.
+const ModulesDir = "blobl_modules"
+
+// resolveImportPath returns the path of a file to import. Paths that are not
+// explicitly relative (prefixed with ./ or ../) and do not exist relative to
+// the base directory are looked up within the modules directory of the base
+// directory, or the closest of its ancestors to have one.
+func resolveImportPath(baseDir, fpath string) string {
+	if filepath.IsAbs(fpath) {
+		return fpath
+	}
+	joined := path.Join(baseDir, fpath)
+	if strings.HasPrefix(fpath, "./") || strings.HasPrefix(fpath, "../") {
+		return joined
+	}
+	if _, err := os.Stat(joined); err == nil {
+		return joined
+	}
+
+	dir, err := filepath.Abs(baseDir)
+	if err != nil {
+		return joined
+	}
+	for {
+		vendored := filepath.Join(dir, ModulesDir, filepath.FromSlash(fpath))
+		if _, err := os.Stat(vendored); err == nil {
+			return vendored
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return joined
+		}
+		dir = parent
+	}
+}
+
+// importKey returns an absolute form of a file path where possible, used for
+// identifying files that are imported via different relative paths.
+func importKey(fpath string) string {
+	if absPath, err := filepath.Abs(fpath); err == nil {
+		return absPath
+	}
+	return path.Clean(fpath)
+}
+
+// parseImport reads and parses a mapping file, where files that have already
+// been imported during the parse are reused.
+func parseImport(input []rune, fpath string, pCtx Context) (importedFile, *Error) {
+	key := importKey(fpath)
+
+	pCtx, err := pCtx.withImport(key)
+	if err != nil {
+		return importedFile{}, NewFatalError(input, err)
+	}
+	if imported, exists := pCtx.imports[key]; exists {
+		return imported, nil
+	}
+
+	contents, err := ioutil.ReadFile(fpath)
+	if err != nil {
+		return importedFile{}, NewFatalError(input, fmt.Errorf("failed to read import: %w", err))
+	}
+
+	// Warnings, coverage and traces are positioned relative to the input of
+	// the root mapping, and therefore aren't collected from imported files.
+	pCtx.warnings = nil
+	pCtx.coverage = nil
+	pCtx.trace = nil
+
+	importContent := []rune(string(contents))
+	funcs := map[string]*query.UserFunction{}
+	execRes := parseExecutorWithFunctions(path.Dir(fpath), funcs, pCtx)(importContent)
+	if execRes.Err != nil {
+		return importedFile{}, NewFatalError(input, NewImportError(fpath, importContent, execRes.Err))
+	}
+
+	imported := importedFile{
+		exec:  execRes.Payload.(*mapping.Executor),
+		funcs: funcs,
+	}
+	if pCtx.imports != nil {
+		pCtx.imports[key] = imported
+	}
+	return imported, nil
+}
+
 func importParser(baseDir string, maps map[string]query.Function, pCtx Context) Func {
 	p := Sequence(
 		Term("import"),
@@ -207,31 +337,42 @@ func importParser(baseDir string, maps map[string]query.Function, pCtx Context)
 			return res
 		}
 
-		fpath := res.Payload.([]interface{})[2].(string)
-		if !filepath.IsAbs(fpath) {
-			fpath = path.Join(baseDir, fpath)
-		}
-		contents, err := ioutil.ReadFile(fpath)
-		if err != nil {
-			return Fail(NewFatalError(input, fmt.Errorf("failed to read import: %w", err)), input)
+		fpath := resolveImportPath(baseDir, res.Payload.([]interface{})[2].(string))
+		imported, perr := parseImport(input, fpath, pCtx)
+		if perr != nil {
+			return Fail(perr, input)
 		}
 
-		importContent := []rune(string(contents))
-		execRes := parseExecutor(path.Dir(fpath), pCtx)(importContent)
-		if execRes.Err != nil {
-			return Fail(NewFatalError(input, NewImportError(fpath, importContent, execRes.Err)), input)
+		exec := imported.exec
+		if len(exec.Maps()) == 0 && len(imported.funcs) == 0 {
+			err := fmt.Errorf("no maps or functions to import from '%v'", fpath)
+			return Fail(NewFatalError(input, err), input)
 		}
 
-		exec := execRes.Payload.(*mapping.Executor)
-		if len(exec.Maps()) == 0 {
-			err := fmt.Errorf("no maps to import from '%v'", fpath)
+		// Definitions that are identical are permitted as the same file can
+		// be imported via multiple others.
+		funcCollisions := []string{}
+		for k, v := range imported.funcs {
+			if existing, exists := pCtx.userFunctions[k]; exists {
+				if existing != v {
+					funcCollisions = append(funcCollisions, k)
+				}
+			} else {
+				pCtx.userFunctions[k] = v
+			}
+		}
+		if len(funcCollisions) > 0 {
+			sort.Strings(funcCollisions)
+			err := fmt.Errorf("function name collisions from import '%v': %v", fpath, funcCollisions)
 			return Fail(NewFatalError(input, err), input)
 		}
 
 		collisions := []string{}
 		for k, v := range exec.Maps() {
-			if _, exists := maps[k]; exists {
-				collisions = append(collisions, k)
+			if existing, exists := maps[k]; exists {
+				if existing != v {
+					collisions = append(collisions, k)
+				}
 			} else {
 				maps[k] = v
 			}
@@ -246,6 +387,10 @@ func importParser(baseDir string, maps map[string]query.Function, pCtx Context)
 }
 
 func mapParser(maps map[string]query.Function, pCtx Context) Func {
+	// Maps are executed wherever they're applied, and therefore the types of
+	// variables at the point they're declared aren't relevant.
+	pCtx.varTypes = nil
+
 	newline := NewlineAllowComment()
 	whitespace := SpacesAndTabs()
 	allWhitespace := DiscardAll(OneOf(whitespace, newline))
@@ -270,6 +415,7 @@ func mapParser(maps map[string]query.Function, pCtx Context) Func {
 				allWhitespace,
 			),
 			OneOf(
+				tryCatchStatementParser(true, pCtx),
 				letStatementParser(pCtx),
 				metaStatementParser(true, pCtx), // Prevented for now due to .from(int)
 				plainMappingStatementParser(pCtx),
@@ -312,6 +458,286 @@ func mapParser(maps map[string]query.Function, pCtx Context) Func {
 	}
 }
 
+func funcParamParser() Func {
+	typeNames := make([]string, 0, len(query.UserFunctionParamTypes))
+	for k := range query.UserFunctionParamTypes {
+		typeNames = append(typeNames, k)
+	}
+	sort.Strings(typeNames)
+
+	typeParsers := make([]Func, len(typeNames))
+	for i, k := range typeNames {
+		typeParsers[i] = Term(k)
+	}
+
+	p := Sequence(
+		Expect(SnakeCase(), "parameter name"),
+		Optional(Sequence(
+			Discard(SpacesAndTabs()),
+			Char(':'),
+			Discard(SpacesAndTabs()),
+			MustBe(Expect(OneOf(typeParsers...), "parameter type")),
+		)),
+	)
+
+	return func(input []rune) Result {
+		res := p(input)
+		if res.Err != nil {
+			return res
+		}
+
+		seqSlice := res.Payload.([]interface{})
+		def := query.ParamAny(seqSlice[0].(string), "")
+		if typeSlice, hasType := seqSlice[1].([]interface{}); hasType {
+			def.ValueType = query.UserFunctionParamTypes[typeSlice[3].(string)]
+		}
+		return Success(def, res.Remaining)
+	}
+}
+
+func funcParser(pCtx Context) Func {
+	whitespace := DiscardAll(
+		OneOf(
+			SpacesAndTabs(),
+			NewlineAllowComment(),
+		),
+	)
+
+	p := Sequence(
+		Term("func"),
+		SpacesAndTabs(),
+		varNameParser(),
+		MustBe(DelimitedPattern(
+			Expect(Sequence(Char('('), whitespace), "function parameters"),
+			MustBe(funcParamParser()),
+			MustBe(Expect(Sequence(Discard(SpacesAndTabs()), Char(','), whitespace), "comma")),
+			MustBe(Expect(Sequence(whitespace, Char(')')), "closing bracket")),
+			true,
+		)),
+		SpacesAndTabs(),
+		MustBe(Char('{')),
+		whitespace,
+	)
+
+	return func(input []rune) Result {
+		res := p(input)
+		if res.Err != nil {
+			return res
+		}
+
+		seqSlice := res.Payload.([]interface{})
+		name := seqSlice[2].(string)
+
+		if _, exists := pCtx.userFunctions[name]; exists {
+			return Fail(NewFatalError(input, fmt.Errorf("function name collision: %v", name)), input)
+		}
+		if _, err := pCtx.Functions.Params(name); err == nil {
+			return Fail(NewFatalError(input, fmt.Errorf("function name collides with a built-in function: %v", name)), input)
+		}
+
+		params := query.NewParams()
+		bodyCtx := pCtx
+		bodyCtx.varTypes = nil // Variables may differ wherever the function is called
+		for _, v := range seqSlice[3].([]interface{}) {
+			def := v.(query.ParamDefinition)
+			switch def.Name {
+			case "root", "this":
+				return Fail(NewFatalError(input, fmt.Errorf("parameter name `%v` is not allowed", def.Name)), input)
+			}
+			if bodyCtx.HasNamedContext(def.Name) {
+				return Fail(NewFatalError(input, fmt.Errorf("duplicate parameter name: %v", def.Name)), input)
+			}
+			params = params.Add(def)
+			bodyCtx = bodyCtx.WithNamedContext(def.Name)
+		}
+
+		if res = MustBe(queryParser(bodyCtx))(res.Remaining); res.Err != nil {
+			return Fail(res.Err, input)
+		}
+		body := res.Payload.(query.Function)
+
+		if res = MustBe(Expect(Sequence(whitespace, Char('}')), "end of function"))(res.Remaining); res.Err != nil {
+			return Fail(res.Err, input)
+		}
+
+		pCtx.userFunctions[name] = query.NewUserFunction(name, params, body)
+		return Success(name, res.Remaining)
+	}
+}
+
+func tryCatchStatementParser(inMap bool, pCtx Context) Func {
+	newline := NewlineAllowComment()
+	whitespace := SpacesAndTabs()
+	allWhitespace := DiscardAll(OneOf(whitespace, newline))
+
+	// Parses the statements of a block following its opening brace.
+	blockParser := func(pCtx Context) Func {
+		return func(input []rune) Result {
+			return DelimitedPattern(
+				allWhitespace,
+				OneOf(
+					tryCatchStatementParser(inMap, pCtx),
+					letStatementParser(pCtx),
+					metaStatementParser(inMap, pCtx),
+					plainMappingStatementParser(pCtx),
+				),
+				Sequence(
+					Discard(whitespace),
+					newline,
+					allWhitespace,
+				),
+				Sequence(
+					allWhitespace,
+					Char('}'),
+				),
+				true,
+			)(input)
+		}
+	}
+
+	p := Sequence(
+		Expect(Term("try"), "assignment"),
+		Discard(whitespace),
+		Char('{'),
+	)
+
+	return func(input []rune) Result {
+		res := p(input)
+		if res.Err != nil {
+			return res
+		}
+
+		res = MustBe(blockParser(pCtx))(res.Remaining)
+		if res.Err != nil {
+			return Fail(res.Err, input)
+		}
+		tryStmts := res.Payload.([]interface{})
+
+		res = MustBe(Expect(Sequence(
+			allWhitespace,
+			Term("catch"),
+			Discard(whitespace),
+			Optional(varNameParser()),
+			Discard(whitespace),
+			Char('{'),
+		), "catch block"))(res.Remaining)
+		if res.Err != nil {
+			return Fail(res.Err, input)
+		}
+
+		errName := "_"
+		if name, named := res.Payload.([]interface{})[3].(string); named {
+			errName = name
+		}
+
+		catchCtx := pCtx
+		if errName != "_" {
+			if pCtx.HasNamedContext(errName) {
+				return Fail(NewFatalError(input, fmt.Errorf("context label `%v` would shadow a parent context", errName)), input)
+			}
+			if errName == "root" || errName == "this" {
+				return Fail(NewFatalError(input, fmt.Errorf("context label `%v` is not allowed", errName)), input)
+			}
+			catchCtx = pCtx.WithNamedContext(errName)
+		}
+
+		res = MustBe(blockParser(catchCtx))(res.Remaining)
+		if res.Err != nil {
+			return Fail(res.Err, input)
+		}
+		catchStmts := res.Payload.([]interface{})
+
+		return Success(
+			mapping.NewTryCatchStatement(
+				input,
+				toStatements(tryStmts),
+				errName,
+				toStatements(catchStmts),
+			),
+			res.Remaining,
+		)
+	}
+}
+
+func toStatements(stmtSlice []interface{}) []mapping.Statement {
+	statements := make([]mapping.Statement, len(stmtSlice))
+	for i, v := range stmtSlice {
+		statements[i] = v.(mapping.Statement)
+	}
+	return statements
+}
+
+// destructurePatternParser parses a list of variable names within either
+// braces, for destructuring the fields of an object, or square brackets, for
+// destructuring the elements of an array.
+func destructurePatternParser() Func {
+	whitespace := DiscardAll(
+		OneOf(
+			NewlineAllowComment(),
+			SpacesAndTabs(),
+		),
+	)
+	pattern := func(begin, end rune) Func {
+		return DelimitedPattern(
+			Sequence(
+				Char(begin),
+				whitespace,
+			),
+			varNameParser(),
+			Sequence(
+				Discard(SpacesAndTabs()),
+				Char(','),
+				whitespace,
+			),
+			Sequence(
+				whitespace,
+				Char(end),
+			),
+			true,
+		)
+	}
+	objectPattern, arrayPattern := pattern('{', '}'), pattern('[', ']')
+
+	return func(input []rune) Result {
+		isArray := false
+		res := objectPattern(input)
+		if res.Err != nil {
+			if res = arrayPattern(input); res.Err != nil {
+				return res
+			}
+			isArray = true
+		}
+
+		var names []string
+		seen := map[string]struct{}{}
+		for _, v := range res.Payload.([]interface{}) {
+			name := v.(string)
+			if name == "_" && isArray {
+				names = append(names, name)
+				continue
+			}
+			if name == "_" {
+				return Fail(NewFatalError(input, errors.New("fields of an object cannot be skipped with `_`")), input)
+			}
+			if _, exists := seen[name]; exists {
+				return Fail(NewFatalError(input, fmt.Errorf("duplicate variable name: %v", name)), input)
+			}
+			seen[name] = struct{}{}
+			names = append(names, name)
+		}
+		if len(seen) == 0 {
+			return Fail(NewFatalError(input, errors.New("destructuring pattern must contain at least one variable name")), input)
+		}
+
+		if isArray {
+			res.Payload = mapping.NewArrayDestructureAssignment(names...)
+		} else {
+			res.Payload = mapping.NewObjectDestructureAssignment(names...)
+		}
+		return res
+	}
+}
+
 func letStatementParser(pCtx Context) Func {
 	p := Sequence(
 		Expect(Term("let"), "assignment"),
@@ -320,6 +746,7 @@ func letStatementParser(pCtx Context) Func {
 		MustBe(
 			Expect(
 				OneOf(
+					destructurePatternParser(),
 					QuotedString(),
 					varNameParser(),
 				),
@@ -338,11 +765,20 @@ func letStatementParser(pCtx Context) Func {
 			return res
 		}
 		resSlice := res.Payload.([]interface{})
+
+		var assignment mapping.Assignment
+		switch t := resSlice[2].(type) {
+		case string:
+			assignment = mapping.NewVarAssignment(t)
+		case mapping.Assignment:
+			assignment = t
+		}
+		pCtx.recordVarTypes(assignment, resSlice[6].(query.Function))
 		return Success(
 			mapping.NewStatement(
 				input,
-				mapping.NewVarAssignment(resSlice[2].(string)),
-				resSlice[6].(query.Function),
+				assignment,
+				pCtx.covered(input, "statement", pCtx.traced(input, res.Remaining, "assignment", resSlice[6].(query.Function))),
 			),
 			res.Remaining,
 		)
@@ -399,7 +835,7 @@ func metaStatementParser(disabled bool, pCtx Context) Func {
 			mapping.NewStatement(
 				input,
 				mapping.NewMetaAssignment(keyPtr),
-				resSlice[6].(query.Function),
+				pCtx.covered(input, "statement", pCtx.traced(input, res.Remaining, "assignment", resSlice[6].(query.Function))),
 			),
 			res.Remaining,
 		)
@@ -503,7 +939,7 @@ func plainMappingStatementParser(pCtx Context) Func {
 			mapping.NewStatement(
 				input,
 				mapping.NewJSONAssignment(path...),
-				resSlice[4].(query.Function),
+				pCtx.covered(input, "statement", pCtx.traced(input, res.Remaining, "assignment", resSlice[4].(query.Function))),
 			),
 			res.Remaining,
 		)
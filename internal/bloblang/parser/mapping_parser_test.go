@@ -1,12 +1,15 @@
 package parser
 
 import (
+	"errors"
 	"fmt"
 	"io/ioutil"
 	"os"
 	"path/filepath"
 	"testing"
 
+	"github.com/Jeffail/benthos/v3/internal/bloblang/mapping"
+	"github.com/Jeffail/benthos/v3/internal/bloblang/query"
 	"github.com/Jeffail/benthos/v3/lib/message"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -27,6 +30,13 @@ func TestMappingErrors(t *testing.T) {
 	require.NoError(t, ioutil.WriteFile(noMapsFile, []byte(`foo = "this is valid but has no maps"`), 0777))
 	require.NoError(t, ioutil.WriteFile(goodMapFile, []byte(`map foo { foo = "this is valid" }`), 0777))
 
+	cycleAFile := filepath.Join(dir, "cycle_a.blobl")
+	cycleBFile := filepath.Join(dir, "cycle_b.blobl")
+	require.NoError(t, ioutil.WriteFile(cycleAFile, []byte(`import "./cycle_b.blobl"
+map a { root = this }`), 0777))
+	require.NoError(t, ioutil.WriteFile(cycleBFile, []byte(`import "./cycle_a.blobl"
+map b { root = this }`), 0777))
+
 	tests := map[string]struct {
 		mapping string
 		err     string
@@ -41,12 +51,12 @@ func TestMappingErrors(t *testing.T) {
 		},
 		"no mappings": {
 			mapping: ``,
-			err:     `line 1 char 1: expected import, map, or assignment`,
+			err:     `line 1 char 1: expected import, map, func, or assignment`,
 		},
 		"no mappings 2": {
 			mapping: `
    `,
-			err: `line 2 char 4: expected import, map, or assignment`,
+			err: `line 2 char 4: expected import, map, func, or assignment`,
 		},
 		"double mapping": {
 			mapping: `foo = bar bar = baz`,
@@ -87,13 +97,13 @@ meta foo = "bar"`,
 		"bad char 2": {
 			mapping: `let foo = bar
 !foo = bar`,
-			err: `line 2 char 1: expected import, map, or assignment`,
+			err: `line 2 char 1: expected import, map, func, or assignment`,
 		},
 		"bad char 3": {
 			mapping: `let foo = bar
 !foo = bar
 this = that`,
-			err: `line 2 char 1: expected import, map, or assignment`,
+			err: `line 2 char 1: expected import, map, func, or assignment`,
 		},
 		"bad query": {
 			mapping: `foo = blah.`,
@@ -143,7 +153,7 @@ foo = bar.apply("from_import")`, badMapFile),
 			mapping: fmt.Sprintf(`import "%v"
 
 foo = bar.apply("from_import")`, noMapsFile),
-			err: fmt.Sprintf(`line 1 char 1: no maps to import from '%v'`, noMapsFile),
+			err: fmt.Sprintf(`line 1 char 1: no maps or functions to import from '%v'`, noMapsFile),
 		},
 		"colliding maps file import": {
 			mapping: fmt.Sprintf(`map "foo" { this = that }			
@@ -153,10 +163,81 @@ import "%v"
 foo = bar.apply("foo")`, goodMapFile),
 			err: fmt.Sprintf(`line 3 char 1: map name collisions from import '%v': [foo]`, goodMapFile),
 		},
+		"double function definition": {
+			mapping: `func foo(v) { v }
+func foo(v) { v }
+root = foo(5)`,
+			err: `line 2 char 1: function name collision: foo`,
+		},
+		"function collides with built-in": {
+			mapping: `func uuid_v4() { "nope" }
+root = uuid_v4()`,
+			err: `line 1 char 1: function name collides with a built-in function: uuid_v4`,
+		},
+		"function bad parameter type": {
+			mapping: `func foo(v: nope) { v }
+root = foo(5)`,
+			err: `line 1 char 13: required: expected parameter type`,
+		},
+		"function duplicate parameter": {
+			mapping: `func foo(v, v) { v }
+root = foo(5)`,
+			err: `line 1 char 1: duplicate parameter name: v`,
+		},
+		"function wrong argument type": {
+			mapping: `func foo(v: number) { v }
+root = foo("five")`,
+			err: `line 2 char 8: field v: expected number value, got string ("five")`,
+		},
+		"function wrong argument count": {
+			mapping: `func foo(a, b) { a + b }
+root = foo(5)`,
+			err: `line 2 char 8: missing parameter: b`,
+		},
+		"function recursion": {
+			mapping: `func foo(v) { foo(v) }
+root = foo(5)`,
+			err: `line 1 char 15: unrecognised function 'foo'`,
+		},
+		"import cycle": {
+			mapping: fmt.Sprintf(`import "%v"
+
+foo = bar.apply("a")`, cycleAFile),
+			err: fmt.Sprintf(
+				`line 1 char 1: failed to parse import '%v': line 1 char 1: failed to parse import '%v': line 1 char 1: import cycle detected: %v -> %v -> %v`,
+				cycleAFile, cycleBFile, cycleAFile, cycleBFile, cycleAFile,
+			),
+		},
+		"destructuring duplicate name": {
+			mapping: `let {a, b, a} = this`,
+			err:     `line 1 char 5: duplicate variable name: a`,
+		},
+		"destructuring skipped field": {
+			mapping: `let {a, _} = this`,
+			err:     "line 1 char 5: fields of an object cannot be skipped with `_`",
+		},
+		"destructuring empty pattern": {
+			mapping: `let [_, _] = this`,
+			err:     `line 1 char 5: destructuring pattern must contain at least one variable name`,
+		},
+		"try without catch": {
+			mapping: `try {
+  root = this
+}`,
+			err: `line 3 char 2: required: expected catch block`,
+		},
+		"try catch shadows variable": {
+			mapping: `try {
+  root = this.foo
+} catch this {
+  root = "nope"
+}`,
+			err: "line 1 char 1: context label `this` is not allowed",
+		},
 		"quotes at root": {
 			mapping: `
 "root.something" = 5 + 2`,
-			err: "line 2 char 1: expected import, map, or assignment",
+			err: "line 2 char 1: expected import, map, func, or assignment",
 		},
 	}
 
@@ -187,6 +268,29 @@ func TestMappings(t *testing.T) {
 	directMapFile := filepath.Join(dir, "direct_map.blobl")
 	require.NoError(t, ioutil.WriteFile(directMapFile, []byte(`root.nested = this`), 0777))
 
+	require.NoError(t, os.Mkdir(filepath.Join(dir, "lib"), 0777))
+	require.NoError(t, ioutil.WriteFile(filepath.Join(dir, "lib", "common.blobl"), []byte(`func shout(v: string) {
+  v.uppercase() + "!"
+}
+map tidy {
+  root.name = this.name.trim()
+}`), 0777))
+	require.NoError(t, ioutil.WriteFile(filepath.Join(dir, "lib", "users.blobl"), []byte(`import "./common.blobl"
+map user {
+  root = this.apply("tidy")
+  root.greeting = shout("hello " + this.name.trim())
+}`), 0777))
+	require.NoError(t, ioutil.WriteFile(filepath.Join(dir, "lib", "admins.blobl"), []byte(`import "common.blobl"
+map admin {
+  root = this.apply("tidy")
+  root.role = shout("admin")
+}`), 0777))
+
+	funcsFile := filepath.Join(dir, "funcs.blobl")
+	require.NoError(t, ioutil.WriteFile(funcsFile, []byte(`func greet(name: string) {
+  "hello " + name
+}`), 0777))
+
 	type part struct {
 		Content string
 		Meta    map[string]string
@@ -322,6 +426,30 @@ meta = deleted()`,
 				Content: `{"bar":{"baz":"test1"}}`,
 			},
 		},
+		"test mapping meta_map filtered copy": {
+			mapping: `meta = meta_map().filter(kv -> !kv.key.has_prefix("kafka_"))
+root.all = meta_map()
+meta added = "new"
+root.new = root_meta_map()`,
+			input: []part{
+				{
+					Content: `{}`,
+					Meta: map[string]string{
+						"kafka_key": "foo",
+						"empty":     "",
+						"trace":     "bar",
+					},
+				},
+			},
+			output: part{
+				Content: `{"all":{"empty":"","kafka_key":"foo","trace":"bar"},"new":{"added":"new","empty":"","trace":"bar"}}`,
+				Meta: map[string]string{
+					"added": "new",
+					"empty": "",
+					"trace": "bar",
+				},
+			},
+		},
 		"test variables and json": {
 			mapping: `let foo = foo
 let "bar baz" = "test1"
@@ -465,6 +593,164 @@ root = this.apply("foo")`, goodMapFile),
 				Content: `{"foo":"this is valid","nested":{"outter":{"inner":"hello world"}}}`,
 			},
 		},
+		"test user defined function": {
+			mapping: `func clamp(v: number, min: number, max: number) {
+  if v < min { min } else if v > max { max } else { v }
+}
+
+root.a = clamp(this.a, 0, 10)
+root.b = clamp(this.b, 0, 10)
+root.c = clamp(v: this.c, min: 0, max: 10)`,
+			input: []part{
+				{Content: `{"a":-5,"b":15,"c":7}`},
+			},
+			output: part{
+				Content: `{"a":0,"b":10,"c":7}`,
+			},
+		},
+		"test user defined function calling function": {
+			mapping: `func double(v) {
+  v * 2
+}
+func quadruple(v) {
+  double(double(v))
+}
+map foo {
+  root.value = quadruple(this.value)
+  root.this_unchanged = this.value
+}
+root = this.apply("foo")`,
+			input: []part{
+				{Content: `{"value":3}`},
+			},
+			output: part{
+				Content: `{"this_unchanged":3,"value":12}`,
+			},
+		},
+		"test imported function": {
+			mapping: fmt.Sprintf(`import "%v"
+
+root.greeting = greet(this.name)`, funcsFile),
+			input: []part{
+				{Content: `{"name":"world"}`},
+			},
+			output: part{
+				Content: `{"greeting":"hello world"}`,
+			},
+		},
+		"test shared nested imports": {
+			mapping: fmt.Sprintf(`import "%v"
+import "%v"
+
+root.user = this.apply("user")
+root.admin = this.apply("admin")
+root.tidy = this.apply("tidy")`, filepath.Join(dir, "lib", "users.blobl"), filepath.Join(dir, "lib", "admins.blobl")),
+			input: []part{
+				{Content: `{"name":" foo "}`},
+			},
+			output: part{
+				Content: `{"admin":{"name":"foo","role":"ADMIN!"},"tidy":{"name":"foo"},"user":{"greeting":"HELLO FOO!","name":"foo"}}`,
+			},
+		},
+		"test reassigned variables": {
+			mapping: `let total = this.base
+let total = $total + this.bonus
+try {
+  let total = $total * this.multiplier
+  let total = $total + this.nope.number()
+} catch {
+  let total = $total - 1
+}
+root.total = $total`,
+			input: []part{
+				{Content: `{"base":10,"bonus":5,"multiplier":2}`},
+			},
+			output: part{
+				Content: `{"total":29}`,
+			},
+		},
+		"test destructuring let": {
+			mapping: `let {id, name, nope} = this.user
+let [
+  first,
+  _,
+  third,
+  fourth,
+] = this.items
+root.user = [$id, $name, $nope]
+root.items = [$first, $third, $fourth]`,
+			input: []part{
+				{Content: `{"user":{"id":"u1","name":"foo"},"items":["a","b","c"]}`},
+			},
+			output: part{
+				Content: `{"items":["a","c",null],"user":["u1","foo",null]}`,
+			},
+		},
+		"test try catch": {
+			mapping: `root.a = this.a
+try {
+  root.b = this.b.number()
+  root.c = this.c.uppercase()
+} catch err {
+  root.error = err
+}`,
+			input: []part{
+				{Content: `{"a":"foo","b":"10","c":20}`},
+			},
+			output: part{
+				Content: `{"a":"foo","b":10,"error":{"message":"expected string value, got number from field ` + "`this.c`" + ` (20)","path":"root.c"}}`,
+			},
+		},
+		"test try catch no error": {
+			mapping: `try {
+  root.a = this.a.uppercase()
+} catch err {
+  root.a = err.message
+}`,
+			input: []part{
+				{Content: `{"a":"foo"}`},
+			},
+			output: part{
+				Content: `{"a":"FOO"}`,
+			},
+		},
+		"test nested try catch": {
+			mapping: `try {
+  try {
+    meta foo = this.foo.string()
+    root.a = this.a.uppercase()
+  } catch {
+    root.a = "default"
+  }
+  root.b = this.b.uppercase()
+} catch err {
+  root.failed = err.path
+}`,
+			input: []part{
+				{Content: `{"foo":"bar","a":5,"b":null}`},
+			},
+			output: part{
+				Content: `{"a":"default","failed":"root.b"}`,
+				Meta:    map[string]string{"foo": "bar"},
+			},
+		},
+		"test try catch within map": {
+			mapping: `map foo {
+  try {
+    root.value = this.value.number()
+  } catch err {
+    root.value = 0
+    root.reason = err.message
+  }
+}
+root = this.apply("foo")`,
+			input: []part{
+				{Content: `{"value":"nope"}`},
+			},
+			output: part{
+				Content: `{"reason":"field ` + "`this.value`" + `: strconv.ParseFloat: parsing \"nope\": invalid syntax","value":0}`,
+			},
+		},
 		"test directly imported map": {
 			mapping: fmt.Sprintf(`from "%v"`, directMapFile),
 			input: []part{
@@ -510,3 +796,449 @@ root = this.apply("foo")`, goodMapFile),
 		})
 	}
 }
+
+func TestMappingWarnings(t *testing.T) {
+	tests := map[string]struct {
+		mapping  string
+		warnings []string
+	}{
+		"no type patterns": {
+			mapping: `root = match this.value {
+  "foo" => "bar"
+}`,
+		},
+		"all scalar types handled": {
+			mapping: `root = match this.value {
+  string s => s
+  number n => n
+  bool b => b
+  null _ => "null"
+}`,
+		},
+		"catch-all case": {
+			mapping: `root = match this.value {
+  string s => s
+  _ => "other"
+}`,
+		},
+		"unhandled scalar types": {
+			mapping: `root.foo = "bar"
+root.value = match this.value {
+  string s => s
+  array a => a.length()
+}`,
+			warnings: []string{
+				"line 2 char 14: match expression does not handle the types number, bool, null, consider adding a catch-all case `_ => ...`",
+			},
+		},
+		"unreachable match cases": {
+			mapping: `root = match this.value {
+  "foo" => "a"
+  "foo" => "b"
+  string s => s
+  string t => t
+  _ => "other"
+  number n => n
+}`,
+			warnings: []string{
+				`line 3 char 3: match case is unreachable as the value "foo" is already handled by a previous case`,
+				"line 5 char 3: match case is unreachable as the type string is already handled by a previous case",
+				"line 7 char 3: match case is unreachable as it follows a catch-all case",
+			},
+		},
+		"unused variables": {
+			mapping: `let foo = "foo"
+let bar = "bar"
+let [_, baz, qux] = this
+let quz = "quz"
+map thing {
+  root = $bar
+}
+root.bar = this.apply("thing")
+root.baz = $baz
+root.quz = this.items.map_each(this + $quz)`,
+			warnings: []string{
+				"line 1 char 1: variable $foo is declared but never used",
+				"line 3 char 1: variable $qux is declared but never used",
+			},
+		},
+		"deprecated functions and methods": {
+			mapping: `root.a = timestamp_utc()
+root.b = this.b.parse_timestamp_unix()`,
+			warnings: []string{
+				"line 1 char 10: function timestamp_utc is deprecated",
+				"line 2 char 17: method parse_timestamp_unix is deprecated",
+			},
+		},
+		"overwritten assignments": {
+			mapping: `root.a = this.a
+root.b.c = this.b
+root.a = this.c
+root.b = if this.d { "d" }
+root.e = this.e
+root.e.f = this.f
+root = this.merge({})`,
+			warnings: []string{
+				"line 1 char 1: assignment to root.a is always overwritten by a later assignment to root.a",
+				"line 2 char 1: assignment to root.b.c is always overwritten by a later assignment to root",
+				"line 3 char 1: assignment to root.a is always overwritten by a later assignment to root",
+				"line 4 char 1: assignment to root.b is always overwritten by a later assignment to root",
+				"line 5 char 1: assignment to root.e is always overwritten by a later assignment to root",
+				"line 6 char 1: assignment to root.e.f is always overwritten by a later assignment to root",
+			},
+		},
+		"overwritten assignments in try catch": {
+			mapping: `root.a = this.a
+try {
+  root.a = this.b.number()
+} catch {
+  root.c = "failed"
+}`,
+		},
+		"probable type errors": {
+			mapping: `root.a = {"a":1}.sum()
+root.b = this.foo.keys().uppercase()
+let count = this.items.length()
+root.c = $count.keys()
+let mixed = "foo"
+let mixed = 10
+root.d = $mixed.uppercase()
+root.e = this.bar.split(",").join(" ").uppercase()
+root.f = "foo".uppercase().length()`,
+			warnings: []string{
+				"line 1 char 18: method sum expects array or number but is applied to object",
+				"line 2 char 26: method uppercase expects string or bytes but is applied to array",
+				"line 4 char 17: method keys expects object but is applied to number",
+			},
+		},
+		"probable type errors in functions and maps": {
+			mapping: `let foo = 10
+map thing {
+  root = $foo.uppercase()
+}
+func other() {
+  $foo.uppercase()
+}
+root.a = this.apply("thing")
+root.b = other()`,
+		},
+	}
+
+	for name, test := range tests {
+		test := test
+		t.Run(name, func(t *testing.T) {
+			_, warnings, perr := ParseMappingWithWarnings(GlobalContext(), "", test.mapping)
+			require.Nil(t, perr)
+
+			var warningStrs []string
+			for _, w := range warnings {
+				line, col := w.LineAndCol([]rune(test.mapping))
+				warningStrs = append(warningStrs, fmt.Sprintf("line %v char %v: %v", line, col, w.Message))
+			}
+			assert.Equal(t, test.warnings, warningStrs)
+		})
+	}
+}
+
+func TestMappingAliasWarnings(t *testing.T) {
+	functions := query.AllFunctions.Without()
+	require.NoError(t, functions.AddAlias("ts_utc", "timestamp_utc"))
+	require.NoError(t, functions.AddAlias("nowish", "now"))
+
+	methods := query.AllMethods.Without()
+	require.NoError(t, methods.AddAlias("upper", "uppercase"))
+
+	mapping := `root.a = nowish()
+root.b = this.b.upper()
+root.c = ts_utc()`
+
+	exec, warnings, perr := ParseMappingWithWarnings(Context{
+		Functions: functions,
+		Methods:   methods,
+	}, "", mapping)
+	require.Nil(t, perr)
+
+	var warningStrs []string
+	for _, w := range warnings {
+		line, col := w.LineAndCol([]rune(mapping))
+		warningStrs = append(warningStrs, fmt.Sprintf("line %v char %v: %v", line, col, w.Message))
+	}
+	assert.Equal(t, []string{
+		"line 1 char 10: function nowish is deprecated, use now instead",
+		"line 2 char 17: method upper is deprecated, use uppercase instead",
+		"line 3 char 10: function ts_utc is deprecated, use timestamp_utc instead",
+	}, warningStrs)
+
+	resPart, err := exec.MapPart(0, message.New([][]byte{[]byte(`{"b":"foo"}`)}))
+	require.NoError(t, err)
+
+	resJSON, err := resPart.JSON()
+	require.NoError(t, err)
+	assert.Equal(t, "FOO", resJSON.(map[string]interface{})["b"])
+}
+
+func TestMappingCoverage(t *testing.T) {
+	tests := map[string]struct {
+		mapping   string
+		inputs    []string
+		uncovered []string
+	}{
+		"all covered": {
+			mapping: `root.foo = this.foo
+let bar = "bar"
+meta baz = $bar`,
+			inputs: []string{`{"foo":"a"}`},
+		},
+		"if branches": {
+			mapping: `root.foo = if this.foo > 10 {
+  "big"
+} else if this.foo > 5 {
+  "medium"
+} else {
+  "small"
+}`,
+			inputs: []string{`{"foo":20}`, `{"foo":1}`},
+			uncovered: []string{
+				"line 4 char 3: branch",
+			},
+		},
+		"match branches": {
+			mapping: `root.foo = "static"
+root.bar = match this.bar {
+  "a" => "first"
+  string s => s.uppercase()
+  _ => "other"
+}`,
+			inputs: []string{`{"bar":"a"}`},
+			uncovered: []string{
+				"line 4 char 15: branch",
+				"line 5 char 8: branch",
+			},
+		},
+		"statements after error": {
+			mapping: `root.foo = this.foo.uppercase()
+root.bar = "bar"`,
+			inputs: []string{`{"foo":5}`},
+			uncovered: []string{
+				"line 2 char 1: statement",
+			},
+		},
+		"no inputs": {
+			mapping: `root = this`,
+			uncovered: []string{
+				"line 1 char 1: statement",
+			},
+		},
+	}
+
+	for name, test := range tests {
+		test := test
+		t.Run(name, func(t *testing.T) {
+			exec, coverage, perr := ParseMappingWithCoverage(GlobalContext(), "", test.mapping)
+			require.Nil(t, perr)
+
+			for _, input := range test.inputs {
+				_, _ = exec.MapPart(0, message.New([][]byte{[]byte(input)}))
+			}
+
+			var uncoveredStrs []string
+			for _, p := range coverage.Uncovered() {
+				line, col := p.LineAndCol([]rune(test.mapping))
+				uncoveredStrs = append(uncoveredStrs, fmt.Sprintf("line %v char %v: %v", line, col, p.Kind))
+			}
+			assert.Equal(t, test.uncovered, uncoveredStrs)
+		})
+	}
+}
+
+func TestMappingTrace(t *testing.T) {
+	tests := map[string]struct {
+		mapping string
+		input   string
+		steps   []string
+	}{
+		"methods and assignments": {
+			mapping: `root.a = this.foo.uppercase()
+root.b = this.bar + 5`,
+			input: `{"foo":"bar","bar":5}`,
+			steps: []string{
+				`line 1 char 19: method uppercase() -> BAR <nil>`,
+				`line 1 char 1: assignment root.a = this.foo.uppercase() -> BAR <nil>`,
+				`line 2 char 1: assignment root.b = this.bar + 5 -> 10 <nil>`,
+			},
+		},
+		"variables and meta": {
+			mapping: `let foo = this.foo.trim()
+meta bar = $foo`,
+			input: `{"foo":"  baz  "}`,
+			steps: []string{
+				`line 1 char 20: method trim() -> baz <nil>`,
+				`line 1 char 1: assignment let foo = this.foo.trim() -> baz <nil>`,
+				`line 2 char 1: assignment meta bar = $foo -> baz <nil>`,
+			},
+		},
+		"error stops the trace": {
+			mapping: `root.a = this.foo.uppercase()
+root.b = "not reached"`,
+			input: `{"foo":5}`,
+			steps: []string{
+				`line 1 char 19: method uppercase() -> <nil> expected string value, got number from field ` + "`this.foo`" + ` (5)`,
+				`line 1 char 1: assignment root.a = this.foo.uppercase() -> <nil> expected string value, got number from field ` + "`this.foo`" + ` (5)`,
+			},
+		},
+	}
+
+	for name, test := range tests {
+		test := test
+		t.Run(name, func(t *testing.T) {
+			exec, trace, perr := ParseMappingWithTrace(GlobalContext(), "", test.mapping)
+			require.Nil(t, perr)
+
+			_, _ = exec.MapPart(0, message.New([][]byte{[]byte(test.input)}))
+
+			var stepStrs []string
+			for _, s := range trace.Steps() {
+				line, col := s.LineAndCol([]rune(test.mapping))
+				stepStrs = append(stepStrs, fmt.Sprintf("line %v char %v: %v %v -> %v %v", line, col, s.Kind, s.Expression, s.Value, s.Err))
+			}
+			assert.Equal(t, test.steps, stepStrs)
+
+			trace.Reset()
+			assert.Empty(t, trace.Steps())
+		})
+	}
+}
+
+func TestMappingErrorLocations(t *testing.T) {
+	tests := map[string]struct {
+		mapping string
+		input   string
+		err     string
+	}{
+		"method of statement": {
+			mapping: `root.a = this.a
+root.b = this.b.uppercase()`,
+			input: `{"a":"foo","b":5}`,
+			err: `line 2 char 17: expected string value, got number from field ` + "`this.b`" + ` (5)
+  |
+2 | root.b = this.b.uppercase()
+  |                 ^---`,
+		},
+		"method within lambda": {
+			mapping: `root = this.items.map_each(i -> i.number() + 1)`,
+			input:   `{"items":["1",{}]}`,
+			err: `line 1 char 35: field ` + "`this.items`" + `: failed to process element 1: expected number value, got object from field ` + "`i`" + `
+  |
+1 | root = this.items.map_each(i -> i.number() + 1)
+  |                                   ^---`,
+		},
+		"function of statement": {
+			mapping: `root.a = "foo"
+root.b = throw("nope")`,
+			input: `{}`,
+			err: `line 2 char 10: nope
+  |
+2 | root.b = throw("nope")
+  |          ^---`,
+		},
+		"arithmetic within map": {
+			mapping: `map foo {
+  root = this.a + this.b
+}
+root = this.apply("foo")`,
+			input: `{"a":"foo","b":5}`,
+			err: `line 2 char 10: cannot add types string (from field ` + "`this.a`" + `) and number (from field ` + "`this.b`" + `)
+  |
+2 |   root = this.a + this.b
+  |          ^---`,
+		},
+		"try catch": {
+			mapping: `try {
+  root.a = this.a.uppercase()
+} catch {
+  root.b = this.b.lowercase()
+}`,
+			input: `{"a":5,"b":6}`,
+			err: `line 4 char 19: expected string value, got number from field ` + "`this.b`" + ` (6)
+  |
+4 |   root.b = this.b.lowercase()
+  |                   ^---`,
+		},
+	}
+
+	for name, test := range tests {
+		test := test
+		t.Run(name, func(t *testing.T) {
+			exec, perr := ParseMapping(GlobalContext(), "", test.mapping)
+			require.Nil(t, perr)
+
+			_, err := exec.MapPart(0, message.New([][]byte{[]byte(test.input)}))
+			require.Error(t, err)
+
+			var sErr *mapping.StatementError
+			require.True(t, errors.As(err, &sErr))
+			assert.Equal(t, test.err, sErr.ErrorAtPositionStructured(""))
+		})
+	}
+}
+
+func TestMappingFileRelativeToMapping(t *testing.T) {
+	dir, err := ioutil.TempDir("", "benthos_mapping_relative")
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		os.RemoveAll(dir)
+	})
+
+	require.NoError(t, os.Mkdir(filepath.Join(dir, "lib"), 0777))
+	require.NoError(t, ioutil.WriteFile(filepath.Join(dir, "lookup.txt"), []byte(`top`), 0777))
+	require.NoError(t, ioutil.WriteFile(filepath.Join(dir, "lib", "lookup.txt"), []byte(`nested`), 0777))
+	require.NoError(t, ioutil.WriteFile(filepath.Join(dir, "lib", "maps.blobl"), []byte(`map foo {
+  root = file(path: "lookup.txt", relative_to_mapping: true).string()
+}`), 0777))
+
+	mapping := `import "./lib/maps.blobl"
+root.top = file(path: "lookup.txt", relative_to_mapping: true).string()
+root.nested = null.apply("foo")`
+
+	exec, perr := ParseMapping(GlobalContext(), filepath.Join(dir, "main.blobl"), mapping)
+	require.Nil(t, perr)
+
+	resPart, err := exec.MapPart(0, message.New([][]byte{[]byte(`{}`)}))
+	require.NoError(t, err)
+	assert.Equal(t, `{"nested":"nested","top":"top"}`, string(resPart.Get()))
+
+	_, perr = ParseMapping(GlobalContext(), "", `root = file(path: "lookup.txt", relative_to_mapping: true)`)
+	require.NotNil(t, perr)
+}
+
+func TestMappingImportFromModules(t *testing.T) {
+	dir, err := ioutil.TempDir("", "benthos_mapping_modules")
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		os.RemoveAll(dir)
+	})
+
+	modDir := filepath.Join(dir, ModulesDir, "github.com", "acme", "mappings")
+	require.NoError(t, os.MkdirAll(modDir, 0777))
+	require.NoError(t, ioutil.WriteFile(filepath.Join(modDir, "helpers.blobl"), []byte(`func shout(v) { v.uppercase() }`), 0777))
+	require.NoError(t, ioutil.WriteFile(filepath.Join(modDir, "normalize.blobl"), []byte(`import "./helpers.blobl"
+map normalize {
+  root.id = shout(this.id)
+}`), 0777))
+
+	mapping := `import "github.com/acme/mappings/normalize.blobl"
+root = this.apply("normalize")`
+
+	exec, perr := ParseMapping(GlobalContext(), filepath.Join(dir, "configs", "main.blobl"), mapping)
+	require.Nil(t, perr)
+
+	resPart, err := exec.MapPart(0, message.New([][]byte{[]byte(`{"id":"foo"}`)}))
+	require.NoError(t, err)
+	assert.Equal(t, `{"id":"FOO"}`, string(resPart.Get()))
+
+	// Explicitly relative imports are never resolved from modules.
+	mapping = `import "./github.com/acme/mappings/normalize.blobl"`
+	_, perr = ParseMapping(GlobalContext(), filepath.Join(dir, "configs", "main.blobl"), mapping)
+	require.NotNil(t, perr)
+	assert.Contains(t, perr.ErrorAtPosition([]rune(mapping)), "failed to read import")
+}
@@ -0,0 +1,50 @@
+package parser
+
+import (
+	"github.com/Jeffail/benthos/v3/internal/bloblang/query"
+)
+
+// positionedFunction wraps a function in order to attach the input it was
+// parsed from to any errors that it returns, allowing runtime errors to be
+// traced back to the expression that caused them.
+type positionedFunction struct {
+	query.Function
+	input []rune
+}
+
+func (p *positionedFunction) Exec(ctx query.FunctionContext) (interface{}, error) {
+	v, err := p.Function.Exec(ctx)
+	if err != nil {
+		err = query.ErrAt(err, p.input)
+	}
+	return v, err
+}
+
+// positionedIterable is a positionedFunction that preserves the ability of the
+// function it wraps to be iterated.
+type positionedIterable struct {
+	positionedFunction
+	iter query.Iterable
+}
+
+func (p *positionedIterable) TryIterate(ctx query.FunctionContext) (query.Iterator, interface{}, error) {
+	iter, v, err := p.iter.TryIterate(ctx)
+	if err != nil {
+		err = query.ErrAt(err, p.input)
+	}
+	return iter, v, err
+}
+
+// positioned wraps a function such that errors it returns carry the input it
+// was parsed from. Literals are left as they are since they never fail, and
+// other parsers rely on detecting them.
+func positioned(input []rune, fn query.Function) query.Function {
+	if _, isLit := fn.(*query.Literal); isLit {
+		return fn
+	}
+	p := positionedFunction{Function: fn, input: input}
+	if iter, isIter := fn.(query.Iterable); isIter {
+		return &positionedIterable{positionedFunction: p, iter: iter}
+	}
+	return &p
+}
@@ -125,6 +125,9 @@ func arithmeticParser(fnParser Func) Func {
 		if err != nil {
 			return Fail(NewFatalError(input, err), input)
 		}
+		if len(ops) > 0 {
+			fn = positioned(input, fn)
+		}
 		return Success(fn, res.Remaining)
 	}
 }
@@ -1,35 +1,94 @@
 package parser
 
 import (
+	"errors"
 	"fmt"
+	"sort"
+	"strings"
 
 	"github.com/Jeffail/benthos/v3/internal/bloblang/query"
 )
 
+// matchPatternTypes are the types that can be matched by type patterns within
+// match expressions.
+var matchPatternTypes = map[string]query.ValueType{
+	"string": query.ValueString,
+	"bytes":  query.ValueBytes,
+	"number": query.ValueNumber,
+	"bool":   query.ValueBool,
+	"array":  query.ValueArray,
+	"object": query.ValueObject,
+	"null":   query.ValueNull,
+}
+
+// matchScalarTypes are the types that a match expression using type patterns
+// is expected to handle, otherwise a warning is emitted.
+var matchScalarTypes = []query.ValueType{
+	query.ValueString,
+	query.ValueNumber,
+	query.ValueBool,
+	query.ValueNull,
+}
+
+type matchCase struct {
+	query.MatchCase
+	input []rune
+
+	// Set when the case is a type pattern.
+	valueType query.ValueType
+
+	// Set when the case is a scalar literal.
+	literal interface{}
+
+	// Set when the case is a catch-all.
+	catchAll bool
+}
+
+func matchTypePatternParser() Func {
+	typeNames := make([]string, 0, len(matchPatternTypes))
+	for k := range matchPatternTypes {
+		typeNames = append(typeNames, k)
+	}
+	sort.Strings(typeNames)
+
+	typeParsers := make([]Func, len(typeNames))
+	for i, k := range typeNames {
+		typeParsers[i] = Term(k)
+	}
+
+	return Sequence(
+		OneOf(typeParsers...),
+		SpacesAndTabs(),
+		OneOf(
+			Char('_'),
+			varNameParser(),
+		),
+		Optional(SpacesAndTabs()),
+		Term("=>"),
+	)
+}
+
 func matchCaseParser(pCtx Context) Func {
 	whitespace := SpacesAndTabs()
 
-	p := Sequence(
-		OneOf(
-			Sequence(
-				Expect(
-					Char('_'),
-					"match case",
-				),
-				Optional(whitespace),
-				Term("=>"),
+	p := OneOf(
+		matchTypePatternParser(),
+		Sequence(
+			Expect(
+				Char('_'),
+				"match case",
 			),
-			Sequence(
-				Expect(
-					queryParser(pCtx),
-					"match case",
-				),
-				Optional(whitespace),
-				Term("=>"),
+			Optional(whitespace),
+			Term("=>"),
+		),
+		Sequence(
+			Expect(
+				queryParser(pCtx),
+				"match case",
 			),
+			Optional(whitespace),
+			Term("=>"),
 		),
-		Optional(whitespace),
-		queryParser(pCtx),
 	)
 
 	return func(input []rune) Result {
@@ -40,10 +99,43 @@ func matchCaseParser(pCtx Context) Func {
 
 		seqSlice := res.Payload.([]interface{})
 
+		// Type patterns are the only case with five elements.
+		if len(seqSlice) == 5 {
+			valueType := matchPatternTypes[seqSlice[0].(string)]
+			name := seqSlice[2].(string)
+
+			queryCtx := pCtx
+			if name != "_" {
+				if pCtx.HasNamedContext(name) {
+					return Fail(NewFatalError(input, fmt.Errorf("context label `%v` would shadow a parent context", name)), input)
+				}
+				if name == "root" || name == "this" {
+					return Fail(NewFatalError(input, fmt.Errorf("context label `%v` is not allowed", name)), input)
+				}
+				queryCtx = pCtx.WithNamedContext(name)
+			}
+
+			res = Sequence(Optional(whitespace), coveredQueryParser("branch", queryCtx))(res.Remaining)
+			if res.Err != nil {
+				return Fail(res.Err, input)
+			}
+			return Success(matchCase{
+				MatchCase: query.NewTypeMatchCase(valueType, name, res.Payload.([]interface{})[1].(query.Function)),
+				input:     input,
+				valueType: valueType,
+			}, res.Remaining)
+		}
+
 		var caseFn query.Function
-		switch t := seqSlice[0].([]interface{})[0].(type) {
+		var catchAll bool
+		var literal interface{}
+		switch t := seqSlice[0].(type) {
 		case query.Function:
 			if lit, isLiteral := t.(*query.Literal); isLiteral {
+				switch lit.Value.(type) {
+				case string, int64, float64, bool:
+					literal = lit.Value
+				}
 				caseFn = query.ClosureFunction("case statement", func(ctx query.FunctionContext) (interface{}, error) {
 					v := ctx.Value()
 					if v == nil {
@@ -56,12 +148,19 @@ func matchCaseParser(pCtx Context) Func {
 			}
 		case string:
 			caseFn = query.NewLiteralFunction("", true)
+			catchAll = true
 		}
 
-		return Success(
-			query.NewMatchCase(caseFn, seqSlice[2].(query.Function)),
-			res.Remaining,
-		)
+		res = Sequence(Optional(whitespace), coveredQueryParser("branch", pCtx))(res.Remaining)
+		if res.Err != nil {
+			return Fail(res.Err, input)
+		}
+		return Success(matchCase{
+			MatchCase: query.NewMatchCase(caseFn, res.Payload.([]interface{})[1].(query.Function)),
+			input:     input,
+			literal:   literal,
+			catchAll:  catchAll,
+		}, res.Remaining)
 	}
 }
 
@@ -110,8 +209,43 @@ func matchExpressionParser(pCtx Context) Func {
 		contextFn, _ := seqSlice[2].(query.Function)
 
 		cases := []query.MatchCase{}
+		handledTypes := map[query.ValueType]struct{}{}
+		handledLiterals := map[interface{}]struct{}{}
+		var catchAll bool
 		for _, caseVal := range seqSlice[4].([]interface{}) {
-			cases = append(cases, caseVal.(query.MatchCase))
+			c := caseVal.(matchCase)
+			cases = append(cases, c.MatchCase)
+			if catchAll {
+				pCtx.addWarning(c.input, "match case is unreachable as it follows a catch-all case")
+				continue
+			}
+			if c.valueType != "" {
+				if _, exists := handledTypes[c.valueType]; exists {
+					pCtx.addWarning(c.input, fmt.Sprintf("match case is unreachable as the type %v is already handled by a previous case", c.valueType))
+				}
+				handledTypes[c.valueType] = struct{}{}
+			}
+			if c.literal != nil {
+				if _, exists := handledLiterals[c.literal]; exists {
+					pCtx.addWarning(c.input, fmt.Sprintf("match case is unreachable as the value %#v is already handled by a previous case", c.literal))
+				}
+				handledLiterals[c.literal] = struct{}{}
+			}
+			if c.catchAll {
+				catchAll = true
+			}
+		}
+
+		if len(handledTypes) > 0 && !catchAll {
+			var unhandled []string
+			for _, t := range matchScalarTypes {
+				if _, exists := handledTypes[t]; !exists {
+					unhandled = append(unhandled, string(t))
+				}
+			}
+			if len(unhandled) > 0 {
+				pCtx.addWarning(input, fmt.Sprintf("match expression does not handle the types %v, consider adding a catch-all case `_ => ...`", strings.Join(unhandled, ", ")))
+			}
 		}
 
 		res.Payload = query.NewMatchFunction(contextFn, cases...)
@@ -135,7 +269,7 @@ func ifExpressionParser(pCtx Context) Func {
 			optionalWhitespace,
 			MustBe(Char('{')),
 			optionalWhitespace,
-			MustBe(queryParser(pCtx)),
+			MustBe(coveredQueryParser("branch", pCtx)),
 			optionalWhitespace,
 			MustBe(Char('}')),
 		)
@@ -148,7 +282,7 @@ func ifExpressionParser(pCtx Context) Func {
 			optionalWhitespace,
 			MustBe(Char('{')),
 			optionalWhitespace,
-			MustBe(queryParser(pCtx)),
+			MustBe(coveredQueryParser("branch", pCtx)),
 			optionalWhitespace,
 			MustBe(Char('}')),
 		))
@@ -159,7 +293,7 @@ func ifExpressionParser(pCtx Context) Func {
 			optionalWhitespace,
 			MustBe(Char('{')),
 			optionalWhitespace,
-			MustBe(queryParser(pCtx)),
+			MustBe(coveredQueryParser("branch", pCtx)),
 			optionalWhitespace,
 			MustBe(Char('}')),
 		))
@@ -204,6 +338,49 @@ func ifExpressionParser(pCtx Context) Func {
 	}
 }
 
+func whileExpressionParser(pCtx Context) Func {
+	optionalWhitespace := DiscardAll(
+		OneOf(
+			SpacesAndTabs(),
+			NewlineAllowComment(),
+		),
+	)
+
+	return func(input []rune) Result {
+		res := Sequence(
+			Term("while"),
+			SpacesAndTabs(),
+			MustBe(queryParser(pCtx)),
+			optionalWhitespace,
+			MustBe(Expect(Term("max"), "maximum iterations")),
+			SpacesAndTabs(),
+			MustBe(Expect(Number(), "maximum iterations")),
+			optionalWhitespace,
+			MustBe(Char('{')),
+			optionalWhitespace,
+			MustBe(queryParser(pCtx)),
+			optionalWhitespace,
+			MustBe(Char('}')),
+		)(input)
+		if res.Err != nil {
+			return res
+		}
+
+		seqSlice := res.Payload.([]interface{})
+		maxIterations, isInt := seqSlice[6].(int64)
+		if !isInt || maxIterations < 1 {
+			return Fail(NewFatalError(input, fmt.Errorf("maximum iterations must be a positive integer, got %v", seqSlice[6])), input)
+		}
+
+		res.Payload = query.NewWhileFunction(
+			seqSlice[2].(query.Function),
+			seqSlice[10].(query.Function),
+			maxIterations,
+		)
+		return res
+	}
+}
+
 func bracketsExpressionParser(pCtx Context) Func {
 	whitespace := DiscardAll(
 		OneOf(
@@ -245,10 +422,31 @@ func lambdaExpressionParser(pCtx Context) Func {
 		"context name",
 	)
 
+	contextNamesParser := OneOf(
+		DelimitedPattern(
+			Sequence(
+				Char('('),
+				Discard(SpacesAndTabs()),
+			),
+			contextNameParser,
+			Sequence(
+				Discard(SpacesAndTabs()),
+				Char(','),
+				Discard(SpacesAndTabs()),
+			),
+			Sequence(
+				Discard(SpacesAndTabs()),
+				Char(')'),
+			),
+			false,
+		),
+		contextNameParser,
+	)
+
 	return func(input []rune) Result {
 		res := Expect(
 			Sequence(
-				contextNameParser,
+				contextNamesParser,
 				SpacesAndTabs(),
 				Term("->"),
 				SpacesAndTabs(),
@@ -259,10 +457,28 @@ func lambdaExpressionParser(pCtx Context) Func {
 			return res
 		}
 
-		seqSlice := res.Payload.([]interface{})
-		name := seqSlice[0].(string)
+		var names []string
+		switch t := res.Payload.([]interface{})[0].(type) {
+		case string:
+			names = []string{t}
+		case []interface{}:
+			for _, v := range t {
+				names = append(names, v.(string))
+			}
+		}
+		if len(names) == 0 {
+			return Fail(NewFatalError(input, errors.New("expected at least one context label")), input)
+		}
 
-		if name != "_" {
+		seen := map[string]struct{}{}
+		for _, name := range names {
+			if name == "_" {
+				continue
+			}
+			if _, exists := seen[name]; exists {
+				return Fail(NewFatalError(input, fmt.Errorf("context label `%v` is declared more than once", name)), input)
+			}
+			seen[name] = struct{}{}
 			if pCtx.HasNamedContext(name) {
 				return Fail(NewFatalError(input, fmt.Errorf("context label `%v` would shadow a parent context", name)), input)
 			}
@@ -282,11 +498,15 @@ func lambdaExpressionParser(pCtx Context) Func {
 
 		queryFn := res.Payload.(query.Function)
 		if chained, isChained := queryFn.(*query.NamedContextFunction); isChained {
-			err := fmt.Errorf("it would be in poor taste to capture the same context under both '%v' and '%v'", name, chained.Name())
+			err := fmt.Errorf("it would be in poor taste to capture the same context under both '%v' and '%v'", strings.Join(names, ", "), chained.Name())
 			return Fail(NewFatalError(input, err), input)
 		}
 
-		res.Payload = query.NewNamedContextFunction(name, queryFn)
+		if len(names) == 1 {
+			res.Payload = query.NewNamedContextFunction(names[0], queryFn)
+		} else {
+			res.Payload = query.NewMultiNamedContextFunction(names, queryFn)
+		}
 		return res
 	}
 }
@@ -241,6 +241,57 @@ func TestExpressionsParser(t *testing.T) {
 				{content: `{"type":"none of them"}`},
 			},
 		},
+		"match type patterns": {
+			input: `json("values").map_each(match {
+  string s => s.uppercase()
+  number n => n * 2
+  array a => a.length()
+  object _ => "object"
+  _ => "other"
+})`,
+			output: `["FOO",10,3,"object","other"]`,
+			messages: []easyMsg{
+				{content: `{"values":["foo",5,[1,2,3],{"a":"b"},true]}`},
+			},
+		},
+		"match type patterns with context": {
+			input: `match json("value") {
+  string v => v + " is a string"
+  null _ => "null"
+  _ => this.string() + " is not a string"
+}`,
+			output: `5 is not a string`,
+			messages: []easyMsg{
+				{content: `{"value":5}`},
+			},
+		},
+		"match type pattern binding this": {
+			input: `match json("value") {
+  string v => v + this
+  _ => "nope"
+}`,
+			output: `foofoo`,
+			messages: []easyMsg{
+				{content: `{"value":"foo"}`},
+			},
+		},
+		"while linked list": {
+			input: `json("head").(while this.next != null max 10 {
+  this.next
+}).value`,
+			output: `third`,
+			messages: []easyMsg{
+				{content: `{"head":{"value":"first","next":{"value":"second","next":{"value":"third","next":null}}}}`},
+			},
+		},
+		"while inline": {
+			input:  `1.(while this < 100 max 10 { this * 3 })`,
+			output: `243`,
+		},
+		"while false condition": {
+			input:  `"foo".(while false max 1 { "bar" })`,
+			output: `foo`,
+		},
 	}
 
 	for name, test := range tests {
@@ -274,3 +325,51 @@ func TestExpressionsParser(t *testing.T) {
 		})
 	}
 }
+
+func TestWhileExpression(t *testing.T) {
+	tests := map[string]struct {
+		input    string
+		parseErr string
+		execErr  string
+	}{
+		"missing max": {
+			input:    `while this < 10 { this + 1 }`,
+			parseErr: `required: expected maximum iterations`,
+		},
+		"float max": {
+			input:    `while this < 10 max 1.5 { this + 1 }`,
+			parseErr: `maximum iterations must be a positive integer, got 1.5`,
+		},
+		"zero max": {
+			input:    `while this < 10 max 0 { this + 1 }`,
+			parseErr: `maximum iterations must be a positive integer, got 0`,
+		},
+		"exceeds max": {
+			input:   `5.(while this < 10 max 3 { this + 1 })`,
+			execErr: `loop exceeded the maximum of 3 iterations`,
+		},
+		"non bool condition": {
+			input:   `5.(while this max 3 { this + 1 })`,
+			execErr: "failed to check while condition: expected bool value, got number from field `this` (5)",
+		},
+	}
+
+	for name, test := range tests {
+		test := test
+		t.Run(name, func(t *testing.T) {
+			e, perr := tryParseQuery(test.input, false)
+			if test.parseErr != "" {
+				require.NotNil(t, perr)
+				assert.Contains(t, perr.ErrorAtPosition([]rune(test.input)), test.parseErr)
+				return
+			}
+			require.Nil(t, perr)
+
+			_, err := e.Exec(query.FunctionContext{
+				MsgBatch: message.New(nil),
+			})
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), test.execErr)
+		})
+	}
+}
@@ -192,7 +192,7 @@ func fieldLiteralMapParser(ctxFn query.Function) Func {
 	}
 }
 
-func variableLiteralParser() Func {
+func variableLiteralParser(pCtx Context) Func {
 	varPathParser := Expect(
 		Sequence(
 			Char('$'),
@@ -217,7 +217,10 @@ func variableLiteralParser() Func {
 		}
 
 		path := res.Payload.([]interface{})[1].(string)
-		fn := query.NewVarFunction(path)
+		fn := pCtx.typed(query.NewVarFunction(path), pCtx.varTypes[path])
+		if pCtx.usedVars != nil {
+			pCtx.usedVars[path] = struct{}{}
+		}
 
 		return Success(fn, res.Remaining)
 	}
@@ -302,10 +305,37 @@ func extractArgsParserResult(paramsDef query.Params, args []interface{}) (*query
 	return parsedParams, nil
 }
 
+// namespacedNameParser parses a snake case name that may be prefixed with a
+// namespace, e.g. `acme.lookup_sku`. Since a namespace is syntactically
+// ambiguous with a field path the prefixed form is only consumed when the
+// provided func reports that the full name exists.
+func namespacedNameParser(exists func(name string) bool) Func {
+	nameParser := SnakeCase()
+	nsParser := Sequence(SnakeCase(), Char('.'), SnakeCase())
+
+	return func(input []rune) Result {
+		res := nameParser(input)
+		if res.Err != nil {
+			return res
+		}
+		if nsRes := nsParser(input); nsRes.Err == nil {
+			seqSlice := nsRes.Payload.([]interface{})
+			name := seqSlice[0].(string) + "." + seqSlice[2].(string)
+			if exists(name) {
+				return Success(name, nsRes.Remaining)
+			}
+		}
+		return res
+	}
+}
+
 func methodParser(fn query.Function, pCtx Context) Func {
 	p := Sequence(
 		Expect(
-			SnakeCase(),
+			namespacedNameParser(func(name string) bool {
+				_, err := pCtx.Methods.Params(name)
+				return err == nil
+			}),
 			"method",
 		),
 		functionArgsParser(pCtx),
@@ -334,14 +364,19 @@ func methodParser(fn query.Function, pCtx Context) Func {
 		if err != nil {
 			return Fail(NewFatalError(input, err), input)
 		}
-		return Success(method, res.Remaining)
+		pCtx.warnIfDeprecatedMethod(input, targetMethod)
+		method = pCtx.typed(positioned(input, method), pCtx.checkMethodTarget(input, targetMethod, fn))
+		return Success(pCtx.traced(input, res.Remaining, "method", method), res.Remaining)
 	}
 }
 
 func functionParser(pCtx Context) Func {
 	p := Sequence(
 		Expect(
-			SnakeCase(),
+			namespacedNameParser(func(name string) bool {
+				_, err := pCtx.FunctionParams(name)
+				return err == nil
+			}),
 			"function",
 		),
 		functionArgsParser(pCtx),
@@ -356,7 +391,7 @@ func functionParser(pCtx Context) Func {
 		seqSlice := res.Payload.([]interface{})
 
 		targetFunc := seqSlice[0].(string)
-		params, err := pCtx.Functions.Params(targetFunc)
+		params, err := pCtx.FunctionParams(targetFunc)
 		if err != nil {
 			return Fail(NewFatalError(input, err), input)
 		}
@@ -370,7 +405,8 @@ func functionParser(pCtx Context) Func {
 		if err != nil {
 			return Fail(NewFatalError(input, err), input)
 		}
-		return Success(fn, res.Remaining)
+		pCtx.warnIfDeprecatedFunction(input, targetFunc)
+		return Success(positioned(input, fn), res.Remaining)
 	}
 }
 
@@ -1,6 +1,11 @@
 package parser
 
 import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
 	"github.com/Jeffail/benthos/v3/internal/bloblang/query"
 )
 
@@ -97,12 +102,108 @@ func dynamicObjectParser(pCtx Context) Func {
 	}
 }
 
+// interpolatedStringParser parses a quoted string prefixed with `$`, where any
+// sections of the form `{<query>}` are replaced with the result of the query
+// when executed. Braces can be written literally by doubling them, e.g. `{{`.
+func interpolatedStringParser(pCtx Context) Func {
+	return func(input []rune) Result {
+		if len(input) < 2 || input[0] != '$' || input[1] != '"' {
+			return Fail(NewError(input, "interpolated string"), input)
+		}
+
+		var fns []query.Function
+		var static strings.Builder
+		var chunk []rune
+
+		// Static chunks are unescaped as they are completed, as an escape
+		// sequence cannot span an interpolation.
+		flushChunk := func() error {
+			if len(chunk) > 0 {
+				unquoted, err := strconv.Unquote(`"` + string(chunk) + `"`)
+				if err != nil {
+					return fmt.Errorf("failed to unescape quoted string contents: %v", err)
+				}
+				static.WriteString(unquoted)
+				chunk = nil
+			}
+			return nil
+		}
+		flushStatic := func() {
+			if static.Len() > 0 {
+				fns = append(fns, query.NewLiteralFunction("", static.String()))
+				static.Reset()
+			}
+		}
+
+		for i := 2; i < len(input); {
+			switch input[i] {
+			case '\n':
+				return Fail(NewFatalError(input[i:], errors.New("required"), "end quote"), input)
+			case '"':
+				if err := flushChunk(); err != nil {
+					return Fail(NewFatalError(input, err), input)
+				}
+				flushStatic()
+				if len(fns) == 0 {
+					return Success(query.NewLiteralFunction("", ""), input[i+1:])
+				}
+				if len(fns) == 1 {
+					if lit, isLit := fns[0].(*query.Literal); isLit {
+						return Success(lit, input[i+1:])
+					}
+				}
+				return Success(query.NewInterpolatedStringFunction(fns), input[i+1:])
+			case '\\':
+				chunk = append(chunk, input[i])
+				if i+1 < len(input) {
+					chunk = append(chunk, input[i+1])
+				}
+				i += 2
+				continue
+			case '{', '}':
+				if i+1 < len(input) && input[i+1] == input[i] {
+					chunk = append(chunk, input[i])
+					i += 2
+					continue
+				}
+				if input[i] == '}' {
+					return Fail(NewFatalError(input[i:], errors.New("unexpected closing brace, use }} to write a literal brace")), input)
+				}
+				if err := flushChunk(); err != nil {
+					return Fail(NewFatalError(input, err), input)
+				}
+				flushStatic()
+
+				res := queryParser(pCtx)(input[i+1:])
+				if res.Err != nil {
+					if !res.Err.IsFatal() {
+						res.Err = NewFatalError(res.Err.Input, errors.New("required"), "query")
+					}
+					return Fail(res.Err, input)
+				}
+				fns = append(fns, res.Payload.(query.Function))
+
+				remaining := res.Remaining
+				if res = Sequence(Discard(SpacesAndTabs()), Char('}'))(remaining); res.Err != nil {
+					return Fail(NewFatalError(remaining, errors.New("required"), "end of interpolation"), input)
+				}
+				i = len(input) - len(res.Remaining)
+				continue
+			}
+			chunk = append(chunk, input[i])
+			i++
+		}
+		return Fail(NewFatalError(input[len(input):], errors.New("required"), "end quote"), input)
+	}
+}
+
 func literalValueParser(pCtx Context) Func {
 	p := OneOf(
 		Boolean(),
 		Number(),
 		TripleQuoteString(),
 		QuotedString(),
+		interpolatedStringParser(pCtx),
 		Null(),
 		dynamicArrayParser(pCtx),
 		dynamicObjectParser(pCtx),
@@ -22,6 +22,18 @@ func TestLiteralParserErrors(t *testing.T) {
 			input: `[5,null,"unterminated string]`,
 			err:   `line 1 char 30: required: expected end quote`,
 		},
+		"unterminated interpolation": {
+			input: `$"foo { 5 + 5 "`,
+			err:   `line 1 char 14: required: expected end of interpolation`,
+		},
+		"empty interpolation": {
+			input: `$"foo {}"`,
+			err:   `line 1 char 8: required: expected query`,
+		},
+		"unescaped interpolation closing brace": {
+			input: `$"foo } bar"`,
+			err:   `line 1 char 7: unexpected closing brace, use }} to write a literal brace`,
+		},
 	}
 
 	for name, test := range tests {
@@ -98,6 +110,33 @@ func TestLiteralParser(t *testing.T) {
 				"foo", []interface{}{int64(10), "bar"}, nil,
 			},
 		},
+		"string with interpolation syntax": {
+			mapping: `"foo ${! this.id } {this.id}"`,
+			result:  `foo ${! this.id } {this.id}`,
+		},
+		"interpolated string without interpolations": {
+			mapping: `$"foo \"bar\" {{baz}}"`,
+			result:  `foo "bar" {baz}`,
+		},
+		"interpolated string": {
+			mapping: `$"foo {this.id} bar { this.tags.join(", ") }"`,
+			value: func() *interface{} {
+				var v interface{} = map[string]interface{}{
+					"id":   int64(5),
+					"tags": []interface{}{"a", "b"},
+				}
+				return &v
+			}(),
+			result: `foo 5 bar a, b`,
+		},
+		"interpolated string of structured values": {
+			mapping: `$"{ {"id": 5 + 5} }\t{[1, 2]}"`,
+			result:  "{\"id\":10}\t[1,2]",
+		},
+		"interpolated string error": {
+			mapping: `$"foo { "bar".number() }"`,
+			err:     "string literal: strconv.ParseFloat: parsing \"bar\": invalid syntax",
+		},
 		"bad array element": {
 			mapping:  `["foo",(5 + "not a number"),"bar"]`,
 			parseErr: "cannot add types number (from number literal) and string (from string literal): 5 + \"",
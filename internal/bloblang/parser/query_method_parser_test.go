@@ -210,6 +210,41 @@ func TestMethodParser(t *testing.T) {
 				{content: `{"foo":[1,2,2]}`},
 			},
 		},
+		"map each multiple params": {
+			input:  `json("foo").map_each((v, i) -> v * i)`,
+			output: `[0,2,4]`,
+			messages: []easyMsg{
+				{content: `{"foo":[1,2,2]}`},
+			},
+		},
+		"map each object multiple params": {
+			input:  `json("foo").map_each((k, _) -> k.uppercase())`,
+			output: `{"a":"A","b":"B"}`,
+			messages: []easyMsg{
+				{content: `{"foo":{"a":1,"b":2}}`},
+			},
+		},
+		"fold multiple params": {
+			input:  `json("foo").fold("", (tally, value) -> tally + value)`,
+			output: `abc`,
+			messages: []easyMsg{
+				{content: `{"foo":["a","b","c"]}`},
+			},
+		},
+		"zip with multiple params": {
+			input:  `json("foo").zip_with(json("bar"), (l, r) -> l + r)`,
+			output: `[11,22]`,
+			messages: []easyMsg{
+				{content: `{"foo":[1,2],"bar":[10,20]}`},
+			},
+		},
+		"zip with single param": {
+			input:  `json("foo").zip_with(json("bar"), pair -> pair.right - pair.left)`,
+			output: `[9,18]`,
+			messages: []easyMsg{
+				{content: `{"foo":[1,2],"bar":[10,20]}`},
+			},
+		},
 		"map each inner map": {
 			input:  `json("foo").map_each((this.bar + 10) | "woops")`,
 			output: `[11,"woops",12]`,
@@ -492,6 +527,16 @@ func TestMethodErrors(t *testing.T) {
 			errStr:   "string literal: strconv.ParseFloat: parsing \"not a number\": invalid syntax",
 			messages: []easyMsg{{}},
 		},
+		"lambda params mismatch": {
+			input:    `json("foo").all((a, b) -> true)`,
+			errStr:   "json path `foo`: element 0: failed to capture context (a, b): expected 2 parameters, got number",
+			messages: []easyMsg{{content: `{"foo":[1]}`}},
+		},
+		"zip with mismatched lengths": {
+			input:    `json("foo").zip_with([1, 2], (a, b) -> a + b)`,
+			errStr:   "json path `foo`: cannot zip an array of length 1 with an array of length 2",
+			messages: []easyMsg{{content: `{"foo":[1]}`}},
+		},
 	}
 
 	for name, test := range tests {
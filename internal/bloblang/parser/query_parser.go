@@ -1,6 +1,10 @@
 package parser
 
 import (
+	"fmt"
+	"strings"
+
+	"github.com/Jeffail/benthos/v3/internal/bloblang/mapping"
 	"github.com/Jeffail/benthos/v3/internal/bloblang/query"
 )
 
@@ -22,6 +26,54 @@ type Context struct {
 	Functions    FunctionSet
 	Methods      MethodSet
 	namedContext *namedContext
+	mappingDir   string
+
+	// Functions declared within the mapping being parsed, these take
+	// precedence over the functions of the FunctionSet.
+	userFunctions map[string]*query.UserFunction
+
+	// The chain of files being imported, used for detecting cycles, and the
+	// files already imported during a parse, which allows a file imported by
+	// multiple others to be parsed only once.
+	importChain *importLink
+	imports     map[string]importedFile
+
+	// Collects warnings found whilst parsing when set, along with the names
+	// of variables referenced by queries, which includes those of imported
+	// files, and the types inferred for variables assigned by the mapping.
+	warnings *[]Warning
+	usedVars map[string]struct{}
+	varTypes map[string]query.ValueType
+
+	// Records the execution of statements and branches when set.
+	coverage *Coverage
+
+	// Records the values produced by assignments and methods when set.
+	trace *Trace
+}
+
+type importLink struct {
+	path string
+	next *importLink
+}
+
+type importedFile struct {
+	exec  *mapping.Executor
+	funcs map[string]*query.UserFunction
+}
+
+// withImport returns a Context with a file added to the chain of imports, or
+// an error if the file is already being imported further up the chain.
+func (pCtx Context) withImport(path string) (Context, error) {
+	chain := []string{path}
+	for link := pCtx.importChain; link != nil; link = link.next {
+		chain = append([]string{link.path}, chain...)
+		if link.path == path {
+			return pCtx, fmt.Errorf("import cycle detected: %v", strings.Join(chain, " -> "))
+		}
+	}
+	pCtx.importChain = &importLink{path, pCtx.importChain}
+	return pCtx, nil
 }
 
 // GlobalContext returns a parser context with globally defined functions and
@@ -33,6 +85,14 @@ func GlobalContext() Context {
 	}
 }
 
+// WithMappingDir returns a Context where relative paths referenced by the
+// mapping, such as those of the file function, are resolved from a directory.
+// Mappings parsed from a file use the directory of that file instead.
+func (pCtx Context) WithMappingDir(dir string) Context {
+	pCtx.mappingDir = dir
+	return pCtx
+}
+
 type namedContext struct {
 	name string
 	next *namedContext
@@ -57,9 +117,24 @@ func (pCtx Context) HasNamedContext(name string) bool {
 	return false
 }
 
+// FunctionParams attempts to obtain the parameters definition of a function
+// from the available constructors of the parser context.
+func (pCtx Context) FunctionParams(name string) (query.Params, error) {
+	if uFn, exists := pCtx.userFunctions[name]; exists {
+		return uFn.Params(), nil
+	}
+	return pCtx.Functions.Params(name)
+}
+
 // InitFunction attempts to initialise a function from the available
 // constructors of the parser context.
 func (pCtx Context) InitFunction(name string, args *query.ParsedParams) (query.Function, error) {
+	if uFn, exists := pCtx.userFunctions[name]; exists {
+		return uFn.Init(args)
+	}
+	if pCtx.mappingDir != "" && args != nil {
+		args = args.WithMappingDir(pCtx.mappingDir)
+	}
 	return pCtx.Functions.Init(name, args)
 }
 
@@ -74,11 +149,12 @@ func queryParser(pCtx Context) func(input []rune) Result {
 		OneOf(
 			matchExpressionParser(pCtx),
 			ifExpressionParser(pCtx),
+			whileExpressionParser(pCtx),
 			lambdaExpressionParser(pCtx),
 			bracketsExpressionParser(pCtx),
 			literalValueParser(pCtx),
 			functionParser(pCtx),
-			variableLiteralParser(),
+			variableLiteralParser(pCtx),
 			fieldLiteralRootParser(pCtx),
 		),
 		"query",
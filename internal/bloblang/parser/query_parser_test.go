@@ -130,6 +130,14 @@ func TestFunctionParserErrors(t *testing.T) {
 			input: `this.(root -> root.foo)`,
 			err:   "line 1 char 7: context label `root` is not allowed",
 		},
+		"shadowed multiple params context": {
+			input: `this.(foo -> foo.map_each((bar, foo) -> bar))`,
+			err:   "line 1 char 27: context label `foo` would shadow a parent context",
+		},
+		"duplicate params context": {
+			input: `this.map_each((foo, foo) -> foo)`,
+			err:   "line 1 char 15: context label `foo` is declared more than once",
+		},
 		"chained captured context": {
 			input: `this.(foo -> bar -> baz -> baz.foo)`,
 			err:   "line 1 char 14: it would be in poor taste to capture the same context under both 'bar' and 'baz'",
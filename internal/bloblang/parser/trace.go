@@ -0,0 +1,112 @@
+package parser
+
+import (
+	"sync"
+
+	"github.com/Jeffail/benthos/v3/internal/bloblang/mapping"
+	"github.com/Jeffail/benthos/v3/internal/bloblang/query"
+)
+
+// TraceStep is a record of an assignment or method of a mapping being
+// executed, along with the value it produced or the error it returned.
+//
+// The input at the step can be used in order to infer where exactly in the
+// input the step is with len(input) - len(s.Input).
+type TraceStep struct {
+	Input      []rune
+	Kind       string
+	Expression string
+	Value      interface{}
+	Err        error
+}
+
+// LineAndCol returns the line and column position of the step within the input
+// that was parsed.
+func (s TraceStep) LineAndCol(input []rune) (line, col int) {
+	return LineAndColOf(input, s.Input)
+}
+
+// Trace records the assignments and methods of a mapping in the order that
+// they are executed.
+type Trace struct {
+	mut   sync.Mutex
+	steps []TraceStep
+}
+
+// Steps returns all steps recorded since the trace was last reset.
+func (t *Trace) Steps() []TraceStep {
+	t.mut.Lock()
+	defer t.mut.Unlock()
+
+	steps := make([]TraceStep, len(t.steps))
+	copy(steps, t.steps)
+	return steps
+}
+
+// Reset clears all recorded steps, which is usually done before each execution
+// of the mapping.
+func (t *Trace) Reset() {
+	t.mut.Lock()
+	t.steps = nil
+	t.mut.Unlock()
+}
+
+func (t *Trace) add(step TraceStep) {
+	t.mut.Lock()
+	t.steps = append(t.steps, step)
+	t.mut.Unlock()
+}
+
+type tracedFunction struct {
+	query.Function
+	trace *Trace
+	input []rune
+	kind  string
+	expr  string
+}
+
+func (t *tracedFunction) Exec(ctx query.FunctionContext) (interface{}, error) {
+	v, err := t.Function.Exec(ctx)
+	t.trace.add(TraceStep{
+		Input:      t.input,
+		Kind:       t.kind,
+		Expression: t.expr,
+		Value:      v,
+		Err:        err,
+	})
+	return v, err
+}
+
+// traced wraps a function so that its results are recorded as a step of the
+// trace, if the context is tracing. The expression of the step is the input
+// consumed up to the remaining input.
+func (pCtx Context) traced(input, remaining []rune, kind string, fn query.Function) query.Function {
+	if pCtx.trace == nil {
+		return fn
+	}
+	return &tracedFunction{
+		Function: fn,
+		trace:    pCtx.trace,
+		input:    input,
+		kind:     kind,
+		expr:     string(input[:len(input)-len(remaining)]),
+	}
+}
+
+// ParseMappingWithTrace parses a bloblang mapping and returns an executor to
+// run it along with a trace that records the value produced by each assignment
+// and method of the mapping as it is executed, or an error if the parsing
+// fails. Steps of imported files are not recorded.
+//
+// The filepath is optional and used for relative file imports and error
+// messages.
+func ParseMappingWithTrace(pCtx Context, filepath, expr string) (*mapping.Executor, *Trace, *Error) {
+	trace := &Trace{}
+	pCtx.trace = trace
+
+	exec, err := parseMapping(pCtx, filepath, expr)
+	if err != nil {
+		return nil, nil, err
+	}
+	return exec, trace, nil
+}
@@ -0,0 +1,102 @@
+package parser
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/Jeffail/benthos/v3/internal/bloblang/mapping"
+	"github.com/Jeffail/benthos/v3/internal/bloblang/query"
+)
+
+// typedFunction wraps a function with the type of value that it is known to
+// return, which allows the types of method chains to be inferred.
+type typedFunction struct {
+	query.Function
+	typ query.ValueType
+}
+
+// inferType returns the type of value that a function is known to return, or
+// ValueUnknown if it cannot be inferred.
+func inferType(fn query.Function) query.ValueType {
+	switch t := fn.(type) {
+	case *query.Literal:
+		if vType := query.ITypeOf(t.Value); vType != query.ValueQuery {
+			return vType
+		}
+	case *typedFunction:
+		return t.typ
+	case *tracedFunction:
+		return inferType(t.Function)
+	}
+	return query.ValueUnknown
+}
+
+// typed wraps a function with the type of value it returns, if the context is
+// collecting warnings and the type is known. Literals are left as they are
+// since their type is already known, and other parsers rely on detecting them.
+func (pCtx Context) typed(fn query.Function, vType query.ValueType) query.Function {
+	if pCtx.warnings == nil || vType == "" || vType == query.ValueUnknown {
+		return fn
+	}
+	if _, isLit := fn.(*query.Literal); isLit {
+		return fn
+	}
+	return &typedFunction{Function: fn, typ: vType}
+}
+
+// checkMethodTarget records a warning when the type of value that a method is
+// applied to can be inferred and isn't one that the method accepts, and returns
+// the type of value that the method returns, if known.
+func (pCtx Context) checkMethodTarget(input []rune, name string, target query.Function) query.ValueType {
+	if pCtx.warnings == nil {
+		return query.ValueUnknown
+	}
+	specs, ok := pCtx.Methods.(interface {
+		Spec(name string) (query.MethodSpec, bool)
+	})
+	if !ok {
+		return query.ValueUnknown
+	}
+	spec, exists := specs.Spec(name)
+	if !exists {
+		return query.ValueUnknown
+	}
+
+	if tType := inferType(target); tType != query.ValueUnknown && len(spec.InputTypes) > 0 {
+		accepted := false
+		expected := make([]string, 0, len(spec.InputTypes))
+		for _, t := range spec.InputTypes {
+			if t == tType {
+				accepted = true
+			}
+			expected = append(expected, string(t))
+		}
+		if !accepted {
+			pCtx.addWarning(input, fmt.Sprintf("method %v expects %v but is applied to %v", name, strings.Join(expected, " or "), tType))
+		}
+	}
+	return spec.OutputType
+}
+
+// recordVarTypes records the types of variables assigned by a let statement,
+// if the context is collecting warnings. Variables assigned values of different
+// types throughout a mapping are considered to be of an unknown type.
+func (pCtx Context) recordVarTypes(assignment mapping.Assignment, fn query.Function) {
+	if pCtx.warnings == nil || pCtx.varTypes == nil {
+		return
+	}
+	targets, vType := []mapping.TargetPath{assignment.Target()}, inferType(fn)
+	if d, isDestructure := assignment.(*mapping.DestructureAssignment); isDestructure {
+		targets, vType = d.Targets(), query.ValueUnknown
+	}
+	for _, t := range targets {
+		if len(t.Path) == 0 {
+			continue
+		}
+		if existing, exists := pCtx.varTypes[t.Path[0]]; exists && existing != vType {
+			pCtx.varTypes[t.Path[0]] = query.ValueUnknown
+		} else {
+			pCtx.varTypes[t.Path[0]] = vType
+		}
+	}
+}
@@ -0,0 +1,158 @@
+package parser
+
+import (
+	"fmt"
+
+	"github.com/Jeffail/benthos/v3/internal/bloblang/mapping"
+	"github.com/Jeffail/benthos/v3/internal/bloblang/query"
+)
+
+// Warning describes a potential problem found within a mapping that does not
+// prevent it from being parsed, such as a match expression that doesn't handle
+// all possible types of its context.
+//
+// The input at the point of the warning can be used in order to infer where
+// exactly in the input the warning occurred with len(input) - len(w.Input).
+type Warning struct {
+	Input   []rune
+	Message string
+}
+
+// LineAndCol returns the line and column position of the warning within the
+// input that was parsed.
+func (w Warning) LineAndCol(input []rune) (line, col int) {
+	return LineAndColOf(input, w.Input)
+}
+
+// addWarning records a warning for the mapping being parsed, if the context is
+// collecting warnings. Parsers may attempt the same input more than once and
+// therefore duplicate warnings are ignored.
+func (pCtx Context) addWarning(input []rune, msg string) {
+	if pCtx.warnings == nil {
+		return
+	}
+	for _, w := range *pCtx.warnings {
+		if len(w.Input) == len(input) && w.Message == msg {
+			return
+		}
+	}
+	*pCtx.warnings = append(*pCtx.warnings, Warning{
+		Input:   input,
+		Message: msg,
+	})
+}
+
+// warnIfDeprecatedFunction records a warning when a function is deprecated, as
+// long as the function set of the context is able to provide its spec.
+func (pCtx Context) warnIfDeprecatedFunction(input []rune, name string) {
+	if _, exists := pCtx.userFunctions[name]; exists {
+		return
+	}
+	specs, ok := pCtx.Functions.(interface {
+		Spec(name string) (query.FunctionSpec, bool)
+	})
+	if !ok {
+		return
+	}
+	spec, exists := specs.Spec(name)
+	if !exists || spec.Status != query.StatusDeprecated {
+		return
+	}
+	if spec.AliasOf != "" {
+		pCtx.addWarning(input, fmt.Sprintf("function %v is deprecated, use %v instead", name, spec.AliasOf))
+		return
+	}
+	pCtx.addWarning(input, fmt.Sprintf("function %v is deprecated", name))
+}
+
+// warnIfDeprecatedMethod records a warning when a method is deprecated, as long
+// as the method set of the context is able to provide its spec.
+func (pCtx Context) warnIfDeprecatedMethod(input []rune, name string) {
+	specs, ok := pCtx.Methods.(interface {
+		Spec(name string) (query.MethodSpec, bool)
+	})
+	if !ok {
+		return
+	}
+	spec, exists := specs.Spec(name)
+	if !exists || spec.Status != query.StatusDeprecated {
+		return
+	}
+	if spec.AliasOf != "" {
+		pCtx.addWarning(input, fmt.Sprintf("method %v is deprecated, use %v instead", name, spec.AliasOf))
+		return
+	}
+	pCtx.addWarning(input, fmt.Sprintf("method %v is deprecated", name))
+}
+
+// warnStatements records warnings for problems that can only be detected once
+// all statements of a mapping have been parsed, which are variables that are
+// declared but never used and assignments that are always overwritten by a
+// later assignment.
+func (pCtx Context) warnStatements(statements []mapping.Statement) {
+	if pCtx.warnings == nil {
+		return
+	}
+
+	for i, stmt := range statements {
+		if stmt.Query() == nil {
+			continue
+		}
+		for _, t := range stmt.AssignmentTargets() {
+			if t.Type != mapping.TargetVariable || len(t.Path) == 0 {
+				continue
+			}
+			if _, exists := pCtx.usedVars[t.Path[0]]; !exists {
+				pCtx.addWarning(stmt.Input(), fmt.Sprintf("variable %v is declared but never used", t.String()))
+			}
+		}
+		if overwrittenBy := overwritingStatement(stmt, statements[i+1:]); overwrittenBy != nil {
+			pCtx.addWarning(stmt.Input(), fmt.Sprintf("assignment to %v is always overwritten by a later assignment to %v", stmt.AssignmentTargets()[0].String(), overwrittenBy.AssignmentTargets()[0].String()))
+		}
+	}
+}
+
+// overwritingStatement returns the first of a list of statements that always
+// replaces the value assigned by a statement, or nil if there isn't one. Since
+// queries are unable to reference the new document being mapped the value of
+// an assignment cannot be observed once it has been overwritten.
+func overwritingStatement(stmt mapping.Statement, later []mapping.Statement) *mapping.Statement {
+	targets := stmt.AssignmentTargets()
+	if len(targets) != 1 || targets[0].Type != mapping.TargetValue {
+		return nil
+	}
+	path := targets[0].Path
+
+	for i, l := range later {
+		if l.Query() == nil || isConditional(l.Query()) {
+			continue
+		}
+		lTargets := l.AssignmentTargets()
+		if len(lTargets) != 1 || lTargets[0].Type != mapping.TargetValue {
+			continue
+		}
+		if lPath := lTargets[0].Path; len(lPath) <= len(path) && pathHasPrefix(path, lPath) {
+			return &later[i]
+		}
+	}
+	return nil
+}
+
+// isConditional returns true if a query might result in nothing, in which case
+// the assignment of a statement is skipped.
+func isConditional(fn query.Function) bool {
+	switch fn.Annotation() {
+	case "if expression", "match expression":
+		return true
+	}
+	return false
+}
+
+func pathHasPrefix(path, prefix []string) bool {
+	for i, p := range prefix {
+		if path[i] != p {
+			return false
+		}
+	}
+	return true
+}
@@ -9,14 +9,11 @@ import (
 // Register adds any native Bloblang methods and functions to the global sets
 // that aren't defined within the query package.
 func Register() error {
-	dynamicBloblangParserContext := parser.Context{
-		Functions: query.AllFunctions.OnlyPure().NoMessage(),
-		Methods:   query.AllMethods,
-	}
+	dynamicBloblangFunctions := query.AllFunctions.OnlyPure().NoMessage()
 
 	return query.AllMethods.Add(
 		query.NewMethodSpec(
-			"bloblang", "Executes an argument Bloblang mapping on the target. This method can be used in order to execute dynamic mappings. Functions that interact with the environment, such as `file` and `env`, or that access message information directly, such as `content` or `json`, are not enabled for dynamic Bloblang mappings.",
+			"bloblang", "Executes an argument Bloblang mapping on the target. This method can be used in order to execute dynamic mappings. Functions and methods that interact with the environment, such as `file` and `env`, or that access message information directly, such as `content` or `json`, are not enabled for dynamic Bloblang mappings.",
 		).InCategory(
 			query.MethodCategoryParsing, "",
 			query.NewExampleSpec(
@@ -33,7 +30,12 @@ func Register() error {
 			if err != nil {
 				return nil, err
 			}
-			exec, parserErr := parser.ParseMapping(dynamicBloblangParserContext, "", mappingStr)
+			// Methods are resolved at parse time so that plugin methods
+			// registered after this one are also available.
+			exec, parserErr := parser.ParseMapping(parser.Context{
+				Functions: dynamicBloblangFunctions,
+				Methods:   query.AllMethods.OnlyPure(),
+			}, "", mappingStr)
 			if parserErr != nil {
 				return nil, parserErr
 			}
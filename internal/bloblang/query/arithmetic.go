@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"math/big"
 
 	"github.com/google/go-cmp/cmp"
 )
@@ -94,15 +95,69 @@ var ErrDivideByZero = errors.New("attempted to divide by zero")
 
 type intArithmeticFunc func(left, right int64) (int64, error)
 type floatArithmeticFunc func(left, right float64) (float64, error)
+type bigIntArithmeticFunc func(left, right *big.Int) (*big.Int, error)
+type decimalArithmeticFunc func(left, right *Decimal) (*Decimal, error)
+
+// Returns both values as decimals when at least one of them is already a
+// decimal and the other is a number of any kind.
+func decimalOperands(left, right interface{}) (l, r *Decimal, ok bool) {
+	_, leftIsDec := left.(*Decimal)
+	_, rightIsDec := right.(*Decimal)
+	if !leftIsDec && !rightIsDec {
+		return nil, nil, false
+	}
+	var err error
+	if l, err = IGetDecimal(left); err != nil {
+		return nil, nil, false
+	}
+	if r, err = IGetDecimal(right); err != nil {
+		return nil, nil, false
+	}
+	return l, r, true
+}
 
-// Takes two arithmetic funcs, one for integer values and one for float values
-// and returns a generic arithmetic func. If both values can be represented as
-// integers the integer func is called, otherwise the float func is called.
-func numberDegradationFunc(op ArithmeticOperator, iFn intArithmeticFunc, fFn floatArithmeticFunc) arithmeticOpFunc {
+// Returns both values as big integers when at least one of them is already a
+// big integer and the other is an integer value. Float values always take
+// precedence and therefore never result in big integer operands.
+func bigIntOperands(left, right interface{}) (l, r *big.Int, ok bool) {
+	_, leftIsBig := left.(*big.Int)
+	_, rightIsBig := right.(*big.Int)
+	if !leftIsBig && !rightIsBig {
+		return nil, nil, false
+	}
+	_, leftIsFloat := left.(float64)
+	_, rightIsFloat := right.(float64)
+	if leftIsFloat || rightIsFloat {
+		return nil, nil, false
+	}
+	var err error
+	if l, err = IGetBigInt(left); err != nil {
+		return nil, nil, false
+	}
+	if r, err = IGetBigInt(right); err != nil {
+		return nil, nil, false
+	}
+	return l, r, true
+}
+
+// Takes arithmetic funcs for integer, float, big integer and decimal values
+// and returns a generic arithmetic func. If either value is a decimal the
+// decimal func is called, if either value is a float the float func is called,
+// if either value is a big integer and the other is an integer the big integer
+// func is called, otherwise the integer func is called.
+func numberDegradationFunc(op ArithmeticOperator, iFn intArithmeticFunc, fFn floatArithmeticFunc, bFn bigIntArithmeticFunc, dFn decimalArithmeticFunc) arithmeticOpFunc {
 	return func(lhs, rhs Function, left, right interface{}) (interface{}, error) {
 		left = ISanitize(left)
 		right = ISanitize(right)
 
+		if leftDec, rightDec, isDec := decimalOperands(left, right); isDec {
+			return dFn(leftDec, rightDec)
+		}
+
+		if leftBig, rightBig, isBig := bigIntOperands(left, right); isBig {
+			return bFn(leftBig, rightBig)
+		}
+
 		if leftFloat, leftIsFloat := left.(float64); leftIsFloat {
 			rightFloat, err := IGetNumber(right)
 			if err != nil {
@@ -141,10 +196,23 @@ func prodOp(op ArithmeticOperator) (arithmeticOpFunc, bool) {
 			func(lhs, rhs float64) (float64, error) {
 				return lhs * rhs, nil
 			},
+			func(lhs, rhs *big.Int) (*big.Int, error) {
+				return new(big.Int).Mul(lhs, rhs), nil
+			},
+			func(lhs, rhs *Decimal) (*Decimal, error) {
+				return lhs.Mul(rhs), nil
+			},
 		), true
 	case ArithmeticDiv:
-		// Only executes on float values.
+		// Only executes on float or decimal values.
 		return func(lFn, rFn Function, left, right interface{}) (interface{}, error) {
+			if lhs, rhs, isDec := decimalOperands(ISanitize(left), ISanitize(right)); isDec {
+				res, err := lhs.Quo(rhs)
+				if err != nil {
+					return nil, ErrFrom(err, rFn)
+				}
+				return res, nil
+			}
 			lhs, err := IGetNumber(left)
 			if err != nil {
 				return nil, NewTypeMismatch(op.String(), lFn, rFn, left, right)
@@ -159,8 +227,21 @@ func prodOp(op ArithmeticOperator) (arithmeticOpFunc, bool) {
 			return lhs / rhs, nil
 		}, true
 	case ArithmeticMod:
-		// Only executes on integer values.
+		// Only executes on integer or decimal values.
 		return func(lFn, rFn Function, left, right interface{}) (interface{}, error) {
+			if lhs, rhs, isDec := decimalOperands(ISanitize(left), ISanitize(right)); isDec {
+				res, err := lhs.Rem(rhs)
+				if err != nil {
+					return nil, ErrFrom(err, rFn)
+				}
+				return res, nil
+			}
+			if lhs, rhs, isBig := bigIntOperands(ISanitize(left), ISanitize(right)); isBig {
+				if rhs.Sign() == 0 {
+					return nil, ErrFrom(ErrDivideByZero, rFn)
+				}
+				return new(big.Int).Rem(lhs, rhs), nil
+			}
 			lhs, err := IGetInt(left)
 			if err != nil {
 				return nil, NewTypeMismatch(op.String(), lFn, rFn, left, right)
@@ -188,10 +269,16 @@ func sumOp(op ArithmeticOperator) (arithmeticOpFunc, bool) {
 			func(left, right float64) (float64, error) {
 				return left + right, nil
 			},
+			func(left, right *big.Int) (*big.Int, error) {
+				return new(big.Int).Add(left, right), nil
+			},
+			func(left, right *Decimal) (*Decimal, error) {
+				return left.Add(right), nil
+			},
 		)
 		return func(lFn, rFn Function, left, right interface{}) (interface{}, error) {
 			switch left.(type) {
-			case float64, int, int64, uint64, json.Number:
+			case float64, int, int64, uint64, json.Number, *big.Int, *Decimal:
 				return numberAdd(lFn, rFn, left, right)
 			case string, []byte:
 				lhs, err := IGetString(left)
@@ -214,6 +301,12 @@ func sumOp(op ArithmeticOperator) (arithmeticOpFunc, bool) {
 			func(lhs, rhs float64) (float64, error) {
 				return lhs - rhs, nil
 			},
+			func(lhs, rhs *big.Int) (*big.Int, error) {
+				return new(big.Int).Sub(lhs, rhs), nil
+			},
+			func(lhs, rhs *Decimal) (*Decimal, error) {
+				return lhs.Sub(rhs), nil
+			},
 		), true
 	}
 	return nil, false
@@ -295,15 +388,23 @@ func compareBoolFn(op ArithmeticOperator) func(lhs, rhs bool) bool {
 	return nil
 }
 
+// Big integers contain unexported fields and therefore need an explicit
+// comparer when nested within structured values, decimals implement Equal.
+var genericCompareOpts = []cmp.Option{
+	cmp.Comparer(func(lhs, rhs *big.Int) bool {
+		return lhs.Cmp(rhs) == 0
+	}),
+}
+
 func compareGenericFn(op ArithmeticOperator) func(lhs, rhs interface{}) bool {
 	switch op {
 	case ArithmeticEq:
 		return func(lhs, rhs interface{}) bool {
-			return cmp.Equal(lhs, rhs)
+			return cmp.Equal(lhs, rhs, genericCompareOpts...)
 		}
 	case ArithmeticNeq:
 		return func(lhs, rhs interface{}) bool {
-			return !cmp.Equal(lhs, rhs)
+			return !cmp.Equal(lhs, rhs, genericCompareOpts...)
 		}
 	}
 	return nil
@@ -316,6 +417,9 @@ func restrictForComparison(v interface{}) interface{} {
 		return float64(t)
 	case uint64:
 		return float64(t)
+	case *big.Int, *Decimal:
+		f, _ := IGetNumber(t)
+		return f
 	case json.Number:
 		if f, err := IGetNumber(t); err == nil {
 			return f
@@ -339,6 +443,13 @@ func compareOp(op ArithmeticOperator) (arithmeticOpFunc, bool) {
 		boolOpFn := compareBoolFn(op)
 		genericOpFn := compareGenericFn(op)
 		return func(lFn, rFn Function, left, right interface{}) (interface{}, error) {
+			// Compare decimals and big integers exactly rather than via float64.
+			if lhs, rhs, isDec := decimalOperands(ISanitize(left), ISanitize(right)); isDec && numOpFn != nil {
+				return numOpFn(float64(lhs.Cmp(rhs)), 0), nil
+			}
+			if lhs, rhs, isBig := bigIntOperands(ISanitize(left), ISanitize(right)); isBig && numOpFn != nil {
+				return numOpFn(float64(lhs.Cmp(rhs)), 0), nil
+			}
 			switch lhs := restrictForComparison(left).(type) {
 			case string:
 				if strOpFn == nil {
@@ -3,6 +3,7 @@ package query
 import (
 	"encoding/json"
 	"errors"
+	"math/big"
 	"testing"
 
 	"github.com/Jeffail/benthos/v3/lib/message"
@@ -18,6 +19,12 @@ func TestArithmeticNumberDegradation(t *testing.T) {
 		func(left, right float64) (float64, error) {
 			return left / right, nil
 		},
+		func(left, right *big.Int) (*big.Int, error) {
+			return new(big.Int).Quo(left, right), nil
+		},
+		func(left, right *Decimal) (*Decimal, error) {
+			return left.Quo(right)
+		},
 	)
 
 	testCases := []struct {
@@ -75,6 +82,24 @@ func TestArithmeticNumberDegradation(t *testing.T) {
 			right:  json.Number("3"),
 			result: 4.0,
 		},
+		{
+			name:   "left is big int",
+			left:   bigIntFromString(t, "340282366920938463463374607431768211456"),
+			right:  int64(2),
+			result: bigIntFromString(t, "170141183460469231731687303715884105728"),
+		},
+		{
+			name:   "right is big int",
+			left:   json.Number("12"),
+			right:  big.NewInt(3),
+			result: big.NewInt(4),
+		},
+		{
+			name:   "big int and float",
+			left:   big.NewInt(10),
+			right:  4.0,
+			result: 2.5,
+		},
 		{
 			name:  "left is invalid int",
 			left:  "not a number",
@@ -115,6 +140,13 @@ func TestArithmeticNumberDegradation(t *testing.T) {
 	}
 }
 
+func bigIntFromString(t testing.TB, s string) *big.Int {
+	t.Helper()
+	i, ok := new(big.Int).SetString(s, 10)
+	require.True(t, ok)
+	return i
+}
+
 func TestArithmetic(t *testing.T) {
 	type easyMsg struct {
 		content string
@@ -158,6 +190,102 @@ func TestArithmetic(t *testing.T) {
 			),
 			output: true,
 		},
+		"compare big ints exactly": {
+			input: arithmetic(
+				[]Function{
+					NewLiteralFunction("", bigIntFromString(t, "18446744073709551617")),
+					NewLiteralFunction("", uint64(18446744073709551615)),
+				},
+				[]ArithmeticOperator{
+					ArithmeticGt,
+				},
+			),
+			output: true,
+		},
+		"compare nested big ints": {
+			input: arithmetic(
+				[]Function{
+					NewLiteralFunction("", map[string]interface{}{"id": bigIntFromString(t, "18446744073709551617")}),
+					NewLiteralFunction("", map[string]interface{}{"id": bigIntFromString(t, "18446744073709551617")}),
+				},
+				[]ArithmeticOperator{
+					ArithmeticEq,
+				},
+			),
+			output: true,
+		},
+		"big int modulo": {
+			input: arithmetic(
+				[]Function{
+					NewLiteralFunction("", bigIntFromString(t, "18446744073709551617")),
+					NewLiteralFunction("", int64(10)),
+				},
+				[]ArithmeticOperator{
+					ArithmeticMod,
+				},
+			),
+			output: big.NewInt(7),
+		},
+		"decimal add float": {
+			input: arithmetic(
+				[]Function{
+					NewLiteralFunction("", decimalFromString(t, "0.1")),
+					NewLiteralFunction("", 0.2),
+				},
+				[]ArithmeticOperator{
+					ArithmeticAdd,
+				},
+			),
+			output: decimalFromString(t, "0.3"),
+		},
+		"decimal divide int": {
+			input: arithmetic(
+				[]Function{
+					NewLiteralFunction("", decimalFromString(t, "10.00")),
+					NewLiteralFunction("", int64(4)),
+				},
+				[]ArithmeticOperator{
+					ArithmeticDiv,
+				},
+			),
+			output: decimalFromString(t, "2.50"),
+		},
+		"decimal divide by zero": {
+			input: arithmetic(
+				[]Function{
+					NewLiteralFunction("", decimalFromString(t, "10.00")),
+					opaqueLit(int64(0)),
+				},
+				[]ArithmeticOperator{
+					ArithmeticDiv,
+				},
+			),
+			err: errors.New("foobar: attempted to divide by zero"),
+		},
+		"compare decimals exactly": {
+			input: arithmetic(
+				[]Function{
+					NewLiteralFunction("", decimalFromString(t, "0.30")),
+					NewLiteralFunction("", 0.3),
+				},
+				[]ArithmeticOperator{
+					ArithmeticEq,
+				},
+			),
+			output: true,
+		},
+		"compare nested decimals": {
+			input: arithmetic(
+				[]Function{
+					NewLiteralFunction("", []interface{}{decimalFromString(t, "1.50")}),
+					NewLiteralFunction("", []interface{}{decimalFromString(t, "1.5")}),
+				},
+				[]ArithmeticOperator{
+					ArithmeticEq,
+				},
+			),
+			output: true,
+		},
 		"dont divide by zero": {
 			input: arithmetic(
 				[]Function{
@@ -0,0 +1,281 @@
+package query
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
+	"strconv"
+	"strings"
+)
+
+// The number of decimal places calculated when dividing decimal values, which
+// is the minimum scale of a division result.
+const decimalDivisionScale = 16
+
+// The maximum number of decimal places (or the maximum exponent) a decimal
+// value is permitted to have, which prevents small inputs from allocating huge
+// numbers.
+const decimalMaxScale = 1000
+
+// Decimal is an exact base 10 number represented by an arbitrary precision
+// unscaled integer and a number of decimal places. Decimals are used in order
+// to perform arithmetic on values such as currency amounts without floating
+// point rounding errors, and serialize as JSON numbers with the decimal places
+// preserved.
+type Decimal struct {
+	unscaled *big.Int
+	scale    int
+}
+
+// NewDecimal creates a decimal value from an unscaled integer and a number of
+// decimal places, the value represented is unscaled * 10^-scale.
+func NewDecimal(unscaled *big.Int, scale int) *Decimal {
+	if scale < 0 {
+		unscaled = new(big.Int).Mul(unscaled, pow10(-scale))
+		scale = 0
+	}
+	return &Decimal{unscaled: new(big.Int).Set(unscaled), scale: scale}
+}
+
+// ParseDecimal attempts to parse a string as a decimal value. The string may
+// contain an optional sign, a fractional component and an exponent.
+func ParseDecimal(s string) (*Decimal, error) {
+	str := strings.TrimSpace(s)
+
+	mantissa, exponent := str, 0
+	if i := strings.IndexAny(str, "eE"); i >= 0 {
+		mantissa = str[:i]
+		var err error
+		if exponent, err = strconv.Atoi(str[i+1:]); err != nil || exponent > decimalMaxScale || exponent < -decimalMaxScale {
+			return nil, fmt.Errorf("failed to parse '%v' as a decimal", s)
+		}
+	}
+
+	neg := false
+	if len(mantissa) > 0 && (mantissa[0] == '-' || mantissa[0] == '+') {
+		neg = mantissa[0] == '-'
+		mantissa = mantissa[1:]
+	}
+
+	intPart, fracPart := mantissa, ""
+	if i := strings.IndexByte(mantissa, '.'); i >= 0 {
+		intPart, fracPart = mantissa[:i], mantissa[i+1:]
+	}
+	digits := intPart + fracPart
+	if digits == "" || len(fracPart) > decimalMaxScale {
+		return nil, fmt.Errorf("failed to parse '%v' as a decimal", s)
+	}
+	for _, c := range digits {
+		if c < '0' || c > '9' {
+			return nil, fmt.Errorf("failed to parse '%v' as a decimal", s)
+		}
+	}
+
+	unscaled, _ := new(big.Int).SetString(digits, 10)
+	if neg {
+		unscaled.Neg(unscaled)
+	}
+	return NewDecimal(unscaled, len(fracPart)-exponent), nil
+}
+
+func pow10(n int) *big.Int {
+	return new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(n)), nil)
+}
+
+// decimalFromRat converts a rational number into a decimal with a given number
+// of decimal places, the rational must already be rounded to that scale.
+func decimalFromRat(r *big.Rat, scale int) *Decimal {
+	num := new(big.Int).Mul(r.Num(), pow10(scale))
+	return &Decimal{unscaled: num.Quo(num, r.Denom()), scale: scale}
+}
+
+// Scale returns the number of decimal places of the value.
+func (d *Decimal) Scale() int {
+	return d.scale
+}
+
+// Rat returns the value of the decimal as a rational number.
+func (d *Decimal) Rat() *big.Rat {
+	return new(big.Rat).SetFrac(d.unscaled, pow10(d.scale))
+}
+
+// Float64 returns the nearest float64 value of the decimal.
+func (d *Decimal) Float64() float64 {
+	f, _ := d.Rat().Float64()
+	return f
+}
+
+// Sign returns -1, 0 or 1 depending on the sign of the value.
+func (d *Decimal) Sign() int {
+	return d.unscaled.Sign()
+}
+
+// IsInt returns whether the decimal has no fractional component.
+func (d *Decimal) IsInt() bool {
+	return d.Rat().IsInt()
+}
+
+// Int returns the integer component of the decimal, truncated towards zero.
+func (d *Decimal) Int() *big.Int {
+	return new(big.Int).Quo(d.unscaled, pow10(d.scale))
+}
+
+func (d *Decimal) unscaledAt(scale int) *big.Int {
+	if scale == d.scale {
+		return d.unscaled
+	}
+	return new(big.Int).Mul(d.unscaled, pow10(scale-d.scale))
+}
+
+func maxScale(l, r *Decimal) int {
+	if l.scale > r.scale {
+		return l.scale
+	}
+	return r.scale
+}
+
+// Add returns the sum of two decimals.
+func (d *Decimal) Add(o *Decimal) *Decimal {
+	scale := maxScale(d, o)
+	return &Decimal{unscaled: new(big.Int).Add(d.unscaledAt(scale), o.unscaledAt(scale)), scale: scale}
+}
+
+// Sub returns the difference of two decimals.
+func (d *Decimal) Sub(o *Decimal) *Decimal {
+	scale := maxScale(d, o)
+	return &Decimal{unscaled: new(big.Int).Sub(d.unscaledAt(scale), o.unscaledAt(scale)), scale: scale}
+}
+
+// Mul returns the product of two decimals.
+func (d *Decimal) Mul(o *Decimal) *Decimal {
+	return &Decimal{unscaled: new(big.Int).Mul(d.unscaled, o.unscaled), scale: d.scale + o.scale}
+}
+
+// Quo returns the quotient of two decimals. Results that cannot be represented
+// exactly are rounded half to even at 16 decimal places, and trailing zeros
+// are removed down to the scale of the largest operand.
+func (d *Decimal) Quo(o *Decimal) (*Decimal, error) {
+	if o.Sign() == 0 {
+		return nil, ErrDivideByZero
+	}
+	minScale := maxScale(d, o)
+	scale := minScale
+	if scale < decimalDivisionScale {
+		scale = decimalDivisionScale
+	}
+	q := new(big.Rat).Quo(d.Rat(), o.Rat())
+	res := decimalFromRat(roundRat(q, scale, roundHalfEven), scale)
+	return res.trimZeros(minScale), nil
+}
+
+// Rem returns the remainder of dividing two decimals, which has the sign of
+// the dividend.
+func (d *Decimal) Rem(o *Decimal) (*Decimal, error) {
+	if o.Sign() == 0 {
+		return nil, ErrDivideByZero
+	}
+	scale := maxScale(d, o)
+	return &Decimal{unscaled: new(big.Int).Rem(d.unscaledAt(scale), o.unscaledAt(scale)), scale: scale}, nil
+}
+
+// Cmp compares two decimals and returns -1, 0 or 1 when the value is less
+// than, equal to or greater than the other respectively.
+func (d *Decimal) Cmp(o *Decimal) int {
+	scale := maxScale(d, o)
+	return d.unscaledAt(scale).Cmp(o.unscaledAt(scale))
+}
+
+// Equal returns whether two decimals represent the same number, regardless of
+// scale.
+func (d *Decimal) Equal(o *Decimal) bool {
+	return d.Cmp(o) == 0
+}
+
+// Round returns the decimal rounded to a number of decimal places using the
+// provided rounding mode. A negative number of places rounds to the left of the
+// decimal point.
+func (d *Decimal) Round(places int, mode roundingMode) *Decimal {
+	r := roundRat(d.Rat(), places, mode)
+	if places < 0 {
+		places = 0
+	}
+	return decimalFromRat(r, places)
+}
+
+func (d *Decimal) trimZeros(minScale int) *Decimal {
+	unscaled, scale := new(big.Int).Set(d.unscaled), d.scale
+	ten, rem := big.NewInt(10), new(big.Int)
+	for scale > minScale {
+		q, r := new(big.Int).QuoRem(unscaled, ten, rem)
+		if r.Sign() != 0 {
+			break
+		}
+		unscaled, scale = q, scale-1
+	}
+	return &Decimal{unscaled: unscaled, scale: scale}
+}
+
+// String returns the decimal formatted with all of its decimal places.
+func (d *Decimal) String() string {
+	digits := new(big.Int).Abs(d.unscaled).String()
+	if d.scale > 0 {
+		if len(digits) <= d.scale {
+			digits = strings.Repeat("0", d.scale-len(digits)+1) + digits
+		}
+		digits = digits[:len(digits)-d.scale] + "." + digits[len(digits)-d.scale:]
+	}
+	if d.unscaled.Sign() < 0 {
+		return "-" + digits
+	}
+	return digits
+}
+
+// MarshalJSON serializes the decimal as a JSON number.
+func (d *Decimal) MarshalJSON() ([]byte, error) {
+	return []byte(d.String()), nil
+}
+
+var _ json.Marshaler = &Decimal{}
+
+//------------------------------------------------------------------------------
+
+// IGetDecimal takes a boxed value and attempts to extract a decimal from it.
+// Float values are converted using the shortest representation that parses
+// back into the same float, and therefore a float literal such as 0.1 becomes
+// exactly 0.1.
+func IGetDecimal(v interface{}) (*Decimal, error) {
+	switch t := v.(type) {
+	case *Decimal:
+		return t, nil
+	case int:
+		return NewDecimal(big.NewInt(int64(t)), 0), nil
+	case int64:
+		return NewDecimal(big.NewInt(t), 0), nil
+	case uint64:
+		return NewDecimal(new(big.Int).SetUint64(t), 0), nil
+	case *big.Int:
+		return NewDecimal(t, 0), nil
+	case float64:
+		if math.IsNaN(t) || math.IsInf(t, 0) {
+			return nil, errors.New("cannot represent a non-finite float as a decimal")
+		}
+		return ParseDecimal(strconv.FormatFloat(t, 'g', -1, 64))
+	case json.Number:
+		return ParseDecimal(t.String())
+	}
+	return nil, NewTypeError(v, ValueNumber)
+}
+
+// IToDecimal takes a boxed value and attempts to extract a decimal from it or
+// parse one.
+func IToDecimal(v interface{}) (*Decimal, error) {
+	switch t := v.(type) {
+	case string:
+		return ParseDecimal(t)
+	case []byte:
+		return ParseDecimal(string(t))
+	}
+	return IGetDecimal(v)
+}
@@ -0,0 +1,114 @@
+package query
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func decimalFromString(t testing.TB, s string) *Decimal {
+	t.Helper()
+	d, err := ParseDecimal(s)
+	require.NoError(t, err)
+	return d
+}
+
+func TestDecimalParse(t *testing.T) {
+	tests := map[string]string{
+		"0":       "0",
+		"-0.00":   "0.00",
+		"+12.340": "12.340",
+		"-0.05":   "-0.05",
+		".5":      "0.5",
+		"5.":      "5",
+		"1.5e3":   "1500",
+		"1.5E-3":  "0.0015",
+		" 42 ":    "42",
+		"1234567890123456789012345678901234567890.1": "1234567890123456789012345678901234567890.1",
+	}
+	for input, exp := range tests {
+		d, err := ParseDecimal(input)
+		require.NoError(t, err, input)
+		assert.Equal(t, exp, d.String(), input)
+	}
+
+	for _, bad := range []string{"", "-", ".", "1.2.3", "abc", "1e", "1e5000", "0x10", "1,000"} {
+		_, err := ParseDecimal(bad)
+		assert.Error(t, err, bad)
+	}
+}
+
+func TestDecimalArithmetic(t *testing.T) {
+	a, b := decimalFromString(t, "10.25"), decimalFromString(t, "0.5")
+
+	assert.Equal(t, "10.75", a.Add(b).String())
+	assert.Equal(t, "9.75", a.Sub(b).String())
+	assert.Equal(t, "5.125", a.Mul(b).String())
+
+	q, err := a.Quo(b)
+	require.NoError(t, err)
+	assert.Equal(t, "20.50", q.String())
+
+	q, err = decimalFromString(t, "1").Quo(decimalFromString(t, "3"))
+	require.NoError(t, err)
+	assert.Equal(t, "0.3333333333333333", q.String())
+
+	q, err = decimalFromString(t, "2").Quo(decimalFromString(t, "3"))
+	require.NoError(t, err)
+	assert.Equal(t, "0.6666666666666667", q.String())
+
+	_, err = a.Quo(decimalFromString(t, "0.00"))
+	assert.Equal(t, ErrDivideByZero, err)
+
+	r, err := decimalFromString(t, "-10.25").Rem(decimalFromString(t, "3"))
+	require.NoError(t, err)
+	assert.Equal(t, "-1.25", r.String())
+
+	assert.Equal(t, 0, decimalFromString(t, "1.50").Cmp(decimalFromString(t, "1.5")))
+	assert.Equal(t, -1, decimalFromString(t, "-1.5").Cmp(decimalFromString(t, "1")))
+	assert.True(t, decimalFromString(t, "2.0").Equal(decimalFromString(t, "2")))
+}
+
+func TestDecimalRound(t *testing.T) {
+	tests := []struct {
+		input  string
+		places int
+		mode   roundingMode
+		output string
+	}{
+		{input: "1.005", places: 2, mode: roundHalfAwayFromZero, output: "1.01"},
+		{input: "-1.005", places: 2, mode: roundHalfAwayFromZero, output: "-1.01"},
+		{input: "1.005", places: 2, mode: roundHalfEven, output: "1.00"},
+		{input: "1.2", places: 3, mode: roundHalfEven, output: "1.200"},
+		{input: "1250", places: -2, mode: roundHalfEven, output: "1200"},
+		{input: "1.99", places: 0, mode: roundFloor, output: "1"},
+	}
+	for _, test := range tests {
+		d := decimalFromString(t, test.input)
+		assert.Equal(t, test.output, d.Round(test.places, test.mode).String(), "%v (%v)", test.input, test.places)
+	}
+}
+
+func TestDecimalJSON(t *testing.T) {
+	b, err := json.Marshal(map[string]interface{}{
+		"amount": decimalFromString(t, "0.10"),
+	})
+	require.NoError(t, err)
+	assert.Equal(t, `{"amount":0.10}`, string(b))
+}
+
+func TestDecimalFromFloat(t *testing.T) {
+	d, err := IGetDecimal(0.1)
+	require.NoError(t, err)
+	assert.Equal(t, "0.1", d.String())
+
+	d, err = IGetDecimal(1e21)
+	require.NoError(t, err)
+	assert.Equal(t, "1000000000000000000000", d.String())
+
+	d, err = IGetDecimal(json.Number("3.14"))
+	require.NoError(t, err)
+	assert.Equal(t, "3.14", d.String())
+}
@@ -3,9 +3,9 @@ package query
 // ExampleSpec provides a mapping example and some input/output results to
 // display.
 type ExampleSpec struct {
-	Mapping string
-	Summary string
-	Results [][2]string
+	Mapping string      `json:"mapping"`
+	Summary string      `json:"summary"`
+	Results [][2]string `json:"results"`
 }
 
 // NewExampleSpec creates a new example spec.
@@ -53,26 +53,30 @@ var (
 // FunctionSpec describes a Bloblang function.
 type FunctionSpec struct {
 	// The release status of the function.
-	Status Status
+	Status Status `json:"status"`
 
 	// A category to place the function within.
-	Category FunctionCategory
+	Category FunctionCategory `json:"category"`
 
 	// Name of the function (as it appears in config).
-	Name string
+	Name string `json:"name"`
 
 	// Description of the functions purpose (in markdown).
-	Description string
+	Description string `json:"description"`
 
 	// Params defines the expected arguments of the function.
-	Params Params
+	Params Params `json:"params"`
 
 	// Examples shows general usage for the function.
-	Examples []ExampleSpec
+	Examples []ExampleSpec `json:"examples,omitempty"`
 
 	// Impure indicates that a function accesses or interacts with the outter
 	// environment, and is therefore unsafe to execute in shared environments.
-	Impure bool
+	Impure bool `json:"impure"`
+
+	// AliasOf is the name of the function that this function forwards to when
+	// it is a deprecated alias.
+	AliasOf string `json:"alias_of,omitempty"`
 }
 
 // NewFunctionSpec creates a new function spec.
@@ -149,30 +153,46 @@ var (
 // MethodCatSpec describes how a method behaves in the context of a given
 // category.
 type MethodCatSpec struct {
-	Category    MethodCategory
-	Description string
-	Examples    []ExampleSpec
+	Category    MethodCategory `json:"category"`
+	Description string         `json:"description"`
+	Examples    []ExampleSpec  `json:"examples,omitempty"`
 }
 
 // MethodSpec describes a Bloblang method.
 type MethodSpec struct {
 	// The release status of the function.
-	Status Status
+	Status Status `json:"status"`
 
 	// Name of the method (as it appears in config).
-	Name string
+	Name string `json:"name"`
 
 	// Description of the method purpose (in markdown).
-	Description string
+	Description string `json:"description"`
 
 	// Params defines the expected arguments of the method.
-	Params Params
+	Params Params `json:"params"`
 
 	// Examples shows general usage for the method.
-	Examples []ExampleSpec
+	Examples []ExampleSpec `json:"examples,omitempty"`
 
 	// Categories that this method fits within.
-	Categories []MethodCatSpec
+	Categories []MethodCatSpec `json:"categories,omitempty"`
+
+	// Impure indicates that a method accesses or interacts with the outter
+	// environment, and is therefore unsafe to execute in shared environments.
+	Impure bool `json:"impure"`
+
+	// InputTypes lists the types of value that the method can be applied to,
+	// when empty the method is assumed to accept any type.
+	InputTypes []ValueType `json:"input_types,omitempty"`
+
+	// OutputType is the type of value returned by the method, when empty the
+	// type is assumed to vary.
+	OutputType ValueType `json:"output_type,omitempty"`
+
+	// AliasOf is the name of the method that this method forwards to when it
+	// is a deprecated alias.
+	AliasOf string `json:"alias_of,omitempty"`
 }
 
 // NewMethodSpec creates a new method spec.
@@ -213,12 +233,33 @@ func (m MethodSpec) Beta() MethodSpec {
 	return m
 }
 
+// MarkImpure flags the method as being impure, meaning it access or interacts
+// with the environment.
+func (m MethodSpec) MarkImpure() MethodSpec {
+	m.Impure = true
+	return m
+}
+
 // Param adds a parameter to the function.
 func (m MethodSpec) Param(def ParamDefinition) MethodSpec {
 	m.Params = m.Params.Add(def)
 	return m
 }
 
+// AppliesTo describes the types of value that the method can be applied to,
+// which allows mappings to be checked for probable type errors.
+func (m MethodSpec) AppliesTo(types ...ValueType) MethodSpec {
+	m.InputTypes = types
+	return m
+}
+
+// Returns describes the type of value returned by the method, which allows the
+// types of method chains to be inferred.
+func (m MethodSpec) Returns(t ValueType) MethodSpec {
+	m.OutputType = t
+	return m
+}
+
 // VariadicParams configures the method spec to allow variadic parameters.
 func (m MethodSpec) VariadicParams() MethodSpec {
 	m.Params = VariadicParams()
@@ -20,6 +20,10 @@ type TypeError struct {
 	Expected []ValueType
 	Actual   ValueType
 	Value    string
+
+	// Input is the input of the expression that failed from the point at which
+	// it was parsed, if known.
+	Input []rune
 }
 
 // Error implements the standard error interface for TypeError.
@@ -82,8 +86,9 @@ func NewTypeErrorFrom(from string, value interface{}, exp ...ValueType) *TypeErr
 //------------------------------------------------------------------------------
 
 type errFrom struct {
-	from Function
-	err  error
+	from  Function
+	err   error
+	input []rune
 }
 
 func (e *errFrom) Error() string {
@@ -112,7 +117,7 @@ func ErrFrom(err error, from Function) error {
 	if errors.As(err, &fErr) {
 		return err
 	}
-	return &errFrom{from, err}
+	return &errFrom{from: from, err: err}
 }
 
 //------------------------------------------------------------------------------
@@ -125,6 +130,10 @@ type TypeMismatch struct {
 	Left      ValueType
 	Right     ValueType
 	Operation string
+
+	// Input is the input of the expression that failed from the point at which
+	// it was parsed, if known.
+	Input []rune
 }
 
 // Error implements the standard error interface.
@@ -142,3 +151,69 @@ func NewTypeMismatch(operation string, lfn, rfn Function, left, right interface{
 		Operation: operation,
 	}
 }
+
+//------------------------------------------------------------------------------
+
+type errAt struct {
+	input []rune
+	err   error
+}
+
+func (e *errAt) Error() string {
+	return e.err.Error()
+}
+
+func (e *errAt) Unwrap() error {
+	return e.err
+}
+
+// ErrAt attaches the input of the expression that caused an error from the
+// point at which it was parsed, which allows the error to be traced back to its
+// location within a mapping. Errors that already carry an input are returned
+// unchanged, and therefore the input carried is that of the innermost
+// expression that failed.
+func ErrAt(err error, input []rune) error {
+	if err == nil || len(input) == 0 || ErrInput(err) != nil {
+		return err
+	}
+	switch t := err.(type) {
+	case *TypeError:
+		t.Input = input
+	case *TypeMismatch:
+		t.Input = input
+	case *errFrom:
+		t.input = input
+	case *ErrRecoverable:
+		// Recoverable errors are detected by their type and are therefore
+		// never wrapped.
+		t.Err = ErrAt(t.Err, input)
+	default:
+		return &errAt{input: input, err: err}
+	}
+	return err
+}
+
+// ErrInput returns the input of the expression that caused an error from the
+// point at which it was parsed, or nil if the error doesn't carry one.
+func ErrInput(err error) []rune {
+	for err != nil {
+		switch t := err.(type) {
+		case *TypeError:
+			if t.Input != nil {
+				return t.Input
+			}
+		case *TypeMismatch:
+			if t.Input != nil {
+				return t.Input
+			}
+		case *errFrom:
+			if t.input != nil {
+				return t.input
+			}
+		case *errAt:
+			return t.input
+		}
+		err = errors.Unwrap(err)
+	}
+	return nil
+}
@@ -138,3 +138,32 @@ func TestTypeMismatchError(t *testing.T) {
 		})
 	}
 }
+
+func TestErrAt(t *testing.T) {
+	input := []rune(`root = this.foo.uppercase()`)
+	inner, outer := input[16:], input[7:]
+
+	tErr := NewTypeError(5, ValueString)
+	err := ErrAt(tErr, inner)
+	assert.Equal(t, tErr, err)
+	assert.Equal(t, inner, ErrInput(err))
+
+	err = ErrAt(ErrFrom(err, NewLiteralFunction("foo", nil)), outer)
+	assert.Equal(t, inner, ErrInput(err))
+	assert.EqualError(t, err, "expected string value, got number from foo (5)")
+
+	err = ErrAt(errors.New("foo"), inner)
+	assert.Equal(t, inner, ErrInput(err))
+	assert.EqualError(t, err, "foo")
+
+	err = ErrAt(fmt.Errorf("bar: %w", err), outer)
+	assert.Equal(t, inner, ErrInput(err))
+	assert.EqualError(t, err, "bar: foo")
+
+	err = ErrAt(&ErrRecoverable{Recovered: "baz", Err: errors.New("foo")}, inner)
+	assert.IsType(t, &ErrRecoverable{}, err)
+	assert.Equal(t, inner, ErrInput(err))
+
+	assert.Nil(t, ErrInput(errors.New("foo")))
+	assert.Nil(t, ErrAt(nil, inner))
+}
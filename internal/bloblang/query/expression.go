@@ -2,6 +2,8 @@ package query
 
 import (
 	"fmt"
+	"strconv"
+	"strings"
 )
 
 // MatchCase represents a single match case of a match expression, where a case
@@ -19,6 +21,39 @@ func NewMatchCase(caseFn, queryFn Function) MatchCase {
 	}
 }
 
+// NewTypeMatchCase creates a single match case of a match expression that is
+// matched when the context is of a given type. When the case is matched the
+// underlying query is executed with the context also captured under a name,
+// unless the name is `_`.
+func NewTypeMatchCase(t ValueType, name string, queryFn Function) MatchCase {
+	caseFn := ClosureFunction("type case "+string(t), func(ctx FunctionContext) (interface{}, error) {
+		v := ctx.Value()
+		if v == nil {
+			return false, nil
+		}
+		return ITypeOf(*v) == t, nil
+	}, nil)
+	if name == "_" {
+		return MatchCase{caseFn, queryFn}
+	}
+	return MatchCase{
+		caseFn: caseFn,
+		queryFn: ClosureFunction(queryFn.Annotation(), func(ctx FunctionContext) (interface{}, error) {
+			if v := ctx.Value(); v != nil {
+				ctx = ctx.WithNamedValue(name, *v)
+			}
+			return queryFn.Exec(ctx)
+		}, func(ctx TargetsContext) (TargetsContext, []TargetPath) {
+			ctx.namedContext = &namedContextPath{
+				name:  name,
+				paths: ctx.mainContext,
+				next:  ctx.namedContext,
+			}
+			return queryFn.QueryTargets(ctx)
+		}),
+	}
+}
+
 // NewMatchFunction takes a contextual mapping and a list of MatchCases, when
 // the function is executed
 func NewMatchFunction(contextFn Function, cases ...MatchCase) Function {
@@ -76,6 +111,25 @@ type ElseIf struct {
 // return a boolean value. If the returned boolean is true then the ifFn is
 // executed and returned, otherwise elseFn is executed and returned.
 func NewIfFunction(queryFn, ifFn Function, elseIfs []ElseIf, elseFn Function) Function {
+	// When a condition is a literal the branch taken is already known, and so
+	// the expression is reduced to that branch.
+	for {
+		lit, isLit := queryFn.(*Literal)
+		if !isLit {
+			break
+		}
+		if queryRes, _ := lit.Value.(bool); queryRes {
+			return ifFn
+		}
+		if len(elseIfs) == 0 {
+			if elseFn != nil {
+				return elseFn
+			}
+			return NewLiteralFunction("if expression", Nothing(nil))
+		}
+		queryFn, ifFn, elseIfs = elseIfs[0].QueryFn, elseIfs[0].MapFn, elseIfs[1:]
+	}
+
 	allFns := []Function{
 		queryFn, ifFn, elseFn,
 	}
@@ -109,23 +163,74 @@ func NewIfFunction(queryFn, ifFn Function, elseIfs []ElseIf, elseFn Function) Fu
 	}, aggregateTargetPaths(allFns...))
 }
 
+// NewWhileFunction creates a bounded loop expression, where a query function is
+// repeatedly executed with its own result as the context for as long as a
+// condition query returns true, beginning with the current context. The result
+// is the context at the point that the condition returns false, or an error if
+// the condition remains true after the maximum number of iterations.
+func NewWhileFunction(queryFn, loopFn Function, maxIterations int64) Function {
+	return ClosureFunction("while expression", func(ctx FunctionContext) (interface{}, error) {
+		v := ctx.Value()
+		if v == nil {
+			return nil, fmt.Errorf("failed to begin loop: %w", ErrNoContext)
+		}
+		state := *v
+		for i := int64(0); ; i++ {
+			queryVal, err := queryFn.Exec(ctx.WithValue(state))
+			if err != nil {
+				return nil, fmt.Errorf("failed to check while condition: %w", err)
+			}
+			queryRes, isBool := queryVal.(bool)
+			if !isBool {
+				return nil, fmt.Errorf("failed to check while condition: %w", ErrFrom(NewTypeError(queryVal, ValueBool), queryFn))
+			}
+			if !queryRes {
+				return state, nil
+			}
+			if i >= maxIterations {
+				return nil, fmt.Errorf("loop exceeded the maximum of %v iterations", maxIterations)
+			}
+			if state, err = loopFn.Exec(ctx.WithValue(state)); err != nil {
+				return nil, fmt.Errorf("failed to execute loop iteration %v: %w", i, err)
+			}
+		}
+	}, func(ctx TargetsContext) (TargetsContext, []TargetPath) {
+		_, queryTargets := queryFn.QueryTargets(ctx)
+		_, loopTargets := loopFn.QueryTargets(ctx)
+		return ctx, append(queryTargets, loopTargets...)
+	})
+}
+
 // NewNamedContextFunction wraps a function and ensures that when the function
 // is executed with a new context the context is captured under a new name, with
 // the "main" context left intact.
 func NewNamedContextFunction(name string, fn Function) Function {
-	return &NamedContextFunction{name, fn}
+	return &NamedContextFunction{[]string{name}, fn}
+}
+
+// NewMultiNamedContextFunction wraps a function and ensures that when the
+// function is executed with a new context the context, which must be an array
+// with an element for each name, is captured with each element under a new
+// name, with the "main" context left intact.
+func NewMultiNamedContextFunction(names []string, fn Function) Function {
+	return &NamedContextFunction{names, fn}
 }
 
 // NamedContextFunction wraps a query function in a mechanism that captures the
 // current context under an alias.
 type NamedContextFunction struct {
-	name string
-	fn   Function
+	names []string
+	fn    Function
 }
 
 // Name returns the alias under which the context will be captured.
 func (n *NamedContextFunction) Name() string {
-	return n.name
+	return strings.Join(n.names, ", ")
+}
+
+// Params returns the number of context parameters captured by the function.
+func (n *NamedContextFunction) Params() int {
+	return len(n.names)
 }
 
 // Annotation returns the annotation of the underlying function.
@@ -138,21 +243,76 @@ func (n *NamedContextFunction) Annotation() string {
 func (n *NamedContextFunction) Exec(ctx FunctionContext) (interface{}, error) {
 	v, nextCtx := ctx.PopValue()
 	if v == nil {
-		return nil, fmt.Errorf("failed to capture context %v: %w", n.name, ErrNoContext)
+		return nil, fmt.Errorf("failed to capture context %v: %w", n.Name(), ErrNoContext)
 	}
-	if n.name != "_" {
-		nextCtx = nextCtx.WithNamedValue(n.name, *v)
+	if len(n.names) == 1 {
+		if n.names[0] != "_" {
+			nextCtx = nextCtx.WithNamedValue(n.names[0], *v)
+		}
+		return n.fn.Exec(nextCtx)
+	}
+	params, ok := (*v).([]interface{})
+	if !ok || len(params) != len(n.names) {
+		return nil, fmt.Errorf("failed to capture context (%v): expected %v parameters, got %v", n.Name(), len(n.names), describeParams(*v))
+	}
+	for i, name := range n.names {
+		if name != "_" {
+			nextCtx = nextCtx.WithNamedValue(name, params[i])
+		}
 	}
 	return n.fn.Exec(nextCtx)
 }
 
+func describeParams(v interface{}) string {
+	if arr, ok := v.([]interface{}); ok {
+		return strconv.Itoa(len(arr))
+	}
+	return string(ITypeOf(v))
+}
+
+// ExecWithParams executes a query function with a context value, unless the
+// function is a lambda that declares multiple context parameters, in which case
+// the function is executed with the parameters captured by name instead.
+func ExecWithParams(fn Function, ctx FunctionContext, value interface{}, params ...interface{}) (interface{}, error) {
+	if n, ok := fn.(*NamedContextFunction); ok && n.Params() > 1 {
+		if len(params) != n.Params() {
+			return nil, fmt.Errorf("failed to capture context (%v): expected %v parameters, got %v", n.Name(), n.Params(), len(params))
+		}
+		return fn.Exec(ctx.WithValue(params))
+	}
+	return fn.Exec(ctx.WithValue(value))
+}
+
 // QueryTargets provides a summary of which fields the underlying query function
 // targets.
 func (n *NamedContextFunction) QueryTargets(ctx TargetsContext) (TargetsContext, []TargetPath) {
-	if n.name == "_" {
+	var names []string
+	for _, name := range n.names {
+		if name != "_" {
+			names = append(names, name)
+		}
+	}
+	if len(names) == 0 {
 		ctx = ctx.PopContext()
 	} else {
-		ctx = ctx.WithContextAsNamed(n.name)
+		ctx = ctx.WithContextAsNamed(names...)
 	}
 	return n.fn.QueryTargets(ctx)
 }
+
+// NewInterpolatedStringFunction creates a query function that executes a list
+// of query functions and returns the results concatenated as a string, which is
+// used for string literals containing interpolations.
+func NewInterpolatedStringFunction(fns []Function) Function {
+	return ClosureFunction("interpolated string", func(ctx FunctionContext) (interface{}, error) {
+		var buf strings.Builder
+		for _, fn := range fns {
+			v, err := fn.Exec(ctx)
+			if err != nil {
+				return nil, err
+			}
+			buf.WriteString(IToString(v))
+		}
+		return buf.String(), nil
+	}, aggregateTargetPaths(fns...))
+}
@@ -269,6 +269,63 @@ func TestExpressions(t *testing.T) {
 	}
 }
 
+func TestIfFunctionLiteralConditions(t *testing.T) {
+	ifFn := NewFieldFunction("if")
+	elseIfFn := NewFieldFunction("else_if")
+	elseFn := NewFieldFunction("else")
+
+	tests := map[string]struct {
+		input  Function
+		output Function
+	}{
+		"true condition": {
+			input:  NewIfFunction(NewLiteralFunction("", true), ifFn, nil, elseFn),
+			output: ifFn,
+		},
+		"false condition": {
+			input:  NewIfFunction(NewLiteralFunction("", false), ifFn, nil, elseFn),
+			output: elseFn,
+		},
+		"non-bool condition": {
+			input:  NewIfFunction(NewLiteralFunction("", "true"), ifFn, nil, elseFn),
+			output: elseFn,
+		},
+		"false condition without else": {
+			input:  NewIfFunction(NewLiteralFunction("", false), ifFn, nil, nil),
+			output: NewLiteralFunction("if expression", Nothing(nil)),
+		},
+		"true else if condition": {
+			input: NewIfFunction(NewLiteralFunction("", false), ifFn, []ElseIf{
+				{QueryFn: NewLiteralFunction("", true), MapFn: elseIfFn},
+			}, elseFn),
+			output: elseIfFn,
+		},
+	}
+
+	for name, test := range tests {
+		test := test
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, test.output, test.input)
+		})
+	}
+
+	dynamic := NewIfFunction(NewLiteralFunction("", false), ifFn, []ElseIf{
+		{QueryFn: NewFieldFunction("cond"), MapFn: elseIfFn},
+	}, elseFn)
+	assert.Equal(t, "if expression", dynamic.Annotation())
+
+	res, err := dynamic.Exec(FunctionContext{
+		MsgBatch: message.New(nil),
+	}.WithValue(map[string]interface{}{"cond": true, "else_if": "foo"}))
+	require.NoError(t, err)
+	assert.Equal(t, "foo", res)
+}
+
+func TestNotLiteral(t *testing.T) {
+	assert.Equal(t, NewLiteralFunction("", false), Not(NewLiteralFunction("", true)))
+	assert.IsType(t, &notMethod{}, Not(NewLiteralFunction("", "true")))
+}
+
 func TestExpressionTargets(t *testing.T) {
 	mustFunc := func(fn Function, err error) Function {
 		t.Helper()
@@ -24,12 +24,17 @@ func NewFunctionSet() *FunctionSet {
 var nameRegexpRaw = `^[a-z0-9]+(_[a-z0-9]+)*$`
 var nameRegexp = regexp.MustCompile(nameRegexpRaw)
 
+// Functions and methods may optionally be prefixed with a single namespace,
+// e.g. `acme.lookup_sku`, in order to avoid collisions between plugins.
+var namespacedNameRegexpRaw = `^([a-z0-9]+(_[a-z0-9]+)*\.)?[a-z0-9]+(_[a-z0-9]+)*$`
+var namespacedNameRegexp = regexp.MustCompile(namespacedNameRegexpRaw)
+
 // Add a new function to this set by providing a spec (name and documentation),
 // a constructor to be called for each instantiation of the function, and
 // information regarding the arguments of the function.
 func (f *FunctionSet) Add(spec FunctionSpec, ctor FunctionCtor) error {
-	if !nameRegexp.MatchString(spec.Name) {
-		return fmt.Errorf("function name '%v' does not match the required regular expression /%v/", spec.Name, nameRegexpRaw)
+	if !namespacedNameRegexp.MatchString(spec.Name) {
+		return fmt.Errorf("function name '%v' does not match the required regular expression /%v/", spec.Name, namespacedNameRegexpRaw)
 	}
 	if _, exists := f.constructors[spec.Name]; exists {
 		return fmt.Errorf("conflicting function name: %v", spec.Name)
@@ -42,6 +47,27 @@ func (f *FunctionSet) Add(spec FunctionSpec, ctor FunctionCtor) error {
 	return nil
 }
 
+// AddAlias adds a deprecated alias to this set that forwards to an existing
+// function, which allows functions to be renamed without breaking mappings
+// that use the old name. Mappings that use the alias are warned by the linter.
+func (f *FunctionSet) AddAlias(alias, target string) error {
+	spec, exists := f.specs[target]
+	if !exists {
+		return fmt.Errorf("function '%v' cannot be aliased as it does not exist", target)
+	}
+	if spec.AliasOf != "" {
+		target = spec.AliasOf
+	}
+
+	spec.Name = alias
+	spec.Status = StatusDeprecated
+	spec.Category = FunctionCategoryDeprecated
+	spec.Description = fmt.Sprintf("This function is a deprecated alias of [`%v`](#%v).", target, target)
+	spec.Examples = nil
+	spec.AliasOf = target
+	return f.Add(spec, f.constructors[target])
+}
+
 // Docs returns a slice of function specs, which document each function.
 func (f *FunctionSet) Docs() []FunctionSpec {
 	specSlice := make([]FunctionSpec, 0, len(f.specs))
@@ -73,6 +99,12 @@ func (f *FunctionSet) Params(name string) (Params, error) {
 	return spec.Params, nil
 }
 
+// Spec attempts to obtain the spec (name and documentation) of a function.
+func (f *FunctionSet) Spec(name string) (FunctionSpec, bool) {
+	spec, exists := f.specs[name]
+	return spec, exists
+}
+
 // Init attempts to initialize a function of the set by name and zero or more
 // arguments.
 func (f *FunctionSet) Init(name string, args *ParsedParams) (Function, error) {
@@ -98,12 +130,17 @@ func (f *FunctionSet) Without(functions ...string) *FunctionSet {
 		}
 	}
 
-	specs := map[string]FunctionSpec{}
+	// Aliases are removed along with the functions that they forward to.
 	for _, v := range f.specs {
-		if _, exists := excludeMap[v.Name]; !exists {
-			specs[v.Name] = v
+		if _, exists := excludeMap[v.AliasOf]; v.AliasOf != "" && exists {
+			delete(constructors, v.Name)
 		}
 	}
+
+	specs := map[string]FunctionSpec{}
+	for k := range constructors {
+		specs[k] = f.specs[k]
+	}
 	return &FunctionSet{constructors, specs}
 }
 
@@ -4,6 +4,7 @@ import (
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestFunctionSetWithout(t *testing.T) {
@@ -34,20 +35,52 @@ func TestFunctionSetOnlyPure(t *testing.T) {
 	assert.NotContains(t, setTwo.List(), "file")
 }
 
+func TestFunctionSetAlias(t *testing.T) {
+	setOne := AllFunctions.Without()
+	require.NoError(t, setOne.AddAlias("environment", "env"))
+
+	assert.EqualError(t, setOne.AddAlias("environment", "hostname"), "conflicting function name: environment")
+	assert.EqualError(t, setOne.AddAlias("nope", "does_not_exist"), "function 'does_not_exist' cannot be aliased as it does not exist")
+	assert.NotContains(t, AllFunctions.List(), "environment")
+
+	spec, exists := setOne.Spec("environment")
+	require.True(t, exists)
+	assert.Equal(t, StatusDeprecated, spec.Status)
+	assert.Equal(t, FunctionCategoryDeprecated, spec.Category)
+	assert.Equal(t, "env", spec.AliasOf)
+	assert.True(t, spec.Impure)
+
+	params, err := setOne.Params("environment")
+	require.NoError(t, err)
+
+	parsedParams, err := params.PopulateNameless("BENTHOS_TEST_ALIAS")
+	require.NoError(t, err)
+
+	_, err = setOne.Init("environment", parsedParams)
+	require.NoError(t, err)
+
+	assert.NotContains(t, setOne.OnlyPure().List(), "environment")
+	assert.NotContains(t, setOne.Without("env").List(), "environment")
+}
+
 func TestFunctionBadName(t *testing.T) {
 	testCases := map[string]string{
-		"!no":         "function name '!no' does not match the required regular expression /^[a-z0-9]+(_[a-z0-9]+)*$/",
-		"foo__bar":    "function name 'foo__bar' does not match the required regular expression /^[a-z0-9]+(_[a-z0-9]+)*$/",
-		"-foo-bar":    "function name '-foo-bar' does not match the required regular expression /^[a-z0-9]+(_[a-z0-9]+)*$/",
-		"foo-bar-":    "function name 'foo-bar-' does not match the required regular expression /^[a-z0-9]+(_[a-z0-9]+)*$/",
-		"":            "function name '' does not match the required regular expression /^[a-z0-9]+(_[a-z0-9]+)*$/",
-		"foo-bar":     "function name 'foo-bar' does not match the required regular expression /^[a-z0-9]+(_[a-z0-9]+)*$/",
-		"foo-bar_baz": "function name 'foo-bar_baz' does not match the required regular expression /^[a-z0-9]+(_[a-z0-9]+)*$/",
-		"FOO":         "function name 'FOO' does not match the required regular expression /^[a-z0-9]+(_[a-z0-9]+)*$/",
-		"foobarbaz":   "",
-		"foobarbaz89": "",
-		"foo_bar_baz": "",
-		"fo1_ba2_ba3": "",
+		"!no":          "function name '!no' does not match the required regular expression /^([a-z0-9]+(_[a-z0-9]+)*\\.)?[a-z0-9]+(_[a-z0-9]+)*$/",
+		"foo__bar":     "function name 'foo__bar' does not match the required regular expression /^([a-z0-9]+(_[a-z0-9]+)*\\.)?[a-z0-9]+(_[a-z0-9]+)*$/",
+		"-foo-bar":     "function name '-foo-bar' does not match the required regular expression /^([a-z0-9]+(_[a-z0-9]+)*\\.)?[a-z0-9]+(_[a-z0-9]+)*$/",
+		"foo-bar-":     "function name 'foo-bar-' does not match the required regular expression /^([a-z0-9]+(_[a-z0-9]+)*\\.)?[a-z0-9]+(_[a-z0-9]+)*$/",
+		"":             "function name '' does not match the required regular expression /^([a-z0-9]+(_[a-z0-9]+)*\\.)?[a-z0-9]+(_[a-z0-9]+)*$/",
+		"foo-bar":      "function name 'foo-bar' does not match the required regular expression /^([a-z0-9]+(_[a-z0-9]+)*\\.)?[a-z0-9]+(_[a-z0-9]+)*$/",
+		"foo-bar_baz":  "function name 'foo-bar_baz' does not match the required regular expression /^([a-z0-9]+(_[a-z0-9]+)*\\.)?[a-z0-9]+(_[a-z0-9]+)*$/",
+		"FOO":          "function name 'FOO' does not match the required regular expression /^([a-z0-9]+(_[a-z0-9]+)*\\.)?[a-z0-9]+(_[a-z0-9]+)*$/",
+		"foobarbaz":    "",
+		"foobarbaz89":  "",
+		"foo_bar_baz":  "",
+		"fo1_ba2_ba3":  "",
+		"acme.foo":     "",
+		"acme.foo_bar": "",
+		"acme.":        "function name 'acme.' does not match the required regular expression /^([a-z0-9]+(_[a-z0-9]+)*\\.)?[a-z0-9]+(_[a-z0-9]+)*$/",
+		"a.b.c":        "function name 'a.b.c' does not match the required regular expression /^([a-z0-9]+(_[a-z0-9]+)*\\.)?[a-z0-9]+(_[a-z0-9]+)*$/",
 	}
 
 	for k, v := range testCases {
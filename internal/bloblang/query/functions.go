@@ -7,6 +7,7 @@ import (
 	"io/ioutil"
 	"math/rand"
 	"os"
+	"path/filepath"
 	"sync"
 	"time"
 
@@ -19,15 +20,22 @@ import (
 type fieldFunction struct {
 	namedContext string
 	path         []string
+	resolved     resolvedPath
+}
+
+func newFieldFunction(namedContext string, path []string) *fieldFunction {
+	return &fieldFunction{
+		namedContext: namedContext,
+		path:         path,
+		resolved:     resolvePath(path),
+	}
 }
 
 func (f *fieldFunction) expand(path ...string) *fieldFunction {
-	newFn := *f
 	newPath := make([]string, 0, len(f.path)+len(path))
 	newPath = append(newPath, f.path...)
 	newPath = append(newPath, path...)
-	newFn.path = newPath
-	return &newFn
+	return newFieldFunction(f.namedContext, newPath)
 }
 
 func (f *fieldFunction) Annotation() string {
@@ -61,7 +69,7 @@ func (f *fieldFunction) Exec(ctx FunctionContext) (interface{}, error) {
 	if len(f.path) == 0 {
 		return target, nil
 	}
-	return gabs.Wrap(target).S(f.path...).Data(), nil
+	return f.resolved.get(target), nil
 }
 
 func (f *fieldFunction) QueryTargets(ctx TargetsContext) (TargetsContext, []TargetPath) {
@@ -93,7 +101,7 @@ func NewNamedContextFieldFunction(namedContext, pathStr string) Function {
 	if len(pathStr) > 0 {
 		path = gabs.DotPathToSlice(pathStr)
 	}
-	return &fieldFunction{namedContext, path}
+	return newFieldFunction(namedContext, path)
 }
 
 // NewFieldFunction creates a query function that returns a field from the
@@ -103,9 +111,7 @@ func NewFieldFunction(pathStr string) Function {
 	if len(pathStr) > 0 {
 		path = gabs.DotPathToSlice(pathStr)
 	}
-	return &fieldFunction{
-		path: path,
-	}
+	return newFieldFunction("", path)
 }
 
 //------------------------------------------------------------------------------
@@ -234,6 +240,49 @@ func countFunction(args *ParsedParams) (Function, error) {
 	}, nil), nil
 }
 
+var _ = registerFunction(
+	NewFunctionSpec(
+		FunctionCategoryGeneral, "counter",
+		"Returns an integer starting at 1 that increments each time the function is called, which is useful for numbering the records emitted by a pipeline. Counters are identified by a name and counters of the same name are shared by all mappings within the process, but are separate from those of the [`count`](#count) function. Counters are held in memory only and therefore start again from 1 when the process restarts.",
+		NewExampleSpec("",
+			`root = this
+root.seq = counter("bloblang_counter_example")`,
+			`{"message":"foo"}`,
+			`{"message":"foo","seq":1}`,
+			`{"message":"bar"}`,
+			`{"message":"bar","seq":2}`,
+		),
+	).Beta().MarkImpure().Param(ParamString("name", "An identifier for the counter.")),
+	func(args *ParsedParams) (Function, error) {
+		name, err := args.FieldString("name")
+		if err != nil {
+			return nil, err
+		}
+		return ClosureFunction("function counter", func(ctx FunctionContext) (interface{}, error) {
+			return globalNamedCounters.increment(name), nil
+		}, nil), nil
+	},
+)
+
+// namedCounters holds the counters of the counter function, which are kept
+// apart from those of count so that the two never interfere.
+type namedCounters struct {
+	mut    sync.Mutex
+	values map[string]int64
+}
+
+var globalNamedCounters = &namedCounters{
+	values: map[string]int64{},
+}
+
+func (c *namedCounters) increment(name string) int64 {
+	c.mut.Lock()
+	defer c.mut.Unlock()
+
+	c.values[name]++
+	return c.values[name]
+}
+
 //------------------------------------------------------------------------------
 
 var _ = registerFunction(
@@ -263,13 +312,18 @@ root.bar = deleted()`,
 var _ = registerFunction(
 	NewFunctionSpec(
 		FunctionCategoryEnvironment, "env",
-		"Returns the value of an environment variable, or an empty string if the environment variable does not exist.",
+		"Returns the value of an environment variable, or an empty string if the environment variable does not exist. A default value can be provided for when the variable does not exist, or alternatively the variable can be marked as required, in which case the mapping fails to initialise when the variable does not exist.",
 		NewExampleSpec("",
 			`root.thing.key = env("key")`,
 		),
+		NewExampleSpec("",
+			`root.thing.region = env(name: "REGION", default: "eu-west-1")`,
+		),
 	).
 		MarkImpure().
-		Param(ParamString("name", "The name of an environment variable.")),
+		Param(ParamString("name", "The name of an environment variable.")).
+		Param(ParamString("default", "A value to return when the environment variable does not exist, which is only evaluated when it is needed.").Optional().Lazy()).
+		Param(ParamBool("required", "Whether the mapping should fail to initialise when the environment variable does not exist. Cannot be combined with a default value.").Default(false)),
 	envFunction,
 )
 
@@ -278,22 +332,79 @@ func envFunction(args *ParsedParams) (Function, error) {
 	if err != nil {
 		return nil, err
 	}
-	key := os.Getenv(name)
+	defaultFn, err := args.FieldLazy("default")
+	if err != nil {
+		return nil, err
+	}
+	required, err := args.FieldBool("required")
+	if err != nil {
+		return nil, err
+	}
+	if required && defaultFn != nil {
+		return nil, errors.New("a default value cannot be provided for a required environment variable")
+	}
+	key, exists := os.LookupEnv(name)
+	if !exists {
+		if required {
+			return nil, fmt.Errorf("required environment variable %v is not set", name)
+		}
+		if defaultFn != nil {
+			if lit, isLit := defaultFn.(*Literal); isLit {
+				key, _ = lit.Value.(string)
+			} else {
+				// The default is only executed when the mapping is, as the
+				// variable doesn't exist.
+				return defaultFn, nil
+			}
+		}
+	}
 	return NewLiteralFunction("env "+key, key), nil
 }
 
 //------------------------------------------------------------------------------
 
-var _ = registerSimpleFunction(
+var _ = registerFunction(
 	NewFunctionSpec(
 		FunctionCategoryMessage, "error",
-		"If an error has occurred during the processing of a message this function returns the reported cause of the error. For more information about error handling patterns read [here][error_handling].",
+		"If an error has occurred during the processing of a message this function returns the reported cause of the error. For more information about error handling patterns read [here][error_handling].\n\nWhen the parameter `structured` is `true` the error is instead returned as an object containing the fields `message`, which is the reported cause of the error, `label`, which is the label of the processor that failed (or `null` if the processor has no label), and `path`, which is the path of the processor within the config. If the message has not failed then `null` is returned.",
 		NewExampleSpec("",
 			`root.doc.error = error()`,
 		),
-	),
-	func(ctx FunctionContext) (interface{}, error) {
-		return ctx.MsgBatch.Get(ctx.Index).Metadata().Get(types.FailFlagKey), nil
+		NewExampleSpec(
+			"A structured error is useful for producing dead-letter payloads that identify where within a config a message failed.",
+			`root.content = content().string()
+root.error = error(structured: true)`,
+		),
+	).Param(ParamBool("structured", "Whether to return the error as a structured object.").Default(false)),
+	func(args *ParsedParams) (Function, error) {
+		structured, err := args.FieldBool("structured")
+		if err != nil {
+			return nil, err
+		}
+		if !structured {
+			return ClosureFunction("function error", func(ctx FunctionContext) (interface{}, error) {
+				return ctx.MsgBatch.Get(ctx.Index).Metadata().Get(types.FailFlagKey), nil
+			}, nil), nil
+		}
+		return ClosureFunction("function error", func(ctx FunctionContext) (interface{}, error) {
+			meta := ctx.MsgBatch.Get(ctx.Index).Metadata()
+			errStr := meta.Get(types.FailFlagKey)
+			if errStr == "" {
+				return nil, nil
+			}
+			var label, path interface{}
+			if v := meta.Get(types.FailLabelKey); v != "" {
+				label = v
+			}
+			if v := meta.Get(types.FailPathKey); v != "" {
+				path = v
+			}
+			return map[string]interface{}{
+				"message": errStr,
+				"label":   label,
+				"path":    path,
+			}, nil
+		}, nil), nil
 	},
 )
 
@@ -315,14 +426,23 @@ var _ = registerSimpleFunction(
 var _ = registerFunction(
 	NewFunctionSpec(
 		FunctionCategoryEnvironment, "file",
-		"Reads a file and returns its contents. Relative paths are resolved from the directory of the process executing the mapping.",
+		"Reads a file and returns its contents. Relative paths are resolved from the directory of the process executing the mapping, or from the directory of the mapping file when `relative_to_mapping` is `true`. Mappings embedded within a config resolve relative paths from the directory of the config file when `relative_to_mapping` is `true` and the service is run with a single config file, or when the config is tested with the `test` subcommand.\n\nFiles with a static path are read once when the mapping is initialised, and files with a dynamic path are read each time the function is executed. When a `cache_ttl` is set the contents of files are instead cached and read again once the duration has passed, and when `watch` is `true` the contents are cached and read again whenever the file is modified, which allows lookup tables to be updated without restarting and avoids reading files with dynamic paths for every message.",
 		NewExampleSpec("",
 			`root.doc = file(env("BENTHOS_TEST_BLOBLANG_FILE")).parse_json()`,
 			`{}`,
 			`{"doc":{"foo":"bar"}}`,
 		),
+		NewExampleSpec("",
+			`root.region = file(path: "./regions/" + this.country + ".txt", cache_ttl: "1m").string()`,
+		),
+		NewExampleSpec("",
+			`root.allowed = file(path: "./allow_lists/" + this.group + ".txt", relative_to_mapping: true, watch: true).string().split("\n").contains(this.user)`,
+		),
 	).Beta().MarkImpure().
-		Param(ParamString("path", "The path of the target file.")),
+		Param(ParamString("path", "The path of the target file.")).
+		Param(ParamBool("relative_to_mapping", "Whether relative paths should be resolved from the directory of the mapping or config file.").Default(false)).
+		Param(ParamString("cache_ttl", "An optional duration string, after which the cached contents of a file are read again.").Optional()).
+		Param(ParamBool("watch", "Whether the cached contents of a file should be read again when the file is modified. Modifications are checked for at most once per second.").Default(false)),
 	fileFunction,
 )
 
@@ -331,11 +451,134 @@ func fileFunction(args *ParsedParams) (Function, error) {
 	if err != nil {
 		return nil, err
 	}
-	pathBytes, err := ioutil.ReadFile(path)
+	relative, err := args.FieldBool("relative_to_mapping")
+	if err != nil {
+		return nil, err
+	}
+	if relative && !filepath.IsAbs(path) {
+		if dir := args.MappingDir(); dir != "" {
+			path = filepath.Join(dir, path)
+		}
+	}
+
+	ttlStr, err := args.FieldOptionalString("cache_ttl")
+	if err != nil {
+		return nil, err
+	}
+	watch, err := args.FieldBool("watch")
 	if err != nil {
 		return nil, err
 	}
-	return NewLiteralFunction("file "+path, pathBytes), nil
+	if ttlStr == nil && !watch {
+		pathBytes, err := ioutil.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+		return NewLiteralFunction("file "+path, pathBytes), nil
+	}
+
+	var ttl time.Duration
+	if ttlStr != nil {
+		if ttl, err = time.ParseDuration(*ttlStr); err != nil {
+			return nil, fmt.Errorf("failed to parse cache_ttl: %w", err)
+		}
+		if ttl <= 0 {
+			return nil, errors.New("cache_ttl must be greater than zero")
+		}
+	}
+	if _, err := globalFileCache.read(path, ttl, watch); err != nil {
+		return nil, err
+	}
+	return ClosureFunction("file "+path, func(ctx FunctionContext) (interface{}, error) {
+		return globalFileCache.read(path, ttl, watch)
+	}, nil), nil
+}
+
+const (
+	fileCacheLimit    = 1024
+	fileWatchInterval = time.Second
+)
+
+type cachedFile struct {
+	contents  []byte
+	readAt    time.Time
+	checkedAt time.Time
+	modTime   time.Time
+	size      int64
+}
+
+// fileCache holds the contents of files read by the file function, shared
+// across all mappings so that a file is only held in memory once. Once the
+// cache reaches its limit the least recently read entry is evicted.
+type fileCache struct {
+	mut   sync.Mutex
+	limit int
+	files map[string]cachedFile
+}
+
+var globalFileCache = newFileCache(fileCacheLimit)
+
+func newFileCache(limit int) *fileCache {
+	return &fileCache{
+		limit: limit,
+		files: map[string]cachedFile{},
+	}
+}
+
+func (c *fileCache) read(path string, ttl time.Duration, watch bool) ([]byte, error) {
+	c.mut.Lock()
+	defer c.mut.Unlock()
+
+	now := time.Now()
+	f, exists := c.files[path]
+	if exists && ttl > 0 && now.Sub(f.readAt) >= ttl {
+		exists = false
+	}
+	if exists && watch && now.Sub(f.checkedAt) >= fileWatchInterval {
+		info, err := os.Stat(path)
+		if err != nil || !info.ModTime().Equal(f.modTime) || info.Size() != f.size {
+			exists = false
+		} else {
+			f.checkedAt = now
+			c.files[path] = f
+		}
+	}
+	if exists {
+		return f.contents, nil
+	}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		delete(c.files, path)
+		return nil, err
+	}
+	contents, err := ioutil.ReadFile(path)
+	if err != nil {
+		delete(c.files, path)
+		return nil, err
+	}
+	if _, exists := c.files[path]; !exists && len(c.files) >= c.limit {
+		c.evictOldest()
+	}
+	c.files[path] = cachedFile{
+		contents:  contents,
+		readAt:    now,
+		checkedAt: now,
+		modTime:   info.ModTime(),
+		size:      info.Size(),
+	}
+	return contents, nil
+}
+
+func (c *fileCache) evictOldest() {
+	var oldestPath string
+	var oldest time.Time
+	for path, f := range c.files {
+		if oldestPath == "" || f.readAt.Before(oldest) {
+			oldestPath, oldest = path, f.readAt
+		}
+	}
+	delete(c.files, oldestPath)
 }
 
 //------------------------------------------------------------------------------
@@ -405,6 +648,42 @@ var _ = registerSimpleFunction(
 	},
 )
 
+var _ = registerSimpleFunction(
+	NewFunctionSpec(
+		FunctionCategoryEnvironment, "pid",
+		"Returns the process ID of the running Benthos instance as an integer.",
+		NewExampleSpec("",
+			`root.thing.pid = pid()`,
+		),
+	).MarkImpure(),
+	func(_ FunctionContext) (interface{}, error) {
+		return int64(os.Getpid()), nil
+	},
+)
+
+var instanceID string
+var instanceIDOnce sync.Once
+
+var _ = registerSimpleFunction(
+	NewFunctionSpec(
+		FunctionCategoryEnvironment, "instance_id",
+		"Returns a string that uniquely identifies the running Benthos instance, which is a random UUID V4 generated the first time it is called and remains the same for the lifetime of the process. This can be used to distinguish the origin of messages from multiple instances running on the same host.",
+		NewExampleSpec("",
+			`root.thing.instance = instance_id()`,
+		),
+	).MarkImpure(),
+	func(_ FunctionContext) (interface{}, error) {
+		instanceIDOnce.Do(func() {
+			u4, err := uuid.NewV4()
+			if err != nil {
+				panic(err)
+			}
+			instanceID = u4.String()
+		})
+		return instanceID, nil
+	},
+)
+
 //------------------------------------------------------------------------------
 
 var _ = registerFunction(
@@ -580,6 +859,61 @@ var _ = registerFunction(
 	},
 )
 
+func metadataMap(p types.Part) map[string]interface{} {
+	kvs := map[string]interface{}{}
+	_ = p.Metadata().Iter(func(k, v string) error {
+		kvs[k] = v
+		return nil
+	})
+	return kvs
+}
+
+func metadataMapTargets(ctx TargetsContext) (TargetsContext, []TargetPath) {
+	paths := []TargetPath{
+		NewTargetPath(TargetMetadata),
+	}
+	ctx = ctx.WithValues(paths)
+	return ctx, paths
+}
+
+var _ = registerFunction(
+	NewFunctionSpec(
+		FunctionCategoryMessage, "meta_map",
+		"Returns an object containing all metadata key/value pairs of the input message. Since values are extracted from the read-only input message they do NOT reflect changes made from within the map, in order to query metadata mutations made within a mapping use the [`root_meta_map` function](#root_meta_map). Unlike `meta()` keys with empty values are included.",
+		NewExampleSpec("",
+			`root.metadata = meta_map()`,
+		),
+		NewExampleSpec(
+			"Since metadata can be assigned as an object it can be copied wholesale while being filtered or transformed.",
+			`meta = meta_map().filter(kv -> !kv.key.has_prefix("kafka_"))`,
+		),
+	),
+	func(*ParsedParams) (Function, error) {
+		return ClosureFunction("function meta_map", func(ctx FunctionContext) (interface{}, error) {
+			return metadataMap(ctx.MsgBatch.Get(ctx.Index)), nil
+		}, metadataMapTargets), nil
+	},
+)
+
+var _ = registerFunction(
+	NewFunctionSpec(
+		FunctionCategoryMessage, "root_meta_map",
+		"Returns an object containing all metadata key/value pairs of the new message being created. Changes made to metadata during a mapping will be reflected by this function.",
+		NewExampleSpec("",
+			`meta foo = "bar"
+root.metadata = root_meta_map()`,
+		),
+	).Beta(),
+	func(*ParsedParams) (Function, error) {
+		return ClosureFunction("function root_meta_map", func(ctx FunctionContext) (interface{}, error) {
+			if ctx.NewMsg == nil {
+				return nil, errors.New("root metadata cannot be queried in this context")
+			}
+			return metadataMap(ctx.NewMsg), nil
+		}, metadataMapTargets), nil
+	},
+)
+
 //------------------------------------------------------------------------------
 
 var _ = registerFunction(
@@ -594,7 +928,7 @@ var _ = registerFunction(
 var _ = registerOldParamsFunction(
 	NewFunctionSpec(
 		FunctionCategoryGeneral, "random_int",
-		"Generates a non-negative pseudo-random 64-bit integer. An optional integer argument can be provided in order to seed the random number generator.",
+		"Generates a non-negative pseudo-random 64-bit integer. An optional integer argument can be provided in order to seed the random number generator. In order to generate a deterministic number from a value of each message, such as a user ID, use the method [`random_int`](/docs/guides/bloblang/methods#random_int) instead.",
 		NewExampleSpec("",
 			`root.first = random_int()
 root.second = random_int(1)`,
@@ -651,17 +985,49 @@ func randomIntFunction(args ...interface{}) (Function, error) {
 var _ = registerFunction(
 	NewFunctionSpec(
 		FunctionCategoryEnvironment, "now",
-		"Returns the current timestamp as a string in ISO 8601 format with the local timezone. Use the method `format_timestamp` in order to change the format and timezone.",
+		"Returns the current timestamp as a string in ISO 8601 format with the local timezone. An optional format and timezone can be provided in order to change the output, where the format follows the same rules as the method [`format_timestamp`][methods.format_timestamp]. The format can also be one of `unix`, `unix_milli` or `unix_nano` in order to return the current unix timestamp as an integer in seconds, milliseconds or nanoseconds respectively.",
 		NewExampleSpec("",
 			`root.received_at = now()`,
 		),
 		NewExampleSpec("",
-			`root.received_at = now().format_timestamp("Mon Jan 2 15:04:05 -0700 MST 2006", "UTC")`,
+			`root.received_at = now("Mon Jan 2 15:04:05 -0700 MST 2006", "UTC")`,
 		),
-	),
+		NewExampleSpec("",
+			`root.received_date = now(format: "2006-01-02", tz: "America/New_York")
+root.received_at_ms = now("unix_milli")`,
+		),
+	).
+		Param(ParamString("format", "The output format to use.").Default(time.RFC3339Nano)).
+		Param(ParamString("tz", "An optional timezone to use, otherwise the local timezone is used.").Optional()),
 	func(args *ParsedParams) (Function, error) {
+		format, err := args.FieldString("format")
+		if err != nil {
+			return nil, err
+		}
+		tzOpt, err := args.FieldOptionalString("tz")
+		if err != nil {
+			return nil, err
+		}
+		var timezone *time.Location
+		if tzOpt != nil {
+			if timezone, err = time.LoadLocation(*tzOpt); err != nil {
+				return nil, fmt.Errorf("failed to parse timezone location name: %w", err)
+			}
+		}
 		return ClosureFunction("function now", func(_ FunctionContext) (interface{}, error) {
-			return time.Now().Format(time.RFC3339Nano), nil
+			t := time.Now()
+			switch format {
+			case "unix":
+				return t.Unix(), nil
+			case "unix_milli":
+				return t.UnixNano() / int64(time.Millisecond), nil
+			case "unix_nano":
+				return t.UnixNano(), nil
+			}
+			if timezone != nil {
+				t = t.In(timezone)
+			}
+			return t.Format(format), nil
 		}, nil), nil
 	},
 )
@@ -0,0 +1,75 @@
+package query
+
+import (
+	"fmt"
+	"sync"
+)
+
+var constants = struct {
+	mut     sync.RWMutex
+	values  map[string]interface{}
+	version uint64
+}{
+	values: map[string]interface{}{},
+}
+
+// SetConstants replaces the values that can be referenced by name with the
+// constant function.
+func SetConstants(values map[string]interface{}) {
+	constants.mut.Lock()
+	defer constants.mut.Unlock()
+
+	constants.values = make(map[string]interface{}, len(values))
+	for k, v := range values {
+		constants.values[k] = v
+	}
+	constants.version++
+}
+
+// ConstantsVersion returns a number that changes each time the constants are
+// replaced, since mappings capture the values of constants when parsed.
+func ConstantsVersion() uint64 {
+	constants.mut.RLock()
+	defer constants.mut.RUnlock()
+
+	return constants.version
+}
+
+func getConstant(name string) (interface{}, bool) {
+	constants.mut.RLock()
+	defer constants.mut.RUnlock()
+
+	v, exists := constants.values[name]
+	return v, exists
+}
+
+var _ = registerFunction(
+	NewFunctionSpec(
+		FunctionCategoryEnvironment, "constant",
+		"Returns the value of a constant defined within the `constants` field of a config, where each constant is computed once when the config is loaded.",
+		NewExampleSpec("",
+			`root.environment = constant("environment")
+root.region_name = constant("regions").get(this.region)`,
+		),
+	).Beta().MarkImpure().
+		Param(ParamString("name", "The name of the constant.")),
+	func(args *ParsedParams) (Function, error) {
+		name, err := args.FieldString("name")
+		if err != nil {
+			return nil, err
+		}
+		if v, exists := getConstant(name); exists {
+			return NewLiteralFunction("constant "+name, v), nil
+		}
+		// Constants are resolved when the mapping is executed if they aren't
+		// yet known, which allows mappings to be parsed before a config has
+		// been loaded, e.g. when linting.
+		return ClosureFunction("constant "+name, func(ctx FunctionContext) (interface{}, error) {
+			v, exists := getConstant(name)
+			if !exists {
+				return nil, fmt.Errorf("constant %v is not defined", name)
+			}
+			return v, nil
+		}, nil), nil
+	},
+)
@@ -0,0 +1,246 @@
+package query
+
+import (
+	"fmt"
+	"math/rand"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+var fakeFirstNames = []string{
+	"Ada", "Alan", "Amara", "Ana", "Arjun", "Beatriz", "Ben", "Carlos", "Chen",
+	"Chloe", "Daniel", "Elena", "Emma", "Fatima", "Freya", "Grace", "Hana",
+	"Hugo", "Isla", "Ivan", "Jack", "James", "Julia", "Kai", "Kenji", "Lars",
+	"Leila", "Liam", "Lucia", "Maria", "Mateo", "Mei", "Mohammed", "Nadia",
+	"Noah", "Olivia", "Omar", "Priya", "Rosa", "Sam", "Sofia", "Tariq",
+	"Tom", "Uma", "Victor", "Wei", "Yara", "Yusuf", "Zoe",
+}
+
+var fakeLastNames = []string{
+	"Adams", "Ahmed", "Andersen", "Brown", "Chen", "Clarke", "Costa", "Dubois",
+	"Evans", "Fernandez", "Fischer", "Garcia", "Gupta", "Hansen", "Hughes",
+	"Ito", "Jackson", "Jones", "Kim", "Kowalski", "Lee", "Lopez", "Martin",
+	"Meyer", "Murphy", "Nguyen", "Novak", "Okafor", "Patel", "Perez", "Rossi",
+	"Sato", "Schmidt", "Silva", "Singh", "Smith", "Taylor", "Thomas", "Wang",
+	"Williams", "Wilson", "Wright", "Yamamoto", "Young", "Zhang",
+}
+
+var fakeWords = []string{
+	"alpha", "amber", "anchor", "apple", "arrow", "atlas", "autumn", "beacon",
+	"birch", "bridge", "canyon", "cedar", "cloud", "comet", "copper", "coral",
+	"delta", "desert", "ember", "falcon", "field", "forest", "garden", "glacier",
+	"harbor", "hollow", "island", "jade", "lantern", "maple", "meadow", "nova",
+	"ocean", "orbit", "pebble", "pine", "prairie", "quartz", "raven", "river",
+	"saffron", "signal", "silver", "spruce", "stone", "summit", "thunder",
+	"timber", "valley", "willow",
+}
+
+var fakeCities = []string{
+	"Amsterdam", "Auckland", "Austin", "Bangalore", "Barcelona", "Berlin",
+	"Bogotá", "Cairo", "Cape Town", "Chicago", "Dublin", "Edinburgh", "Helsinki",
+	"Istanbul", "Jakarta", "Lagos", "Lima", "Lisbon", "London", "Madrid",
+	"Manchester", "Melbourne", "Mexico City", "Montreal", "Mumbai", "Nairobi",
+	"Osaka", "Oslo", "Paris", "Prague", "Seoul", "Singapore", "Stockholm",
+	"São Paulo", "Sydney", "Tokyo", "Toronto", "Vancouver", "Vienna", "Warsaw",
+}
+
+var fakeCountries = []struct {
+	name string
+	code string
+}{
+	{"Argentina", "AR"}, {"Australia", "AU"}, {"Brazil", "BR"}, {"Canada", "CA"},
+	{"China", "CN"}, {"Egypt", "EG"}, {"Finland", "FI"}, {"France", "FR"},
+	{"Germany", "DE"}, {"India", "IN"}, {"Indonesia", "ID"}, {"Ireland", "IE"},
+	{"Italy", "IT"}, {"Japan", "JP"}, {"Kenya", "KE"}, {"Mexico", "MX"},
+	{"Netherlands", "NL"}, {"New Zealand", "NZ"}, {"Nigeria", "NG"},
+	{"Norway", "NO"}, {"Peru", "PE"}, {"Poland", "PL"}, {"Portugal", "PT"},
+	{"Singapore", "SG"}, {"South Africa", "ZA"}, {"South Korea", "KR"},
+	{"Spain", "ES"}, {"Sweden", "SE"}, {"Turkey", "TR"},
+	{"United Kingdom", "GB"}, {"United States", "US"},
+}
+
+var fakeStreetSuffixes = []string{
+	"Avenue", "Close", "Court", "Drive", "Lane", "Place", "Road", "Street", "Way",
+}
+
+var fakeCompanySuffixes = []string{
+	"Co", "Group", "Holdings", "Inc", "Labs", "Ltd", "Partners", "Systems",
+}
+
+// Reserved for documentation by RFC 2606, which prevents generated addresses
+// from belonging to real people.
+var fakeEmailDomains = []string{
+	"example.com", "example.net", "example.org",
+}
+
+var fakeTLDs = []string{"com", "io", "net", "org"}
+
+func fakePick(r *rand.Rand, s []string) string {
+	return s[r.Intn(len(s))]
+}
+
+func fakeFirstName(r *rand.Rand) string {
+	return fakePick(r, fakeFirstNames)
+}
+
+func fakeLastName(r *rand.Rand) string {
+	return fakePick(r, fakeLastNames)
+}
+
+func fakeUsername(r *rand.Rand) string {
+	return strings.ToLower(fakeFirstName(r)) + "_" + fakePick(r, fakeWords) + strconv.Itoa(r.Intn(100))
+}
+
+func fakeDomain(r *rand.Rand) string {
+	return fakePick(r, fakeWords) + fakePick(r, fakeWords) + "." + fakePick(r, fakeTLDs)
+}
+
+func fakeSentence(r *rand.Rand) string {
+	words := make([]string, 5+r.Intn(8))
+	for i := range words {
+		words[i] = fakePick(r, fakeWords)
+	}
+	words[0] = strings.ToUpper(words[0][:1]) + words[0][1:]
+	return strings.Join(words, " ") + "."
+}
+
+func fakeCreditCardNumber(r *rand.Rand) string {
+	digits := make([]byte, 16)
+	digits[0] = '4'
+	for i := 1; i < 15; i++ {
+		digits[i] = byte('0' + r.Intn(10))
+	}
+
+	// Calculate the check digit so that the number passes the Luhn checksum.
+	sum := 0
+	for i := 14; i >= 0; i-- {
+		d := int(digits[i] - '0')
+		if (14-i)%2 == 0 {
+			if d *= 2; d > 9 {
+				d -= 9
+			}
+		}
+		sum += d
+	}
+	digits[15] = byte('0' + (10-sum%10)%10)
+	return string(digits)
+}
+
+var fakeGenerators = map[string]func(r *rand.Rand) string{
+	"first_name": fakeFirstName,
+	"last_name":  fakeLastName,
+	"name": func(r *rand.Rand) string {
+		return fakeFirstName(r) + " " + fakeLastName(r)
+	},
+	"username": fakeUsername,
+	"email": func(r *rand.Rand) string {
+		return strings.ToLower(fakeFirstName(r)+"."+fakeLastName(r)) + strconv.Itoa(r.Intn(100)) + "@" + fakePick(r, fakeEmailDomains)
+	},
+	"domain": fakeDomain,
+	"url": func(r *rand.Rand) string {
+		return "https://www." + fakeDomain(r) + "/" + fakePick(r, fakeWords)
+	},
+	"ipv4": func(r *rand.Rand) string {
+		return fmt.Sprintf("%d.%d.%d.%d", 1+r.Intn(223), r.Intn(256), r.Intn(256), 1+r.Intn(254))
+	},
+	"ipv6": func(r *rand.Rand) string {
+		groups := make([]string, 8)
+		for i := range groups {
+			groups[i] = strconv.FormatInt(int64(r.Intn(0x10000)), 16)
+		}
+		return strings.Join(groups, ":")
+	},
+	"mac_address": func(r *rand.Rand) string {
+		b := make([]byte, 6)
+		_, _ = r.Read(b)
+		// Locally administered unicast addresses do not clash with vendors.
+		b[0] = (b[0] | 0x02) & 0xfe
+		return fmt.Sprintf("%02x:%02x:%02x:%02x:%02x:%02x", b[0], b[1], b[2], b[3], b[4], b[5])
+	},
+	"phone_number": func(r *rand.Rand) string {
+		// The 555-01XX range is reserved for fictional use.
+		return fmt.Sprintf("+1 %03d-555-01%02d", 201+r.Intn(779), r.Intn(100))
+	},
+	"street_address": func(r *rand.Rand) string {
+		name := fakePick(r, fakeWords)
+		return fmt.Sprintf("%d %s %s", 1+r.Intn(999), strings.ToUpper(name[:1])+name[1:], fakePick(r, fakeStreetSuffixes))
+	},
+	"city": func(r *rand.Rand) string {
+		return fakePick(r, fakeCities)
+	},
+	"country": func(r *rand.Rand) string {
+		return fakeCountries[r.Intn(len(fakeCountries))].name
+	},
+	"country_code": func(r *rand.Rand) string {
+		return fakeCountries[r.Intn(len(fakeCountries))].code
+	},
+	"company": func(r *rand.Rand) string {
+		return fakeLastName(r) + " " + fakePick(r, fakeCompanySuffixes)
+	},
+	"word":     func(r *rand.Rand) string { return fakePick(r, fakeWords) },
+	"sentence": fakeSentence,
+	"uuid": func(r *rand.Rand) string {
+		b := make([]byte, 16)
+		_, _ = r.Read(b)
+		b[6] = (b[6] & 0x0f) | 0x40
+		b[8] = (b[8] & 0x3f) | 0x80
+		return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:])
+	},
+	"credit_card_number": fakeCreditCardNumber,
+}
+
+func fakeKinds() []string {
+	kinds := make([]string, 0, len(fakeGenerators))
+	for k := range fakeGenerators {
+		kinds = append(kinds, k)
+	}
+	sort.Strings(kinds)
+	return kinds
+}
+
+var _ = registerFunction(
+	NewFunctionSpec(
+		FunctionCategoryGeneral, "fake",
+		"Generates a realistic but synthetic value of a given kind, which is useful for producing test data for load testing pipelines, or for replacing sensitive fields in order to anonymise a dataset. Generated email addresses use domains reserved for documentation, phone numbers use a range reserved for fictional use, and credit card numbers are randomly generated numbers that pass the Luhn checksum.\n\nAvailable kinds are: `"+strings.Join(fakeKinds(), "`, `")+"`.",
+		NewExampleSpec("",
+			`root.user.name = fake("name")
+root.user.email = fake("email")
+root.user.ip = fake("ipv4")`,
+		),
+		NewExampleSpec(
+			"An optional seed can be provided in order to produce the same sequence of values each time the mapping is initialised.",
+			`root.id = fake(kind: "uuid", seed: 10)`,
+		),
+	).Beta().MarkImpure().
+		Param(ParamString("kind", "The kind of value to generate.")).
+		Param(ParamInt64("seed", "An optional seed for the random number generator.").Optional()),
+	func(args *ParsedParams) (Function, error) {
+		kind, err := args.FieldString("kind")
+		if err != nil {
+			return nil, err
+		}
+		gen, exists := fakeGenerators[kind]
+		if !exists {
+			return nil, fmt.Errorf("unrecognised fake data kind '%v', expected one of: %v", kind, strings.Join(fakeKinds(), ", "))
+		}
+		seed, err := args.FieldOptionalInt64("seed")
+		if err != nil {
+			return nil, err
+		}
+		var r *rand.Rand
+		if seed != nil {
+			r = rand.New(rand.NewSource(*seed))
+		} else {
+			r = rand.New(rand.NewSource(time.Now().UnixNano()))
+		}
+		var randMut sync.Mutex
+		return ClosureFunction("function fake", func(ctx FunctionContext) (interface{}, error) {
+			randMut.Lock()
+			defer randMut.Unlock()
+			return gen(r), nil
+		}, nil), nil
+	},
+)
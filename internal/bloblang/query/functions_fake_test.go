@@ -0,0 +1,80 @@
+package query
+
+import (
+	"net"
+	"regexp"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFakeFunction(t *testing.T) {
+	spec, ok := AllFunctions.specs["fake"]
+	require.True(t, ok)
+
+	initFake := func(args map[string]interface{}) (Function, error) {
+		t.Helper()
+		parsedArgs, err := spec.Params.PopulateNamed(args)
+		require.NoError(t, err)
+		return AllFunctions.Init("fake", parsedArgs)
+	}
+
+	checks := map[string]func(s string) bool{
+		"email": regexp.MustCompile(`^[a-z]+\.[a-z]+\d*@example\.(com|net|org)$`).MatchString,
+		"ipv4": func(s string) bool {
+			ip := net.ParseIP(s)
+			return ip != nil && ip.To4() != nil
+		},
+		"ipv6": func(s string) bool {
+			ip := net.ParseIP(s)
+			return ip != nil && ip.To4() == nil
+		},
+		"mac_address": func(s string) bool {
+			mac, err := net.ParseMAC(s)
+			return err == nil && mac[0]&0x02 == 0x02 && mac[0]&0x01 == 0
+		},
+		"uuid":               regexp.MustCompile(`^[0-9a-f]{8}-[0-9a-f]{4}-4[0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}$`).MatchString,
+		"credit_card_number": luhnValid,
+		"name":               regexp.MustCompile(`^[A-Z][a-z]+ [A-Z][a-z]+$`).MatchString,
+	}
+
+	for _, kind := range fakeKinds() {
+		fn, err := initFake(map[string]interface{}{"kind": kind})
+		require.NoError(t, err, kind)
+		for i := 0; i < 20; i++ {
+			res, err := fn.Exec(FunctionContext{})
+			require.NoError(t, err, kind)
+			require.IsType(t, "", res, kind)
+			assert.NotEmpty(t, res, kind)
+			if check, exists := checks[kind]; exists {
+				assert.True(t, check(res.(string)), "%v: %v", kind, res)
+			}
+		}
+	}
+
+	_, err := initFake(map[string]interface{}{"kind": "nope"})
+	require.Error(t, err)
+}
+
+func TestFakeFunctionSeeded(t *testing.T) {
+	spec, ok := AllFunctions.specs["fake"]
+	require.True(t, ok)
+
+	generate := func() []interface{} {
+		parsedArgs, err := spec.Params.PopulateNamed(map[string]interface{}{"kind": "name", "seed": int64(5)})
+		require.NoError(t, err)
+		fn, err := AllFunctions.Init("fake", parsedArgs)
+		require.NoError(t, err)
+
+		var results []interface{}
+		for i := 0; i < 5; i++ {
+			res, err := fn.Exec(FunctionContext{})
+			require.NoError(t, err)
+			results = append(results, res)
+		}
+		return results
+	}
+
+	assert.Equal(t, generate(), generate())
+}
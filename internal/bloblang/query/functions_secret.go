@@ -0,0 +1,138 @@
+package query
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// SecretBackendFunc resolves the value of a secret from a path, where the
+// format of the path is specific to the backend.
+type SecretBackendFunc func(ctx context.Context, path string) (string, error)
+
+var secretBackends = struct {
+	mut      sync.RWMutex
+	backends map[string]SecretBackendFunc
+}{
+	backends: map[string]SecretBackendFunc{
+		"env": func(_ context.Context, path string) (string, error) {
+			v, exists := os.LookupEnv(path)
+			if !exists {
+				return "", fmt.Errorf("environment variable %v is not set", path)
+			}
+			return v, nil
+		},
+		"file": func(_ context.Context, path string) (string, error) {
+			b, err := ioutil.ReadFile(path)
+			if err != nil {
+				return "", err
+			}
+			return strings.TrimRight(string(b), "\r\n"), nil
+		},
+	},
+}
+
+// RegisterSecretBackend adds a backend that the secret function is able to
+// resolve secrets from, identified by a scheme that prefixes secret paths.
+func RegisterSecretBackend(scheme string, fn SecretBackendFunc) error {
+	secretBackends.mut.Lock()
+	defer secretBackends.mut.Unlock()
+
+	if _, exists := secretBackends.backends[scheme]; exists {
+		return fmt.Errorf("secret backend %v already registered", scheme)
+	}
+	secretBackends.backends[scheme] = fn
+	return nil
+}
+
+func secretBackendSchemes() []string {
+	secretBackends.mut.RLock()
+	defer secretBackends.mut.RUnlock()
+
+	schemes := make([]string, 0, len(secretBackends.backends))
+	for k := range secretBackends.backends {
+		schemes = append(schemes, k)
+	}
+	sort.Strings(schemes)
+	return schemes
+}
+
+// The maximum time to wait for a secret to be resolved by a backend.
+const secretResolveTimeout = time.Second * 30
+
+func resolveSecret(ref string) (string, error) {
+	schemeEnd := strings.Index(ref, ":")
+	if schemeEnd <= 0 {
+		return "", fmt.Errorf("secret reference must be prefixed with a backend, e.g. env:%v", ref)
+	}
+	scheme, path := ref[:schemeEnd], ref[schemeEnd+1:]
+
+	var key string
+	if keyStart := strings.LastIndex(path, "#"); keyStart != -1 {
+		path, key = path[:keyStart], path[keyStart+1:]
+	}
+
+	secretBackends.mut.RLock()
+	backend, exists := secretBackends.backends[scheme]
+	secretBackends.mut.RUnlock()
+	if !exists {
+		return "", fmt.Errorf("secret backend %v not recognised, expected one of: %v", scheme, strings.Join(secretBackendSchemes(), ", "))
+	}
+
+	ctx, done := context.WithTimeout(context.Background(), secretResolveTimeout)
+	defer done()
+
+	value, err := backend(ctx, path)
+	if err != nil {
+		return "", fmt.Errorf("failed to resolve secret from %v backend: %w", scheme, err)
+	}
+	if key == "" {
+		return value, nil
+	}
+
+	var obj map[string]interface{}
+	if err := json.Unmarshal([]byte(value), &obj); err != nil {
+		return "", errors.New("failed to extract secret key: secret value is not a JSON object")
+	}
+	keyValue, exists := obj[key]
+	if !exists {
+		return "", fmt.Errorf("failed to extract secret key: key %v does not exist", key)
+	}
+	if s, isStr := keyValue.(string); isStr {
+		return s, nil
+	}
+	b, _ := json.Marshal(keyValue)
+	return string(b), nil
+}
+
+var _ = registerFunction(
+	NewFunctionSpec(
+		FunctionCategoryEnvironment, "secret",
+		"Returns the value of a secret, which is resolved once when the mapping is initialised. Secrets are referenced with a path prefixed by the backend that they should be resolved from, and the mapping fails to initialise if the secret cannot be resolved. Since secrets are resolved at runtime their values never appear in config files or the output of commands such as `benthos echo`.\n\nThe available backends are:\n\n- `env`: The value of an environment variable, e.g. `env:DB_PASSWORD`.\n- `file`: The contents of a file with trailing newlines removed, e.g. `file:/run/secrets/db_password`.\n- `vault`: A secret from [HashiCorp Vault](https://www.vaultproject.io/), configured with the environment variables `VAULT_ADDR`, `VAULT_TOKEN` and optionally `VAULT_NAMESPACE`, e.g. `vault:secret/data/db`.\n- `aws_secrets_manager`: A secret from AWS Secrets Manager using the default AWS credentials chain, e.g. `aws_secrets_manager:prod/db`.\n\nWhen a secret is a JSON object a specific key can be selected by adding it to the end of the path following a `#`, e.g. `vault:secret/data/db#password`.",
+		NewExampleSpec("",
+			`root.user = this.user
+root.password = secret("env:DB_PASSWORD")`,
+			`{"user":"admin"}`,
+			`{"password":"hunter2","user":"admin"}`,
+		),
+	).Beta().MarkImpure().
+		Param(ParamString("path", "The path of the secret, prefixed by the backend to resolve it from.")),
+	func(args *ParsedParams) (Function, error) {
+		path, err := args.FieldString("path")
+		if err != nil {
+			return nil, err
+		}
+		value, err := resolveSecret(path)
+		if err != nil {
+			return nil, err
+		}
+		return NewLiteralFunction("secret "+path, value), nil
+	},
+)
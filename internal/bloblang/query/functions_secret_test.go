@@ -0,0 +1,109 @@
+package query
+
+import (
+	"context"
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSecretFunction(t *testing.T) {
+	dir, err := ioutil.TempDir("", "benthos_bloblang_secret")
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		os.RemoveAll(dir)
+	})
+
+	plainPath := filepath.Join(dir, "plain")
+	require.NoError(t, ioutil.WriteFile(plainPath, []byte("hunter2\n"), 0600))
+
+	objPath := filepath.Join(dir, "obj.json")
+	require.NoError(t, ioutil.WriteFile(objPath, []byte(`{"user":"admin","password":"hunter2","port":5432}`), 0600))
+
+	envKey := "BENTHOS_TEST_BLOBLANG_SECRET"
+	os.Setenv(envKey, "from env")
+	t.Cleanup(func() {
+		os.Unsetenv(envKey)
+	})
+
+	require.NoError(t, RegisterSecretBackend("benthos_test", func(_ context.Context, path string) (string, error) {
+		if path == "fail" {
+			return "", errors.New("nope")
+		}
+		return "test " + path, nil
+	}))
+	require.Error(t, RegisterSecretBackend("benthos_test", nil))
+
+	tests := map[string]struct {
+		path   string
+		output string
+		err    string
+	}{
+		"env": {
+			path:   "env:" + envKey,
+			output: "from env",
+		},
+		"env missing": {
+			path: "env:BENTHOS_TEST_BLOBLANG_SECRET_MISSING",
+			err:  "failed to resolve secret from env backend: environment variable BENTHOS_TEST_BLOBLANG_SECRET_MISSING is not set",
+		},
+		"file": {
+			path:   "file:" + plainPath,
+			output: "hunter2",
+		},
+		"file key": {
+			path:   "file:" + objPath + "#password",
+			output: "hunter2",
+		},
+		"file non string key": {
+			path:   "file:" + objPath + "#port",
+			output: "5432",
+		},
+		"file missing key": {
+			path: "file:" + objPath + "#nope",
+			err:  "failed to extract secret key: key nope does not exist",
+		},
+		"file key not object": {
+			path: "file:" + plainPath + "#password",
+			err:  "failed to extract secret key: secret value is not a JSON object",
+		},
+		"custom backend": {
+			path:   "benthos_test:foo",
+			output: "test foo",
+		},
+		"custom backend error": {
+			path: "benthos_test:fail",
+			err:  "failed to resolve secret from benthos_test backend: nope",
+		},
+		"no backend": {
+			path: "foo",
+			err:  "secret reference must be prefixed with a backend, e.g. env:foo",
+		},
+		"unknown backend": {
+			path: "nope:foo",
+			err:  "secret backend nope not recognised",
+		},
+	}
+
+	for name, test := range tests {
+		test := test
+		t.Run(name, func(t *testing.T) {
+			fn, err := InitFunctionHelper("secret", test.path)
+			if test.err != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), test.err)
+				return
+			}
+			require.NoError(t, err)
+
+			res, err := fn.Exec(FunctionContext{})
+			require.NoError(t, err)
+			assert.Equal(t, test.output, res)
+		})
+	}
+}
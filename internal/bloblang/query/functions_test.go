@@ -2,9 +2,13 @@ package query
 
 import (
 	"fmt"
+	"io/ioutil"
 	"os"
+	"path/filepath"
+	"strings"
 	"sync"
 	"testing"
+	"time"
 
 	"github.com/Jeffail/benthos/v3/lib/message"
 	"github.com/stretchr/testify/assert"
@@ -112,6 +116,48 @@ func TestFunctions(t *testing.T) {
 				}},
 			},
 		},
+		"check error function": {
+			input:  mustFunc("error"),
+			output: "it broke",
+			messages: []easyMsg{
+				{content: "", meta: map[string]string{
+					"benthos_processing_failed": "it broke",
+				}},
+			},
+		},
+		"check error function structured": {
+			input: mustFunc("error", true),
+			output: map[string]interface{}{
+				"message": "it broke",
+				"label":   "foo_proc",
+				"path":    "foo_proc",
+			},
+			messages: []easyMsg{
+				{content: "", meta: map[string]string{
+					"benthos_processing_failed":       "it broke",
+					"benthos_processing_failed_label": "foo_proc",
+					"benthos_processing_failed_path":  "foo_proc",
+				}},
+			},
+		},
+		"check error function structured no label": {
+			input: mustFunc("error", true),
+			output: map[string]interface{}{
+				"message": "it broke",
+				"label":   nil,
+				"path":    nil,
+			},
+			messages: []easyMsg{
+				{content: "", meta: map[string]string{
+					"benthos_processing_failed": "it broke",
+				}},
+			},
+		},
+		"check error function structured not failed": {
+			input:    mustFunc("error", true),
+			output:   nil,
+			messages: []easyMsg{{content: ""}},
+		},
 	}
 
 	for name, test := range tests {
@@ -212,6 +258,52 @@ func TestFunctionTargets(t *testing.T) {
 	}
 }
 
+func TestFieldFunctionPaths(t *testing.T) {
+	value := map[string]interface{}{
+		"foo": map[string]interface{}{
+			"bar": []interface{}{
+				map[string]interface{}{"baz": "first"},
+				map[string]interface{}{"baz": "second"},
+			},
+			"5": "five",
+		},
+	}
+
+	tests := map[string]interface{}{
+		"foo.bar.1.baz": "second",
+		"foo.bar.0":     map[string]interface{}{"baz": "first"},
+		"foo.bar.00":    map[string]interface{}{"baz": "first"},
+		"foo.bar.*.baz": []interface{}{"first", "second"},
+		"foo.bar.2.baz": nil,
+		"foo.bar.-1":    nil,
+		"foo.bar.baz":   nil,
+		"foo.5":         "five",
+		"foo.nope":      nil,
+		"foo.5.nope":    nil,
+		"foo~1bar":      nil,
+	}
+
+	for path, exp := range tests {
+		path, exp := path, exp
+		t.Run(path, func(t *testing.T) {
+			res, err := NewFieldFunction(path).Exec(FunctionContext{
+				MsgBatch: message.New(nil),
+			}.WithValue(value))
+			require.NoError(t, err)
+			assert.Equal(t, exp, res)
+
+			getFn, err := NewGetMethod(NewVarFunction("foo"), path)
+			require.NoError(t, err)
+			res, err = getFn.Exec(FunctionContext{
+				Vars:     map[string]interface{}{"foo": value},
+				MsgBatch: message.New(nil),
+			})
+			require.NoError(t, err)
+			assert.Equal(t, exp, res)
+		})
+	}
+}
+
 func TestNanoidFunction(t *testing.T) {
 	e, err := InitFunctionHelper("nanoid")
 	require.Nil(t, err)
@@ -254,6 +346,86 @@ func TestEnvFunction(t *testing.T) {
 	assert.Equal(t, "foobar", res)
 }
 
+func TestEnvFunctionDefaultAndRequired(t *testing.T) {
+	key := "BENTHOS_TEST_BLOBLANG_FUNCTION_MISSING"
+	os.Unsetenv(key)
+
+	initEnv := func(args map[string]interface{}) (Function, error) {
+		t.Helper()
+		spec, ok := AllFunctions.specs["env"]
+		require.True(t, ok)
+		parsedArgs, err := spec.Params.PopulateNamed(args)
+		require.NoError(t, err)
+		return AllFunctions.Init("env", parsedArgs)
+	}
+
+	e, err := initEnv(map[string]interface{}{"name": key, "default": "fallback"})
+	require.NoError(t, err)
+	res, err := e.Exec(FunctionContext{})
+	require.NoError(t, err)
+	assert.Equal(t, "fallback", res)
+
+	_, err = initEnv(map[string]interface{}{"name": key, "required": true})
+	require.EqualError(t, err, "required environment variable BENTHOS_TEST_BLOBLANG_FUNCTION_MISSING is not set")
+
+	_, err = initEnv(map[string]interface{}{"name": key, "default": "fallback", "required": true})
+	require.Error(t, err)
+
+	os.Setenv(key, "")
+	t.Cleanup(func() {
+		os.Unsetenv(key)
+	})
+
+	e, err = initEnv(map[string]interface{}{"name": key, "default": "fallback"})
+	require.NoError(t, err)
+	res, err = e.Exec(FunctionContext{})
+	require.NoError(t, err)
+	assert.Equal(t, "", res)
+
+	e, err = initEnv(map[string]interface{}{"name": key, "required": true})
+	require.NoError(t, err)
+	res, err = e.Exec(FunctionContext{})
+	require.NoError(t, err)
+	assert.Equal(t, "", res)
+}
+
+func TestEnvFunctionLazyDefault(t *testing.T) {
+	key := "BENTHOS_TEST_BLOBLANG_FUNCTION_LAZY"
+	os.Unsetenv(key)
+
+	initEnv := func(args map[string]interface{}) Function {
+		t.Helper()
+		spec, ok := AllFunctions.specs["env"]
+		require.True(t, ok)
+		parsedArgs, err := spec.Params.PopulateNamed(args)
+		require.NoError(t, err)
+		fn, err := AllFunctions.Init("env", parsedArgs)
+		require.NoError(t, err)
+		return fn
+	}
+
+	throwFn, err := InitFunctionHelper("throw", "default was evaluated")
+	require.NoError(t, err)
+
+	e := initEnv(map[string]interface{}{"name": key, "default": NewFieldFunction("fallback")})
+	res, err := e.Exec(FunctionContext{}.WithValue(map[string]interface{}{"fallback": "from this"}))
+	require.NoError(t, err)
+	assert.Equal(t, "from this", res)
+
+	_, err = e.Exec(FunctionContext{}.WithValue(map[string]interface{}{"fallback": 10}))
+	require.EqualError(t, err, "failed to extract input arg default: wrong argument type, expected string, got number")
+
+	os.Setenv(key, "foobar")
+	t.Cleanup(func() {
+		os.Unsetenv(key)
+	})
+
+	e = initEnv(map[string]interface{}{"name": key, "default": throwFn})
+	res, err = e.Exec(FunctionContext{})
+	require.NoError(t, err)
+	assert.Equal(t, "foobar", res)
+}
+
 func TestRandomInt(t *testing.T) {
 	e, err := InitFunctionHelper("random_int")
 	require.Nil(t, err)
@@ -377,3 +549,206 @@ func TestRandomIntDynamicParallel(t *testing.T) {
 	close(startChan)
 	wg.Wait()
 }
+
+func TestFileFunctionCacheTTL(t *testing.T) {
+	dir, err := ioutil.TempDir("", "benthos_bloblang_file")
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		os.RemoveAll(dir)
+	})
+
+	path := filepath.Join(dir, "lookup.txt")
+	require.NoError(t, ioutil.WriteFile(path, []byte("first"), 0644))
+
+	spec, ok := AllFunctions.specs["file"]
+	require.True(t, ok)
+
+	initFile := func(ttl string) Function {
+		t.Helper()
+		parsedArgs, err := spec.Params.PopulateNamed(map[string]interface{}{
+			"path":      path,
+			"cache_ttl": ttl,
+		})
+		require.NoError(t, err)
+		fn, err := AllFunctions.Init("file", parsedArgs)
+		require.NoError(t, err)
+		return fn
+	}
+
+	cached := initFile("1h")
+	refreshed := initFile("1ns")
+
+	require.NoError(t, ioutil.WriteFile(path, []byte("second"), 0644))
+
+	res, err := cached.Exec(FunctionContext{})
+	require.NoError(t, err)
+	assert.Equal(t, []byte("first"), res)
+
+	res, err = refreshed.Exec(FunctionContext{})
+	require.NoError(t, err)
+	assert.Equal(t, []byte("second"), res)
+
+	parsedArgs, err := spec.Params.PopulateNamed(map[string]interface{}{
+		"path":      path,
+		"cache_ttl": "not a duration",
+	})
+	require.NoError(t, err)
+	_, err = AllFunctions.Init("file", parsedArgs)
+	require.Error(t, err)
+}
+
+func TestFileFunctionWatch(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "lookup.txt")
+	require.NoError(t, ioutil.WriteFile(path, []byte("first"), 0644))
+
+	spec, ok := AllFunctions.specs["file"]
+	require.True(t, ok)
+
+	parsedArgs, err := spec.Params.PopulateNamed(map[string]interface{}{
+		"path":  path,
+		"watch": true,
+	})
+	require.NoError(t, err)
+	fn, err := AllFunctions.Init("file", parsedArgs)
+	require.NoError(t, err)
+
+	res, err := fn.Exec(FunctionContext{})
+	require.NoError(t, err)
+	assert.Equal(t, []byte("first"), res)
+
+	require.NoError(t, ioutil.WriteFile(path, []byte("second"), 0644))
+	assert.Eventually(t, func() bool {
+		res, err := fn.Exec(FunctionContext{})
+		return err == nil && string(res.([]byte)) == "second"
+	}, time.Second*5, time.Millisecond*50)
+}
+
+func TestFileCacheLimit(t *testing.T) {
+	dir := t.TempDir()
+	c := newFileCache(2)
+
+	var paths []string
+	for _, name := range []string{"a", "b", "c"} {
+		path := filepath.Join(dir, name)
+		require.NoError(t, ioutil.WriteFile(path, []byte(name), 0644))
+		paths = append(paths, path)
+	}
+
+	for _, path := range paths {
+		_, err := c.read(path, time.Hour, false)
+		require.NoError(t, err)
+	}
+	assert.Len(t, c.files, 2)
+	assert.NotContains(t, c.files, paths[0])
+
+	// Files that can no longer be read are dropped from the cache.
+	require.NoError(t, os.Remove(paths[2]))
+	_, err := c.read(paths[2], time.Nanosecond, false)
+	require.Error(t, err)
+	assert.Len(t, c.files, 1)
+}
+
+func TestProcessInfoFunctions(t *testing.T) {
+	e, err := InitFunctionHelper("pid")
+	require.NoError(t, err)
+
+	res, err := e.Exec(FunctionContext{})
+	require.NoError(t, err)
+	assert.Equal(t, int64(os.Getpid()), res)
+
+	e, err = InitFunctionHelper("instance_id")
+	require.NoError(t, err)
+
+	first, err := e.Exec(FunctionContext{})
+	require.NoError(t, err)
+	require.IsType(t, "", first)
+	assert.Len(t, first, 36)
+
+	second, err := e.Exec(FunctionContext{})
+	require.NoError(t, err)
+	assert.Equal(t, first, second)
+}
+
+func TestNowFunctionFormats(t *testing.T) {
+	spec, ok := AllFunctions.specs["now"]
+	require.True(t, ok)
+
+	initNow := func(args map[string]interface{}) Function {
+		t.Helper()
+		parsedArgs, err := spec.Params.PopulateNamed(args)
+		require.NoError(t, err)
+		fn, err := AllFunctions.Init("now", parsedArgs)
+		require.NoError(t, err)
+		return fn
+	}
+
+	before := time.Now()
+
+	res, err := initNow(map[string]interface{}{}).Exec(FunctionContext{})
+	require.NoError(t, err)
+	ts, err := time.Parse(time.RFC3339Nano, res.(string))
+	require.NoError(t, err)
+	assert.False(t, ts.Before(before))
+
+	res, err = initNow(map[string]interface{}{"format": "2006-01-02T15:04:05Z07:00", "tz": "UTC"}).Exec(FunctionContext{})
+	require.NoError(t, err)
+	assert.True(t, strings.HasSuffix(res.(string), "Z"), res)
+
+	res, err = initNow(map[string]interface{}{"format": "unix"}).Exec(FunctionContext{})
+	require.NoError(t, err)
+	assert.GreaterOrEqual(t, res.(int64), before.Unix())
+
+	res, err = initNow(map[string]interface{}{"format": "unix_milli"}).Exec(FunctionContext{})
+	require.NoError(t, err)
+	assert.GreaterOrEqual(t, res.(int64), before.UnixNano()/int64(time.Millisecond))
+
+	res, err = initNow(map[string]interface{}{"format": "unix_nano"}).Exec(FunctionContext{})
+	require.NoError(t, err)
+	assert.GreaterOrEqual(t, res.(int64), before.UnixNano())
+
+	parsedArgs, err := spec.Params.PopulateNamed(map[string]interface{}{"tz": "Not/AZone"})
+	require.NoError(t, err)
+	_, err = AllFunctions.Init("now", parsedArgs)
+	require.Error(t, err)
+}
+
+func TestCounterFunction(t *testing.T) {
+	name := "benthos_test_counter_function"
+
+	a, err := InitFunctionHelper("counter", name)
+	require.NoError(t, err)
+	b, err := InitFunctionHelper("counter", name)
+	require.NoError(t, err)
+	count, err := InitFunctionHelper("count", name)
+	require.NoError(t, err)
+	other, err := InitFunctionHelper("counter", name+"_other")
+	require.NoError(t, err)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 10; j++ {
+				_, err := a.Exec(FunctionContext{})
+				require.NoError(t, err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	// Counters of the same name are shared between mappings.
+	res, err := b.Exec(FunctionContext{})
+	require.NoError(t, err)
+	assert.Equal(t, int64(101), res)
+
+	// But not with the count function.
+	res, err = count.Exec(FunctionContext{})
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), res)
+
+	res, err = other.Exec(FunctionContext{})
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), res)
+}
@@ -24,8 +24,8 @@ func NewMethodSet() *MethodSet {
 // a constructor to be called for each instantiation of the method, and
 // information regarding the arguments of the method.
 func (m *MethodSet) Add(spec MethodSpec, ctor MethodCtor) error {
-	if !nameRegexp.MatchString(spec.Name) {
-		return fmt.Errorf("method name '%v' does not match the required regular expression /%v/", spec.Name, nameRegexpRaw)
+	if !namespacedNameRegexp.MatchString(spec.Name) {
+		return fmt.Errorf("method name '%v' does not match the required regular expression /%v/", spec.Name, namespacedNameRegexpRaw)
 	}
 	if _, exists := m.constructors[spec.Name]; exists {
 		return fmt.Errorf("conflicting method name: %v", spec.Name)
@@ -38,6 +38,27 @@ func (m *MethodSet) Add(spec MethodSpec, ctor MethodCtor) error {
 	return nil
 }
 
+// AddAlias adds a deprecated alias to this set that forwards to an existing
+// method, which allows methods to be renamed without breaking mappings that
+// use the old name. Mappings that use the alias are warned by the linter.
+func (m *MethodSet) AddAlias(alias, target string) error {
+	spec, exists := m.specs[target]
+	if !exists {
+		return fmt.Errorf("method '%v' cannot be aliased as it does not exist", target)
+	}
+	if spec.AliasOf != "" {
+		target = spec.AliasOf
+	}
+
+	spec.Name = alias
+	spec.Status = StatusDeprecated
+	spec.Description = fmt.Sprintf("This method is a deprecated alias of [`%v`](#%v).", target, target)
+	spec.Examples = nil
+	spec.Categories = []MethodCatSpec{{Category: MethodCategoryDeprecated}}
+	spec.AliasOf = target
+	return m.Add(spec, m.constructors[target])
+}
+
 // Docs returns a slice of method specs, which document each method.
 func (m *MethodSet) Docs() []MethodSpec {
 	specSlice := make([]MethodSpec, 0, len(m.specs))
@@ -69,6 +90,12 @@ func (m *MethodSet) Params(name string) (Params, error) {
 	return spec.Params, nil
 }
 
+// Spec attempts to obtain the spec (name and documentation) of a method.
+func (m *MethodSet) Spec(name string) (MethodSpec, bool) {
+	spec, exists := m.specs[name]
+	return spec, exists
+}
+
 // Init attempts to initialize a method of the set by name from a target
 // function and zero or more arguments.
 func (m *MethodSet) Init(name string, target Function, args *ParsedParams) (Function, error) {
@@ -94,15 +121,32 @@ func (m *MethodSet) Without(methods ...string) *MethodSet {
 		}
 	}
 
-	specs := map[string]MethodSpec{}
+	// Aliases are removed along with the methods that they forward to.
 	for _, v := range m.specs {
-		if _, exists := excludeMap[v.Name]; !exists {
-			specs[v.Name] = v
+		if _, exists := excludeMap[v.AliasOf]; v.AliasOf != "" && exists {
+			delete(constructors, v.Name)
 		}
 	}
+
+	specs := map[string]MethodSpec{}
+	for k := range constructors {
+		specs[k] = m.specs[k]
+	}
 	return &MethodSet{constructors, specs}
 }
 
+// OnlyPure creates a clone of the method set that can be mutated in isolation,
+// where all impure methods are removed.
+func (m *MethodSet) OnlyPure() *MethodSet {
+	var excludes []string
+	for _, v := range m.specs {
+		if v.Impure {
+			excludes = append(excludes, v.Name)
+		}
+	}
+	return m.Without(excludes...)
+}
+
 //------------------------------------------------------------------------------
 
 // AllMethods is a set containing every single method declared by this package,
@@ -32,20 +32,63 @@ func TestMethodSetWithout(t *testing.T) {
 	assert.NoError(t, err)
 }
 
+func TestMethodSetOnlyPure(t *testing.T) {
+	setOne := AllMethods
+	setTwo := setOne.OnlyPure()
+
+	assert.Contains(t, setOne.List(), "geoip")
+	assert.NotContains(t, setTwo.List(), "geoip")
+
+	assert.Contains(t, setTwo.List(), "uppercase")
+}
+
+func TestMethodSetAlias(t *testing.T) {
+	setOne := AllMethods.Without()
+	require.NoError(t, setOne.AddAlias("upper", "uppercase"))
+	require.NoError(t, setOne.AddAlias("shout", "upper"))
+
+	assert.EqualError(t, setOne.AddAlias("upper", "lowercase"), "conflicting method name: upper")
+	assert.EqualError(t, setOne.AddAlias("nope", "does_not_exist"), "method 'does_not_exist' cannot be aliased as it does not exist")
+	assert.NotContains(t, AllMethods.List(), "upper")
+
+	for _, name := range []string{"upper", "shout"} {
+		spec, exists := setOne.Spec(name)
+		require.True(t, exists)
+		assert.Equal(t, StatusDeprecated, spec.Status)
+		assert.Equal(t, "uppercase", spec.AliasOf)
+
+		fn, err := setOne.Init(name, NewLiteralFunction("", "foo"), nil)
+		require.NoError(t, err)
+
+		v, err := fn.Exec(FunctionContext{})
+		require.NoError(t, err)
+		assert.Equal(t, "FOO", v)
+	}
+
+	setTwo := setOne.Without("uppercase")
+	assert.NotContains(t, setTwo.List(), "upper")
+	assert.NotContains(t, setTwo.List(), "shout")
+	assert.Contains(t, setTwo.List(), "lowercase")
+}
+
 func TestMethodBadName(t *testing.T) {
 	testCases := map[string]string{
-		"!no":         "method name '!no' does not match the required regular expression /^[a-z0-9]+(_[a-z0-9]+)*$/",
-		"foo__bar":    "method name 'foo__bar' does not match the required regular expression /^[a-z0-9]+(_[a-z0-9]+)*$/",
-		"-foo-bar":    "method name '-foo-bar' does not match the required regular expression /^[a-z0-9]+(_[a-z0-9]+)*$/",
-		"foo-bar-":    "method name 'foo-bar-' does not match the required regular expression /^[a-z0-9]+(_[a-z0-9]+)*$/",
-		"":            "method name '' does not match the required regular expression /^[a-z0-9]+(_[a-z0-9]+)*$/",
-		"foo-bar":     "method name 'foo-bar' does not match the required regular expression /^[a-z0-9]+(_[a-z0-9]+)*$/",
-		"foo-bar_baz": "method name 'foo-bar_baz' does not match the required regular expression /^[a-z0-9]+(_[a-z0-9]+)*$/",
-		"FOO":         "method name 'FOO' does not match the required regular expression /^[a-z0-9]+(_[a-z0-9]+)*$/",
-		"foobarbaz":   "",
-		"foobarbaz89": "",
-		"foo_bar_baz": "",
-		"fo1_ba2_ba3": "",
+		"!no":          "method name '!no' does not match the required regular expression /^([a-z0-9]+(_[a-z0-9]+)*\\.)?[a-z0-9]+(_[a-z0-9]+)*$/",
+		"foo__bar":     "method name 'foo__bar' does not match the required regular expression /^([a-z0-9]+(_[a-z0-9]+)*\\.)?[a-z0-9]+(_[a-z0-9]+)*$/",
+		"-foo-bar":     "method name '-foo-bar' does not match the required regular expression /^([a-z0-9]+(_[a-z0-9]+)*\\.)?[a-z0-9]+(_[a-z0-9]+)*$/",
+		"foo-bar-":     "method name 'foo-bar-' does not match the required regular expression /^([a-z0-9]+(_[a-z0-9]+)*\\.)?[a-z0-9]+(_[a-z0-9]+)*$/",
+		"":             "method name '' does not match the required regular expression /^([a-z0-9]+(_[a-z0-9]+)*\\.)?[a-z0-9]+(_[a-z0-9]+)*$/",
+		"foo-bar":      "method name 'foo-bar' does not match the required regular expression /^([a-z0-9]+(_[a-z0-9]+)*\\.)?[a-z0-9]+(_[a-z0-9]+)*$/",
+		"foo-bar_baz":  "method name 'foo-bar_baz' does not match the required regular expression /^([a-z0-9]+(_[a-z0-9]+)*\\.)?[a-z0-9]+(_[a-z0-9]+)*$/",
+		"FOO":          "method name 'FOO' does not match the required regular expression /^([a-z0-9]+(_[a-z0-9]+)*\\.)?[a-z0-9]+(_[a-z0-9]+)*$/",
+		"foobarbaz":    "",
+		"foobarbaz89":  "",
+		"foo_bar_baz":  "",
+		"fo1_ba2_ba3":  "",
+		"acme.foo":     "",
+		"acme.foo_bar": "",
+		"acme.":        "method name 'acme.' does not match the required regular expression /^([a-z0-9]+(_[a-z0-9]+)*\\.)?[a-z0-9]+(_[a-z0-9]+)*$/",
+		"a.b.c":        "method name 'a.b.c' does not match the required regular expression /^([a-z0-9]+(_[a-z0-9]+)*\\.)?[a-z0-9]+(_[a-z0-9]+)*$/",
 	}
 
 	for k, v := range testCases {
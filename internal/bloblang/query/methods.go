@@ -119,7 +119,7 @@ func boolMethod(target Function, args ...interface{}) (Function, error) {
 var _ = registerOldParamsMethod(
 	NewMethodSpec(
 		"catch",
-		"If the result of a target query fails (due to incorrect types, failed parsing, etc) the argument is returned instead.",
+		"If the result of a target query fails (due to incorrect types, failed parsing, etc) the argument is returned instead. The argument is only executed when the target query fails, and therefore expensive fallbacks do not impact the successful path.",
 		NewExampleSpec("",
 			`root.doc.id = this.thing.id.string().catch(uuid_v4())`,
 		),
@@ -254,8 +254,9 @@ var _ = registerOldParamsMethod(
 )
 
 type getMethod struct {
-	fn   Function
-	path []string
+	fn       Function
+	path     []string
+	resolved resolvedPath
 }
 
 func (g *getMethod) Annotation() string {
@@ -267,7 +268,7 @@ func (g *getMethod) Exec(ctx FunctionContext) (interface{}, error) {
 	if err != nil {
 		return nil, err
 	}
-	return gabs.Wrap(v).S(g.path...).Data(), nil
+	return g.resolved.get(v), nil
 }
 
 func (g *getMethod) QueryTargets(ctx TargetsContext) (TargetsContext, []TargetPath) {
@@ -296,15 +297,17 @@ func getMethodCtor(target Function, args ...interface{}) (Function, error) {
 		newPath := append([]string{}, t.path...)
 		newPath = append(newPath, path...)
 		return &getMethod{
-			fn:   t.fn,
-			path: newPath,
+			fn:       t.fn,
+			path:     newPath,
+			resolved: resolvePath(newPath),
 		}, nil
 	case *fieldFunction:
 		return t.expand(path...), nil
 	}
 	return &getMethod{
-		fn:   target,
-		path: path,
+		fn:       target,
+		path:     path,
+		resolved: resolvePath(path),
 	}, nil
 }
 
@@ -354,6 +357,11 @@ type notMethod struct {
 
 // Not returns a logical NOT of a child function.
 func Not(fn Function) Function {
+	if lit, isLit := fn.(*Literal); isLit {
+		if b, isBool := lit.Value.(bool); isBool {
+			return NewLiteralFunction("", !b)
+		}
+	}
 	return &notMethod{
 		fn: fn,
 	}
@@ -380,7 +388,7 @@ func (n *notMethod) QueryTargets(ctx TargetsContext) (TargetsContext, []TargetPa
 }
 
 func notMethodCtor(target Function, _ ...interface{}) (Function, error) {
-	return &notMethod{fn: target}, nil
+	return Not(target), nil
 }
 
 //------------------------------------------------------------------------------
@@ -455,9 +463,61 @@ func numberCoerceMethod(target Function, args ...interface{}) (Function, error)
 
 //------------------------------------------------------------------------------
 
+var _ = registerSimpleMethod(
+	NewMethodSpec(
+		"int_big", "",
+	).InCategory(
+		MethodCategoryCoercion,
+		"Attempt to parse a value into an arbitrary precision integer. Unlike regular numbers, big integers are never degraded into floating point values and therefore integers larger than 64 bits, such as 128-bit identifiers, survive arithmetic and serialization without losing precision. Arithmetic between a big integer and another integer results in a big integer, whereas arithmetic involving a floating point value results in a floating point value. Strings may be prefixed with `0x`, `0o` or `0b` in order to parse hexadecimal, octal or binary values respectively.",
+		NewExampleSpec("",
+			`root.next_id = this.id.int_big() + 1`,
+			`{"id":340282366920938463463374607431768211455}`,
+			`{"next_id":340282366920938463463374607431768211456}`,
+		),
+		NewExampleSpec("",
+			`root.value = this.value.int_big()`,
+			`{"value":"0x1ffffffffffffffff"}`,
+			`{"value":36893488147419103231}`,
+		),
+	),
+	func(*ParsedParams) (simpleMethod, error) {
+		return func(v interface{}, ctx FunctionContext) (interface{}, error) {
+			return IToBigInt(v)
+		}, nil
+	},
+)
+
+//------------------------------------------------------------------------------
+
+var _ = registerSimpleMethod(
+	NewMethodSpec(
+		"decimal", "",
+	).InCategory(
+		MethodCategoryCoercion,
+		"Attempt to parse a value into an exact decimal number, which is useful for manipulating values such as currency amounts where floating point rounding errors are unacceptable. Arithmetic between a decimal and any other number results in a decimal, where floating point values are converted using their shortest representation (the literal `0.1` becomes exactly `0.1`). Addition, subtraction, multiplication and modulo are always exact, whereas division results that cannot be represented exactly are rounded to 16 decimal places. Decimals are serialized as numbers with their decimal places preserved, and the method `format_decimal` can be used in order to round them into a string.",
+		NewExampleSpec("",
+			`root.total = this.price.decimal() + this.tax.decimal()`,
+			`{"price":"0.10","tax":"0.20"}`,
+			`{"total":0.30}`,
+		),
+		NewExampleSpec("",
+			`root.total = (this.price.decimal() * this.quantity).format_decimal(2)`,
+			`{"price":"19.99","quantity":3}`,
+			`{"total":"59.97"}`,
+		),
+	),
+	func(*ParsedParams) (simpleMethod, error) {
+		return func(v interface{}, ctx FunctionContext) (interface{}, error) {
+			return IToDecimal(v)
+		}, nil
+	},
+)
+
+//------------------------------------------------------------------------------
+
 var _ = registerOldParamsMethod(
 	NewMethodSpec(
-		"or", "If the result of the target query fails or resolves to `null`, returns the argument instead. This is an explicit method alternative to the coalesce pipe operator `|`.",
+		"or", "If the result of the target query fails or resolves to `null`, returns the argument instead. The argument is only executed when it is needed. This is an explicit method alternative to the coalesce pipe operator `|`.",
 		NewExampleSpec("", `root.doc.id = this.thing.id.or(uuid_v4())`),
 	),
 	false, orMethod,
@@ -492,7 +552,7 @@ root.foo_type = this.foo.type()`,
 			`{"bar":10,"foo":"is a string"}`,
 			`{"bar_type":"number","foo_type":"string"}`,
 		),
-	),
+	).Returns(ValueString),
 	func(...interface{}) (simpleMethod, error) {
 		return func(v interface{}, ctx FunctionContext) (interface{}, error) {
 			return string(ITypeOf(v)), nil
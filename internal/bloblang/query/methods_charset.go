@@ -0,0 +1,167 @@
+package query
+
+import (
+	"fmt"
+	"unicode/utf8"
+
+	"golang.org/x/text/encoding"
+	"golang.org/x/text/encoding/htmlindex"
+	"golang.org/x/text/encoding/ianaindex"
+	"golang.org/x/text/encoding/unicode"
+	"golang.org/x/text/transform"
+)
+
+var _ = registerSimpleMethod(
+	NewMethodSpec(
+		"detect_encoding", "",
+	).InCategory(
+		MethodCategoryEncoding,
+		"Attempts to detect the character encoding of a string or byte array target and returns its name, which can be fed into the method [`convert_encoding`][methods.convert_encoding]. Detection is a best attempt based on byte order marks and the validity of the data as UTF-8 or UTF-16, where data that is neither is reported as either `iso-8859-1` or `windows-1252`.\n\nPossible results are: `utf-8`, `utf-16le`, `utf-16be`, `iso-8859-1`, `windows-1252`.",
+		NewExampleSpec("",
+			`root.encoding = this.value.decode("base64").detect_encoding()`,
+			`{"value":"Y2Fmw6k="}`,
+			`{"encoding":"utf-8"}`,
+			`{"value":"Y2Fm6Q=="}`,
+			`{"encoding":"iso-8859-1"}`,
+		),
+	),
+	func(*ParsedParams) (simpleMethod, error) {
+		return func(v interface{}, ctx FunctionContext) (interface{}, error) {
+			b, err := charsetInputBytes(v)
+			if err != nil {
+				return nil, err
+			}
+			return detectEncoding(b), nil
+		}, nil
+	},
+)
+
+var _ = registerSimpleMethod(
+	NewMethodSpec(
+		"convert_encoding", "",
+	).InCategory(
+		MethodCategoryEncoding,
+		"Converts a string or byte array target from one character encoding to another and returns the result as a byte array. When mapping the result to a JSON field the value should be cast to a string using the method [`string`][methods.string]. Encodings are identified by their [WHATWG](https://encoding.spec.whatwg.org/#names-and-labels) or [IANA](https://www.iana.org/assignments/character-sets/character-sets.xhtml) names, and a source encoding of `auto` detects the encoding of each value with the same rules as the method [`detect_encoding`][methods.detect_encoding].\n\nA byte order mark at the start of the target overrides the source encoding and is removed. Characters that cannot be represented by the destination encoding result in an error.",
+		NewExampleSpec("",
+			`root.value = this.value.decode("base64").convert_encoding("iso-8859-1").string()`,
+			`{"value":"Y2Fm6Q=="}`,
+			`{"value":"café"}`,
+		),
+		NewExampleSpec(
+			"Values within a stream of mixed encodings can be normalised to UTF-8 by detecting the encoding of each value.",
+			`root = content().convert_encoding("auto")`,
+		),
+	).
+		Param(ParamString("from", "The encoding of the target, or `auto` in order to detect it.")).
+		Param(ParamString("to", "The encoding to convert the target to.").Default("utf-8")),
+	func(args *ParsedParams) (simpleMethod, error) {
+		fromStr, err := args.FieldString("from")
+		if err != nil {
+			return nil, err
+		}
+		toStr, err := args.FieldString("to")
+		if err != nil {
+			return nil, err
+		}
+
+		var from encoding.Encoding
+		if fromStr != "auto" {
+			if from, err = lookupEncoding(fromStr); err != nil {
+				return nil, err
+			}
+		}
+		to, err := lookupEncoding(toStr)
+		if err != nil {
+			return nil, err
+		}
+
+		return func(v interface{}, ctx FunctionContext) (interface{}, error) {
+			b, err := charsetInputBytes(v)
+			if err != nil {
+				return nil, err
+			}
+			srcEnc := from
+			if srcEnc == nil {
+				if srcEnc, err = lookupEncoding(detectEncoding(b)); err != nil {
+					return nil, err
+				}
+			}
+			res, _, err := transform.Bytes(transform.Chain(
+				unicode.BOMOverride(srcEnc.NewDecoder()),
+				to.NewEncoder(),
+			), b)
+			if err != nil {
+				return nil, fmt.Errorf("failed to convert encoding to %v: %w", toStr, err)
+			}
+			return res, nil
+		}, nil
+	},
+)
+
+//------------------------------------------------------------------------------
+
+func charsetInputBytes(v interface{}) ([]byte, error) {
+	switch t := v.(type) {
+	case string:
+		return []byte(t), nil
+	case []byte:
+		return t, nil
+	}
+	return nil, NewTypeError(v, ValueString)
+}
+
+func lookupEncoding(name string) (encoding.Encoding, error) {
+	if enc, err := htmlindex.Get(name); err == nil {
+		return enc, nil
+	}
+	if enc, err := ianaindex.IANA.Encoding(name); err == nil && enc != nil {
+		return enc, nil
+	}
+	return nil, fmt.Errorf("unrecognised character encoding: %v", name)
+}
+
+func detectEncoding(b []byte) string {
+	switch {
+	case len(b) >= 3 && b[0] == 0xEF && b[1] == 0xBB && b[2] == 0xBF:
+		return "utf-8"
+	case len(b) >= 2 && b[0] == 0xFF && b[1] == 0xFE:
+		return "utf-16le"
+	case len(b) >= 2 && b[0] == 0xFE && b[1] == 0xFF:
+		return "utf-16be"
+	}
+
+	// Text encoded as UTF-16 without a byte order mark is mostly recognisable
+	// by the zero bytes of ASCII characters all falling on one side of each
+	// code unit, which also prevents it from being mistaken as UTF-8.
+	if len(b) >= 2 && len(b)%2 == 0 {
+		var evenZeros, oddZeros int
+		for i := 0; i < len(b); i += 2 {
+			if b[i] == 0 {
+				evenZeros++
+			}
+			if b[i+1] == 0 {
+				oddZeros++
+			}
+		}
+		units := len(b) / 2
+		if oddZeros*2 > units && evenZeros == 0 {
+			return "utf-16le"
+		}
+		if evenZeros*2 > units && oddZeros == 0 {
+			return "utf-16be"
+		}
+	}
+
+	if utf8.Valid(b) {
+		return "utf-8"
+	}
+
+	// The C1 control range of ISO-8859-1 is used for printable characters by
+	// windows-1252, and is otherwise very rare in text.
+	for _, c := range b {
+		if c >= 0x80 && c <= 0x9F {
+			return "windows-1252"
+		}
+	}
+	return "iso-8859-1"
+}
@@ -0,0 +1,432 @@
+package query
+
+import (
+	"encoding/hex"
+	"fmt"
+	"net"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Jeffail/benthos/v3/internal/mmdb"
+)
+
+var _ = registerSimpleMethod(
+	NewMethodSpec(
+		"parse_ip", "",
+	).InCategory(
+		MethodCategoryParsing,
+		"Attempts to parse a string as an IPv4 or IPv6 address and returns an object containing the normalized address as `ip`, the `version` of the address (`4` or `6`), and the boolean fields `is_private`, `is_loopback`, `is_link_local`, `is_multicast` and `is_unspecified`. Private addresses are those within the ranges defined by RFC 1918 (IPv4) and RFC 4193 (IPv6).",
+		NewExampleSpec("",
+			`root.ip = this.ip.parse_ip()`,
+			`{"ip":"10.1.2.3"}`,
+			`{"ip":{"ip":"10.1.2.3","is_link_local":false,"is_loopback":false,"is_multicast":false,"is_private":true,"is_unspecified":false,"version":4}}`,
+			`{"ip":"2001:DB8::0:1"}`,
+			`{"ip":{"ip":"2001:db8::1","is_link_local":false,"is_loopback":false,"is_multicast":false,"is_private":false,"is_unspecified":false,"version":6}}`,
+		),
+		NewExampleSpec("",
+			`root.is_internal = this.ip.parse_ip().(is_private || is_loopback)`,
+			`{"ip":"127.0.0.1"}`,
+			`{"is_internal":true}`,
+		),
+	),
+	func(*ParsedParams) (simpleMethod, error) {
+		return stringMethod(func(s string) (interface{}, error) {
+			ip, err := parseIP(s)
+			if err != nil {
+				return nil, err
+			}
+			version := int64(6)
+			if ip.To4() != nil {
+				version = 4
+			}
+			return map[string]interface{}{
+				"ip":             ip.String(),
+				"version":        version,
+				"is_private":     isPrivateIP(ip),
+				"is_loopback":    ip.IsLoopback(),
+				"is_link_local":  ip.IsLinkLocalUnicast() || ip.IsLinkLocalMulticast(),
+				"is_multicast":   ip.IsMulticast(),
+				"is_unspecified": ip.IsUnspecified(),
+			}, nil
+		}), nil
+	},
+)
+
+var _ = registerSimpleMethod(
+	NewMethodSpec(
+		"ip_in_cidr", "",
+	).InCategory(
+		MethodCategoryStrings,
+		"Checks whether a string IP address is within a network range expressed in CIDR notation and returns a boolean.",
+		NewExampleSpec("",
+			`root.is_internal = this.ip.ip_in_cidr("10.0.0.0/8")`,
+			`{"ip":"10.20.30.40"}`,
+			`{"is_internal":true}`,
+			`{"ip":"192.168.0.1"}`,
+			`{"is_internal":false}`,
+		),
+		NewExampleSpec("",
+			`root.is_documentation = this.ip.ip_in_cidr("2001:db8::/32")`,
+			`{"ip":"2001:db8:1234::1"}`,
+			`{"is_documentation":true}`,
+		),
+	).Param(ParamString("cidr", "The network range to check against, e.g. `10.0.0.0/8`.")),
+	func(args *ParsedParams) (simpleMethod, error) {
+		cidrStr, err := args.FieldString("cidr")
+		if err != nil {
+			return nil, err
+		}
+		network, err := parseCIDR(cidrStr)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse argument: %w", err)
+		}
+		return stringMethod(func(s string) (interface{}, error) {
+			ip, err := parseIP(s)
+			if err != nil {
+				return nil, err
+			}
+			return network.Contains(ip), nil
+		}), nil
+	},
+)
+
+var _ = registerSimpleMethod(
+	NewMethodSpec(
+		"cidr_contains", "",
+	).InCategory(
+		MethodCategoryStrings,
+		"Checks whether a network range expressed as a string in CIDR notation contains an IP address and returns a boolean.",
+		NewExampleSpec("",
+			`root.matched = this.network.cidr_contains(this.ip)`,
+			`{"network":"192.168.0.0/16","ip":"192.168.4.20"}`,
+			`{"matched":true}`,
+			`{"network":"192.168.0.0/16","ip":"172.16.4.20"}`,
+			`{"matched":false}`,
+		),
+	).Param(ParamString("ip", "The IP address to check for.")),
+	func(args *ParsedParams) (simpleMethod, error) {
+		ipStr, err := args.FieldString("ip")
+		if err != nil {
+			return nil, err
+		}
+		ip, err := parseIP(ipStr)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse argument: %w", err)
+		}
+		return stringMethod(func(s string) (interface{}, error) {
+			network, err := parseCIDR(s)
+			if err != nil {
+				return nil, err
+			}
+			return network.Contains(ip), nil
+		}), nil
+	},
+)
+
+var _ = registerSimpleMethod(
+	NewMethodSpec(
+		"geoip", "",
+	).InCategory(
+		MethodCategoryStrings,
+		"Looks up a string IP address within a [MaxMind DB](https://maxmind.github.io/MaxMind-DB/) file, such as the GeoIP2 and GeoLite2 City, Country and ASN databases, and returns an object containing any of the fields `country_code`, `country`, `continent_code`, `city`, `subdivision`, `postal_code`, `time_zone`, `latitude`, `longitude`, `asn` and `as_org` that the database provides for the address. If the address is not found within the database then `null` is returned.\n\nDatabases are read into memory upon the first lookup and shared between all mappings that reference the same path. The modification time of the file is checked at most once a minute, and when it changes the database is reloaded, which allows databases to be updated without restarting. Relative paths are resolved from the directory of the process executing the mapping.",
+		NewExampleSpec("",
+			`let geo = this.client_ip.geoip("/var/lib/geoip/GeoLite2-City.mmdb")
+root.country = $geo.country_code | "unknown"
+root.location = [ $geo.latitude, $geo.longitude ]`,
+		),
+		NewExampleSpec("Fields from multiple databases can be combined with the `merge` method.",
+			`root.geo = this.ip.geoip("./GeoLite2-City.mmdb").or({}).merge(this.ip.geoip("./GeoLite2-ASN.mmdb").or({}))`,
+		),
+	).Beta().MarkImpure().
+		Param(ParamString("path", "The path of a MaxMind DB file.")),
+	func(args *ParsedParams) (simpleMethod, error) {
+		path, err := args.FieldString("path")
+		if err != nil {
+			return nil, err
+		}
+		db := getGeoIPDatabase(path)
+		return stringMethod(func(s string) (interface{}, error) {
+			ip, err := parseIP(s)
+			if err != nil {
+				return nil, err
+			}
+			reader, err := db.get()
+			if err != nil {
+				return nil, fmt.Errorf("failed to open geoip database: %w", err)
+			}
+			record, found, err := reader.Lookup(ip)
+			if err != nil {
+				return nil, err
+			}
+			if !found {
+				return nil, nil
+			}
+			return geoIPResult(record), nil
+		}), nil
+	},
+)
+
+// How often the modification time of a geoip database file is checked.
+const geoIPReloadInterval = time.Minute
+
+type geoIPDatabase struct {
+	path string
+
+	mut       sync.RWMutex
+	reader    *mmdb.Reader
+	modTime   time.Time
+	lastCheck time.Time
+}
+
+var geoIPDatabases = struct {
+	sync.Mutex
+	m map[string]*geoIPDatabase
+}{m: map[string]*geoIPDatabase{}}
+
+func getGeoIPDatabase(path string) *geoIPDatabase {
+	geoIPDatabases.Lock()
+	defer geoIPDatabases.Unlock()
+
+	db, exists := geoIPDatabases.m[path]
+	if !exists {
+		db = &geoIPDatabase{path: path}
+		geoIPDatabases.m[path] = db
+	}
+	return db
+}
+
+// get returns the current reader of the database, reloading it if the file
+// has been modified since it was last read. If a reload fails then the
+// previous reader continues to be used.
+func (g *geoIPDatabase) get() (*mmdb.Reader, error) {
+	g.mut.RLock()
+	reader, lastCheck := g.reader, g.lastCheck
+	g.mut.RUnlock()
+	if reader != nil && time.Since(lastCheck) < geoIPReloadInterval {
+		return reader, nil
+	}
+
+	g.mut.Lock()
+	defer g.mut.Unlock()
+	if g.reader != nil && time.Since(g.lastCheck) < geoIPReloadInterval {
+		return g.reader, nil
+	}
+
+	info, err := os.Stat(g.path)
+	if err == nil && g.reader != nil && info.ModTime().Equal(g.modTime) {
+		g.lastCheck = time.Now()
+		return g.reader, nil
+	}
+	if err == nil {
+		var newReader *mmdb.Reader
+		if newReader, err = mmdb.Open(g.path); err == nil {
+			g.reader, g.modTime, g.lastCheck = newReader, info.ModTime(), time.Now()
+			return g.reader, nil
+		}
+	}
+	if g.reader != nil {
+		g.lastCheck = time.Now()
+		return g.reader, nil
+	}
+	return nil, err
+}
+
+// geoIPResult extracts the commonly used fields of GeoIP2 and GeoLite2 records
+// into a flat object.
+func geoIPResult(record interface{}) map[string]interface{} {
+	res := map[string]interface{}{}
+	recordMap, ok := record.(map[string]interface{})
+	if !ok {
+		return res
+	}
+
+	getFrom := func(v interface{}, path ...string) (interface{}, bool) {
+		for _, p := range path {
+			m, ok := v.(map[string]interface{})
+			if !ok {
+				return nil, false
+			}
+			if v, ok = m[p]; !ok {
+				return nil, false
+			}
+		}
+		return v, true
+	}
+	get := func(path ...string) (interface{}, bool) {
+		return getFrom(recordMap, path...)
+	}
+	set := func(key string, path ...string) {
+		if v, ok := get(path...); ok {
+			res[key] = v
+		}
+	}
+
+	set("country_code", "country", "iso_code")
+	set("country", "country", "names", "en")
+	set("continent_code", "continent", "code")
+	set("city", "city", "names", "en")
+	if subdivisions, ok := get("subdivisions"); ok {
+		if s, ok := subdivisions.([]interface{}); ok && len(s) > 0 {
+			if name, ok := getFrom(s[0], "names", "en"); ok {
+				res["subdivision"] = name
+			}
+		}
+	}
+	set("postal_code", "postal", "code")
+	set("time_zone", "location", "time_zone")
+	set("latitude", "location", "latitude")
+	set("longitude", "location", "longitude")
+	set("asn", "autonomous_system_number")
+	set("as_org", "autonomous_system_organization")
+	return res
+}
+
+var _ = registerSimpleMethod(
+	NewMethodSpec(
+		"parse_mac", "",
+	).InCategory(
+		MethodCategoryParsing,
+		"Attempts to parse a string as a MAC address in either colon (`00:1a:2b:3c:4d:5e`), dash (`00-1A-2B-3C-4D-5E`), Cisco dotted (`001a.2b3c.4d5e`) or bare hexadecimal (`001a2b3c4d5e`) format, and returns an object containing the normalized address as `mac`, the Organizationally Unique Identifier that identifies the vendor of the address as `oui`, and the boolean fields `is_local` (the address is locally administered) and `is_multicast`. EUI-64 addresses are also supported.",
+		NewExampleSpec("",
+			`root.device = this.mac.parse_mac()`,
+			`{"mac":"00-1A-2B-3C-4D-5E"}`,
+			`{"device":{"is_local":false,"is_multicast":false,"mac":"00:1a:2b:3c:4d:5e","oui":"00:1a:2b"}}`,
+		),
+	),
+	func(*ParsedParams) (simpleMethod, error) {
+		return stringMethod(func(s string) (interface{}, error) {
+			mac, err := parseMAC(s)
+			if err != nil {
+				return nil, err
+			}
+			return map[string]interface{}{
+				"mac":          mac.String(),
+				"oui":          mac[:3].String(),
+				"is_local":     mac[0]&0x02 != 0,
+				"is_multicast": mac[0]&0x01 != 0,
+			}, nil
+		}), nil
+	},
+)
+
+var _ = registerSimpleMethod(
+	NewMethodSpec(
+		"format_mac", "",
+	).InCategory(
+		MethodCategoryStrings,
+		"Parses a string MAC address in any of the formats supported by [`parse_mac`](#parse_mac) and formats it in a given style, which is useful for normalizing addresses reported by different devices. The style `colon` results in `00:1a:2b:3c:4d:5e`, `dash` in `00-1a-2b-3c-4d-5e`, `dot` (Cisco) in `001a.2b3c.4d5e`, and `bare` in `001a2b3c4d5e`.",
+		NewExampleSpec("",
+			`root.mac = this.mac.format_mac()`,
+			`{"mac":"001A.2B3C.4D5E"}`,
+			`{"mac":"00:1a:2b:3c:4d:5e"}`,
+		),
+		NewExampleSpec("",
+			`root.mac = this.mac.format_mac(style: "dash", uppercase: true)`,
+			`{"mac":"00:1a:2b:3c:4d:5e"}`,
+			`{"mac":"00-1A-2B-3C-4D-5E"}`,
+		),
+	).
+		Param(ParamString("style", "The style to format the address with, one of `colon`, `dash`, `dot` or `bare`.").Default("colon")).
+		Param(ParamBool("uppercase", "Whether hexadecimal digits should be uppercase.").Default(false)),
+	func(args *ParsedParams) (simpleMethod, error) {
+		style, err := args.FieldString("style")
+		if err != nil {
+			return nil, err
+		}
+		uppercase, err := args.FieldBool("uppercase")
+		if err != nil {
+			return nil, err
+		}
+		var sep string
+		var groupSize int
+		switch style {
+		case "colon":
+			sep, groupSize = ":", 2
+		case "dash":
+			sep, groupSize = "-", 2
+		case "dot":
+			sep, groupSize = ".", 4
+		case "bare":
+			groupSize = 2
+		default:
+			return nil, fmt.Errorf("unrecognised style: %v", style)
+		}
+		return stringMethod(func(s string) (interface{}, error) {
+			mac, err := parseMAC(s)
+			if err != nil {
+				return nil, err
+			}
+			digits := hex.EncodeToString(mac)
+			if uppercase {
+				digits = strings.ToUpper(digits)
+			}
+			var b strings.Builder
+			for i := 0; i < len(digits); i += groupSize {
+				if i > 0 {
+					b.WriteString(sep)
+				}
+				b.WriteString(digits[i : i+groupSize])
+			}
+			return b.String(), nil
+		}), nil
+	},
+)
+
+// parseMAC parses a MAC address in any of the formats supported by
+// net.ParseMAC as well as bare hexadecimal.
+func parseMAC(s string) (net.HardwareAddr, error) {
+	if len(s) == 12 || len(s) == 16 {
+		if b, err := hex.DecodeString(s); err == nil {
+			return net.HardwareAddr(b), nil
+		}
+	}
+	mac, err := net.ParseMAC(s)
+	if err != nil || (len(mac) != 6 && len(mac) != 8) {
+		return nil, fmt.Errorf("failed to parse '%v' as a MAC address", s)
+	}
+	return mac, nil
+}
+
+func parseIP(s string) (net.IP, error) {
+	ip := net.ParseIP(s)
+	if ip == nil {
+		return nil, fmt.Errorf("failed to parse '%v' as an IP address", s)
+	}
+	return ip, nil
+}
+
+func parseCIDR(s string) (*net.IPNet, error) {
+	_, network, err := net.ParseCIDR(s)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse '%v' as a CIDR network", s)
+	}
+	return network, nil
+}
+
+var privateIPNetworks = func() []*net.IPNet {
+	var networks []*net.IPNet
+	for _, cidr := range []string{
+		"10.0.0.0/8",     // RFC 1918
+		"172.16.0.0/12",  // RFC 1918
+		"192.168.0.0/16", // RFC 1918
+		"fc00::/7",       // RFC 4193
+	} {
+		_, network, err := net.ParseCIDR(cidr)
+		if err != nil {
+			panic(err)
+		}
+		networks = append(networks, network)
+	}
+	return networks
+}()
+
+func isPrivateIP(ip net.IP) bool {
+	for _, network := range privateIPNetworks {
+		if network.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,88 @@
+package query
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGeoIPResult(t *testing.T) {
+	record := map[string]interface{}{
+		"city": map[string]interface{}{
+			"geoname_id": int64(2643743),
+			"names":      map[string]interface{}{"en": "London", "de": "London"},
+		},
+		"continent": map[string]interface{}{"code": "EU"},
+		"country": map[string]interface{}{
+			"iso_code": "GB",
+			"names":    map[string]interface{}{"en": "United Kingdom"},
+		},
+		"location": map[string]interface{}{
+			"latitude":  51.5142,
+			"longitude": -0.0931,
+			"time_zone": "Europe/London",
+		},
+		"postal": map[string]interface{}{"code": "EC2V"},
+		"subdivisions": []interface{}{
+			map[string]interface{}{"iso_code": "ENG", "names": map[string]interface{}{"en": "England"}},
+		},
+	}
+
+	assert.Equal(t, map[string]interface{}{
+		"city":           "London",
+		"continent_code": "EU",
+		"country":        "United Kingdom",
+		"country_code":   "GB",
+		"latitude":       51.5142,
+		"longitude":      -0.0931,
+		"postal_code":    "EC2V",
+		"subdivision":    "England",
+		"time_zone":      "Europe/London",
+	}, geoIPResult(record))
+
+	assert.Equal(t, map[string]interface{}{
+		"asn":    int64(15169),
+		"as_org": "Google LLC",
+	}, geoIPResult(map[string]interface{}{
+		"autonomous_system_number":       int64(15169),
+		"autonomous_system_organization": "Google LLC",
+	}))
+
+	assert.Equal(t, map[string]interface{}{}, geoIPResult("nope"))
+}
+
+func TestGeoIPResultMalformedSubdivisions(t *testing.T) {
+	tests := map[string]interface{}{
+		"not an array":      "nope",
+		"empty array":       []interface{}{},
+		"not an object":     []interface{}{"nope"},
+		"missing names":     []interface{}{map[string]interface{}{"iso_code": "ENG"}},
+		"names not object":  []interface{}{map[string]interface{}{"names": "England"}},
+		"missing english":   []interface{}{map[string]interface{}{"names": map[string]interface{}{"de": "England"}}},
+		"null subdivisions": nil,
+	}
+
+	for name, subdivisions := range tests {
+		subdivisions := subdivisions
+		t.Run(name, func(t *testing.T) {
+			var res map[string]interface{}
+			require.NotPanics(t, func() {
+				res = geoIPResult(map[string]interface{}{
+					"country":      map[string]interface{}{"iso_code": "GB"},
+					"subdivisions": subdivisions,
+				})
+			})
+			assert.Equal(t, map[string]interface{}{"country_code": "GB"}, res)
+		})
+	}
+}
+
+func TestGeoIPMissingDatabase(t *testing.T) {
+	fn, err := InitMethodHelper("geoip", NewLiteralFunction("", "1.2.3.4"), "/does/not/exist.mmdb")
+	require.NoError(t, err)
+
+	_, err = fn.Exec(FunctionContext{})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "failed to open geoip database")
+}
@@ -4,6 +4,13 @@ import (
 	"errors"
 	"fmt"
 	"math"
+	"math/big"
+	"math/rand"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/OneOfOne/xxhash"
 )
 
 var _ = registerSimpleMethod(
@@ -16,7 +23,7 @@ var _ = registerSimpleMethod(
 			`{"value":-5.9}`,
 			`{"new_value":5.9}`,
 		),
-	),
+	).AppliesTo(ValueNumber).Returns(ValueNumber),
 	func(*ParsedParams) (simpleMethod, error) {
 		return numberMethod(func(f *float64, i *int64, ui *uint64) (interface{}, error) {
 			var v float64
@@ -33,7 +40,7 @@ var _ = registerSimpleMethod(
 )
 
 var _ = registerSimpleMethod(
-	NewMethodSpec("ceil", "Returns the least integer value greater than or equal to a number.").InCategory(
+	NewMethodSpec("ceil", "Returns the least integer value greater than or equal to a number. If the resulting value fits within a 64-bit integer then that is returned, otherwise a new floating point number is returned.").InCategory(
 		MethodCategoryNumbers, "",
 		NewExampleSpec("",
 			`root.new_value = this.value.ceil()`,
@@ -42,23 +49,20 @@ var _ = registerSimpleMethod(
 			`{"value":-5.9}`,
 			`{"new_value":-5}`,
 		),
-	),
-	func(*ParsedParams) (simpleMethod, error) {
-		return numberMethod(func(f *float64, i *int64, ui *uint64) (interface{}, error) {
-			if f != nil {
-				return int64(math.Ceil(*f)), nil
-			}
-			if i != nil {
-				return *i, nil
-			}
-			return *ui, nil
-		}), nil
+		NewExampleSpec("An optional precision argument can be provided in order to round up to a number of decimal places.",
+			`root.new_value = this.value.ceil(2)`,
+			`{"value":5.301}`,
+			`{"new_value":5.31}`,
+		),
+	).Param(roundingPrecisionParam()).AppliesTo(ValueNumber).Returns(ValueNumber),
+	func(args *ParsedParams) (simpleMethod, error) {
+		return roundingMethod(args, roundCeil, math.Ceil)
 	},
 )
 
 var _ = registerSimpleMethod(
 	NewMethodSpec(
-		"floor", "Returns the greatest integer value less than or equal to the target number.",
+		"floor", "Returns the greatest integer value less than or equal to the target number. If the resulting value fits within a 64-bit integer then that is returned, otherwise a new floating point number is returned.",
 	).InCategory(
 		MethodCategoryNumbers,
 		"",
@@ -67,20 +71,151 @@ var _ = registerSimpleMethod(
 			`{"value":5.7}`,
 			`{"new_value":5}`,
 		),
-	),
-	func(*ParsedParams) (simpleMethod, error) {
+		NewExampleSpec("An optional precision argument can be provided in order to round down to a number of decimal places.",
+			`root.new_value = this.value.floor(1)`,
+			`{"value":5.79}`,
+			`{"new_value":5.7}`,
+		),
+	).Param(roundingPrecisionParam()).AppliesTo(ValueNumber).Returns(ValueNumber),
+	func(args *ParsedParams) (simpleMethod, error) {
+		return roundingMethod(args, roundFloor, math.Floor)
+	},
+)
+
+var _ = registerSimpleMethod(
+	NewMethodSpec(
+		"format_number", "Formats a number as a string with a fixed number of decimal places, a decimal separator and a separator between each group of thousands, which is useful for rendering numbers for human facing outputs.",
+	).InCategory(
+		MethodCategoryNumbers, "",
+		NewExampleSpec("",
+			`root.total = this.total.format_number()`,
+			`{"total":1234567.891}`,
+			`{"total":"1,234,567.89"}`,
+		),
+		NewExampleSpec("Separators can be customised in order to match the formatting conventions of a locale.",
+			`root.total = this.total.format_number(2, ",", ".") + " €"`,
+			`{"total":1234567.891}`,
+			`{"total":"1.234.567,89 €"}`,
+		),
+		NewExampleSpec("",
+			`root.count = this.count.format_number(decimals: 0, thousands_separator: " ")`,
+			`{"count":-9876543}`,
+			`{"count":"-9 876 543"}`,
+		),
+	).
+		Param(ParamInt64("decimals", "The number of decimal places to display, the number is rounded when necessary.").Default(2)).
+		Param(ParamString("decimal_separator", "The separator to place between the integer and fractional parts of the number.").Default(".")).
+		Param(ParamString("thousands_separator", "The separator to place between each group of thousands, which can be empty in order to disable grouping.").Default(",")).
+		AppliesTo(ValueNumber).
+		Returns(ValueString),
+	func(args *ParsedParams) (simpleMethod, error) {
+		decimals, err := args.FieldInt64("decimals")
+		if err != nil {
+			return nil, err
+		}
+		if decimals < 0 {
+			return nil, fmt.Errorf("decimals must be a non-negative integer, got %v", decimals)
+		}
+		decSep, err := args.FieldString("decimal_separator")
+		if err != nil {
+			return nil, err
+		}
+		thousandsSep, err := args.FieldString("thousands_separator")
+		if err != nil {
+			return nil, err
+		}
 		return numberMethod(func(f *float64, i *int64, ui *uint64) (interface{}, error) {
+			var str string
 			if f != nil {
-				return int64(math.Floor(*f)), nil
-			}
-			if i != nil {
-				return *i, nil
+				if math.IsNaN(*f) || math.IsInf(*f, 0) {
+					return nil, fmt.Errorf("cannot format number: %v", *f)
+				}
+				str = strconv.FormatFloat(*f, 'f', int(decimals), 64)
+			} else {
+				if i != nil {
+					str = strconv.FormatInt(*i, 10)
+				} else {
+					str = strconv.FormatUint(*ui, 10)
+				}
+				if decimals > 0 {
+					str += "." + strings.Repeat("0", int(decimals))
+				}
 			}
-			return *ui, nil
+			return formatNumberString(str, decSep, thousandsSep), nil
 		}), nil
 	},
 )
 
+var _ = registerSimpleMethod(
+	NewMethodSpec(
+		"format_decimal", "Formats a number as an exact decimal string. When a scale is provided the number is rounded half away from zero to that number of decimal places, and padded with zeros when necessary. Unlike `format_number` the value is converted into a decimal before rounding, and therefore floating point values are rounded according to their shortest representation.",
+	).InCategory(
+		MethodCategoryNumbers, "",
+		NewExampleSpec("",
+			`root.amount = this.amount.decimal().format_decimal(2)`,
+			`{"amount":"1.005"}`,
+			`{"amount":"1.01"}`,
+			`{"amount":"12"}`,
+			`{"amount":"12.00"}`,
+		),
+		NewExampleSpec("",
+			`root.rate = (this.a.decimal() / this.b).format_decimal()`,
+			`{"a":"1","b":8}`,
+			`{"rate":"0.125"}`,
+		),
+	).
+		Param(ParamInt64("scale", "The number of decimal places to format with, when omitted all decimal places of the value are kept.").Optional()),
+	func(args *ParsedParams) (simpleMethod, error) {
+		scale, err := args.FieldOptionalInt64("scale")
+		if err != nil {
+			return nil, err
+		}
+		if scale != nil && (*scale < 0 || *scale > decimalMaxScale) {
+			return nil, fmt.Errorf("scale must be between 0 and %v, got %v", decimalMaxScale, *scale)
+		}
+		return func(v interface{}, ctx FunctionContext) (interface{}, error) {
+			d, err := IGetDecimal(v)
+			if err != nil {
+				return nil, err
+			}
+			if scale != nil {
+				d = d.Round(int(*scale), roundHalfAwayFromZero)
+			}
+			return d.String(), nil
+		}, nil
+	},
+)
+
+// formatNumberString replaces the separators of a formatted decimal number.
+func formatNumberString(str, decSep, thousandsSep string) string {
+	var sign string
+	if strings.HasPrefix(str, "-") {
+		str = str[1:]
+		// Avoid rendering a negative zero after rounding.
+		if strings.Trim(str, "0.") != "" {
+			sign = "-"
+		}
+	}
+	intPart, fracPart := str, ""
+	if i := strings.IndexByte(str, '.'); i >= 0 {
+		intPart, fracPart = str[:i], str[i+1:]
+	}
+
+	var b strings.Builder
+	b.WriteString(sign)
+	for i, c := range intPart {
+		if i > 0 && (len(intPart)-i)%3 == 0 {
+			b.WriteString(thousandsSep)
+		}
+		b.WriteRune(c)
+	}
+	if len(fracPart) > 0 {
+		b.WriteString(decSep)
+		b.WriteString(fracPart)
+	}
+	return b.String()
+}
+
 var _ = registerSimpleMethod(
 	NewMethodSpec("log", "Returns the natural logarithm of a number.").InCategory(
 		MethodCategoryNumbers, "",
@@ -91,7 +226,7 @@ var _ = registerSimpleMethod(
 			`{"value":2.7183}`,
 			`{"new_value":1}`,
 		),
-	),
+	).AppliesTo(ValueNumber).Returns(ValueNumber),
 	func(*ParsedParams) (simpleMethod, error) {
 		return numberMethod(func(f *float64, i *int64, ui *uint64) (interface{}, error) {
 			var v float64
@@ -117,7 +252,7 @@ var _ = registerSimpleMethod(
 			`{"value":1000}`,
 			`{"new_value":3}`,
 		),
-	),
+	).AppliesTo(ValueNumber).Returns(ValueNumber),
 	func(*ParsedParams) (simpleMethod, error) {
 		return numberMethod(func(f *float64, i *int64, ui *uint64) (interface{}, error) {
 			var v float64
@@ -151,7 +286,7 @@ var _ = registerSimpleMethod(
 			`{"value":7}`,
 			`{"new_value":7}`,
 		),
-	),
+	).AppliesTo(ValueArray).Returns(ValueNumber),
 	func(*ParsedParams) (simpleMethod, error) {
 		return func(v interface{}, ctx FunctionContext) (interface{}, error) {
 			arr, ok := v.([]interface{})
@@ -194,7 +329,7 @@ var _ = registerSimpleMethod(
 			`{"value":23}`,
 			`{"new_value":10}`,
 		),
-	),
+	).AppliesTo(ValueArray).Returns(ValueNumber),
 	func(*ParsedParams) (simpleMethod, error) {
 		return func(v interface{}, ctx FunctionContext) (interface{}, error) {
 			arr, ok := v.([]interface{})
@@ -221,7 +356,56 @@ var _ = registerSimpleMethod(
 
 var _ = registerSimpleMethod(
 	NewMethodSpec(
-		"round", "Rounds numbers to the nearest integer, rounding half away from zero.",
+		"random_int", "Generates a non-negative pseudo-random 64-bit integer seeded by the target value, which means the same value always results in the same number. This is useful for deterministic sampling and routing decisions based on a field such as a user ID, where the decision must remain consistent across restarts and replays. Integer values are used directly as the seed, which results in the same number as the first value generated by the function `random_int` with that seed, and all other values are hashed into a seed.",
+	).InCategory(
+		MethodCategoryNumbers, "",
+		NewExampleSpec("A consistent sample of roughly ten percent of users can be obtained by deleting messages unless their user ID maps to a number below ten.",
+			`root = if this.user_id.random_int(max: 100) >= 10 { deleted() }`,
+		),
+		NewExampleSpec("",
+			`root.bucket = this.id.random_int(4)`,
+		),
+	).
+		Param(ParamInt64("max", "An optional exclusive upper bound of the generated number, which must be greater than zero.").Optional()),
+	func(args *ParsedParams) (simpleMethod, error) {
+		max, err := args.FieldOptionalInt64("max")
+		if err != nil {
+			return nil, err
+		}
+		if max != nil && *max <= 0 {
+			return nil, fmt.Errorf("max must be greater than zero, got %v", *max)
+		}
+		// The generator is reseeded from each value rather than allocated for
+		// each invocation, which yields the same numbers as a fresh generator.
+		var randMut sync.Mutex
+		r := rand.New(rand.NewSource(0))
+		return func(v interface{}, ctx FunctionContext) (interface{}, error) {
+			var seed int64
+			switch t := ISanitize(v).(type) {
+			case int64:
+				seed = t
+			case uint64:
+				seed = int64(t)
+			case nil:
+				return nil, NewTypeError(v, ValueString, ValueNumber)
+			default:
+				seed = int64(xxhash.Checksum64(IToBytes(t)))
+			}
+			randMut.Lock()
+			defer randMut.Unlock()
+
+			r.Seed(seed)
+			if max != nil {
+				return r.Int63n(*max), nil
+			}
+			return int64(r.Int()), nil
+		}, nil
+	},
+)
+
+var _ = registerSimpleMethod(
+	NewMethodSpec(
+		"round", "Rounds numbers to the nearest integer, rounding half away from zero. If the resulting value fits within a 64-bit integer then that is returned, otherwise a new floating point number is returned.",
 	).InCategory(
 		MethodCategoryNumbers,
 		"",
@@ -232,16 +416,165 @@ var _ = registerSimpleMethod(
 			`{"value":5.9}`,
 			`{"new_value":6}`,
 		),
-	),
-	func(*ParsedParams) (simpleMethod, error) {
-		return numberMethod(func(f *float64, i *int64, ui *uint64) (interface{}, error) {
+		NewExampleSpec("An optional precision argument can be provided in order to round to a number of decimal places. Rounding is performed on the shortest decimal representation of the number, and therefore values such as `1.005` are rounded as they appear rather than as their binary floating point approximation.",
+			`root.new_value = this.value.round(2)`,
+			`{"value":1.005}`,
+			`{"new_value":1.01}`,
+		),
+		NewExampleSpec("A negative precision rounds to the left of the decimal point.",
+			`root.new_value = this.value.round(-2)`,
+			`{"value":1250}`,
+			`{"new_value":1300}`,
+		),
+	).Param(roundingPrecisionParam()).AppliesTo(ValueNumber).Returns(ValueNumber),
+	func(args *ParsedParams) (simpleMethod, error) {
+		return roundingMethod(args, roundHalfAwayFromZero, math.Round)
+	},
+)
+
+var _ = registerSimpleMethod(
+	NewMethodSpec(
+		"round_half_even", "Rounds numbers to the nearest integer, rounding half to the nearest even number, also known as banker's rounding. This avoids the upwards bias of rounding half away from zero when aggregating many rounded values. If the resulting value fits within a 64-bit integer then that is returned, otherwise a new floating point number is returned.",
+	).InCategory(
+		MethodCategoryNumbers,
+		"",
+		NewExampleSpec("",
+			`root.new_value = this.value.round_half_even()`,
+			`{"value":2.5}`,
+			`{"new_value":2}`,
+			`{"value":3.5}`,
+			`{"new_value":4}`,
+		),
+		NewExampleSpec("An optional precision argument can be provided in order to round to a number of decimal places.",
+			`root.new_value = this.value.round_half_even(2)`,
+			`{"value":10.125}`,
+			`{"new_value":10.12}`,
+			`{"value":10.135}`,
+			`{"new_value":10.14}`,
+		),
+	).Param(roundingPrecisionParam()).AppliesTo(ValueNumber).Returns(ValueNumber),
+	func(args *ParsedParams) (simpleMethod, error) {
+		return roundingMethod(args, roundHalfEven, math.RoundToEven)
+	},
+)
+
+//------------------------------------------------------------------------------
+
+type roundingMode int
+
+const (
+	roundHalfAwayFromZero roundingMode = iota
+	roundHalfEven
+	roundFloor
+	roundCeil
+)
+
+func roundingPrecisionParam() ParamDefinition {
+	return ParamInt64("precision", "The number of decimal places to round to, a negative value rounds to the left of the decimal point.").Default(0)
+}
+
+func roundingMethod(args *ParsedParams, mode roundingMode, intRoundFn func(float64) float64) (simpleMethod, error) {
+	precision, err := args.FieldInt64("precision")
+	if err != nil {
+		return nil, err
+	}
+	if precision > 100 || precision < -100 {
+		return nil, fmt.Errorf("precision must be between -100 and 100, got %v", precision)
+	}
+	return numberMethod(func(f *float64, i *int64, ui *uint64) (interface{}, error) {
+		if precision == 0 {
 			if f != nil {
-				return int64(math.Round(*f)), nil
+				return roundedFloatResult(intRoundFn(*f)), nil
 			}
 			if i != nil {
 				return *i, nil
 			}
 			return *ui, nil
-		}), nil
-	},
-)
+		}
+		if precision > 0 && f == nil {
+			// Integers are already exact to any number of decimal places.
+			if i != nil {
+				return *i, nil
+			}
+			return *ui, nil
+		}
+
+		var r *big.Rat
+		switch {
+		case f != nil:
+			if math.IsNaN(*f) || math.IsInf(*f, 0) {
+				return *f, nil
+			}
+			r, _ = new(big.Rat).SetString(strconv.FormatFloat(*f, 'f', -1, 64))
+		case i != nil:
+			r = new(big.Rat).SetInt64(*i)
+		default:
+			r = new(big.Rat).SetInt(new(big.Int).SetUint64(*ui))
+		}
+
+		res := roundRat(r, int(precision), mode)
+		if precision < 0 && res.IsInt() && res.Num().IsInt64() {
+			return res.Num().Int64(), nil
+		}
+		resF, _ := res.Float64()
+		return resF, nil
+	}), nil
+}
+
+// roundedFloatResult returns an integer representation of a rounded float when
+// it fits within an int64, otherwise the float is returned.
+func roundedFloatResult(f float64) interface{} {
+	if f >= math.MinInt64 && f < math.MaxInt64 {
+		return int64(f)
+	}
+	return f
+}
+
+// roundRat rounds a rational number to a number of decimal places using the
+// provided rounding mode.
+func roundRat(r *big.Rat, places int, mode roundingMode) *big.Rat {
+	scale := new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(absInt(places))), nil)
+
+	num := new(big.Int).Set(r.Num())
+	denom := new(big.Int).Set(r.Denom())
+	if places >= 0 {
+		num.Mul(num, scale)
+	} else {
+		denom.Mul(denom, scale)
+	}
+
+	q, rem := new(big.Int).QuoRem(num, denom, new(big.Int))
+	if rem.Sign() != 0 {
+		// Compare the magnitude of the remainder against half of the divisor.
+		half := new(big.Int).Abs(rem)
+		half.Mul(half, big.NewInt(2))
+		halfCmp := half.Cmp(denom)
+
+		var bump bool
+		switch mode {
+		case roundHalfAwayFromZero:
+			bump = halfCmp >= 0
+		case roundHalfEven:
+			bump = halfCmp > 0 || (halfCmp == 0 && q.Bit(0) == 1)
+		case roundFloor:
+			bump = rem.Sign() < 0
+		case roundCeil:
+			bump = rem.Sign() > 0
+		}
+		if bump {
+			q.Add(q, big.NewInt(int64(rem.Sign())))
+		}
+	}
+
+	if places >= 0 {
+		return new(big.Rat).SetFrac(q, scale)
+	}
+	return new(big.Rat).SetInt(q.Mul(q, scale))
+}
+
+func absInt(i int) int {
+	if i < 0 {
+		return -i
+	}
+	return i
+}
@@ -0,0 +1,248 @@
+package query
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+var _ = registerSimpleMethod(
+	NewMethodSpec(
+		"parse_phone", "",
+	).InCategory(
+		MethodCategoryParsing,
+		"Attempts to parse a string as a telephone number and returns an object containing the number normalized in [E.164](https://en.wikipedia.org/wiki/E.164) format as `e164`, the numerical `country_code`, the `national_number`, the two letter `region` of the number, an `extension` when present, and a boolean `valid`.\n\nNumbers in international format (prefixed with `+` or `00`) are parsed regardless of the region argument, whereas numbers in national format are interpreted as belonging to the region provided, with the national trunk prefix of the region removed. Formatting characters such as spaces, dashes, dots and parentheses are ignored.\n\nA number is considered valid when the length of its national number matches the known lengths of numbers within its region, or when it is within the limits of the E.164 specification for regions where the lengths are not known. Since the numbering plans of each region are not checked in detail a valid number is not guaranteed to be assigned.",
+		NewExampleSpec("",
+			`root.phone = this.phone.parse_phone("GB")`,
+			`{"phone":"020 7946 0018"}`,
+			`{"phone":{"country_code":44,"e164":"+442079460018","extension":"","national_number":"2079460018","region":"GB","valid":true}}`,
+			`{"phone":"+1 (415) 555-2671 ext. 12"}`,
+			`{"phone":{"country_code":1,"e164":"+14155552671","extension":"12","national_number":"4155552671","region":"US","valid":true}}`,
+		),
+		NewExampleSpec("",
+			`root.to = this.to.parse_phone(region: "DE").e164`,
+			`{"to":"0151 23456789"}`,
+			`{"to":"+4915123456789"}`,
+		),
+	).Param(ParamString("region", "The two letter ISO 3166-1 region code used in order to interpret numbers written in national format.").Default("")),
+	func(args *ParsedParams) (simpleMethod, error) {
+		region, err := args.FieldString("region")
+		if err != nil {
+			return nil, err
+		}
+		region = strings.ToUpper(region)
+		if region != "" {
+			if _, exists := phoneRegionCodes[region]; !exists {
+				return nil, fmt.Errorf("unrecognised region: %v", region)
+			}
+		}
+		return stringMethod(func(s string) (interface{}, error) {
+			p, err := parsePhoneNumber(s, region)
+			if err != nil {
+				return nil, err
+			}
+			return map[string]interface{}{
+				"e164":            "+" + strconv.Itoa(p.countryCode) + p.nationalNumber,
+				"country_code":    int64(p.countryCode),
+				"national_number": p.nationalNumber,
+				"region":          p.region,
+				"extension":       p.extension,
+				"valid":           p.valid(),
+			}, nil
+		}), nil
+	},
+)
+
+type phoneNumber struct {
+	countryCode    int
+	nationalNumber string
+	region         string
+	extension      string
+}
+
+var phoneExtensionSeparators = []string{"extension", "ext.", "ext", "#", "x"}
+
+func parsePhoneNumber(s, defaultRegion string) (phoneNumber, error) {
+	var p phoneNumber
+
+	number := strings.ToLower(strings.TrimSpace(s))
+	for _, sep := range phoneExtensionSeparators {
+		if i := strings.LastIndex(number, sep); i > 0 {
+			ext := strings.TrimSpace(number[i+len(sep):])
+			if ext != "" && strings.Trim(ext, "0123456789") == "" {
+				p.extension = ext
+				number = number[:i]
+				break
+			}
+		}
+	}
+
+	var digits strings.Builder
+	international := false
+	for i, c := range number {
+		switch {
+		case c >= '0' && c <= '9':
+			digits.WriteRune(c)
+		case c == '+' && i == 0:
+			international = true
+		case c == ' ' || c == '-' || c == '.' || c == '(' || c == ')' || c == '/' || c == '\t':
+		default:
+			return p, fmt.Errorf("failed to parse '%v' as a phone number: unexpected character '%c'", s, c)
+		}
+	}
+
+	n := digits.String()
+	if !international {
+		switch {
+		case phoneRegionCodes[defaultRegion] == 1:
+			if strings.HasPrefix(n, "011") {
+				n, international = n[3:], true
+			}
+		case strings.HasPrefix(n, "00"):
+			n, international = n[2:], true
+		}
+	}
+
+	if international {
+		for l := 1; l <= 3 && l < len(n); l++ {
+			code, _ := strconv.Atoi(n[:l])
+			if region, exists := phoneCodeRegions[code]; exists {
+				p.countryCode, p.nationalNumber, p.region = code, n[l:], region
+				if phoneRegionCodes[defaultRegion] == code {
+					p.region = defaultRegion
+				}
+				break
+			}
+		}
+		if p.countryCode == 0 {
+			return p, fmt.Errorf("failed to parse '%v' as a phone number: unrecognised country calling code", s)
+		}
+	} else {
+		if defaultRegion == "" {
+			return p, fmt.Errorf("failed to parse '%v' as a phone number: a region is required for numbers in national format", s)
+		}
+		p.countryCode, p.region = phoneRegionCodes[defaultRegion], defaultRegion
+		if prefix := phoneTrunkPrefix(defaultRegion); prefix != "" && len(n) > len(prefix) {
+			n = strings.TrimPrefix(n, prefix)
+		}
+		p.nationalNumber = n
+	}
+
+	if p.nationalNumber == "" {
+		return p, fmt.Errorf("failed to parse '%v' as a phone number: number is empty", s)
+	}
+	return p, nil
+}
+
+func (p phoneNumber) valid() bool {
+	l := len(p.nationalNumber)
+	if l < 4 || l+len(strconv.Itoa(p.countryCode)) > 15 {
+		return false
+	}
+	if p.countryCode == 1 {
+		// Area codes and exchange codes of the North American Numbering Plan
+		// never begin with a 0 or 1.
+		return l == 10 && p.nationalNumber[0] >= '2' && p.nationalNumber[3] >= '2'
+	}
+	lengths, known := phoneNationalLengths[p.region]
+	if !known {
+		return true
+	}
+	for _, known := range lengths {
+		if l == known {
+			return true
+		}
+	}
+	return false
+}
+
+func phoneTrunkPrefix(region string) string {
+	switch region {
+	case "RU", "KZ":
+		return "8"
+	}
+	if phoneRegionCodes[region] == 1 {
+		return "1"
+	}
+	if _, none := phoneNoTrunkPrefix[region]; none {
+		return ""
+	}
+	return "0"
+}
+
+// Regions where numbers in national format have no trunk prefix, or where a
+// leading zero is part of the national number.
+var phoneNoTrunkPrefix = map[string]struct{}{
+	"AD": {}, "BZ": {}, "CL": {}, "CO": {}, "CR": {}, "CZ": {}, "DK": {}, "EE": {},
+	"ES": {}, "GR": {}, "GT": {}, "HK": {}, "HN": {}, "IS": {}, "IT": {}, "LU": {},
+	"LV": {}, "MC": {}, "MO": {}, "MT": {}, "MX": {}, "NO": {}, "PA": {}, "PL": {},
+	"PT": {}, "QA": {}, "SG": {}, "SK": {}, "SM": {}, "SV": {}, "UY": {}, "VA": {},
+}
+
+// The known lengths of national numbers of regions, used in order to determine
+// whether a number is valid.
+var phoneNationalLengths = map[string][]int{
+	"AE": {8, 9}, "AR": {10}, "AT": {4, 5, 6, 7, 8, 9, 10, 11, 12, 13}, "AU": {9},
+	"BE": {8, 9}, "BR": {10, 11}, "CH": {9}, "CL": {9}, "CN": {7, 8, 9, 10, 11, 12},
+	"CO": {10}, "CZ": {9}, "DE": {6, 7, 8, 9, 10, 11, 12, 13}, "DK": {8}, "EG": {9, 10},
+	"ES": {9}, "FI": {5, 6, 7, 8, 9, 10, 11, 12}, "FR": {9}, "GB": {7, 9, 10}, "GR": {10},
+	"HK": {8}, "ID": {9, 10, 11, 12}, "IE": {7, 8, 9}, "IL": {8, 9}, "IN": {10},
+	"IT": {6, 7, 8, 9, 10, 11}, "JP": {9, 10}, "KR": {8, 9, 10}, "KZ": {10}, "MX": {10},
+	"MY": {9, 10}, "NG": {8, 10}, "NL": {9}, "NO": {8}, "NZ": {8, 9, 10}, "PE": {8, 9},
+	"PH": {10}, "PK": {9, 10}, "PL": {9}, "PT": {9}, "RU": {10}, "SA": {9}, "SE": {7, 8, 9, 10},
+	"SG": {8}, "TH": {8, 9}, "TR": {10}, "UA": {9}, "VN": {9, 10}, "ZA": {9},
+}
+
+// Country calling codes and the primary region of each code.
+var phoneCodeRegions = map[int]string{
+	1: "US", 7: "RU", 20: "EG", 27: "ZA", 30: "GR", 31: "NL", 32: "BE", 33: "FR",
+	34: "ES", 36: "HU", 39: "IT", 40: "RO", 41: "CH", 43: "AT", 44: "GB", 45: "DK",
+	46: "SE", 47: "NO", 48: "PL", 49: "DE", 51: "PE", 52: "MX", 53: "CU", 54: "AR",
+	55: "BR", 56: "CL", 57: "CO", 58: "VE", 60: "MY", 61: "AU", 62: "ID", 63: "PH",
+	64: "NZ", 65: "SG", 66: "TH", 81: "JP", 82: "KR", 84: "VN", 86: "CN", 90: "TR",
+	91: "IN", 92: "PK", 93: "AF", 94: "LK", 95: "MM", 98: "IR", 211: "SS", 212: "MA",
+	213: "DZ", 216: "TN", 218: "LY", 220: "GM", 221: "SN", 222: "MR", 223: "ML",
+	224: "GN", 225: "CI", 226: "BF", 227: "NE", 228: "TG", 229: "BJ", 230: "MU",
+	231: "LR", 232: "SL", 233: "GH", 234: "NG", 235: "TD", 236: "CF", 237: "CM",
+	238: "CV", 239: "ST", 240: "GQ", 241: "GA", 242: "CG", 243: "CD", 244: "AO",
+	245: "GW", 246: "IO", 248: "SC", 249: "SD", 250: "RW", 251: "ET", 252: "SO",
+	253: "DJ", 254: "KE", 255: "TZ", 256: "UG", 257: "BI", 258: "MZ", 260: "ZM",
+	261: "MG", 262: "RE", 263: "ZW", 264: "NA", 265: "MW", 266: "LS", 267: "BW",
+	268: "SZ", 269: "KM", 290: "SH", 291: "ER", 297: "AW", 298: "FO", 299: "GL",
+	350: "GI", 351: "PT", 352: "LU", 353: "IE", 354: "IS", 355: "AL", 356: "MT",
+	357: "CY", 358: "FI", 359: "BG", 370: "LT", 371: "LV", 372: "EE", 373: "MD",
+	374: "AM", 375: "BY", 376: "AD", 377: "MC", 378: "SM", 380: "UA", 381: "RS",
+	382: "ME", 383: "XK", 385: "HR", 386: "SI", 387: "BA", 389: "MK", 420: "CZ",
+	421: "SK", 423: "LI", 500: "FK", 501: "BZ", 502: "GT", 503: "SV", 504: "HN",
+	505: "NI", 506: "CR", 507: "PA", 508: "PM", 509: "HT", 590: "GP", 591: "BO",
+	592: "GY", 593: "EC", 594: "GF", 595: "PY", 596: "MQ", 597: "SR", 598: "UY",
+	599: "CW", 670: "TL", 672: "NF", 673: "BN", 674: "NR", 675: "PG", 676: "TO",
+	677: "SB", 678: "VU", 679: "FJ", 680: "PW", 681: "WF", 682: "CK", 683: "NU",
+	685: "WS", 686: "KI", 687: "NC", 688: "TV", 689: "PF", 690: "TK", 691: "FM",
+	692: "MH", 850: "KP", 852: "HK", 853: "MO", 855: "KH", 856: "LA", 880: "BD",
+	886: "TW", 960: "MV", 961: "LB", 962: "JO", 963: "SY", 964: "IQ", 965: "KW",
+	966: "SA", 967: "YE", 968: "OM", 970: "PS", 971: "AE", 972: "IL", 973: "BH",
+	974: "QA", 975: "BT", 976: "MN", 977: "NP", 992: "TJ", 993: "TM", 994: "AZ",
+	995: "GE", 996: "KG", 998: "UZ",
+}
+
+// Regions that share a country calling code with another primary region.
+var phoneSharedCodeRegions = map[string]int{
+	"CA": 1, "AG": 1, "AI": 1, "AS": 1, "BB": 1, "BM": 1, "BS": 1, "DM": 1, "DO": 1,
+	"GD": 1, "GU": 1, "JM": 1, "KN": 1, "KY": 1, "LC": 1, "MP": 1, "MS": 1, "PR": 1,
+	"SX": 1, "TC": 1, "TT": 1, "VC": 1, "VG": 1, "VI": 1, "KZ": 7, "GG": 44, "IM": 44,
+	"JE": 44, "SJ": 47, "CX": 61, "CC": 61, "YT": 262, "BL": 590, "MF": 590, "BQ": 599,
+	"EH": 212, "AX": 358, "VA": 39,
+}
+
+// Country calling codes of each region.
+var phoneRegionCodes = func() map[string]int {
+	codes := make(map[string]int, len(phoneCodeRegions)+len(phoneSharedCodeRegions))
+	for code, region := range phoneCodeRegions {
+		codes[region] = code
+	}
+	for region, code := range phoneSharedCodeRegions {
+		codes[region] = code
+	}
+	return codes
+}()
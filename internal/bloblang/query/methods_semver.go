@@ -0,0 +1,422 @@
+package query
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+var _ = registerSimpleMethod(
+	NewMethodSpec(
+		"parse_semver", "",
+	).InCategory(
+		MethodCategoryParsing,
+		"Attempts to parse a string as a [semantic version](https://semver.org/) and returns an object containing the fields `major`, `minor` and `patch` as integers, and `prerelease` and `build` as strings, which are empty when not present. A leading `v` is permitted.",
+		NewExampleSpec("",
+			`root.version = this.version.parse_semver()`,
+			`{"version":"v1.4.2-beta.1+exp.sha.5114f85"}`,
+			`{"version":{"build":"exp.sha.5114f85","major":1,"minor":4,"patch":2,"prerelease":"beta.1"}}`,
+		),
+	),
+	func(*ParsedParams) (simpleMethod, error) {
+		return stringMethod(func(s string) (interface{}, error) {
+			v, err := parseSemver(s)
+			if err != nil {
+				return nil, err
+			}
+			return map[string]interface{}{
+				"major":      v.major,
+				"minor":      v.minor,
+				"patch":      v.patch,
+				"prerelease": strings.Join(v.prerelease, "."),
+				"build":      v.build,
+			}, nil
+		}), nil
+	},
+)
+
+var _ = registerSimpleMethod(
+	NewMethodSpec(
+		"semver_compare", "",
+	).InCategory(
+		MethodCategoryStrings,
+		"Compares a string [semantic version](https://semver.org/) with another following the precedence rules of the specification, and returns `-1` if the target is lower than the argument, `0` if they are equal and `1` if the target is greater. Build metadata is ignored.",
+		NewExampleSpec("",
+			`root.is_outdated = this.version.semver_compare("2.1.0") < 0`,
+			`{"version":"2.0.11"}`,
+			`{"is_outdated":true}`,
+			`{"version":"2.1.0-rc.1"}`,
+			`{"is_outdated":true}`,
+			`{"version":"v2.10.0"}`,
+			`{"is_outdated":false}`,
+		),
+	).Param(ParamString("other", "The version to compare against.")),
+	func(args *ParsedParams) (simpleMethod, error) {
+		otherStr, err := args.FieldString("other")
+		if err != nil {
+			return nil, err
+		}
+		other, err := parseSemver(otherStr)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse argument: %w", err)
+		}
+		return stringMethod(func(s string) (interface{}, error) {
+			v, err := parseSemver(s)
+			if err != nil {
+				return nil, err
+			}
+			return int64(v.compare(other)), nil
+		}), nil
+	},
+)
+
+var _ = registerSimpleMethod(
+	NewMethodSpec(
+		"semver_matches", "",
+	).InCategory(
+		MethodCategoryStrings,
+		"Checks whether a string [semantic version](https://semver.org/) satisfies a constraint and returns a boolean. Constraints consist of one or more comparisons separated by spaces or commas, all of which must be satisfied, and alternative sets of comparisons can be separated with `||`.\n\nSupported operators are `=`, `!=`, `>`, `>=`, `<` and `<=`, as well as `^` (changes that do not modify the left-most non-zero version number) and `~` (patch level changes when a minor version is specified). Versions within a constraint may be partial (`1.2`) or use the wildcards `x` and `*` (`1.2.x`). Pre-release versions only satisfy a constraint when one of its comparisons also specifies a pre-release version.",
+		NewExampleSpec("",
+			`root.supported = this.client_version.semver_matches("^1.2 || >=2.4.1, <3")`,
+			`{"client_version":"1.9.0"}`,
+			`{"supported":true}`,
+			`{"client_version":"2.0.3"}`,
+			`{"supported":false}`,
+			`{"client_version":"2.5.0"}`,
+			`{"supported":true}`,
+		),
+	).Param(ParamString("constraint", "The constraint to check against.")),
+	func(args *ParsedParams) (simpleMethod, error) {
+		constraintStr, err := args.FieldString("constraint")
+		if err != nil {
+			return nil, err
+		}
+		constraint, err := parseSemverConstraint(constraintStr)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse constraint: %w", err)
+		}
+		return stringMethod(func(s string) (interface{}, error) {
+			v, err := parseSemver(s)
+			if err != nil {
+				return nil, err
+			}
+			return constraint.matches(v), nil
+		}), nil
+	},
+)
+
+//------------------------------------------------------------------------------
+
+type semver struct {
+	major, minor, patch int64
+	prerelease          []string
+	build               string
+}
+
+func parseSemverNumber(s, name string) (int64, error) {
+	if s == "" {
+		return 0, fmt.Errorf("%v version is empty", name)
+	}
+	if len(s) > 1 && s[0] == '0' {
+		return 0, fmt.Errorf("%v version must not contain leading zeros: %v", name, s)
+	}
+	n, err := strconv.ParseUint(s, 10, 63)
+	if err != nil {
+		return 0, fmt.Errorf("%v version is not a valid number: %v", name, s)
+	}
+	return int64(n), nil
+}
+
+func isSemverIdentifier(s string, numeric bool) bool {
+	if s == "" {
+		return false
+	}
+	for _, c := range s {
+		if (c < '0' || c > '9') && (numeric || (c < 'a' || c > 'z') && (c < 'A' || c > 'Z') && c != '-') {
+			return false
+		}
+	}
+	return true
+}
+
+func parseSemver(s string) (v semver, err error) {
+	str := strings.TrimPrefix(strings.TrimSpace(s), "v")
+	if i := strings.IndexByte(str, '+'); i >= 0 {
+		v.build = str[i+1:]
+		for _, ident := range strings.Split(v.build, ".") {
+			if !isSemverIdentifier(ident, false) {
+				return v, fmt.Errorf("invalid semantic version %q: invalid build metadata", s)
+			}
+		}
+		str = str[:i]
+	}
+	if i := strings.IndexByte(str, '-'); i >= 0 {
+		for _, ident := range strings.Split(str[i+1:], ".") {
+			if !isSemverIdentifier(ident, false) {
+				return v, fmt.Errorf("invalid semantic version %q: invalid pre-release", s)
+			}
+			if isSemverIdentifier(ident, true) && len(ident) > 1 && ident[0] == '0' {
+				return v, fmt.Errorf("invalid semantic version %q: pre-release must not contain leading zeros", s)
+			}
+			v.prerelease = append(v.prerelease, ident)
+		}
+		str = str[:i]
+	}
+	parts := strings.Split(str, ".")
+	if len(parts) != 3 {
+		return v, fmt.Errorf("invalid semantic version %q: expected three version numbers", s)
+	}
+	if v.major, err = parseSemverNumber(parts[0], "major"); err != nil {
+		return v, fmt.Errorf("invalid semantic version %q: %w", s, err)
+	}
+	if v.minor, err = parseSemverNumber(parts[1], "minor"); err != nil {
+		return v, fmt.Errorf("invalid semantic version %q: %w", s, err)
+	}
+	if v.patch, err = parseSemverNumber(parts[2], "patch"); err != nil {
+		return v, fmt.Errorf("invalid semantic version %q: %w", s, err)
+	}
+	return v, nil
+}
+
+func compareInt64(a, b int64) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	}
+	return 0
+}
+
+func (v semver) compare(o semver) int {
+	if c := compareInt64(v.major, o.major); c != 0 {
+		return c
+	}
+	if c := compareInt64(v.minor, o.minor); c != 0 {
+		return c
+	}
+	if c := compareInt64(v.patch, o.patch); c != 0 {
+		return c
+	}
+
+	// A version without a pre-release has a higher precedence.
+	switch {
+	case len(v.prerelease) == 0 && len(o.prerelease) == 0:
+		return 0
+	case len(v.prerelease) == 0:
+		return 1
+	case len(o.prerelease) == 0:
+		return -1
+	}
+
+	for i := 0; i < len(v.prerelease) && i < len(o.prerelease); i++ {
+		a, b := v.prerelease[i], o.prerelease[i]
+		aNum, bNum := isSemverIdentifier(a, true), isSemverIdentifier(b, true)
+		switch {
+		case aNum && bNum:
+			an, _ := strconv.ParseInt(a, 10, 64)
+			bn, _ := strconv.ParseInt(b, 10, 64)
+			if c := compareInt64(an, bn); c != 0 {
+				return c
+			}
+		case aNum:
+			return -1
+		case bNum:
+			return 1
+		default:
+			if c := strings.Compare(a, b); c != 0 {
+				return c
+			}
+		}
+	}
+	return compareInt64(int64(len(v.prerelease)), int64(len(o.prerelease)))
+}
+
+//------------------------------------------------------------------------------
+
+type semverComparator struct {
+	op      string
+	version semver
+}
+
+func (c semverComparator) matches(v semver) bool {
+	cmp := v.compare(c.version)
+	switch c.op {
+	case "!=":
+		return cmp != 0
+	case ">":
+		return cmp > 0
+	case ">=":
+		return cmp >= 0
+	case "<":
+		return cmp < 0
+	case "<=":
+		return cmp <= 0
+	}
+	return cmp == 0
+}
+
+type semverConstraint [][]semverComparator
+
+func (c semverConstraint) matches(v semver) bool {
+	for _, group := range c {
+		if semverGroupMatches(group, v) {
+			return true
+		}
+	}
+	return false
+}
+
+func semverGroupMatches(group []semverComparator, v semver) bool {
+	allowPrerelease := len(v.prerelease) == 0
+	for _, comp := range group {
+		if !comp.matches(v) {
+			return false
+		}
+		cv := comp.version
+		if len(cv.prerelease) > 0 && cv.major == v.major && cv.minor == v.minor && cv.patch == v.patch {
+			allowPrerelease = true
+		}
+	}
+	return allowPrerelease
+}
+
+func parseSemverConstraint(s string) (semverConstraint, error) {
+	var constraint semverConstraint
+	for _, groupStr := range strings.Split(s, "||") {
+		var group []semverComparator
+		fields := strings.FieldsFunc(groupStr, func(r rune) bool {
+			return r == ' ' || r == ','
+		})
+		for i := 0; i < len(fields); i++ {
+			term := fields[i]
+			// Allow whitespace between an operator and its version.
+			if strings.Trim(term, "=!<>^~") == "" && i+1 < len(fields) {
+				i++
+				term += fields[i]
+			}
+			comps, err := parseSemverComparator(term)
+			if err != nil {
+				return nil, err
+			}
+			group = append(group, comps...)
+		}
+		constraint = append(constraint, group)
+	}
+	return constraint, nil
+}
+
+func isSemverWildcard(s string) bool {
+	return s == "x" || s == "X" || s == "*"
+}
+
+func parseSemverComparator(term string) ([]semverComparator, error) {
+	opEnd := 0
+	for opEnd < len(term) && strings.ContainsRune("=!<>^~", rune(term[opEnd])) {
+		opEnd++
+	}
+	op, versionStr := term[:opEnd], strings.TrimPrefix(term[opEnd:], "v")
+	switch op {
+	case "", "=", "!=", ">", ">=", "<", "<=", "^", "~":
+	default:
+		return nil, fmt.Errorf("unrecognised operator %q", op)
+	}
+
+	var prerelease []string
+	if i := strings.IndexByte(versionStr, '+'); i >= 0 {
+		versionStr = versionStr[:i]
+	}
+	if i := strings.IndexByte(versionStr, '-'); i >= 0 {
+		prerelease = strings.Split(versionStr[i+1:], ".")
+		versionStr = versionStr[:i]
+	}
+
+	parts := strings.Split(versionStr, ".")
+	if len(parts) > 3 {
+		return nil, fmt.Errorf("invalid version %q", term[opEnd:])
+	}
+	var nums [3]int64
+	specified := 0
+	for i, p := range parts {
+		if isSemverWildcard(p) {
+			break
+		}
+		if i == 0 && p == "" && len(parts) == 1 {
+			break
+		}
+		n, err := parseSemverNumber(p, [3]string{"major", "minor", "patch"}[i])
+		if err != nil {
+			return nil, fmt.Errorf("invalid version %q: %w", term[opEnd:], err)
+		}
+		nums[i] = n
+		specified++
+	}
+	if specified < 3 && len(prerelease) > 0 {
+		return nil, errors.New("pre-release versions must specify major, minor and patch versions")
+	}
+
+	lower := semver{major: nums[0], minor: nums[1], patch: nums[2], prerelease: prerelease}
+	if specified == 0 {
+		switch op {
+		case "", "=", ">=", "<=", "^", "~":
+			return nil, nil
+		}
+		// Nothing can be greater than, lower than or not equal to any version.
+		return []semverComparator{{op: "<", version: semver{}}, {op: ">", version: semver{}}}, nil
+	}
+
+	// The upper bound (exclusive) of a partial version, e.g. 1.2 -> 1.3.0.
+	upperOf := func(n int) semver {
+		switch n {
+		case 1:
+			return semver{major: nums[0] + 1}
+		case 2:
+			return semver{major: nums[0], minor: nums[1] + 1}
+		}
+		return semver{major: nums[0], minor: nums[1], patch: nums[2] + 1}
+	}
+
+	switch op {
+	case "", "=":
+		if specified == 3 {
+			return []semverComparator{{op: "=", version: lower}}, nil
+		}
+		return []semverComparator{{op: ">=", version: lower}, {op: "<", version: upperOf(specified)}}, nil
+	case "!=":
+		if specified == 3 {
+			return []semverComparator{{op: "!=", version: lower}}, nil
+		}
+		return nil, fmt.Errorf("operator != requires a full version, got %q", term[opEnd:])
+	case ">":
+		if specified == 3 {
+			return []semverComparator{{op: ">", version: lower}}, nil
+		}
+		return []semverComparator{{op: ">=", version: upperOf(specified)}}, nil
+	case ">=":
+		return []semverComparator{{op: ">=", version: lower}}, nil
+	case "<":
+		return []semverComparator{{op: "<", version: lower}}, nil
+	case "<=":
+		if specified == 3 {
+			return []semverComparator{{op: "<=", version: lower}}, nil
+		}
+		return []semverComparator{{op: "<", version: upperOf(specified)}}, nil
+	case "~":
+		if specified == 1 {
+			return []semverComparator{{op: ">=", version: lower}, {op: "<", version: upperOf(1)}}, nil
+		}
+		return []semverComparator{{op: ">=", version: lower}, {op: "<", version: upperOf(2)}}, nil
+	}
+
+	// Caret ranges allow changes that do not modify the left-most non-zero
+	// version number.
+	upper := upperOf(specified)
+	switch {
+	case nums[0] > 0 || specified == 1:
+		upper = upperOf(1)
+	case nums[1] > 0 || specified == 2:
+		upper = upperOf(2)
+	}
+	return []semverComparator{{op: ">=", version: lower}, {op: "<", version: upper}}, nil
+}
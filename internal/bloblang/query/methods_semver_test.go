@@ -0,0 +1,144 @@
+package query
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSemverParse(t *testing.T) {
+	for _, bad := range []string{
+		"", "1", "1.2", "1.2.3.4", "01.2.3", "1.2.03", "1.2.3-", "1.2.3-01", "1.2.3+", "1.2.3-a..b", "a.b.c", "-1.2.3",
+	} {
+		_, err := parseSemver(bad)
+		assert.Error(t, err, bad)
+	}
+
+	v, err := parseSemver("v10.20.30-rc.1+build.5")
+	require.NoError(t, err)
+	assert.Equal(t, semver{
+		major: 10, minor: 20, patch: 30,
+		prerelease: []string{"rc", "1"},
+		build:      "build.5",
+	}, v)
+}
+
+func TestSemverPrecedence(t *testing.T) {
+	// Taken from the precedence example of the semver 2.0.0 specification.
+	ordered := []string{
+		"1.0.0-alpha",
+		"1.0.0-alpha.1",
+		"1.0.0-alpha.beta",
+		"1.0.0-beta",
+		"1.0.0-beta.2",
+		"1.0.0-beta.11",
+		"1.0.0-rc.1",
+		"1.0.0",
+		"1.0.1",
+		"1.1.0",
+		"2.0.0",
+	}
+	for i := 0; i < len(ordered)-1; i++ {
+		a, err := parseSemver(ordered[i])
+		require.NoError(t, err)
+		b, err := parseSemver(ordered[i+1])
+		require.NoError(t, err)
+		assert.Equal(t, -1, a.compare(b), "%v < %v", ordered[i], ordered[i+1])
+		assert.Equal(t, 1, b.compare(a), "%v > %v", ordered[i+1], ordered[i])
+	}
+
+	a, err := parseSemver("1.0.0+foo")
+	require.NoError(t, err)
+	b, err := parseSemver("1.0.0+bar")
+	require.NoError(t, err)
+	assert.Equal(t, 0, a.compare(b))
+}
+
+func TestSemverConstraints(t *testing.T) {
+	tests := []struct {
+		constraint string
+		matches    []string
+		misses     []string
+	}{
+		{
+			constraint: "^1.2",
+			matches:    []string{"1.2.0", "1.9.9"},
+			misses:     []string{"1.1.9", "2.0.0", "2.0.0-alpha", "1.5.0-beta"},
+		},
+		{
+			constraint: "^0.2.3",
+			matches:    []string{"0.2.3", "0.2.9"},
+			misses:     []string{"0.2.2", "0.3.0"},
+		},
+		{
+			constraint: "^0.0.3",
+			matches:    []string{"0.0.3"},
+			misses:     []string{"0.0.4"},
+		},
+		{
+			constraint: "~1.2.3",
+			matches:    []string{"1.2.3", "1.2.10"},
+			misses:     []string{"1.3.0", "1.2.2"},
+		},
+		{
+			constraint: "~1",
+			matches:    []string{"1.0.0", "1.9.0"},
+			misses:     []string{"2.0.0"},
+		},
+		{
+			constraint: "1.2.x",
+			matches:    []string{"1.2.0", "1.2.99"},
+			misses:     []string{"1.3.0"},
+		},
+		{
+			constraint: "> 1.2, <= 2",
+			matches:    []string{"1.3.0", "2.9.9"},
+			misses:     []string{"1.2.5", "3.0.0"},
+		},
+		{
+			constraint: "!=1.2.3",
+			matches:    []string{"1.2.4"},
+			misses:     []string{"1.2.3"},
+		},
+		{
+			constraint: ">=1.2.3-beta.2 <1.3",
+			matches:    []string{"1.2.3-beta.2", "1.2.3-rc.1", "1.2.3"},
+			misses:     []string{"1.2.3-beta.1", "1.2.4-rc.1"},
+		},
+		{
+			constraint: "<1 || >=3",
+			matches:    []string{"0.9.0", "3.0.0"},
+			misses:     []string{"1.0.0", "2.9.9"},
+		},
+		{
+			constraint: "*",
+			matches:    []string{"0.0.1", "10.0.0"},
+			misses:     []string{"1.0.0-alpha"},
+		},
+		{
+			constraint: ">*",
+			misses:     []string{"1.0.0"},
+		},
+	}
+
+	for _, test := range tests {
+		c, err := parseSemverConstraint(test.constraint)
+		require.NoError(t, err, test.constraint)
+		for _, m := range test.matches {
+			v, err := parseSemver(m)
+			require.NoError(t, err)
+			assert.True(t, c.matches(v), "%v should match %v", m, test.constraint)
+		}
+		for _, m := range test.misses {
+			v, err := parseSemver(m)
+			require.NoError(t, err)
+			assert.False(t, c.matches(v), "%v should not match %v", m, test.constraint)
+		}
+	}
+
+	for _, bad := range []string{"=>1.2.3", "1.2.3.4", "^1.x-beta", "!=1.2", "1.a"} {
+		_, err := parseSemverConstraint(bad)
+		assert.Error(t, err, bad)
+	}
+}
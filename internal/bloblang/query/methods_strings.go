@@ -24,12 +24,15 @@ import (
 	"strconv"
 	"strings"
 	"time"
+	"unicode"
+	"unicode/utf8"
 
 	"github.com/Jeffail/benthos/v3/internal/xml"
 	"github.com/OneOfOne/xxhash"
 	"github.com/itchyny/timefmt-go"
 	"github.com/microcosm-cc/bluemonday"
 	"github.com/tilinna/z85"
+	"golang.org/x/text/unicode/norm"
 	"gopkg.in/yaml.v3"
 )
 
@@ -44,7 +47,7 @@ var _ = registerSimpleMethod(
 			`{"name":"foobar bazson"}`,
 			`{"first_byte":102}`,
 		),
-	),
+	).Returns(ValueBytes),
 	func(*ParsedParams) (simpleMethod, error) {
 		return func(v interface{}, ctx FunctionContext) (interface{}, error) {
 			return IToBytes(v), nil
@@ -65,7 +68,7 @@ var _ = registerSimpleMethod(
 			`{"title":"the foo bar"}`,
 			`{"title":"The Foo Bar"}`,
 		),
-	),
+	).AppliesTo(ValueString, ValueBytes),
 	func(*ParsedParams) (simpleMethod, error) {
 		return func(v interface{}, ctx FunctionContext) (interface{}, error) {
 			switch t := v.(type) {
@@ -199,7 +202,7 @@ var _ = registerSimpleMethod(
 			"{\"encoded\":\"FD,B0+DGm>FDl80Ci\\\"A>F`)8BEckl6F`M&(+Cno&@/\"}",
 			`this is totally unstructured data`,
 		),
-	).Param(ParamString("scheme", "The decoding scheme to use.")),
+	).Param(ParamString("scheme", "The decoding scheme to use.")).AppliesTo(ValueString, ValueBytes),
 	func(args *ParsedParams) (simpleMethod, error) {
 		schemeStr, err := args.FieldString("scheme")
 		if err != nil {
@@ -276,7 +279,8 @@ root.encrypted = this.value.encrypt_aes("ctr", $key, $vector).encode("hex")`,
 	).
 		Param(ParamString("scheme", "The scheme to use for encryption, one of `ctr`, `ofb`, `cbc`.")).
 		Param(ParamString("key", "A key to encrypt with.")).
-		Param(ParamString("iv", "An initialization vector / nonce.")),
+		Param(ParamString("iv", "An initialization vector / nonce.")).
+		AppliesTo(ValueString, ValueBytes),
 	func(args *ParsedParams) (simpleMethod, error) {
 		schemeStr, err := args.FieldString("scheme")
 		if err != nil {
@@ -363,7 +367,8 @@ root.decrypted = this.value.decode("hex").decrypt_aes("ctr", $key, $vector).stri
 	).
 		Param(ParamString("scheme", "The scheme to use for decryption, one of `ctr`, `ofb`, `cbc`.")).
 		Param(ParamString("key", "A key to decrypt with.")).
-		Param(ParamString("iv", "An initialization vector / nonce.")),
+		Param(ParamString("iv", "An initialization vector / nonce.")).
+		AppliesTo(ValueString, ValueBytes),
 	func(args *ParsedParams) (simpleMethod, error) {
 		schemeStr, err := args.FieldString("scheme")
 		if err != nil {
@@ -469,7 +474,7 @@ var _ = registerOldParamsSimpleMethod(
 			`the cat meowed, the dog woofed`,
 			`{"index":8}`,
 		),
-	),
+	).AppliesTo(ValueString, ValueBytes).Returns(ValueNumber),
 	func(args ...interface{}) (simpleMethod, error) {
 		substring := args[0].(string)
 		return func(v interface{}, ctx FunctionContext) (interface{}, error) {
@@ -489,6 +494,375 @@ var _ = registerOldParamsSimpleMethod(
 
 //------------------------------------------------------------------------------
 
+var _ = registerSimpleMethod(
+	NewMethodSpec(
+		"levenshtein", "",
+	).InCategory(
+		MethodCategoryStrings,
+		"Returns the [Levenshtein distance](https://en.wikipedia.org/wiki/Levenshtein_distance) between a string target and an argument string, which is the minimum number of single character insertions, deletions or substitutions required to change one into the other. Characters are compared as unicode code points.",
+		NewExampleSpec("",
+			`root.distance = this.a.levenshtein(this.b)`,
+			`{"a":"kitten","b":"sitting"}`,
+			`{"distance":3}`,
+			`{"a":"flaw","b":"lawn"}`,
+			`{"distance":2}`,
+		),
+	).Param(ParamString("other", "The string to compare against.")),
+	func(args *ParsedParams) (simpleMethod, error) {
+		other, err := args.FieldString("other")
+		if err != nil {
+			return nil, err
+		}
+		otherRunes := []rune(other)
+		return stringMethod(func(s string) (interface{}, error) {
+			return int64(levenshteinDistance([]rune(s), otherRunes)), nil
+		}), nil
+	},
+)
+
+var _ = registerSimpleMethod(
+	NewMethodSpec(
+		"similarity", "",
+	).InCategory(
+		MethodCategoryStrings,
+		"Returns a normalized similarity score between a string target and an argument string as a floating point number between 0 (entirely different) and 1 (identical). The score is calculated from the [Levenshtein distance](#levenshtein) of the two strings relative to the length of the longest string.",
+		NewExampleSpec("",
+			`root.is_duplicate = this.a.similarity(this.b) >= 0.8`,
+			`{"a":"hello","b":"hallo"}`,
+			`{"is_duplicate":true}`,
+			`{"a":"hello","b":"world"}`,
+			`{"is_duplicate":false}`,
+		),
+	).Param(ParamString("other", "The string to compare against.")),
+	func(args *ParsedParams) (simpleMethod, error) {
+		other, err := args.FieldString("other")
+		if err != nil {
+			return nil, err
+		}
+		otherRunes := []rune(other)
+		return stringMethod(func(s string) (interface{}, error) {
+			sRunes := []rune(s)
+			maxLen := len(sRunes)
+			if len(otherRunes) > maxLen {
+				maxLen = len(otherRunes)
+			}
+			if maxLen == 0 {
+				return float64(1), nil
+			}
+			dist := levenshteinDistance(sRunes, otherRunes)
+			return 1 - float64(dist)/float64(maxLen), nil
+		}), nil
+	},
+)
+
+func levenshteinDistance(a, b []rune) int {
+	if len(a) < len(b) {
+		a, b = b, a
+	}
+	row := make([]int, len(b)+1)
+	for j := range row {
+		row[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		prev := row[0]
+		row[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			current := row[j]
+			row[j] = minInt(minInt(row[j]+1, row[j-1]+1), prev+cost)
+			prev = current
+		}
+	}
+	return row[len(b)]
+}
+
+func minInt(a, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
+
+//------------------------------------------------------------------------------
+
+var _ = registerSimpleMethod(
+	NewMethodSpec(
+		"soundex", "",
+	).InCategory(
+		MethodCategoryStrings,
+		"Returns the [American Soundex](https://en.wikipedia.org/wiki/Soundex) code of a string, which is a phonetic key consisting of a letter followed by three digits. Names that sound alike, such as `Robert` and `Rupert`, share the same code. Characters that are not ASCII letters are ignored, and an empty string is returned when no letters are present.",
+		NewExampleSpec("",
+			`root.key = this.name.soundex()`,
+			`{"name":"Robert"}`,
+			`{"key":"R163"}`,
+			`{"name":"Rupert"}`,
+			`{"key":"R163"}`,
+		),
+	),
+	func(*ParsedParams) (simpleMethod, error) {
+		return stringMethod(func(s string) (interface{}, error) {
+			return soundex(s), nil
+		}), nil
+	},
+)
+
+func soundexCode(r rune) byte {
+	switch r {
+	case 'B', 'F', 'P', 'V':
+		return '1'
+	case 'C', 'G', 'J', 'K', 'Q', 'S', 'X', 'Z':
+		return '2'
+	case 'D', 'T':
+		return '3'
+	case 'L':
+		return '4'
+	case 'M', 'N':
+		return '5'
+	case 'R':
+		return '6'
+	}
+	return '0'
+}
+
+func soundex(s string) string {
+	code := make([]byte, 0, 4)
+	var last byte
+	for _, r := range strings.ToUpper(s) {
+		if r < 'A' || r > 'Z' {
+			continue
+		}
+		c := soundexCode(r)
+		if len(code) == 0 {
+			code = append(code, byte(r))
+			last = c
+			continue
+		}
+		if r == 'H' || r == 'W' {
+			// H and W do not separate letters with the same code.
+			continue
+		}
+		if c != '0' && c != last {
+			code = append(code, c)
+			if len(code) == 4 {
+				break
+			}
+		}
+		last = c
+	}
+	if len(code) == 0 {
+		return ""
+	}
+	for len(code) < 4 {
+		code = append(code, '0')
+	}
+	return string(code)
+}
+
+//------------------------------------------------------------------------------
+
+var _ = registerSimpleMethod(
+	NewMethodSpec(
+		"metaphone", "",
+	).InCategory(
+		MethodCategoryStrings,
+		"Returns the [Metaphone](https://en.wikipedia.org/wiki/Metaphone) phonetic key of a string, which is useful for matching words that sound alike but are spelled differently. Each whitespace separated word of the string is encoded individually and the resulting keys are joined with a single space. Characters that are not ASCII letters are ignored.",
+		NewExampleSpec("",
+			`root.key = this.name.metaphone()`,
+			`{"name":"Catherine"}`,
+			`{"key":"K0RN"}`,
+			`{"name":"Kathryn"}`,
+			`{"key":"K0RN"}`,
+		),
+		NewExampleSpec("",
+			`root.key = this.name.metaphone()`,
+			`{"name":"Philip Wright"}`,
+			`{"key":"FLP RT"}`,
+		),
+	),
+	func(*ParsedParams) (simpleMethod, error) {
+		return stringMethod(func(s string) (interface{}, error) {
+			words := strings.Fields(s)
+			keys := make([]string, 0, len(words))
+			for _, w := range words {
+				if k := metaphone(w); len(k) > 0 {
+					keys = append(keys, k)
+				}
+			}
+			return strings.Join(keys, " "), nil
+		}), nil
+	},
+)
+
+func metaphone(word string) string {
+	w := make([]byte, 0, len(word))
+	for _, r := range strings.ToUpper(word) {
+		if r >= 'A' && r <= 'Z' {
+			w = append(w, byte(r))
+		}
+	}
+	if len(w) == 0 {
+		return ""
+	}
+	if len(w) == 1 {
+		return string(w)
+	}
+
+	// Initial letter exceptions.
+	switch {
+	case (w[0] == 'K' || w[0] == 'G' || w[0] == 'P') && w[1] == 'N',
+		w[0] == 'A' && w[1] == 'E',
+		w[0] == 'W' && w[1] == 'R':
+		w = w[1:]
+	case w[0] == 'W' && w[1] == 'H':
+		w = w[1:]
+		w[0] = 'W'
+	case w[0] == 'X':
+		w[0] = 'S'
+	}
+
+	size := len(w)
+	at := func(i int) byte {
+		if i < 0 || i >= size {
+			return 0
+		}
+		return w[i]
+	}
+	isVowel := func(i int) bool {
+		switch at(i) {
+		case 'A', 'E', 'I', 'O', 'U':
+			return true
+		}
+		return false
+	}
+	isFrontVowel := func(i int) bool {
+		switch at(i) {
+		case 'E', 'I', 'Y':
+			return true
+		}
+		return false
+	}
+	matches := func(i int, s string) bool {
+		return i+len(s) <= size && string(w[i:i+len(s)]) == s
+	}
+	isLast := func(i int) bool {
+		return i == size-1
+	}
+
+	var code []byte
+	for n := 0; n < size; n++ {
+		symb := w[n]
+		if symb != 'C' && at(n-1) == symb {
+			continue
+		}
+		switch symb {
+		case 'A', 'E', 'I', 'O', 'U':
+			if n == 0 {
+				code = append(code, symb)
+			}
+		case 'B':
+			if !(at(n-1) == 'M' && isLast(n)) {
+				code = append(code, 'B')
+			}
+		case 'C':
+			switch {
+			case at(n-1) == 'S' && isFrontVowel(n+1):
+				// Silent in SCI, SCE and SCY.
+			case matches(n, "CIA"):
+				code = append(code, 'X')
+			case isFrontVowel(n + 1):
+				code = append(code, 'S')
+			case at(n-1) == 'S' && at(n+1) == 'H':
+				code = append(code, 'K')
+			case at(n+1) == 'H':
+				if n == 0 && size >= 3 && isVowel(2) {
+					code = append(code, 'K')
+				} else {
+					code = append(code, 'X')
+				}
+			default:
+				code = append(code, 'K')
+			}
+		case 'D':
+			if at(n+1) == 'G' && isFrontVowel(n+2) {
+				code = append(code, 'J')
+				n += 2
+			} else {
+				code = append(code, 'T')
+			}
+		case 'G':
+			if at(n+1) == 'H' && (isLast(n+1) || !isVowel(n+2)) {
+				break
+			}
+			if n > 0 && (matches(n, "GN") || matches(n, "GNED")) {
+				break
+			}
+			if isFrontVowel(n+1) && at(n-1) != 'G' {
+				code = append(code, 'J')
+			} else {
+				code = append(code, 'K')
+			}
+		case 'H':
+			if isLast(n) {
+				break
+			}
+			switch at(n - 1) {
+			case 'C', 'S', 'P', 'T', 'G':
+				break
+			default:
+				if isVowel(n + 1) {
+					code = append(code, 'H')
+				}
+			}
+		case 'K':
+			if at(n-1) != 'C' {
+				code = append(code, 'K')
+			}
+		case 'P':
+			if at(n+1) == 'H' {
+				code = append(code, 'F')
+			} else {
+				code = append(code, 'P')
+			}
+		case 'Q':
+			code = append(code, 'K')
+		case 'S':
+			if matches(n, "SH") || matches(n, "SIO") || matches(n, "SIA") {
+				code = append(code, 'X')
+			} else {
+				code = append(code, 'S')
+			}
+		case 'T':
+			switch {
+			case matches(n, "TIA"), matches(n, "TIO"):
+				code = append(code, 'X')
+			case matches(n, "TCH"):
+			case matches(n, "TH"):
+				code = append(code, '0')
+			default:
+				code = append(code, 'T')
+			}
+		case 'V':
+			code = append(code, 'F')
+		case 'W', 'Y':
+			if isVowel(n + 1) {
+				code = append(code, symb)
+			}
+		case 'X':
+			code = append(code, 'K', 'S')
+		case 'Z':
+			code = append(code, 'S')
+		default:
+			code = append(code, symb)
+		}
+	}
+	return string(code)
+}
+
+//------------------------------------------------------------------------------
+
 var _ = registerSimpleMethod(
 	NewMethodSpec(
 		"unescape_html", "",
@@ -552,6 +926,48 @@ var _ = registerSimpleMethod(
 
 //------------------------------------------------------------------------------
 
+var _ = registerSimpleMethod(
+	NewMethodSpec(
+		"escape_url_path", "",
+	).InCategory(
+		MethodCategoryStrings,
+		"Escapes a string so that it can be safely placed within a single segment of a URL path. Unlike `escape_url_query` spaces are escaped as `%20` and slashes are escaped.",
+		NewExampleSpec("",
+			`root.url = "https://example.com/files/" + this.name.escape_url_path()`,
+			`{"name":"a b/c.txt"}`,
+			`{"url":"https://example.com/files/a%20b%2Fc.txt"}`,
+		),
+	),
+	func(*ParsedParams) (simpleMethod, error) {
+		return stringMethod(func(s string) (interface{}, error) {
+			return url.PathEscape(s), nil
+		}), nil
+	},
+)
+
+//------------------------------------------------------------------------------
+
+var _ = registerSimpleMethod(
+	NewMethodSpec(
+		"unescape_url_path", "",
+	).InCategory(
+		MethodCategoryStrings,
+		"Expands escape sequences from a URL path segment. Unlike `unescape_url_query` plus characters are left unchanged.",
+		NewExampleSpec("",
+			`root.name = this.segment.unescape_url_path()`,
+			`{"segment":"a%20b+c%2Fd.txt"}`,
+			`{"name":"a b+c/d.txt"}`,
+		),
+	),
+	func(*ParsedParams) (simpleMethod, error) {
+		return stringMethod(func(s string) (interface{}, error) {
+			return url.PathUnescape(s)
+		}), nil
+	},
+)
+
+//------------------------------------------------------------------------------
+
 var _ = registerSimpleMethod(
 	NewMethodSpec(
 		"filepath_join", "",
@@ -641,7 +1057,7 @@ root.t2 = this.v2.has_prefix("foo")`,
 			`{"v1":"foobar","v2":"barfoo"}`,
 			`{"t1":true,"t2":false}`,
 		),
-	),
+	).AppliesTo(ValueString, ValueBytes).Returns(ValueBool),
 	func(args ...interface{}) (simpleMethod, error) {
 		prefix := args[0].(string)
 		prefixB := []byte(prefix)
@@ -674,7 +1090,7 @@ root.t2 = this.v2.has_suffix("foo")`,
 			`{"v1":"foobar","v2":"barfoo"}`,
 			`{"t1":false,"t2":true}`,
 		),
-	),
+	).AppliesTo(ValueString, ValueBytes).Returns(ValueBool),
 	func(args ...interface{}) (simpleMethod, error) {
 		prefix := args[0].(string)
 		prefixB := []byte(prefix)
@@ -695,6 +1111,109 @@ root.t2 = this.v2.has_suffix("foo")`,
 
 //------------------------------------------------------------------------------
 
+var _ = registerSimpleMethod(
+	NewMethodSpec(
+		"matches_glob", "",
+	).InCategory(
+		MethodCategoryStrings,
+		"Checks whether a string matches a glob pattern and returns a boolean. The wildcard `*` matches any sequence of characters other than `/`, `**` matches any sequence of characters including `/`, and `?` matches any single character other than `/`. Character classes such as `[abc]` and `[a-z]` are also supported, and any special character can be escaped with a backslash in order to match it literally. The pattern must match the entire string.",
+		NewExampleSpec("",
+			`root.is_log = this.path.matches_glob("/var/log/**/*.log")`,
+			`{"path":"/var/log/nginx/access.log"}`,
+			`{"is_log":true}`,
+			`{"path":"/var/log/nginx/access.log.gz"}`,
+			`{"is_log":false}`,
+		),
+		NewExampleSpec("",
+			`root.output = if this.topic.matches_glob("orders-??-*") { "regional" } else { "global" }`,
+			`{"topic":"orders-eu-created"}`,
+			`{"output":"regional"}`,
+			`{"topic":"orders-europe-created"}`,
+			`{"output":"global"}`,
+		),
+	).Param(ParamString("pattern", "The glob pattern to match against.")).AppliesTo(ValueString, ValueBytes).Returns(ValueBool),
+	func(args *ParsedParams) (simpleMethod, error) {
+		pattern, err := args.FieldString("pattern")
+		if err != nil {
+			return nil, err
+		}
+		re, err := globToRegexp(pattern)
+		if err != nil {
+			return nil, err
+		}
+		return func(v interface{}, ctx FunctionContext) (interface{}, error) {
+			switch t := v.(type) {
+			case string:
+				return re.MatchString(t), nil
+			case []byte:
+				return re.Match(t), nil
+			}
+			return nil, NewTypeError(v, ValueString)
+		}, nil
+	},
+)
+
+// globToRegexp compiles a glob pattern into an anchored regular expression.
+func globToRegexp(pattern string) (*regexp.Regexp, error) {
+	var b strings.Builder
+	b.WriteString("^")
+	runes := []rune(pattern)
+	for i := 0; i < len(runes); i++ {
+		switch r := runes[i]; r {
+		case '*':
+			if i+1 < len(runes) && runes[i+1] == '*' {
+				i++
+				if i+1 < len(runes) && runes[i+1] == '/' {
+					// A **/ segment may match zero or more directories.
+					i++
+					b.WriteString("(?:.*/)?")
+				} else {
+					b.WriteString(".*")
+				}
+			} else {
+				b.WriteString("[^/]*")
+			}
+		case '?':
+			b.WriteString("[^/]")
+		case '\\':
+			if i+1 >= len(runes) {
+				return nil, errors.New("glob pattern ends with an unterminated escape character")
+			}
+			i++
+			b.WriteString(regexp.QuoteMeta(string(runes[i])))
+		case '[':
+			end := i + 1
+			if end < len(runes) && (runes[end] == '!' || runes[end] == '^') {
+				end++
+			}
+			if end < len(runes) && runes[end] == ']' {
+				end++
+			}
+			for end < len(runes) && runes[end] != ']' {
+				end++
+			}
+			if end >= len(runes) {
+				return nil, fmt.Errorf("glob pattern contains an unterminated character class at position %v", i)
+			}
+			class := runes[i+1 : end]
+			b.WriteString("[")
+			if len(class) > 0 && class[0] == '!' {
+				b.WriteString("^")
+				class = class[1:]
+			}
+			b.WriteString(strings.ReplaceAll(string(class), "\\", "\\\\"))
+			b.WriteString("]")
+			i = end
+		default:
+			b.WriteString(regexp.QuoteMeta(string(r)))
+		}
+	}
+	b.WriteString("$")
+	return regexp.Compile(b.String())
+}
+
+//------------------------------------------------------------------------------
+
 var _ = registerOldParamsSimpleMethod(
 	NewMethodSpec(
 		"hash", "",
@@ -712,7 +1231,7 @@ root.h2 = this.value.hash("hmac_sha1","static-key").encode("hex")`,
 			`{"value":"hello world"}`,
 			`{"h1":"2aae6c35c94fcfb415dbe95f408b9ce91ee846ed","h2":"d87e5f068fa08fe90bb95bc7c8344cb809179d76"}`,
 		),
-	),
+	).AppliesTo(ValueString, ValueBytes),
 	func(args ...interface{}) (simpleMethod, error) {
 		var key []byte
 		if len(args) > 1 {
@@ -814,7 +1333,7 @@ root.joined_numbers = this.numbers.map_each(this.string()).join(",")`,
 			`{"words":["hello","world"],"numbers":[3,8,11]}`,
 			`{"joined_numbers":"3,8,11","joined_words":"helloworld"}`,
 		),
-	),
+	).AppliesTo(ValueArray).Returns(ValueString),
 	func(args ...interface{}) (simpleMethod, error) {
 		var delim string
 		if len(args) > 0 {
@@ -861,7 +1380,7 @@ var _ = registerSimpleMethod(
 			`{"foo":"hello world"}`,
 			`{"foo":"HELLO WORLD"}`,
 		),
-	),
+	).AppliesTo(ValueString, ValueBytes),
 	func(*ParsedParams) (simpleMethod, error) {
 		return func(v interface{}, ctx FunctionContext) (interface{}, error) {
 			switch t := v.(type) {
@@ -889,7 +1408,7 @@ var _ = registerSimpleMethod(
 			`{"foo":"HELLO WORLD"}`,
 			`{"foo":"hello world"}`,
 		),
-	),
+	).AppliesTo(ValueString, ValueBytes),
 	func(*ParsedParams) (simpleMethod, error) {
 		return func(v interface{}, ctx FunctionContext) (interface{}, error) {
 			switch t := v.(type) {
@@ -906,6 +1425,567 @@ var _ = registerSimpleMethod(
 
 //------------------------------------------------------------------------------
 
+var _ = registerSimpleMethod(
+	NewMethodSpec(
+		"to_snake_case", "",
+	).InCategory(
+		MethodCategoryStrings,
+		"Converts a string into snake case, where words are lowercase and separated by underscores. Word boundaries are detected from changes in letter case as well as any non-alphanumeric characters.",
+		NewExampleSpec("",
+			`root.name = this.name.to_snake_case()`,
+			`{"name":"HTTPServerName"}`,
+			`{"name":"http_server_name"}`,
+			`{"name":"user-id"}`,
+			`{"name":"user_id"}`,
+		),
+		NewExampleSpec("This method can be combined with [`map_each_key`](#map_each_key) in order to convert the naming convention of every key within an object.",
+			`root = this.map_each_key(key -> key.to_snake_case())`,
+			`{"firstName":"foo","lastName":"bar"}`,
+			`{"first_name":"foo","last_name":"bar"}`,
+		),
+	),
+	func(*ParsedParams) (simpleMethod, error) {
+		return stringMethod(func(s string) (interface{}, error) {
+			return joinCaseWords(splitCaseWords(s), "_", strings.ToLower, strings.ToLower), nil
+		}), nil
+	},
+)
+
+var _ = registerSimpleMethod(
+	NewMethodSpec(
+		"to_kebab_case", "",
+	).InCategory(
+		MethodCategoryStrings,
+		"Converts a string into kebab case, where words are lowercase and separated by hyphens. Word boundaries are detected from changes in letter case as well as any non-alphanumeric characters.",
+		NewExampleSpec("",
+			`root.name = this.name.to_kebab_case()`,
+			`{"name":"HTTPServerName"}`,
+			`{"name":"http-server-name"}`,
+			`{"name":"user_id"}`,
+			`{"name":"user-id"}`,
+		),
+	),
+	func(*ParsedParams) (simpleMethod, error) {
+		return stringMethod(func(s string) (interface{}, error) {
+			return joinCaseWords(splitCaseWords(s), "-", strings.ToLower, strings.ToLower), nil
+		}), nil
+	},
+)
+
+var _ = registerSimpleMethod(
+	NewMethodSpec(
+		"to_camel_case", "",
+	).InCategory(
+		MethodCategoryStrings,
+		"Converts a string into camel case, where the first word is lowercase and each subsequent word begins with an uppercase letter, without separators. Word boundaries are detected from changes in letter case as well as any non-alphanumeric characters.",
+		NewExampleSpec("",
+			`root.name = this.name.to_camel_case()`,
+			`{"name":"http_server_name"}`,
+			`{"name":"httpServerName"}`,
+			`{"name":"User ID"}`,
+			`{"name":"userId"}`,
+		),
+	),
+	func(*ParsedParams) (simpleMethod, error) {
+		return stringMethod(func(s string) (interface{}, error) {
+			return joinCaseWords(splitCaseWords(s), "", strings.ToLower, titleCaseWord), nil
+		}), nil
+	},
+)
+
+var _ = registerSimpleMethod(
+	NewMethodSpec(
+		"to_pascal_case", "",
+	).InCategory(
+		MethodCategoryStrings,
+		"Converts a string into pascal case, where each word begins with an uppercase letter, without separators. Word boundaries are detected from changes in letter case as well as any non-alphanumeric characters.",
+		NewExampleSpec("",
+			`root.name = this.name.to_pascal_case()`,
+			`{"name":"http_server_name"}`,
+			`{"name":"HttpServerName"}`,
+			`{"name":"user id"}`,
+			`{"name":"UserId"}`,
+		),
+	),
+	func(*ParsedParams) (simpleMethod, error) {
+		return stringMethod(func(s string) (interface{}, error) {
+			return joinCaseWords(splitCaseWords(s), "", titleCaseWord, titleCaseWord), nil
+		}), nil
+	},
+)
+
+// splitCaseWords breaks a string into words by splitting on non-alphanumeric
+// characters and on changes of case, where a run of uppercase characters
+// followed by a lowercase character (e.g. HTTPServer) is treated as an acronym
+// followed by a new word.
+func splitCaseWords(s string) []string {
+	var words []string
+	runes := []rune(s)
+	start := -1
+	for i, r := range runes {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) {
+			if start >= 0 {
+				words = append(words, string(runes[start:i]))
+				start = -1
+			}
+			continue
+		}
+		if start < 0 {
+			start = i
+			continue
+		}
+		if unicode.IsUpper(r) {
+			prev := runes[i-1]
+			if unicode.IsLower(prev) || unicode.IsDigit(prev) ||
+				(unicode.IsUpper(prev) && i+1 < len(runes) && unicode.IsLower(runes[i+1])) {
+				words = append(words, string(runes[start:i]))
+				start = i
+			}
+		}
+	}
+	if start >= 0 {
+		words = append(words, string(runes[start:]))
+	}
+	return words
+}
+
+func titleCaseWord(w string) string {
+	runes := []rune(strings.ToLower(w))
+	if len(runes) > 0 {
+		runes[0] = unicode.ToUpper(runes[0])
+	}
+	return string(runes)
+}
+
+func joinCaseWords(words []string, sep string, first, rest func(string) string) string {
+	var b strings.Builder
+	for i, w := range words {
+		if i == 0 {
+			b.WriteString(first(w))
+			continue
+		}
+		b.WriteString(sep)
+		b.WriteString(rest(w))
+	}
+	return b.String()
+}
+
+//------------------------------------------------------------------------------
+
+var _ = registerSimpleMethod(
+	NewMethodSpec(
+		"slugify", "",
+	).InCategory(
+		MethodCategoryStrings,
+		"Converts a string into a URL and file name safe identifier. The string is lowercased, unicode characters are transliterated into their closest ASCII equivalents where possible (e.g. `é` becomes `e`), and any remaining runs of characters that are not ASCII letters or digits are replaced with a single separator. Leading and trailing separators are removed.",
+		NewExampleSpec("",
+			`root.slug = this.title.slugify()`,
+			`{"title":"Héllo, Wörld! A straße café"}`,
+			`{"slug":"hello-world-a-strasse-cafe"}`,
+		),
+		NewExampleSpec("",
+			`root.file = this.name.slugify("_") + ".json"`,
+			`{"name":"  Quarterly Report (Q3)  "}`,
+			`{"file":"quarterly_report_q3.json"}`,
+		),
+	).Param(ParamString("separator", "The separator to place between words.").Default("-")),
+	func(args *ParsedParams) (simpleMethod, error) {
+		sep, err := args.FieldString("separator")
+		if err != nil {
+			return nil, err
+		}
+		return stringMethod(func(s string) (interface{}, error) {
+			return slugify(s, sep), nil
+		}), nil
+	},
+)
+
+// Characters that do not decompose into an ASCII base character and a
+// combining mark, and therefore need an explicit transliteration.
+var slugTransliterations = map[rune]string{
+	'ß': "ss", 'æ': "ae", 'œ': "oe", 'ø': "o", 'đ': "d", 'ð': "d",
+	'ł': "l", 'þ': "th", 'ı': "i", 'ħ': "h", 'ŧ': "t", 'ŋ': "n",
+}
+
+func slugify(s, sep string) string {
+	var b strings.Builder
+	pendingSep := false
+	writeRune := func(r rune) {
+		if (r >= 'a' && r <= 'z') || (r >= '0' && r <= '9') {
+			if pendingSep && b.Len() > 0 {
+				b.WriteString(sep)
+			}
+			pendingSep = false
+			b.WriteRune(r)
+			return
+		}
+		pendingSep = true
+	}
+	for _, r := range norm.NFKD.String(strings.ToLower(s)) {
+		if unicode.Is(unicode.Mn, r) {
+			continue
+		}
+		if t, exists := slugTransliterations[r]; exists {
+			for _, tr := range t {
+				writeRune(tr)
+			}
+			continue
+		}
+		writeRune(r)
+	}
+	return b.String()
+}
+
+//------------------------------------------------------------------------------
+
+var _ = registerSimpleMethod(
+	NewMethodSpec(
+		"truncate", "",
+	).InCategory(
+		MethodCategoryStrings,
+		"Truncates a string so that it contains no more than a maximum number of characters, counted as unicode code points so that multi-byte characters are never split. An optional suffix such as an ellipsis can be provided, which is appended to truncated strings and is counted towards the maximum length. Strings that are already within the limit are returned unchanged.",
+		NewExampleSpec("",
+			`root.summary = this.text.truncate(10)`,
+			`{"text":"the quick brown fox"}`,
+			`{"summary":"the quick "}`,
+			`{"text":"short"}`,
+			`{"summary":"short"}`,
+		),
+		NewExampleSpec("",
+			`root.summary = this.text.truncate(10, "…")`,
+			`{"text":"naïve café society"}`,
+			`{"summary":"naïve caf…"}`,
+		),
+	).
+		Param(ParamInt64("length", "The maximum number of characters of the resulting string.")).
+		Param(ParamString("suffix", "A suffix to append to strings that are truncated.").Default("")),
+	func(args *ParsedParams) (simpleMethod, error) {
+		length, err := args.FieldInt64("length")
+		if err != nil {
+			return nil, err
+		}
+		if length < 0 {
+			return nil, fmt.Errorf("length must be a non-negative integer, got %v", length)
+		}
+		suffix, err := args.FieldString("suffix")
+		if err != nil {
+			return nil, err
+		}
+		suffixRunes := []rune(suffix)
+		if int64(len(suffixRunes)) > length {
+			return nil, fmt.Errorf("suffix length (%v) must not exceed the maximum length (%v)", len(suffixRunes), length)
+		}
+		return stringMethod(func(s string) (interface{}, error) {
+			runes := []rune(s)
+			if int64(len(runes)) <= length {
+				return s, nil
+			}
+			keep := int(length) - len(suffixRunes)
+			return string(runes[:keep]) + suffix, nil
+		}), nil
+	},
+)
+
+//------------------------------------------------------------------------------
+
+var _ = registerSimpleMethod(
+	NewMethodSpec(
+		"word_wrap", "",
+	).InCategory(
+		MethodCategoryStrings,
+		"Wraps a string into lines of no more than a given number of characters by replacing whitespace between words with line breaks. Words that are longer than the width are placed on a line of their own rather than being split. Existing line breaks are preserved and characters are counted as unicode code points.",
+		NewExampleSpec("",
+			`root.wrapped = this.text.word_wrap(16)`,
+			`{"text":"the quick brown fox jumps over the lazy dog"}`,
+			`{"wrapped":"the quick brown\nfox jumps over\nthe lazy dog"}`,
+		),
+	).Param(ParamInt64("width", "The maximum number of characters of each line.")),
+	func(args *ParsedParams) (simpleMethod, error) {
+		width, err := args.FieldInt64("width")
+		if err != nil {
+			return nil, err
+		}
+		if width < 1 {
+			return nil, fmt.Errorf("width must be greater than zero, got %v", width)
+		}
+		return stringMethod(func(s string) (interface{}, error) {
+			return wordWrap(s, int(width)), nil
+		}), nil
+	},
+)
+
+func wordWrap(s string, width int) string {
+	var b strings.Builder
+	for i, line := range strings.Split(s, "\n") {
+		if i > 0 {
+			b.WriteByte('\n')
+		}
+		lineLen := 0
+		for _, word := range strings.Fields(line) {
+			wordLen := utf8.RuneCountInString(word)
+			if lineLen > 0 {
+				if lineLen+1+wordLen > width {
+					b.WriteByte('\n')
+					lineLen = 0
+				} else {
+					b.WriteByte(' ')
+					lineLen++
+				}
+			}
+			b.WriteString(word)
+			lineLen += wordLen
+		}
+	}
+	return b.String()
+}
+
+//------------------------------------------------------------------------------
+
+// maxStringMethodBytes is the largest string, in bytes, that the methods
+// pad_left, pad_right and repeat will produce, which prevents a single mapping
+// from exhausting the memory of the process.
+const maxStringMethodBytes = 16 * 1024 * 1024
+
+var _ = registerSimpleMethod(
+	NewMethodSpec(
+		"pad_left", "",
+	).InCategory(
+		MethodCategoryStrings,
+		"Pads the beginning of a string with a fill character until it reaches a target length, counted as unicode code points. Strings that already meet or exceed the target length are returned unchanged. If the fill argument contains multiple characters then it is repeated and cut in order to fit exactly. An error is returned if the resulting string would exceed 16MiB (16777216 bytes).",
+		NewExampleSpec("",
+			`root.id = this.id.string().pad_left(8, "0")`,
+			`{"id":1234}`,
+			`{"id":"00001234"}`,
+		),
+		NewExampleSpec("",
+			`root.name = this.name.pad_left(8)`,
+			`{"name":"foo"}`,
+			`{"name":"     foo"}`,
+		),
+	).
+		Param(ParamInt64("length", "The target length of the resulting string.")).
+		Param(ParamString("fill", "The characters to pad with.").Default(" ")),
+	func(args *ParsedParams) (simpleMethod, error) {
+		length, fill, err := padMethodArgs(args)
+		if err != nil {
+			return nil, err
+		}
+		return stringMethod(func(s string) (interface{}, error) {
+			pad, err := padding(s, length, fill)
+			if err != nil {
+				return nil, err
+			}
+			return pad + s, nil
+		}), nil
+	},
+)
+
+var _ = registerSimpleMethod(
+	NewMethodSpec(
+		"pad_right", "",
+	).InCategory(
+		MethodCategoryStrings,
+		"Pads the end of a string with a fill character until it reaches a target length, counted as unicode code points. Strings that already meet or exceed the target length are returned unchanged. If the fill argument contains multiple characters then it is repeated and cut in order to fit exactly. An error is returned if the resulting string would exceed 16MiB (16777216 bytes).",
+		NewExampleSpec("",
+			`root.record = this.code.pad_right(6, ".") + this.amount.string().pad_left(6, "0")`,
+			`{"code":"AB","amount":55}`,
+			`{"record":"AB....000055"}`,
+		),
+	).
+		Param(ParamInt64("length", "The target length of the resulting string.")).
+		Param(ParamString("fill", "The characters to pad with.").Default(" ")),
+	func(args *ParsedParams) (simpleMethod, error) {
+		length, fill, err := padMethodArgs(args)
+		if err != nil {
+			return nil, err
+		}
+		return stringMethod(func(s string) (interface{}, error) {
+			pad, err := padding(s, length, fill)
+			if err != nil {
+				return nil, err
+			}
+			return s + pad, nil
+		}), nil
+	},
+)
+
+func padMethodArgs(args *ParsedParams) (int, []rune, error) {
+	length, err := args.FieldInt64("length")
+	if err != nil {
+		return 0, nil, err
+	}
+	fill, err := args.FieldString("fill")
+	if err != nil {
+		return 0, nil, err
+	}
+	if length < 0 {
+		return 0, nil, fmt.Errorf("length must be a non-negative integer, got %v", length)
+	}
+	if length > maxStringMethodBytes {
+		return 0, nil, fmt.Errorf("length must not exceed %v, got %v", maxStringMethodBytes, length)
+	}
+	if len(fill) == 0 {
+		return 0, nil, errors.New("fill must not be empty")
+	}
+	return int(length), []rune(fill), nil
+}
+
+func padding(s string, length int, fill []rune) (string, error) {
+	n := length - utf8.RuneCountInString(s)
+	if n <= 0 {
+		return "", nil
+	}
+	fillBytes := len(string(fill))
+	padBytes := (n/len(fill))*fillBytes + len(string(fill[:n%len(fill)]))
+	if padBytes > maxStringMethodBytes-len(s) {
+		return "", fmt.Errorf("padded string would exceed %v bytes", maxStringMethodBytes)
+	}
+	var pad strings.Builder
+	pad.Grow(padBytes)
+	for i := 0; i < n; i++ {
+		pad.WriteRune(fill[i%len(fill)])
+	}
+	return pad.String(), nil
+}
+
+//------------------------------------------------------------------------------
+
+var _ = registerSimpleMethod(
+	NewMethodSpec(
+		"repeat", "",
+	).InCategory(
+		MethodCategoryStrings,
+		"Returns a string consisting of a number of copies of the target string. An error is returned if the resulting string would exceed 16MiB (16777216 bytes).",
+		NewExampleSpec("",
+			`root.divider = this.char.repeat(10)`,
+			`{"char":"="}`,
+			`{"divider":"=========="}`,
+		),
+	).Param(ParamInt64("count", "The number of copies.")),
+	func(args *ParsedParams) (simpleMethod, error) {
+		count, err := args.FieldInt64("count")
+		if err != nil {
+			return nil, err
+		}
+		if count < 0 {
+			return nil, fmt.Errorf("count must be a non-negative integer, got %v", count)
+		}
+		return stringMethod(func(s string) (interface{}, error) {
+			if len(s) > 0 && count > int64(maxStringMethodBytes/len(s)) {
+				return nil, fmt.Errorf("repeated string would exceed %v bytes", maxStringMethodBytes)
+			}
+			return strings.Repeat(s, int(count)), nil
+		}), nil
+	},
+)
+
+//------------------------------------------------------------------------------
+
+var _ = registerSimpleMethod(
+	NewMethodSpec(
+		"luhn_valid", "",
+	).InCategory(
+		MethodCategoryStrings,
+		"Checks whether a string of digits, such as a payment card number, passes the [Luhn checksum](https://en.wikipedia.org/wiki/Luhn_algorithm). Spaces and dashes are ignored, and any other non-digit character results in `false`.",
+		NewExampleSpec("",
+			`root.valid = this.card.luhn_valid()`,
+			`{"card":"4111 1111 1111 1111"}`,
+			`{"valid":true}`,
+			`{"card":"4111-1111-1111-1112"}`,
+			`{"valid":false}`,
+		),
+	),
+	func(*ParsedParams) (simpleMethod, error) {
+		return stringMethod(func(s string) (interface{}, error) {
+			return luhnValid(s), nil
+		}), nil
+	},
+)
+
+func luhnValid(s string) bool {
+	var sum, digits int
+	for i := len(s) - 1; i >= 0; i-- {
+		c := s[i]
+		if c == ' ' || c == '-' {
+			continue
+		}
+		if c < '0' || c > '9' {
+			return false
+		}
+		d := int(c - '0')
+		if digits%2 == 1 {
+			if d *= 2; d > 9 {
+				d -= 9
+			}
+		}
+		sum += d
+		digits++
+	}
+	return digits > 1 && sum%10 == 0
+}
+
+//------------------------------------------------------------------------------
+
+var _ = registerSimpleMethod(
+	NewMethodSpec(
+		"mask", "",
+	).InCategory(
+		MethodCategoryStrings,
+		"Replaces the characters of a string with a mask character, optionally keeping a number of characters at the start and end visible. If the string is not longer than the characters being kept then it is masked entirely, which prevents short values from being leaked.",
+		NewExampleSpec("",
+			`root.card = this.card.mask()`,
+			`{"card":"4111111111111111"}`,
+			`{"card":"************1111"}`,
+		),
+		NewExampleSpec("",
+			`root.card = this.card.mask(keep_first: 6, keep_last: 4, char: "#")`,
+			`{"card":"4111111111111111"}`,
+			`{"card":"411111######1111"}`,
+			`{"card":"1234"}`,
+			`{"card":"####"}`,
+		),
+	).
+		Param(ParamInt64("keep_first", "The number of characters at the start of the string to leave unmasked.").Default(0)).
+		Param(ParamInt64("keep_last", "The number of characters at the end of the string to leave unmasked.").Default(4)).
+		Param(ParamString("char", "The character to replace masked characters with.").Default("*")),
+	func(args *ParsedParams) (simpleMethod, error) {
+		keepFirst, err := args.FieldInt64("keep_first")
+		if err != nil {
+			return nil, err
+		}
+		keepLast, err := args.FieldInt64("keep_last")
+		if err != nil {
+			return nil, err
+		}
+		if keepFirst < 0 || keepLast < 0 {
+			return nil, fmt.Errorf("keep_first and keep_last must be non-negative integers, got %v and %v", keepFirst, keepLast)
+		}
+		char, err := args.FieldString("char")
+		if err != nil {
+			return nil, err
+		}
+		if char == "" {
+			return nil, errors.New("char must not be empty")
+		}
+		return stringMethod(func(s string) (interface{}, error) {
+			runes := []rune(s)
+			n := int64(len(runes))
+			// Compared separately as keepFirst+keepLast could overflow.
+			if keepFirst >= n || keepLast >= n-keepFirst {
+				return strings.Repeat(char, len(runes)), nil
+			}
+			first, last := int(keepFirst), int(keepLast)
+			var buf strings.Builder
+			buf.WriteString(string(runes[:first]))
+			buf.WriteString(strings.Repeat(char, len(runes)-first-last))
+			buf.WriteString(string(runes[len(runes)-last:]))
+			return buf.String(), nil
+		}), nil
+	},
+)
+
+//------------------------------------------------------------------------------
+
 var _ = registerSimpleMethod(
 	NewMethodSpec(
 		"parse_csv", "",
@@ -917,7 +1997,7 @@ var _ = registerSimpleMethod(
 			`{"orders":"foo,bar\nfoo 1,bar 1\nfoo 2,bar 2"}`,
 			`{"orders":[{"bar":"bar 1","foo":"foo 1"},{"bar":"bar 2","foo":"foo 2"}]}`,
 		),
-	),
+	).AppliesTo(ValueString, ValueBytes).Returns(ValueArray),
 	parseCSVMethod,
 )
 
@@ -975,7 +2055,7 @@ var _ = registerSimpleMethod(
 			`{"doc":"{\"foo\":\"bar\"}"}`,
 			`{"doc":{"foo":"bar"}}`,
 		),
-	),
+	).AppliesTo(ValueString, ValueBytes),
 	func(*ParsedParams) (simpleMethod, error) {
 		return func(v interface{}, ctx FunctionContext) (interface{}, error) {
 			var jsonBytes []byte
@@ -1012,7 +2092,7 @@ var _ = registerSimpleMethod(
 			`{"doc":"<root><title>This is a title</title><content>This is some content</content></root>"}`,
 			`{"doc":{"root":{"content":"This is some content","title":"This is a title"}}}`,
 		),
-	).Beta(),
+	).Beta().AppliesTo(ValueString, ValueBytes),
 	func(*ParsedParams) (simpleMethod, error) {
 		return func(v interface{}, ctx FunctionContext) (interface{}, error) {
 			var xmlBytes []byte
@@ -1044,7 +2124,7 @@ var _ = registerSimpleMethod(
 			`{"doc":"foo: bar"}`,
 			`{"doc":{"foo":"bar"}}`,
 		),
-	),
+	).AppliesTo(ValueString, ValueBytes),
 	func(*ParsedParams) (simpleMethod, error) {
 		return func(v interface{}, ctx FunctionContext) (interface{}, error) {
 			var yamlBytes []byte
@@ -1180,7 +2260,7 @@ var _ = registerOldParamsSimpleMethod(
 			`{"doc":{"timestamp":"2020-Aug-14"}}`,
 			`{"doc":{"timestamp":"2020-08-14T00:00:00Z"}}`,
 		),
-	).Beta(),
+	).Beta().AppliesTo(ValueString, ValueBytes),
 	func(args ...interface{}) (simpleMethod, error) {
 		layout := args[0].(string)
 		return func(v interface{}, ctx FunctionContext) (interface{}, error) {
@@ -1219,7 +2299,7 @@ var _ = registerOldParamsSimpleMethod(
 			`{"doc":{"timestamp":"2020-Aug-14"}}`,
 			`{"doc":{"timestamp":"2020-08-14T00:00:00Z"}}`,
 		),
-	).Beta(),
+	).Beta().AppliesTo(ValueString, ValueBytes),
 	func(args ...interface{}) (simpleMethod, error) {
 		layout := args[0].(string)
 		return func(v interface{}, ctx FunctionContext) (interface{}, error) {
@@ -1262,7 +2342,7 @@ var _ = registerSimpleMethod(
 			`{"thing":"backwards"}`,
 			`}"sdrawkcab":"gniht"{`,
 		),
-	),
+	).AppliesTo(ValueString, ValueBytes),
 	func(*ParsedParams) (simpleMethod, error) {
 		return func(v interface{}, ctx FunctionContext) (interface{}, error) {
 			switch t := v.(type) {
@@ -1326,7 +2406,9 @@ var _ = registerSimpleMethod(
 		),
 	).Beta().
 		Param(ParamString("format", "The output format to use.").Default(time.RFC3339Nano)).
-		Param(ParamString("tz", "An optional timezone to use, otherwise the timezone of the input string is used, or in the case of unix timestamps the local timezone is used.").Optional()),
+		Param(ParamString("tz", "An optional timezone to use, otherwise the timezone of the input string is used, or in the case of unix timestamps the local timezone is used.").Optional()).
+		AppliesTo(ValueNumber, ValueString, ValueBytes).
+		Returns(ValueString),
 	func(args *ParsedParams) (simpleMethod, error) {
 		layout, err := args.FieldString("format")
 		if err != nil {
@@ -1379,7 +2461,7 @@ var _ = registerOldParamsSimpleMethod(
 			`{"created_at":"2020-08-14T11:50:26.371Z"}`,
 			`{"something_at":"2020-Aug-14 11:50:26"}`,
 		),
-	).Beta(),
+	).Beta().AppliesTo(ValueNumber, ValueString, ValueBytes).Returns(ValueString),
 	func(args ...interface{}) (simpleMethod, error) {
 		layout := args[0].(string)
 		var timezone *time.Location
@@ -1419,7 +2501,7 @@ var _ = registerSimpleMethod(
 			`{"created_at":"2009-11-10T23:00:00Z"}`,
 			`{"created_at_unix":1257894000}`,
 		),
-	).Beta(),
+	).Beta().AppliesTo(ValueNumber, ValueString, ValueBytes).Returns(ValueNumber),
 	func(*ParsedParams) (simpleMethod, error) {
 		return func(v interface{}, ctx FunctionContext) (interface{}, error) {
 			target, err := IGetTimestamp(v)
@@ -1445,7 +2527,7 @@ var _ = registerSimpleMethod(
 			`{"created_at":"2009-11-10T23:00:00Z"}`,
 			`{"created_at_unix":1257894000000000000}`,
 		),
-	).Beta(),
+	).Beta().AppliesTo(ValueNumber, ValueString, ValueBytes).Returns(ValueNumber),
 	func(*ParsedParams) (simpleMethod, error) {
 		return func(v interface{}, ctx FunctionContext) (interface{}, error) {
 			target, err := IGetTimestamp(v)
@@ -1513,7 +2595,7 @@ var _ = registerOldParamsSimpleMethod(
 			`{"value":"The foo ate my homework"}`,
 			`{"new_value":"The dog ate my homework"}`,
 		),
-	),
+	).AppliesTo(ValueString, ValueBytes),
 	func(args ...interface{}) (simpleMethod, error) {
 		match := args[0].(string)
 		matchB := []byte(match)
@@ -1553,7 +2635,7 @@ var _ = registerOldParamsSimpleMethod(
 			`{"value":"<i>Hello</i> <b>World</b>"}`,
 			`{"new_value":"&lt;i&gt;Hello&lt;/i&gt; &lt;b&gt;World&lt;/b&gt;"}`,
 		),
-	),
+	).AppliesTo(ValueString, ValueBytes),
 	func(args ...interface{}) (simpleMethod, error) {
 		items, ok := args[0].([]interface{})
 		if !ok {
@@ -1612,7 +2694,7 @@ var _ = registerOldParamsSimpleMethod(
 			`{"value":"paranormal"}`,
 			`{"matches":["ar","an","al"]}`,
 		),
-	),
+	).AppliesTo(ValueString, ValueBytes).Returns(ValueArray),
 	func(args ...interface{}) (simpleMethod, error) {
 		re, err := regexp.Compile(args[0].(string))
 		if err != nil {
@@ -1657,7 +2739,7 @@ var _ = registerOldParamsSimpleMethod(
 			`{"value":"-axxb-ab-"}`,
 			`{"matches":[["axxb","xx"],["ab",""]]}`,
 		),
-	),
+	).AppliesTo(ValueString, ValueBytes).Returns(ValueArray),
 	func(args ...interface{}) (simpleMethod, error) {
 		re, err := regexp.Compile(args[0].(string))
 		if err != nil {
@@ -1715,7 +2797,12 @@ var _ = registerOldParamsSimpleMethod(
 			`{"value":"option1: value1"}`,
 			`{"matches":{"0":"option1: value1","key":"option1","value":"value1"}}`,
 		),
-	),
+		NewExampleSpec("Named groups make it possible to extract structured data from unstructured log lines without relying on positional indexes.",
+			`root = content().re_find_object("^(?P<level>[A-Z]+) \\[(?P<component>[^\\]]+)\\] (?P<message>.*)$").without("0")`,
+			`WARN [http_server] request took too long`,
+			`{"component":"http_server","level":"WARN","message":"request took too long"}`,
+		),
+	).AppliesTo(ValueString, ValueBytes).Returns(ValueObject),
 	func(args ...interface{}) (simpleMethod, error) {
 		re, err := regexp.Compile(args[0].(string))
 		if err != nil {
@@ -1740,7 +2827,7 @@ var _ = registerOldParamsSimpleMethod(
 				groupMatches := re.FindSubmatch(t)
 				for i, match := range groupMatches {
 					key := groups[i]
-					result[key] = match
+					result[key] = string(match)
 				}
 			default:
 				return nil, NewTypeError(v, ValueString)
@@ -1771,7 +2858,7 @@ var _ = registerOldParamsSimpleMethod(
 			`{"value":"option1: value1\noption2: value2\noption3: value3"}`,
 			`{"matches":[{"0":"option1: value1","key":"option1","value":"value1"},{"0":"option2: value2","key":"option2","value":"value2"},{"0":"option3: value3","key":"option3","value":"value3"}]}`,
 		),
-	),
+	).AppliesTo(ValueString, ValueBytes).Returns(ValueArray),
 	func(args ...interface{}) (simpleMethod, error) {
 		re, err := regexp.Compile(args[0].(string))
 		if err != nil {
@@ -1804,7 +2891,7 @@ var _ = registerOldParamsSimpleMethod(
 					obj := make(map[string]interface{}, len(groups))
 					for i, match := range matches {
 						key := groups[i]
-						obj[key] = match
+						obj[key] = string(match)
 					}
 					result = append(result, obj)
 				}
@@ -1834,7 +2921,7 @@ var _ = registerOldParamsSimpleMethod(
 			`{"value":"there are ten puppies"}`,
 			`{"matches":false}`,
 		),
-	),
+	).AppliesTo(ValueString, ValueBytes).Returns(ValueBool),
 	func(args ...interface{}) (simpleMethod, error) {
 		re, err := regexp.Compile(args[0].(string))
 		if err != nil {
@@ -1871,7 +2958,7 @@ var _ = registerOldParamsSimpleMethod(
 			`{"value":"foo ADD 70"}`,
 			`{"new_value":"foo +(70)"}`,
 		),
-	),
+	).AppliesTo(ValueString, ValueBytes),
 	func(args ...interface{}) (simpleMethod, error) {
 		re, err := regexp.Compile(args[0].(string))
 		if err != nil {
@@ -1911,7 +2998,7 @@ var _ = registerOldParamsSimpleMethod(
 			`{"value":"foo,bar,baz"}`,
 			`{"new_value":["foo","bar","baz"]}`,
 		),
-	),
+	).AppliesTo(ValueString, ValueBytes).Returns(ValueArray),
 	func(args ...interface{}) (simpleMethod, error) {
 		delim := args[0].(string)
 		delimB := []byte(delim)
@@ -1958,7 +3045,7 @@ var _ = registerSimpleMethod(
 			`{"id":228930314431312345}`,
 			`{"id":"228930314431312345"}`,
 		),
-	),
+	).Returns(ValueString),
 	func(*ParsedParams) (simpleMethod, error) {
 		return func(v interface{}, ctx FunctionContext) (interface{}, error) {
 			return IToString(v), nil
@@ -1968,6 +3055,39 @@ var _ = registerSimpleMethod(
 
 //------------------------------------------------------------------------------
 
+var ansiEscapeRegexp = regexp.MustCompile(
+	`\x1b\][^\x07\x1b]*(?:\x07|\x1b\\)` + // OSC sequences, e.g. hyperlinks and window titles
+		`|(?:\x1b\[|\x{9b})[0-?]*[ -/]*[@-~]` + // CSI sequences, e.g. colours and cursor movement
+		`|\x1b[@-_]`, // Other two character escape sequences
+)
+
+var _ = registerSimpleMethod(
+	NewMethodSpec(
+		"strip_ansi", "",
+	).InCategory(
+		MethodCategoryStrings,
+		"Removes all ANSI escape sequences, such as terminal colours, text styles, cursor movements and hyperlinks, from a string. This is useful when ingesting logs from CI systems and command line tools that are intended for display in a terminal.",
+		NewExampleSpec("",
+			`root.line = this.line.strip_ansi()`,
+			`{"line":"\u001b[1;31mERROR\u001b[0m: build failed"}`,
+			`{"line":"ERROR: build failed"}`,
+		),
+	),
+	func(*ParsedParams) (simpleMethod, error) {
+		return func(v interface{}, ctx FunctionContext) (interface{}, error) {
+			switch t := v.(type) {
+			case string:
+				return ansiEscapeRegexp.ReplaceAllString(t, ""), nil
+			case []byte:
+				return ansiEscapeRegexp.ReplaceAll(t, nil), nil
+			}
+			return nil, NewTypeError(v, ValueString)
+		}, nil
+	},
+)
+
+//------------------------------------------------------------------------------
+
 var _ = registerOldParamsSimpleMethod(
 	NewMethodSpec(
 		"strip_html", "",
@@ -1979,6 +3099,11 @@ var _ = registerOldParamsSimpleMethod(
 			`{"value":"<p>the plain <strong>old text</strong></p>"}`,
 			`{"stripped":"the plain old text"}`,
 		),
+		NewExampleSpec("HTML entities within the text content remain escaped, in order to obtain the raw text content follow this method with [`unescape_html`](#unescape_html).",
+			`root.text = this.value.strip_html().unescape_html()`,
+			`{"value":"<p>fish &amp; chips</p>"}`,
+			`{"text":"fish & chips"}`,
+		),
 		NewExampleSpec("It's also possible to provide an explicit list of element types to preserve in the output.",
 			`root.stripped = this.value.strip_html(["article"])`,
 			`{"value":"<article><p>the plain <strong>old text</strong></p></article>"}`,
@@ -2028,7 +3153,7 @@ root.description = this.description.trim()`,
 			`{"description":"  something happened and its amazing! ","title":"!!!watch out!?"}`,
 			`{"description":"something happened and its amazing!","title":"watch out"}`,
 		),
-	),
+	).AppliesTo(ValueString, ValueBytes),
 	func(args ...interface{}) (simpleMethod, error) {
 		var cutset string
 		if len(args) > 0 {
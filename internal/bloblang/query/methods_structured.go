@@ -7,6 +7,8 @@ import (
 	"fmt"
 	"sort"
 	"strings"
+	"sync"
+	"sync/atomic"
 
 	"github.com/Jeffail/gabs/v2"
 	jsonschema "github.com/xeipuuv/gojsonschema"
@@ -26,7 +28,7 @@ var _ = registerSimpleMethod(
 			`{"patrons":[{"id":"1","age":45},{"id":"2","age":23}]}`,
 			`{"all_over_21":true}`,
 		),
-	).Param(ParamQuery("test", "A test query to apply to each element.")),
+	).Param(ParamQuery("test", "A test query to apply to each element.")).AppliesTo(ValueArray).Returns(ValueBool),
 	func(args *ParsedParams) (simpleMethod, error) {
 		queryFn, err := args.FieldQuery("test")
 		if err != nil {
@@ -72,7 +74,7 @@ var _ = registerSimpleMethod(
 			`{"patrons":[{"id":"1","age":10},{"id":"2","age":12}]}`,
 			`{"any_over_21":false}`,
 		),
-	).Param(ParamQuery("test", "A test query to apply to each element.")),
+	).Param(ParamQuery("test", "A test query to apply to each element.")).AppliesTo(ValueArray).Returns(ValueBool),
 	func(args *ParsedParams) (simpleMethod, error) {
 		queryFn, err := args.FieldQuery("test")
 		if err != nil {
@@ -121,7 +123,7 @@ var _ = registerSimpleMethod(
 			`{"foo":["bar","baz"]}`,
 			`{"foo":["bar","baz","and","this"]}`,
 		),
-	).VariadicParams(),
+	).VariadicParams().AppliesTo(ValueArray).Returns(ValueArray),
 	func(args *ParsedParams) (simpleMethod, error) {
 		argsList := args.Raw()
 		return func(res interface{}, ctx FunctionContext) (interface{}, error) {
@@ -206,7 +208,7 @@ var _ = registerOldParamsSimpleMethod(
 			`{"thing":"this bar that"}`,
 			`{"has_foo":false}`,
 		),
-	),
+	).AppliesTo(ValueString, ValueBytes, ValueArray, ValueObject).Returns(ValueBool),
 	func(args ...interface{}) (simpleMethod, error) {
 		compareRight := args[0]
 		compareFn := func(compareLeft interface{}) bool {
@@ -263,7 +265,7 @@ var _ = registerSimpleMethod(
 			`{"foo":["bar","baz"]}`,
 			`{"foo":[{"index":0,"value":"bar"},{"index":1,"value":"baz"}]}`,
 		),
-	),
+	).AppliesTo(ValueArray).Returns(ValueArray),
 	func(*ParsedParams) (simpleMethod, error) {
 		return func(v interface{}, ctx FunctionContext) (interface{}, error) {
 			arr, ok := v.([]interface{})
@@ -386,7 +388,15 @@ When filtering objects the mapping query argument is provided a context with a f
 			`{"dict":{"first":"hello foo","second":"world","third":"this foo is great"}}`,
 			`{"new_dict":{"first":"hello foo","third":"this foo is great"}}`,
 		),
-	),
+		NewExampleSpec(`##### With multiple parameters
+
+When the query argument is a lambda with two parameters the first is given the value of an array element and the second its index, or when filtering objects the first is given the key and the second the value.`,
+			`root.evens = this.nums.filter((num, i) -> i % 2 == 0)
+root.new_dict = this.dict.filter((k, v) -> k != "second")`,
+			`{"nums":[3,11,4,17],"dict":{"first":"hello","second":"world"}}`,
+			`{"evens":[3,4],"new_dict":{"first":"hello"}}`,
+		),
+	).AppliesTo(ValueArray, ValueObject),
 	func(args ...interface{}) (simpleMethod, error) {
 		mapFn, ok := args[0].(Function)
 		if !ok {
@@ -397,8 +407,8 @@ When filtering objects the mapping query argument is provided a context with a f
 			switch t := res.(type) {
 			case []interface{}:
 				newSlice := make([]interface{}, 0, len(t))
-				for _, v := range t {
-					f, err := mapFn.Exec(ctx.WithValue(v))
+				for i, v := range t {
+					f, err := ExecWithParams(mapFn, ctx, v, v, int64(i))
 					if err != nil {
 						return nil, err
 					}
@@ -414,7 +424,7 @@ When filtering objects the mapping query argument is provided a context with a f
 						"key":   k,
 						"value": v,
 					}
-					f, err := mapFn.Exec(ctx.WithValue(ctxMap))
+					f, err := ExecWithParams(mapFn, ctx, ctxMap, k, v)
 					if err != nil {
 						return nil, err
 					}
@@ -447,7 +457,7 @@ var _ = registerSimpleMethod(
 			`["foo",["bar","baz"],"buz"]`,
 			`{"result":["foo","bar","baz","buz"]}`,
 		),
-	),
+	).AppliesTo(ValueArray).Returns(ValueArray),
 	func(*ParsedParams) (simpleMethod, error) {
 		return func(v interface{}, ctx FunctionContext) (interface{}, error) {
 			array, isArray := v.([]interface{})
@@ -486,7 +496,12 @@ var _ = registerOldParamsSimpleMethod(
 			`{"foo":["hello ", "world"]}`,
 			`{"result":"hello world"}`,
 		),
-	),
+		NewExampleSpec(`When the query argument is a lambda with two parameters the first is given the tally and the second the value of the current element.`,
+			`root.sum = this.foo.fold(0, (tally, value) -> tally + value)`,
+			`{"foo":[3,8,11]}`,
+			`{"sum":22}`,
+		),
+	).AppliesTo(ValueArray),
 	func(args ...interface{}) (simpleMethod, error) {
 		var foldTallyStart interface{}
 		switch t := args[0].(type) {
@@ -517,10 +532,10 @@ var _ = registerOldParamsSimpleMethod(
 			}
 
 			for _, v := range resArray {
-				newV, mapErr := foldFn.Exec(ctx.WithValue(map[string]interface{}{
+				newV, mapErr := ExecWithParams(foldFn, ctx, map[string]interface{}{
 					"tally": tally,
 					"value": v,
-				}))
+				}, tally, v)
 				if mapErr != nil {
 					return nil, mapErr
 				}
@@ -552,7 +567,7 @@ var _ = registerOldParamsSimpleMethod(
 			`{"name":"foobar bazson"}`,
 			`{"last_byte":110}`,
 		),
-	),
+	).AppliesTo(ValueArray, ValueBytes),
 	func(args ...interface{}) (simpleMethod, error) {
 		index := args[0].(int64)
 		return func(v interface{}, ctx FunctionContext) (interface{}, error) {
@@ -658,7 +673,7 @@ var _ = registerSimpleMethod(
 			`{"foo":{"bar":1,"baz":2}}`,
 			`{"foo_keys":["bar","baz"]}`,
 		),
-	),
+	).AppliesTo(ValueObject).Returns(ValueArray),
 	func(*ParsedParams) (simpleMethod, error) {
 		return func(v interface{}, ctx FunctionContext) (interface{}, error) {
 			if m, ok := v.(map[string]interface{}); ok {
@@ -688,7 +703,7 @@ var _ = registerSimpleMethod(
 			`{"foo":{"bar":1,"baz":2}}`,
 			`{"foo_key_values":[{"key":"bar","value":1},{"key":"baz","value":2}]}`,
 		),
-	),
+	).AppliesTo(ValueObject).Returns(ValueArray),
 	func(*ParsedParams) (simpleMethod, error) {
 		return func(v interface{}, ctx FunctionContext) (interface{}, error) {
 			if m, ok := v.(map[string]interface{}); ok {
@@ -727,7 +742,7 @@ var _ = registerSimpleMethod(
 			`{"foo":{"first":"bar","second":"baz"}}`,
 			`{"foo_len":2}`,
 		),
-	),
+	).AppliesTo(ValueString, ValueBytes, ValueArray, ValueObject).Returns(ValueNumber),
 	func(*ParsedParams) (simpleMethod, error) {
 		return func(v interface{}, ctx FunctionContext) (interface{}, error) {
 			var length int64
@@ -750,7 +765,7 @@ var _ = registerSimpleMethod(
 
 //------------------------------------------------------------------------------
 
-var _ = registerOldParamsSimpleMethod(
+var _ = registerSimpleMethod(
 	NewMethodSpec(
 		"map_each", "",
 	).InCategory(
@@ -773,66 +788,149 @@ Apply a mapping to each value of an object and replace the value with the result
 			`{"dict":{"foo":"hello","bar":"world"}}`,
 			`{"new_dict":{"bar":"WORLD","foo":"HELLO"}}`,
 		),
-	),
-	func(args ...interface{}) (simpleMethod, error) {
-		mapFn, ok := args[0].(Function)
-		if !ok {
-			return nil, fmt.Errorf("expected query argument, received %T", args[0])
+		NewExampleSpec(`##### With multiple parameters
+
+When the query argument is a lambda with two parameters the first is given the value of an array element and the second its index, or when mapping objects the first is given the key and the second the value.`,
+			`root.new_nums = this.nums.map_each((num, i) -> num * i)
+root.new_dict = this.dict.map_each((k, v) -> k + ": " + v)`,
+			`{"nums":[3,11,4,17],"dict":{"foo":"hello"}}`,
+			`{"new_dict":{"foo":"foo: hello"},"new_nums":[0,11,8,51]}`,
+		),
+		NewExampleSpec(`##### In parallel
+
+When the query is expensive, such as hashing or validating large documents, the elements can be mapped in parallel by setting the `+"`parallel`"+` parameter to the number of elements to map at once. The order of the elements is preserved, and variables assigned by maps applied within the query are not visible outside of it.`,
+			`root.hashes = this.docs.map_each(query: doc -> doc.string().hash("sha256").encode("hex"), parallel: 4)`,
+			`{"docs":["foo","bar"]}`,
+			`{"hashes":["2c26b46b68ffc68ff99b453c1d30413413422d706483bfa0f98a5e886266e7ae","fcde2b2edba56bf408601fb721fe9b5c338d10ee429ea04fae5511b68fbf8fb9"]}`,
+		),
+	).
+		Param(ParamQuery("query", "A query that will be used to map each element.")).
+		Param(ParamInt64("parallel", "The number of elements to map in parallel.").Default(1)).
+		AppliesTo(ValueArray, ValueObject),
+	func(args *ParsedParams) (simpleMethod, error) {
+		mapFn, err := args.FieldQuery("query")
+		if err != nil {
+			return nil, err
+		}
+		parallel, err := args.FieldInt64("parallel")
+		if err != nil {
+			return nil, err
+		}
+		if parallel < 1 {
+			return nil, fmt.Errorf("parallel must be at least 1, got %v", parallel)
 		}
 		return func(res interface{}, ctx FunctionContext) (interface{}, error) {
-			var resValue interface{}
-			var err error
 			switch t := res.(type) {
 			case []interface{}:
+				results, failed, err := execEach(int(parallel), len(t), ctx, func(i int, ctx FunctionContext) (interface{}, error) {
+					return ExecWithParams(mapFn, ctx, t[i], t[i], int64(i))
+				})
+				if err != nil {
+					return nil, fmt.Errorf("failed to process element %v: %w", failed, ErrFrom(err, mapFn))
+				}
 				newSlice := make([]interface{}, 0, len(t))
-				for i, v := range t {
-					newV, mapErr := mapFn.Exec(ctx.WithValue(v))
-					if mapErr != nil {
-						return nil, fmt.Errorf("failed to process element %v: %w", i, ErrFrom(mapErr, mapFn))
-					}
+				for i, newV := range results {
 					switch newV.(type) {
 					case Delete:
 					case Nothing:
-						newSlice = append(newSlice, v)
+						newSlice = append(newSlice, t[i])
 					default:
 						newSlice = append(newSlice, newV)
 					}
 				}
-				resValue = newSlice
+				return newSlice, nil
 			case map[string]interface{}:
-				newMap := make(map[string]interface{}, len(t))
-				for k, v := range t {
+				keys := make([]string, 0, len(t))
+				for k := range t {
+					keys = append(keys, k)
+				}
+				results, failed, err := execEach(int(parallel), len(keys), ctx, func(i int, ctx FunctionContext) (interface{}, error) {
+					k, v := keys[i], t[keys[i]]
 					var ctxMap interface{} = map[string]interface{}{
 						"key":   k,
 						"value": v,
 					}
-					newV, mapErr := mapFn.Exec(ctx.WithValue(ctxMap))
-					if mapErr != nil {
-						return nil, fmt.Errorf("failed to process element %v: %w", k, ErrFrom(mapErr, mapFn))
-					}
+					return ExecWithParams(mapFn, ctx, ctxMap, k, v)
+				})
+				if err != nil {
+					return nil, fmt.Errorf("failed to process element %v: %w", keys[failed], ErrFrom(err, mapFn))
+				}
+				newMap := make(map[string]interface{}, len(t))
+				for i, newV := range results {
+					k := keys[i]
 					switch newV.(type) {
 					case Delete:
 					case Nothing:
-						newMap[k] = v
+						newMap[k] = t[k]
 					default:
 						newMap[k] = newV
 					}
 				}
-				resValue = newMap
-			default:
-				return nil, NewTypeError(res, ValueArray)
-			}
-			if err != nil {
-				return nil, err
+				return newMap, nil
 			}
-			return resValue, nil
+			return nil, NewTypeError(res, ValueArray)
 		}, nil
 	},
-	false,
-	oldParamsExpectNArgs(1),
-	oldParamsExpectFunctionArg(0),
 )
 
+// execEach executes a function for each index up to a count and returns the
+// results in order. When parallel is greater than one the executions are spread
+// across that many goroutines, each with its own copy of the context variables,
+// as maps applied within the function may assign them.
+//
+// When any execution fails the error of the lowest failed index is returned
+// along with that index, which matches the error that executing in sequence
+// would return.
+func execEach(parallel, count int, ctx FunctionContext, fn func(i int, ctx FunctionContext) (interface{}, error)) ([]interface{}, int, error) {
+	results := make([]interface{}, count)
+	if parallel <= 1 || count <= 1 {
+		for i := 0; i < count; i++ {
+			v, err := fn(i, ctx)
+			if err != nil {
+				return nil, i, err
+			}
+			results[i] = v
+		}
+		return results, 0, nil
+	}
+	if parallel > count {
+		parallel = count
+	}
+
+	var next, failed int64 = -1, 0
+	errs := make([]error, count)
+
+	var wg sync.WaitGroup
+	wg.Add(parallel)
+	for w := 0; w < parallel; w++ {
+		workerCtx := ctx
+		workerCtx.Vars = make(map[string]interface{}, len(ctx.Vars))
+		for k, v := range ctx.Vars {
+			workerCtx.Vars[k] = v
+		}
+		go func() {
+			defer wg.Done()
+			for atomic.LoadInt64(&failed) == 0 {
+				i := int(atomic.AddInt64(&next, 1))
+				if i >= count {
+					return
+				}
+				if results[i], errs[i] = fn(i, workerCtx); errs[i] != nil {
+					atomic.StoreInt64(&failed, 1)
+				}
+			}
+		}()
+	}
+	wg.Wait()
+
+	for i, err := range errs {
+		if err != nil {
+			return nil, i, err
+		}
+	}
+	return results, 0, nil
+}
+
 //------------------------------------------------------------------------------
 
 var _ = registerOldParamsSimpleMethod(
@@ -850,7 +948,7 @@ var _ = registerOldParamsSimpleMethod(
 			`{"amqp_key":"foo","kafka_key":"bar","kafka_topic":"baz"}`,
 			`{"_kafka_key":"bar","_kafka_topic":"baz","amqp_key":"foo"}`,
 		),
-	),
+	).AppliesTo(ValueObject).Returns(ValueObject),
 	func(args ...interface{}) (simpleMethod, error) {
 		mapFn, ok := args[0].(Function)
 		if !ok {
@@ -975,7 +1073,7 @@ var _ = registerSimpleMethod(
 			`{"a":{}}`,
 			`Error("failed assignment (line 1): field `+"`this.a`"+`: object value is empty")`,
 		),
-	),
+	).AppliesTo(ValueString, ValueArray, ValueObject),
 	func(*ParsedParams) (simpleMethod, error) {
 		return func(v interface{}, ctx FunctionContext) (interface{}, error) {
 			switch t := v.(type) {
@@ -1017,7 +1115,7 @@ var _ = registerOldParamsMethod(
 			`{"foo":[{"id":"foo","v":"bbb"},{"id":"bar","v":"ccc"},{"id":"baz","v":"aaa"}]}`,
 			`{"sorted":[{"id":"baz","v":"aaa"},{"id":"foo","v":"bbb"},{"id":"bar","v":"ccc"}]}`,
 		),
-	),
+	).AppliesTo(ValueArray).Returns(ValueArray),
 	false, sortMethod,
 	oldParamsExpectOneOrZeroArgs(),
 	oldParamsExpectFunctionArg(0),
@@ -1114,7 +1212,7 @@ var _ = registerOldParamsMethod(
 			`{"foo":[{"id":"bbb","message":"bar"},{"id":"aaa","message":"foo"},{"id":"ccc","message":"baz"}]}`,
 			`{"sorted":[{"id":"aaa","message":"foo"},{"id":"bbb","message":"bar"},{"id":"ccc","message":"baz"}]}`,
 		),
-	),
+	).AppliesTo(ValueArray).Returns(ValueArray),
 	false, sortByMethod,
 	oldParamsExpectNArgs(1),
 )
@@ -1223,7 +1321,7 @@ root.the_rest = this.value.slice(0, -2)`,
 			`{"value":["foo","bar","baz","buz","bev"]}`,
 			`{"last_chunk":["buz","bev"],"the_rest":["foo","bar","baz"]}`,
 		),
-	),
+	).AppliesTo(ValueString, ValueBytes, ValueArray),
 	sliceMethod,
 	true,
 	oldParamsExpectAtLeastOneArg(),
@@ -1306,7 +1404,7 @@ var _ = registerMethod(
 			`{"foo":[3,8,4]}`,
 			`{"sum":15}`,
 		),
-	),
+	).AppliesTo(ValueArray, ValueNumber).Returns(ValueNumber),
 	sumMethod,
 )
 
@@ -1351,7 +1449,7 @@ var _ = registerOldParamsSimpleMethod(
 			`{"foo":["a","b","a","c"]}`,
 			`{"uniques":["a","b","c"]}`,
 		),
-	),
+	).AppliesTo(ValueArray).Returns(ValueArray),
 	uniqueMethod,
 	false,
 	oldParamsExpectOneOrZeroArgs(),
@@ -1456,7 +1554,7 @@ var _ = registerSimpleMethod(
 			`{"foo":{"bar":1,"baz":2}}`,
 			`{"foo_vals":[1,2]}`,
 		),
-	),
+	).AppliesTo(ValueObject).Returns(ValueArray),
 	func(*ParsedParams) (simpleMethod, error) {
 		return func(v interface{}, ctx FunctionContext) (interface{}, error) {
 			if m, ok := v.(map[string]interface{}); ok {
@@ -1486,7 +1584,7 @@ If a key within a nested path does not exist or is not an object then it is not
 			`{"inner":{"a":"first","b":"second","c":"third"},"d":"fourth","e":"fifth"}`,
 			`{"e":"fifth","inner":{"b":"second"}}`,
 		),
-	),
+	).AppliesTo(ValueObject).Returns(ValueObject),
 	func(args ...interface{}) (simpleMethod, error) {
 		excludeList := make([][]string, 0, len(args))
 		for _, arg := range args {
@@ -1534,3 +1632,61 @@ func mapWithout(m map[string]interface{}, paths [][]string) map[string]interface
 	}
 	return newMap
 }
+
+//------------------------------------------------------------------------------
+
+var _ = registerSimpleMethod(
+	NewMethodSpec(
+		"zip_with",
+		"Combines the elements of an array with the elements at the same index of another array by applying a query, returning an array of the results. Within the query the context is an object with a field `left` containing the element of the target array and a field `right` containing the element of the argument array, or when the query is a lambda with two parameters the elements are given to each parameter in the same order. An error occurs if the arrays are not the same length.",
+	).InCategory(
+		MethodCategoryObjectAndArray,
+		"",
+		NewExampleSpec("",
+			`root.sums = this.a.zip_with(this.b, (l, r) -> l + r)`,
+			`{"a":[1,2,3],"b":[10,20,30]}`,
+			`{"sums":[11,22,33]}`,
+		),
+		NewExampleSpec("",
+			`root.pairs = this.names.zip_with(this.ages, pair -> "%v is %v".format(pair.left, pair.right))`,
+			`{"names":["foo","bar"],"ages":[21,35]}`,
+			`{"pairs":["foo is 21","bar is 35"]}`,
+		),
+	).
+		Param(ParamArray("with", "An array to combine with the target array.")).
+		Param(ParamQuery("query", "A query to apply to each pair of elements.")).
+		AppliesTo(ValueArray).
+		Returns(ValueArray),
+	func(args *ParsedParams) (simpleMethod, error) {
+		withV, err := args.Field("with")
+		if err != nil {
+			return nil, err
+		}
+		with, _ := withV.([]interface{})
+		queryFn, err := args.FieldQuery("query")
+		if err != nil {
+			return nil, err
+		}
+		return func(res interface{}, ctx FunctionContext) (interface{}, error) {
+			arr, ok := res.([]interface{})
+			if !ok {
+				return nil, NewTypeError(res, ValueArray)
+			}
+			if len(arr) != len(with) {
+				return nil, fmt.Errorf("cannot zip an array of length %v with an array of length %v", len(arr), len(with))
+			}
+			results := make([]interface{}, 0, len(arr))
+			for i, v := range arr {
+				newV, err := ExecWithParams(queryFn, ctx, map[string]interface{}{
+					"left":  v,
+					"right": with[i],
+				}, v, with[i])
+				if err != nil {
+					return nil, fmt.Errorf("element %v: %w", i, err)
+				}
+				results = append(results, newV)
+			}
+			return results, nil
+		}, nil
+	},
+)
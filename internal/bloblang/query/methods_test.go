@@ -2,7 +2,10 @@ package query
 
 import (
 	"encoding/json"
+	"math"
+	"math/big"
 	"strconv"
+	"sync"
 	"testing"
 
 	"github.com/Jeffail/benthos/v3/lib/message"
@@ -573,6 +576,28 @@ func TestMethods(t *testing.T) {
 			),
 			output: "buz",
 		},
+		"check catch fallback not executed": {
+			input: methods(
+				jsonFn(`["foo","bar","baz"]`),
+				method("index", int64(1)),
+				method("catch", function("throw", "fallback was executed")),
+			),
+			output: "bar",
+		},
+		"check or fallback not executed": {
+			input: methods(
+				literalFn("foo"),
+				method("or", function("throw", "fallback was executed")),
+			),
+			output: "foo",
+		},
+		"check or fallback executed": {
+			input: methods(
+				literalFn(nil),
+				method("or", function("throw", "fallback was executed")),
+			),
+			err: "fallback was executed",
+		},
 		"check url escape query": {
 			input: methods(
 				literalFn("foo & bar"),
@@ -590,6 +615,27 @@ func TestMethods(t *testing.T) {
 			},
 			output: "foo+%26+bar",
 		},
+		"check url escape path": {
+			input: methods(
+				literalFn("foo & bar/baz"),
+				method("escape_url_path"),
+			),
+			output: "foo%20&%20bar%2Fbaz",
+		},
+		"check url unescape path": {
+			input: methods(
+				literalFn("foo%20&+bar%2Fbaz"),
+				method("unescape_url_path"),
+			),
+			output: "foo &+bar/baz",
+		},
+		"check url unescape path bad": {
+			input: methods(
+				literalFn("foo%zz"),
+				method("unescape_url_path"),
+			),
+			err: `string literal: invalid URL escape "%zz"`,
+		},
 		"check url unescape query": {
 			input: methods(
 				literalFn("foo+%26+bar"),
@@ -1433,6 +1479,41 @@ func TestMethods(t *testing.T) {
 				"bar": "THIS IS ASH",
 			},
 		},
+		"check map each parallel": {
+			input: methods(
+				jsonFn(`["foo","bar","baz","buz","qux"]`),
+				method("map_each", methods(
+					NewFieldFunction(""),
+					method("uppercase"),
+				), int64(3)),
+			),
+			output: []interface{}{"FOO", "BAR", "BAZ", "BUZ", "QUX"},
+		},
+		"check map each parallel object": {
+			input: methods(
+				jsonFn(`{"foo":"hello world","bar":"this is ash","baz":"deleted"}`),
+				method("map_each", NewIfFunction(
+					methods(NewFieldFunction("value"), method("contains", "deleted")),
+					function("deleted"),
+					nil,
+					methods(NewFieldFunction("value"), method("uppercase")),
+				), int64(2)),
+			),
+			output: map[string]interface{}{
+				"foo": "HELLO WORLD",
+				"bar": "THIS IS ASH",
+			},
+		},
+		"check map each parallel error": {
+			input: methods(
+				jsonFn(`["foo",5,"bar",6,"baz"]`),
+				method("map_each", methods(
+					NewFieldFunction(""),
+					method("uppercase"),
+				), int64(4)),
+			),
+			err: "array literal: failed to process element 1: expected string value, got number from field `this` (5)",
+		},
 		"check filter array": {
 			input: methods(
 				jsonFn(`[2,14,4,11,7]`),
@@ -1959,6 +2040,54 @@ func TestMethods(t *testing.T) {
 			input:  methods(literalFn(json.Number("5.8")), method("floor")),
 			output: int64(5),
 		},
+		"check format_number int with decimals": {
+			input:  methods(literalFn(int64(1000)), method("format_number", int64(1))),
+			output: "1,000.0",
+		},
+		"check format_number small negative": {
+			input:  methods(literalFn(-0.5), method("format_number", int64(0), ".", "")),
+			output: "0",
+		},
+		"check format_number uint": {
+			input:  methods(literalFn(uint64(123456)), method("format_number", int64(0), ".", "'")),
+			output: "123'456",
+		},
+		"check round precision float": {
+			input:  methods(literalFn(2.675), method("round", int64(2))),
+			output: 2.68,
+		},
+		"check round precision negative value": {
+			input:  methods(literalFn(-2.675), method("round", int64(2))),
+			output: -2.68,
+		},
+		"check round precision int": {
+			input:  methods(literalFn(int64(5)), method("round", int64(2))),
+			output: int64(5),
+		},
+		"check round negative precision uint": {
+			input:  methods(literalFn(uint64(149)), method("round", int64(-2))),
+			output: int64(100),
+		},
+		"check floor precision negative value": {
+			input:  methods(literalFn(-1.231), method("floor", int64(2))),
+			output: -1.24,
+		},
+		"check ceil precision negative value": {
+			input:  methods(literalFn(-1.239), method("ceil", int64(2))),
+			output: -1.23,
+		},
+		"check round_half_even": {
+			input:  methods(literalFn(-2.5), method("round_half_even")),
+			output: int64(-2),
+		},
+		"check round_half_even precision": {
+			input:  methods(literalFn(0.125), method("round_half_even", int64(2))),
+			output: 0.12,
+		},
+		"check round_half_even negative precision": {
+			input:  methods(literalFn(int64(250)), method("round_half_even", int64(-2))),
+			output: int64(200),
+		},
 		"check round up": {
 			input:  methods(literalFn(5.8), method("round")),
 			output: int64(6),
@@ -2005,6 +2134,588 @@ func TestMethods(t *testing.T) {
 			},
 			output: int64(-1),
 		},
+		"check levenshtein unicode": {
+			input: methods(
+				literalFn("héllo wörld"),
+				method("levenshtein", "hello world"),
+			),
+			output: int64(2),
+		},
+		"check levenshtein empty": {
+			input: methods(
+				literalFn(""),
+				method("levenshtein", "foo"),
+			),
+			output: int64(3),
+		},
+		"check similarity identical": {
+			input: methods(
+				literalFn("foobar"),
+				method("similarity", "foobar"),
+			),
+			output: float64(1),
+		},
+		"check similarity both empty": {
+			input: methods(
+				literalFn(""),
+				method("similarity", ""),
+			),
+			output: float64(1),
+		},
+		"check similarity different": {
+			input: methods(
+				literalFn("abcd"),
+				method("similarity", "wxyz"),
+			),
+			output: float64(0),
+		},
+		"check soundex": {
+			input: methods(
+				literalFn("Ashcraft"),
+				method("soundex"),
+			),
+			output: "A261",
+		},
+		"check soundex separators": {
+			input: methods(
+				literalFn("Tymczak"),
+				method("soundex"),
+			),
+			output: "T522",
+		},
+		"check soundex padding": {
+			input: methods(
+				literalFn("Lee"),
+				method("soundex"),
+			),
+			output: "L000",
+		},
+		"check soundex no letters": {
+			input: methods(
+				literalFn("123"),
+				method("soundex"),
+			),
+			output: "",
+		},
+		"check metaphone initial exceptions": {
+			input: methods(
+				literalFn("Knight Xavier Aeon"),
+				method("metaphone"),
+			),
+			output: "NT SFR EN",
+		},
+		"check to_snake_case digits and unicode": {
+			input: methods(
+				literalFn("Über2Fast  for.You"),
+				method("to_snake_case"),
+			),
+			output: "über2_fast_for_you",
+		},
+		"check to_camel_case empty": {
+			input: methods(
+				literalFn("__"),
+				method("to_camel_case"),
+			),
+			output: "",
+		},
+		"check to_pascal_case acronym": {
+			input: methods(
+				literalFn("parseJSONValue"),
+				method("to_pascal_case"),
+			),
+			output: "ParseJsonValue",
+		},
+		"check slugify empty": {
+			input: methods(
+				literalFn("!!! ??? !!!"),
+				method("slugify"),
+			),
+			output: "",
+		},
+		"check slugify ligatures": {
+			input: methods(
+				literalFn("Ærøskøbing ﬁnale"),
+				method("slugify"),
+			),
+			output: "aeroskobing-finale",
+		},
+		"check truncate exact length": {
+			input: methods(
+				literalFn("héllo"),
+				method("truncate", int64(5), "..."),
+			),
+			output: "héllo",
+		},
+		"check truncate suffix": {
+			input: methods(
+				literalFn("日本語のテキスト"),
+				method("truncate", int64(5), "..."),
+			),
+			output: "日本...",
+		},
+		"check word_wrap long word and newlines": {
+			input: methods(
+				literalFn("a verylongword\nb c d"),
+				method("word_wrap", int64(3)),
+			),
+			output: "a\nverylongword\nb c\nd",
+		},
+		"check pad_left multi char fill": {
+			input: methods(
+				literalFn("ü"),
+				method("pad_left", int64(6), "ab"),
+			),
+			output: "ababaü",
+		},
+		"check pad_right already long": {
+			input: methods(
+				literalFn("foobar"),
+				method("pad_right", int64(3)),
+			),
+			output: "foobar",
+		},
+		"check repeat zero": {
+			input: methods(
+				literalFn("foo"),
+				method("repeat", int64(0)),
+			),
+			output: "",
+		},
+		"check luhn_valid": {
+			input: methods(
+				literalFn("79927398713"),
+				method("luhn_valid"),
+			),
+			output: true,
+		},
+		"check luhn_valid bad chars": {
+			input: methods(
+				literalFn("7992739871x3"),
+				method("luhn_valid"),
+			),
+			output: false,
+		},
+		"check luhn_valid single digit": {
+			input: methods(
+				literalFn("0"),
+				method("luhn_valid"),
+			),
+			output: false,
+		},
+		"check mask unicode": {
+			input: methods(
+				literalFn("héllo wörld"),
+				method("mask", int64(1), int64(2), "•"),
+			),
+			output: "h••••••••ld",
+		},
+		"check mask keep none": {
+			input: methods(
+				literalFn("secret"),
+				method("mask", int64(0), int64(0), "x"),
+			),
+			output: "xxxxxx",
+		},
+		"check mask keep overflow": {
+			input: methods(
+				literalFn("secret"),
+				method("mask", int64(math.MaxInt64), int64(1), "x"),
+			),
+			output: "xxxxxx",
+		},
+		"check strip_ansi osc and cursor": {
+			input: methods(
+				literalFn("\x1b]8;;http://example.com\x07link\x1b]8;;\x07 \x1b[2Kdone\x1bM"),
+				method("strip_ansi"),
+			),
+			output: "link done",
+		},
+		"check strip_ansi bytes": {
+			input: methods(
+				literalFn([]byte("\x1b[32mok\x1b[0m")),
+				method("strip_ansi"),
+			),
+			output: []byte("ok"),
+		},
+		"check re_find_object bytes": {
+			input: methods(
+				literalFn([]byte("foo: bar")),
+				method("re_find_object", "(?P<key>\\w+): (?P<value>\\w+)"),
+			),
+			output: map[string]interface{}{
+				"0":     "foo: bar",
+				"key":   "foo",
+				"value": "bar",
+			},
+		},
+		"check re_find_object no match": {
+			input: methods(
+				literalFn("nope"),
+				method("re_find_object", "(?P<key>\\d+)"),
+			),
+			output: map[string]interface{}{},
+		},
+		"check re_find_all_object bytes": {
+			input: methods(
+				literalFn([]byte("a=1 b=2")),
+				method("re_find_all_object", "(?P<k>\\w)=(?P<v>\\d)"),
+			),
+			output: []interface{}{
+				map[string]interface{}{"0": "a=1", "k": "a", "v": "1"},
+				map[string]interface{}{"0": "b=2", "k": "b", "v": "2"},
+			},
+		},
+		"check matches_glob double star zero dirs": {
+			input: methods(
+				literalFn("a/b.txt"),
+				method("matches_glob", "a/**/*.txt"),
+			),
+			output: true,
+		},
+		"check matches_glob star no separator": {
+			input: methods(
+				literalFn("a/b/c.txt"),
+				method("matches_glob", "a/*.txt"),
+			),
+			output: false,
+		},
+		"check matches_glob class and escape": {
+			input: methods(
+				literalFn("file1*.csv"),
+				method("matches_glob", "file[0-9]\\*.[!t]sv"),
+			),
+			output: true,
+		},
+		"check matches_glob regexp chars literal": {
+			input: methods(
+				literalFn("a+b(c).json"),
+				method("matches_glob", "a+b(c).*"),
+			),
+			output: true,
+		},
+		"check int_big string": {
+			input: methods(
+				literalFn("340282366920938463463374607431768211455"),
+				method("int_big"),
+			),
+			output: bigIntFromString(t, "340282366920938463463374607431768211455"),
+		},
+		"check int_big hex bytes": {
+			input: methods(
+				literalFn([]byte("0xff")),
+				method("int_big"),
+			),
+			output: big.NewInt(255),
+		},
+		"check int_big json number": {
+			input: methods(
+				literalFn(json.Number("18446744073709551616")),
+				method("int_big"),
+			),
+			output: bigIntFromString(t, "18446744073709551616"),
+		},
+		"check int_big float": {
+			input: methods(
+				literalFn(5.5),
+				method("int_big"),
+			),
+			err: "number literal: float value 5.5 cannot be represented as an integer",
+		},
+		"check int_big not a number": {
+			input: methods(
+				literalFn("nope"),
+				method("int_big"),
+			),
+			err: "string literal: failed to parse 'nope' as an integer",
+		},
+		"check int_big to string": {
+			input: methods(
+				literalFn("-123456789012345678901234567890"),
+				method("int_big"),
+				method("string"),
+			),
+			output: "-123456789012345678901234567890",
+		},
+		"check decimal string": {
+			input: methods(
+				literalFn("-12.50"),
+				method("decimal"),
+			),
+			output: decimalFromString(t, "-12.50"),
+		},
+		"check decimal float": {
+			input: methods(
+				literalFn(0.1),
+				method("decimal"),
+			),
+			output: decimalFromString(t, "0.1"),
+		},
+		"check decimal not a number": {
+			input: methods(
+				literalFn("twelve"),
+				method("decimal"),
+			),
+			err: "string literal: failed to parse 'twelve' as a decimal",
+		},
+		"check decimal number coercion": {
+			input: methods(
+				literalFn("2.25"),
+				method("decimal"),
+				method("number"),
+			),
+			output: 2.25,
+		},
+		"check format_decimal": {
+			input: methods(
+				literalFn("2.675"),
+				method("decimal"),
+				method("format_decimal", int64(2)),
+			),
+			output: "2.68",
+		},
+		"check format_decimal int": {
+			input: methods(
+				literalFn(int64(-3)),
+				method("format_decimal", int64(1)),
+			),
+			output: "-3.0",
+		},
+		"check format_decimal no scale": {
+			input: methods(
+				literalFn(json.Number("1.250")),
+				method("format_decimal"),
+			),
+			output: "1.250",
+		},
+		"check parse_ip ipv4 mapped": {
+			input: methods(
+				literalFn("::ffff:192.168.1.1"),
+				method("parse_ip"),
+			),
+			output: map[string]interface{}{
+				"ip":             "192.168.1.1",
+				"version":        int64(4),
+				"is_private":     true,
+				"is_loopback":    false,
+				"is_link_local":  false,
+				"is_multicast":   false,
+				"is_unspecified": false,
+			},
+		},
+		"check parse_ip ipv6 link local": {
+			input: methods(
+				literalFn("fe80::1"),
+				method("parse_ip"),
+				method("get", "is_link_local"),
+			),
+			output: true,
+		},
+		"check parse_ip bad": {
+			input: methods(
+				literalFn("300.1.2.3"),
+				method("parse_ip"),
+			),
+			err: "string literal: failed to parse '300.1.2.3' as an IP address",
+		},
+		"check ip_in_cidr": {
+			input: methods(
+				literalFn([]byte("172.31.255.255")),
+				method("ip_in_cidr", "172.16.0.0/12"),
+			),
+			output: true,
+		},
+		"check ip_in_cidr mixed versions": {
+			input: methods(
+				literalFn("::1"),
+				method("ip_in_cidr", "0.0.0.0/0"),
+			),
+			output: false,
+		},
+		"check cidr_contains": {
+			input: methods(
+				literalFn("10.0.0.0/24"),
+				method("cidr_contains", "10.0.1.1"),
+			),
+			output: false,
+		},
+		"check cidr_contains bad network": {
+			input: methods(
+				literalFn("10.0.0.0"),
+				method("cidr_contains", "10.0.0.1"),
+			),
+			err: "string literal: failed to parse '10.0.0.0' as a CIDR network",
+		},
+		"check parse_mac local multicast": {
+			input: methods(
+				literalFn("03:00:00:00:00:01"),
+				method("parse_mac"),
+			),
+			output: map[string]interface{}{
+				"mac":          "03:00:00:00:00:01",
+				"oui":          "03:00:00",
+				"is_local":     true,
+				"is_multicast": true,
+			},
+		},
+		"check parse_mac bare": {
+			input: methods(
+				literalFn("001A2B3C4D5E"),
+				method("parse_mac"),
+				method("get", "mac"),
+			),
+			output: "00:1a:2b:3c:4d:5e",
+		},
+		"check parse_mac bad": {
+			input: methods(
+				literalFn("00:1a:2b:3c:4d"),
+				method("parse_mac"),
+			),
+			err: "string literal: failed to parse '00:1a:2b:3c:4d' as a MAC address",
+		},
+		"check format_mac dot": {
+			input: methods(
+				literalFn("00-1A-2B-3C-4D-5E"),
+				method("format_mac", "dot"),
+			),
+			output: "001a.2b3c.4d5e",
+		},
+		"check format_mac bare uppercase": {
+			input: methods(
+				literalFn([]byte("00:1a:2b:3c:4d:5e")),
+				method("format_mac", "bare", true),
+			),
+			output: "001A2B3C4D5E",
+		},
+		"check format_mac eui64": {
+			input: methods(
+				literalFn("0000.5e00.5301.0203"),
+				method("format_mac"),
+			),
+			output: "00:00:5e:00:53:01:02:03",
+		},
+		"check parse_phone international": {
+			input: methods(
+				literalFn("0033 1 23 45 67 89"),
+				method("parse_phone", "GB"),
+			),
+			output: map[string]interface{}{
+				"e164":            "+33123456789",
+				"country_code":    int64(33),
+				"national_number": "123456789",
+				"region":          "FR",
+				"extension":       "",
+				"valid":           true,
+			},
+		},
+		"check parse_phone shared code": {
+			input: methods(
+				literalFn("1-613-555-0199"),
+				method("parse_phone", "ca"),
+			),
+			output: map[string]interface{}{
+				"e164":            "+16135550199",
+				"country_code":    int64(1),
+				"national_number": "6135550199",
+				"region":          "CA",
+				"extension":       "",
+				"valid":           true,
+			},
+		},
+		"check parse_phone nanp exit code": {
+			input: methods(
+				literalFn("011 44 7700 900123"),
+				method("parse_phone", "US"),
+				method("get", "e164"),
+			),
+			output: "+447700900123",
+		},
+		"check parse_phone italy keeps leading zero": {
+			input: methods(
+				literalFn("06 1234 5678"),
+				method("parse_phone", "IT"),
+				method("get", "e164"),
+			),
+			output: "+390612345678",
+		},
+		"check parse_phone invalid length": {
+			input: methods(
+				literalFn("+33 1 23 45"),
+				method("parse_phone"),
+				method("get", "valid"),
+			),
+			output: false,
+		},
+		"check parse_phone invalid nanp": {
+			input: methods(
+				literalFn("(123) 555-0199"),
+				method("parse_phone", "US"),
+				method("get", "valid"),
+			),
+			output: false,
+		},
+		"check parse_phone no region": {
+			input: methods(
+				literalFn("020 7946 0018"),
+				method("parse_phone"),
+			),
+			err: "string literal: failed to parse '020 7946 0018' as a phone number: a region is required for numbers in national format",
+		},
+		"check parse_phone letters": {
+			input: methods(
+				literalFn("1-800-FLOWERS"),
+				method("parse_phone", "US"),
+			),
+			err: "string literal: failed to parse '1-800-FLOWERS' as a phone number: unexpected character 'f'",
+		},
+		"check detect_encoding utf-16le": {
+			input: methods(
+				literalFn([]byte("h\x00i\x00")),
+				method("detect_encoding"),
+			),
+			output: "utf-16le",
+		},
+		"check detect_encoding utf-16be bom": {
+			input: methods(
+				literalFn([]byte("\xfe\xff\x00h")),
+				method("detect_encoding"),
+			),
+			output: "utf-16be",
+		},
+		"check detect_encoding windows-1252": {
+			input: methods(
+				literalFn([]byte("\x93quoted\x94")),
+				method("detect_encoding"),
+			),
+			output: "windows-1252",
+		},
+		"check convert_encoding auto": {
+			input: methods(
+				literalFn([]byte("\xff\xfec\x00a\x00f\x00\xe9\x00")),
+				method("convert_encoding", "auto", "utf-8"),
+			),
+			output: []byte("café"),
+		},
+		"check convert_encoding to latin1": {
+			input: methods(
+				literalFn("café"),
+				method("convert_encoding", "utf-8", "latin1"),
+			),
+			output: []byte("caf\xe9"),
+		},
+		"check convert_encoding unsupported rune": {
+			input: methods(
+				literalFn("日本"),
+				method("convert_encoding", "utf-8", "iso-8859-1"),
+			),
+			err: "string literal: failed to convert encoding to iso-8859-1: encoding: rune not supported by encoding.",
+		},
+		"check convert_encoding shift_jis": {
+			input: methods(
+				literalFn([]byte("\x93\xfa\x96\x7b")),
+				method("convert_encoding", "Shift_JIS", "utf-8"),
+			),
+			output: []byte("日本"),
+		},
 		"check reverse": {
 			input: methods(
 				function(`content`),
@@ -2140,3 +2851,115 @@ func TestMethodNoArgsTargets(t *testing.T) {
 		assert.Contains(t, targets, exp, "method: %v", k)
 	}
 }
+
+func TestRandomIntMethod(t *testing.T) {
+	randomInt := func(v interface{}, args ...interface{}) interface{} {
+		t.Helper()
+		fn, err := InitMethodHelper("random_int", NewLiteralFunction("", v), args...)
+		require.NoError(t, err)
+		res, err := fn.Exec(FunctionContext{})
+		require.NoError(t, err)
+		return res
+	}
+
+	// Integer seeds match the first value of the seeded function.
+	fn, err := InitFunctionHelper("random_int", int64(10))
+	require.NoError(t, err)
+	exp, err := fn.Exec(FunctionContext{})
+	require.NoError(t, err)
+	assert.Equal(t, exp, randomInt(int64(10)))
+	assert.Equal(t, exp, randomInt(json.Number("10")))
+
+	// The same values always produce the same numbers, whereas different
+	// values are spread across the range.
+	tallies := map[int64]int64{}
+	for i := 0; i < 1000; i++ {
+		id := "user-" + strconv.Itoa(i)
+		res := randomInt(id, int64(10))
+		require.IsType(t, int64(0), res)
+		assert.Equal(t, res, randomInt(id, int64(10)))
+		assert.Equal(t, res, randomInt([]byte(id), int64(10)))
+
+		v := res.(int64)
+		require.True(t, v >= 0 && v < 10, v)
+		tallies[v]++
+	}
+	assert.Len(t, tallies, 10)
+	for _, v := range tallies {
+		assert.Greater(t, v, int64(50))
+	}
+
+	// A single instance produces the same numbers when executed concurrently
+	// and after values with other seeds.
+	expected := map[string]interface{}{}
+	for i := 0; i < 20; i++ {
+		id := "user-" + strconv.Itoa(i)
+		expected[id] = randomInt(id, int64(1000))
+	}
+	fn, err = InitMethodHelper("random_int", NewFieldFunction("id"), int64(1000))
+	require.NoError(t, err)
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				id := "user-" + strconv.Itoa((i+j)%20)
+				res, err := fn.Exec(FunctionContext{}.WithValue(map[string]interface{}{"id": id}))
+				assert.NoError(t, err)
+				assert.Equal(t, expected[id], res)
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	_, err = InitMethodHelper("random_int", NewLiteralFunction("", "foo"), int64(0))
+	require.EqualError(t, err, "max must be greater than zero, got 0")
+
+	fn, err = InitMethodHelper("random_int", NewLiteralFunction("", nil))
+	require.NoError(t, err)
+	_, err = fn.Exec(FunctionContext{})
+	require.Error(t, err)
+}
+
+func TestStringMethodLimits(t *testing.T) {
+	for _, name := range []string{"repeat", "pad_left", "pad_right"} {
+		_, err := InitMethodHelper(name, NewLiteralFunction("", "foo"), int64(-1))
+		require.Error(t, err, name)
+		assert.Contains(t, err.Error(), "must be a non-negative integer", name)
+	}
+
+	for _, name := range []string{"pad_left", "pad_right"} {
+		_, err := InitMethodHelper(name, NewLiteralFunction("", "foo"), int64(maxStringMethodBytes)+1)
+		require.Error(t, err, name)
+		assert.Contains(t, err.Error(), "must not exceed", name)
+
+		// Multi-byte fill characters count towards the limit.
+		fn, err := InitMethodHelper(name, NewLiteralFunction("", "foo"), int64(maxStringMethodBytes), "é")
+		require.NoError(t, err, name)
+		_, err = fn.Exec(FunctionContext{})
+		require.Error(t, err, name)
+		assert.Contains(t, err.Error(), "would exceed 16777216 bytes", name)
+	}
+
+	fn, err := InitMethodHelper("repeat", NewLiteralFunction("", "foo"), int64(maxStringMethodBytes/3+1))
+	require.NoError(t, err)
+	_, err = fn.Exec(FunctionContext{})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "would exceed 16777216 bytes")
+
+	fn, err = InitMethodHelper("repeat", NewLiteralFunction("", ""), int64(math.MaxInt64))
+	require.NoError(t, err)
+	res, err := fn.Exec(FunctionContext{})
+	require.NoError(t, err)
+	assert.Equal(t, "", res)
+
+	_, err = InitMethodHelper("mask", NewLiteralFunction("", "foo"), int64(0), int64(0), "")
+	require.EqualError(t, err, "char must not be empty")
+
+	fn, err = InitMethodHelper("pad_left", NewLiteralFunction("", "foo"), int64(8), "é")
+	require.NoError(t, err)
+	res, err = fn.Exec(FunctionContext{})
+	require.NoError(t, err)
+	assert.Equal(t, "éééééfoo", res)
+}
@@ -10,15 +10,20 @@ import (
 
 // ParamDefinition describes a single parameter for a function or method.
 type ParamDefinition struct {
-	Name        string
-	Description string
-	ValueType   ValueType
+	Name        string    `json:"name"`
+	Description string    `json:"description"`
+	ValueType   ValueType `json:"type"`
 
 	// IsOptional is implicit when there's a DefaultValue. However, there are
 	// times when a parameter is used to change behaviour without having a
 	// default.
-	IsOptional   bool
-	DefaultValue *interface{}
+	IsOptional   bool         `json:"is_optional"`
+	DefaultValue *interface{} `json:"default,omitempty"`
+
+	// IsLazy indicates that a dynamic argument should not be resolved before
+	// the function or method is executed, and is instead only resolved when
+	// the function or method needs it.
+	IsLazy bool `json:"-"`
 }
 
 func (d ParamDefinition) validate() error {
@@ -98,6 +103,15 @@ func (d ParamDefinition) Optional() ParamDefinition {
 	return d
 }
 
+// Lazy marks the parameter as lazily evaluated, where a dynamic argument is
+// only executed when the function or method requires it rather than before
+// each execution. This is useful for fallback values that might be expensive
+// to compute. Lazy arguments must be accessed with FieldLazy.
+func (d ParamDefinition) Lazy() ParamDefinition {
+	d.IsLazy = true
+	return d
+}
+
 // Default adds a default value to a parameter, also making it implicitly
 // optional.
 func (d ParamDefinition) Default(v interface{}) ParamDefinition {
@@ -144,7 +158,7 @@ func (d ParamDefinition) parseArgValue(v interface{}) (interface{}, error) {
 type Params struct {
 	oldStyle    bool
 	variadic    bool
-	Definitions []ParamDefinition
+	Definitions []ParamDefinition `json:"named,omitempty"`
 
 	// Used by parsed param frames, we instantiate this here so that it's
 	// allocated only once at parse time rather than execution time.
@@ -257,7 +271,7 @@ func (p Params) gatherDynamicArgs(args []interface{}) (dynArgs []dynamicArgIndex
 		return
 	}
 	for i, param := range p.Definitions {
-		if param.ValueType == ValueQuery {
+		if param.ValueType == ValueQuery || param.IsLazy {
 			continue
 		}
 		if fn, isFn := args[i].(Function); isFn {
@@ -401,9 +415,27 @@ func (p Params) processNamed(args map[string]interface{}) ([]interface{}, error)
 // ParsedParams is a reference to the arguments of a method or function
 // instantiation.
 type ParsedParams struct {
-	source  Params
-	dynArgs []dynamicArgIndex
-	values  []interface{}
+	source     Params
+	dynArgs    []dynamicArgIndex
+	values     []interface{}
+	mappingDir string
+}
+
+// WithMappingDir returns a copy of the parsed parameters with the directory of
+// the mapping file that they were parsed from.
+func (p *ParsedParams) WithMappingDir(dir string) *ParsedParams {
+	newP := *p
+	newP.mappingDir = dir
+	return &newP
+}
+
+// MappingDir returns the directory of the mapping file that the parameters were
+// parsed from, or an empty string if the mapping was not read from a file.
+func (p *ParsedParams) MappingDir() string {
+	if p == nil {
+		return ""
+	}
+	return p.mappingDir
 }
 
 // dynamic returns any argument functions that must be evaluated at query time.
@@ -447,8 +479,9 @@ func (p *ParsedParams) ResolveDynamic(ctx FunctionContext) (*ParsedParams, error
 		}
 	}
 	return &ParsedParams{
-		source: p.source,
-		values: newValues,
+		source:     p.source,
+		values:     newValues,
+		mappingDir: p.mappingDir,
 	}, nil
 }
 
@@ -586,6 +619,35 @@ func (p *ParsedParams) FieldQuery(n string) (Function, error) {
 	return f, nil
 }
 
+// FieldLazy returns the argument of a lazy parameter with a given name as a
+// function that only resolves the argument when executed, or nil if the
+// parameter is optional and was not defined. Static arguments are returned as
+// a literal function.
+func (p *ParsedParams) FieldLazy(n string) (Function, error) {
+	v, err := p.Field(n)
+	if err != nil {
+		return nil, err
+	}
+	if v == nil {
+		return nil, nil
+	}
+	fn, isDyn := v.(Function)
+	if !isDyn {
+		return NewLiteralFunction("", v), nil
+	}
+	def := p.source.Definitions[p.source.nameToIndex[n]]
+	return ClosureFunction(fn.Annotation(), func(ctx FunctionContext) (interface{}, error) {
+		v, err := fn.Exec(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to extract input arg %v: %w", def.Name, err)
+		}
+		if v, err = def.parseArgValue(v); err != nil {
+			return nil, fmt.Errorf("failed to extract input arg %v: %w", def.Name, err)
+		}
+		return v, nil
+	}, fn.QueryTargets), nil
+}
+
 // FieldOptionalQuery returns a query argument value with a given name if it
 // was defined, otherwise nil.
 func (p *ParsedParams) FieldOptionalQuery(n string) (Function, error) {
@@ -30,6 +30,12 @@ func TestFunctionExamples(t *testing.T) {
 		os.Unsetenv(key)
 	})
 
+	secretKey := "DB_PASSWORD"
+	os.Setenv(secretKey, "hunter2")
+	t.Cleanup(func() {
+		os.Unsetenv(secretKey)
+	})
+
 	for _, spec := range query.FunctionDocs() {
 		spec := spec
 		t.Run(spec.Name, func(t *testing.T) {
@@ -116,16 +116,17 @@ func (ctx TargetsContext) WithValuesAsContext() TargetsContext {
 	return ctx
 }
 
-// WithContextAsNamed moves the latest context into a named context and returns
-// the context prior to that one to the main context. This is a way for named
-// context mappings to correct the contexts so that the child query function
-// returns the right paths.
-func (ctx TargetsContext) WithContextAsNamed(name string) TargetsContext {
-	previous := ctx.namedContext
-	ctx.namedContext = &namedContextPath{
-		name:  name,
-		paths: ctx.mainContext,
-		next:  previous,
+// WithContextAsNamed moves the latest context into one or more named contexts
+// and returns the context prior to that one to the main context. This is a way
+// for named context mappings to correct the contexts so that the child query
+// function returns the right paths.
+func (ctx TargetsContext) WithContextAsNamed(names ...string) TargetsContext {
+	for _, name := range names {
+		ctx.namedContext = &namedContextPath{
+			name:  name,
+			paths: ctx.mainContext,
+			next:  ctx.namedContext,
+		}
 	}
 	if ctx.prevContext != nil {
 		ctx.mainContext = ctx.prevContext.paths
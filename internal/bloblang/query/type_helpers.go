@@ -5,6 +5,7 @@ import (
 	"errors"
 	"fmt"
 	"math"
+	"math/big"
 	"strconv"
 	"strings"
 	"time"
@@ -23,6 +24,59 @@ func SliceToDotPath(path ...string) string {
 	return strings.Join(escapes, ".")
 }
 
+// resolvedPath is a field path where the array index of each segment is parsed
+// ahead of time, allowing values to be extracted from a structure without
+// allocating.
+type resolvedPath struct {
+	segments []string
+	indexes  []int
+	wildcard bool
+}
+
+func resolvePath(path []string) resolvedPath {
+	r := resolvedPath{
+		segments: path,
+		indexes:  make([]int, len(path)),
+	}
+	for i, s := range path {
+		if s == "*" {
+			r.wildcard = true
+		}
+		index, err := strconv.Atoi(s)
+		if err != nil || index < 0 {
+			index = -1
+		}
+		r.indexes[i] = index
+	}
+	return r
+}
+
+// get returns the value at the path within a structure, or nil if the path
+// does not exist, following the same rules as gabs.Container.Search.
+func (r resolvedPath) get(v interface{}) interface{} {
+	if r.wildcard {
+		return gabs.Wrap(v).S(r.segments...).Data()
+	}
+	for i, s := range r.segments {
+		switch t := v.(type) {
+		case map[string]interface{}:
+			var exists bool
+			if v, exists = t[s]; !exists {
+				return nil
+			}
+		case []interface{}:
+			index := r.indexes[i]
+			if index < 0 || index >= len(t) {
+				return nil
+			}
+			v = t[index]
+		default:
+			return nil
+		}
+	}
+	return v
+}
+
 //------------------------------------------------------------------------------
 
 // ValueType represents a discrete value type supported by Bloblang queries.
@@ -55,7 +109,7 @@ func ITypeOf(i interface{}) ValueType {
 		return ValueString
 	case []byte:
 		return ValueBytes
-	case int, int64, uint64, float64, json.Number:
+	case int, int64, uint64, float64, json.Number, *big.Int, *Decimal:
 		return ValueNumber
 	case bool:
 		return ValueBool
@@ -100,6 +154,11 @@ func IGetNumber(v interface{}) (float64, error) {
 		return t, nil
 	case json.Number:
 		return t.Float64()
+	case *big.Int:
+		f, _ := new(big.Float).SetInt(t).Float64()
+		return f, nil
+	case *Decimal:
+		return t.Float64(), nil
 	}
 	return 0, NewTypeError(v, ValueNumber)
 }
@@ -125,10 +184,54 @@ func IGetInt(v interface{}) (int64, error) {
 			return int64(f), nil
 		}
 		return 0, err
+	case *big.Int:
+		if !t.IsInt64() {
+			return 0, fmt.Errorf("big integer value %v is too large to be cast as a 64-bit integer", t)
+		}
+		return t.Int64(), nil
+	case *Decimal:
+		return IGetInt(t.Int())
 	}
 	return 0, NewTypeError(v, ValueNumber)
 }
 
+// IGetBigInt takes a boxed value and attempts to extract an arbitrary precision
+// integer from it. Float values are only accepted when they have no fractional
+// component.
+func IGetBigInt(v interface{}) (*big.Int, error) {
+	switch t := v.(type) {
+	case *big.Int:
+		return t, nil
+	case int:
+		return big.NewInt(int64(t)), nil
+	case int64:
+		return big.NewInt(t), nil
+	case uint64:
+		return new(big.Int).SetUint64(t), nil
+	case float64:
+		if math.IsInf(t, 0) || math.IsNaN(t) || t != math.Trunc(t) {
+			return nil, fmt.Errorf("float value %v cannot be represented as an integer", t)
+		}
+		i, _ := big.NewFloat(t).Int(nil)
+		return i, nil
+	case json.Number:
+		if i, ok := new(big.Int).SetString(t.String(), 10); ok {
+			return i, nil
+		}
+		f, err := t.Float64()
+		if err != nil {
+			return nil, err
+		}
+		return IGetBigInt(f)
+	case *Decimal:
+		if !t.IsInt() {
+			return nil, fmt.Errorf("decimal value %v cannot be represented as an integer", t)
+		}
+		return t.Int(), nil
+	}
+	return nil, NewTypeError(v, ValueNumber)
+}
+
 // IGetBool takes a boxed value and attempts to extract a boolean from it.
 func IGetBool(v interface{}) (bool, error) {
 	switch t := v.(type) {
@@ -144,6 +247,10 @@ func IGetBool(v interface{}) (bool, error) {
 		return t != 0, nil
 	case json.Number:
 		return t.String() != "0", nil
+	case *big.Int:
+		return t.Sign() != 0, nil
+	case *Decimal:
+		return t.Sign() != 0, nil
 	}
 	return false, NewTypeError(v, ValueBool)
 }
@@ -217,11 +324,11 @@ func IIsNull(i interface{}) bool {
 }
 
 // ISanitize takes a boxed value of any type and attempts to convert it into one
-// of the following types: string, []byte, int64, uint64, float64, bool,
-// []interface{}, map[string]interface{}, Delete, Nothing.
+// of the following types: string, []byte, int64, uint64, float64, *big.Int,
+// *Decimal, bool, []interface{}, map[string]interface{}, Delete, Nothing.
 func ISanitize(i interface{}) interface{} {
 	switch t := i.(type) {
-	case string, []byte, int64, uint64, float64, bool, []interface{}, map[string]interface{}, Delete, Nothing:
+	case string, []byte, int64, uint64, float64, bool, []interface{}, map[string]interface{}, Delete, Nothing, *big.Int, *Decimal:
 		return i
 	case json.RawMessage:
 		return []byte(t)
@@ -260,6 +367,10 @@ func IToBytes(i interface{}) []byte {
 		return t
 	case json.Number:
 		return []byte(t.String())
+	case *big.Int:
+		return []byte(t.String())
+	case *Decimal:
+		return []byte(t.String())
 	case int64, uint64, float64:
 		return []byte(fmt.Sprintf("%v", t)) // TODO
 	case bool:
@@ -286,6 +397,10 @@ func IToString(i interface{}) string {
 		return fmt.Sprintf("%v", t) // TODO
 	case json.Number:
 		return t.String()
+	case *big.Int:
+		return t.String()
+	case *Decimal:
+		return t.String()
 	case bool:
 		if t {
 			return "true"
@@ -312,6 +427,8 @@ func IToNumber(v interface{}) (float64, error) {
 		return t, nil
 	case json.Number:
 		return t.Float64()
+	case *big.Int, *Decimal:
+		return IGetNumber(t)
 	case []byte:
 		return strconv.ParseFloat(string(t), 64)
 	case string:
@@ -340,6 +457,8 @@ func IToInt(v interface{}) (int64, error) {
 		return int64(t), nil
 	case json.Number:
 		return t.Int64()
+	case *big.Int, *Decimal:
+		return IGetInt(t)
 	case []byte:
 		return strconv.ParseInt(string(t), 10, 64)
 	case string:
@@ -348,6 +467,26 @@ func IToInt(v interface{}) (int64, error) {
 	return 0, NewTypeError(v, ValueNumber)
 }
 
+// IToBigInt takes a boxed value and attempts to extract an arbitrary precision
+// integer from it or parse one. Strings may use a 0x, 0o or 0b prefix in order
+// to specify a base other than 10.
+func IToBigInt(v interface{}) (*big.Int, error) {
+	var s string
+	switch t := v.(type) {
+	case []byte:
+		s = string(t)
+	case string:
+		s = t
+	default:
+		return IGetBigInt(v)
+	}
+	i, ok := new(big.Int).SetString(strings.TrimSpace(s), 0)
+	if !ok {
+		return nil, fmt.Errorf("failed to parse '%v' as an integer", s)
+	}
+	return i, nil
+}
+
 // IToBool takes a boxed value and attempts to extract a boolean from it or
 // parse it into a bool.
 func IToBool(v interface{}) (bool, error) {
@@ -364,6 +503,10 @@ func IToBool(v interface{}) (bool, error) {
 		return t != 0, nil
 	case json.Number:
 		return t.String() != "0", nil
+	case *big.Int:
+		return t.Sign() != 0, nil
+	case *Decimal:
+		return t.Sign() != 0, nil
 	case []byte:
 		if v, err := strconv.ParseBool(string(t)); err == nil {
 			return v, nil
@@ -0,0 +1,55 @@
+package query
+
+// UserFunctionParamTypes lists the type names that can be used in order to
+// constrain the parameters of a user defined function.
+var UserFunctionParamTypes = map[string]ValueType{
+	"string":  ValueString,
+	"number":  ValueNumber,
+	"integer": ValueInt,
+	"bool":    ValueBool,
+	"array":   ValueArray,
+	"object":  ValueObject,
+	"any":     ValueUnknown,
+}
+
+// UserFunction is a function declared within a mapping, where the arguments of
+// each call are captured as named values when executing the body.
+type UserFunction struct {
+	name   string
+	params Params
+	body   Function
+}
+
+// NewUserFunction creates a user defined function from a parameters definition
+// and a query function to execute as its body.
+func NewUserFunction(name string, params Params, body Function) *UserFunction {
+	return &UserFunction{
+		name:   name,
+		params: params,
+		body:   body,
+	}
+}
+
+// Name returns the name of the function.
+func (u *UserFunction) Name() string {
+	return u.name
+}
+
+// Params returns the parameters definition of the function.
+func (u *UserFunction) Params() Params {
+	return u.params
+}
+
+// Init creates a call to the function with a set of arguments, where any
+// dynamic arguments are resolved each time the call is executed.
+func (u *UserFunction) Init(args *ParsedParams) (Function, error) {
+	return wrapCtorWithDynamicArgs(u.name, args, func(args *ParsedParams) (Function, error) {
+		values := args.Raw()
+		return ClosureFunction("function "+u.name, func(ctx FunctionContext) (interface{}, error) {
+			for i, def := range u.params.Definitions {
+				ctx = ctx.WithNamedValue(def.Name, values[i])
+			}
+			return u.body.Exec(ctx)
+		}, u.body.QueryTargets), nil
+	})
+}
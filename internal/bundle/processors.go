@@ -54,7 +54,45 @@ func (s *ProcessorSet) Init(conf processor.Config, mgr NewManagement) (types.Pro
 		}
 		return nil, types.ErrInvalidProcessorType
 	}
-	return spec.constructor(conf, mgr)
+	p, err := spec.constructor(conf, mgr)
+	if err != nil {
+		return nil, err
+	}
+	return &failContextProcessor{
+		Processor: p,
+		label:     conf.Label,
+		path:      mgr.Label(),
+	}, nil
+}
+
+// failContextProcessor wraps a processor in order to record the label and path
+// of the processor on message parts that it flags with new errors, which allows
+// errors to be traced back to their origin within a config. Flagging an error
+// removes the context of any previous error, and therefore parts with an error
+// but without a path were flagged by this processor, whereas errors that
+// already existed or were flagged by a child processor are left untouched.
+type failContextProcessor struct {
+	types.Processor
+	label string
+	path  string
+}
+
+func (f *failContextProcessor) ProcessMessage(msg types.Message) ([]types.Message, types.Response) {
+	msgs, res := f.Processor.ProcessMessage(msg)
+	for _, m := range msgs {
+		_ = m.Iter(func(_ int, p types.Part) error {
+			meta := p.Metadata()
+			if meta.Get(types.FailFlagKey) == "" || meta.Get(types.FailPathKey) != "" {
+				return nil
+			}
+			if f.label != "" {
+				meta.Set(types.FailLabelKey, f.label)
+			}
+			meta.Set(types.FailPathKey, f.path)
+			return nil
+		})
+	}
+	return msgs, res
 }
 
 // Docs returns a slice of processor specs, which document each method.
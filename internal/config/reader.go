@@ -3,8 +3,12 @@ package config
 import (
 	"bytes"
 	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
 	"strings"
 
+	"github.com/Jeffail/benthos/v3/internal/bloblang"
 	"github.com/Jeffail/benthos/v3/internal/docs"
 	"github.com/Jeffail/benthos/v3/lib/config"
 	"github.com/Jeffail/benthos/v3/lib/manager"
@@ -15,16 +19,21 @@ import (
 // Reader provides utilities for parsing a Benthos config as a main file with
 // a collection of resource files, and options such as overrides.
 type Reader struct {
-	mainPath      string
+	mainPaths     []string
 	resourcePaths []string
 	overrides     []string
+	lintRules     *docs.LintRules
+	bloblEnv      *bloblang.Environment
 }
 
-// NewReader creates a new config reader.
-func NewReader(mainPath string, resourcePaths []string, opts ...OptFunc) *Reader {
+// NewReader creates a new config reader. When multiple main paths are provided
+// they are deep merged in order, where each path may also be a directory of
+// config files that are merged in lexical order.
+func NewReader(mainPaths []string, resourcePaths []string, opts ...OptFunc) *Reader {
 	r := &Reader{
-		mainPath:      mainPath,
+		mainPaths:     mainPaths,
 		resourcePaths: resourcePaths,
+		bloblEnv:      bloblang.GlobalEnvironment(),
 	}
 	for _, opt := range opts {
 		opt(r)
@@ -45,8 +54,49 @@ func OptAddOverrides(overrides ...string) OptFunc {
 	}
 }
 
+// OptSetLintRules sets custom lint rules that the main config and resource
+// files are linted against.
+func OptSetLintRules(rules *docs.LintRules) OptFunc {
+	return func(r *Reader) {
+		r.lintRules = rules
+	}
+}
+
+// OptSetBloblangEnvironment sets the environment used to parse the bloblang
+// mappings of configs when they're linted, which should match the environment
+// of the manager that components of the config are created with.
+func OptSetBloblangEnvironment(env *bloblang.Environment) OptFunc {
+	return func(r *Reader) {
+		r.bloblEnv = env
+	}
+}
+
 //------------------------------------------------------------------------------
 
+func (r *Reader) lintContext() docs.LintContext {
+	ctx := docs.NewLintContext()
+	ctx.BloblangEnv = r.bloblEnv
+	return ctx
+}
+
+// lintRulesFile returns lints of the raw contents of a config file, where
+// environment variables have not been replaced, against the custom lint rules
+// of the reader.
+func (r *Reader) lintRulesFile(path string, spec docs.FieldSpecs) ([]docs.Lint, error) {
+	if r.lintRules == nil {
+		return nil, nil
+	}
+	rawBytes, _, err := config.ReadWithJSONPointersLinted(path, false)
+	if err != nil {
+		return nil, err
+	}
+	var rawNode yaml.Node
+	if err := yaml.Unmarshal(rawBytes, &rawNode); err != nil {
+		return nil, err
+	}
+	return r.lintRules.LintYAML(r.lintContext(), spec, &rawNode), nil
+}
+
 func applyOverrides(specs docs.FieldSpecs, root *yaml.Node, overrides ...string) error {
 	for _, override := range overrides {
 		eqIndex := strings.Index(override, "=")
@@ -71,44 +121,115 @@ func applyOverrides(specs docs.FieldSpecs, root *yaml.Node, overrides ...string)
 	return nil
 }
 
-func (r *Reader) readMain(conf *config.Type) (lints []string, err error) {
+// MainPaths returns the files that make up the main config in the order that
+// they are merged, where directories are expanded into the YAML files they
+// contain.
+func (r *Reader) MainPaths() ([]string, error) {
+	var paths []string
+	for _, p := range r.mainPaths {
+		info, err := os.Stat(p)
+		if err != nil {
+			return nil, err
+		}
+		if !info.IsDir() {
+			paths = append(paths, p)
+			continue
+		}
+		var dirPaths []string
+		if err := filepath.Walk(p, func(path string, info os.FileInfo, err error) error {
+			if err != nil {
+				return err
+			}
+			if !info.IsDir() && (strings.HasSuffix(path, ".yaml") || strings.HasSuffix(path, ".yml")) {
+				dirPaths = append(dirPaths, path)
+			}
+			return nil
+		}); err != nil {
+			return nil, err
+		}
+		sort.Strings(dirPaths)
+		paths = append(paths, dirPaths...)
+	}
+	return paths, nil
+}
+
+func (r *Reader) readMainFile(path string, node *yaml.Node) (lints []string, err error) {
 	defer func() {
-		if err != nil && r.mainPath != "" {
-			err = fmt.Errorf("%v: %w", r.mainPath, err)
+		if err != nil {
+			err = fmt.Errorf("%v: %w", path, err)
 		}
 	}()
 
-	if r.mainPath == "" && len(r.overrides) == 0 {
+	var confBytes []byte
+	if confBytes, lints, err = config.ReadWithJSONPointersLinted(path, true); err != nil {
+		return
+	}
+	if err = yaml.Unmarshal(confBytes, node); err != nil {
 		return
 	}
 
-	var rawNode yaml.Node
-	var confBytes []byte
-	if r.mainPath != "" {
-		if confBytes, lints, err = config.ReadWithJSONPointersLinted(r.mainPath, true); err != nil {
+	if !bytes.HasPrefix(confBytes, []byte("# BENTHOS LINT DISABLE")) {
+		confSpec := config.Spec()
+		dLints := confSpec.LintYAML(r.lintContext(), node)
+		var ruleLints []docs.Lint
+		if ruleLints, err = r.lintRulesFile(path, confSpec); err != nil {
 			return
 		}
-		if err = yaml.Unmarshal(confBytes, &rawNode); err != nil {
-			return
+		for _, lint := range append(dLints, ruleLints...) {
+			// Warnings are reported by the lint subcommand but must not
+			// prevent a config from running.
+			if lint.Level != docs.LintError {
+				continue
+			}
+			lints = append(lints, fmt.Sprintf("%v: line %v: %v", path, lint.Line, lint.What))
 		}
 	}
+	return
+}
 
-	confSpec := config.Spec()
-	if err = applyOverrides(confSpec, &rawNode, r.overrides...); err != nil {
+func (r *Reader) readMain(conf *config.Type) (lints []string, err error) {
+	var paths []string
+	if paths, err = r.MainPaths(); err != nil {
+		return
+	}
+	if len(paths) == 0 && len(r.overrides) == 0 {
 		return
 	}
 
-	if !bytes.HasPrefix(confBytes, []byte("# BENTHOS LINT DISABLE")) {
-		lintFilePrefix := ""
-		if r.mainPath != "" {
-			lintFilePrefix = fmt.Sprintf("%v: ", r.mainPath)
+	confSpec := config.Spec()
+
+	// Each file is linted in isolation so that lints refer to the correct
+	// file and line, and is then merged over the files before it.
+	var rawNode yaml.Node
+	for _, path := range paths {
+		var fileNode yaml.Node
+		var fileLints []string
+		if fileLints, err = r.readMainFile(path, &fileNode); err != nil {
+			return
 		}
-		for _, lint := range confSpec.LintYAML(docs.NewLintContext(), &rawNode) {
-			lints = append(lints, fmt.Sprintf("%vline %v: %v", lintFilePrefix, lint.Line, lint.What))
+		lints = append(lints, fileLints...)
+		if rawNode.Kind == 0 {
+			rawNode = fileNode
+		} else if fileNode.Kind != 0 {
+			confSpec.MergeYAML(nil, &rawNode, &fileNode)
 		}
 	}
 
-	err = rawNode.Decode(conf)
+	if err = applyOverrides(confSpec, &rawNode, r.overrides...); err != nil {
+		return
+	}
+	if len(paths) == 0 {
+		for _, lint := range confSpec.LintYAML(r.lintContext(), &rawNode) {
+			if lint.Level != docs.LintError {
+				continue
+			}
+			lints = append(lints, fmt.Sprintf("line %v: %v", lint.Line, lint.What))
+		}
+	}
+
+	if err = rawNode.Decode(conf); err != nil && len(paths) > 0 {
+		err = fmt.Errorf("%v: %w", strings.Join(paths, ", "), err)
+	}
 	return
 }
 
@@ -116,7 +237,7 @@ func (r *Reader) readResources(conf *config.Type) (lints []string, err error) {
 	for _, path := range r.resourcePaths {
 		rconf := manager.NewResourceConfig()
 		var rLints []string
-		if rLints, err = readResource(path, &rconf); err != nil {
+		if rLints, err = r.readResource(path, &rconf); err != nil {
 			return
 		}
 		lints = append(lints, rLints...)
@@ -128,7 +249,7 @@ func (r *Reader) readResources(conf *config.Type) (lints []string, err error) {
 	return
 }
 
-func readResource(path string, conf *manager.ResourceConfig) (lints []string, err error) {
+func (r *Reader) readResource(path string, conf *manager.ResourceConfig) (lints []string, err error) {
 	defer func() {
 		if err != nil {
 			err = fmt.Errorf("%v: %w", path, err)
@@ -145,7 +266,15 @@ func readResource(path string, conf *manager.ResourceConfig) (lints []string, er
 		return
 	}
 	if !bytes.HasPrefix(confBytes, []byte("# BENTHOS LINT DISABLE")) {
-		for _, lint := range manager.Spec().LintYAML(docs.NewLintContext(), &rawNode) {
+		dLints := manager.Spec().LintYAML(r.lintContext(), &rawNode)
+		var ruleLints []docs.Lint
+		if ruleLints, err = r.lintRulesFile(path, manager.Spec()); err != nil {
+			return
+		}
+		for _, lint := range append(dLints, ruleLints...) {
+			if lint.Level != docs.LintError {
+				continue
+			}
 			lints = append(lints, fmt.Sprintf("resource file %v: line %v: %v", path, lint.Line, lint.What))
 		}
 	}
@@ -154,7 +283,9 @@ func readResource(path string, conf *manager.ResourceConfig) (lints []string, er
 	return
 }
 
-// Read a Benthos config from the files and options specified.
+// Read a Benthos config from the files and options specified, and set the
+// constants of the config so that they can be referenced by the components
+// created from it.
 func (r *Reader) Read(conf *config.Type) (lints []string, err error) {
 	if lints, err = r.readMain(conf); err != nil {
 		return
@@ -164,5 +295,8 @@ func (r *Reader) Read(conf *config.Type) (lints []string, err error) {
 		return
 	}
 	lints = append(lints, rLints...)
+	if err = bloblang.SetConstants(conf.Constants); err != nil {
+		err = fmt.Errorf("failed to set constants: %w", err)
+	}
 	return
 }
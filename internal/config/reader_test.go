@@ -12,6 +12,7 @@ import (
 	"github.com/Jeffail/benthos/v3/lib/log"
 	"github.com/Jeffail/benthos/v3/lib/metrics"
 	"github.com/Jeffail/benthos/v3/lib/types"
+	"github.com/Jeffail/benthos/v3/public/bloblang"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
@@ -20,7 +21,7 @@ import (
 
 func TestSetOverridesOnNothing(t *testing.T) {
 	conf := config.New()
-	rdr := iconfig.NewReader("", nil, iconfig.OptAddOverrides(
+	rdr := iconfig.NewReader(nil, nil, iconfig.OptAddOverrides(
 		"input.type=kafka",
 		"input.kafka.addresses=foobarbaz.com",
 		"output.type=amqp_0_9",
@@ -61,17 +62,59 @@ func TestSetOverrideErrors(t *testing.T) {
 			input: "input=meow",
 			err:   "yaml: unmarshal errors",
 		},
+		{
+			name:  "not an int",
+			input: "input.kafka.batching.count=meow",
+			err:   "input.kafka.batching.count: expected an int value, got 'meow'",
+		},
+		{
+			name:  "not a bool",
+			input: "http.enabled=meow",
+			err:   "http.enabled: expected a bool value, got 'meow'",
+		},
+		{
+			name:  "bad yaml array",
+			input: "input.kafka.topics=[ foo, bar",
+			err:   "input.kafka.topics: failed to parse value as YAML",
+		},
 	}
 
 	for _, test := range tests {
 		conf := config.New()
-		rdr := iconfig.NewReader("", nil, iconfig.OptAddOverrides(test.input))
+		rdr := iconfig.NewReader(nil, nil, iconfig.OptAddOverrides(test.input))
 
 		_, err := rdr.Read(&conf)
 		assert.Contains(t, err.Error(), test.err)
 	}
 }
 
+func TestSetOverridesTypeCoercion(t *testing.T) {
+	conf := config.New()
+	rdr := iconfig.NewReader(nil, nil, iconfig.OptAddOverrides(
+		"input.kafka.addresses=[ foo.com, bar.com ]",
+		"input.kafka.topics=123",
+		"input.kafka.client_id=null",
+		"input.kafka.batching.count=10",
+		"input.kafka.start_from_oldest=FALSE",
+		"input.kafka.tls={ enabled: true, skip_cert_verify: true }",
+		"output={ drop: {} }",
+	))
+
+	lints, err := rdr.Read(&conf)
+	require.NoError(t, err)
+	assert.Empty(t, lints)
+
+	assert.Equal(t, "kafka", conf.Input.Type)
+	assert.Equal(t, []string{"foo.com", "bar.com"}, conf.Input.Kafka.Addresses)
+	assert.Equal(t, []string{"123"}, conf.Input.Kafka.Topics)
+	assert.Equal(t, "null", conf.Input.Kafka.ClientID)
+	assert.Equal(t, 10, conf.Input.Kafka.Batching.Count)
+	assert.False(t, conf.Input.Kafka.StartFromOldest)
+	assert.True(t, conf.Input.Kafka.TLS.Enabled)
+	assert.True(t, conf.Input.Kafka.TLS.InsecureSkipVerify)
+	assert.Equal(t, "drop", conf.Output.Type)
+}
+
 func TestSetOverridesOfFile(t *testing.T) {
 	dir, err := os.MkdirTemp("", "test_set_overrides_of_file")
 	require.NoError(t, err)
@@ -89,7 +132,7 @@ input:
 `), 0644))
 
 	conf := config.New()
-	rdr := iconfig.NewReader(fullPath, nil, iconfig.OptAddOverrides(
+	rdr := iconfig.NewReader([]string{fullPath}, nil, iconfig.OptAddOverrides(
 		"input.kafka.addresses.0=nope1.com",
 		"input.kafka.addresses.1=nope2.com",
 		"input.kafka.topics=justthis",
@@ -144,7 +187,7 @@ cache_resources:
 `), 0644))
 
 	conf := config.New()
-	rdr := iconfig.NewReader(fullPath, []string{resourceOnePath, resourceTwoPath})
+	rdr := iconfig.NewReader([]string{fullPath}, []string{resourceOnePath, resourceTwoPath})
 
 	lints, err := rdr.Read(&conf)
 	require.NoError(t, err)
@@ -165,6 +208,55 @@ cache_resources:
 	assert.Equal(t, 13, conf.ResourceCaches[1].Memory.TTL)
 }
 
+func TestReadConstants(t *testing.T) {
+	dir := t.TempDir()
+
+	fullPath := filepath.Join(dir, "main.yaml")
+	require.NoError(t, os.WriteFile(fullPath, []byte(`
+constants:
+  env: '"prod"'
+  region: '"eu-" + "west"'
+`), 0644))
+
+	conf := config.New()
+	_, err := iconfig.NewReader([]string{fullPath}, nil).Read(&conf)
+	require.NoError(t, err)
+
+	exec, err := bloblang.Parse(`root = constant("env") + ":" + constant("region")`)
+	require.NoError(t, err)
+
+	v, err := exec.Query(nil)
+	require.NoError(t, err)
+	assert.Equal(t, "prod:eu-west", v)
+
+	// Reading a config with different constants replaces them.
+	require.NoError(t, os.WriteFile(fullPath, []byte(`
+constants:
+  env: '"dev"'
+`), 0644))
+
+	conf = config.New()
+	_, err = iconfig.NewReader([]string{fullPath}, nil).Read(&conf)
+	require.NoError(t, err)
+
+	exec, err = bloblang.Parse(`root = constant("env")`)
+	require.NoError(t, err)
+
+	v, err = exec.Query(nil)
+	require.NoError(t, err)
+	assert.Equal(t, "dev", v)
+
+	require.NoError(t, os.WriteFile(fullPath, []byte(`
+constants:
+  env: 'throw("nope")'
+`), 0644))
+
+	conf = config.New()
+	_, err = iconfig.NewReader([]string{fullPath}, nil).Read(&conf)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "failed to set constants")
+}
+
 func TestLints(t *testing.T) {
 	dir, err := os.MkdirTemp("", "test_resources")
 	require.NoError(t, err)
@@ -201,7 +293,7 @@ cache_resources:
 `), 0644))
 
 	conf := config.New()
-	rdr := iconfig.NewReader(fullPath, []string{resourceOnePath, resourceTwoPath})
+	rdr := iconfig.NewReader([]string{fullPath}, []string{resourceOnePath, resourceTwoPath})
 
 	lints, err := rdr.Read(&conf)
 	require.NoError(t, err)
@@ -225,6 +317,33 @@ cache_resources:
 	assert.Equal(t, 13, conf.ResourceCaches[1].Memory.TTL)
 }
 
+func TestLintWarningsIgnored(t *testing.T) {
+	dir := t.TempDir()
+
+	fullPath := filepath.Join(dir, "main.yaml")
+	require.NoError(t, os.WriteFile(fullPath, []byte(`
+pipeline:
+  processors:
+    - bloblang: |
+        let unused = "foo"
+        root = this
+`), 0644))
+
+	resourcePath := filepath.Join(dir, "res.yaml")
+	require.NoError(t, os.WriteFile(resourcePath, []byte(`
+processor_resources:
+  - label: foo
+    bloblang: |
+      root.a = this.a
+      root.a = this.b
+`), 0644))
+
+	conf := config.New()
+	lints, err := iconfig.NewReader([]string{fullPath}, []string{resourcePath}).Read(&conf)
+	require.NoError(t, err)
+	assert.Empty(t, lints)
+}
+
 func TestLintsOfOldPlugins(t *testing.T) {
 	dir, err := os.MkdirTemp("", "test_resources")
 	require.NoError(t, err)
@@ -250,9 +369,113 @@ input:
 	})
 
 	conf := config.New()
-	rdr := iconfig.NewReader(fullPath, nil)
+	rdr := iconfig.NewReader([]string{fullPath}, nil)
 
 	lints, err := rdr.Read(&conf)
 	require.NoError(t, err)
 	require.Len(t, lints, 0)
 }
+
+func TestMultipleMainPaths(t *testing.T) {
+	dir := t.TempDir()
+
+	basePath := filepath.Join(dir, "base.yaml")
+	require.NoError(t, os.WriteFile(basePath, []byte(`
+http:
+  address: 0.0.0.0:4196
+input:
+  kafka:
+    addresses: [ foobar.com, barbaz.com ]
+    topics: [ meow1, meow2 ]
+    consumer_group: foo
+output:
+  kafka:
+    addresses: [ foobar.com ]
+    topic: meow3
+metrics:
+  prometheus:
+    prefix: foo
+`), 0644))
+
+	overlayDir := filepath.Join(dir, "overlays")
+	require.NoError(t, os.Mkdir(overlayDir, 0755))
+
+	require.NoError(t, os.WriteFile(filepath.Join(overlayDir, "a.yaml"), []byte(`
+input:
+  kafka:
+    topics: [ woof ]
+  processors:
+    - bloblang: 'root = this'
+output:
+  drop: {}
+`), 0644))
+
+	require.NoError(t, os.WriteFile(filepath.Join(overlayDir, "b.yaml"), []byte(`
+input:
+  kafka:
+    consumer_group: bar
+metrics:
+  prometheus:
+    prefix: bar
+`), 0644))
+
+	conf := config.New()
+	rdr := iconfig.NewReader([]string{basePath, overlayDir}, nil, iconfig.OptAddOverrides(
+		"http.enabled=false",
+	))
+
+	paths, err := rdr.MainPaths()
+	require.NoError(t, err)
+	assert.Equal(t, []string{
+		basePath,
+		filepath.Join(overlayDir, "a.yaml"),
+		filepath.Join(overlayDir, "b.yaml"),
+	}, paths)
+
+	lints, err := rdr.Read(&conf)
+	require.NoError(t, err)
+	assert.Empty(t, lints)
+
+	assert.Equal(t, "0.0.0.0:4196", conf.HTTP.Address)
+	assert.False(t, conf.HTTP.Enabled)
+
+	assert.Equal(t, "kafka", conf.Input.Type)
+	assert.Equal(t, []string{"foobar.com", "barbaz.com"}, conf.Input.Kafka.Addresses)
+	assert.Equal(t, []string{"woof"}, conf.Input.Kafka.Topics)
+	assert.Equal(t, "bar", conf.Input.Kafka.ConsumerGroup)
+	require.Len(t, conf.Input.Processors, 1)
+	assert.Equal(t, "bloblang", conf.Input.Processors[0].Type)
+
+	// Changing the type of a component discards the config of the old type.
+	assert.Equal(t, "drop", conf.Output.Type)
+	assert.Equal(t, "benthos_stream", conf.Output.Kafka.Topic)
+
+	assert.Equal(t, "prometheus", conf.Metrics.Type)
+	assert.Equal(t, "bar", conf.Metrics.Prometheus.Prefix)
+}
+
+func TestMultipleMainPathsLints(t *testing.T) {
+	dir := t.TempDir()
+
+	basePath := filepath.Join(dir, "base.yaml")
+	require.NoError(t, os.WriteFile(basePath, []byte(`
+input:
+  kafka:
+    addresses: [ foobar.com ]
+    topics: [ meow1 ]
+`), 0644))
+
+	overlayPath := filepath.Join(dir, "overlay.yaml")
+	require.NoError(t, os.WriteFile(overlayPath, []byte(`
+input:
+  kafka:
+    topics: [ meow2 ]
+    meow3: not this
+`), 0644))
+
+	conf := config.New()
+	lints, err := iconfig.NewReader([]string{basePath, overlayPath}, nil).Read(&conf)
+	require.NoError(t, err)
+	require.Len(t, lints, 1)
+	assert.Contains(t, lints[0], "/overlay.yaml: line 5: field meow3 ")
+}
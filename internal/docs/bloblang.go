@@ -15,22 +15,31 @@ import (
 func LintBloblangMapping(ctx LintContext, line, col int, v interface{}) []Lint {
 	str, ok := v.(string)
 	if !ok {
-		return []Lint{NewLintError(line, fmt.Sprintf("expected string value, got %T", v))}
+		return []Lint{NewLintError(line, fmt.Sprintf("expected string value, got %T", v)).WithType(LintExpectedScalar)}
 	}
 	if str == "" {
 		return nil
 	}
-	_, err := ctx.BloblangEnv.NewMapping("", str)
+	warnings, err := ctx.BloblangEnv.CheckMapping("", str)
 	if err == nil {
-		return nil
+		// Mapping warnings describe likely problems rather than invalid
+		// mappings, and therefore must not prevent a config from running.
+		var lints []Lint
+		for _, w := range warnings {
+			bline, bcol := w.LineAndCol([]rune(str))
+			lint := NewLintWarning(line+bline-1, w.Message).WithType(LintBadBloblang)
+			lint.Column = col + bcol - 1
+			lints = append(lints, lint)
+		}
+		return lints
 	}
 	if mErr, ok := err.(*parser.Error); ok {
 		bline, bcol := parser.LineAndColOf([]rune(str), mErr.Input)
-		lint := NewLintError(line+bline-1, mErr.ErrorAtPositionStructured("", []rune(str)))
-		lint.Column = col + bcol
+		lint := NewLintError(line+bline-1, mErr.ErrorAtPositionStructured("", []rune(str))).WithType(LintBadBloblang)
+		lint.Column = col + bcol - 1
 		return []Lint{lint}
 	}
-	return []Lint{NewLintError(line, err.Error())}
+	return []Lint{NewLintError(line, err.Error()).WithType(LintBadBloblang)}
 }
 
 // LintBloblangField is function for linting a config field expected to be an
@@ -38,7 +47,7 @@ func LintBloblangMapping(ctx LintContext, line, col int, v interface{}) []Lint {
 func LintBloblangField(ctx LintContext, line, col int, v interface{}) []Lint {
 	str, ok := v.(string)
 	if !ok {
-		return []Lint{NewLintWarning(line, fmt.Sprintf("expected string value, got %T", v))}
+		return []Lint{NewLintWarning(line, fmt.Sprintf("expected string value, got %T", v)).WithType(LintExpectedScalar)}
 	}
 	if str == "" {
 		return nil
@@ -49,11 +58,11 @@ func LintBloblangField(ctx LintContext, line, col int, v interface{}) []Lint {
 	}
 	if mErr, ok := err.(*parser.Error); ok {
 		bline, bcol := parser.LineAndColOf([]rune(str), mErr.Input)
-		lint := NewLintError(line+bline-1, mErr.ErrorAtPositionStructured("", []rune(str)))
-		lint.Column = col + bcol
+		lint := NewLintError(line+bline-1, mErr.ErrorAtPositionStructured("", []rune(str))).WithType(LintBadBloblang)
+		lint.Column = col + bcol - 1
 		return []Lint{lint}
 	}
-	return []Lint{NewLintError(line, err.Error())}
+	return []Lint{NewLintError(line, err.Error()).WithType(LintBadBloblang)}
 }
 
 type functionCategory struct {
@@ -142,6 +151,7 @@ root.doc.host = hostname()
 [field_paths]: /docs/configuration/field_paths
 [meta_proc]: /docs/components/processors/metadata
 [methods.encode]: /docs/guides/bloblang/methods#encode
+[methods.format_timestamp]: /docs/guides/bloblang/methods#format_timestamp
 [methods.string]: /docs/guides/bloblang/methods#string
 `
 
@@ -156,6 +166,7 @@ func BloblangFunctionsMarkdown() ([]byte, error) {
 		query.FunctionCategoryGeneral,
 		query.FunctionCategoryMessage,
 		query.FunctionCategoryEnvironment,
+		query.FunctionCategoryPlugin,
 		query.FunctionCategoryDeprecated,
 	} {
 		functions := functionCategory{
@@ -177,6 +188,15 @@ func BloblangFunctionsMarkdown() ([]byte, error) {
 	return buf.Bytes(), err
 }
 
+// BloblangFunctionMarkdown returns a markdown document for a single Bloblang
+// function.
+func BloblangFunctionMarkdown(spec query.FunctionSpec) ([]byte, error) {
+	var buf bytes.Buffer
+	err := template.Must(template.New("functions").Parse(bloblangFunctionsTemplate)).ExecuteTemplate(&buf, "function_spec", spec)
+
+	return buf.Bytes(), err
+}
+
 //------------------------------------------------------------------------------
 
 type methodCategory struct {
@@ -276,6 +296,8 @@ root.has_good_taste = ["pikachu","mewtwo","magmar"].contains(this.user.fav_pokem
 {{end -}}
 
 [field_paths]: /docs/configuration/field_paths
+[methods.convert_encoding]: #convert_encoding
+[methods.detect_encoding]: #detect_encoding
 [methods.encode]: #encode
 [methods.string]: #string
 `
@@ -311,6 +333,7 @@ func BloblangMethodsMarkdown() ([]byte, error) {
 		query.MethodCategoryObjectAndArray,
 		query.MethodCategoryParsing,
 		query.MethodCategoryEncoding,
+		query.MethodCategoryPlugin,
 		query.MethodCategoryDeprecated,
 	} {
 		methods := methodCategory{
@@ -339,3 +362,30 @@ func BloblangMethodsMarkdown() ([]byte, error) {
 
 	return buf.Bytes(), err
 }
+
+// BloblangMethodMarkdown returns a markdown document for a single Bloblang
+// method. Methods that behave differently within each of their categories are
+// documented once for each category.
+func BloblangMethodMarkdown(spec query.MethodSpec) ([]byte, error) {
+	tmpl := template.Must(template.New("methods").Parse(bloblangMethodsTemplate))
+
+	var buf bytes.Buffer
+	if len(spec.Categories) == 0 {
+		spec.Description = strings.TrimSpace(spec.Description)
+		err := tmpl.ExecuteTemplate(&buf, "method_spec", spec)
+		return buf.Bytes(), err
+	}
+	for i, cat := range spec.Categories {
+		catSpec, _ := methodForCat(spec, cat.Category)
+		if i > 0 {
+			buf.WriteString("\n")
+		}
+		if len(spec.Categories) > 1 {
+			fmt.Fprintf(&buf, "## %v\n\n", cat.Category)
+		}
+		if err := tmpl.ExecuteTemplate(&buf, "method_spec", catSpec); err != nil {
+			return nil, err
+		}
+	}
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,56 @@
+package docs_test
+
+import (
+	"testing"
+
+	"github.com/Jeffail/benthos/v3/internal/docs"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLintBloblangMappingWarnings(t *testing.T) {
+	mapping := `root.foo = "bar"
+root.value = match this.value {
+  string s => s
+  array a => a.length()
+}`
+
+	lints := docs.LintBloblangMapping(docs.NewLintContext(), 10, 5, mapping)
+	require.Len(t, lints, 1)
+	assert.Equal(t, docs.LintWarning, lints[0].Level)
+	assert.Equal(t, docs.LintBadBloblang, lints[0].Type)
+	assert.Equal(t, 11, lints[0].Line)
+	assert.Equal(t, 18, lints[0].Column)
+}
+
+func TestLintBloblangMappingErrors(t *testing.T) {
+	lints := docs.LintBloblangMapping(docs.NewLintContext(), 10, 5, `root = this.`)
+	require.Len(t, lints, 1)
+	assert.Equal(t, docs.LintError, lints[0].Level)
+	assert.Equal(t, docs.LintBadBloblang, lints[0].Type)
+}
+
+func TestLintBloblangMappingSemanticWarnings(t *testing.T) {
+	mapping := `let foo = "foo"
+root.a = this.a
+root.a = this.b
+root.c = timestamp_utc()`
+
+	lints := docs.LintBloblangMapping(docs.NewLintContext(), 1, 1, mapping)
+	require.Len(t, lints, 3)
+	for _, l := range lints {
+		assert.Equal(t, docs.LintWarning, l.Level, l.What)
+	}
+}
+
+func TestLintBloblangMappingTypeWarnings(t *testing.T) {
+	mapping := `root.a = {"a":1}.sum()
+root.b = this.foo.keys().uppercase()`
+
+	lints := docs.LintBloblangMapping(docs.NewLintContext(), 1, 1, mapping)
+	require.Len(t, lints, 2)
+	for _, l := range lints {
+		assert.Equal(t, docs.LintWarning, l.Level, l.What)
+		assert.Equal(t, docs.LintBadBloblang, l.Type, l.What)
+	}
+}
@@ -97,6 +97,22 @@ type ComponentSpec struct {
 
 	// Version is the Benthos version this component was introduced.
 	Version string `json:"version,omitempty"`
+
+	// ReplacedBy optionally describes a component that replaces this one when
+	// it is deprecated, which allows configs to be migrated automatically.
+	ReplacedBy *ComponentReplacement `json:"replaced_by,omitempty"`
+}
+
+// ComponentReplacement describes a component that replaces a deprecated
+// component, where the config of the deprecated component is also valid for
+// the replacement.
+type ComponentReplacement struct {
+	// Name of the replacement component.
+	Name string `json:"name"`
+
+	// Fields that must be added to the config of the replacement in order for
+	// it to behave the same as the deprecated component.
+	Fields map[string]interface{} `json:"fields,omitempty"`
 }
 
 type componentContext struct {
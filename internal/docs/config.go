@@ -48,13 +48,13 @@ var labelField = FieldString(
 	}
 	if err := ValidateLabel(l); err != nil {
 		return []Lint{
-			NewLintError(line, fmt.Sprintf("Invalid label '%v': %v", l, err)),
+			NewLintError(line, fmt.Sprintf("Invalid label '%v': %v", l, err)).WithType(LintBadLabel),
 		}
 	}
 	prevLine, exists := ctx.LabelsToLine[l]
 	if exists {
 		return []Lint{
-			NewLintError(line, fmt.Sprintf("Label '%v' collides with a previously defined label at line %v", l, prevLine)),
+			NewLintError(line, fmt.Sprintf("Label '%v' collides with a previously defined label at line %v", l, prevLine)).WithType(LintDuplicateLabel),
 		}
 	}
 	ctx.LabelsToLine[l] = line
@@ -224,12 +224,18 @@ func SanitiseComponentConfig(componentType Type, raw interface{}, filter FieldFi
 	return nil
 }
 
+// SecretScrubbed is the value that replaces the values of secret fields when
+// they are scrubbed from a sanitised config.
+const SecretScrubbed = "!!!SECRET_SCRUBBED!!!"
+
 // SanitiseConfig contains fields describing the desired behaviour of the config
 // sanitiser such as removing certain fields.
 type SanitiseConfig struct {
 	RemoveTypeField  bool
 	RemoveDeprecated bool
+	ScrubSecrets     bool
 	ForExample       bool
+	DocumentFields   bool
 	Filter           FieldFilter
 	DocsProvider     Provider
 }
@@ -109,6 +109,10 @@ type FieldSpec struct {
 	// Bloblang indicates that a string field is a Bloblang mapping.
 	Bloblang bool `json:"bloblang"`
 
+	// IsSecret indicates that a field contains a secret such as a password,
+	// which is scrubbed when a config is printed.
+	IsSecret bool `json:"is_secret,omitempty"`
+
 	// Examples is a slice of optional example values for a field.
 	Examples []interface{} `json:"examples,omitempty"`
 
@@ -143,6 +147,13 @@ func (f FieldSpec) IsBloblang() FieldSpec {
 	return f
 }
 
+// Secret marks the field as containing a secret, such as a password or an
+// access token, which is scrubbed when a config is printed.
+func (f FieldSpec) Secret() FieldSpec {
+	f.IsSecret = true
+	return f
+}
+
 // HasType returns a new FieldSpec that specifies a specific type.
 func (f FieldSpec) HasType(t FieldType) FieldSpec {
 	f.Type = t
@@ -268,7 +279,7 @@ func (f FieldSpec) LintOptions() FieldSpec {
 	f.customLintFn = func(ctx LintContext, line, col int, value interface{}) []Lint {
 		str, ok := value.(string)
 		if !ok {
-			return []Lint{NewLintWarning(line, fmt.Sprintf("expected string value, got %T", value))}
+			return []Lint{NewLintWarning(line, fmt.Sprintf("expected string value, got %T", value)).WithType(LintExpectedScalar)}
 		}
 		if len(f.Options) > 0 {
 			for _, optStr := range f.Options {
@@ -283,7 +294,7 @@ func (f FieldSpec) LintOptions() FieldSpec {
 				}
 			}
 		}
-		return []Lint{NewLintError(line, fmt.Sprintf("value %v is not a valid option for this field", str))}
+		return []Lint{NewLintError(line, fmt.Sprintf("value %v is not a valid option for this field", str)).WithType(LintInvalidOption)}
 	}
 	return f
 }
@@ -479,6 +490,10 @@ func (f FieldSpecs) sanitise(s interface{}, filter FieldFilter) {
 		v := m[spec.Name]
 		if _, omit := spec.shouldOmit(v, m); omit {
 			delete(m, spec.Name)
+		} else if spec.IsSecret {
+			if str, ok := v.(string); ok && str != "" {
+				m[spec.Name] = SecretScrubbed
+			}
 		} else {
 			spec.sanitise(v, filter)
 		}
@@ -498,6 +513,17 @@ type LintContext struct {
 
 	// Provides an isolated context for Bloblang parsing.
 	BloblangEnv *bloblang.Environment
+
+	// Optional custom rules to lint against.
+	Rules *LintRules
+
+	// Optional raw source of the config being linted, which allows lints
+	// within block scalars to be given accurate columns.
+	Source []byte
+
+	// Whether the component being linted is an element of a map, and is
+	// therefore identified by its key.
+	keyedComponent bool
 }
 
 // NewLintContext creates a new linting context.
@@ -521,22 +547,86 @@ const (
 	LintWarning LintLevel = iota
 )
 
+// String returns a human readable name of the lint level.
+func (l LintLevel) String() string {
+	if l == LintWarning {
+		return "warning"
+	}
+	return "error"
+}
+
+// LintType is a stable identifier of the rule that raised a lint, allowing
+// tooling to categorise findings.
+type LintType string
+
+// Lint types
+const (
+	// A lint raised by a custom linter of a field or component.
+	LintCustom LintType = "custom"
+
+	// A config could not be read or parsed.
+	LintFailedRead LintType = "failed_read"
+
+	// A field value is not one of the options of the field.
+	LintInvalidOption LintType = "invalid_option"
+
+	// A component label is invalid.
+	LintBadLabel LintType = "bad_label"
+
+	// A component label collides with another.
+	LintDuplicateLabel LintType = "duplicate_label"
+
+	// A Bloblang mapping or interpolation string is invalid.
+	LintBadBloblang LintType = "bad_bloblang"
+
+	// A field should be omitted as it has no effect.
+	LintShouldOmit LintType = "should_omit"
+
+	// The type of a component could not be inferred.
+	LintComponentMissing LintType = "component_missing"
+
+	// A component type is not recognised.
+	LintComponentNotFound LintType = "component_not_found"
+
+	// A field is not recognised.
+	LintUnknown LintType = "unknown"
+
+	// A required field is missing.
+	LintMissing LintType = "missing"
+
+	// A field value was expected to be an array.
+	LintExpectedArray LintType = "expected_array"
+
+	// A field value was expected to be an object.
+	LintExpectedObject LintType = "expected_object"
+
+	// A field value was expected to be a scalar.
+	LintExpectedScalar LintType = "expected_scalar"
+)
+
 // Lint describes a single linting issue found with a Benthos config.
 type Lint struct {
 	Line   int
 	Column int // Optional, omitted from lint report unless >= 1
 	Level  LintLevel
+	Type   LintType
 	What   string
 }
 
 // NewLintError returns an error lint.
 func NewLintError(line int, msg string) Lint {
-	return Lint{Line: line, Level: LintError, What: msg}
+	return Lint{Line: line, Level: LintError, Type: LintCustom, What: msg}
 }
 
 // NewLintWarning returns a warning lint.
 func NewLintWarning(line int, msg string) Lint {
-	return Lint{Line: line, Level: LintWarning, What: msg}
+	return Lint{Line: line, Level: LintWarning, Type: LintCustom, What: msg}
+}
+
+// WithType returns a copy of the lint with a specific type.
+func (l Lint) WithType(t LintType) Lint {
+	l.Type = t
+	return l
 }
 
 //------------------------------------------------------------------------------
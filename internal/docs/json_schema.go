@@ -1,5 +1,9 @@
 package docs
 
+import (
+	"sort"
+)
+
 // JSONSchema serializes a field spec into a JSON schema structure.
 func (f FieldSpec) JSONSchema() interface{} {
 	spec := map[string]interface{}{}
@@ -33,35 +37,32 @@ func (f FieldSpec) JSONSchema() interface{} {
 			spec["type"] = "number"
 		case FieldTypeObject:
 			spec["type"] = "object"
-			spec["properties"] = f.Children.JSONSchema()
-			var required []string
-			for _, child := range f.Children {
-				if !child.IsOptional && child.Default == nil {
-					required = append(required, child.Name)
-				}
-			}
-			if len(required) > 0 {
-				spec["required"] = required
+			if len(f.Children) == 0 {
+				// Objects without children are free form.
+				break
 			}
+			// Fields omitted from a config take their default values, and
+			// therefore none of the children are required.
+			spec["properties"] = f.Children.JSONSchema()
 			spec["additionalProperties"] = false
 		case FieldTypeInput:
-			spec["$ref"] = "#/$defs/input"
+			spec["$ref"] = "#/definitions/input"
 		case FieldTypeBuffer:
-			spec["$ref"] = "#/$defs/buffer"
+			spec["$ref"] = "#/definitions/buffer"
 		case FieldTypeCache:
-			spec["$ref"] = "#/$defs/cache"
+			spec["$ref"] = "#/definitions/cache"
 		case FieldTypeCondition:
 			return true
 		case FieldTypeProcessor:
-			spec["$ref"] = "#/$defs/processor"
+			spec["$ref"] = "#/definitions/processor"
 		case FieldTypeRateLimit:
-			spec["$ref"] = "#/$defs/rate_limit"
+			spec["$ref"] = "#/definitions/rate_limit"
 		case FieldTypeOutput:
-			spec["$ref"] = "#/$defs/output"
+			spec["$ref"] = "#/definitions/output"
 		case FieldTypeMetrics:
-			spec["$ref"] = "#/$defs/metrics"
+			spec["$ref"] = "#/definitions/metrics"
 		case FieldTypeTracer:
-			spec["$ref"] = "#/$defs/tracer"
+			spec["$ref"] = "#/definitions/tracer"
 		}
 	}
 	return spec
@@ -75,3 +76,75 @@ func (f FieldSpecs) JSONSchema() map[string]interface{} {
 	}
 	return spec
 }
+
+// JSONSchema serializes a component spec into a JSON schema structure, which
+// matches configs that either name the component with a `type` field or where
+// the type is inferred from the key of the component config.
+func (c ComponentSpec) JSONSchema() interface{} {
+	properties := map[string]interface{}{
+		"type": map[string]interface{}{
+			"const": c.Name,
+		},
+		c.Name: c.Config.JSONSchema(),
+	}
+	for k, v := range reservedFieldsByType(c.Type) {
+		if k != "type" && k != "plugin" {
+			properties[k] = v.JSONSchema()
+		}
+	}
+	if c.Plugin {
+		// Old style plugins may nest their config under a `plugin` field.
+		properties["plugin"] = c.Config.JSONSchema()
+	}
+	return map[string]interface{}{
+		"type":                 "object",
+		"properties":           properties,
+		"additionalProperties": false,
+		"anyOf": []interface{}{
+			map[string]interface{}{"required": []string{"type"}},
+			map[string]interface{}{"required": []string{c.Name}},
+		},
+	}
+}
+
+// ConfigJSONSchema returns a JSON schema for a config described by a set of
+// fields, where fields of a component type reference a definition that matches
+// any of the provided components of that type.
+func ConfigJSONSchema(fields FieldSpecs, components ...ComponentSpec) map[string]interface{} {
+	sorted := make([]ComponentSpec, len(components))
+	copy(sorted, components)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].Name < sorted[j].Name
+	})
+
+	definitions := map[string]interface{}{}
+	for _, t := range Types() {
+		var options []interface{}
+		for _, c := range sorted {
+			if c.Type == t {
+				options = append(options, c.JSONSchema())
+			}
+		}
+		if len(options) == 0 {
+			// No config is valid for a type without components.
+			definitions[string(t)] = false
+			continue
+		}
+		// An empty object is also valid, resulting in the default component
+		// of the type.
+		options = append(options, map[string]interface{}{
+			"type":          "object",
+			"maxProperties": 0,
+		})
+		definitions[string(t)] = map[string]interface{}{
+			"anyOf": options,
+		}
+	}
+
+	return map[string]interface{}{
+		"$schema":     "http://json-schema.org/draft-07/schema#",
+		"type":        "object",
+		"properties":  fields.JSONSchema(),
+		"definitions": definitions,
+	}
+}
@@ -0,0 +1,141 @@
+package docs_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/Jeffail/benthos/v3/internal/docs"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	jsonschema "github.com/xeipuuv/gojsonschema"
+	"gopkg.in/yaml.v3"
+)
+
+func TestConfigJSONSchema(t *testing.T) {
+	fields := docs.FieldSpecs{
+		docs.FieldCommon("input", "").HasType(docs.FieldTypeInput),
+		docs.FieldCommon("cache_resources", "").Array().HasType(docs.FieldTypeCache),
+		docs.FieldCommon("shutdown_timeout", "").HasType(docs.FieldTypeString),
+	}
+
+	components := []docs.ComponentSpec{
+		{
+			Name: "foo",
+			Type: docs.TypeInput,
+			Config: docs.FieldComponent().WithChildren(
+				docs.FieldCommon("address", "").HasType(docs.FieldTypeString),
+				docs.FieldCommon("count", "").HasType(docs.FieldTypeInt),
+				docs.FieldCommon("headers", "").Map().HasType(docs.FieldTypeString),
+				docs.FieldCommon("extra", "").HasType(docs.FieldTypeObject),
+			),
+		},
+		{
+			Name:   "bar",
+			Type:   docs.TypeInput,
+			Config: docs.FieldComponent().HasType(docs.FieldTypeString),
+		},
+		{
+			Name: "baz",
+			Type: docs.TypeProcessor,
+		},
+	}
+
+	schemaBytes, err := json.Marshal(docs.ConfigJSONSchema(fields, components...))
+	require.NoError(t, err)
+
+	schema, err := jsonschema.NewSchema(jsonschema.NewBytesLoader(schemaBytes))
+	require.NoError(t, err)
+
+	tests := map[string]struct {
+		config string
+		valid  bool
+	}{
+		"inferred type": {
+			config: `
+input:
+  label: a
+  foo:
+    address: localhost
+    count: 10
+    headers:
+      a: b
+    extra:
+      anything: goes
+  processors:
+    - baz: {}
+shutdown_timeout: 10s
+`,
+			valid: true,
+		},
+		"explicit type": {
+			config: `
+input:
+  type: bar
+  bar: hello
+`,
+			valid: true,
+		},
+		"type without config": {
+			config: `
+input:
+  type: foo
+`,
+			valid: true,
+		},
+		"empty component": {
+			config: `
+input: {}
+`,
+			valid: true,
+		},
+		"unknown component": {
+			config: `
+input:
+  nope: {}
+`,
+		},
+		"mismatched type": {
+			config: `
+input:
+  type: bar
+  foo: {}
+`,
+		},
+		"unknown field": {
+			config: `
+input:
+  foo:
+    nope: true
+`,
+		},
+		"wrong field type": {
+			config: `
+input:
+  foo:
+    count: ten
+`,
+		},
+		"no cache components": {
+			config: `
+cache_resources:
+  - label: foo
+    memory: {}
+`,
+		},
+	}
+
+	for name, test := range tests {
+		test := test
+		t.Run(name, func(t *testing.T) {
+			var v interface{}
+			require.NoError(t, yaml.Unmarshal([]byte(test.config), &v))
+
+			jBytes, err := json.Marshal(v)
+			require.NoError(t, err)
+
+			res, err := schema.Validate(jsonschema.NewBytesLoader(jBytes))
+			require.NoError(t, err)
+			assert.Equal(t, test.valid, res.Valid(), res.Errors())
+		})
+	}
+}
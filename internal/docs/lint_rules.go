@@ -0,0 +1,156 @@
+package docs
+
+import (
+	"fmt"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// LintRules describes a set of custom linting rules that codify policies on
+// top of the standard linting of a config, such as forbidding components.
+type LintRules struct {
+	// A map of component types to the names of components of that type that
+	// must not be used.
+	ForbiddenComponents map[Type][]string `yaml:"forbidden_components"`
+
+	// A list of component types that must have a label.
+	RequireLabels []Type `yaml:"require_labels"`
+
+	// Forbid credential fields, such as passwords and secrets, from containing
+	// values other than environment variable interpolations.
+	ForbidPlainTextCredentials bool `yaml:"forbid_plain_text_credentials"`
+}
+
+// Lint types raised by lint rules.
+const (
+	// A component is used that is forbidden by lint rules.
+	LintForbiddenComponent LintType = "forbidden_component"
+
+	// A component is missing a label that is required by lint rules.
+	LintMissingLabel LintType = "missing_label"
+
+	// A credential field contains a plain text value.
+	LintPlainTextCredential LintType = "plain_text_credential"
+)
+
+// LintRulesFromYAML parses a set of lint rules from a YAML document and checks
+// that the component types it references exist.
+func LintRulesFromYAML(rawBytes []byte) (*LintRules, error) {
+	var rules LintRules
+	if err := yaml.Unmarshal(rawBytes, &rules); err != nil {
+		return nil, err
+	}
+
+	validTypes := map[Type]struct{}{}
+	for _, t := range Types() {
+		validTypes[t] = struct{}{}
+	}
+	for t := range rules.ForbiddenComponents {
+		if _, exists := validTypes[t]; !exists {
+			return nil, fmt.Errorf("forbidden_components: component type not recognised: %v", t)
+		}
+	}
+	for _, t := range rules.RequireLabels {
+		if _, exists := reservedFieldsByType(t)["label"]; !exists {
+			return nil, fmt.Errorf("require_labels: component type %v does not support labels", t)
+		}
+	}
+	return &rules, nil
+}
+
+// LintYAML walks a yaml node of a config and returns a list of lints that
+// break the rules. The node is expected to be the raw config, where environment
+// variable interpolations have not yet been replaced, in order to detect plain
+// text credentials. Since standard lints of a raw config are unreliable only
+// the lints raised by the rules are returned.
+func (r *LintRules) LintYAML(ctx LintContext, spec FieldSpecs, node *yaml.Node) []Lint {
+	ctx.Rules = r
+	ctx.LabelsToLine = map[string]int{}
+
+	var lints []Lint
+	for _, l := range spec.LintYAML(ctx, node) {
+		switch l.Type {
+		case LintForbiddenComponent, LintMissingLabel, LintPlainTextCredential:
+			lints = append(lints, l)
+		}
+	}
+	return lints
+}
+
+func (r *LintRules) lintComponent(ctx LintContext, cType Type, name string, node *yaml.Node) []Lint {
+	if r == nil {
+		return nil
+	}
+
+	var lints []Lint
+	for _, forbidden := range r.ForbiddenComponents[cType] {
+		if forbidden == name {
+			lints = append(lints, lintErrorAtNode(node, LintForbiddenComponent, fmt.Sprintf("%v %v is forbidden by lint rules", cType, name)))
+		}
+	}
+
+	// Components within maps are labelled by their key, and components that
+	// reference resources must not be labelled at all.
+	if ctx.keyedComponent || name == "resource" {
+		return lints
+	}
+	for _, t := range r.RequireLabels {
+		if t != cType {
+			continue
+		}
+		var label string
+		for i := 0; i < len(node.Content)-1; i += 2 {
+			if node.Content[i].Value == "label" {
+				label = node.Content[i+1].Value
+			}
+		}
+		if label == "" {
+			lints = append(lints, lintErrorAtNode(node, LintMissingLabel, fmt.Sprintf("%v %v requires a label", cType, name)))
+		}
+	}
+	return lints
+}
+
+var credentialFieldNames = []string{
+	"password", "secret", "token", "api_key", "access_key", "private_key",
+}
+
+// Suffixes of fields that reference credentials rather than containing them,
+// such as `private_key_file` and `token_cache`.
+var credentialReferenceSuffixes = []string{
+	"_file", "_path", "_cache", "_key",
+}
+
+func isCredentialField(name string) bool {
+	name = strings.ToLower(name)
+	for _, c := range credentialFieldNames {
+		if strings.HasSuffix(name, c) {
+			return true
+		}
+	}
+	for _, s := range credentialReferenceSuffixes {
+		if strings.HasSuffix(name, s) {
+			return false
+		}
+	}
+	for _, c := range credentialFieldNames {
+		if strings.Contains(name, c) {
+			return true
+		}
+	}
+	return false
+}
+
+func (r *LintRules) lintField(f FieldSpec, node *yaml.Node) []Lint {
+	if r == nil || !r.ForbidPlainTextCredentials {
+		return nil
+	}
+	if f.Type != FieldTypeString || node.Kind != yaml.ScalarNode || !(f.IsSecret || isCredentialField(f.Name)) {
+		return nil
+	}
+	if node.Value == "" || strings.Contains(node.Value, "${") {
+		return nil
+	}
+	return []Lint{lintErrorAtNode(node, LintPlainTextCredential, fmt.Sprintf("field %v contains a plain text credential, use an environment variable interpolation instead", f.Name))}
+}
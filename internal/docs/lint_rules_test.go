@@ -0,0 +1,135 @@
+package docs_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/Jeffail/benthos/v3/internal/docs"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
+)
+
+func TestLintRulesFromYAML(t *testing.T) {
+	rules, err := docs.LintRulesFromYAML([]byte(`
+forbidden_components:
+  input: [ foo ]
+require_labels: [ input, output ]
+forbid_plain_text_credentials: true
+`))
+	require.NoError(t, err)
+	assert.Equal(t, &docs.LintRules{
+		ForbiddenComponents:        map[docs.Type][]string{docs.TypeInput: {"foo"}},
+		RequireLabels:              []docs.Type{docs.TypeInput, docs.TypeOutput},
+		ForbidPlainTextCredentials: true,
+	}, rules)
+
+	_, err = docs.LintRulesFromYAML([]byte(`forbidden_components: { nope: [ foo ] }`))
+	require.EqualError(t, err, "forbidden_components: component type not recognised: nope")
+
+	_, err = docs.LintRulesFromYAML([]byte(`require_labels: [ metrics ]`))
+	require.EqualError(t, err, "require_labels: component type metrics does not support labels")
+}
+
+func TestLintRules(t *testing.T) {
+	for _, t := range docs.Types() {
+		docs.RegisterDocs(docs.ComponentSpec{
+			Name: fmt.Sprintf("testrulesfoo%v", string(t)),
+			Type: t,
+			Config: docs.FieldComponent().WithChildren(
+				docs.FieldString("password", "").Optional(),
+				docs.FieldString("private_key_file", "").Optional(),
+				docs.FieldCommon("procs", "").Array().HasType(docs.FieldTypeProcessor).Optional(),
+			),
+		})
+	}
+
+	spec := docs.FieldSpecs{
+		docs.FieldCommon("input", "").HasType(docs.FieldTypeInput),
+		docs.FieldCommon("resources", "").WithChildren(
+			docs.FieldCommon("inputs", "").Map().HasType(docs.FieldTypeInput),
+		),
+	}
+
+	rules := &docs.LintRules{
+		ForbiddenComponents:        map[docs.Type][]string{docs.TypeProcessor: {"testrulesfooprocessor"}},
+		RequireLabels:              []docs.Type{docs.TypeInput},
+		ForbidPlainTextCredentials: true,
+	}
+
+	tests := []struct {
+		name string
+		conf string
+		res  []docs.Lint
+	}{
+		{
+			name: "no breaches",
+			conf: `
+input:
+  label: foo
+  testrulesfooinput:
+    password: ${PASSWORD}
+    private_key_file: ./key.pem
+resources:
+  inputs:
+    bar:
+      testrulesfooinput: {}
+`,
+		},
+		{
+			name: "ignores standard lints",
+			conf: `
+input:
+  label: foo
+  testrulesfooinput:
+    nope: not a field
+`,
+		},
+		{
+			name: "missing label",
+			conf: `
+input:
+  testrulesfooinput: {}
+`,
+			res: []docs.Lint{
+				{Line: 3, Column: 3, Level: docs.LintError, Type: docs.LintMissingLabel, What: "input testrulesfooinput requires a label"},
+			},
+		},
+		{
+			name: "forbidden nested component",
+			conf: `
+input:
+  label: foo
+  testrulesfooinput:
+    procs:
+      - testrulesfooprocessor: {}
+`,
+			res: []docs.Lint{
+				{Line: 6, Column: 9, Level: docs.LintError, Type: docs.LintForbiddenComponent, What: "processor testrulesfooprocessor is forbidden by lint rules"},
+			},
+		},
+		{
+			name: "plain text credential",
+			conf: `
+input:
+  label: foo
+  testrulesfooinput:
+    password: hunter2
+`,
+			res: []docs.Lint{
+				{Line: 5, Column: 15, Level: docs.LintError, Type: docs.LintPlainTextCredential, What: "field password contains a plain text credential, use an environment variable interpolation instead"},
+			},
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			var node yaml.Node
+			require.NoError(t, yaml.Unmarshal([]byte(test.conf), &node))
+
+			lints := rules.LintYAML(docs.NewLintContext(), spec, &node)
+			assert.Equal(t, test.res, lints)
+		})
+	}
+}
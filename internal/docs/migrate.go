@@ -0,0 +1,243 @@
+package docs
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Migration describes a change made to a config in order to move it away from
+// deprecated components and fields, or a deprecated component or field that
+// could not be changed automatically and must be migrated by hand.
+type Migration struct {
+	Line   int    `json:"line"`
+	Column int    `json:"column"`
+	Path   string `json:"path"`
+	Manual bool   `json:"manual"`
+	What   string `json:"what"`
+}
+
+func migrationAtNode(node *yaml.Node, path string, manual bool, what string) Migration {
+	return Migration{
+		Line:   node.Line,
+		Column: node.Column,
+		Path:   path,
+		Manual: manual,
+		What:   what,
+	}
+}
+
+func joinMigratePath(path, key string) string {
+	if path == "" {
+		return key
+	}
+	return path + "." + key
+}
+
+// isDefaultYAML returns true if a yaml node holds the default value of a field.
+func (f FieldSpec) isDefaultYAML(node *yaml.Node) bool {
+	if f.Default == nil {
+		return false
+	}
+	var value interface{}
+	if err := node.Decode(&value); err != nil {
+		return false
+	}
+	valueBytes, err := json.Marshal(value)
+	if err != nil {
+		return false
+	}
+	defaultBytes, err := json.Marshal(*f.Default)
+	if err != nil {
+		return false
+	}
+	return string(valueBytes) == string(defaultBytes)
+}
+
+// MigrateYAML modifies a yaml node of a component config in place, replacing
+// deprecated components with their replacements and removing deprecated
+// fields that are set to their default values. Each change is returned, along
+// with any deprecated components and fields that must be migrated by hand.
+func MigrateYAML(docProv Provider, cType Type, path string, node *yaml.Node) []Migration {
+	if docProv == nil {
+		docProv = globalProvider
+	}
+	if cType == "condition" {
+		return nil
+	}
+
+	node = unwrapDocumentNode(node)
+	if node.Kind != yaml.MappingNode || len(node.Content) == 0 {
+		return nil
+	}
+
+	name, cSpec, err := GetInferenceCandidateFromYAML(docProv, cType, "", node)
+	if err != nil {
+		return nil
+	}
+
+	var migrations []Migration
+	reservedFields := reservedFieldsByType(cType)
+	for i := 0; i < len(node.Content)-1; i += 2 {
+		key := node.Content[i].Value
+		if key == name || (key == "plugin" && cSpec.Plugin) {
+			migrations = append(migrations, cSpec.Config.MigrateYAML(docProv, joinMigratePath(path, key), node.Content[i+1])...)
+			continue
+		}
+		if spec, exists := reservedFields[key]; exists {
+			migrations = append(migrations, spec.MigrateYAML(docProv, joinMigratePath(path, key), node.Content[i+1])...)
+		}
+	}
+
+	// Deprecated components are replaced after their fields are migrated, as
+	// deprecated fields might not exist within the replacement.
+	if cSpec.Status == StatusDeprecated {
+		if r := cSpec.ReplacedBy; r != nil {
+			if _, exists := GetDocs(docProv, r.Name, cType); exists {
+				migrations = append([]Migration{replaceComponentYAML(cType, name, path, *r, node)}, migrations...)
+			}
+		} else {
+			migrations = append([]Migration{migrationAtNode(node, path, true, fmt.Sprintf(
+				"%v %v is deprecated and has no direct replacement, alternatives are listed by `benthos docs %v %v`",
+				cType, name, cType, name,
+			))}, migrations...)
+		}
+	}
+	return migrations
+}
+
+// replaceComponentYAML renames a deprecated component within a yaml node and
+// adds the fields required by its replacement.
+func replaceComponentYAML(cType Type, name, path string, r ComponentReplacement, node *yaml.Node) Migration {
+	m := migrationAtNode(node, path, false, fmt.Sprintf("replaced deprecated %v %v with %v", cType, name, r.Name))
+
+	var conf *yaml.Node
+	for i := 0; i < len(node.Content)-1; i += 2 {
+		switch node.Content[i].Value {
+		case "type":
+			node.Content[i+1].Value = r.Name
+		case name:
+			node.Content[i].Value = r.Name
+			conf = node.Content[i+1]
+		}
+	}
+	if len(r.Fields) == 0 {
+		return m
+	}
+	if conf == nil || conf.Kind != yaml.MappingNode {
+		conf = &yaml.Node{Kind: yaml.MappingNode}
+		node.Content = append(node.Content, &yaml.Node{Kind: yaml.ScalarNode, Value: r.Name}, conf)
+	}
+
+	keys := make([]string, 0, len(r.Fields))
+	for k := range r.Fields {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+keysLoop:
+	for _, k := range keys {
+		for i := 0; i < len(conf.Content)-1; i += 2 {
+			if conf.Content[i].Value == k {
+				continue keysLoop
+			}
+		}
+		var value yaml.Node
+		if err := value.Encode(r.Fields[k]); err != nil {
+			continue
+		}
+		conf.Content = append(conf.Content, &yaml.Node{Kind: yaml.ScalarNode, Value: k}, &value)
+	}
+	return m
+}
+
+// MigrateYAML modifies a yaml node of a field in place in order to move it
+// away from deprecated components and fields.
+func (f FieldSpec) MigrateYAML(docProv Provider, path string, node *yaml.Node) []Migration {
+	node = unwrapDocumentNode(node)
+
+	var migrations []Migration
+	switch f.Kind {
+	case Kind2DArray:
+		if node.Kind != yaml.SequenceNode {
+			return nil
+		}
+		for i, child := range node.Content {
+			migrations = append(migrations, f.Array().MigrateYAML(docProv, joinMigratePath(path, strconv.Itoa(i)), child)...)
+		}
+		return migrations
+	case KindArray:
+		if node.Kind != yaml.SequenceNode {
+			return nil
+		}
+		for i, child := range node.Content {
+			migrations = append(migrations, f.Scalar().MigrateYAML(docProv, joinMigratePath(path, strconv.Itoa(i)), child)...)
+		}
+		return migrations
+	case KindMap:
+		if node.Kind != yaml.MappingNode {
+			return nil
+		}
+		for i := 0; i < len(node.Content)-1; i += 2 {
+			migrations = append(migrations, f.Scalar().MigrateYAML(docProv, joinMigratePath(path, node.Content[i].Value), node.Content[i+1])...)
+		}
+		return migrations
+	}
+
+	if coreType, isCore := f.Type.IsCoreComponent(); isCore {
+		return MigrateYAML(docProv, coreType, path, node)
+	}
+	if len(f.Children) > 0 {
+		return f.Children.MigrateYAML(docProv, path, node)
+	}
+	return nil
+}
+
+// MigrateYAML modifies a yaml node of an object in place, removing deprecated
+// fields that are set to their default values and migrating the remaining
+// fields.
+func (f FieldSpecs) MigrateYAML(docProv Provider, path string, node *yaml.Node) []Migration {
+	node = unwrapDocumentNode(node)
+	if node.Kind != yaml.MappingNode {
+		return nil
+	}
+
+	specNames := map[string]FieldSpec{}
+	for _, field := range f {
+		specNames[field.Name] = field
+	}
+
+	var migrations []Migration
+	newContent := make([]*yaml.Node, 0, len(node.Content))
+	for i := 0; i < len(node.Content)-1; i += 2 {
+		key, value := node.Content[i], node.Content[i+1]
+		fieldPath := joinMigratePath(path, key.Value)
+
+		spec, exists := specNames[key.Value]
+		if !exists {
+			newContent = append(newContent, key, value)
+			continue
+		}
+		if spec.IsDeprecated {
+			if spec.isDefaultYAML(value) {
+				migrations = append(migrations, migrationAtNode(key, fieldPath, false, fmt.Sprintf(
+					"removed deprecated field %v as it is set to its default value", key.Value,
+				)))
+				continue
+			}
+			what := fmt.Sprintf("field %v is deprecated", key.Value)
+			if desc := strings.TrimPrefix(firstSentence(spec.Description), "DEPRECATED: "); desc != "" && desc != "Do not use." {
+				what += ": " + desc
+			}
+			migrations = append(migrations, migrationAtNode(key, fieldPath, true, what))
+		}
+		newContent = append(newContent, key, value)
+		migrations = append(migrations, spec.MigrateYAML(docProv, fieldPath, value)...)
+	}
+	node.Content = newContent
+	return migrations
+}
@@ -0,0 +1,159 @@
+package docs_test
+
+import (
+	"testing"
+
+	"github.com/Jeffail/benthos/v3/internal/docs"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
+)
+
+func TestMigrateYAML(t *testing.T) {
+	docsProv := docs.NewMappedDocsProvider()
+	docsProv.RegisterDocs(docs.ComponentSpec{
+		Name:   "oldinput",
+		Type:   docs.TypeInput,
+		Status: docs.StatusDeprecated,
+		ReplacedBy: &docs.ComponentReplacement{
+			Name: "newinput",
+			Fields: map[string]interface{}{
+				"mode": "legacy",
+			},
+		},
+		Config: docs.FieldComponent().WithChildren(
+			docs.FieldString("address", ""),
+			docs.FieldDeprecated("count").HasDefault(1),
+		),
+	})
+	docsProv.RegisterDocs(docs.ComponentSpec{
+		Name: "newinput",
+		Type: docs.TypeInput,
+		Config: docs.FieldComponent().WithChildren(
+			docs.FieldString("address", ""),
+			docs.FieldString("mode", "").HasDefault("modern"),
+		),
+	})
+	docsProv.RegisterDocs(docs.ComponentSpec{
+		Name:   "oldproc",
+		Type:   docs.TypeProcessor,
+		Status: docs.StatusDeprecated,
+		Config: docs.FieldComponent().WithChildren(
+			docs.FieldString("value", ""),
+		),
+	})
+	docsProv.RegisterDocs(docs.ComponentSpec{
+		Name: "newproc",
+		Type: docs.TypeProcessor,
+		Config: docs.FieldComponent().WithChildren(
+			docs.FieldDeprecated("legacy", "Use the new thing."),
+			docs.FieldDeprecated("other").HasDefault("nah"),
+		),
+	})
+
+	spec := docs.FieldSpecs{
+		docs.FieldCommon("input", "").HasType(docs.FieldTypeInput),
+	}
+
+	tests := map[string]struct {
+		input      string
+		output     string
+		migrations []docs.Migration
+	}{
+		"no changes": {
+			input: `
+input:
+  newinput:
+    address: foo
+`,
+			output: `
+input:
+  newinput:
+    address: foo
+`,
+		},
+		"replace component": {
+			input: `
+input:
+  oldinput:
+    address: foo
+    count: 1
+`,
+			output: `
+input:
+  newinput:
+    address: foo
+    mode: legacy
+`,
+			migrations: []docs.Migration{
+				{Line: 3, Column: 3, Path: "input", What: "replaced deprecated input oldinput with newinput"},
+				{Line: 5, Column: 5, Path: "input.oldinput.count", What: "removed deprecated field count as it is set to its default value"},
+			},
+		},
+		"replace component with type field": {
+			input: `
+input:
+  type: oldinput
+  oldinput:
+    mode: modern
+`,
+			output: `
+input:
+  type: newinput
+  newinput:
+    mode: modern
+`,
+			migrations: []docs.Migration{
+				{Line: 3, Column: 3, Path: "input", What: "replaced deprecated input oldinput with newinput"},
+			},
+		},
+		"manual migrations": {
+			input: `
+input:
+  newinput:
+    address: foo
+  processors:
+    - oldproc:
+        value: bar
+    - newproc:
+        legacy: baz
+        other: nah
+`,
+			output: `
+input:
+  newinput:
+    address: foo
+  processors:
+    - oldproc:
+        value: bar
+    - newproc:
+        legacy: baz
+`,
+			migrations: []docs.Migration{
+				{Line: 6, Column: 7, Path: "input.processors.0", Manual: true, What: "processor oldproc is deprecated and has no direct replacement, alternatives are listed by `benthos docs processor oldproc`"},
+				{Line: 9, Column: 9, Path: "input.processors.1.newproc.legacy", Manual: true, What: "field legacy is deprecated: Use the new thing."},
+				{Line: 10, Column: 9, Path: "input.processors.1.newproc.other", What: "removed deprecated field other as it is set to its default value"},
+			},
+		},
+	}
+
+	for name, test := range tests {
+		test := test
+		t.Run(name, func(t *testing.T) {
+			var node yaml.Node
+			require.NoError(t, yaml.Unmarshal([]byte(test.input), &node))
+
+			migrations := spec.MigrateYAML(docsProv, "", &node)
+			assert.Equal(t, test.migrations, migrations)
+
+			var expected yaml.Node
+			require.NoError(t, yaml.Unmarshal([]byte(test.output), &expected))
+
+			actualBytes, err := yaml.Marshal(&node)
+			require.NoError(t, err)
+			expectedBytes, err := yaml.Marshal(&expected)
+			require.NoError(t, err)
+			assert.Equal(t, string(expectedBytes), string(actualBytes))
+		})
+	}
+}
@@ -1,7 +1,10 @@
 package docs
 
 import (
+	"bytes"
+	"encoding/json"
 	"fmt"
+	"strings"
 
 	"gopkg.in/yaml.v3"
 )
@@ -231,6 +234,9 @@ func SanitiseYAML(cType Type, node *yaml.Node, conf SanitiseConfig) error {
 	for i := 0; i < len(node.Content)-1; i += 2 {
 		if node.Content[i].Value == "label" {
 			if _, omit := labelField.shouldOmitYAML(nil, node.Content[i+1], node); !omit {
+				if conf.DocumentFields {
+					node.Content[i].HeadComment = labelField.yamlComment()
+				}
 				newNodes = append(newNodes, node.Content[i], node.Content[i+1])
 			}
 			break
@@ -276,6 +282,9 @@ func SanitiseYAML(cType Type, node *yaml.Node, conf SanitiseConfig) error {
 		if err := cSpec.Config.SanitiseYAML(node.Content[i+1], conf); err != nil {
 			return err
 		}
+		if conf.DocumentFields {
+			node.Content[i].HeadComment = cSpec.yamlComment()
+		}
 		newNodes = append(newNodes, node.Content[i], node.Content[i+1])
 		break
 	}
@@ -294,6 +303,9 @@ func SanitiseYAML(cType Type, node *yaml.Node, conf SanitiseConfig) error {
 		if err := cSpec.Config.SanitiseYAML(bodyNode, conf); err != nil {
 			return err
 		}
+		if conf.DocumentFields {
+			keyNode.HeadComment = cSpec.yamlComment()
+		}
 		newNodes = append(newNodes, &keyNode, bodyNode)
 	}
 
@@ -408,25 +420,93 @@ func (f FieldSpecs) SanitiseYAML(node *yaml.Node, conf SanitiseConfig) error {
 		if _, omit := field.shouldOmitYAML(f, value, node); omit {
 			continue
 		}
-		if err := field.SanitiseYAML(value, conf); err != nil {
+		if field.IsSecret && conf.ScrubSecrets {
+			if value.Kind == yaml.ScalarNode && value.Value != "" {
+				value.SetString(SecretScrubbed)
+			}
+		} else if err := field.SanitiseYAML(value, conf); err != nil {
 			return err
 		}
 		var keyNode yaml.Node
 		if err := keyNode.Encode(field.Name); err != nil {
 			return err
 		}
+		if conf.DocumentFields {
+			keyNode.HeadComment = field.yamlComment()
+		}
 		newNodes = append(newNodes, &keyNode, value)
 	}
 	node.Content = newNodes
 	return nil
 }
 
+// firstSentence returns the first sentence of a markdown description.
+func firstSentence(desc string) string {
+	desc = strings.TrimSpace(desc)
+	if i := strings.Index(desc, "\n"); i >= 0 {
+		desc = desc[:i]
+	}
+	if i := strings.Index(desc, ". "); i >= 0 {
+		desc = desc[:i+1]
+	}
+	return desc
+}
+
+// yamlComment returns a comment that documents a component when it is used
+// within a YAML config.
+func (c ComponentSpec) yamlComment() string {
+	comment := firstSentence(c.Summary)
+	if comment == "" {
+		comment = firstSentence(c.Description)
+	}
+	if c.Status == StatusDeprecated && !strings.HasPrefix(comment, "DEPRECATED") {
+		comment = strings.TrimSpace("DEPRECATED: " + comment)
+	}
+	return comment
+}
+
+// yamlComment returns a comment that documents a field when it is used within
+// a YAML config, including its default value where it has one.
+func (f FieldSpec) yamlComment() string {
+	var lines []string
+	desc := firstSentence(f.Description)
+	if f.IsDeprecated && !strings.HasPrefix(desc, "DEPRECATED") {
+		desc = strings.TrimSpace("DEPRECATED: " + desc)
+	}
+	if desc != "" {
+		lines = append(lines, desc)
+	}
+	if len(f.Options) > 0 {
+		lines = append(lines, "Options: "+strings.Join(f.Options, ", ")+".")
+	} else if len(f.AnnotatedOptions) > 0 {
+		opts := make([]string, len(f.AnnotatedOptions))
+		for i, o := range f.AnnotatedOptions {
+			opts[i] = o[0]
+		}
+		lines = append(lines, "Options: "+strings.Join(opts, ", ")+".")
+	}
+	if f.Default != nil {
+		if defBytes, err := json.Marshal(*f.Default); err == nil {
+			lines = append(lines, "Default: "+string(defBytes))
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
 //------------------------------------------------------------------------------
 
+func lintErrorAtNode(node *yaml.Node, t LintType, msg string) Lint {
+	return Lint{Line: node.Line, Column: node.Column, Level: LintError, Type: t, What: msg}
+}
+
+func lintWarningAtNode(node *yaml.Node, t LintType, msg string) Lint {
+	return Lint{Line: node.Line, Column: node.Column, Level: LintWarning, Type: t, What: msg}
+}
+
 func lintYAMLFromOmit(parentSpec FieldSpecs, lintTargetSpec FieldSpec, parent, node *yaml.Node) []Lint {
 	why, shouldOmit := lintTargetSpec.shouldOmitYAML(parentSpec, node, parent)
 	if shouldOmit {
-		return []Lint{NewLintError(node.Line, why)}
+		return []Lint{lintErrorAtNode(node, LintShouldOmit, why)}
 	}
 	return nil
 }
@@ -445,15 +525,37 @@ func customLintFromYAML(ctx LintContext, spec FieldSpec, node *yaml.Node) []Lint
 		// that we'll capture this type error elsewhere.
 		return []Lint{}
 	}
-	line := node.Line
+	line, col := node.Line, node.Column
 	if node.Style == yaml.LiteralStyle {
 		line++
+		if indentCol, ok := sourceIndentColumn(ctx.Source, line); ok {
+			col = indentCol
+		}
 	}
 
-	lints := lintFn(ctx, line, node.Column, fieldValue)
+	lints := lintFn(ctx, line, col, fieldValue)
 	return lints
 }
 
+// sourceIndentColumn returns the column of the first non-whitespace character
+// of a line within a raw source, which is where the contents of a block scalar
+// begin.
+func sourceIndentColumn(source []byte, line int) (int, bool) {
+	if line < 1 {
+		return 0, false
+	}
+	lines := bytes.SplitN(source, []byte("\n"), line+1)
+	if len(lines) < line {
+		return 0, false
+	}
+	content := lines[line-1]
+	indent := len(content) - len(bytes.TrimLeft(content, " \t"))
+	if indent == len(content) {
+		return 0, false
+	}
+	return indent + 1, true
+}
+
 // LintYAML takes a yaml.Node and a config spec and returns a list of linting
 // errors found in the config.
 func LintYAML(ctx LintContext, cType Type, node *yaml.Node) []Lint {
@@ -481,17 +583,20 @@ func LintYAML(ctx LintContext, cType Type, node *yaml.Node) []Lint {
 		}
 		var err error
 		if name, _, err = getInferenceCandidateFromList(ctx.DocsProvider, cType, "", keys); err != nil {
-			lints = append(lints, NewLintWarning(node.Line, "unable to infer component type"))
+			lints = append(lints, lintWarningAtNode(node, LintComponentMissing, "unable to infer component type"))
 			return lints
 		}
 	}
 
 	cSpec, exists := GetDocs(ctx.DocsProvider, name, cType)
 	if !exists {
-		lints = append(lints, NewLintWarning(node.Line, fmt.Sprintf("failed to obtain docs for %v type %v", cType, name)))
+		lints = append(lints, lintWarningAtNode(node, LintComponentNotFound, fmt.Sprintf("failed to obtain docs for %v type %v", cType, name)))
 		return lints
 	}
 
+	lints = append(lints, ctx.Rules.lintComponent(ctx, cType, name, node)...)
+	ctx.keyedComponent = false
+
 	nameFound := false
 	for i := 0; i < len(node.Content)-1; i += 2 {
 		if node.Content[i].Value == name {
@@ -508,7 +613,7 @@ func LintYAML(ctx LintContext, cType Type, node *yaml.Node) []Lint {
 		}
 		if node.Content[i].Value == "plugin" {
 			if nameFound || !cSpec.Plugin {
-				lints = append(lints, NewLintError(node.Content[i].Line, "plugin object is ineffective"))
+				lints = append(lints, lintErrorAtNode(node.Content[i], LintShouldOmit, "plugin object is ineffective"))
 			} else {
 				lints = append(lints, cSpec.Config.LintYAML(ctx, node.Content[i+1])...)
 			}
@@ -518,8 +623,8 @@ func LintYAML(ctx LintContext, cType Type, node *yaml.Node) []Lint {
 			lints = append(lints, lintYAMLFromOmit(cSpec.Config.Children, spec, node, node.Content[i+1])...)
 			lints = append(lints, spec.LintYAML(ctx, node.Content[i+1])...)
 		} else {
-			lints = append(lints, NewLintError(
-				node.Content[i].Line,
+			lints = append(lints, lintErrorAtNode(
+				node.Content[i], LintUnknown,
 				fmt.Sprintf("field %v is invalid when the component type is %v (%v)", node.Content[i].Value, name, cType),
 			))
 		}
@@ -543,7 +648,7 @@ func (f FieldSpec) LintYAML(ctx LintContext, node *yaml.Node) []Lint {
 	switch f.Kind {
 	case Kind2DArray:
 		if node.Kind != yaml.SequenceNode {
-			lints = append(lints, NewLintError(node.Line, "expected array value"))
+			lints = append(lints, lintErrorAtNode(node, LintExpectedArray, "expected array value"))
 			return lints
 		}
 		for i := 0; i < len(node.Content); i++ {
@@ -552,7 +657,7 @@ func (f FieldSpec) LintYAML(ctx LintContext, node *yaml.Node) []Lint {
 		return lints
 	case KindArray:
 		if node.Kind != yaml.SequenceNode {
-			lints = append(lints, NewLintError(node.Line, "expected array value"))
+			lints = append(lints, lintErrorAtNode(node, LintExpectedArray, "expected array value"))
 			return lints
 		}
 		for i := 0; i < len(node.Content); i++ {
@@ -561,11 +666,15 @@ func (f FieldSpec) LintYAML(ctx LintContext, node *yaml.Node) []Lint {
 		return lints
 	case KindMap:
 		if node.Kind != yaml.MappingNode {
-			lints = append(lints, NewLintError(node.Line, "expected object value"))
+			lints = append(lints, lintErrorAtNode(node, LintExpectedObject, "expected object value"))
 			return lints
 		}
+		elemCtx := ctx
+		if _, isCore := f.Type.IsCoreComponent(); isCore {
+			elemCtx.keyedComponent = true
+		}
 		for i := 0; i < len(node.Content)-1; i += 2 {
-			lints = append(lints, f.Scalar().LintYAML(ctx, node.Content[i+1])...)
+			lints = append(lints, f.Scalar().LintYAML(elemCtx, node.Content[i+1])...)
 		}
 		return lints
 	}
@@ -584,15 +693,16 @@ func (f FieldSpec) LintYAML(ctx LintContext, node *yaml.Node) []Lint {
 	}
 
 	// Otherwise we're a leaf node, so do basic type checking
+	lints = append(lints, ctx.Rules.lintField(f, node)...)
 	switch f.Type {
 	// TODO: Do proper checking for bool and number types.
 	case FieldTypeBool, FieldTypeString, FieldTypeInt, FieldTypeFloat:
 		if node.Kind == yaml.MappingNode || node.Kind == yaml.SequenceNode {
-			lints = append(lints, NewLintError(node.Line, fmt.Sprintf("expected %v value", f.Type)))
+			lints = append(lints, lintErrorAtNode(node, LintExpectedScalar, fmt.Sprintf("expected %v value", f.Type)))
 		}
 	case FieldTypeObject:
 		if node.Kind != yaml.MappingNode && node.Kind != yaml.AliasNode {
-			lints = append(lints, NewLintError(node.Line, "expected object value"))
+			lints = append(lints, lintErrorAtNode(node, LintExpectedObject, "expected object value"))
 		}
 	}
 	return lints
@@ -608,7 +718,7 @@ func (f FieldSpecs) LintYAML(ctx LintContext, node *yaml.Node) []Lint {
 			// TODO: Actually lint through aliases
 			return nil
 		}
-		lints = append(lints, NewLintError(node.Line, "expected object value"))
+		lints = append(lints, lintErrorAtNode(node, LintExpectedObject, "expected object value"))
 		return lints
 	}
 
@@ -621,7 +731,7 @@ func (f FieldSpecs) LintYAML(ctx LintContext, node *yaml.Node) []Lint {
 		spec, exists := specNames[node.Content[i].Value]
 		if !exists {
 			if node.Content[i+1].Kind != yaml.AliasNode {
-				lints = append(lints, NewLintError(node.Content[i].Line, fmt.Sprintf("field %v not recognised", node.Content[i].Value)))
+				lints = append(lints, lintErrorAtNode(node.Content[i], LintUnknown, fmt.Sprintf("field %v not recognised", node.Content[i].Value)))
 			}
 			continue
 		}
@@ -638,7 +748,7 @@ func (f FieldSpecs) LintYAML(ctx LintContext, node *yaml.Node) []Lint {
 			remaining.Kind == KindScalar &&
 			!remaining.IsDeprecated &&
 			len(remaining.Children) == 0 {
-			lints = append(lints, NewLintError(node.Line, fmt.Sprintf("field %v is required", name)))
+			lints = append(lints, lintErrorAtNode(node, LintMissing, fmt.Sprintf("field %v is required", name)))
 		}
 	}
 	return lints
@@ -0,0 +1,115 @@
+package docs
+
+import (
+	"gopkg.in/yaml.v3"
+)
+
+func mergeMappingYAML(dst, src *yaml.Node, mergeField func(name string, dst, src *yaml.Node)) {
+	for i := 0; i < len(src.Content)-1; i += 2 {
+		name := src.Content[i].Value
+
+		var dstValue *yaml.Node
+		for j := 0; j < len(dst.Content)-1; j += 2 {
+			if dst.Content[j].Value == name {
+				dstValue = dst.Content[j+1]
+				break
+			}
+		}
+		if dstValue == nil {
+			dst.Content = append(dst.Content, src.Content[i], src.Content[i+1])
+			continue
+		}
+		mergeField(name, dstValue, src.Content[i+1])
+	}
+}
+
+// MergeYAML deep merges the fields of a YAML document src into a YAML document
+// dst. Objects are merged field by field, whereas all other values, including
+// arrays, are replaced by the value within src.
+func (f FieldSpecs) MergeYAML(docsProvider Provider, dst, src *yaml.Node) {
+	dst = unwrapDocumentNode(dst)
+	src = unwrapDocumentNode(src)
+
+	if dst.Kind != yaml.MappingNode || src.Kind != yaml.MappingNode {
+		*dst = *src
+		return
+	}
+
+	mergeMappingYAML(dst, src, func(name string, dst, src *yaml.Node) {
+		for _, spec := range f {
+			if spec.Name == name {
+				spec.MergeYAML(docsProvider, dst, src)
+				return
+			}
+		}
+		*dst = *src
+	})
+}
+
+func mergeYAMLCore(docsProvider Provider, coreType Type, dst, src *yaml.Node) {
+	if docsProvider == nil {
+		docsProvider = globalProvider
+	}
+
+	// When the component type changes the old config is discarded, as the
+	// fields of the two types would otherwise be mixed together.
+	dstName, _, dstErr := GetInferenceCandidateFromYAML(docsProvider, coreType, "", dst)
+	srcName, _, srcErr := GetInferenceCandidateFromYAML(docsProvider, coreType, "", src)
+	if dstErr == nil && srcErr == nil && dstName != srcName {
+		*dst = *src
+		return
+	}
+
+	reservedFields := reservedFieldsByType(coreType)
+	mergeMappingYAML(dst, src, func(name string, dst, src *yaml.Node) {
+		if f, exists := reservedFields[name]; exists {
+			f.MergeYAML(docsProvider, dst, src)
+			return
+		}
+		if cSpec, exists := GetDocs(docsProvider, name, coreType); exists {
+			cSpec.Config.MergeYAML(docsProvider, dst, src)
+			return
+		}
+		*dst = *src
+	})
+}
+
+// MergeYAML deep merges a YAML value src into a YAML value dst according to
+// the field spec. Objects are merged field by field, whereas all other values,
+// including arrays, are replaced by the value within src. If src contains a
+// component of a different type to dst then it replaces the component
+// entirely.
+func (f FieldSpec) MergeYAML(docsProvider Provider, dst, src *yaml.Node) {
+	dst = unwrapDocumentNode(dst)
+	src = unwrapDocumentNode(src)
+
+	if dst.Kind != yaml.MappingNode || src.Kind != yaml.MappingNode {
+		*dst = *src
+		return
+	}
+
+	switch f.Kind {
+	case KindMap:
+		mergeMappingYAML(dst, src, func(_ string, dst, src *yaml.Node) {
+			f.Scalar().MergeYAML(docsProvider, dst, src)
+		})
+		return
+	case KindArray, Kind2DArray:
+		*dst = *src
+		return
+	}
+	if coreType, isCore := f.Type.IsCoreComponent(); isCore {
+		mergeYAMLCore(docsProvider, coreType, dst, src)
+		return
+	}
+	if len(f.Children) > 0 {
+		f.Children.MergeYAML(docsProvider, dst, src)
+		return
+	}
+
+	// Objects without documented children, such as plugin configs, are merged
+	// naively.
+	mergeMappingYAML(dst, src, func(_ string, dst, src *yaml.Node) {
+		FieldSpec{}.MergeYAML(docsProvider, dst, src)
+	})
+}
@@ -0,0 +1,161 @@
+package docs_test
+
+import (
+	"testing"
+
+	"github.com/Jeffail/benthos/v3/internal/docs"
+	"github.com/Jeffail/benthos/v3/lib/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
+)
+
+func TestMergeYAML(t *testing.T) {
+	mockProv := docs.NewMappedDocsProvider()
+	mockProv.RegisterDocs(docs.ComponentSpec{
+		Name: "kafka",
+		Type: docs.TypeInput,
+		Config: docs.FieldComponent().WithChildren(
+			docs.FieldString("addresses", "").Array(),
+			docs.FieldString("topics", "").Array(),
+			docs.FieldString("consumer_group", ""),
+		),
+	})
+	mockProv.RegisterDocs(docs.ComponentSpec{
+		Name: "generate",
+		Type: docs.TypeInput,
+		Config: docs.FieldComponent().WithChildren(
+			docs.FieldString("mapping", ""),
+		),
+	})
+	mockProv.RegisterDocs(docs.ComponentSpec{
+		Name: "compress",
+		Type: docs.TypeProcessor,
+		Config: docs.FieldComponent().WithChildren(
+			docs.FieldString("algorithm", ""),
+		),
+	})
+
+	tests := []struct {
+		name   string
+		dst    string
+		src    string
+		output string
+	}{
+		{
+			name: "merge component fields",
+			dst: `
+input:
+  kafka:
+    addresses: [ "foo", "bar" ]
+    topics: [ "baz" ]
+    consumer_group: meow
+`,
+			src: `
+input:
+  kafka:
+    topics: [ "buz" ]
+`,
+			output: `
+input:
+  kafka:
+    addresses: [ "foo", "bar" ]
+    topics: [ "buz" ]
+    consumer_group: meow
+`,
+		},
+		{
+			name: "add reserved fields to component",
+			dst: `
+input:
+  label: foo
+  kafka:
+    topics: [ "baz" ]
+`,
+			src: `
+input:
+  label: bar
+  processors:
+    - compress:
+        algorithm: gzip
+`,
+			output: `
+input:
+  label: bar
+  kafka:
+    topics: [ "baz" ]
+  processors:
+    - compress:
+        algorithm: gzip
+`,
+		},
+		{
+			name: "replace component of different type",
+			dst: `
+input:
+  kafka:
+    topics: [ "baz" ]
+`,
+			src: `
+input:
+  generate:
+    mapping: 'root = "hello world"'
+`,
+			output: `
+input:
+  generate:
+    mapping: 'root = "hello world"'
+`,
+		},
+		{
+			name: "merge objects and unknown fields",
+			dst: `
+http:
+  address: 0.0.0.0:4195
+  enabled: true
+logger:
+  static_fields:
+    foo: bar
+tests:
+  - name: foo
+`,
+			src: `
+http:
+  enabled: false
+logger:
+  static_fields:
+    baz: buz
+tests:
+  - name: bar
+`,
+			output: `
+http:
+  address: 0.0.0.0:4195
+  enabled: false
+logger:
+  static_fields:
+    foo: bar
+    baz: buz
+tests:
+  - name: bar
+`,
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			var dst, src yaml.Node
+
+			require.NoError(t, yaml.Unmarshal([]byte(test.dst), &dst))
+			require.NoError(t, yaml.Unmarshal([]byte(test.src), &src))
+
+			config.Spec().MergeYAML(mockProv, &dst, &src)
+
+			var idst, ioutput interface{}
+			require.NoError(t, dst.Decode(&idst))
+			require.NoError(t, yaml.Unmarshal([]byte(test.output), &ioutput))
+			assert.Equal(t, ioutput, idst)
+		})
+	}
+}
@@ -3,7 +3,9 @@ package docs
 import (
 	"errors"
 	"fmt"
+	"sort"
 	"strconv"
+	"strings"
 
 	"gopkg.in/yaml.v3"
 )
@@ -55,8 +57,75 @@ func getIndexFromSequence(name string, allowAppend bool, node *yaml.Node) (*yaml
 	return foundNode, nil
 }
 
+// coerceValue converts an untagged scalar value, such as one provided as a CLI
+// flag, into a node of the type expected by the field. Values of array, map,
+// object and component fields beginning with a bracket are parsed as YAML.
+func (f FieldSpec) coerceValue(value *yaml.Node) error {
+	if value.Kind != yaml.ScalarNode || value.Tag != "" {
+		return nil
+	}
+
+	parseYAML := func() error {
+		var node yaml.Node
+		if err := yaml.Unmarshal([]byte(value.Value), &node); err != nil {
+			return fmt.Errorf("failed to parse value as YAML: %w", err)
+		}
+		*value = *unwrapDocumentNode(&node)
+		return nil
+	}
+	trimmed := strings.TrimSpace(value.Value)
+
+	switch f.Kind {
+	case KindArray:
+		if strings.HasPrefix(trimmed, "[") {
+			return parseYAML()
+		}
+		return f.Scalar().coerceValue(value)
+	case Kind2DArray:
+		if strings.HasPrefix(trimmed, "[") {
+			return parseYAML()
+		}
+		return f.Array().coerceValue(value)
+	case KindMap:
+		if strings.HasPrefix(trimmed, "{") {
+			return parseYAML()
+		}
+		return nil
+	}
+
+	if _, isCore := f.Type.IsCoreComponent(); isCore || f.Type == FieldTypeObject || len(f.Children) > 0 {
+		if strings.HasPrefix(trimmed, "{") {
+			return parseYAML()
+		}
+		return nil
+	}
+
+	switch f.Type {
+	case FieldTypeString:
+		value.Tag = "!!str"
+	case FieldTypeInt:
+		if _, err := strconv.ParseInt(trimmed, 10, 64); err != nil {
+			return fmt.Errorf("expected an int value, got '%v'", value.Value)
+		}
+		value.Tag, value.Value = "!!int", trimmed
+	case FieldTypeFloat:
+		if _, err := strconv.ParseFloat(trimmed, 64); err != nil {
+			return fmt.Errorf("expected a float value, got '%v'", value.Value)
+		}
+		value.Tag, value.Value = "!!float", trimmed
+	case FieldTypeBool:
+		b, err := strconv.ParseBool(trimmed)
+		if err != nil {
+			return fmt.Errorf("expected a bool value, got '%v'", value.Value)
+		}
+		value.Tag, value.Value = "!!bool", strconv.FormatBool(b)
+	}
+	return nil
+}
+
 // SetYAMLPath sets the value of a node within a YAML document identified by a
-// path to a value.
+// path to a value. Untagged scalar values are coerced into the type of the
+// target field.
 func (f FieldSpecs) SetYAMLPath(docsProvider Provider, root, value *yaml.Node, path ...string) error {
 	root = unwrapDocumentNode(root)
 	value = unwrapDocumentNode(value)
@@ -72,6 +141,12 @@ func (f FieldSpecs) SetYAMLPath(docsProvider Provider, root, value *yaml.Node, p
 		return fmt.Errorf("%v: field not recognised", path[0])
 	}
 
+	if len(path) == 1 {
+		if err := foundSpec.coerceValue(value); err != nil {
+			return fmt.Errorf("%v: %w", path[0], err)
+		}
+	}
+
 	foundNode, err := getFieldFromMapping(path[0], true, root)
 	if err != nil {
 		return err
@@ -145,6 +220,11 @@ func (f FieldSpec) SetYAMLPath(docsProvider Provider, root, value *yaml.Node, pa
 			}
 			return nil
 		}
+		if len(path) == 1 {
+			if err := f.Scalar().coerceValue(value); err != nil {
+				return fmt.Errorf("%v: %w", path[0], err)
+			}
+		}
 		target, err := getIndexFromSequence(path[0], true, root)
 		if err != nil {
 			return err
@@ -157,6 +237,11 @@ func (f FieldSpec) SetYAMLPath(docsProvider Provider, root, value *yaml.Node, pa
 		if len(path) == 0 {
 			return errors.New("cannot set map directly")
 		}
+		if len(path) == 1 {
+			if err := f.Scalar().coerceValue(value); err != nil {
+				return fmt.Errorf("%v: %w", path[0], err)
+			}
+		}
 		target, err := getFieldFromMapping(path[0], true, root)
 		if err != nil {
 			return err
@@ -283,3 +368,64 @@ func (f FieldSpec) YAMLLabelsToPaths(docsProvider Provider, node *yaml.Node, lab
 		}
 	}
 }
+
+//------------------------------------------------------------------------------
+
+// FieldsAtPath walks a field spec along a path of field names and returns the
+// fields that may follow it, which is useful for completing paths. Array
+// indexes and map keys within the path are skipped over. When the path leads
+// to a core component the component type is also returned, as the name of any
+// implementation of that type may also follow the path.
+func (f FieldSpecs) FieldsAtPath(docsProvider Provider, path ...string) (FieldSpecs, Type, bool) {
+	if len(path) == 0 {
+		return f, "", false
+	}
+	for _, spec := range f {
+		if spec.Name == path[0] {
+			return spec.FieldsAtPath(docsProvider, path[1:]...)
+		}
+	}
+	return nil, "", false
+}
+
+// FieldsAtPath walks a field spec along a path of field names and returns the
+// fields that may follow it.
+func (f FieldSpec) FieldsAtPath(docsProvider Provider, path ...string) (FieldSpecs, Type, bool) {
+	switch f.Kind {
+	case Kind2DArray:
+		if len(path) == 0 {
+			return nil, "", false
+		}
+		return f.Array().FieldsAtPath(docsProvider, path[1:]...)
+	case KindArray, KindMap:
+		if len(path) == 0 {
+			return nil, "", false
+		}
+		return f.Scalar().FieldsAtPath(docsProvider, path[1:]...)
+	}
+
+	coreType, isCore := f.Type.IsCoreComponent()
+	if !isCore {
+		return f.Children.FieldsAtPath(docsProvider, path...)
+	}
+	if docsProvider == nil {
+		docsProvider = globalProvider
+	}
+
+	coreFields := FieldSpecs{}
+	for _, spec := range reservedFieldsByType(coreType) {
+		coreFields = append(coreFields, spec)
+	}
+	sort.Slice(coreFields, func(i, j int) bool {
+		return coreFields[i].Name < coreFields[j].Name
+	})
+	if len(path) == 0 {
+		return coreFields, coreType, true
+	}
+	if cSpec, exists := GetDocs(docsProvider, path[0], coreType); exists {
+		conf := cSpec.Config
+		conf.Name = path[0]
+		coreFields = append(coreFields, conf)
+	}
+	return coreFields.FieldsAtPath(docsProvider, path...)
+}
@@ -585,3 +585,84 @@ pipeline:
 		})
 	}
 }
+
+func TestFieldsAtPath(t *testing.T) {
+	mockProv := docs.NewMappedDocsProvider()
+	mockProv.RegisterDocs(docs.ComponentSpec{
+		Name: "kafka",
+		Type: docs.TypeInput,
+		Config: docs.FieldComponent().WithChildren(
+			docs.FieldString("addresses", "").Array(),
+			docs.FieldCommon("tls", "").WithChildren(
+				docs.FieldBool("enabled", ""),
+			),
+		),
+	})
+	mockProv.RegisterDocs(docs.ComponentSpec{
+		Name: "compress",
+		Type: docs.TypeProcessor,
+		Config: docs.FieldComponent().WithChildren(
+			docs.FieldString("algorithm", ""),
+		),
+	})
+
+	names := func(specs docs.FieldSpecs) []string {
+		var n []string
+		for _, s := range specs {
+			n = append(n, s.Name)
+		}
+		return n
+	}
+
+	tests := []struct {
+		name     string
+		path     []string
+		fields   []string
+		coreType docs.Type
+		isCore   bool
+	}{
+		{
+			name:   "nested object",
+			path:   []string{"input", "kafka", "tls"},
+			fields: []string{"enabled"},
+		},
+		{
+			name:     "core component",
+			path:     []string{"input"},
+			fields:   []string{"label", "plugin", "processors", "type"},
+			coreType: docs.TypeInput,
+			isCore:   true,
+		},
+		{
+			name:   "component implementation",
+			path:   []string{"input", "kafka"},
+			fields: []string{"addresses", "tls"},
+		},
+		{
+			name:     "array of components",
+			path:     []string{"pipeline", "processors", "0"},
+			fields:   []string{"label", "plugin", "type"},
+			coreType: docs.TypeProcessor,
+			isCore:   true,
+		},
+		{
+			name:   "component within array",
+			path:   []string{"input", "processors", "1", "compress"},
+			fields: []string{"algorithm"},
+		},
+		{
+			name: "unknown field",
+			path: []string{"input", "nope"},
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			fields, coreType, isCore := config.Spec().FieldsAtPath(mockProv, test.path...)
+			assert.Equal(t, test.fields, names(fields))
+			assert.Equal(t, test.coreType, coreType)
+			assert.Equal(t, test.isCore, isCore)
+		})
+	}
+}
@@ -652,7 +652,7 @@ testlintfooinput: &test-anchor
 processors:
   - testlintfooprocessor: *test-anchor`,
 			res: []docs.Lint{
-				docs.NewLintError(4, "field nope not recognised"),
+				{Line: 4, Column: 3, Level: docs.LintError, Type: docs.LintUnknown, What: "field nope not recognised"},
 			},
 		},
 		{
@@ -666,9 +666,9 @@ testlintfooinput:
   also_not_recognised: nah
 definitely_not_recognised: huh`,
 			res: []docs.Lint{
-				docs.NewLintError(4, "field not_recognised not recognised"),
-				docs.NewLintError(6, "field also_not_recognised not recognised"),
-				docs.NewLintError(7, "field definitely_not_recognised is invalid when the component type is testlintfooinput (input)"),
+				{Line: 4, Column: 3, Level: docs.LintError, Type: docs.LintUnknown, What: "field not_recognised not recognised"},
+				{Line: 6, Column: 3, Level: docs.LintError, Type: docs.LintUnknown, What: "field also_not_recognised not recognised"},
+				{Line: 7, Column: 1, Level: docs.LintError, Type: docs.LintUnknown, What: "field definitely_not_recognised is invalid when the component type is testlintfooinput (input)"},
 			},
 		},
 		{
@@ -682,8 +682,8 @@ processors:
   - testlintfooprocessor:
       also_not_recognised: nah`,
 			res: []docs.Lint{
-				docs.NewLintError(3, "field not_recognised not recognised"),
-				docs.NewLintError(7, "field also_not_recognised not recognised"),
+				{Line: 3, Column: 3, Level: docs.LintError, Type: docs.LintUnknown, What: "field not_recognised not recognised"},
+				{Line: 7, Column: 7, Level: docs.LintError, Type: docs.LintUnknown, What: "field also_not_recognised not recognised"},
 			},
 		},
 		{
@@ -699,7 +699,7 @@ processors:
   - label: foo
     testlintfooprocessor: {}`,
 			res: []docs.Lint{
-				docs.NewLintError(8, "Label 'foo' collides with a previously defined label at line 2"),
+				{Line: 8, Level: docs.LintError, Type: docs.LintDuplicateLabel, What: "Label 'foo' collides with a previously defined label at line 2"},
 			},
 		},
 		{
@@ -710,7 +710,7 @@ testlintfooinput:
   foo1: hello world
 processors: []`,
 			res: []docs.Lint{
-				docs.NewLintError(4, "field processors is empty and can be removed"),
+				{Line: 4, Column: 13, Level: docs.LintError, Type: docs.LintShouldOmit, What: "field processors is empty and can be removed"},
 			},
 		},
 		{
@@ -721,7 +721,7 @@ testlintfooinput:
   foo1: hello world
   foo2: drop me`,
 			res: []docs.Lint{
-				docs.NewLintError(4, "because foo"),
+				{Line: 4, Column: 9, Level: docs.LintError, Type: docs.LintShouldOmit, What: "because foo"},
 			},
 		},
 		{
@@ -735,7 +735,7 @@ testlintfooinput:
         foo1: somevalue
         not_recognised: nah`,
 			res: []docs.Lint{
-				docs.NewLintError(4, "expected array value"),
+				{Line: 4, Column: 5, Level: docs.LintError, Type: docs.LintExpectedArray, What: "expected array value"},
 			},
 		},
 		{
@@ -748,7 +748,7 @@ testlintfooinput:
       foo1: somevalue
       not_recognised: nah`,
 			res: []docs.Lint{
-				docs.NewLintError(6, "field not_recognised not recognised"),
+				{Line: 6, Column: 7, Level: docs.LintError, Type: docs.LintUnknown, What: "field not_recognised not recognised"},
 			},
 		},
 		{
@@ -761,7 +761,7 @@ testlintfooinput:
       foo1: [ somevalue ]
 `,
 			res: []docs.Lint{
-				docs.NewLintError(5, "expected string value"),
+				{Line: 5, Column: 13, Level: docs.LintError, Type: docs.LintExpectedScalar, What: "expected string value"},
 			},
 		},
 		{
@@ -775,7 +775,7 @@ testlintfooinput:
         foo1: somevalue
         not_recognised: nah`,
 			res: []docs.Lint{
-				docs.NewLintError(7, "field not_recognised not recognised"),
+				{Line: 7, Column: 9, Level: docs.LintError, Type: docs.LintUnknown, What: "field not_recognised not recognised"},
 			},
 		},
 		{
@@ -788,7 +788,7 @@ testlintfooinput:
         foo1: somevalue
         not_recognised: nah`,
 			res: []docs.Lint{
-				docs.NewLintError(4, "expected object value"),
+				{Line: 4, Column: 5, Level: docs.LintError, Type: docs.LintExpectedObject, What: "expected object value"},
 			},
 		},
 		{
@@ -807,7 +807,7 @@ testlintfooinput:
   foo7:
    - wat: no`,
 			res: []docs.Lint{
-				docs.NewLintError(4, "field wat not recognised"),
+				{Line: 4, Column: 6, Level: docs.LintError, Type: docs.LintUnknown, What: "field wat not recognised"},
 			},
 		},
 		{
@@ -819,7 +819,7 @@ testlintfooinput:
     key1:
       wat: no`,
 			res: []docs.Lint{
-				docs.NewLintError(4, "expected array value"),
+				{Line: 4, Column: 5, Level: docs.LintError, Type: docs.LintExpectedArray, What: "expected array value"},
 			},
 		},
 		{
@@ -840,7 +840,7 @@ testlintfooinput:
     key1:
       wat: nope`,
 			res: []docs.Lint{
-				docs.NewLintError(5, "field wat not recognised"),
+				{Line: 5, Column: 7, Level: docs.LintError, Type: docs.LintUnknown, What: "field wat not recognised"},
 			},
 		},
 		{
@@ -851,7 +851,7 @@ testlintfooinput:
   foo8:
     - wat: nope`,
 			res: []docs.Lint{
-				docs.NewLintError(4, "expected object value"),
+				{Line: 4, Column: 5, Level: docs.LintError, Type: docs.LintExpectedObject, What: "expected object value"},
 			},
 		},
 		{
@@ -892,7 +892,7 @@ func TestYAMLLinting(t *testing.T) {
 			inputSpec: docs.FieldString("foo", ""),
 			inputConf: `["foo","bar"]`,
 			res: []docs.Lint{
-				docs.NewLintError(1, "expected string value"),
+				{Line: 1, Column: 1, Level: docs.LintError, Type: docs.LintExpectedScalar, What: "expected string value"},
 			},
 		},
 		{
@@ -900,7 +900,7 @@ func TestYAMLLinting(t *testing.T) {
 			inputSpec: docs.FieldString("foo", "").Array(),
 			inputConf: `"foo"`,
 			res: []docs.Lint{
-				docs.NewLintError(1, "expected array value"),
+				{Line: 1, Column: 1, Level: docs.LintError, Type: docs.LintExpectedArray, What: "expected array value"},
 			},
 		},
 		{
@@ -910,7 +910,7 @@ func TestYAMLLinting(t *testing.T) {
 			),
 			inputConf: `"foo"`,
 			res: []docs.Lint{
-				docs.NewLintError(1, "expected object value"),
+				{Line: 1, Column: 1, Level: docs.LintError, Type: docs.LintExpectedObject, What: "expected object value"},
 			},
 		},
 		{
@@ -920,7 +920,7 @@ func TestYAMLLinting(t *testing.T) {
 			),
 			inputConf: `bar: {}`,
 			res: []docs.Lint{
-				docs.NewLintError(1, "expected string value"),
+				{Line: 1, Column: 6, Level: docs.LintError, Type: docs.LintExpectedScalar, What: "expected string value"},
 			},
 		},
 		{
@@ -933,7 +933,7 @@ func TestYAMLLinting(t *testing.T) {
 			inputConf: `bar:
   baz: {}`,
 			res: []docs.Lint{
-				docs.NewLintError(2, "expected string value"),
+				{Line: 2, Column: 8, Level: docs.LintError, Type: docs.LintExpectedScalar, What: "expected string value"},
 			},
 		},
 		{
@@ -946,7 +946,7 @@ func TestYAMLLinting(t *testing.T) {
 			),
 			inputConf: `bev: hello world`,
 			res: []docs.Lint{
-				docs.NewLintError(1, "field baz is required"),
+				{Line: 1, Column: 1, Level: docs.LintError, Type: docs.LintMissing, What: "field baz is required"},
 			},
 		},
 	}
@@ -1186,3 +1186,93 @@ processors:
 		})
 	}
 }
+
+func TestYAMLSanitationScrubSecrets(t *testing.T) {
+	docs.RegisterDocs(docs.ComponentSpec{
+		Name: "testyamlsanitsecretinput",
+		Type: docs.TypeInput,
+		Config: docs.FieldComponent().WithChildren(
+			docs.FieldString("user", ""),
+			docs.FieldString("password", "").Secret(),
+			docs.FieldString("token", "").Secret(),
+		),
+	})
+
+	conf := `
+testyamlsanitsecretinput:
+  user: foo
+  password: bar
+  token: ""
+`
+
+	for _, scrub := range []bool{true, false} {
+		var node yaml.Node
+		require.NoError(t, yaml.Unmarshal([]byte(conf), &node))
+		require.NoError(t, docs.SanitiseYAML(docs.TypeInput, &node, docs.SanitiseConfig{
+			ScrubSecrets: scrub,
+		}))
+
+		resBytes, err := yaml.Marshal(node.Content[0])
+		require.NoError(t, err)
+
+		expPassword := "bar"
+		if scrub {
+			expPassword = "'!!!SECRET_SCRUBBED!!!'"
+		}
+		assert.Equal(t, `testyamlsanitsecretinput:
+    user: foo
+    password: `+expPassword+`
+    token: ""
+`, string(resBytes))
+	}
+}
+
+func TestYAMLSanitationDocumentFields(t *testing.T) {
+	docs.RegisterDocs(docs.ComponentSpec{
+		Name:    "testyamlsanitdocumentinput",
+		Type:    docs.TypeInput,
+		Summary: "Reads things. It does so quite well.",
+		Config: docs.FieldComponent().WithChildren(
+			docs.FieldString("url", "The URL to read from. Can be anything.").HasDefault("http://localhost"),
+			docs.FieldString("mode", "The mode to read with.").HasOptions("fast", "slow").HasDefault("fast"),
+			docs.FieldDeprecated("old").HasType(docs.FieldTypeString).HasDefault(""),
+			docs.FieldCommon("tags", "Tags to add.").Array(),
+		),
+	})
+
+	conf := `
+label: foo
+testyamlsanitdocumentinput:
+  url: http://example.com
+  mode: slow
+  old: ""
+  tags: [ a ]
+`
+
+	var node yaml.Node
+	require.NoError(t, yaml.Unmarshal([]byte(conf), &node))
+	require.NoError(t, docs.SanitiseYAML(docs.TypeInput, &node, docs.SanitiseConfig{
+		DocumentFields: true,
+	}))
+
+	resBytes, err := yaml.Marshal(node.Content[0])
+	require.NoError(t, err)
+
+	assert.Equal(t, `# An optional label to use as an identifier for observability data such as metrics and logging.
+label: foo
+# Reads things.
+testyamlsanitdocumentinput:
+    # The URL to read from.
+    # Default: "http://localhost"
+    url: http://example.com
+    # The mode to read with.
+    # Options: fast, slow.
+    # Default: "fast"
+    mode: slow
+    # DEPRECATED: Do not use.
+    # Default: ""
+    old: ""
+    # Tags to add.
+    tags: [a]
+`, string(resBytes))
+}
@@ -0,0 +1,38 @@
+package aws
+
+import (
+	"context"
+
+	"github.com/Jeffail/benthos/v3/internal/bloblang/query"
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/secretsmanager"
+	"github.com/aws/aws-sdk-go/service/secretsmanager/secretsmanageriface"
+)
+
+func init() {
+	if err := query.RegisterSecretBackend("aws_secrets_manager", func(ctx context.Context, path string) (string, error) {
+		sess, err := session.NewSessionWithOptions(session.Options{
+			SharedConfigState: session.SharedConfigEnable,
+		})
+		if err != nil {
+			return "", err
+		}
+		return getSecretValue(ctx, secretsmanager.New(sess), path)
+	}); err != nil {
+		panic(err)
+	}
+}
+
+func getSecretValue(ctx context.Context, client secretsmanageriface.SecretsManagerAPI, id string) (string, error) {
+	out, err := client.GetSecretValueWithContext(ctx, &secretsmanager.GetSecretValueInput{
+		SecretId: aws.String(id),
+	})
+	if err != nil {
+		return "", err
+	}
+	if out.SecretString != nil {
+		return *out.SecretString, nil
+	}
+	return string(out.SecretBinary), nil
+}
@@ -0,0 +1,51 @@
+package aws
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/service/secretsmanager"
+	"github.com/aws/aws-sdk-go/service/secretsmanager/secretsmanageriface"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type mockSecretsManager struct {
+	secretsmanageriface.SecretsManagerAPI
+	secrets map[string]*secretsmanager.GetSecretValueOutput
+}
+
+func (m *mockSecretsManager) GetSecretValueWithContext(_ context.Context, input *secretsmanager.GetSecretValueInput, _ ...request.Option) (*secretsmanager.GetSecretValueOutput, error) {
+	out, exists := m.secrets[*input.SecretId]
+	if !exists {
+		return nil, errors.New("secret not found")
+	}
+	return out, nil
+}
+
+func TestGetSecretValue(t *testing.T) {
+	client := &mockSecretsManager{
+		secrets: map[string]*secretsmanager.GetSecretValueOutput{
+			"prod/db": {
+				SecretString: aws.String(`{"password":"hunter2"}`),
+			},
+			"prod/cert": {
+				SecretBinary: []byte("binary data"),
+			},
+		},
+	}
+
+	v, err := getSecretValue(context.Background(), client, "prod/db")
+	require.NoError(t, err)
+	assert.Equal(t, `{"password":"hunter2"}`, v)
+
+	v, err = getSecretValue(context.Background(), client, "prod/cert")
+	require.NoError(t, err)
+	assert.Equal(t, "binary data", v)
+
+	_, err = getSecretValue(context.Background(), client, "prod/nope")
+	require.EqualError(t, err, "secret not found")
+}
@@ -7,6 +7,7 @@ import (
 	"net/http"
 	"strings"
 	"sync"
+	"time"
 
 	"cloud.google.com/go/bigquery"
 	"github.com/Jeffail/benthos/v3/internal/component/output"
@@ -47,6 +48,31 @@ func gcpBigQueryCSVConfigFromParsed(conf *service.ParsedConfig) (csvconf gcpBigQ
 	return
 }
 
+type gcpBigQueryPartitioningConfig struct {
+	Type       string
+	Field      string
+	Expiration time.Duration
+}
+
+func gcpBigQueryPartitioningConfigFromParsed(conf *service.ParsedConfig) (pconf gcpBigQueryPartitioningConfig, err error) {
+	if pconf.Type, err = conf.FieldString("type"); err != nil {
+		return
+	}
+	if pconf.Field, err = conf.FieldString("field"); err != nil {
+		return
+	}
+	var expiration string
+	if expiration, err = conf.FieldString("expiration"); err != nil {
+		return
+	}
+	if expiration != "" {
+		if pconf.Expiration, err = time.ParseDuration(expiration); err != nil {
+			err = fmt.Errorf("failed to parse time_partitioning.expiration: %w", err)
+		}
+	}
+	return
+}
+
 type gcpBigQueryOutputConfig struct {
 	ProjectID           string
 	DatasetID           string
@@ -57,6 +83,10 @@ type gcpBigQueryOutputConfig struct {
 	AutoDetect          bool
 	IgnoreUnknownValues bool
 	MaxBadRecords       int
+	ClusteringFields    []string
+
+	// Table creation options
+	Partitioning gcpBigQueryPartitioningConfig
 
 	// CSV options
 	CSVOptions gcpBigQueryCSVConfig
@@ -90,6 +120,12 @@ func gcpBigQueryOutputConfigFromParsed(conf *service.ParsedConfig) (gconf gcpBig
 	if gconf.AutoDetect, err = conf.FieldBool("auto_detect"); err != nil {
 		return
 	}
+	if gconf.ClusteringFields, err = conf.FieldStringList("clustering_fields"); err != nil {
+		return
+	}
+	if gconf.Partitioning, err = gcpBigQueryPartitioningConfigFromParsed(conf.Namespace("time_partitioning")); err != nil {
+		return
+	}
 	if gconf.CSVOptions, err = gcpBigQueryCSVConfigFromParsed(conf.Namespace("csv")); err != nil {
 		return
 	}
@@ -145,7 +181,30 @@ The same is true for the CSV format.
 
 ### CSV
 
-For the CSV format when the field `+"`csv.header`"+` is specified a header row will be inserted as the first line of each message batch. If this field is not provided then the first message of each message batch must include a header line.`)).
+For the CSV format when the field `+"`csv.header`"+` is specified a header row will be inserted as the first line of each message batch. If this field is not provided then the first message of each message batch must include a header line.
+
+## Partitioning and Clustering
+
+When the destination table is created by this output (with the create disposition `+"`CREATE_IF_NEEDED`"+`) the fields `+"`time_partitioning`"+` and `+"`clustering_fields`"+` determine how the new table is partitioned and clustered. When the table already exists these fields must match its existing configuration, otherwise the insertion fails.
+
+## Dead Lettering
+
+Each batch is inserted as a single load job, and therefore when any row of a batch is rejected (beyond the limit set with `+"`max_bad_records`"+`) the whole batch fails. Failed batches can be routed to a dead letter queue with a `+"[`try`](/docs/components/outputs/try)"+` output:
+
+`+"```yaml"+`
+output:
+  try:
+    - gcp_bigquery:
+        project: foo
+        dataset: bar
+        table: baz
+        batching:
+          count: 100
+          period: 10s
+    - gcp_cloud_storage:
+        bucket: bigquery-dead-letters
+        path: ${! timestamp_unix_nano() }.json
+`+"```"+``)).
 		Field(service.NewStringField("project").Description("The project ID of the dataset to insert data to.")).
 		Field(service.NewStringField("dataset").Description("The BigQuery Dataset ID.")).
 		Field(service.NewStringField("table").Description("The table to insert messages to.")).
@@ -176,6 +235,22 @@ For the CSV format when the field `+"`csv.header`"+` is specified a header row w
 			Description("Indicates if we should automatically infer the options and schema for CSV and JSON sources. If the table doesn't exist and this field is set to `false` the output may not be able to insert data and will throw insertion error. Be careful using this field since it delegates to the GCP BigQuery service the schema detection and values like `\"no\"` may be treated as booleans for the CSV format.").
 			Advanced().
 			Default(false)).
+		Field(service.NewStringListField("clustering_fields").
+			Description("An optional list of fields to cluster a newly created table by, in order of priority.").
+			Advanced().
+			Default([]interface{}{})).
+		Field(service.NewObjectField("time_partitioning",
+			service.NewStringEnumField("type", "", string(bigquery.DayPartitioningType), string(bigquery.HourPartitioningType)).
+				Description("The interval of each partition of a newly created table. When empty the table is not partitioned by time.").
+				Default(""),
+			service.NewStringField("field").
+				Description("An optional top level `TIMESTAMP` or `DATE` field to partition the table by. When empty the table is partitioned by the time of ingestion.").
+				Default(""),
+			service.NewStringField("expiration").
+				Description("An optional duration after which partitions are deleted. When empty partitions do not expire.").
+				Example("720h").
+				Default(""),
+		).Description("Specify how a newly created table should be partitioned by time.").Advanced()).
 		Field(service.NewObjectField("csv",
 			service.NewStringListField("header").
 				Description("A list of values to use as header for each batch of messages. If not specified the first line of each message will be used as header.").
@@ -401,6 +476,19 @@ func (g *gcpBigQueryOutput) createTableLoader(data *[]byte) *bigquery.Loader {
 	loader.CreateDisposition = bigquery.TableCreateDisposition(g.conf.CreateDisposition)
 	loader.WriteDisposition = bigquery.TableWriteDisposition(g.conf.WriteDisposition)
 
+	if g.conf.Partitioning.Type != "" {
+		loader.TimePartitioning = &bigquery.TimePartitioning{
+			Type:       bigquery.TimePartitioningType(g.conf.Partitioning.Type),
+			Field:      g.conf.Partitioning.Field,
+			Expiration: g.conf.Partitioning.Expiration,
+		}
+	}
+	if len(g.conf.ClusteringFields) > 0 {
+		loader.Clustering = &bigquery.Clustering{
+			Fields: g.conf.ClusteringFields,
+		}
+	}
+
 	return loader
 }
 
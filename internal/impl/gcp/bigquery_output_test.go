@@ -7,6 +7,7 @@ import (
 	"net/http/httptest"
 	"strings"
 	"testing"
+	"time"
 
 	"cloud.google.com/go/bigquery"
 	"github.com/Jeffail/benthos/v3/public/service"
@@ -502,3 +503,63 @@ table: table_meow
 	})
 	require.Error(t, err)
 }
+
+func TestGCPBigQueryOutputCreateTableLoaderPartitioning(t *testing.T) {
+	outputConfig := gcpBigQueryConfFromYAML(t, `
+project: project_meow
+dataset: dataset_meow
+table: table_meow
+clustering_fields: [ country, city ]
+time_partitioning:
+  type: HOUR
+  field: created_at
+  expiration: 720h
+`)
+
+	output, err := newGCPBigQueryOutput(outputConfig, nil)
+	require.NoError(t, err)
+
+	var data = []byte(`{"created_at":"2021-09-01T00:00:00Z"}`)
+	loader := output.createTableLoader(&data)
+
+	require.NotNil(t, loader.TimePartitioning)
+	assert.Equal(t, bigquery.HourPartitioningType, loader.TimePartitioning.Type)
+	assert.Equal(t, "created_at", loader.TimePartitioning.Field)
+	assert.Equal(t, time.Hour*720, loader.TimePartitioning.Expiration)
+
+	require.NotNil(t, loader.Clustering)
+	assert.Equal(t, []string{"country", "city"}, loader.Clustering.Fields)
+}
+
+func TestGCPBigQueryOutputCreateTableLoaderNoPartitioning(t *testing.T) {
+	outputConfig := gcpBigQueryConfFromYAML(t, `
+project: project_meow
+dataset: dataset_meow
+table: table_meow
+`)
+
+	output, err := newGCPBigQueryOutput(outputConfig, nil)
+	require.NoError(t, err)
+
+	var data = []byte(`{}`)
+	loader := output.createTableLoader(&data)
+
+	assert.Nil(t, loader.TimePartitioning)
+	assert.Nil(t, loader.Clustering)
+}
+
+func TestGCPBigQueryOutputBadPartitionExpiration(t *testing.T) {
+	parsedConf, err := gcpBigQueryConfig().ParseYAML(`
+project: foo
+dataset: bar
+table: baz
+time_partitioning:
+  type: DAY
+  expiration: nope
+`, nil)
+	require.NoError(t, err)
+
+	_, err = gcpBigQueryOutputConfigFromParsed(parsedConf)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "time_partitioning.expiration")
+}
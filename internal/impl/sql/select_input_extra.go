@@ -0,0 +1,10 @@
+//go:build !wasm
+// +build !wasm
+
+package sql
+
+// Import extra drivers that aren't supported by WASM builds.
+import (
+	// SQL Drivers
+	_ "github.com/lib/pq"
+)
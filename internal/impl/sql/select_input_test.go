@@ -0,0 +1,187 @@
+package sql
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/Jeffail/benthos/v3/lib/cache"
+	"github.com/Jeffail/benthos/v3/lib/input"
+	"github.com/Jeffail/benthos/v3/lib/log"
+	"github.com/Jeffail/benthos/v3/lib/manager"
+	"github.com/Jeffail/benthos/v3/lib/metrics"
+	"github.com/Jeffail/benthos/v3/lib/response"
+	"github.com/Jeffail/benthos/v3/lib/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	_ "github.com/Jeffail/benthos/v3/public/components/legacy"
+)
+
+func TestSQLSelectQueryBuilding(t *testing.T) {
+	tests := map[string]struct {
+		conf   func(c *input.SQLSelectConfig)
+		cursor sqlSelectCursor
+		query  string
+		args   []interface{}
+	}{
+		"keyset first page": {
+			conf:  func(c *input.SQLSelectConfig) {},
+			query: "SELECT * FROM foo ORDER BY id LIMIT 10",
+		},
+		"keyset with key": {
+			conf:   func(c *input.SQLSelectConfig) {},
+			cursor: sqlSelectCursor{Key: int64(5)},
+			query:  "SELECT * FROM foo WHERE id > ? ORDER BY id LIMIT 10",
+			args:   []interface{}{int64(5)},
+		},
+		"keyset postgres with where": {
+			conf: func(c *input.SQLSelectConfig) {
+				c.Driver = "postgres"
+				c.Where = "a = 'b' OR c > 2"
+			},
+			cursor: sqlSelectCursor{Key: "bar"},
+			query:  "SELECT * FROM foo WHERE (a = 'b' OR c > 2) AND id > $1 ORDER BY id LIMIT 10",
+			args:   []interface{}{"bar"},
+		},
+		"keyset mssql": {
+			conf: func(c *input.SQLSelectConfig) {
+				c.Driver = "mssql"
+			},
+			cursor: sqlSelectCursor{Key: int64(5)},
+			query:  "SELECT * FROM foo WHERE id > @p1 ORDER BY id OFFSET 0 ROWS FETCH NEXT 10 ROWS ONLY",
+			args:   []interface{}{int64(5)},
+		},
+		"columns without key": {
+			conf: func(c *input.SQLSelectConfig) {
+				c.Columns = []string{"name", "age"}
+			},
+			query: "SELECT name, age, id FROM foo ORDER BY id LIMIT 10",
+		},
+		"columns with key": {
+			conf: func(c *input.SQLSelectConfig) {
+				c.Columns = []string{"id", "name"}
+			},
+			query: "SELECT id, name FROM foo ORDER BY id LIMIT 10",
+		},
+		"offset first page": {
+			conf: func(c *input.SQLSelectConfig) {
+				c.Pagination = "offset"
+			},
+			query: "SELECT * FROM foo ORDER BY id LIMIT 10",
+		},
+		"offset with where": {
+			conf: func(c *input.SQLSelectConfig) {
+				c.Pagination = "offset"
+				c.Where = "a = 'b'"
+			},
+			cursor: sqlSelectCursor{Offset: 20},
+			query:  "SELECT * FROM foo WHERE (a = 'b') ORDER BY id LIMIT 10 OFFSET 20",
+		},
+		"offset mssql": {
+			conf: func(c *input.SQLSelectConfig) {
+				c.Driver = "mssql"
+				c.Pagination = "offset"
+			},
+			cursor: sqlSelectCursor{Offset: 20},
+			query:  "SELECT * FROM foo ORDER BY id OFFSET 20 ROWS FETCH NEXT 10 ROWS ONLY",
+		},
+	}
+
+	for name, test := range tests {
+		test := test
+		t.Run(name, func(t *testing.T) {
+			conf := input.NewSQLSelectConfig()
+			conf.Table = "foo"
+			conf.KeyColumn = "id"
+			conf.PageSize = 10
+			test.conf(&conf)
+
+			query, args := buildSQLSelectQuery(conf, test.cursor)
+			assert.Equal(t, test.query, query)
+			assert.Equal(t, test.args, args)
+		})
+	}
+}
+
+func TestSQLSelectCursorDecoding(t *testing.T) {
+	tests := map[string]struct {
+		input  string
+		cursor sqlSelectCursor
+	}{
+		"integer key": {
+			input:  `{"key":9007199254740993}`,
+			cursor: sqlSelectCursor{Key: int64(9007199254740993)},
+		},
+		"float key": {
+			input:  `{"key":5.5}`,
+			cursor: sqlSelectCursor{Key: 5.5},
+		},
+		"string key": {
+			input:  `{"key":"2021-09-01T00:00:00Z"}`,
+			cursor: sqlSelectCursor{Key: "2021-09-01T00:00:00Z"},
+		},
+		"offset": {
+			input:  `{"offset":20}`,
+			cursor: sqlSelectCursor{Offset: 20},
+		},
+	}
+
+	for name, test := range tests {
+		test := test
+		t.Run(name, func(t *testing.T) {
+			cursor, err := decodeSQLSelectCursor([]byte(test.input))
+			require.NoError(t, err)
+			assert.Equal(t, test.cursor, cursor)
+		})
+	}
+}
+
+func TestSQLSelectNackedPageCursor(t *testing.T) {
+	mgrConf := manager.NewConfig()
+	mgrConf.Caches["foo"] = cache.NewConfig()
+
+	mgr, err := manager.New(mgrConf, nil, log.Noop(), metrics.Noop())
+	require.NoError(t, err)
+
+	conf := input.NewSQLSelectConfig()
+	conf.Driver = "mysql"
+	conf.Table = "footable"
+	conf.KeyColumn = "id"
+	conf.CheckpointCache = "foo"
+
+	s, err := newSQLSelectReader(conf, mgr)
+	require.NoError(t, err)
+
+	storedCursor := func() string {
+		var cBytes []byte
+		var cErr error
+		require.NoError(t, mgr.AccessCache(context.Background(), "foo", func(c types.Cache) {
+			cBytes, cErr = c.Get("footable")
+		}))
+		if cErr == types.ErrKeyNotFound {
+			return ""
+		}
+		require.NoError(t, cErr)
+		return string(cBytes)
+	}
+
+	ctx := context.Background()
+
+	resolveFirst, err := s.cp.Track(ctx, sqlSelectCursor{Key: int64(10)}, 1)
+	require.NoError(t, err)
+	ackFirst := s.pageAckFn(resolveFirst)
+
+	resolveSecond, err := s.cp.Track(ctx, sqlSelectCursor{Key: int64(20)}, 1)
+	require.NoError(t, err)
+	ackSecond := s.pageAckFn(resolveSecond)
+
+	require.NoError(t, ackFirst(ctx, response.NewError(errors.New("nope"))))
+	assert.Equal(t, "", storedCursor())
+
+	require.NoError(t, ackSecond(ctx, response.NewAck()))
+	assert.Equal(t, "", storedCursor())
+
+	require.NoError(t, ackFirst(ctx, response.NewAck()))
+	assert.Equal(t, `{"key":20}`, storedCursor())
+}
@@ -0,0 +1,85 @@
+// Package vault contains component implementations for HashiCorp Vault.
+package vault
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"strings"
+
+	"github.com/Jeffail/benthos/v3/internal/bloblang/query"
+)
+
+func init() {
+	if err := query.RegisterSecretBackend("vault", func(ctx context.Context, path string) (string, error) {
+		return readSecret(ctx, http.DefaultClient, os.Getenv("VAULT_ADDR"), os.Getenv("VAULT_TOKEN"), os.Getenv("VAULT_NAMESPACE"), path)
+	}); err != nil {
+		panic(err)
+	}
+}
+
+// readSecret reads a secret from the Vault HTTP API and returns its data as a
+// JSON object. Secrets from both versions of the KV secrets engine are
+// supported, where the data of version 2 secrets is unwrapped.
+func readSecret(ctx context.Context, client *http.Client, addr, token, namespace, path string) (string, error) {
+	if addr == "" {
+		return "", errors.New("the environment variable VAULT_ADDR must be set")
+	}
+	if token == "" {
+		return "", errors.New("the environment variable VAULT_TOKEN must be set")
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, strings.TrimSuffix(addr, "/")+"/v1/"+strings.TrimPrefix(path, "/"), nil)
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("X-Vault-Token", token)
+	if namespace != "" {
+		req.Header.Set("X-Vault-Namespace", namespace)
+	}
+
+	res, err := client.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer res.Body.Close()
+
+	body, err := ioutil.ReadAll(res.Body)
+	if err != nil {
+		return "", err
+	}
+
+	if res.StatusCode != http.StatusOK {
+		var errRes struct {
+			Errors []string `json:"errors"`
+		}
+		if err := json.Unmarshal(body, &errRes); err == nil && len(errRes.Errors) > 0 {
+			return "", fmt.Errorf("request to %v returned status %v: %v", path, res.StatusCode, strings.Join(errRes.Errors, ", "))
+		}
+		return "", fmt.Errorf("request to %v returned status %v", path, res.StatusCode)
+	}
+
+	var secretRes struct {
+		Data map[string]json.RawMessage `json:"data"`
+	}
+	if err := json.Unmarshal(body, &secretRes); err != nil {
+		return "", fmt.Errorf("failed to parse response: %w", err)
+	}
+	if secretRes.Data == nil {
+		return "", fmt.Errorf("secret %v contains no data", path)
+	}
+
+	data, hasData := secretRes.Data["data"]
+	if _, hasMeta := secretRes.Data["metadata"]; hasData && hasMeta {
+		return string(data), nil
+	}
+	b, err := json.Marshal(secretRes.Data)
+	if err != nil {
+		return "", err
+	}
+	return string(b), nil
+}
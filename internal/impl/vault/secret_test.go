@@ -0,0 +1,51 @@
+package vault
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReadSecret(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-Vault-Token") != "footoken" {
+			w.WriteHeader(http.StatusForbidden)
+			_, _ = w.Write([]byte(`{"errors":["permission denied"]}`))
+			return
+		}
+		assert.Equal(t, "foons", r.Header.Get("X-Vault-Namespace"))
+		switch r.URL.Path {
+		case "/v1/secret/data/db":
+			_, _ = w.Write([]byte(`{"data":{"data":{"password":"hunter2"},"metadata":{"version":3}}}`))
+		case "/v1/kv/db":
+			_, _ = w.Write([]byte(`{"data":{"password":"hunter3"}}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte(`{"errors":[]}`))
+		}
+	}))
+	t.Cleanup(ts.Close)
+
+	ctx := context.Background()
+
+	v, err := readSecret(ctx, ts.Client(), ts.URL+"/", "footoken", "foons", "secret/data/db")
+	require.NoError(t, err)
+	assert.Equal(t, `{"password":"hunter2"}`, v)
+
+	v, err = readSecret(ctx, ts.Client(), ts.URL, "footoken", "foons", "/kv/db")
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"password":"hunter3"}`, v)
+
+	_, err = readSecret(ctx, ts.Client(), ts.URL, "footoken", "foons", "nope")
+	require.EqualError(t, err, "request to nope returned status 404")
+
+	_, err = readSecret(ctx, ts.Client(), ts.URL, "badtoken", "foons", "secret/data/db")
+	require.EqualError(t, err, "request to secret/data/db returned status 403: permission denied")
+
+	_, err = readSecret(ctx, ts.Client(), "", "footoken", "", "secret/data/db")
+	require.Error(t, err)
+}
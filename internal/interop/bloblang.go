@@ -0,0 +1,17 @@
+package interop
+
+import (
+	"github.com/Jeffail/benthos/v3/internal/bloblang"
+	"github.com/Jeffail/benthos/v3/lib/types"
+)
+
+// BloblangEnvironment returns the Bloblang environment of a manager when it
+// provides one, otherwise the global environment is returned.
+func BloblangEnvironment(mgr types.Manager) *bloblang.Environment {
+	if bm, ok := mgr.(interface {
+		BloblEnvironment() *bloblang.Environment
+	}); ok {
+		return bm.BloblEnvironment()
+	}
+	return bloblang.GlobalEnvironment()
+}
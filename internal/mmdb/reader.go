@@ -0,0 +1,404 @@
+// Package mmdb implements a minimal reader of the MaxMind DB file format, as
+// used by GeoIP2 and GeoLite2 databases, see
+// https://maxmind.github.io/MaxMind-DB/ for the specification.
+package mmdb
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"math"
+	"math/big"
+	"net"
+)
+
+var metadataStartMarker = []byte("\xAB\xCD\xEFMaxMind.com")
+
+// The size of the zeroed section between the search tree and the data section.
+const dataSectionSeparatorSize = 16
+
+// Metadata describes the layout and contents of a database.
+type Metadata struct {
+	NodeCount    uint
+	RecordSize   uint
+	IPVersion    uint
+	DatabaseType string
+	BuildEpoch   uint64
+}
+
+// Reader provides lookups of IP addresses within a database.
+type Reader struct {
+	buffer    []byte
+	decoder   decoder
+	Metadata  Metadata
+	ipv4Start uint
+}
+
+// Open reads a database file into memory.
+func Open(path string) (*Reader, error) {
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return FromBytes(b)
+}
+
+// FromBytes creates a reader from the contents of a database.
+func FromBytes(b []byte) (*Reader, error) {
+	metaStart := bytes.LastIndex(b, metadataStartMarker)
+	if metaStart == -1 {
+		return nil, errors.New("invalid database: metadata section not found")
+	}
+	metaStart += len(metadataStartMarker)
+
+	metaDec := decoder{buffer: b[metaStart:]}
+	rawMeta, _, err := metaDec.decode(0, 0)
+	if err != nil {
+		return nil, fmt.Errorf("invalid database metadata: %w", err)
+	}
+	metaMap, ok := rawMeta.(map[string]interface{})
+	if !ok {
+		return nil, errors.New("invalid database metadata: expected map")
+	}
+
+	var meta Metadata
+	var metaErr error
+	getUint := func(k string) uint64 {
+		switch t := metaMap[k].(type) {
+		case uint64:
+			return t
+		case int64:
+			if t >= 0 {
+				return uint64(t)
+			}
+		}
+		if metaErr == nil {
+			metaErr = fmt.Errorf("invalid database metadata: missing or invalid field %v", k)
+		}
+		return 0
+	}
+	meta.NodeCount = uint(getUint("node_count"))
+	meta.RecordSize = uint(getUint("record_size"))
+	meta.IPVersion = uint(getUint("ip_version"))
+	if metaErr != nil {
+		return nil, metaErr
+	}
+	if _, exists := metaMap["build_epoch"]; exists {
+		meta.BuildEpoch = getUint("build_epoch")
+	}
+	meta.DatabaseType, _ = metaMap["database_type"].(string)
+
+	switch meta.RecordSize {
+	case 24, 28, 32:
+	default:
+		return nil, fmt.Errorf("invalid database: unsupported record size %v", meta.RecordSize)
+	}
+	if meta.IPVersion != 4 && meta.IPVersion != 6 {
+		return nil, fmt.Errorf("invalid database: unsupported IP version %v", meta.IPVersion)
+	}
+
+	treeSize := meta.NodeCount * meta.RecordSize / 4
+	dataStart := treeSize + dataSectionSeparatorSize
+	dataEnd := uint(metaStart - len(metadataStartMarker))
+	if dataStart > dataEnd {
+		return nil, errors.New("invalid database: search tree exceeds file size")
+	}
+
+	r := &Reader{
+		buffer:   b,
+		decoder:  decoder{buffer: b[dataStart:dataEnd]},
+		Metadata: meta,
+	}
+
+	// IPv4 addresses within an IPv6 tree are found within the ::/96 subnet.
+	if meta.IPVersion == 6 {
+		for i := 0; i < 96 && r.ipv4Start < meta.NodeCount; i++ {
+			if r.ipv4Start, err = r.readNode(r.ipv4Start, 0); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return r, nil
+}
+
+// Lookup attempts to find the record of an IP address, and returns the record
+// and true if a record was found.
+func (r *Reader) Lookup(ip net.IP) (interface{}, bool, error) {
+	if ip4 := ip.To4(); ip4 != nil {
+		ip = ip4
+	} else if r.Metadata.IPVersion == 4 {
+		return nil, false, fmt.Errorf("cannot look up IPv6 address %v in an IPv4 only database", ip)
+	}
+
+	var node uint
+	if len(ip) == net.IPv4len {
+		node = r.ipv4Start
+	}
+
+	var err error
+	nodeCount := r.Metadata.NodeCount
+	bitCount := uint(len(ip) * 8)
+	for i := uint(0); i < bitCount && node < nodeCount; i++ {
+		bit := uint(1) & (uint(ip[i>>3]) >> (7 - (i % 8)))
+		if node, err = r.readNode(node, bit); err != nil {
+			return nil, false, err
+		}
+	}
+
+	if node == nodeCount {
+		return nil, false, nil
+	}
+	if node < nodeCount {
+		return nil, false, errors.New("invalid database: search tree is deeper than the address")
+	}
+
+	offset := node - nodeCount - dataSectionSeparatorSize
+	record, _, err := r.decoder.decode(offset, 0)
+	if err != nil {
+		return nil, false, fmt.Errorf("invalid database record: %w", err)
+	}
+	return record, true, nil
+}
+
+func (r *Reader) readNode(node, bit uint) (uint, error) {
+	recordSize := r.Metadata.RecordSize
+	offset := node * recordSize / 4
+	if offset+recordSize/4 > uint(len(r.buffer)) {
+		return 0, errors.New("invalid database: node exceeds file size")
+	}
+	b := r.buffer[offset:]
+
+	switch recordSize {
+	case 24:
+		b = b[bit*3:]
+		return uint(b[0])<<16 | uint(b[1])<<8 | uint(b[2]), nil
+	case 28:
+		if bit == 0 {
+			return (uint(b[3])&0xF0)<<20 | uint(b[0])<<16 | uint(b[1])<<8 | uint(b[2]), nil
+		}
+		return (uint(b[3])&0x0F)<<24 | uint(b[4])<<16 | uint(b[5])<<8 | uint(b[6]), nil
+	}
+	return uint(binary.BigEndian.Uint32(b[bit*4:])), nil
+}
+
+//------------------------------------------------------------------------------
+
+const (
+	typeExtended  = 0
+	typePointer   = 1
+	typeString    = 2
+	typeDouble    = 3
+	typeBytes     = 4
+	typeUint16    = 5
+	typeUint32    = 6
+	typeMap       = 7
+	typeInt32     = 8
+	typeUint64    = 9
+	typeUint128   = 10
+	typeArray     = 11
+	typeContainer = 12
+	typeEndMarker = 13
+	typeBool      = 14
+	typeFloat     = 15
+)
+
+// The maximum depth of nested maps and arrays, which protects against stack
+// exhaustion from malicious databases.
+const maxDecodeDepth = 64
+
+type decoder struct {
+	buffer []byte
+}
+
+func (d *decoder) byteAt(offset uint) (byte, error) {
+	if offset >= uint(len(d.buffer)) {
+		return 0, errors.New("unexpected end of data")
+	}
+	return d.buffer[offset], nil
+}
+
+func (d *decoder) slice(offset, size uint) ([]byte, error) {
+	if offset+size > uint(len(d.buffer)) || offset+size < offset {
+		return nil, errors.New("unexpected end of data")
+	}
+	return d.buffer[offset : offset+size], nil
+}
+
+// decode a value at an offset and returns the value along with the offset of
+// the next value.
+func (d *decoder) decode(offset uint, depth int) (interface{}, uint, error) {
+	if depth > maxDecodeDepth {
+		return nil, 0, errors.New("maximum data structure depth exceeded")
+	}
+
+	ctrl, err := d.byteAt(offset)
+	if err != nil {
+		return nil, 0, err
+	}
+	offset++
+
+	dataType := uint(ctrl >> 5)
+	if dataType == typePointer {
+		pointer, next, err := d.decodePointer(ctrl, offset)
+		if err != nil {
+			return nil, 0, err
+		}
+		if t, err := d.byteAt(pointer); err == nil && t>>5 == typePointer {
+			return nil, 0, errors.New("pointer to a pointer is not permitted")
+		}
+		v, _, err := d.decode(pointer, depth+1)
+		return v, next, err
+	}
+
+	if dataType == typeExtended {
+		ext, err := d.byteAt(offset)
+		if err != nil {
+			return nil, 0, err
+		}
+		offset++
+		dataType = 7 + uint(ext)
+	}
+
+	size := uint(ctrl & 0x1f)
+	if size >= 29 {
+		extra := size - 28
+		b, err := d.slice(offset, extra)
+		if err != nil {
+			return nil, 0, err
+		}
+		offset += extra
+		n := uint(0)
+		for _, c := range b {
+			n = n<<8 | uint(c)
+		}
+		switch size {
+		case 29:
+			size = 29 + n
+		case 30:
+			size = 285 + n
+		default:
+			size = 65821 + n
+		}
+	}
+
+	switch dataType {
+	case typeMap:
+		m := make(map[string]interface{}, minUint(size, 64))
+		for i := uint(0); i < size; i++ {
+			var k, v interface{}
+			if k, offset, err = d.decode(offset, depth+1); err != nil {
+				return nil, 0, err
+			}
+			key, ok := k.(string)
+			if !ok {
+				return nil, 0, fmt.Errorf("expected string map key, got %T", k)
+			}
+			if v, offset, err = d.decode(offset, depth+1); err != nil {
+				return nil, 0, err
+			}
+			m[key] = v
+		}
+		return m, offset, nil
+	case typeArray:
+		a := make([]interface{}, 0, minUint(size, 64))
+		for i := uint(0); i < size; i++ {
+			var v interface{}
+			if v, offset, err = d.decode(offset, depth+1); err != nil {
+				return nil, 0, err
+			}
+			a = append(a, v)
+		}
+		return a, offset, nil
+	case typeBool:
+		if size > 1 {
+			return nil, 0, fmt.Errorf("invalid boolean size %v", size)
+		}
+		return size == 1, offset, nil
+	case typeContainer, typeEndMarker:
+		return nil, 0, fmt.Errorf("unexpected data type %v", dataType)
+	}
+
+	b, err := d.slice(offset, size)
+	if err != nil {
+		return nil, 0, err
+	}
+	offset += size
+
+	switch dataType {
+	case typeString:
+		return string(b), offset, nil
+	case typeBytes:
+		return append([]byte(nil), b...), offset, nil
+	case typeDouble:
+		if size != 8 {
+			return nil, 0, fmt.Errorf("invalid double size %v", size)
+		}
+		return math.Float64frombits(binary.BigEndian.Uint64(b)), offset, nil
+	case typeFloat:
+		if size != 4 {
+			return nil, 0, fmt.Errorf("invalid float size %v", size)
+		}
+		return float64(math.Float32frombits(binary.BigEndian.Uint32(b))), offset, nil
+	case typeUint16, typeUint32, typeInt32, typeUint64:
+		maxSize := uint(4)
+		switch dataType {
+		case typeUint16:
+			maxSize = 2
+		case typeUint64:
+			maxSize = 8
+		}
+		if size > maxSize {
+			return nil, 0, fmt.Errorf("invalid integer size %v", size)
+		}
+		var n uint64
+		for _, c := range b {
+			n = n<<8 | uint64(c)
+		}
+		switch dataType {
+		case typeInt32:
+			return int64(int32(uint32(n))), offset, nil
+		case typeUint64:
+			return n, offset, nil
+		}
+		return int64(n), offset, nil
+	case typeUint128:
+		if size > 16 {
+			return nil, 0, fmt.Errorf("invalid integer size %v", size)
+		}
+		return new(big.Int).SetBytes(b), offset, nil
+	}
+	return nil, 0, fmt.Errorf("unknown data type %v", dataType)
+}
+
+func (d *decoder) decodePointer(ctrl byte, offset uint) (pointer, next uint, err error) {
+	size := uint((ctrl>>3)&0x3) + 1
+	b, err := d.slice(offset, size)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	var prefix uint
+	if size != 4 {
+		prefix = uint(ctrl & 0x7)
+	}
+	for _, c := range b {
+		prefix = prefix<<8 | uint(c)
+	}
+
+	switch size {
+	case 2:
+		prefix += 2048
+	case 3:
+		prefix += 526336
+	}
+	return prefix, offset + size, nil
+}
+
+func minUint(a, b uint) uint {
+	if a < b {
+		return a
+	}
+	return b
+}
@@ -0,0 +1,356 @@
+package mmdb
+
+import (
+	"bytes"
+	"encoding/binary"
+	"math"
+	"math/big"
+	"net"
+	"sort"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type pointer uint
+
+type encoder struct {
+	buf bytes.Buffer
+}
+
+func (e *encoder) ctrl(dataType, size int) {
+	first := dataType
+	if dataType > 7 {
+		first = typeExtended
+	}
+	var sizeBytes []byte
+	switch {
+	case size < 29:
+	case size < 285:
+		sizeBytes = []byte{byte(size - 29)}
+		size = 29
+	case size < 65821:
+		sizeBytes = []byte{byte((size - 285) >> 8), byte(size - 285)}
+		size = 30
+	default:
+		n := size - 65821
+		sizeBytes = []byte{byte(n >> 16), byte(n >> 8), byte(n)}
+		size = 31
+	}
+	e.buf.WriteByte(byte(first<<5 | size))
+	if dataType > 7 {
+		e.buf.WriteByte(byte(dataType - 7))
+	}
+	e.buf.Write(sizeBytes)
+}
+
+func trimLeadingZeros(b []byte) []byte {
+	for len(b) > 0 && b[0] == 0 {
+		b = b[1:]
+	}
+	return b
+}
+
+func (e *encoder) encode(v interface{}) {
+	switch t := v.(type) {
+	case pointer:
+		e.buf.WriteByte(byte(typePointer<<5 | (t>>8)&0x7))
+		e.buf.WriteByte(byte(t))
+	case string:
+		e.ctrl(typeString, len(t))
+		e.buf.WriteString(t)
+	case []byte:
+		e.ctrl(typeBytes, len(t))
+		e.buf.Write(t)
+	case float64:
+		e.ctrl(typeDouble, 8)
+		_ = binary.Write(&e.buf, binary.BigEndian, math.Float64bits(t))
+	case float32:
+		e.ctrl(typeFloat, 4)
+		_ = binary.Write(&e.buf, binary.BigEndian, math.Float32bits(t))
+	case uint16:
+		b := trimLeadingZeros([]byte{byte(t >> 8), byte(t)})
+		e.ctrl(typeUint16, len(b))
+		e.buf.Write(b)
+	case uint32:
+		b := make([]byte, 4)
+		binary.BigEndian.PutUint32(b, t)
+		b = trimLeadingZeros(b)
+		e.ctrl(typeUint32, len(b))
+		e.buf.Write(b)
+	case int32:
+		b := make([]byte, 4)
+		binary.BigEndian.PutUint32(b, uint32(t))
+		e.ctrl(typeInt32, 4)
+		e.buf.Write(b)
+	case uint64:
+		b := make([]byte, 8)
+		binary.BigEndian.PutUint64(b, t)
+		b = trimLeadingZeros(b)
+		e.ctrl(typeUint64, len(b))
+		e.buf.Write(b)
+	case *big.Int:
+		b := t.Bytes()
+		e.ctrl(typeUint128, len(b))
+		e.buf.Write(b)
+	case bool:
+		size := 0
+		if t {
+			size = 1
+		}
+		e.ctrl(typeBool, size)
+	case []interface{}:
+		e.ctrl(typeArray, len(t))
+		for _, v := range t {
+			e.encode(v)
+		}
+	case map[string]interface{}:
+		keys := make([]string, 0, len(t))
+		for k := range t {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		e.ctrl(typeMap, len(t))
+		for _, k := range keys {
+			e.encode(k)
+			e.encode(t[k])
+		}
+	default:
+		panic(v)
+	}
+}
+
+type dataRef uint
+
+type treeNode struct {
+	children [2]interface{}
+	index    uint
+}
+
+type testDB struct {
+	ipVersion  uint
+	recordSize uint
+	root       *treeNode
+	data       encoder
+}
+
+func newTestDB(ipVersion, recordSize uint) *testDB {
+	return &testDB{ipVersion: ipVersion, recordSize: recordSize, root: &treeNode{}}
+}
+
+// addData appends a value to the data section and returns its offset.
+func (d *testDB) addData(v interface{}) uint {
+	offset := uint(d.data.buf.Len())
+	d.data.encode(v)
+	return offset
+}
+
+func (d *testDB) insert(t *testing.T, cidr string, offset uint) {
+	t.Helper()
+	_, network, err := net.ParseCIDR(cidr)
+	require.NoError(t, err)
+
+	ip := network.IP
+	ones, _ := network.Mask.Size()
+	if d.ipVersion == 6 && len(ip) == net.IPv4len {
+		ip, ones = ip.To16(), ones+96
+		ip[10], ip[11] = 0, 0
+	}
+
+	node := d.root
+	for i := 0; i < ones; i++ {
+		bit := (ip[i/8] >> (7 - uint(i%8))) & 1
+		if i == ones-1 {
+			node.children[bit] = dataRef(offset)
+			return
+		}
+		next, ok := node.children[bit].(*treeNode)
+		if !ok {
+			next = &treeNode{}
+			node.children[bit] = next
+		}
+		node = next
+	}
+}
+
+func (d *testDB) bytes() []byte {
+	var nodes []*treeNode
+	var index func(n *treeNode)
+	index = func(n *treeNode) {
+		n.index = uint(len(nodes))
+		nodes = append(nodes, n)
+		for _, c := range n.children {
+			if cn, ok := c.(*treeNode); ok {
+				index(cn)
+			}
+		}
+	}
+	index(d.root)
+
+	nodeCount := uint(len(nodes))
+	record := func(c interface{}) uint {
+		switch t := c.(type) {
+		case *treeNode:
+			return t.index
+		case dataRef:
+			return nodeCount + dataSectionSeparatorSize + uint(t)
+		}
+		return nodeCount
+	}
+
+	var out bytes.Buffer
+	for _, n := range nodes {
+		l, r := record(n.children[0]), record(n.children[1])
+		switch d.recordSize {
+		case 24:
+			out.Write([]byte{byte(l >> 16), byte(l >> 8), byte(l), byte(r >> 16), byte(r >> 8), byte(r)})
+		case 28:
+			out.Write([]byte{byte(l >> 16), byte(l >> 8), byte(l), byte((l>>24)<<4 | (r >> 24)), byte(r >> 16), byte(r >> 8), byte(r)})
+		case 32:
+			_ = binary.Write(&out, binary.BigEndian, uint32(l))
+			_ = binary.Write(&out, binary.BigEndian, uint32(r))
+		}
+	}
+	out.Write(make([]byte, dataSectionSeparatorSize))
+	out.Write(d.data.buf.Bytes())
+	out.Write(metadataStartMarker)
+
+	var meta encoder
+	meta.encode(map[string]interface{}{
+		"node_count":    uint32(nodeCount),
+		"record_size":   uint16(d.recordSize),
+		"ip_version":    uint16(d.ipVersion),
+		"database_type": "Test-City",
+		"build_epoch":   uint64(1600000000),
+	})
+	out.Write(meta.buf.Bytes())
+	return out.Bytes()
+}
+
+func TestReaderIPv4(t *testing.T) {
+	db := newTestDB(4, 24)
+	db.insert(t, "1.2.3.0/24", db.addData(map[string]interface{}{
+		"country": map[string]interface{}{"iso_code": "GB"},
+	}))
+	db.insert(t, "8.0.0.0/8", db.addData(map[string]interface{}{
+		"country": map[string]interface{}{"iso_code": "US"},
+	}))
+
+	r, err := FromBytes(db.bytes())
+	require.NoError(t, err)
+	assert.Equal(t, "Test-City", r.Metadata.DatabaseType)
+	assert.Equal(t, uint64(1600000000), r.Metadata.BuildEpoch)
+
+	rec, found, err := r.Lookup(net.ParseIP("1.2.3.4"))
+	require.NoError(t, err)
+	assert.True(t, found)
+	assert.Equal(t, map[string]interface{}{
+		"country": map[string]interface{}{"iso_code": "GB"},
+	}, rec)
+
+	rec, found, err = r.Lookup(net.ParseIP("8.8.8.8"))
+	require.NoError(t, err)
+	assert.True(t, found)
+	assert.Equal(t, map[string]interface{}{
+		"country": map[string]interface{}{"iso_code": "US"},
+	}, rec)
+
+	_, found, err = r.Lookup(net.ParseIP("1.2.4.1"))
+	require.NoError(t, err)
+	assert.False(t, found)
+
+	_, _, err = r.Lookup(net.ParseIP("2001:db8::1"))
+	require.Error(t, err)
+}
+
+func TestReaderIPv6(t *testing.T) {
+	for _, recordSize := range []uint{24, 28, 32} {
+		db := newTestDB(6, recordSize)
+		nameOffset := db.addData("shared name")
+		db.insert(t, "2001:db8::/32", db.addData(map[string]interface{}{
+			"name": pointer(nameOffset),
+			"v":    uint16(6),
+		}))
+		db.insert(t, "5.6.0.0/16", db.addData(map[string]interface{}{
+			"name": pointer(nameOffset),
+			"v":    uint16(4),
+		}))
+
+		r, err := FromBytes(db.bytes())
+		require.NoError(t, err, recordSize)
+
+		rec, found, err := r.Lookup(net.ParseIP("2001:db8:ffff::1"))
+		require.NoError(t, err, recordSize)
+		assert.True(t, found, recordSize)
+		assert.Equal(t, map[string]interface{}{"name": "shared name", "v": int64(6)}, rec, recordSize)
+
+		rec, found, err = r.Lookup(net.ParseIP("5.6.7.8"))
+		require.NoError(t, err, recordSize)
+		assert.True(t, found, recordSize)
+		assert.Equal(t, map[string]interface{}{"name": "shared name", "v": int64(4)}, rec, recordSize)
+
+		_, found, err = r.Lookup(net.ParseIP("2001:db9::1"))
+		require.NoError(t, err, recordSize)
+		assert.False(t, found, recordSize)
+	}
+}
+
+func TestReaderDataTypes(t *testing.T) {
+	longString := string(bytes.Repeat([]byte("a"), 300))
+	u128, _ := new(big.Int).SetString("340282366920938463463374607431768211455", 10)
+
+	db := newTestDB(4, 24)
+	db.insert(t, "10.0.0.0/8", db.addData(map[string]interface{}{
+		"string":  "hello",
+		"long":    longString,
+		"bytes":   []byte("raw"),
+		"double":  1.5,
+		"float":   float32(0.25),
+		"uint16":  uint16(300),
+		"uint32":  uint32(70000),
+		"int32":   int32(-12),
+		"uint64":  uint64(math.MaxUint64),
+		"uint128": u128,
+		"true":    true,
+		"false":   false,
+		"array":   []interface{}{"a", uint32(0)},
+		"empty":   map[string]interface{}{},
+	}))
+
+	r, err := FromBytes(db.bytes())
+	require.NoError(t, err)
+
+	rec, found, err := r.Lookup(net.ParseIP("10.1.2.3"))
+	require.NoError(t, err)
+	require.True(t, found)
+	assert.Equal(t, map[string]interface{}{
+		"string":  "hello",
+		"long":    longString,
+		"bytes":   []byte("raw"),
+		"double":  1.5,
+		"float":   0.25,
+		"uint16":  int64(300),
+		"uint32":  int64(70000),
+		"int32":   int64(-12),
+		"uint64":  uint64(math.MaxUint64),
+		"uint128": u128,
+		"true":    true,
+		"false":   false,
+		"array":   []interface{}{"a", int64(0)},
+		"empty":   map[string]interface{}{},
+	}, rec)
+}
+
+func TestReaderInvalid(t *testing.T) {
+	_, err := FromBytes([]byte("not a database"))
+	require.Error(t, err)
+
+	db := newTestDB(4, 24)
+	db.insert(t, "10.0.0.0/8", db.addData("foo"))
+	b := db.bytes()
+
+	// Truncate the search tree.
+	_, err = FromBytes(b[30:])
+	require.Error(t, err)
+}
@@ -210,8 +210,8 @@ func FieldConfigSpec() docs.FieldSpecs {
 	return docs.FieldSpecs{
 		docs.FieldString("name", "The name of the field."),
 		docs.FieldString("description", "A description of the field.").HasDefault(""),
-		docs.FieldString("type", "The scalar type of the field.").HasOptions(
-			"string", "int", "float", "bool",
+		docs.FieldString("type", "The type of the field. Fields of the component types `input`, `output` and `processor` accept the config of a component, which the mapping can embed within the resulting config, and fields of type `unknown` accept any value.").HasOptions(
+			"string", "int", "float", "bool", "unknown", "input", "output", "processor",
 		).LintOptions(),
 		docs.FieldString("kind", "The kind of the field.").HasOptions(
 			"scalar", "map", "list",
@@ -22,13 +22,46 @@ import (
 
 // Config contains the configuration fields for the Benthos API.
 type Config struct {
-	Address        string `json:"address" yaml:"address"`
-	Enabled        bool   `json:"enabled" yaml:"enabled"`
-	ReadTimeout    string `json:"read_timeout" yaml:"read_timeout"`
-	RootPath       string `json:"root_path" yaml:"root_path"`
-	DebugEndpoints bool   `json:"debug_endpoints" yaml:"debug_endpoints"`
-	CertFile       string `json:"cert_file" yaml:"cert_file"`
-	KeyFile        string `json:"key_file" yaml:"key_file"`
+	Address        string       `json:"address" yaml:"address"`
+	Enabled        bool         `json:"enabled" yaml:"enabled"`
+	ReadTimeout    string       `json:"read_timeout" yaml:"read_timeout"`
+	RootPath       string       `json:"root_path" yaml:"root_path"`
+	DebugEndpoints bool         `json:"debug_endpoints" yaml:"debug_endpoints"`
+	CertFile       string       `json:"cert_file" yaml:"cert_file"`
+	KeyFile        string       `json:"key_file" yaml:"key_file"`
+	Auth           AuthConfig   `json:"auth" yaml:"auth"`
+	Probes         ProbesConfig `json:"probes" yaml:"probes"`
+}
+
+// ProbesConfig contains configuration fields for the readiness and liveness
+// probe endpoints.
+type ProbesConfig struct {
+	ReadyGracePeriod string `json:"ready_grace_period" yaml:"ready_grace_period"`
+	LiveGracePeriod  string `json:"live_grace_period" yaml:"live_grace_period"`
+}
+
+// NewProbesConfig creates a new ProbesConfig with default values.
+func NewProbesConfig() ProbesConfig {
+	return ProbesConfig{
+		ReadyGracePeriod: "",
+		LiveGracePeriod:  "",
+	}
+}
+
+// GracePeriods parses the grace periods of the probes, where an empty string
+// results in a period of zero.
+func (p ProbesConfig) GracePeriods() (ready, live time.Duration, err error) {
+	if p.ReadyGracePeriod != "" {
+		if ready, err = time.ParseDuration(p.ReadyGracePeriod); err != nil {
+			return 0, 0, fmt.Errorf("failed to parse ready grace period: %w", err)
+		}
+	}
+	if p.LiveGracePeriod != "" {
+		if live, err = time.ParseDuration(p.LiveGracePeriod); err != nil {
+			return 0, 0, fmt.Errorf("failed to parse live grace period: %w", err)
+		}
+	}
+	return
 }
 
 // NewConfig creates a new API config with default values.
@@ -41,6 +74,8 @@ func NewConfig() Config {
 		DebugEndpoints: false,
 		CertFile:       "",
 		KeyFile:        "",
+		Auth:           NewAuthConfig(),
+		Probes:         NewProbesConfig(),
 	}
 }
 
@@ -63,6 +98,14 @@ func OptWithTLS(tls *tls.Config) OptFunc {
 	}
 }
 
+// OptWithVersionInfo adds fields to the JSON object returned by the /version
+// endpoint, which already contains the fields version and built.
+func OptWithVersionInfo(info map[string]interface{}) OptFunc {
+	return func(t *Type) {
+		t.versionInfo = info
+	}
+}
+
 //------------------------------------------------------------------------------
 
 // Type implements the Benthos HTTP API.
@@ -77,6 +120,10 @@ type Type struct {
 	handlers    map[string]http.HandlerFunc
 	handlersMut sync.RWMutex
 
+	auth *Authenticator
+
+	versionInfo map[string]interface{}
+
 	log    log.Modular
 	mux    *mux.Router
 	server *http.Server
@@ -121,6 +168,21 @@ func New(
 	}
 	t.ctx, t.cancel = context.WithCancel(context.Background())
 
+	if conf.Auth.Enabled {
+		var err error
+		if t.auth, err = NewAuthenticator(conf.Auth); err != nil {
+			return nil, fmt.Errorf("failed to create auth: %w", err)
+		}
+		if conf.Auth.ClientCAFile != "" {
+			if conf.CertFile == "" {
+				return nil, errors.New("auth client_ca_file requires TLS to be enabled with cert_file and key_file")
+			}
+			if server.TLSConfig, err = clientTLSConfig(conf.Auth.ClientCAFile); err != nil {
+				return nil, err
+			}
+		}
+	}
+
 	handlePing := func(w http.ResponseWriter, r *http.Request) {
 		w.Write([]byte("pong"))
 	}
@@ -160,7 +222,19 @@ func New(
 	}
 
 	handleVersion := func(w http.ResponseWriter, r *http.Request) {
-		w.Write([]byte(fmt.Sprintf("{\"version\":\"%v\", \"built\":\"%v\"}", version, dateBuilt)))
+		info := map[string]interface{}{}
+		for k, v := range t.versionInfo {
+			info[k] = v
+		}
+		info["version"] = version
+		info["built"] = dateBuilt
+
+		resBytes, err := json.Marshal(info)
+		if err != nil {
+			w.WriteHeader(http.StatusBadGateway)
+			return
+		}
+		w.Write(resBytes)
 	}
 
 	handleEndpoints := func(w http.ResponseWriter, r *http.Request) {
@@ -178,44 +252,56 @@ func New(
 	if t.conf.DebugEndpoints {
 		t.RegisterEndpoint(
 			"/debug/config/json", "DEBUG: Returns the loaded config as JSON.",
-			handlePrintJSONConfig,
+			t.auth.Wrap(handlePrintJSONConfig),
 		)
 		t.RegisterEndpoint(
 			"/debug/config/yaml", "DEBUG: Returns the loaded config as YAML.",
-			handlePrintYAMLConfig,
+			t.auth.Wrap(handlePrintYAMLConfig),
 		)
 		t.RegisterEndpoint(
 			"/debug/stack", "DEBUG: Returns a snapshot of the current service stack trace.",
-			handleStackTrace,
+			t.auth.Wrap(handleStackTrace),
 		)
 		t.RegisterEndpoint(
 			"/debug/pprof/profile", "DEBUG: Responds with a pprof-formatted cpu profile.",
-			pprof.Profile,
+			t.auth.Wrap(pprof.Profile),
 		)
 		t.RegisterEndpoint(
 			"/debug/pprof/heap", "DEBUG: Responds with a pprof-formatted heap profile.",
-			pprof.Index,
+			t.auth.Wrap(pprof.Index),
+		)
+		t.RegisterEndpoint(
+			"/debug/pprof/allocs", "DEBUG: Responds with a pprof-formatted profile of all past memory allocations.",
+			t.auth.Wrap(pprof.Index),
+		)
+		t.RegisterEndpoint(
+			"/debug/pprof/goroutine", "DEBUG: Responds with a pprof-formatted profile of all current goroutines.",
+			t.auth.Wrap(pprof.Index),
+		)
+		t.RegisterEndpoint(
+			"/debug/pprof/threadcreate", "DEBUG: Responds with a pprof-formatted profile of the stack traces that led to the creation of OS threads.",
+			t.auth.Wrap(pprof.Index),
 		)
 		t.RegisterEndpoint(
 			"/debug/pprof/block", "DEBUG: Responds with a pprof-formatted block profile.",
-			pprof.Index,
+			t.auth.Wrap(pprof.Index),
 		)
 		t.RegisterEndpoint(
 			"/debug/pprof/mutex", "DEBUG: Responds with a pprof-formatted mutex profile.",
-			pprof.Index,
+			t.auth.Wrap(pprof.Index),
 		)
 		t.RegisterEndpoint(
 			"/debug/pprof/symbol", "DEBUG: looks up the program counters listed"+
 				" in the request, responding with a table mapping program"+
 				" counters to function names.",
-			pprof.Symbol,
+			t.auth.Wrap(pprof.Symbol),
 		)
 		t.RegisterEndpoint(
 			"/debug/pprof/trace",
 			"DEBUG: Responds with the execution trace in binary form."+
 				" Tracing lasts for duration specified in seconds GET"+
 				" parameter, or for 1 second if not specified.",
-			pprof.Trace,
+			t.auth.Wrap(pprof.Trace),
 		)
 	}
 
@@ -242,6 +328,12 @@ func New(
 	return t, nil
 }
 
+// Auth returns an authenticator that should wrap the handlers of management
+// endpoints, or nil if authentication is disabled.
+func (t *Type) Auth() *Authenticator {
+	return t.auth
+}
+
 // RegisterEndpoint registers a http.HandlerFunc under a path with a
 // description that will be displayed under the /endpoints path.
 func (t *Type) RegisterEndpoint(path, desc string, handler http.HandlerFunc) {
@@ -276,12 +368,12 @@ func (t *Type) ListenAndServe() error {
 		"Listening for HTTP requests at: %v\n",
 		"http://"+t.conf.Address,
 	)
-	if t.server.TLSConfig != nil {
-		return t.server.ListenAndServeTLS("", "")
-	}
 	if len(t.conf.CertFile) > 0 {
 		return t.server.ListenAndServeTLS(t.conf.CertFile, t.conf.KeyFile)
 	}
+	if t.server.TLSConfig != nil {
+		return t.server.ListenAndServeTLS("", "")
+	}
 	return t.server.ListenAndServe()
 }
 
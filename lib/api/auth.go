@@ -0,0 +1,200 @@
+package api
+
+import (
+	"crypto/subtle"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+)
+
+//------------------------------------------------------------------------------
+
+// Scopes that can be granted to authenticated clients of the API.
+const (
+	// AuthScopeRead allows a client to make read only (GET) requests.
+	AuthScopeRead = "read"
+
+	// AuthScopeAdmin allows a client to make any request.
+	AuthScopeAdmin = "admin"
+)
+
+// AuthBasicUser contains credentials of a user authenticated with basic auth.
+type AuthBasicUser struct {
+	Username string `json:"username" yaml:"username"`
+	Password string `json:"password" yaml:"password"`
+	Scope    string `json:"scope" yaml:"scope"`
+}
+
+// AuthBearerToken contains a token authenticated as a bearer token.
+type AuthBearerToken struct {
+	Token string `json:"token" yaml:"token"`
+	Scope string `json:"scope" yaml:"scope"`
+}
+
+// AuthClientCert contains the common name of a client certificate.
+type AuthClientCert struct {
+	CommonName string `json:"common_name" yaml:"common_name"`
+	Scope      string `json:"scope" yaml:"scope"`
+}
+
+// AuthConfig contains configuration fields for authenticating and authorising
+// requests to the management endpoints of the API.
+type AuthConfig struct {
+	Enabled      bool              `json:"enabled" yaml:"enabled"`
+	BasicUsers   []AuthBasicUser   `json:"basic_users" yaml:"basic_users"`
+	BearerTokens []AuthBearerToken `json:"bearer_tokens" yaml:"bearer_tokens"`
+	ClientCAFile string            `json:"client_ca_file" yaml:"client_ca_file"`
+	ClientCerts  []AuthClientCert  `json:"client_certs" yaml:"client_certs"`
+}
+
+// NewAuthConfig creates a new AuthConfig with default values.
+func NewAuthConfig() AuthConfig {
+	return AuthConfig{
+		Enabled:      false,
+		BasicUsers:   []AuthBasicUser{},
+		BearerTokens: []AuthBearerToken{},
+		ClientCAFile: "",
+		ClientCerts:  []AuthClientCert{},
+	}
+}
+
+//------------------------------------------------------------------------------
+
+// Authenticator authenticates requests to the management endpoints of the API
+// and authorises them according to the scope of the client, where requests
+// that modify state require the admin scope.
+type Authenticator struct {
+	users       []AuthBasicUser
+	tokens      []AuthBearerToken
+	clientCerts map[string]string
+}
+
+// checkScope validates a scope, where an empty scope defaults to read.
+func checkScope(scope *string) error {
+	if *scope == "" {
+		*scope = AuthScopeRead
+	}
+	if *scope != AuthScopeRead && *scope != AuthScopeAdmin {
+		return fmt.Errorf("scope '%v' not recognised, expected %v or %v", *scope, AuthScopeRead, AuthScopeAdmin)
+	}
+	return nil
+}
+
+// NewAuthenticator creates an authenticator from a config.
+func NewAuthenticator(conf AuthConfig) (*Authenticator, error) {
+	a := &Authenticator{
+		clientCerts: map[string]string{},
+	}
+	for _, u := range conf.BasicUsers {
+		if u.Username == "" {
+			return nil, errors.New("basic auth users must have a username")
+		}
+		if err := checkScope(&u.Scope); err != nil {
+			return nil, fmt.Errorf("basic auth user '%v': %w", u.Username, err)
+		}
+		a.users = append(a.users, u)
+	}
+	for i, t := range conf.BearerTokens {
+		if t.Token == "" {
+			return nil, fmt.Errorf("bearer token %v must not be empty", i)
+		}
+		if err := checkScope(&t.Scope); err != nil {
+			return nil, fmt.Errorf("bearer token %v: %w", i, err)
+		}
+		a.tokens = append(a.tokens, t)
+	}
+	for _, c := range conf.ClientCerts {
+		if err := checkScope(&c.Scope); err != nil {
+			return nil, fmt.Errorf("client cert '%v': %w", c.CommonName, err)
+		}
+		a.clientCerts[c.CommonName] = c.Scope
+	}
+	if len(conf.ClientCerts) > 0 && conf.ClientCAFile == "" {
+		return nil, errors.New("client_certs requires a client_ca_file to be specified")
+	}
+	if len(a.users) == 0 && len(a.tokens) == 0 && len(a.clientCerts) == 0 {
+		return nil, errors.New("at least one of basic_users, bearer_tokens or client_certs must be specified")
+	}
+	return a, nil
+}
+
+func secureEqual(a, b string) bool {
+	return subtle.ConstantTimeCompare([]byte(a), []byte(b)) == 1
+}
+
+// authenticate returns the scope of the client of a request, or an empty
+// string if the client could not be authenticated.
+func (a *Authenticator) authenticate(r *http.Request) string {
+	if r.TLS != nil && len(r.TLS.VerifiedChains) > 0 && len(r.TLS.VerifiedChains[0]) > 0 {
+		if scope, exists := a.clientCerts[r.TLS.VerifiedChains[0][0].Subject.CommonName]; exists {
+			return scope
+		}
+	}
+	if username, password, ok := r.BasicAuth(); ok {
+		for _, u := range a.users {
+			if secureEqual(u.Username, username) && secureEqual(u.Password, password) {
+				return u.Scope
+			}
+		}
+		return ""
+	}
+	if authHeader := r.Header.Get("Authorization"); len(authHeader) > 7 && strings.EqualFold(authHeader[:7], "bearer ") {
+		token := authHeader[7:]
+		for _, t := range a.tokens {
+			if secureEqual(t.Token, token) {
+				return t.Scope
+			}
+		}
+	}
+	return ""
+}
+
+// Wrap a handler so that requests are only served when the client is
+// authenticated with a scope that permits the method of the request. A nil
+// authenticator returns the handler unchanged.
+func (a *Authenticator) Wrap(h http.HandlerFunc) http.HandlerFunc {
+	if a == nil {
+		return h
+	}
+	return func(w http.ResponseWriter, r *http.Request) {
+		scope := a.authenticate(r)
+		if scope == "" {
+			if len(a.users) > 0 {
+				w.Header().Set("WWW-Authenticate", `Basic realm="benthos"`)
+			}
+			http.Error(w, "Unauthorized", http.StatusUnauthorized)
+			return
+		}
+		switch r.Method {
+		case http.MethodGet, http.MethodHead, http.MethodOptions:
+		default:
+			if scope != AuthScopeAdmin {
+				http.Error(w, "Forbidden", http.StatusForbidden)
+				return
+			}
+		}
+		h(w, r)
+	}
+}
+
+// clientTLSConfig returns a TLS config that verifies client certificates
+// against a CA file when provided. Clients without a certificate are still
+// accepted in order to allow other forms of authentication.
+func clientTLSConfig(caFile string) (*tls.Config, error) {
+	caBytes, err := ioutil.ReadFile(caFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read client_ca_file: %w", err)
+	}
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(caBytes) {
+		return nil, errors.New("failed to parse certificates from client_ca_file")
+	}
+	return &tls.Config{
+		ClientCAs:  pool,
+		ClientAuth: tls.VerifyClientCertIfGiven,
+	}, nil
+}
@@ -0,0 +1,118 @@
+package api
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAuthenticatorConfigErrors(t *testing.T) {
+	tests := map[string]struct {
+		conf AuthConfig
+		err  string
+	}{
+		"no credentials": {
+			conf: NewAuthConfig(),
+			err:  "at least one of basic_users, bearer_tokens or client_certs must be specified",
+		},
+		"bad scope": {
+			conf: AuthConfig{
+				BasicUsers: []AuthBasicUser{{Username: "foo", Password: "bar", Scope: "root"}},
+			},
+			err: "basic auth user 'foo': scope 'root' not recognised, expected read or admin",
+		},
+		"empty token": {
+			conf: AuthConfig{
+				BearerTokens: []AuthBearerToken{{Scope: "admin"}},
+			},
+			err: "bearer token 0 must not be empty",
+		},
+		"client certs without ca": {
+			conf: AuthConfig{
+				ClientCerts: []AuthClientCert{{CommonName: "foo", Scope: "admin"}},
+			},
+			err: "client_certs requires a client_ca_file to be specified",
+		},
+	}
+
+	for name, test := range tests {
+		_, err := NewAuthenticator(test.conf)
+		assert.EqualError(t, err, test.err, name)
+	}
+}
+
+func TestAuthenticatorWrap(t *testing.T) {
+	auth, err := NewAuthenticator(AuthConfig{
+		BasicUsers: []AuthBasicUser{
+			{Username: "reader", Password: "readerpass"},
+			{Username: "admin", Password: "adminpass", Scope: "admin"},
+		},
+		BearerTokens: []AuthBearerToken{
+			{Token: "readertoken", Scope: "read"},
+			{Token: "admintoken", Scope: "admin"},
+		},
+		ClientCAFile: "./ca.pem",
+		ClientCerts: []AuthClientCert{
+			{CommonName: "deploy-bot", Scope: "admin"},
+		},
+	})
+	require.NoError(t, err)
+
+	handler := auth.Wrap(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("ok"))
+	})
+
+	withCert := func(r *http.Request, commonName string) {
+		r.TLS = &tls.ConnectionState{
+			VerifiedChains: [][]*x509.Certificate{{{Subject: pkix.Name{CommonName: commonName}}}},
+		}
+	}
+
+	tests := []struct {
+		name   string
+		method string
+		setup  func(r *http.Request)
+		code   int
+	}{
+		{name: "no credentials", method: "GET", setup: func(r *http.Request) {}, code: 401},
+		{name: "bad password", method: "GET", setup: func(r *http.Request) { r.SetBasicAuth("reader", "nope") }, code: 401},
+		{name: "basic reader get", method: "GET", setup: func(r *http.Request) { r.SetBasicAuth("reader", "readerpass") }, code: 200},
+		{name: "basic reader post", method: "POST", setup: func(r *http.Request) { r.SetBasicAuth("reader", "readerpass") }, code: 403},
+		{name: "basic admin delete", method: "DELETE", setup: func(r *http.Request) { r.SetBasicAuth("admin", "adminpass") }, code: 200},
+		{name: "bad token", method: "GET", setup: func(r *http.Request) { r.Header.Set("Authorization", "Bearer nope") }, code: 401},
+		{name: "token reader get", method: "GET", setup: func(r *http.Request) { r.Header.Set("Authorization", "Bearer readertoken") }, code: 200},
+		{name: "token reader put", method: "PUT", setup: func(r *http.Request) { r.Header.Set("Authorization", "Bearer readertoken") }, code: 403},
+		{name: "token admin put", method: "PUT", setup: func(r *http.Request) { r.Header.Set("Authorization", "bearer admintoken") }, code: 200},
+		{name: "unknown cert", method: "GET", setup: func(r *http.Request) { withCert(r, "someone") }, code: 401},
+		{name: "cert admin post", method: "POST", setup: func(r *http.Request) { withCert(r, "deploy-bot") }, code: 200},
+	}
+
+	for _, test := range tests {
+		req := httptest.NewRequest(test.method, "/streams/foo", nil)
+		test.setup(req)
+
+		res := httptest.NewRecorder()
+		handler(res, req)
+		assert.Equal(t, test.code, res.Code, test.name)
+		if test.code == 401 {
+			assert.Equal(t, `Basic realm="benthos"`, res.Header().Get("WWW-Authenticate"), test.name)
+		}
+	}
+}
+
+func TestAuthenticatorNil(t *testing.T) {
+	var auth *Authenticator
+	handler := auth.Wrap(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("ok"))
+	})
+
+	res := httptest.NewRecorder()
+	handler(res, httptest.NewRequest("DELETE", "/streams/foo", nil))
+	assert.Equal(t, 200, res.Code)
+}
@@ -15,6 +15,62 @@ func Spec() docs.FieldSpecs {
 		).HasDefault(false),
 		docs.FieldString("cert_file", "An optional certificate file for enabling TLS.").Advanced().HasDefault(""),
 		docs.FieldString("key_file", "An optional key file for enabling TLS.").Advanced().HasDefault(""),
+		docs.FieldAdvanced(
+			"auth", "Optional authentication of requests to management endpoints, which are the streams mode REST API endpoints `/streams` and `/resources`, the `/drain` endpoint, and all `/debug` endpoints. Requests that modify state (any method other than `GET`, `HEAD` or `OPTIONS`) require the `admin` scope, and all other requests require either the `read` or `admin` scope.",
+		).WithChildren(
+			docs.FieldBool("enabled", "Whether to require authentication of management endpoints.").HasDefault(false),
+			docs.FieldCommon(
+				"basic_users", "A list of users authenticated with basic auth.",
+				[]interface{}{
+					map[string]interface{}{
+						"username": "ops",
+						"password": "${OPS_PASSWORD}",
+						"scope":    "admin",
+					},
+				},
+			).Array().WithChildren(
+				docs.FieldString("username", "The username of the user.").HasDefault(""),
+				docs.FieldString("password", "The password of the user.").Secret().HasDefault(""),
+				docs.FieldString("scope", "The scope granted to the user.").HasOptions("read", "admin").HasDefault("read"),
+			).HasDefault([]interface{}{}),
+			docs.FieldCommon(
+				"bearer_tokens", "A list of tokens authenticated when provided in an `Authorization` header of the form `Bearer <token>`.",
+				[]interface{}{
+					map[string]interface{}{
+						"token": "${DASHBOARD_TOKEN}",
+						"scope": "read",
+					},
+				},
+			).Array().WithChildren(
+				docs.FieldString("token", "The token.").Secret().HasDefault(""),
+				docs.FieldString("scope", "The scope granted to clients of the token.").HasOptions("read", "admin").HasDefault("read"),
+			).HasDefault([]interface{}{}),
+			docs.FieldString(
+				"client_ca_file", "An optional path of a certificate authority file used to verify client certificates, which requires the fields `cert_file` and `key_file` to be set. Clients without a certificate are still able to connect and authenticate by other means.", "./client_ca.pem",
+			).HasDefault(""),
+			docs.FieldCommon(
+				"client_certs", "A list of common names of client certificates, verified with the `client_ca_file`, that are authenticated.",
+				[]interface{}{
+					map[string]interface{}{
+						"common_name": "deploy-bot",
+						"scope":       "admin",
+					},
+				},
+			).Array().WithChildren(
+				docs.FieldString("common_name", "The common name of the client certificate.").HasDefault(""),
+				docs.FieldString("scope", "The scope granted to the client.").HasOptions("read", "admin").HasDefault("read"),
+			).HasDefault([]interface{}{}),
+		),
+		docs.FieldAdvanced(
+			"probes", "Configures the readiness probe endpoint `/ready`, which fails while the input or output is disconnected, and the liveness probe endpoint `/live`, which fails when the input or output has been disconnected for too long.",
+		).WithChildren(
+			docs.FieldString(
+				"ready_grace_period", "A period of time that the input and output are allowed to be disconnected for, after having connected, before the readiness probe fails. This prevents brief reconnections from taking an instance out of rotation. When empty the probe fails as soon as a component is disconnected.", "10s",
+			).HasDefault(""),
+			docs.FieldString(
+				"live_grace_period", "A period of time that the input and output are allowed to be disconnected for, including during startup, before the liveness probe fails, which prompts orchestrators to restart the instance. When empty the liveness probe always passes.", "5m",
+			).HasDefault(""),
+		),
 		docs.FieldDeprecated("read_timeout"),
 	}
 }
@@ -57,6 +57,9 @@ allowing you to transfer data across accounts. You can find out more
 	Constructors[TypeDynamoDB] = TypeSpec{
 		constructor: NewDynamoDB,
 		Status:      docs.StatusDeprecated,
+		ReplacedBy: &docs.ComponentReplacement{
+			Name: TypeAWSDynamoDB,
+		},
 		Summary: `
 Stores key/value pairs as a single document in a DynamoDB table. The key is
 stored as a string value and used as the table hash key. The value is stored as
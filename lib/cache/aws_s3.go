@@ -50,6 +50,9 @@ allowing you to transfer data across accounts. You can find out more
 	Constructors[TypeS3] = TypeSpec{
 		constructor: NewS3,
 		Status:      docs.StatusDeprecated,
+		ReplacedBy: &docs.ComponentReplacement{
+			Name: TypeAWSS3,
+		},
 		Summary: `
 Stores each item in an S3 bucket as a file, where an item ID is the path of the
 item within the bucket.`,
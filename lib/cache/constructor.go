@@ -32,6 +32,10 @@ type TypeSpec struct {
 	Status            docs.Status
 	SupportsPerKeyTTL bool
 	Version           string
+
+	// ReplacedBy describes the component that replaces this one when it
+	// is deprecated.
+	ReplacedBy *docs.ComponentReplacement
 }
 
 // ConstructorFunc is a func signature able to construct a cache.
@@ -56,6 +60,7 @@ func WalkConstructors(fn func(ConstructorFunc, docs.ComponentSpec)) {
 			Config:      conf,
 			Status:      v.Status,
 			Version:     v.Version,
+			ReplacedBy:  v.ReplacedBy,
 		}
 		spec.Description = cache.Description(v.SupportsPerKeyTTL, spec.Description)
 		fn(ConstructorFunc(v.constructor), spec)
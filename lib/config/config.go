@@ -1,6 +1,8 @@
 package config
 
 import (
+	"path/filepath"
+
 	"github.com/Jeffail/benthos/v3/internal/docs"
 	"github.com/Jeffail/benthos/v3/lib/api"
 	"github.com/Jeffail/benthos/v3/lib/buffer"
@@ -23,11 +25,12 @@ type Type struct {
 	HTTP                   api.Config `json:"http" yaml:"http"`
 	stream.Config          `json:",inline" yaml:",inline"`
 	manager.ResourceConfig `json:",inline" yaml:",inline"`
-	Logger                 log.Config     `json:"logger" yaml:"logger"`
-	Metrics                metrics.Config `json:"metrics" yaml:"metrics"`
-	Tracer                 tracer.Config  `json:"tracer" yaml:"tracer"`
-	SystemCloseTimeout     string         `json:"shutdown_timeout" yaml:"shutdown_timeout"`
-	Tests                  []interface{}  `json:"tests,omitempty" yaml:"tests,omitempty"`
+	Logger                 log.Config        `json:"logger" yaml:"logger"`
+	Metrics                metrics.Config    `json:"metrics" yaml:"metrics"`
+	Tracer                 tracer.Config     `json:"tracer" yaml:"tracer"`
+	SystemCloseTimeout     string            `json:"shutdown_timeout" yaml:"shutdown_timeout"`
+	Constants              map[string]string `json:"constants,omitempty" yaml:"constants,omitempty"`
+	Tests                  []interface{}     `json:"tests,omitempty" yaml:"tests,omitempty"`
 }
 
 // New returns a new configuration with default values.
@@ -40,6 +43,7 @@ func New() Type {
 		Metrics:            metrics.NewConfig(),
 		Tracer:             tracer.NewConfig(),
 		SystemCloseTimeout: "20s",
+		Constants:          nil,
 		Tests:              nil,
 	}
 }
@@ -58,6 +62,7 @@ type SanitisedConfig struct {
 	Metrics            interface{} `json:"metrics" yaml:"metrics"`
 	Tracer             interface{} `json:"tracer" yaml:"tracer"`
 	SystemCloseTimeout interface{} `json:"shutdown_timeout" yaml:"shutdown_timeout"`
+	Constants          interface{} `json:"constants,omitempty" yaml:"constants,omitempty"`
 	Tests              interface{} `json:"tests,omitempty" yaml:"tests,omitempty"`
 }
 
@@ -66,6 +71,7 @@ type SanitisedConfig struct {
 type SanitisedV2Config struct {
 	RemoveTypeField        bool
 	RemoveDeprecatedFields bool
+	ScrubSecrets           bool
 }
 
 // SanitisedV2 returns a sanitised version of the config as a yaml.Node.
@@ -78,6 +84,7 @@ func (c Type) SanitisedV2(conf SanitisedV2Config) (yaml.Node, error) {
 	if err := Spec().SanitiseYAML(&node, docs.SanitiseConfig{
 		RemoveTypeField:  conf.RemoveTypeField,
 		RemoveDeprecated: conf.RemoveDeprecatedFields,
+		ScrubSecrets:     conf.ScrubSecrets,
 	}); err != nil {
 		return node, err
 	}
@@ -158,6 +165,7 @@ func (c Type) sanitised(skipDeprecated bool) (*SanitisedConfig, error) {
 		Metrics:            metConf,
 		Tracer:             tracConf,
 		SystemCloseTimeout: c.SystemCloseTimeout,
+		Constants:          c.Constants,
 		Tests:              c.Tests,
 	}, nil
 }
@@ -219,9 +227,20 @@ func AddExamples(conf *Type, examples ...string) {
 //------------------------------------------------------------------------------
 
 // Read will attempt to read a configuration file path into a structure. Returns
-// an array of lint messages or an error.
+// an array of lint error messages or an error.
 func Read(path string, replaceEnvs bool, config *Type) ([]string, error) {
-	configBytes, lints, err := ReadWithJSONPointersLinted(path, replaceEnvs)
+	lints, err := ReadLinted(path, replaceEnvs, config)
+	if err != nil {
+		return nil, err
+	}
+	return lintStrings(lintErrors(lints)), nil
+}
+
+// ReadLinted will attempt to read a configuration file path into a structure.
+// Returns a slice of lints, each describing the position, level and type of the
+// issue, or an error.
+func ReadLinted(path string, replaceEnvs bool, config *Type) ([]docs.Lint, error) {
+	configBytes, lintStrs, err := ReadWithJSONPointersLinted(path, replaceEnvs)
 	if err != nil {
 		return nil, err
 	}
@@ -230,7 +249,12 @@ func Read(path string, replaceEnvs bool, config *Type) ([]string, error) {
 		return nil, err
 	}
 
-	newLints, err := Lint(configBytes, *config)
+	var lints []docs.Lint
+	for _, l := range lintStrs {
+		lints = append(lints, docs.NewLintError(0, l).WithType(docs.LintFailedRead))
+	}
+
+	newLints, err := lintBytes(configBytes, filepath.Dir(path))
 	if err != nil {
 		return nil, err
 	}
@@ -20,6 +20,13 @@ func Spec() docs.FieldSpecs {
 		docs.FieldCommon("metrics", "A mechanism for exporting metrics.").HasType(docs.FieldTypeMetrics),
 		docs.FieldCommon("tracer", "A mechanism for exporting traces.").HasType(docs.FieldTypeTracer),
 		docs.FieldString("shutdown_timeout", "The maximum period of time to wait for a clean shutdown. If this time is exceeded Benthos will forcefully close.").HasDefault("20s"),
+		docs.FieldBloblang(
+			"constants", "Optional Bloblang queries that are executed once when the config is loaded, where each result can be referenced by name within any mapping or interpolation of the config with the function `constant`. Since the queries are executed without a message they are unable to reference message contents or metadata.",
+			map[string]string{
+				"environment": `env("ENVIRONMENT").or("dev")`,
+				"regions":     `{"eu":"Europe","us":"United States"}`,
+			},
+		).Map().Advanced().HasDefault(map[string]interface{}{}),
 		docs.FieldCommon("tests", "Optional unit tests for the config, to be run with the `benthos test` subcommand.").Array().HasType(docs.FieldTypeUnknown).HasDefault([]interface{}{}),
 	}...)
 
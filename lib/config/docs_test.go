@@ -378,3 +378,48 @@ func TestDocumentationCoverage(t *testing.T) {
 		}
 	})
 }
+
+func TestComponentReplacements(t *testing.T) {
+	for _, set := range []struct {
+		docs    []docs.ComponentSpec
+		docsFor func(string) (docs.ComponentSpec, bool)
+	}{
+		{bundle.AllCaches.Docs(), bundle.AllCaches.DocsFor},
+		{bundle.AllInputs.Docs(), bundle.AllInputs.DocsFor},
+		{bundle.AllMetrics.Docs(), bundle.AllMetrics.DocsFor},
+		{bundle.AllOutputs.Docs(), bundle.AllOutputs.DocsFor},
+		{bundle.AllProcessors.Docs(), bundle.AllProcessors.DocsFor},
+	} {
+		for _, spec := range set.docs {
+			if spec.ReplacedBy == nil {
+				continue
+			}
+			prefix := fmt.Sprintf("%v %v", spec.Type, spec.Name)
+			assert.Equal(t, docs.StatusDeprecated, spec.Status, prefix)
+
+			replacement, exists := set.docsFor(spec.ReplacedBy.Name)
+			if !assert.True(t, exists, "%v: replacement %v not found", prefix, spec.ReplacedBy.Name) {
+				continue
+			}
+			assert.NotEqual(t, docs.StatusDeprecated, replacement.Status, prefix)
+
+			replacementFields := map[string]docs.FieldSpec{}
+			for _, f := range replacement.Config.Children {
+				replacementFields[f.Name] = f
+			}
+			for _, f := range spec.Config.Children {
+				if f.IsDeprecated {
+					continue
+				}
+				rf, exists := replacementFields[f.Name]
+				if assert.True(t, exists, "%v: field %v not found in replacement", prefix, f.Name) {
+					assert.Equal(t, f.Kind, rf.Kind, "%v: field %v", prefix, f.Name)
+				}
+			}
+			for k := range spec.ReplacedBy.Fields {
+				_, exists := replacementFields[k]
+				assert.True(t, exists, "%v: replacement field %v not found", prefix, k)
+			}
+		}
+	}
+}
@@ -3,14 +3,65 @@ package config
 import (
 	"bytes"
 	"fmt"
+	"io/ioutil"
 
 	"github.com/Jeffail/benthos/v3/internal/docs"
 	"gopkg.in/yaml.v3"
 )
 
+// LintBytes attempts to report errors and warnings within a user config.
+// Returns a slice of lint results, each describing the position, level and type
+// of the issue.
+func LintBytes(rawBytes []byte) ([]docs.Lint, error) {
+	return lintBytes(rawBytes, "")
+}
+
+// lintBytes lints a config where mappings resolve relative paths from the
+// directory of the config file, when known.
+func lintBytes(rawBytes []byte, dir string) ([]docs.Lint, error) {
+	if bytes.HasPrefix(rawBytes, []byte("# BENTHOS LINT DISABLE")) {
+		return nil, nil
+	}
+
+	var rawNode yaml.Node
+	if err := yaml.Unmarshal(rawBytes, &rawNode); err != nil {
+		return nil, err
+	}
+
+	ctx := docs.NewLintContext()
+	ctx.Source = rawBytes
+	if dir != "" {
+		ctx.BloblangEnv = ctx.BloblangEnv.WithMappingDir(dir)
+	}
+	return Spec().LintYAML(ctx, &rawNode), nil
+}
+
 // Lint attempts to report errors within a user config. Returns a slice of lint
 // results.
 func Lint(rawBytes []byte, _ Type) ([]string, error) {
+	lints, err := LintBytes(rawBytes)
+	if err != nil {
+		return nil, err
+	}
+	return lintStrings(lintErrors(lints)), nil
+}
+
+// ReadLintRules reads a file of custom lint rules.
+func ReadLintRules(path string) (*docs.LintRules, error) {
+	rawBytes, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	rules, err := docs.LintRulesFromYAML(rawBytes)
+	if err != nil {
+		return nil, fmt.Errorf("%v: %w", path, err)
+	}
+	return rules, nil
+}
+
+// LintRulesBytes attempts to report breaches of custom lint rules within a user
+// config, where environment variable interpolations have not been replaced.
+func LintRulesBytes(rules *docs.LintRules, rawBytes []byte) ([]docs.Lint, error) {
 	if bytes.HasPrefix(rawBytes, []byte("# BENTHOS LINT DISABLE")) {
 		return nil, nil
 	}
@@ -19,12 +70,39 @@ func Lint(rawBytes []byte, _ Type) ([]string, error) {
 	if err := yaml.Unmarshal(rawBytes, &rawNode); err != nil {
 		return nil, err
 	}
+	return rules.LintYAML(docs.NewLintContext(), Spec(), &rawNode), nil
+}
 
-	var lintStrs []string
-	for _, lint := range Spec().LintYAML(docs.NewLintContext(), &rawNode) {
+// LintRulesFile attempts to report breaches of custom lint rules within a user
+// config file.
+func LintRulesFile(rules *docs.LintRules, path string) ([]docs.Lint, error) {
+	rawBytes, _, err := ReadWithJSONPointersLinted(path, false)
+	if err != nil {
+		return nil, err
+	}
+	return LintRulesBytes(rules, rawBytes)
+}
+
+// lintErrors returns only the lints that are errors, as warnings describe
+// likely problems that must not prevent a config from running.
+func lintErrors(lints []docs.Lint) []docs.Lint {
+	var errs []docs.Lint
+	for _, lint := range lints {
 		if lint.Level == docs.LintError {
+			errs = append(errs, lint)
+		}
+	}
+	return errs
+}
+
+func lintStrings(lints []docs.Lint) []string {
+	var lintStrs []string
+	for _, lint := range lints {
+		if lint.Line > 0 {
 			lintStrs = append(lintStrs, fmt.Sprintf("line %v: %v", lint.Line, lint.What))
+		} else {
+			lintStrs = append(lintStrs, lint.What)
 		}
 	}
-	return lintStrs, nil
+	return lintStrs
 }
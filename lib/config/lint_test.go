@@ -1,11 +1,16 @@
 package config_test
 
 import (
+	"os"
+	"path/filepath"
 	"reflect"
 	"testing"
 
+	"github.com/Jeffail/benthos/v3/internal/docs"
 	"github.com/Jeffail/benthos/v3/lib/config"
 	_ "github.com/Jeffail/benthos/v3/public/components/all"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 //------------------------------------------------------------------------------
@@ -167,4 +172,127 @@ tests:
 	}
 }
 
+func TestConfigLintBytes(t *testing.T) {
+	lints, err := config.LintBytes([]byte(`input:
+  stdin:
+    nope: true
+pipeline:
+  processors:
+    - bloblang: root = this.
+output:
+  label: nah nope
+  stdout: {}
+`))
+	require.NoError(t, err)
+	require.Len(t, lints, 3)
+
+	assert.Equal(t, docs.Lint{Line: 3, Column: 5, Level: docs.LintError, Type: docs.LintUnknown, What: "field nope not recognised"}, lints[0])
+
+	assert.Equal(t, 6, lints[1].Line)
+	assert.Equal(t, 29, lints[1].Column)
+	assert.Equal(t, docs.LintBadBloblang, lints[1].Type)
+
+	assert.Equal(t, 8, lints[2].Line)
+	assert.Equal(t, docs.LintBadLabel, lints[2].Type)
+}
+
+func TestConfigLintBytesMappingWarnings(t *testing.T) {
+	lints, err := config.LintBytes([]byte(`pipeline:
+  processors:
+    - bloblang: |
+        root = match this.value {
+          string s => s
+          array a => a.length()
+        }
+`))
+	require.NoError(t, err)
+	require.Len(t, lints, 1)
+
+	assert.Equal(t, docs.LintWarning, lints[0].Level)
+	assert.Equal(t, docs.LintBadBloblang, lints[0].Type)
+	assert.Equal(t, 4, lints[0].Line)
+	assert.Equal(t, 16, lints[0].Column)
+	assert.Contains(t, lints[0].What, "match expression does not handle the types number, bool, null")
+}
+
+func TestConfigLintBytesTypeWarnings(t *testing.T) {
+	lints, err := config.LintBytes([]byte(`pipeline:
+  processors:
+    - bloblang: |
+        root.a = {"a":1}.sum()
+        root.b = this.foo.keys().uppercase()
+`))
+	require.NoError(t, err)
+	require.Len(t, lints, 2)
+
+	for _, l := range lints {
+		assert.Equal(t, docs.LintWarning, l.Level)
+		assert.Equal(t, docs.LintBadBloblang, l.Type)
+	}
+	assert.Equal(t, 4, lints[0].Line)
+	assert.Equal(t, 26, lints[0].Column)
+	assert.Contains(t, lints[0].What, "method sum")
+	assert.Equal(t, 5, lints[1].Line)
+	assert.Equal(t, 34, lints[1].Column)
+	assert.Contains(t, lints[1].What, "method uppercase")
+}
+
+func TestConfigLintBytesSemanticWarnings(t *testing.T) {
+	lints, err := config.LintBytes([]byte(`pipeline:
+  processors:
+    - bloblang: |
+        let unused = "foo"
+        root.a = this.a
+        root.a = this.b
+        root.c = timestamp()
+`))
+	require.NoError(t, err)
+	require.Len(t, lints, 3)
+
+	for _, l := range lints {
+		assert.Equal(t, docs.LintWarning, l.Level)
+		assert.Equal(t, docs.LintBadBloblang, l.Type)
+	}
+	assert.Equal(t, 4, lints[0].Line)
+	assert.Equal(t, 9, lints[0].Column)
+	assert.Equal(t, "variable $unused is declared but never used", lints[0].What)
+	assert.Equal(t, 5, lints[1].Line)
+	assert.Equal(t, 9, lints[1].Column)
+	assert.Equal(t, "assignment to root.a is always overwritten by a later assignment to root.a", lints[1].What)
+	assert.Equal(t, 7, lints[2].Line)
+	assert.Equal(t, 18, lints[2].Column)
+	assert.Equal(t, "function timestamp is deprecated", lints[2].What)
+}
+
+func TestConfigLintWarningsNotErrors(t *testing.T) {
+	conf := []byte(`pipeline:
+  processors:
+    - bloblang: |
+        let unused = "foo"
+        root = this
+`)
+
+	lints, err := config.LintBytes(conf)
+	require.NoError(t, err)
+	require.Len(t, lints, 1)
+
+	// Warnings never prevent a config from running.
+	lintStrs, err := config.Lint(conf, config.New())
+	require.NoError(t, err)
+	assert.Empty(t, lintStrs)
+
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	require.NoError(t, os.WriteFile(path, conf, 0644))
+
+	readConf := config.New()
+	lintStrs, err = config.Read(path, true, &readConf)
+	require.NoError(t, err)
+	assert.Empty(t, lintStrs)
+
+	readConf = config.New()
+	readLints, err := config.ReadLinted(path, true, &readConf)
+	require.NoError(t, err)
+	assert.Equal(t, lints, readLints)
+}
+
 //------------------------------------------------------------------------------
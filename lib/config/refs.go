@@ -41,7 +41,9 @@ func ReadWithJSONPointersLinted(path string, replaceEnvs bool) (configBytes []by
 	}
 
 	if replaceEnvs {
-		configBytes = text.ReplaceEnvVariables(configBytes)
+		if configBytes, err = text.ReplaceEnvVariablesAndSecrets(configBytes); err != nil {
+			return nil, lints, err
+		}
 	}
 
 	var gen interface{}
@@ -168,7 +170,9 @@ func expandRefVal(path string, level int, root, v interface{}) (interface{}, err
 		if err != nil {
 			return nil, fmt.Errorf("failed to read relative $ref path '%v' in config '%v': %v", rPath, path, err)
 		}
-		configBytes = text.ReplaceEnvVariables(configBytes)
+		if configBytes, err = text.ReplaceEnvVariablesAndSecrets(configBytes); err != nil {
+			return nil, fmt.Errorf("failed to read relative $ref path '%v' in config '%v': %v", rPath, path, err)
+		}
 
 		var gen interface{}
 		if err := yaml.Unmarshal(configBytes, &gen); err != nil {
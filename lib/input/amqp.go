@@ -18,6 +18,9 @@ func init() {
 DEPRECATED: This input is deprecated and scheduled for removal in Benthos V4.
 Please use [` + "`amqp_0_9`" + `](/docs/components/inputs/amqp_0_9) instead.`,
 		Status: docs.StatusDeprecated,
+		ReplacedBy: &docs.ComponentReplacement{
+			Name: TypeAMQP09,
+		},
 		FieldSpecs: docs.FieldSpecs{
 			docs.FieldCommon("url",
 				"A URL to connect to.",
@@ -49,6 +49,16 @@ Benthos will not store a consumed sequence unless it is acknowledged at the outp
 
 It's possible to configure Benthos to create the DynamoDB table required for coordination if it does not already exist. However, if you wish to create this yourself (recommended) then create a table with a string HASH key ` + "`StreamID`" + ` and a string RANGE key ` + "`ShardID`" + `. 
 
+Once a shard has been fully consumed its checkpoint is set to the sequence ` + "`SHARD_END`" + `, following the convention of the Kinesis Client Library (KCL), and is removed once the shard is no longer listed by the stream.
+
+## Resharding
+
+When shards are balanced the list of shards of each stream is refreshed every ` + "`rebalance_period`" + `, and therefore shards created by splitting or merging are discovered automatically. Shards that have been closed by a resharding are consumed until their end, and child shards are only claimed once all of their parent shards have been fully consumed, which preserves the ordering of messages that share a partition key. A closed shard that has no checkpoint at all is considered fully consumed, as prior versions of this input removed the checkpoints of finished shards.
+
+## Enhanced Fan-Out
+
+By setting ` + "`enhanced_fan_out.enabled`" + ` to ` + "`true`" + ` this input registers a [stream consumer](https://docs.aws.amazon.com/streams/latest/dev/enhanced-consumers.html) with the name ` + "`enhanced_fan_out.consumer_name`" + ` for each stream (unless it already exists), and shards are read by subscribing to them rather than by polling. This provides each consumer with its own dedicated read throughput and lower propagation delays. The consumer name should be shared by all instances of a given pipeline and unique to that pipeline, as a shard can only have one active subscription per consumer at any given time.
+
 ## Batching
 
 Use the ` + "`batching`" + ` fields to configure an optional [batching policy](/docs/configuration/batching#batch-policy). Each stream shard will be batched separately in order to ensure that acknowledgements aren't contaminated. Any other batching mechanism will stall with this input due its sequential transaction model.`,
@@ -58,6 +68,10 @@ Use the ` + "`batching`" + ` fields to configure an optional [batching policy](/
 				docs.FieldCommon(
 					"dynamodb", "Determines the table used for storing and accessing the latest consumed sequence for shards, and for coordinating balanced consumers of streams.",
 				).WithChildren(dynamoDBCheckpointFields...),
+				docs.FieldAdvanced("enhanced_fan_out", "Allows you to consume shards with [enhanced fan-out](#enhanced-fan-out) subscriptions instead of polling them.").WithChildren(
+					docs.FieldCommon("enabled", "Whether to consume shards with enhanced fan-out."),
+					docs.FieldCommon("consumer_name", "The name of the stream consumer to subscribe with, which is registered if it does not already exist."),
+				).AtVersion("3.55.0"),
 				docs.FieldCommon(
 					"checkpoint_limit", "The maximum gap between the in flight sequence versus the latest acknowledged sequence at a given time. Increasing this limit enables parallel processing and batching at the output level to work on individual shards. Any given sequence will not be committed unless all messages under that offset are delivered in order to preserve at least once delivery guarantees.",
 				),
@@ -77,17 +91,34 @@ Use the ` + "`batching`" + ` fields to configure an optional [batching policy](/
 
 //------------------------------------------------------------------------------
 
+// AWSKinesisEnhancedFanOutConfig contains configuration fields for consuming
+// Kinesis shards with enhanced fan-out subscriptions.
+type AWSKinesisEnhancedFanOutConfig struct {
+	Enabled      bool   `json:"enabled" yaml:"enabled"`
+	ConsumerName string `json:"consumer_name" yaml:"consumer_name"`
+}
+
+// NewAWSKinesisEnhancedFanOutConfig returns an AWSKinesisEnhancedFanOutConfig
+// with default values.
+func NewAWSKinesisEnhancedFanOutConfig() AWSKinesisEnhancedFanOutConfig {
+	return AWSKinesisEnhancedFanOutConfig{
+		Enabled:      false,
+		ConsumerName: "",
+	}
+}
+
 // AWSKinesisConfig is configuration values for the input type.
 type AWSKinesisConfig struct {
 	session.Config  `json:",inline" yaml:",inline"`
-	Streams         []string                 `json:"streams" yaml:"streams"`
-	DynamoDB        DynamoDBCheckpointConfig `json:"dynamodb" yaml:"dynamodb"`
-	CheckpointLimit int                      `json:"checkpoint_limit" yaml:"checkpoint_limit"`
-	CommitPeriod    string                   `json:"commit_period" yaml:"commit_period"`
-	LeasePeriod     string                   `json:"lease_period" yaml:"lease_period"`
-	RebalancePeriod string                   `json:"rebalance_period" yaml:"rebalance_period"`
-	StartFromOldest bool                     `json:"start_from_oldest" yaml:"start_from_oldest"`
-	Batching        batch.PolicyConfig       `json:"batching" yaml:"batching"`
+	Streams         []string                       `json:"streams" yaml:"streams"`
+	DynamoDB        DynamoDBCheckpointConfig       `json:"dynamodb" yaml:"dynamodb"`
+	EnhancedFanOut  AWSKinesisEnhancedFanOutConfig `json:"enhanced_fan_out" yaml:"enhanced_fan_out"`
+	CheckpointLimit int                            `json:"checkpoint_limit" yaml:"checkpoint_limit"`
+	CommitPeriod    string                         `json:"commit_period" yaml:"commit_period"`
+	LeasePeriod     string                         `json:"lease_period" yaml:"lease_period"`
+	RebalancePeriod string                         `json:"rebalance_period" yaml:"rebalance_period"`
+	StartFromOldest bool                           `json:"start_from_oldest" yaml:"start_from_oldest"`
+	Batching        batch.PolicyConfig             `json:"batching" yaml:"batching"`
 }
 
 // NewAWSKinesisConfig creates a new Config with default values.
@@ -96,6 +127,7 @@ func NewAWSKinesisConfig() AWSKinesisConfig {
 		Config:          session.NewConfig(),
 		Streams:         []string{},
 		DynamoDB:        NewDynamoDBCheckpointConfig(),
+		EnhancedFanOut:  NewAWSKinesisEnhancedFanOutConfig(),
 		CheckpointLimit: 1,
 		CommitPeriod:    "5s",
 		LeasePeriod:     "30s",
@@ -127,6 +159,7 @@ type kinesisReader struct {
 
 	streamShards    map[string][]string
 	balancedStreams []string
+	consumerARNs    map[string]string
 
 	commitPeriod    time.Duration
 	leasePeriod     time.Duration
@@ -161,6 +194,7 @@ func newKinesisReader(
 		mRebalanced:  stats.GetCounter("rebalanced"),
 		closedChan:   make(chan struct{}),
 		streamShards: map[string][]string{},
+		consumerARNs: map[string]string{},
 	}
 	k.ctx, k.done = context.WithCancel(context.Background())
 
@@ -204,6 +238,9 @@ func newKinesisReader(
 			}
 		}
 	}
+	if conf.EnhancedFanOut.Enabled && conf.EnhancedFanOut.ConsumerName == "" {
+		return nil, errors.New("a consumer_name must be specified when enhanced_fan_out is enabled")
+	}
 	if k.commitPeriod, err = time.ParseDuration(k.conf.CommitPeriod); err != nil {
 		return nil, fmt.Errorf("failed to parse commit period string: %v", err)
 	}
@@ -293,6 +330,149 @@ func (k *kinesisReader) getRecords(streamID, shardID, shardIter string) ([]*kine
 	return res.Records, nextIter, nil
 }
 
+func (k *kinesisReader) startingPosition(sequence string) *kinesis.StartingPosition {
+	if len(sequence) > 0 {
+		return &kinesis.StartingPosition{
+			Type:           aws.String(kinesis.ShardIteratorTypeAfterSequenceNumber),
+			SequenceNumber: aws.String(sequence),
+		}
+	}
+	iterType := kinesis.ShardIteratorTypeTrimHorizon
+	if !k.conf.StartFromOldest {
+		iterType = kinesis.ShardIteratorTypeLatest
+	}
+	return &kinesis.StartingPosition{Type: &iterType}
+}
+
+// registerStreamConsumer obtains the ARN of the enhanced fan-out consumer of a
+// stream, registering the consumer if it does not yet exist and blocking until
+// it becomes active.
+func (k *kinesisReader) registerStreamConsumer(ctx context.Context, streamID string) (string, error) {
+	summary, err := k.svc.DescribeStreamSummaryWithContext(ctx, &kinesis.DescribeStreamSummaryInput{
+		StreamName: aws.String(streamID),
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to describe stream '%v': %w", streamID, err)
+	}
+	streamARN := summary.StreamDescriptionSummary.StreamARN
+
+	registered := false
+	for {
+		res, err := k.svc.DescribeStreamConsumerWithContext(ctx, &kinesis.DescribeStreamConsumerInput{
+			StreamARN:    streamARN,
+			ConsumerName: aws.String(k.conf.EnhancedFanOut.ConsumerName),
+		})
+		if err != nil {
+			aerr, ok := err.(awserr.Error)
+			if !ok || aerr.Code() != kinesis.ErrCodeResourceNotFoundException || registered {
+				return "", fmt.Errorf("failed to describe stream '%v' consumer: %w", streamID, err)
+			}
+			if _, err = k.svc.RegisterStreamConsumerWithContext(ctx, &kinesis.RegisterStreamConsumerInput{
+				StreamARN:    streamARN,
+				ConsumerName: aws.String(k.conf.EnhancedFanOut.ConsumerName),
+			}); err != nil {
+				// Another client might have registered the consumer first.
+				if aerr, ok := err.(awserr.Error); !ok || aerr.Code() != kinesis.ErrCodeResourceInUseException {
+					return "", fmt.Errorf("failed to register stream '%v' consumer: %w", streamID, err)
+				}
+			}
+			registered = true
+			k.log.Infof("Registered enhanced fan-out consumer '%v' for stream '%v'\n", k.conf.EnhancedFanOut.ConsumerName, streamID)
+		} else if desc := res.ConsumerDescription; desc != nil && desc.ConsumerStatus != nil && *desc.ConsumerStatus == kinesis.ConsumerStatusActive {
+			return *desc.ConsumerARN, nil
+		}
+
+		select {
+		case <-time.After(time.Second):
+		case <-ctx.Done():
+			return "", ctx.Err()
+		}
+	}
+}
+
+// subscribeShard consumes a shard with enhanced fan-out subscriptions, which
+// are renewed each time they expire. Records are written to the returned
+// channel, which is closed once the end of the shard has been reached. The
+// channel is left open when the context is cancelled.
+func (k *kinesisReader) subscribeShard(ctx context.Context, streamID, shardID, sequence string) <-chan []*kinesis.Record {
+	recordsChan := make(chan []*kinesis.Record)
+	go func() {
+		boff := k.boffPool.Get().(backoff.BackOff)
+		defer func() {
+			boff.Reset()
+			k.boffPool.Put(boff)
+		}()
+
+		for {
+			res, err := k.svc.SubscribeToShardWithContext(ctx, &kinesis.SubscribeToShardInput{
+				ConsumerARN:      aws.String(k.consumerARNs[streamID]),
+				ShardId:          aws.String(shardID),
+				StartingPosition: k.startingPosition(sequence),
+			})
+			if err == nil {
+				var finished bool
+				if sequence, finished, err = readShardSubscription(ctx, res.EventStream, sequence, recordsChan); err == nil {
+					if finished {
+						close(recordsChan)
+						return
+					}
+					boff.Reset()
+					continue
+				}
+			}
+			if ctx.Err() != nil {
+				return
+			}
+			k.log.Errorf("Failed to read subscription of stream '%v' shard '%v': %v\n", streamID, shardID, err)
+			select {
+			case <-time.After(boff.NextBackOff()):
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return recordsChan
+}
+
+// readShardSubscription writes the records of a shard subscription to a
+// channel until the subscription expires, returning the sequence to continue
+// from and whether the end of the shard has been reached.
+func readShardSubscription(
+	ctx context.Context,
+	stream *kinesis.SubscribeToShardEventStream,
+	sequence string,
+	recordsChan chan<- []*kinesis.Record,
+) (string, bool, error) {
+	defer stream.Close()
+	for {
+		select {
+		case e, open := <-stream.Events():
+			if !open {
+				return sequence, false, stream.Err()
+			}
+			event, ok := e.(*kinesis.SubscribeToShardEvent)
+			if !ok {
+				continue
+			}
+			if len(event.Records) > 0 {
+				select {
+				case recordsChan <- event.Records:
+				case <-ctx.Done():
+					return sequence, false, ctx.Err()
+				}
+			}
+			// A nil continuation sequence indicates that the shard has been
+			// closed and all of its records have been delivered.
+			if event.ContinuationSequenceNumber == nil {
+				return sequence, true, nil
+			}
+			sequence = *event.ContinuationSequenceNumber
+		case <-ctx.Done():
+			return sequence, false, ctx.Err()
+		}
+	}
+}
+
 func awsErrIsTimeout(err error) bool {
 	return errors.Is(err, context.Canceled) ||
 		errors.Is(err, context.DeadlineExceeded) ||
@@ -310,6 +490,15 @@ const (
 )
 
 func (k *kinesisReader) runConsumer(wg *sync.WaitGroup, streamID, shardID, startingSequence string) (initErr error) {
+	if startingSequence == awsKinesisShardEnd {
+		defer wg.Done()
+		k.log.Debugf("Stream '%v' shard '%v' has already been consumed to its end\n", streamID, shardID)
+		if _, err := k.checkpointer.Checkpoint(context.Background(), streamID, shardID, startingSequence, true); err != nil {
+			k.log.Errorf("Failed to gracefully yield checkpoint: %v\n", err)
+		}
+		return nil
+	}
+
 	defer func() {
 		if initErr != nil {
 			wg.Done()
@@ -332,7 +521,16 @@ func (k *kinesisReader) runConsumer(wg *sync.WaitGroup, streamID, shardID, start
 	// Stores consumed records that have yet to be added to the batcher.
 	var pending []*kinesis.Record
 	var iter string
-	if iter, initErr = k.getIter(streamID, shardID, startingSequence); initErr != nil {
+
+	// When consuming with enhanced fan-out records are pushed to this channel
+	// rather than pulled with a shard iterator.
+	var recordsChan <-chan []*kinesis.Record
+	stopSubscription := func() {}
+	if k.conf.EnhancedFanOut.Enabled {
+		var subCtx context.Context
+		subCtx, stopSubscription = context.WithCancel(k.ctx)
+		recordsChan = k.subscribeShard(subCtx, streamID, shardID, startingSequence)
+	} else if iter, initErr = k.getIter(streamID, shardID, startingSequence); initErr != nil {
 		return initErr
 	}
 
@@ -361,6 +559,7 @@ func (k *kinesisReader) runConsumer(wg *sync.WaitGroup, streamID, shardID, start
 	go func() {
 		defer func() {
 			commitCtxClose()
+			stopSubscription()
 			recordBatcher.Close(state == awsKinesisConsumerFinished)
 			boff.Reset()
 			k.boffPool.Put(boff)
@@ -369,8 +568,8 @@ func (k *kinesisReader) runConsumer(wg *sync.WaitGroup, streamID, shardID, start
 			switch state {
 			case awsKinesisConsumerFinished:
 				reason = " because the shard is closed"
-				if err := k.checkpointer.Delete(k.ctx, streamID, shardID); err != nil {
-					k.log.Errorf("Failed to remove checkpoint for finished stream '%v' shard '%v': %v\n", streamID, shardID, err)
+				if _, err := k.checkpointer.Checkpoint(k.ctx, streamID, shardID, awsKinesisShardEnd, true); err != nil {
+					k.log.Errorf("Failed to store final checkpoint for finished stream '%v' shard '%v': %v\n", streamID, shardID, err)
 				}
 			case awsKinesisConsumerYielding:
 				reason = " because the shard has been claimed by another client"
@@ -392,7 +591,7 @@ func (k *kinesisReader) runConsumer(wg *sync.WaitGroup, streamID, shardID, start
 
 		for {
 			var err error
-			if state == awsKinesisConsumerConsuming && len(pending) == 0 && nextPullChan == unblockedChan {
+			if recordsChan == nil && state == awsKinesisConsumerConsuming && len(pending) == 0 && nextPullChan == unblockedChan {
 				if pending, iter, err = k.getRecords(streamID, shardID, iter); err != nil {
 					if !awsErrIsTimeout(err) {
 						nextPullChan = time.After(boff.NextBackOff())
@@ -464,6 +663,16 @@ func (k *kinesisReader) runConsumer(wg *sync.WaitGroup, streamID, shardID, start
 				nextFlushChan = nil
 			}
 
+			var nextRecordsChan <-chan []*kinesis.Record
+			if recordsChan != nil {
+				// Records are pushed by the subscription so there is nothing
+				// to pull.
+				nextPullChan = blockedChan
+				if state == awsKinesisConsumerConsuming && len(pending) == 0 {
+					nextRecordsChan = recordsChan
+				}
+			}
+
 			if nextTimedBatchChan == nil {
 				if tNext := recordBatcher.UntilNext(); tNext >= 0 {
 					nextTimedBatchChan = time.After(tNext)
@@ -495,6 +704,12 @@ func (k *kinesisReader) runConsumer(wg *sync.WaitGroup, streamID, shardID, start
 				pendingMsg = asyncMessage{}
 			case <-nextPullChan:
 				nextPullChan = unblockedChan
+			case records, open := <-nextRecordsChan:
+				if !open {
+					state = awsKinesisConsumerFinished
+				} else {
+					pending = records
+				}
 			case <-k.ctx.Done():
 				state = awsKinesisConsumerClosing
 				return
@@ -506,7 +721,28 @@ func (k *kinesisReader) runConsumer(wg *sync.WaitGroup, streamID, shardID, start
 
 //------------------------------------------------------------------------------
 
-func isShardFinished(s *kinesis.Shard) bool {
+func (k *kinesisReader) listShards(streamID string) ([]*kinesis.Shard, error) {
+	var shards []*kinesis.Shard
+	input := &kinesis.ListShardsInput{
+		StreamName: aws.String(streamID),
+	}
+	for {
+		res, err := k.svc.ListShardsWithContext(k.ctx, input)
+		if err != nil {
+			return nil, err
+		}
+		shards = append(shards, res.Shards...)
+		if res.NextToken == nil || *res.NextToken == "" {
+			return shards, nil
+		}
+		// The stream name must not be specified alongside a next token.
+		input = &kinesis.ListShardsInput{
+			NextToken: res.NextToken,
+		}
+	}
+}
+
+func isShardClosed(s *kinesis.Shard) bool {
 	if s.SequenceNumberRange == nil {
 		return false
 	}
@@ -516,6 +752,62 @@ func isShardFinished(s *kinesis.Shard) bool {
 	return *s.SequenceNumberRange.EndingSequenceNumber != "null"
 }
 
+// awsKinesisFinishedShards returns the shards of a stream that have been
+// consumed to their end, which are shards checkpointed at the end of the shard.
+//
+// Prior versions of this input removed the checkpoint of a shard once it was
+// consumed to its end, and therefore closed shards without any checkpoint are
+// also considered finished. This prevents those shards from being consumed
+// again from the start after an upgrade.
+func awsKinesisFinishedShards(shards []*kinesis.Shard, checkpoints map[string]string) map[string]struct{} {
+	finished := map[string]struct{}{}
+	for shardID, sequence := range checkpoints {
+		if sequence == awsKinesisShardEnd {
+			finished[shardID] = struct{}{}
+		}
+	}
+	for _, s := range shards {
+		if _, exists := checkpoints[*s.ShardId]; !exists && isShardClosed(s) {
+			finished[*s.ShardId] = struct{}{}
+		}
+	}
+	return finished
+}
+
+// awsKinesisConsumableShards returns the shards of a stream that are ready to
+// be consumed, which excludes shards that have already been consumed to their
+// end and shards with a parent that is yet to be consumed to its end. Parent
+// shards that are no longer listed have expired and are considered consumed.
+func awsKinesisConsumableShards(shards []*kinesis.Shard, finished map[string]struct{}) map[string]string {
+	listed := make(map[string]struct{}, len(shards))
+	for _, s := range shards {
+		listed[*s.ShardId] = struct{}{}
+	}
+
+	isPending := func(shardID *string) bool {
+		if shardID == nil {
+			return false
+		}
+		if _, exists := listed[*shardID]; !exists {
+			return false
+		}
+		_, done := finished[*shardID]
+		return !done
+	}
+
+	consumable := make(map[string]string, len(shards))
+	for _, s := range shards {
+		if _, done := finished[*s.ShardId]; done {
+			continue
+		}
+		if isPending(s.ParentShardId) || isPending(s.AdjacentParentShardId) {
+			continue
+		}
+		consumable[*s.ShardId] = ""
+	}
+	return consumable
+}
+
 func (k *kinesisReader) runBalancedShards() {
 	var wg sync.WaitGroup
 	defer func() {
@@ -528,14 +820,16 @@ func (k *kinesisReader) runBalancedShards() {
 
 	for {
 		for _, streamID := range k.balancedStreams {
-			shardsRes, err := k.svc.ListShardsWithContext(k.ctx, &kinesis.ListShardsInput{
-				StreamName: aws.String(streamID),
-			})
+			shards, err := k.listShards(streamID)
 
 			var clientClaims map[string][]awsKinesisClientClaim
 			if err == nil {
 				clientClaims, err = k.checkpointer.AllClaims(k.ctx, streamID)
 			}
+			var checkpoints map[string]string
+			if err == nil {
+				checkpoints, err = k.checkpointer.AllCheckpoints(k.ctx, streamID)
+			}
 			if err != nil {
 				if k.ctx.Err() != nil {
 					return
@@ -544,13 +838,9 @@ func (k *kinesisReader) runBalancedShards() {
 				continue
 			}
 
-			totalShards := len(shardsRes.Shards)
-			unclaimedShards := make(map[string]string, totalShards)
-			for _, s := range shardsRes.Shards {
-				if !isShardFinished(s) {
-					unclaimedShards[*s.ShardId] = ""
-				}
-			}
+			finishedShards := awsKinesisFinishedShards(shards, checkpoints)
+			unclaimedShards := awsKinesisConsumableShards(shards, finishedShards)
+			k.removeExpiredShards(streamID, shards, finishedShards)
 			for clientID, claims := range clientClaims {
 				for _, claim := range claims {
 					if time.Since(claim.LeaseTimeout) > k.leasePeriod*2 {
@@ -643,6 +933,26 @@ func (k *kinesisReader) runBalancedShards() {
 	}
 }
 
+// removeExpiredShards deletes the checkpoints of finished shards that are no
+// longer listed by the stream.
+func (k *kinesisReader) removeExpiredShards(streamID string, shards []*kinesis.Shard, finished map[string]struct{}) {
+	if len(finished) == 0 {
+		return
+	}
+	listed := make(map[string]struct{}, len(shards))
+	for _, s := range shards {
+		listed[*s.ShardId] = struct{}{}
+	}
+	for shardID := range finished {
+		if _, exists := listed[shardID]; exists {
+			continue
+		}
+		if err := k.checkpointer.Delete(k.ctx, streamID, shardID); err != nil {
+			k.log.Errorf("Failed to remove checkpoint for expired stream '%v' shard '%v': %v\n", streamID, shardID, err)
+		}
+	}
+}
+
 func (k *kinesisReader) runExplicitShards() {
 	var wg sync.WaitGroup
 	defer func() {
@@ -706,6 +1016,18 @@ func (k *kinesisReader) ConnectWithContext(ctx context.Context) error {
 	}
 
 	k.svc = svc
+	if k.conf.EnhancedFanOut.Enabled {
+		streams := append([]string{}, k.balancedStreams...)
+		for streamID := range k.streamShards {
+			streams = append(streams, streamID)
+		}
+		for _, streamID := range streams {
+			if k.consumerARNs[streamID], err = k.registerStreamConsumer(ctx, streamID); err != nil {
+				return err
+			}
+		}
+	}
+
 	k.checkpointer = checkpointer
 	k.msgChan = make(chan asyncMessage)
 
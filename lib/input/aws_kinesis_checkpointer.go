@@ -50,6 +50,10 @@ func NewDynamoDBCheckpointConfig() DynamoDBCheckpointConfig {
 
 //------------------------------------------------------------------------------
 
+// awsKinesisShardEnd is the checkpoint sequence of a shard that has been
+// consumed to its end, which matches the sentinel used by the KCL.
+const awsKinesisShardEnd = "SHARD_END"
+
 // Common errors that might occur throughout checkpointing.
 var (
 	ErrLeaseNotAcquired = errors.New("the shard could not be leased due to a collision")
@@ -243,6 +247,38 @@ func (k *awsKinesisCheckpointer) AllClaims(ctx context.Context, streamID string)
 	return clientClaims, scanErr
 }
 
+// AllCheckpoints returns a map of shard IDs to the checkpointed sequence of
+// each shard of a stream that has a checkpoint. The sequence is empty for
+// shards that have been claimed but not yet checkpointed.
+func (k *awsKinesisCheckpointer) AllCheckpoints(ctx context.Context, streamID string) (map[string]string, error) {
+	checkpoints := map[string]string{}
+	if err := k.svc.ScanPagesWithContext(ctx, &dynamodb.ScanInput{
+		TableName:        aws.String(k.conf.Table),
+		FilterExpression: aws.String("StreamID = :stream_id"),
+		ExpressionAttributeValues: map[string]*dynamodb.AttributeValue{
+			":stream_id": {
+				S: &streamID,
+			},
+		},
+	}, func(page *dynamodb.ScanOutput, last bool) bool {
+		for _, i := range page.Items {
+			s, ok := i["ShardID"]
+			if !ok || s.S == nil {
+				continue
+			}
+			var sequence string
+			if seq, ok := i["SequenceNumber"]; ok && seq.S != nil {
+				sequence = *seq.S
+			}
+			checkpoints[*s.S] = sequence
+		}
+		return true
+	}); err != nil {
+		return nil, err
+	}
+	return checkpoints, nil
+}
+
 // Claim attempts to claim a shard for a particular stream ID. If fromClientID
 // is specified the shard is stolen from that particular client, and the
 // operation fails if a different client ID has it claimed.
@@ -420,8 +456,8 @@ func (k *awsKinesisCheckpointer) Yield(ctx context.Context, streamID, shardID, s
 	return err
 }
 
-// Delete attempts to delete a checkpoint, this should be called when a shard is
-// emptied.
+// Delete attempts to delete a checkpoint, this should be called once a shard
+// that has been emptied is no longer listed by the stream.
 func (k *awsKinesisCheckpointer) Delete(ctx context.Context, streamID, shardID string) error {
 	_, err := k.svc.DeleteItemWithContext(ctx, &dynamodb.DeleteItemInput{
 		TableName: aws.String(k.conf.Table),
@@ -0,0 +1,210 @@
+package input
+
+import (
+	"testing"
+
+	"github.com/Jeffail/benthos/v3/lib/log"
+	"github.com/Jeffail/benthos/v3/lib/metrics"
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/kinesis"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAWSKinesisBadParams(t *testing.T) {
+	testCases := []struct {
+		name    string
+		streams []string
+		efo     bool
+		errStr  string
+	}{
+		{
+			name:    "mixing consumer types",
+			streams: []string{"foo", "foo:1"},
+			errStr:  "failed to create input 'aws_kinesis': it is not currently possible to include balanced and explicit shard streams in the same kinesis input",
+		},
+		{
+			name:    "too many shards",
+			streams: []string{"foo:1:2"},
+			errStr:  "failed to create input 'aws_kinesis': stream 'foo:1:2' is invalid, only one shard should be specified and the same stream can be listed multiple times, e.g. use `foo:0,foo:1` not `foo:0:1`",
+		},
+		{
+			name:    "enhanced fan-out without consumer name",
+			streams: []string{"foo"},
+			efo:     true,
+			errStr:  "failed to create input 'aws_kinesis': a consumer_name must be specified when enhanced_fan_out is enabled",
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			conf := NewConfig()
+			conf.Type = TypeAWSKinesis
+			conf.AWSKinesis.Streams = test.streams
+			conf.AWSKinesis.EnhancedFanOut.Enabled = test.efo
+
+			_, err := New(conf, nil, log.Noop(), metrics.Noop())
+			assert.EqualError(t, err, test.errStr)
+		})
+	}
+}
+
+func TestAWSKinesisFinishedShards(t *testing.T) {
+	shard := func(id string, closed bool) *kinesis.Shard {
+		s := &kinesis.Shard{
+			ShardId: aws.String(id),
+			SequenceNumberRange: &kinesis.SequenceNumberRange{
+				StartingSequenceNumber: aws.String("1"),
+			},
+		}
+		if closed {
+			s.SequenceNumberRange.EndingSequenceNumber = aws.String("100")
+		}
+		return s
+	}
+
+	testCases := []struct {
+		name        string
+		shards      []*kinesis.Shard
+		checkpoints map[string]string
+		expected    []string
+	}{
+		{
+			name:   "no checkpoints",
+			shards: []*kinesis.Shard{shard("a", false), shard("b", false)},
+		},
+		{
+			name:        "shard end checkpoints",
+			shards:      []*kinesis.Shard{shard("a", true), shard("b", false)},
+			checkpoints: map[string]string{"a": awsKinesisShardEnd, "b": "50", "c": awsKinesisShardEnd},
+			expected:    []string{"a", "c"},
+		},
+		{
+			name:        "closed shard being consumed",
+			shards:      []*kinesis.Shard{shard("a", true), shard("b", false)},
+			checkpoints: map[string]string{"a": "50"},
+		},
+		{
+			name:        "closed shard claimed without a sequence",
+			shards:      []*kinesis.Shard{shard("a", true)},
+			checkpoints: map[string]string{"a": ""},
+		},
+		{
+			name:     "closed shard with a deleted checkpoint",
+			shards:   []*kinesis.Shard{shard("a", true), shard("b", false), shard("c", false)},
+			expected: []string{"a"},
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			expected := map[string]struct{}{}
+			for _, s := range test.expected {
+				expected[s] = struct{}{}
+			}
+			assert.Equal(t, expected, awsKinesisFinishedShards(test.shards, test.checkpoints))
+		})
+	}
+}
+
+func TestAWSKinesisUpgradedShards(t *testing.T) {
+	// Checkpoints of finished shards were previously deleted rather than
+	// marked with the end of the shard, and therefore a closed parent without
+	// a checkpoint must not be consumed again or block its children.
+	shards := []*kinesis.Shard{
+		{
+			ShardId: aws.String("a"),
+			SequenceNumberRange: &kinesis.SequenceNumberRange{
+				StartingSequenceNumber: aws.String("1"),
+				EndingSequenceNumber:   aws.String("100"),
+			},
+		},
+		{
+			ShardId:       aws.String("b"),
+			ParentShardId: aws.String("a"),
+			SequenceNumberRange: &kinesis.SequenceNumberRange{
+				StartingSequenceNumber: aws.String("101"),
+			},
+		},
+		{
+			ShardId:       aws.String("c"),
+			ParentShardId: aws.String("a"),
+			SequenceNumberRange: &kinesis.SequenceNumberRange{
+				StartingSequenceNumber: aws.String("102"),
+			},
+		},
+	}
+
+	finished := awsKinesisFinishedShards(shards, map[string]string{"b": "150"})
+	assert.Equal(t, map[string]string{"b": "", "c": ""}, awsKinesisConsumableShards(shards, finished))
+}
+
+func TestAWSKinesisConsumableShards(t *testing.T) {
+	shard := func(id, parent, adjacentParent string) *kinesis.Shard {
+		s := &kinesis.Shard{ShardId: aws.String(id)}
+		if parent != "" {
+			s.ParentShardId = aws.String(parent)
+		}
+		if adjacentParent != "" {
+			s.AdjacentParentShardId = aws.String(adjacentParent)
+		}
+		return s
+	}
+
+	testCases := []struct {
+		name     string
+		shards   []*kinesis.Shard
+		finished []string
+		expected []string
+	}{
+		{
+			name:     "no resharding",
+			shards:   []*kinesis.Shard{shard("a", "", ""), shard("b", "", "")},
+			expected: []string{"a", "b"},
+		},
+		{
+			name:     "split parent not finished",
+			shards:   []*kinesis.Shard{shard("a", "", ""), shard("b", "a", ""), shard("c", "a", "")},
+			expected: []string{"a"},
+		},
+		{
+			name:     "split parent finished",
+			shards:   []*kinesis.Shard{shard("a", "", ""), shard("b", "a", ""), shard("c", "a", "")},
+			finished: []string{"a"},
+			expected: []string{"b", "c"},
+		},
+		{
+			name:     "split parent expired",
+			shards:   []*kinesis.Shard{shard("b", "a", ""), shard("c", "a", "")},
+			expected: []string{"b", "c"},
+		},
+		{
+			name:     "merge adjacent parent not finished",
+			shards:   []*kinesis.Shard{shard("a", "", ""), shard("b", "", ""), shard("c", "a", "b")},
+			finished: []string{"a"},
+			expected: []string{"b"},
+		},
+		{
+			name:     "merge parents finished",
+			shards:   []*kinesis.Shard{shard("a", "", ""), shard("b", "", ""), shard("c", "a", "b")},
+			finished: []string{"a", "b"},
+			expected: []string{"c"},
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			finished := map[string]struct{}{}
+			for _, s := range test.finished {
+				finished[s] = struct{}{}
+			}
+			expected := map[string]string{}
+			for _, s := range test.expected {
+				expected[s] = ""
+			}
+			assert.Equal(t, expected, awsKinesisConsumableShards(test.shards, finished))
+		})
+	}
+}
@@ -51,7 +51,9 @@ Downloads objects within an Amazon S3 bucket, optionally filtered by a prefix, e
 
 A common pattern for consuming S3 objects is to emit upload notification events from the bucket either directly to an SQS queue, or to an SNS topic that is consumed by an SQS queue, and then have your consumer listen for events which prompt it to download the newly uploaded objects. More information about this pattern and how to set it up can be found at: https://docs.aws.amazon.com/AmazonS3/latest/dev/ways-to-add-notification-config-to-bucket.html.
 
-Benthos is able to follow this pattern when you configure an ` + "`sqs.url`" + `, where it consumes events from SQS and only downloads object keys received within those events. In order for this to work Benthos needs to know where within the event the key and bucket names can be found, specified as [dot paths](/docs/configuration/field_paths) with the fields ` + "`sqs.key_path` and `sqs.bucket_path`" + `. The default values for these fields should already be correct when following the guide above.
+Benthos is able to follow this pattern when you configure an ` + "`sqs.url`" + `, where it consumes events from SQS and only downloads object keys received within those events. In order for this to work Benthos needs to know where within the event the key and bucket names can be found, specified as [dot paths](/docs/configuration/field_paths) with the fields ` + "`sqs.key_path` and `sqs.bucket_path`" + `. The default values for these fields should already be correct when following the guide above. Object keys within these events are URL encoded, and are decoded before the object is downloaded.
+
+The test event that S3 sends to a queue when a notification configuration is first created does not reference any objects and is removed from the queue without being processed.
 
 If your notification events are being routed to SQS via an SNS topic then the events will be enveloped by SNS, in which case you also need to specify the field ` + "`sqs.envelope_path`" + `, which in the case of SNS to SQS will usually be ` + "`Message`" + `.
 
@@ -76,6 +78,8 @@ This input adds the following metadata fields to each message:
 - s3_last_modified (RFC3339)
 - s3_content_type
 - s3_content_encoding
+- s3_content_length
+- s3_etag
 - All user defined metadata
 ` + "```" + `
 
@@ -360,6 +364,10 @@ func digStrsFromSlices(slice []interface{}) []string {
 	return strs
 }
 
+// errS3TestEvent is returned when an SQS message contains the test event that
+// S3 emits when a bucket notification configuration is created.
+var errS3TestEvent = errors.New("message is an S3 test event")
+
 func (s *sqsTargetReader) parseObjectPaths(sqsMsg *string) ([]s3ObjectTarget, error) {
 	gObj, err := gabs.ParseJSON([]byte(*sqsMsg))
 	if err != nil {
@@ -377,6 +385,10 @@ func (s *sqsTargetReader) parseObjectPaths(sqsMsg *string) ([]s3ObjectTarget, er
 		}
 	}
 
+	if event, _ := gObj.Path("Event").Data().(string); event == "s3:TestEvent" {
+		return nil, errS3TestEvent
+	}
+
 	var keys []string
 	var buckets []string
 
@@ -456,6 +468,13 @@ func (s *sqsTargetReader) readSQSEvents(ctx context.Context) ([]*s3ObjectTarget,
 		}
 
 		objects, err := s.parseObjectPaths(sqsMsg.Body)
+		if errors.Is(err, errS3TestEvent) {
+			s.log.Debugln("Removing S3 test event from SQS queue")
+			if err = s.ackSQSMessage(ctx, sqsMsg); err != nil {
+				s.log.Errorf("Failed to remove S3 test event from SQS queue: %v\n", err)
+			}
+			continue
+		}
 		if err != nil {
 			addDudFn(sqsMsg)
 			s.log.Errorf("SQS extract key error: %v\n", err)
@@ -662,6 +681,12 @@ func s3MsgFromParts(p *s3PendingObject, parts []types.Part) types.Message {
 		if p.obj.ContentEncoding != nil {
 			meta.Set("s3_content_encoding", *p.obj.ContentEncoding)
 		}
+		if p.obj.ContentLength != nil {
+			meta.Set("s3_content_length", strconv.FormatInt(*p.obj.ContentLength, 10))
+		}
+		if p.obj.ETag != nil {
+			meta.Set("s3_etag", *p.obj.ETag)
+		}
 		for k, v := range p.obj.Metadata {
 			if v != nil {
 				meta.Set(k, *v)
@@ -0,0 +1,52 @@
+package input
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAWSS3SQSParseObjectPaths(t *testing.T) {
+	conf := NewAWSS3Config()
+	conf.SQS.URL = "http://example.com/queue"
+
+	r := newSQSTargetReader(conf, nil, nil, nil)
+
+	body := `{"Records":[
+  {"s3":{"bucket":{"name":"foo"},"object":{"key":"bar/baz+buz%3D.json","size":10}}},
+  {"s3":{"bucket":{"name":"qux"},"object":{"key":"quz.txt","size":20}}}
+]}`
+	objects, err := r.parseObjectPaths(&body)
+	require.NoError(t, err)
+	assert.Equal(t, []s3ObjectTarget{
+		{key: "bar/baz buz=.json", bucket: "foo"},
+		{key: "quz.txt", bucket: "qux"},
+	}, objects)
+}
+
+func TestAWSS3SQSParseObjectPathsEnvelope(t *testing.T) {
+	conf := NewAWSS3Config()
+	conf.SQS.URL = "http://example.com/queue"
+	conf.SQS.EnvelopePath = "Message"
+
+	r := newSQSTargetReader(conf, nil, nil, nil)
+
+	body := `{"Type":"Notification","Message":"{\"Records\":[{\"s3\":{\"bucket\":{\"name\":\"foo\"},\"object\":{\"key\":\"bar%2Fbaz.json\"}}}]}"}`
+	objects, err := r.parseObjectPaths(&body)
+	require.NoError(t, err)
+	assert.Equal(t, []s3ObjectTarget{
+		{key: "bar/baz.json", bucket: "foo"},
+	}, objects)
+}
+
+func TestAWSS3SQSParseObjectPathsTestEvent(t *testing.T) {
+	conf := NewAWSS3Config()
+	conf.SQS.URL = "http://example.com/queue"
+
+	r := newSQSTargetReader(conf, nil, nil, nil)
+
+	body := `{"Service":"Amazon S3","Event":"s3:TestEvent","Time":"2021-06-01T12:00:00.000Z","Bucket":"foo","RequestId":"abc","HostId":"def"}`
+	_, err := r.parseObjectPaths(&body)
+	assert.ErrorIs(t, err, errS3TestEvent)
+}
@@ -60,15 +60,15 @@ You can access these metadata fields using [function interpolation](/docs/config
 			docs.FieldCommon(
 				"storage_access_key",
 				"The storage account access key. This field is ignored if `storage_connection_string` is set.",
-			),
+			).Secret(),
 			docs.FieldCommon(
 				"storage_sas_token",
 				"The storage account SAS token. This field is ignored if `storage_connection_string` or `storage_access_key` are set.",
-			).AtVersion("3.38.0"),
+			).Secret().AtVersion("3.38.0"),
 			docs.FieldCommon(
 				"storage_connection_string",
 				"A storage account connection string. This field is required if `storage_account` and `storage_access_key` / `storage_sas_token` are not set.",
-			),
+			).Secret(),
 			docs.FieldCommon(
 				"container", "The name of the container from which to download blobs.",
 			),
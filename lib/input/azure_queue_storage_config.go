@@ -41,15 +41,15 @@ Only one authentication method is required, ` + "`storage_connection_string`" +
 			docs.FieldCommon(
 				"storage_access_key",
 				"The storage account access key. This field is ignored if `storage_connection_string` is set.",
-			),
+			).Secret(),
 			docs.FieldCommon(
 				"storage_sas_token",
 				"The storage account SAS token. This field is ignored if `storage_connection_string` or `storage_access_key` are set.",
-			),
+			).Secret(),
 			docs.FieldCommon(
 				"storage_connection_string",
 				"A storage account connection string. This field is required if `storage_account` and `storage_access_key` / `storage_sas_token` are not set.",
-			),
+			).Secret(),
 			docs.FieldCommon(
 				"queue_name", "The name of the target Storage queue.",
 			),
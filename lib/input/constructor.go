@@ -45,6 +45,10 @@ type TypeSpec struct {
 	config      docs.FieldSpec
 	FieldSpecs  docs.FieldSpecs
 	Examples    []docs.AnnotatedExample
+
+	// ReplacedBy describes the component that replaces this one when it
+	// is deprecated.
+	ReplacedBy *docs.ComponentReplacement
 }
 
 // ConstructorFunc is a func signature able to construct an input.
@@ -70,6 +74,7 @@ func WalkConstructors(fn func(ConstructorFunc, docs.ComponentSpec)) {
 			Examples:    v.Examples,
 			Status:      v.Status,
 			Version:     v.Version,
+			ReplacedBy:  v.ReplacedBy,
 		}
 		if len(v.Categories) > 0 {
 			spec.Categories = make([]string, 0, len(v.Categories))
@@ -244,6 +249,7 @@ const (
 	TypeSFTP              = "sftp"
 	TypeSocket            = "socket"
 	TypeSocketServer      = "socket_server"
+	TypeSQLSelect         = "sql_select"
 	TypeSQS               = "sqs"
 	TypeSTDIN             = "stdin"
 	TypeSubprocess        = "subprocess"
@@ -303,6 +309,7 @@ type Config struct {
 	SFTP              SFTPConfig                   `json:"sftp" yaml:"sftp"`
 	Socket            SocketConfig                 `json:"socket" yaml:"socket"`
 	SocketServer      SocketServerConfig           `json:"socket_server" yaml:"socket_server"`
+	SQLSelect         SQLSelectConfig              `json:"sql_select" yaml:"sql_select"`
 	SQS               reader.AmazonSQSConfig       `json:"sqs" yaml:"sqs"`
 	STDIN             STDINConfig                  `json:"stdin" yaml:"stdin"`
 	Subprocess        SubprocessConfig             `json:"subprocess" yaml:"subprocess"`
@@ -362,6 +369,7 @@ func NewConfig() Config {
 		SFTP:              NewSFTPConfig(),
 		Socket:            NewSocketConfig(),
 		SocketServer:      NewSocketServerConfig(),
+		SQLSelect:         NewSQLSelectConfig(),
 		SQS:               reader.NewAmazonSQSConfig(),
 		STDIN:             NewSTDINConfig(),
 		Subprocess:        NewSubprocessConfig(),
@@ -49,6 +49,7 @@ testing your pipeline configs.`,
 				"@every 1s", "0,30 */2 * * * *", "TZ=Europe/London 30 3-6,20-23 * * *",
 			),
 			docs.FieldCommon("count", "An optional number of messages to generate, if set above 0 the specified number of messages is generated and then the input will shut down."),
+			docs.FieldAdvanced("batch_size", "The number of generated messages that should be accumulated into each batch flushed at the specified interval.").AtVersion("3.55.0"),
 		},
 		Categories: []Category{
 			CategoryUtility,
@@ -90,6 +91,41 @@ input:
           "bar": "is gross"
         }
       }
+`,
+			},
+			{
+				Title:   "Load Testing",
+				Summary: "Combining the generate input with the [`fake` function](/docs/guides/bloblang/functions#fake) allows you to produce realistic looking data at a high rate without depending on a source of test data. The following example produces batches of 100 fake user records as fast as the output is able to consume them.",
+				Config: `
+input:
+  generate:
+    interval: ""
+    batch_size: 100
+    mapping: |
+      root.id = uuid_v4()
+      root.user.name = fake("name")
+      root.user.email = fake("email")
+      root.user.ip = fake("ipv4")
+      root.created_at = now()
+`,
+			},
+			{
+				Title:   "Heartbeats",
+				Summary: "A generate input can be placed within a [`broker`](/docs/components/inputs/broker) alongside a real source in order to emit a heartbeat message at a regular interval, which is useful for keeping downstream consumers aware that a pipeline is alive even when there is no data flowing.",
+				Config: `
+input:
+  broker:
+    inputs:
+      - kafka:
+          addresses: [ localhost:9092 ]
+          topics: [ events ]
+          consumer_group: benthos
+      - generate:
+          interval: 30s
+          mapping: |
+            root.type = "heartbeat"
+            root.host = hostname()
+            root.timestamp = now()
 `,
 			},
 		},
@@ -104,6 +140,9 @@ input:
 			return NewAsyncReader(TypeBloblang, true, b, log, stats)
 		}),
 		Status: docs.StatusDeprecated,
+		ReplacedBy: &docs.ComponentReplacement{
+			Name: TypeGenerate,
+		},
 		Summary: `
 Generates messages at a given interval using a [Bloblang](/docs/guides/bloblang/about)
 mapping executed without a context. This allows you to generate messages for
@@ -126,6 +165,7 @@ This input has been ` + "[renamed to `generate`](/docs/components/inputs/generat
 				"@every 1s", "0,30 */2 * * * *", "30 3-6,20-23 * * *",
 			),
 			docs.FieldCommon("count", "An optional number of messages to generate, if set above 0 the specified number of messages is generated and then the input will shut down."),
+			docs.FieldAdvanced("batch_size", "The number of generated messages that should be accumulated into each batch flushed at the specified interval."),
 		},
 		Categories: []Category{
 			CategoryUtility,
@@ -139,16 +179,18 @@ This input has been ` + "[renamed to `generate`](/docs/components/inputs/generat
 type BloblangConfig struct {
 	Mapping string `json:"mapping" yaml:"mapping"`
 	// internal can be both duration string or cron expression
-	Interval string `json:"interval" yaml:"interval"`
-	Count    int    `json:"count" yaml:"count"`
+	Interval  string `json:"interval" yaml:"interval"`
+	Count     int    `json:"count" yaml:"count"`
+	BatchSize int    `json:"batch_size" yaml:"batch_size"`
 }
 
 // NewBloblangConfig creates a new BloblangConfig with default values.
 func NewBloblangConfig() BloblangConfig {
 	return BloblangConfig{
-		Mapping:  "",
-		Interval: "1s",
-		Count:    0,
+		Mapping:   "",
+		Interval:  "1s",
+		Count:     0,
+		BatchSize: 1,
 	}
 }
 
@@ -158,6 +200,7 @@ func NewBloblangConfig() BloblangConfig {
 type Bloblang struct {
 	remaining   int64
 	limited     bool
+	batchSize   int64
 	firstIsFree bool
 	exec        *mapping.Executor
 	timer       *time.Ticker
@@ -195,11 +238,15 @@ func newBloblang(conf BloblangConfig) (*Bloblang, error) {
 		}
 		return nil, fmt.Errorf("failed to parse mapping: %v", err)
 	}
+	if conf.BatchSize < 1 {
+		return nil, fmt.Errorf("batch size must be greater than zero, got %v", conf.BatchSize)
+	}
 	remaining := int64(conf.Count)
 	return &Bloblang{
 		exec:        exec,
 		remaining:   remaining,
 		limited:     remaining > 0,
+		batchSize:   int64(conf.BatchSize),
 		timer:       timer,
 		schedule:    schedule,
 		location:    location,
@@ -243,10 +290,15 @@ func (b *Bloblang) ConnectWithContext(ctx context.Context) error {
 
 // ReadWithContext a new bloblang generated message.
 func (b *Bloblang) ReadWithContext(ctx context.Context) (types.Message, reader.AsyncAckFn, error) {
+	batchSize := b.batchSize
 	if b.limited {
-		if remaining := atomic.AddInt64(&b.remaining, -1); remaining < 0 {
+		remaining := atomic.AddInt64(&b.remaining, -batchSize)
+		if remaining+batchSize <= 0 {
 			return nil, nil, types.ErrTypeClosed
 		}
+		if remaining < 0 {
+			batchSize += remaining
+		}
 	}
 
 	if !b.firstIsFree && b.timer != nil {
@@ -264,17 +316,20 @@ func (b *Bloblang) ReadWithContext(ctx context.Context) (types.Message, reader.A
 	}
 
 	b.firstIsFree = false
-	p, err := b.exec.MapPart(0, message.New(nil))
-	if err != nil {
-		return nil, nil, err
+	msg := message.New(nil)
+	for i := int64(0); i < batchSize; i++ {
+		p, err := b.exec.MapPart(0, message.New(nil))
+		if err != nil {
+			return nil, nil, err
+		}
+		if p != nil {
+			msg.Append(p)
+		}
 	}
-	if p == nil {
+	if msg.Len() == 0 {
 		return nil, nil, types.ErrTimeout
 	}
 
-	msg := message.New(nil)
-	msg.Append(p)
-
 	return msg, func(context.Context, types.Response) error { return nil }, nil
 }
 
@@ -129,3 +129,32 @@ func TestBloblangRemaining(t *testing.T) {
 	_, _, err = b.ReadWithContext(ctx)
 	assert.EqualError(t, err, "type was closed")
 }
+
+func TestBloblangBatchSize(t *testing.T) {
+	ctx, done := context.WithTimeout(context.Background(), time.Millisecond*100)
+	defer done()
+
+	conf := NewBloblangConfig()
+	conf.Mapping = `root = count("batches")`
+	conf.Interval = ""
+	conf.Count = 5
+	conf.BatchSize = 2
+
+	b, err := newBloblang(conf)
+	require.NoError(t, err)
+
+	err = b.ConnectWithContext(ctx)
+	require.NoError(t, err)
+
+	for _, exp := range [][]string{{"1", "2"}, {"3", "4"}, {"5"}} {
+		m, _, err := b.ReadWithContext(ctx)
+		require.NoError(t, err)
+		require.Equal(t, len(exp), m.Len())
+		for i, v := range exp {
+			assert.Equal(t, v, string(m.Get(i).Get()))
+		}
+	}
+
+	_, _, err = b.ReadWithContext(ctx)
+	assert.EqualError(t, err, "type was closed")
+}
@@ -1,6 +1,7 @@
 package input
 
 import (
+	"bufio"
 	"bytes"
 	"context"
 	"errors"
@@ -49,11 +50,13 @@ HTTP server.
 
 The field ` + "`rate_limit`" + ` allows you to specify an optional
 ` + "[`rate_limit` resource](/docs/components/rate_limits/about)" + `, which
-will be applied to each HTTP request made and each websocket payload received.
+will be applied to each HTTP request made, each websocket payload received and
+each server-sent event received.
 
 When the rate limit is breached HTTP requests will have a 429 response returned
 with a Retry-After header. Websocket payloads will be dropped and an optional
-response payload will be sent as per ` + "`ws_rate_limit_message`" + `.
+response payload will be sent as per ` + "`ws_rate_limit_message`" + `. Event
+streams are paused until the rate limit allows more events to be consumed.
 
 ### Responses
 
@@ -89,6 +92,21 @@ established.
 It's also possible to specify a ` + "`ws_rate_limit_message`" + `, which is a
 static payload to be sent to clients that have triggered the servers rate limit.
 
+Synchronous responses are sent back to the client over the websocket
+connection, with each message of the response sent as an individual payload.
+
+#### ` + "`sse_path` (disabled by default)" + `
+
+This endpoint expects requests where the body is a stream of
+[server-sent events](https://html.spec.whatwg.org/multipage/server-sent-events.html)
+(` + "`text/event-stream`" + `), where the data of each event received is passed
+through the pipeline as a batch of one message. The event type and identifier
+are added to messages as the metadata fields ` + "`http_server_sse_event`" + ` and
+` + "`http_server_sse_id`" + ` respectively.
+
+Events are delivered in order, and a response is returned once the event stream
+has ended and all events have been delivered.
+
 ### Metadata
 
 This input adds the following metadata fields to each message:
@@ -97,6 +115,8 @@ This input adds the following metadata fields to each message:
 - http_server_user_agent
 - http_server_request_path
 - http_server_verb
+- http_server_sse_event (server-sent events only)
+- http_server_sse_id (server-sent events only)
 - All headers (only first values are taken)
 - All query parameters
 - All path parameters
@@ -111,6 +131,7 @@ You can access these metadata fields using
 			docs.FieldCommon("ws_path", "The endpoint path to create websocket connections from."),
 			docs.FieldAdvanced("ws_welcome_message", "An optional message to deliver to fresh websocket connections."),
 			docs.FieldAdvanced("ws_rate_limit_message", "An optional message to delivery to websocket connections that are rate limited."),
+			docs.FieldAdvanced("sse_path", "An optional endpoint path to receive streams of server-sent events from.", "/post/sse").AtVersion("3.55.0"),
 			docs.FieldCommon("allowed_verbs", "An array of verbs that are allowed for the `path` endpoint.").AtVersion("3.33.0").Array(),
 			docs.FieldCommon("timeout", "Timeout for requests. If a consumed messages takes longer than this to be delivered the connection is closed, but the message may still be delivered."),
 			docs.FieldCommon("rate_limit", "An optional [rate limit](/docs/components/rate_limits/about) to throttle requests by."),
@@ -159,6 +180,7 @@ type HTTPServerConfig struct {
 	WSPath             string                   `json:"ws_path" yaml:"ws_path"`
 	WSWelcomeMessage   string                   `json:"ws_welcome_message" yaml:"ws_welcome_message"`
 	WSRateLimitMessage string                   `json:"ws_rate_limit_message" yaml:"ws_rate_limit_message"`
+	SSEPath            string                   `json:"sse_path" yaml:"sse_path"`
 	AllowedVerbs       []string                 `json:"allowed_verbs" yaml:"allowed_verbs"`
 	Timeout            string                   `json:"timeout" yaml:"timeout"`
 	RateLimit          string                   `json:"rate_limit" yaml:"rate_limit"`
@@ -175,6 +197,7 @@ func NewHTTPServerConfig() HTTPServerConfig {
 		WSPath:             "/post/ws",
 		WSWelcomeMessage:   "",
 		WSRateLimitMessage: "",
+		SSEPath:            "",
 		AllowedVerbs: []string{
 			"POST",
 		},
@@ -221,6 +244,7 @@ type HTTPServer struct {
 	mRcvd          metrics.StatCounter
 	mPartsRcvd     metrics.StatCounter
 	mWSCount       metrics.StatCounter
+	mSSECount      metrics.StatCounter
 	mTimeout       metrics.StatCounter
 	mErr           metrics.StatCounter
 	mWSErr         metrics.StatCounter
@@ -277,6 +301,7 @@ func NewHTTPServer(conf Config, mgr types.Manager, log log.Modular, stats metric
 		mRcvd:          stats.GetCounter("batch.received"),
 		mPartsRcvd:     stats.GetCounter("received"),
 		mWSCount:       stats.GetCounter("ws.count"),
+		mSSECount:      stats.GetCounter("sse.count"),
 		mTimeout:       stats.GetCounter("send.timeout"),
 		mErr:           stats.GetCounter("send.error"),
 		mWSErr:         stats.GetCounter("ws.send.error"),
@@ -298,6 +323,7 @@ func NewHTTPServer(conf Config, mgr types.Manager, log log.Modular, stats metric
 
 	postHdlr := httputil.GzipHandler(h.postHandler)
 	wsHdlr := httputil.GzipHandler(h.wsHandler)
+	sseHdlr := httputil.GzipHandler(h.sseHandler)
 	if mux != nil {
 		if len(h.conf.Path) > 0 {
 			mux.HandleFunc(h.conf.Path, postHdlr)
@@ -305,6 +331,9 @@ func NewHTTPServer(conf Config, mgr types.Manager, log log.Modular, stats metric
 		if len(h.conf.WSPath) > 0 {
 			mux.HandleFunc(h.conf.WSPath, wsHdlr)
 		}
+		if len(h.conf.SSEPath) > 0 {
+			mux.HandleFunc(h.conf.SSEPath, sseHdlr)
+		}
 	} else {
 		if len(h.conf.Path) > 0 {
 			mgr.RegisterEndpoint(
@@ -316,6 +345,11 @@ func NewHTTPServer(conf Config, mgr types.Manager, log log.Modular, stats metric
 				h.conf.WSPath, "Post messages via websocket into Benthos.", wsHdlr,
 			)
 		}
+		if len(h.conf.SSEPath) > 0 {
+			mgr.RegisterEndpoint(
+				h.conf.SSEPath, "Post a stream of server-sent events into Benthos.", sseHdlr,
+			)
+		}
 	}
 
 	if h.conf.RateLimit != "" {
@@ -330,6 +364,28 @@ func NewHTTPServer(conf Config, mgr types.Manager, log log.Modular, stats metric
 
 //------------------------------------------------------------------------------
 
+func setRequestMetadata(meta types.Metadata, r *http.Request) {
+	meta.Set("http_server_user_agent", r.UserAgent())
+	meta.Set("http_server_request_path", r.URL.Path)
+	meta.Set("http_server_verb", r.Method)
+	for k, v := range r.Header {
+		if len(v) > 0 {
+			meta.Set(k, v[0])
+		}
+	}
+	for k, v := range r.URL.Query() {
+		if len(v) > 0 {
+			meta.Set(k, v[0])
+		}
+	}
+	for k, v := range mux.Vars(r) {
+		meta.Set(k, v)
+	}
+	for _, c := range r.Cookies() {
+		meta.Set(c.Name, c.Value)
+	}
+}
+
 func (h *HTTPServer) extractMessageFromRequest(r *http.Request) (types.Message, error) {
 	msg := message.New(nil)
 
@@ -368,25 +424,7 @@ func (h *HTTPServer) extractMessageFromRequest(r *http.Request) (types.Message,
 	}
 
 	meta := metadata.New(nil)
-	meta.Set("http_server_user_agent", r.UserAgent())
-	meta.Set("http_server_request_path", r.URL.Path)
-	meta.Set("http_server_verb", r.Method)
-	for k, v := range r.Header {
-		if len(v) > 0 {
-			meta.Set(k, v[0])
-		}
-	}
-	for k, v := range r.URL.Query() {
-		if len(v) > 0 {
-			meta.Set(k, v[0])
-		}
-	}
-	for k, v := range mux.Vars(r) {
-		meta.Set(k, v)
-	}
-	for _, c := range r.Cookies() {
-		meta.Set(c.Name, c.Value)
-	}
+	setRequestMetadata(meta, r)
 	message.SetAllMetadata(msg, meta)
 
 	// Try to either extract parent span from headers, or create a new one.
@@ -618,24 +656,7 @@ func (h *HTTPServer) wsHandler(w http.ResponseWriter, r *http.Request) {
 
 		msg := message.New([][]byte{msgBytes})
 
-		meta := msg.Get(0).Metadata()
-		meta.Set("http_server_user_agent", r.UserAgent())
-		for k, v := range r.Header {
-			if len(v) > 0 {
-				meta.Set(k, v[0])
-			}
-		}
-		for k, v := range r.URL.Query() {
-			if len(v) > 0 {
-				meta.Set(k, v[0])
-			}
-		}
-		for k, v := range mux.Vars(r) {
-			meta.Set(k, v)
-		}
-		for _, c := range r.Cookies() {
-			meta.Set(c.Name, c.Value)
-		}
+		setRequestMetadata(msg.Get(0).Metadata(), r)
 		tracing.InitSpans("input_http_server_websocket", msg)
 
 		store := roundtrip.NewResultStore()
@@ -679,6 +700,174 @@ func (h *HTTPServer) wsHandler(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
+// sseEvent is a single event parsed from a text/event-stream.
+type sseEvent struct {
+	name string
+	id   string
+	data []byte
+}
+
+// readSSEEvent reads the next event containing data from a text/event-stream,
+// skipping comments and events without data. An event that isn't terminated by
+// a blank line before the end of the stream is still returned.
+func readSSEEvent(rdr *bufio.Reader) (sseEvent, error) {
+	event := sseEvent{name: "message"}
+	var hasData bool
+	for {
+		line, err := rdr.ReadBytes('\n')
+		if len(line) == 0 && err != nil {
+			if err == io.EOF && hasData {
+				return event, nil
+			}
+			return sseEvent{}, err
+		}
+		line = bytes.TrimSuffix(bytes.TrimSuffix(line, []byte("\n")), []byte("\r"))
+
+		if len(line) == 0 {
+			if hasData {
+				return event, nil
+			}
+			event = sseEvent{name: "message"}
+			continue
+		}
+		if line[0] == ':' {
+			continue
+		}
+
+		field, value := line, []byte(nil)
+		if i := bytes.IndexByte(line, ':'); i >= 0 {
+			field, value = line[:i], bytes.TrimPrefix(line[i+1:], []byte(" "))
+		}
+		switch string(field) {
+		case "event":
+			event.name = string(value)
+		case "id":
+			event.id = string(value)
+		case "data":
+			if hasData {
+				event.data = append(event.data, '\n')
+			}
+			event.data = append(event.data, value...)
+			hasData = true
+		}
+	}
+}
+
+// awaitSSERateLimit blocks until the rate limit permits another event to be
+// consumed, returning false if the event stream should be abandoned.
+func (h *HTTPServer) awaitSSERateLimit(w http.ResponseWriter, r *http.Request) bool {
+	for {
+		var tUntil time.Duration
+		var err error
+		if rerr := interop.AccessRateLimit(r.Context(), h.mgr, h.conf.RateLimit, func(rl types.RateLimit) {
+			tUntil, err = rl.Access()
+		}); rerr != nil {
+			err = rerr
+		}
+		if err != nil {
+			http.Error(w, "Server error", http.StatusBadGateway)
+			h.log.Warnf("Failed to access rate limit: %v\n", err)
+			return false
+		}
+		if tUntil <= 0 {
+			return true
+		}
+		h.mRateLimited.Incr(1)
+		select {
+		case <-time.After(tUntil):
+		case <-r.Context().Done():
+			return false
+		case <-h.shutSig.CloseAtLeisureChan():
+			http.Error(w, "Server closing", http.StatusServiceUnavailable)
+			return false
+		}
+	}
+}
+
+func (h *HTTPServer) sseHandler(w http.ResponseWriter, r *http.Request) {
+	h.handlerWG.Add(1)
+	defer h.handlerWG.Done()
+	defer r.Body.Close()
+
+	if _, exists := h.allowedVerbs[r.Method]; !exists {
+		http.Error(w, "Incorrect method", http.StatusMethodNotAllowed)
+		return
+	}
+
+	resChan := make(chan types.Response, 1)
+	throt := throttle.New(throttle.OptCloseChan(h.shutSig.CloseAtLeisureChan()))
+	rdr := bufio.NewReader(r.Body)
+
+	for {
+		event, err := readSSEEvent(rdr)
+		if err != nil {
+			if err != io.EOF {
+				http.Error(w, "Bad request", http.StatusBadRequest)
+				h.log.Warnf("Event stream read failed: %v\n", err)
+			}
+			return
+		}
+		h.mSSECount.Incr(1)
+		h.mCount.Incr(1)
+
+		if h.conf.RateLimit != "" && !h.awaitSSERateLimit(w, r) {
+			return
+		}
+
+		msg := message.New([][]byte{event.data})
+		meta := msg.Get(0).Metadata()
+		setRequestMetadata(meta, r)
+		meta.Set("http_server_sse_event", event.name)
+		if len(event.id) > 0 {
+			meta.Set("http_server_sse_id", event.id)
+		}
+		tracing.InitSpans("input_http_server_sse", msg)
+
+		h.mPartsRcvd.Incr(1)
+		h.mRcvd.Incr(1)
+
+		for delivered := false; !delivered; {
+			select {
+			case h.transactions <- types.NewTransaction(msg, resChan):
+			case <-r.Context().Done():
+				tracing.FinishSpans(msg)
+				return
+			case <-h.shutSig.CloseAtLeisureChan():
+				tracing.FinishSpans(msg)
+				http.Error(w, "Server closing", http.StatusServiceUnavailable)
+				return
+			}
+			select {
+			case res, open := <-resChan:
+				if !open {
+					tracing.FinishSpans(msg)
+					http.Error(w, "Server closing", http.StatusServiceUnavailable)
+					return
+				}
+				if res.Error() != nil {
+					h.mErr.Incr(1)
+					if !throt.Retry() {
+						tracing.FinishSpans(msg)
+						http.Error(w, "Server closing", http.StatusServiceUnavailable)
+						return
+					}
+				} else {
+					tTaken := time.Since(msg.CreatedAt()).Nanoseconds()
+					h.mLatency.Timing(tTaken)
+					h.mSucc.Incr(1)
+					delivered = true
+					throt.Reset()
+				}
+			case <-h.shutSig.CloseNowChan():
+				tracing.FinishSpans(msg)
+				http.Error(w, "Server closing", http.StatusServiceUnavailable)
+				return
+			}
+		}
+		tracing.FinishSpans(msg)
+	}
+}
+
 //------------------------------------------------------------------------------
 
 func (h *HTTPServer) loop() {
@@ -696,6 +885,9 @@ func (h *HTTPServer) loop() {
 			if len(h.conf.WSPath) > 0 {
 				h.mgr.RegisterEndpoint(h.conf.WSPath, "Does nothing.", http.NotFound)
 			}
+			if len(h.conf.SSEPath) > 0 {
+				h.mgr.RegisterEndpoint(h.conf.SSEPath, "Does nothing.", http.NotFound)
+			}
 		}
 
 		h.handlerWG.Wait()
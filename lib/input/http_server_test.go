@@ -679,6 +679,94 @@ func TestHTTPServerWebsockets(t *testing.T) {
 	}
 }
 
+func TestHTTPServerSSE(t *testing.T) {
+	t.Parallel()
+
+	reg := apiRegGorillaMutWrapper{mut: mux.NewRouter()}
+	mgr, err := manager.New(manager.NewConfig(), reg, log.Noop(), metrics.Noop())
+	require.NoError(t, err)
+
+	conf := input.NewConfig()
+	conf.HTTPServer.SSEPath = "/testsse"
+
+	h, err := input.NewHTTPServer(conf, mgr, log.Noop(), metrics.Noop())
+	require.NoError(t, err)
+
+	server := httptest.NewServer(reg.mut)
+	defer server.Close()
+
+	body := ": a comment\n" +
+		"data: hello world 1\n\n" +
+		"event: greeting\r\n" +
+		"id: 2\r\n" +
+		"data: hello\r\n" +
+		"data: world 2\r\n\r\n" +
+		"event: ignored\n\n" +
+		"data:hello world 3"
+
+	resChan := make(chan int, 1)
+	go func() {
+		res, cerr := http.Post(server.URL+"/testsse?foo=bar", "text/event-stream", bytes.NewBufferString(body))
+		require.NoError(t, cerr)
+		res.Body.Close()
+		resChan <- res.StatusCode
+	}()
+
+	type expectedEvent struct {
+		data, event, id string
+	}
+	for i, exp := range []expectedEvent{
+		{data: "hello world 1", event: "message"},
+		{data: "hello\nworld 2", event: "greeting", id: "2"},
+		{data: "hello world 3", event: "message"},
+	} {
+		var ts types.Transaction
+		select {
+		case ts = <-h.TransactionChan():
+		case <-time.After(time.Second * 5):
+			t.Fatalf("Timed out waiting for message %v", i)
+		}
+		require.Equal(t, 1, ts.Payload.Len())
+		part := ts.Payload.Get(0)
+		assert.Equal(t, exp.data, string(part.Get()))
+		assert.Equal(t, exp.event, part.Metadata().Get("http_server_sse_event"))
+		assert.Equal(t, exp.id, part.Metadata().Get("http_server_sse_id"))
+		assert.Equal(t, "/testsse", part.Metadata().Get("http_server_request_path"))
+		assert.Equal(t, "bar", part.Metadata().Get("foo"))
+
+		if i == 1 {
+			// Rejected events should be delivered again.
+			select {
+			case ts.ResponseChan <- response.NewError(errors.New("nope")):
+			case <-time.After(time.Second * 5):
+				t.Fatal("Timed out waiting for response")
+			}
+			select {
+			case ts = <-h.TransactionChan():
+			case <-time.After(time.Second * 5):
+				t.Fatal("Timed out waiting for redelivered message")
+			}
+			assert.Equal(t, exp.data, string(ts.Payload.Get(0).Get()))
+		}
+
+		select {
+		case ts.ResponseChan <- response.NewAck():
+		case <-time.After(time.Second * 5):
+			t.Fatal("Timed out waiting for response")
+		}
+	}
+
+	select {
+	case code := <-resChan:
+		assert.Equal(t, http.StatusOK, code)
+	case <-time.After(time.Second * 5):
+		t.Fatal("Timed out waiting for event stream response")
+	}
+
+	h.CloseAsync()
+	require.NoError(t, h.WaitForClose(time.Second*5))
+}
+
 func TestHTTPServerWSRateLimit(t *testing.T) {
 	t.Parallel()
 
@@ -17,6 +17,9 @@ func init() {
 	Constructors[TypeKafkaBalanced] = TypeSpec{
 		constructor: fromBatchAwareConstructor(newKafkaBalancedHasBatchProcessor),
 		Status:      docs.StatusDeprecated,
+		ReplacedBy: &docs.ComponentReplacement{
+			Name: TypeKafka,
+		},
 		Summary: `
 Connects to Kafka brokers and consumes topics by automatically sharing
 partitions across other consumers of the same consumer group.`,
@@ -40,7 +40,7 @@ You can access these metadata fields using
 			docs.FieldAdvanced("clean_session", "Set whether the connection is non-persistent."),
 			mqttconf.WillFieldSpec(),
 			docs.FieldAdvanced("user", "A username to assume for the connection."),
-			docs.FieldAdvanced("password", "A password to provide for the connection."),
+			docs.FieldAdvanced("password", "A password to provide for the connection.").Secret(),
 			docs.FieldAdvanced("keepalive", "Max seconds of inactivity before a keepalive message is sent."),
 			tls.FieldSpec().AtVersion("3.45.0"),
 			docs.FieldDeprecated("stale_connection_timeout"),
@@ -2,7 +2,9 @@ package reader
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"strconv"
 	"strings"
 	"sync"
 	"time"
@@ -19,6 +21,28 @@ import (
 
 //------------------------------------------------------------------------------
 
+// RedisStreamsClaimConfig contains configuration fields for claiming the
+// pending messages of other consumers of a group.
+type RedisStreamsClaimConfig struct {
+	Enabled          bool   `json:"enabled" yaml:"enabled"`
+	Period           string `json:"period" yaml:"period"`
+	MinIdle          string `json:"min_idle" yaml:"min_idle"`
+	MaxDeliveries    int64  `json:"max_deliveries" yaml:"max_deliveries"`
+	DeadLetterStream string `json:"dead_letter_stream" yaml:"dead_letter_stream"`
+}
+
+// NewRedisStreamsClaimConfig creates a new RedisStreamsClaimConfig with
+// default values.
+func NewRedisStreamsClaimConfig() RedisStreamsClaimConfig {
+	return RedisStreamsClaimConfig{
+		Enabled:          false,
+		Period:           "30s",
+		MinIdle:          "1m",
+		MaxDeliveries:    0,
+		DeadLetterStream: "",
+	}
+}
+
 // RedisStreamsConfig contains configuration fields for the RedisStreams input
 // type.
 type RedisStreamsConfig struct {
@@ -33,6 +57,8 @@ type RedisStreamsConfig struct {
 	CommitPeriod    string   `json:"commit_period" yaml:"commit_period"`
 	Timeout         string   `json:"timeout" yaml:"timeout"`
 
+	Claim RedisStreamsClaimConfig `json:"claim" yaml:"claim"`
+
 	// TODO: V4 remove this.
 	Batching batch.PolicyConfig `json:"batching" yaml:"batching"`
 }
@@ -51,6 +77,7 @@ func NewRedisStreamsConfig() RedisStreamsConfig {
 		StartFromOldest: true,
 		CommitPeriod:    "1s",
 		Timeout:         "1s",
+		Claim:           NewRedisStreamsClaimConfig(),
 	}
 }
 
@@ -71,6 +98,8 @@ type RedisStreams struct {
 
 	timeout      time.Duration
 	commitPeriod time.Duration
+	claimPeriod  time.Duration
+	claimMinIdle time.Duration
 
 	conf RedisStreamsConfig
 
@@ -125,6 +154,22 @@ func NewRedisStreams(
 		}
 	}
 
+	if conf.Claim.Enabled {
+		var err error
+		if r.claimPeriod, err = time.ParseDuration(conf.Claim.Period); err != nil {
+			return nil, fmt.Errorf("failed to parse claim period string: %v", err)
+		}
+		if r.claimPeriod <= 0 {
+			return nil, errors.New("claim period must be greater than zero")
+		}
+		if r.claimMinIdle, err = time.ParseDuration(conf.Claim.MinIdle); err != nil {
+			return nil, fmt.Errorf("failed to parse claim min idle string: %v", err)
+		}
+		if conf.Claim.DeadLetterStream != "" && conf.Claim.MaxDeliveries <= 0 {
+			return nil, errors.New("a claim dead letter stream requires max deliveries to be set")
+		}
+	}
+
 	go r.loop()
 	return r, nil
 }
@@ -144,11 +189,22 @@ func (r *RedisStreams) loop() {
 		close(r.closedChan)
 	}()
 	commitTimer := time.NewTicker(r.commitPeriod)
+	defer commitTimer.Stop()
+
+	var claimChan <-chan time.Time
+	if r.claimPeriod > 0 {
+		claimTimer := time.NewTicker(r.claimPeriod)
+		defer claimTimer.Stop()
+		claimChan = claimTimer.C
+	}
 
 	closed := false
 	for !closed {
 		select {
 		case <-commitTimer.C:
+		case <-claimChan:
+			r.claimPending()
+			continue
 		case <-r.closeChan:
 			closed = true
 		}
@@ -194,6 +250,170 @@ func (r *RedisStreams) sendAcks() {
 
 //------------------------------------------------------------------------------
 
+// The maximum number of pending entries to inspect with each XPENDING call.
+const redisStreamsClaimPageSize = 100
+
+// nextRedisStreamID returns the smallest possible stream ID that is greater
+// than the provided ID.
+func nextRedisStreamID(id string) (string, bool) {
+	i := strings.LastIndex(id, "-")
+	if i == -1 {
+		return "", false
+	}
+	seq, err := strconv.ParseUint(id[i+1:], 10, 64)
+	if err != nil {
+		return "", false
+	}
+	return id[:i+1] + strconv.FormatUint(seq+1, 10), true
+}
+
+// claimPending walks the pending entries list of each stream and claims the
+// messages of other consumers that have been idle for longer than the minimum
+// idle period.
+func (r *RedisStreams) claimPending() {
+	var client redis.UniversalClient
+	r.cMut.Lock()
+	client = r.client
+	r.cMut.Unlock()
+
+	if client == nil {
+		return
+	}
+
+	for _, str := range r.conf.Streams {
+		if err := r.claimStream(client, str); err != nil {
+			r.log.Errorf("Failed to claim pending messages of stream %v: %v\n", str, err)
+		}
+	}
+}
+
+func (r *RedisStreams) claimStream(client redis.UniversalClient, stream string) error {
+	start := "-"
+	for {
+		pending, err := client.XPendingExt(&redis.XPendingExtArgs{
+			Stream: stream,
+			Group:  r.conf.ConsumerGroup,
+			Start:  start,
+			End:    "+",
+			Count:  redisStreamsClaimPageSize,
+		}).Result()
+		if err != nil && err != redis.Nil {
+			return err
+		}
+
+		var claimIDs, deadIDs []string
+		for _, p := range pending {
+			if p.Consumer == r.conf.ClientID || p.Idle < r.claimMinIdle {
+				continue
+			}
+			if r.conf.Claim.MaxDeliveries > 0 && p.RetryCount >= r.conf.Claim.MaxDeliveries {
+				deadIDs = append(deadIDs, p.ID)
+			} else {
+				claimIDs = append(claimIDs, p.ID)
+			}
+		}
+
+		if len(claimIDs) > 0 {
+			xmsgs, err := r.claim(client, stream, claimIDs)
+			if err != nil {
+				return err
+			}
+			var claimed []pendingRedisStreamMsg
+			for _, xmsg := range xmsgs {
+				part := r.xMessageToPart(xmsg)
+				if part == nil {
+					r.addAsyncAcks(stream, xmsg.ID)
+					continue
+				}
+				claimed = append(claimed, pendingRedisStreamMsg{
+					payload: message.New(nil),
+					stream:  stream,
+					id:      xmsg.ID,
+				})
+				claimed[len(claimed)-1].payload.Append(part)
+			}
+			if len(claimed) > 0 {
+				r.log.Infof("Claimed %v pending messages of stream %v\n", len(claimed), stream)
+				r.pendingMsgsMut.Lock()
+				r.pendingMsgs = append(r.pendingMsgs, claimed...)
+				r.pendingMsgsMut.Unlock()
+			}
+		}
+
+		if len(deadIDs) > 0 {
+			if err := r.deadLetter(client, stream, deadIDs); err != nil {
+				return err
+			}
+		}
+
+		if len(pending) < redisStreamsClaimPageSize {
+			return nil
+		}
+		var ok bool
+		if start, ok = nextRedisStreamID(pending[len(pending)-1].ID); !ok {
+			return nil
+		}
+	}
+}
+
+// claim transfers ownership of pending messages to this consumer. Messages
+// that have since been deleted from the stream are acknowledged and omitted.
+func (r *RedisStreams) claim(client redis.UniversalClient, stream string, ids []string) ([]redis.XMessage, error) {
+	args := redis.XClaimArgs{
+		Stream:   stream,
+		Group:    r.conf.ConsumerGroup,
+		Consumer: r.conf.ClientID,
+		MinIdle:  r.claimMinIdle,
+		Messages: ids,
+	}
+	xmsgs, err := client.XClaim(&args).Result()
+	if err != redis.Nil {
+		return xmsgs, err
+	}
+
+	// At least one message no longer exists, claim them individually.
+	xmsgs = nil
+	for _, id := range ids {
+		args.Messages = []string{id}
+		res, err := client.XClaim(&args).Result()
+		if err == redis.Nil {
+			r.addAsyncAcks(stream, id)
+			continue
+		}
+		if err != nil {
+			return xmsgs, err
+		}
+		xmsgs = append(xmsgs, res...)
+	}
+	return xmsgs, nil
+}
+
+// deadLetter removes messages that have exceeded the maximum number of
+// deliveries from the pending entries list, adding them to the dead letter
+// stream first when one is configured.
+func (r *RedisStreams) deadLetter(client redis.UniversalClient, stream string, ids []string) error {
+	xmsgs, err := r.claim(client, stream, ids)
+	if err != nil {
+		return err
+	}
+	for _, xmsg := range xmsgs {
+		if r.conf.Claim.DeadLetterStream != "" && xmsg.Values != nil {
+			if err := client.XAdd(&redis.XAddArgs{
+				Stream: r.conf.Claim.DeadLetterStream,
+				ID:     "*",
+				Values: xmsg.Values,
+			}).Err(); err != nil {
+				return fmt.Errorf("failed to add message %v to dead letter stream: %w", xmsg.ID, err)
+			}
+		}
+		r.log.Warnf("Message %v of stream %v exceeded the maximum number of deliveries\n", xmsg.ID, stream)
+		r.addAsyncAcks(stream, xmsg.ID)
+	}
+	return nil
+}
+
+//------------------------------------------------------------------------------
+
 // Connect establishes a connection to a Redis server.
 func (r *RedisStreams) Connect() error {
 	return r.ConnectWithContext(context.Background())
@@ -238,6 +458,36 @@ func (r *RedisStreams) ConnectWithContext(ctx context.Context) error {
 	return nil
 }
 
+// xMessageToPart converts a stream entry into a message part, returns nil if
+// the entry does not contain a body.
+func (r *RedisStreams) xMessageToPart(xmsg redis.XMessage) types.Part {
+	body, exists := xmsg.Values[r.conf.BodyKey]
+	if !exists {
+		return nil
+	}
+
+	var bodyBytes []byte
+	switch t := body.(type) {
+	case string:
+		bodyBytes = []byte(t)
+	case []byte:
+		bodyBytes = t
+	}
+	if bodyBytes == nil {
+		return nil
+	}
+
+	part := message.NewPart(bodyBytes)
+	part.Metadata().Set("redis_stream", xmsg.ID)
+	for k, v := range xmsg.Values {
+		if k == r.conf.BodyKey {
+			continue
+		}
+		part.Metadata().Set(k, fmt.Sprintf("%v", v))
+	}
+	return part
+}
+
 func (r *RedisStreams) read() (pendingRedisStreamMsg, error) {
 	var client redis.UniversalClient
 	var msg pendingRedisStreamMsg
@@ -295,29 +545,11 @@ func (r *RedisStreams) read() (pendingRedisStreamMsg, error) {
 			}
 		}
 		for _, xmsg := range strRes.Messages {
-			body, exists := xmsg.Values[r.conf.BodyKey]
-			if !exists {
-				continue
-			}
-			delete(xmsg.Values, r.conf.BodyKey)
-
-			var bodyBytes []byte
-			switch t := body.(type) {
-			case string:
-				bodyBytes = []byte(t)
-			case []byte:
-				bodyBytes = t
-			}
-			if bodyBytes == nil {
+			part := r.xMessageToPart(xmsg)
+			if part == nil {
 				continue
 			}
 
-			part := message.NewPart(bodyBytes)
-			part.Metadata().Set("redis_stream", xmsg.ID)
-			for k, v := range xmsg.Values {
-				part.Metadata().Set(k, fmt.Sprintf("%v", v))
-			}
-
 			nextMsg := pendingRedisStreamMsg{
 				payload: message.New(nil),
 				stream:  strRes.Stream,
@@ -0,0 +1,73 @@
+package reader
+
+import (
+	"testing"
+
+	"github.com/Jeffail/benthos/v3/lib/log"
+	"github.com/Jeffail/benthos/v3/lib/metrics"
+	"github.com/go-redis/redis/v7"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNextRedisStreamID(t *testing.T) {
+	tests := map[string]struct {
+		id   string
+		next string
+		ok   bool
+	}{
+		"basic":        {id: "1526919030474-55", next: "1526919030474-56", ok: true},
+		"zero":         {id: "0-0", next: "0-1", ok: true},
+		"no sequence":  {id: "1526919030474", ok: false},
+		"bad sequence": {id: "1526919030474-nope", ok: false},
+	}
+
+	for name, test := range tests {
+		test := test
+		t.Run(name, func(t *testing.T) {
+			next, ok := nextRedisStreamID(test.id)
+			assert.Equal(t, test.ok, ok)
+			assert.Equal(t, test.next, next)
+		})
+	}
+}
+
+func TestRedisStreamsClaimConfig(t *testing.T) {
+	conf := NewRedisStreamsConfig()
+	conf.Claim.Enabled = true
+	conf.Claim.Period = "nope"
+	_, err := NewRedisStreams(conf, log.Noop(), metrics.Noop())
+	require.Error(t, err)
+
+	conf = NewRedisStreamsConfig()
+	conf.Claim.Enabled = true
+	conf.Claim.DeadLetterStream = "dead"
+	_, err = NewRedisStreams(conf, log.Noop(), metrics.Noop())
+	require.Error(t, err)
+
+	conf = NewRedisStreamsConfig()
+	conf.Claim.Enabled = true
+	conf.Claim.MaxDeliveries = 3
+	conf.Claim.DeadLetterStream = "dead"
+	r, err := NewRedisStreams(conf, log.Noop(), metrics.Noop())
+	require.NoError(t, err)
+	r.CloseAsync()
+}
+
+func TestRedisStreamsXMessageToPart(t *testing.T) {
+	r := &RedisStreams{conf: NewRedisStreamsConfig()}
+
+	values := map[string]interface{}{
+		"body": "hello world",
+		"foo":  "bar",
+	}
+	part := r.xMessageToPart(redis.XMessage{ID: "1-0", Values: values})
+	require.NotNil(t, part)
+	assert.Equal(t, "hello world", string(part.Get()))
+	assert.Equal(t, "1-0", part.Metadata().Get("redis_stream"))
+	assert.Equal(t, "bar", part.Metadata().Get("foo"))
+	assert.Equal(t, "", part.Metadata().Get("body"))
+	assert.Len(t, values, 2, "entry values must not be modified")
+
+	assert.Nil(t, r.xMessageToPart(redis.XMessage{ID: "2-0", Values: map[string]interface{}{"foo": "bar"}}))
+}
@@ -21,7 +21,21 @@ Pulls messages from Redis (v5.0+) streams with the XREADGROUP command. The
 		Description: `
 Redis stream entries are key/value pairs, as such it is necessary to specify the
 key that contains the body of the message. All other keys/value pairs are saved
-as metadata fields.`,
+as metadata fields.
+
+### Claiming Pending Messages
+
+Messages that are delivered to a consumer of a group remain pending until they
+are acknowledged. When a consumer crashes, or is removed from a deployment, its
+pending messages are stranded. Setting ` + "`claim.enabled`" + ` to ` + "`true`" + `
+causes this input to periodically scan the pending messages of each stream and
+claim those of other consumers that have been idle for longer than
+` + "`claim.min_idle`" + `, consuming them as if they were new.
+
+When ` + "`claim.max_deliveries`" + ` is set then messages that have been
+delivered at least that many times are no longer claimed for consumption.
+Instead they are added to the stream ` + "`claim.dead_letter_stream`" + `, if
+set, and are then acknowledged.`,
 		FieldSpecs: redis.ConfigDocs().Add(
 			func() docs.FieldSpec {
 				b := batch.FieldSpec()
@@ -37,6 +51,13 @@ as metadata fields.`,
 			docs.FieldAdvanced("start_from_oldest", "If an offset is not found for a stream, determines whether to consume from the oldest available offset, otherwise messages are consumed from the latest offset."),
 			docs.FieldAdvanced("commit_period", "The period of time between each commit of the current offset. Offsets are always committed during shutdown."),
 			docs.FieldAdvanced("timeout", "The length of time to poll for new messages before reattempting."),
+			docs.FieldAdvanced("claim", "Allows pending messages of other consumers of the group that have been idle for a period of time to be claimed and consumed by this input.").WithChildren(
+				docs.FieldCommon("enabled", "Whether to periodically claim the pending messages of other consumers."),
+				docs.FieldCommon("period", "The period of time between each scan for pending messages."),
+				docs.FieldCommon("min_idle", "The minimum period of time since a pending message was last delivered before it can be claimed."),
+				docs.FieldCommon("max_deliveries", "The maximum number of times a message can be delivered before it is no longer claimed for consumption. When set to zero messages are claimed regardless of how many times they have been delivered."),
+				docs.FieldCommon("dead_letter_stream", "An optional stream to add messages to once they exceed `max_deliveries`. Messages are acknowledged once they have been added to the stream, or immediately when this field is empty."),
+			).AtVersion("3.55.0"),
 		),
 		Categories: []Category{
 			CategoryServices,
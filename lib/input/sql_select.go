@@ -0,0 +1,35 @@
+package input
+
+// SQLSelectConfig contains configuration fields for the SQLSelect input type.
+type SQLSelectConfig struct {
+	Driver          string   `json:"driver" yaml:"driver"`
+	DataSourceName  string   `json:"data_source_name" yaml:"data_source_name"`
+	Table           string   `json:"table" yaml:"table"`
+	Columns         []string `json:"columns" yaml:"columns"`
+	Where           string   `json:"where" yaml:"where"`
+	KeyColumn       string   `json:"key_column" yaml:"key_column"`
+	Pagination      string   `json:"pagination" yaml:"pagination"`
+	PageSize        int      `json:"page_size" yaml:"page_size"`
+	PollInterval    string   `json:"poll_interval" yaml:"poll_interval"`
+	CheckpointCache string   `json:"checkpoint_cache" yaml:"checkpoint_cache"`
+	CheckpointKey   string   `json:"checkpoint_key" yaml:"checkpoint_key"`
+	CheckpointLimit int      `json:"checkpoint_limit" yaml:"checkpoint_limit"`
+}
+
+// NewSQLSelectConfig creates a new SQLSelectConfig with default values.
+func NewSQLSelectConfig() SQLSelectConfig {
+	return SQLSelectConfig{
+		Driver:          "mysql",
+		DataSourceName:  "",
+		Table:           "",
+		Columns:         []string{"*"},
+		Where:           "",
+		KeyColumn:       "",
+		Pagination:      "keyset",
+		PageSize:        1000,
+		PollInterval:    "",
+		CheckpointCache: "",
+		CheckpointKey:   "",
+		CheckpointLimit: 8,
+	}
+}
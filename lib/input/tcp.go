@@ -29,6 +29,12 @@ instance of this input can utilise any number of threads within a
 
 If the delimiter field is left empty then line feed (\n) is used.`,
 		Status: docs.StatusDeprecated,
+		ReplacedBy: &docs.ComponentReplacement{
+			Name: TypeSocket,
+			Fields: map[string]interface{}{
+				"network": "tcp",
+			},
+		},
 		config: docs.FieldComponent().WithChildren(
 			docs.FieldCommon("address", ""),
 			docs.FieldCommon("multipart", ""),
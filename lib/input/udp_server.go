@@ -29,6 +29,12 @@ The field ` + "`max_buffer`" + ` specifies the maximum amount of memory to
 allocate for buffering lines of data, this must exceed the largest expected
 message size.`,
 		Status: docs.StatusDeprecated,
+		ReplacedBy: &docs.ComponentReplacement{
+			Name: TypeSocketServer,
+			Fields: map[string]interface{}{
+				"network": "udp",
+			},
+		},
 		config: docs.FieldComponent().WithChildren(
 			docs.FieldCommon("address", ""),
 			docs.FieldCommon("max_buffer", ""),
@@ -10,7 +10,7 @@ func lintResource(ctx docs.LintContext, line, col int, v interface{}) []docs.Lin
 	label, _ := gObj.S("label").Data().(string)
 	if label == "" {
 		return []docs.Lint{
-			docs.NewLintError(line, "The label field for resources must be unique and not empty"),
+			docs.NewLintError(line, "The label field for resources must be unique and not empty").WithType(docs.LintBadLabel),
 		}
 	}
 	return nil
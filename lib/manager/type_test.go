@@ -10,6 +10,7 @@ import (
 	"github.com/Jeffail/benthos/v3/lib/input"
 	"github.com/Jeffail/benthos/v3/lib/log"
 	"github.com/Jeffail/benthos/v3/lib/manager"
+	"github.com/Jeffail/benthos/v3/lib/message"
 	"github.com/Jeffail/benthos/v3/lib/metrics"
 	"github.com/Jeffail/benthos/v3/lib/output"
 	"github.com/Jeffail/benthos/v3/lib/processor"
@@ -515,3 +516,70 @@ func TestManagerPipeGetSet(t *testing.T) {
 }
 
 //------------------------------------------------------------------------------
+
+func TestManagerProcessorFailContext(t *testing.T) {
+	mgr, err := manager.NewV2(manager.NewResourceConfig(), nil, log.Noop(), metrics.Noop())
+	require.NoError(t, err)
+
+	conf := processor.NewConfig()
+	conf.Type = processor.TypeBloblang
+	conf.Bloblang = `root = if this.fail { throw("nope") } else { this }`
+
+	pathMgr := mgr.ForChildComponent("pipeline.processors.0").(*manager.Type)
+	unlabelled, err := pathMgr.NewProcessor(conf)
+	require.NoError(t, err)
+
+	conf.Label = "foo"
+	labelled, err := mgr.NewProcessor(conf)
+	require.NoError(t, err)
+
+	msgs, res := unlabelled.ProcessMessage(message.New([][]byte{[]byte(`{"fail":true}`)}))
+	require.Nil(t, res)
+	require.Len(t, msgs, 1)
+
+	meta := msgs[0].Get(0).Metadata()
+	assert.NotEmpty(t, meta.Get(types.FailFlagKey))
+	assert.Equal(t, "", meta.Get(types.FailLabelKey))
+	assert.Equal(t, "pipeline.processors.0", meta.Get(types.FailPathKey))
+
+	// An existing error passing through a processor keeps its context.
+	passConf := processor.NewConfig()
+	passConf.Type = processor.TypeBloblang
+	passConf.Bloblang = `root = this`
+	passConf.Label = "bar"
+	passing, err := mgr.NewProcessor(passConf)
+	require.NoError(t, err)
+
+	msgs, res = passing.ProcessMessage(msgs[0])
+	require.Nil(t, res)
+	require.Len(t, msgs, 1)
+
+	meta = msgs[0].Get(0).Metadata()
+	assert.Equal(t, "", meta.Get(types.FailLabelKey))
+	assert.Equal(t, "pipeline.processors.0", meta.Get(types.FailPathKey))
+
+	// Whereas failing again replaces the context.
+	msgs, res = labelled.ProcessMessage(msgs[0])
+	require.Nil(t, res)
+	require.Len(t, msgs, 1)
+
+	meta = msgs[0].Get(0).Metadata()
+	assert.Equal(t, "foo", meta.Get(types.FailLabelKey))
+	assert.Equal(t, "foo", meta.Get(types.FailPathKey))
+
+	// Errors flagged by a child processor keep the context of the child.
+	tryConf := processor.NewConfig()
+	tryConf.Type = processor.TypeTry
+	conf.Label = ""
+	tryConf.Try = append(tryConf.Try, conf)
+	parent, err := pathMgr.NewProcessor(tryConf)
+	require.NoError(t, err)
+
+	msgs, res = parent.ProcessMessage(message.New([][]byte{[]byte(`{"fail":true}`)}))
+	require.Nil(t, res)
+	require.Len(t, msgs, 1)
+
+	meta = msgs[0].Get(0).Metadata()
+	assert.Equal(t, "", meta.Get(types.FailLabelKey))
+	assert.Equal(t, "pipeline.processors.0.0", meta.Get(types.FailPathKey))
+}
@@ -49,6 +49,9 @@ metrics:
 	Constructors[TypeCloudWatch] = TypeSpec{
 		constructor: NewCloudWatch,
 		Status:      docs.StatusDeprecated,
+		ReplacedBy: &docs.ComponentReplacement{
+			Name: TypeAWSCloudWatch,
+		},
 		Summary: `
 Send metrics to AWS CloudWatch using the PutMetricData endpoint.`,
 		Description: `
@@ -35,6 +35,10 @@ type TypeSpec struct {
 	Footnotes   string
 	config      docs.FieldSpec
 	FieldSpecs  docs.FieldSpecs
+
+	// ReplacedBy describes the component that replaces this one when it
+	// is deprecated.
+	ReplacedBy *docs.ComponentReplacement
 }
 
 // ConstructorFunc is a func signature able to construct a metrics output.
@@ -59,6 +63,7 @@ func WalkConstructors(fn func(ConstructorFunc, docs.ComponentSpec)) {
 			Config:      conf,
 			Status:      v.Status,
 			Version:     v.Version,
+			ReplacedBy:  v.ReplacedBy,
 		}
 		fn(ConstructorFunc(v.constructor), spec)
 	}
@@ -27,7 +27,7 @@ Send metrics to InfluxDB 1.x using the ` + "`/write`" + ` endpoint.`,
 			docs.FieldCommon("db", "The name of the database to use."),
 			btls.FieldSpec(),
 			docs.FieldAdvanced("username", "A username (when applicable)."),
-			docs.FieldAdvanced("password", "A password (when applicable)."),
+			docs.FieldAdvanced("password", "A password (when applicable).").Secret(),
 			docs.FieldAdvanced("include", "Optional additional metrics to collect, enabling these metrics may have some performance implications as it acquires a global semaphore and does `stoptheworld()`.").WithChildren(
 				docs.FieldCommon("runtime", "A duration string indicating how often to poll and collect runtime metrics. Leave empty to disable this metric", "1m").HasDefault(""),
 				docs.FieldCommon("debug_gc", "A duration string indicating how often to poll and collect GC metrics. Leave empty to disable this metric.", "1m").HasDefault(""),
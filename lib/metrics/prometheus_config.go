@@ -19,7 +19,7 @@ Metrics paths will differ from [the standard list](/docs/components/metrics/abou
 			docs.FieldAdvanced("push_job_name", "An identifier for push jobs."),
 			docs.FieldAdvanced("push_basic_auth", "The Basic Authentication credentials.").WithChildren(
 				docs.FieldCommon("username", "The Basic Authentication username."),
-				docs.FieldCommon("password", "The Basic Authentication password."),
+				docs.FieldCommon("password", "The Basic Authentication password.").Secret(),
 			),
 		},
 		Footnotes: `
@@ -49,6 +49,9 @@ allowing you to transfer data across accounts. You can find out more
 	Constructors[TypeKinesis] = TypeSpec{
 		constructor: fromSimpleConstructor(NewKinesis),
 		Status:      docs.StatusDeprecated,
+		ReplacedBy: &docs.ComponentReplacement{
+			Name: TypeAWSKinesis,
+		},
 		Summary: `
 Sends messages to a Kinesis stream.`,
 		Description: `
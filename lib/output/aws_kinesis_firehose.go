@@ -42,6 +42,9 @@ allowing you to transfer data across accounts. You can find out more
 	Constructors[TypeKinesisFirehose] = TypeSpec{
 		constructor: fromSimpleConstructor(NewKinesisFirehose),
 		Status:      docs.StatusDeprecated,
+		ReplacedBy: &docs.ComponentReplacement{
+			Name: TypeAWSKinesisFirehose,
+		},
 		Summary: `
 Sends messages to a Kinesis Firehose delivery stream.`,
 		Description: `
@@ -128,6 +128,9 @@ output:
 	Constructors[TypeS3] = TypeSpec{
 		constructor: fromSimpleConstructor(NewAmazonS3),
 		Status:      docs.StatusDeprecated,
+		ReplacedBy: &docs.ComponentReplacement{
+			Name: TypeAWSS3,
+		},
 		Summary: `
 Sends message parts as objects to an Amazon S3 bucket. Each object is uploaded
 with the path specified with the ` + "`path`" + ` field.`,
@@ -39,6 +39,9 @@ allowing you to transfer data across accounts. You can find out more
 	Constructors[TypeSNS] = TypeSpec{
 		constructor: fromSimpleConstructor(NewAmazonSNS),
 		Status:      docs.StatusDeprecated,
+		ReplacedBy: &docs.ComponentReplacement{
+			Name: TypeAWSSNS,
+		},
 		Summary: `
 Sends messages to an AWS SNS topic.`,
 		Description: `
@@ -56,6 +56,9 @@ allowing you to transfer data across accounts. You can find out more
 	Constructors[TypeSQS] = TypeSpec{
 		constructor: fromSimpleConstructor(NewAmazonSQS),
 		Status:      docs.StatusDeprecated,
+		ReplacedBy: &docs.ComponentReplacement{
+			Name: TypeAWSSQS,
+		},
 		Summary: `
 Sends messages to an SQS queue.`,
 		Description: `
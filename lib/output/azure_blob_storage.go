@@ -34,15 +34,15 @@ calculated per message of a batch.`,
 			docs.FieldCommon(
 				"storage_access_key",
 				"The storage account access key. This field is ignored if `storage_connection_string` is set.",
-			),
+			).Secret(),
 			docs.FieldCommon(
 				"storage_sas_token",
 				"The storage account SAS token. This field is ignored if `storage_connection_string` or `storage_access_key` / `storage_sas_token` are set.",
-			).AtVersion("3.38.0"),
+			).Secret().AtVersion("3.38.0"),
 			docs.FieldCommon(
 				"storage_connection_string",
 				"A storage account connection string. This field is required if `storage_account` and `storage_access_key` are not set.",
-			),
+			).Secret(),
 			docs.FieldAdvanced("public_access_level", `The container's public access level. The default value is `+"`PRIVATE`"+`.`).HasOptions(
 				"PRIVATE", "BLOB", "CONTAINER",
 			),
@@ -70,8 +70,11 @@ calculated per message of a batch.`,
 	Constructors[TypeBlobStorage] = TypeSpec{
 		constructor: fromSimpleConstructor(newDeprecatedBlobStorage),
 		Status:      docs.StatusDeprecated,
-		Summary:     "This component has been renamed to [`azure_blob_storage`](/docs/components/outputs/azure_blob_storage).",
-		Async:       true,
+		ReplacedBy: &docs.ComponentReplacement{
+			Name: TypeAzureBlobStorage,
+		},
+		Summary: "This component has been renamed to [`azure_blob_storage`](/docs/components/outputs/azure_blob_storage).",
+		Async:   true,
 		FieldSpecs: docs.FieldSpecs{
 			docs.FieldCommon(
 				"storage_account",
@@ -80,15 +83,15 @@ calculated per message of a batch.`,
 			docs.FieldCommon(
 				"storage_access_key",
 				"The storage account access key. This field is ignored if `storage_connection_string` is set.",
-			),
+			).Secret(),
 			docs.FieldCommon(
 				"storage_sas_token",
 				"The storage account SAS token. This field is ignored if `storage_connection_string` or `storage_access_key` are set.",
-			),
+			).Secret(),
 			docs.FieldCommon(
 				"storage_connection_string",
 				"A storage account connection string. This field is required if `storage_account` and `storage_access_key` / `storage_sas_token` are not set.",
-			),
+			).Secret(),
 			docs.FieldAdvanced("public_access_level", `The container's public access level. The default value is `+"`PRIVATE`"+`.`).HasOptions(
 				"PRIVATE", "BLOB", "CONTAINER",
 			),
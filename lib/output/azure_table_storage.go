@@ -66,11 +66,11 @@ properties:
 			docs.FieldCommon(
 				"storage_access_key",
 				"The storage account access key. This field is ignored if `storage_connection_string` is set.",
-			),
+			).Secret(),
 			docs.FieldCommon(
 				"storage_connection_string",
 				"A storage account connection string. This field is required if `storage_account` and `storage_access_key` are not set.",
-			),
+			).Secret(),
 			docs.FieldCommon("table_name", "The table to store messages into.",
 				`${!meta("kafka_topic")}`,
 			).IsInterpolated(),
@@ -98,9 +98,12 @@ properties:
 	Constructors[TypeTableStorage] = TypeSpec{
 		constructor: fromSimpleConstructor(newDeprecatedTableStorage),
 		Status:      docs.StatusDeprecated,
-		Summary:     "This component has been renamed to [`azure_table_storage`](/docs/components/outputs/azure_table_storage).",
-		Async:       true,
-		Batches:     true,
+		ReplacedBy: &docs.ComponentReplacement{
+			Name: TypeAzureTableStorage,
+		},
+		Summary: "This component has been renamed to [`azure_table_storage`](/docs/components/outputs/azure_table_storage).",
+		Async:   true,
+		Batches: true,
 		FieldSpecs: docs.FieldSpecs{
 			docs.FieldCommon(
 				"storage_account",
@@ -109,11 +112,11 @@ properties:
 			docs.FieldCommon(
 				"storage_access_key",
 				"The storage account access key. This field is ignored if `storage_connection_string` is set.",
-			),
+			).Secret(),
 			docs.FieldCommon(
 				"storage_connection_string",
 				"A storage account connection string. This field is required if `storage_account` and `storage_access_key` are not set.",
-			),
+			).Secret(),
 			docs.FieldCommon("table_name", "The table to store messages into.",
 				`${!meta("kafka_topic")}`,
 			).IsInterpolated(),
@@ -52,6 +52,10 @@ type TypeSpec struct {
 	FieldSpecs  docs.FieldSpecs
 	Examples    []docs.AnnotatedExample
 	Version     string
+
+	// ReplacedBy describes the component that replaces this one when it
+	// is deprecated.
+	ReplacedBy *docs.ComponentReplacement
 }
 
 // AppendProcessorsFromConfig takes a variant arg of pipeline constructor
@@ -126,6 +130,7 @@ func WalkConstructors(fn func(ConstructorFunc, docs.ComponentSpec)) {
 			Examples:    v.Examples,
 			Status:      v.Status,
 			Version:     v.Version,
+			ReplacedBy:  v.ReplacedBy,
 		}
 		if len(v.Categories) > 0 {
 			spec.Categories = make([]string, 0, len(v.Categories))
@@ -26,6 +26,14 @@ Both the ` + "`id` and `index`" + ` fields can be dynamically set using function
 interpolations described [here](/docs/configuration/interpolation#bloblang-queries). When
 sending batched messages these interpolations are performed per message part.
 
+### Data Streams
+
+In order to write to a [data stream](https://www.elastic.co/guide/en/elasticsearch/reference/current/data-streams.html) set the ` + "`action`" + ` to ` + "`create`" + ` and the ` + "`index`" + ` to the name of the data stream. Data streams do not accept document types, and so the field ` + "`type`" + ` should be set to an empty string. The ` + "`id`" + ` can also be set to an empty string, in which case Elasticsearch generates an ID for each document.
+
+### Bulk Errors
+
+Each batch is sent as a single bulk request. Documents that fail with a status of 429 or 5XX are retried individually according to the ` + "`backoff`" + ` settings. Documents that are rejected with any other status, such as mapping conflicts or documents that already exist when using the ` + "`create`" + ` action, are not retried by this output. Instead, the documents that failed are reported individually. Inputs that preserve batches then only resend the documents that failed instead of the whole batch. Batches that contain failed documents can also be routed to an alternative output using a [` + "`try`" + `](/docs/components/outputs/try) output.
+
 ### AWS
 
 It's possible to enable AWS connectivity with this output using the ` + "`aws`" + `
@@ -36,7 +44,7 @@ false for connections to succeed.`,
 		FieldSpecs: docs.FieldSpecs{
 			docs.FieldCommon("urls", "A list of URLs to connect to. If an item of the list contains commas it will be expanded into multiple URLs.", []string{"http://localhost:9200"}).Array(),
 			docs.FieldCommon("index", "The index to place messages.").IsInterpolated(),
-			docs.FieldAdvanced("action", "The action to take on the document.").IsInterpolated().HasOptions("index", "update", "delete"),
+			docs.FieldAdvanced("action", "The action to take on the document.").IsInterpolated().HasOptions("index", "create", "update", "delete"),
 			docs.FieldAdvanced("pipeline", "An optional pipeline id to preprocess incoming documents.").IsInterpolated(),
 			docs.FieldCommon("id", "The ID for indexed messages. Interpolation should be used in order to create a unique ID for each message.").IsInterpolated(),
 			docs.FieldCommon("type", "The document type."),
@@ -48,6 +56,7 @@ false for connections to succeed.`,
 			docs.FieldCommon("max_in_flight", "The maximum number of messages to have in flight at a given time. Increase this to improve throughput."),
 		}.Merge(retries.FieldSpecs()).Add(
 			auth.BasicAuthFieldSpec(),
+			docs.FieldAdvanced("api_key", "An optional base64 encoded API key, in the format expected by the `Authorization: ApiKey` header, used to authenticate requests.").Secret().AtVersion("3.55.0"),
 			batch.FieldSpec(),
 			docs.FieldAdvanced("aws", "Enables and customises connectivity to Amazon Elastic Service.").WithChildren(
 				docs.FieldSpecs{
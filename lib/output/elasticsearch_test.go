@@ -0,0 +1,45 @@
+package output
+
+import (
+	"testing"
+
+	"github.com/Jeffail/benthos/v3/internal/docs"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
+)
+
+func TestElasticsearchScrubSecrets(t *testing.T) {
+	conf := `
+elasticsearch:
+  urls: [ http://localhost:9200 ]
+  index: foo
+  api_key: bar
+  basic_auth:
+    enabled: true
+    username: baz
+    password: buz
+`
+
+	var node yaml.Node
+	require.NoError(t, yaml.Unmarshal([]byte(conf), &node))
+	require.NoError(t, docs.SanitiseYAML(docs.TypeOutput, &node, docs.SanitiseConfig{
+		RemoveDeprecated: true,
+		ScrubSecrets:     true,
+	}))
+
+	var res struct {
+		Elasticsearch struct {
+			APIKey    string `yaml:"api_key"`
+			BasicAuth struct {
+				Username string `yaml:"username"`
+				Password string `yaml:"password"`
+			} `yaml:"basic_auth"`
+		} `yaml:"elasticsearch"`
+	}
+	require.NoError(t, node.Decode(&res))
+
+	assert.Equal(t, "!!!SECRET_SCRUBBED!!!", res.Elasticsearch.APIKey)
+	assert.Equal(t, "baz", res.Elasticsearch.BasicAuth.Username)
+	assert.Equal(t, "!!!SECRET_SCRUBBED!!!", res.Elasticsearch.BasicAuth.Password)
+}
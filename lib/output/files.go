@@ -14,6 +14,12 @@ func init() {
 	Constructors[TypeFiles] = TypeSpec{
 		constructor: fromSimpleConstructor(NewFiles),
 		Status:      docs.StatusDeprecated,
+		ReplacedBy: &docs.ComponentReplacement{
+			Name: TypeFile,
+			Fields: map[string]interface{}{
+				"codec": "all-bytes",
+			},
+		},
 		Summary: `
 Writes each individual message to a new file.`,
 		Description: `
@@ -30,7 +30,7 @@ messages these interpolations are performed per message part.`,
 			docs.FieldBool("retained", "Set message as retained on the topic."),
 			mqttconf.WillFieldSpec(),
 			docs.FieldAdvanced("user", "A username to connect with."),
-			docs.FieldAdvanced("password", "A password to connect with."),
+			docs.FieldAdvanced("password", "A password to connect with.").Secret(),
 			docs.FieldAdvanced("keepalive", "Max seconds of inactivity before a keepalive message is sent."),
 			tls.FieldSpec().AtVersion("3.45.0"),
 			docs.FieldCommon("max_in_flight", "The maximum number of messages to have in flight at a given time. Increase this to improve throughput."),
@@ -20,6 +20,12 @@ connecting to a server.
 If batched messages are sent the final message of the batch will be followed by
 two line breaks in order to indicate the end of the batch.`,
 		Status: docs.StatusDeprecated,
+		ReplacedBy: &docs.ComponentReplacement{
+			Name: TypeSocket,
+			Fields: map[string]interface{}{
+				"network": "udp",
+			},
+		},
 		config: docs.FieldComponent().WithChildren(
 			docs.FieldCommon("address", ""),
 		),
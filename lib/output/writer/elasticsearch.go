@@ -8,6 +8,7 @@ import (
 	"strings"
 	"time"
 
+	ibatch "github.com/Jeffail/benthos/v3/internal/batch"
 	"github.com/Jeffail/benthos/v3/internal/bloblang"
 	"github.com/Jeffail/benthos/v3/internal/bloblang/field"
 	"github.com/Jeffail/benthos/v3/lib/log"
@@ -49,6 +50,7 @@ type ElasticsearchConfig struct {
 	Timeout         string               `json:"timeout" yaml:"timeout"`
 	TLS             btls.Config          `json:"tls" yaml:"tls"`
 	Auth            auth.BasicAuthConfig `json:"basic_auth" yaml:"basic_auth"`
+	APIKey          string               `json:"api_key" yaml:"api_key"`
 	AWS             OptionalAWSConfig    `json:"aws" yaml:"aws"`
 	GzipCompression bool                 `json:"gzip_compression" yaml:"gzip_compression"`
 	MaxInFlight     int                  `json:"max_in_flight" yaml:"max_in_flight"`
@@ -76,6 +78,7 @@ func NewElasticsearchConfig() ElasticsearchConfig {
 		Timeout:     "5s",
 		TLS:         btls.NewConfig(),
 		Auth:        auth.NewBasicAuthConfig(),
+		APIKey:      "",
 		AWS: OptionalAWSConfig{
 			Enabled: false,
 			Config:  sess.NewConfig(),
@@ -196,6 +199,12 @@ func (e *Elasticsearch) Connect() error {
 		))
 	}
 
+	if e.conf.APIKey != "" {
+		opts = append(opts, elastic.SetHeaders(http.Header{
+			"Authorization": []string{"ApiKey " + e.conf.APIKey},
+		}))
+	}
+
 	if e.conf.TLS.Enabled {
 		opts = append(opts, elastic.SetHttpClient(&http.Client{
 			Transport: &http.Transport{
@@ -234,6 +243,9 @@ func (e *Elasticsearch) Connect() error {
 }
 
 func shouldRetry(s int) bool {
+	if s == http.StatusTooManyRequests {
+		return true
+	}
 	if s >= 500 && s <= 599 {
 		return true
 	}
@@ -241,12 +253,15 @@ func shouldRetry(s int) bool {
 }
 
 type pendingBulkIndex struct {
-	Action   string
-	Index    string
-	Pipeline string
-	Routing  string
-	Type     string
-	Doc      interface{}
+	PartIndex int
+	Err       error
+	ID        string
+	Action    string
+	Index     string
+	Pipeline  string
+	Routing   string
+	Type      string
+	Doc       interface{}
 }
 
 // WriteWithContext will attempt to write a message to Elasticsearch, wait for
@@ -264,7 +279,7 @@ func (e *Elasticsearch) Write(msg types.Message) error {
 
 	boff := e.backoffCtor()
 
-	requests := map[string]*pendingBulkIndex{}
+	requests := make([]*pendingBulkIndex, 0, msg.Len())
 	if err := msg.Iter(func(i int, part types.Part) error {
 		jObj, ierr := part.JSON()
 		if ierr != nil {
@@ -272,68 +287,88 @@ func (e *Elasticsearch) Write(msg types.Message) error {
 			e.log.Errorf("Failed to marshal message into JSON document: %v\n", ierr)
 			return fmt.Errorf("failed to marshal message into JSON document: %w", ierr)
 		}
-		requests[e.idStr.String(i, msg)] = &pendingBulkIndex{
-			Action:   e.actionStr.String(i, msg),
-			Index:    e.indexStr.String(i, msg),
-			Pipeline: e.pipelineStr.String(i, msg),
-			Routing:  e.routingStr.String(i, msg),
-			Type:     e.conf.Type,
-			Doc:      jObj,
-		}
+		requests = append(requests, &pendingBulkIndex{
+			PartIndex: i,
+			ID:        e.idStr.String(i, msg),
+			Action:    e.actionStr.String(i, msg),
+			Index:     e.indexStr.String(i, msg),
+			Pipeline:  e.pipelineStr.String(i, msg),
+			Routing:   e.routingStr.String(i, msg),
+			Type:      e.conf.Type,
+			Doc:       jObj,
+		})
 		return nil
 	}); err != nil {
 		return err
 	}
 
-	b := e.client.Bulk()
-	for k, v := range requests {
-		bulkReq, err := e.buildBulkableRequest(k, v)
-		if err != nil {
-			return err
+	var batchErr *ibatch.Error
+	for len(requests) > 0 {
+		b := e.client.Bulk()
+		for _, v := range requests {
+			bulkReq, err := e.buildBulkableRequest(v)
+			if err != nil {
+				return err
+			}
+			b.Add(bulkReq)
 		}
-		b.Add(bulkReq)
-	}
 
-	for b.NumberOfActions() != 0 {
 		result, err := b.Do(context.Background())
 		if err != nil {
 			return err
 		}
 
-		failed := result.Failed()
-		if len(failed) == 0 {
-			continue
-		}
-
-		wait := boff.NextBackOff()
-		for i := 0; i < len(failed); i++ {
-			reason := "no reason given"
-			if fErr := failed[i].Error; fErr != nil {
-				reason = fErr.Reason
+		// Items of a bulk response are in the same order as the requests,
+		// which allows us to match failures to messages even when document
+		// IDs are generated by Elasticsearch.
+		var retries []*pendingBulkIndex
+		for i, item := range result.Items {
+			if i >= len(requests) {
+				break
 			}
-			if !shouldRetry(failed[i].Status) {
-				e.log.Errorf("Elasticsearch message '%v' rejected with code [%v]: %v\n", failed[i].Id, failed[i].Status, reason)
-				return fmt.Errorf("failed to send %v parts from message: [%v]: %v", len(failed), failed[i].Status, reason)
-			}
-			e.log.Errorf("Elasticsearch message '%v' failed with code [%v]: %v\n", failed[i].Id, failed[i].Status, reason)
-			id := failed[i].Id
-			req := requests[id]
-			bulkReq, err := e.buildBulkableRequest(id, req)
-			if err != nil {
-				return err
+			for _, res := range item {
+				if res.Status >= 200 && res.Status <= 299 {
+					continue
+				}
+				reason := "no reason given"
+				if res.Error != nil {
+					reason = res.Error.Reason
+				}
+				err := fmt.Errorf("[%v]: %v", res.Status, reason)
+				if shouldRetry(res.Status) {
+					e.log.Errorf("Elasticsearch message '%v' failed with code [%v]: %v\n", res.Id, res.Status, reason)
+					requests[i].Err = err
+					retries = append(retries, requests[i])
+					continue
+				}
+				e.log.Errorf("Elasticsearch message '%v' rejected with code [%v]: %v\n", res.Id, res.Status, reason)
+				if batchErr == nil {
+					batchErr = ibatch.NewError(msg, err)
+				}
+				batchErr.Failed(requests[i].PartIndex, err)
 			}
-			b.Add(bulkReq)
 		}
+		if len(retries) == 0 {
+			break
+		}
+
+		wait := boff.NextBackOff()
 		if wait == backoff.Stop {
-			reason := "no reason given"
-			if fErr := failed[0].Error; fErr != nil {
-				reason = fErr.Reason
+			if batchErr == nil {
+				batchErr = ibatch.NewError(msg, retries[0].Err)
+			}
+			for _, r := range retries {
+				batchErr.Failed(r.PartIndex, r.Err)
 			}
-			return fmt.Errorf("failed to send %v parts from message: %v", len(failed), reason)
+			break
 		}
 		time.Sleep(wait)
+		requests = retries
 	}
 
+	if batchErr != nil {
+		return batchErr
+	}
 	return nil
 }
 
@@ -347,28 +382,29 @@ func (e *Elasticsearch) WaitForClose(timeout time.Duration) error {
 }
 
 // Build a bulkable request for a given pending bulk index item.
-func (e *Elasticsearch) buildBulkableRequest(id string, p *pendingBulkIndex) (elastic.BulkableRequest, error) {
+func (e *Elasticsearch) buildBulkableRequest(p *pendingBulkIndex) (elastic.BulkableRequest, error) {
 	switch p.Action {
 	case "update":
 		return elastic.NewBulkUpdateRequest().
 			Index(p.Index).
 			Routing(p.Routing).
 			Type(p.Type).
-			Id(id).
+			Id(p.ID).
 			Doc(p.Doc), nil
 	case "delete":
 		return elastic.NewBulkDeleteRequest().
 			Index(p.Index).
 			Routing(p.Routing).
-			Id(id).
+			Id(p.ID).
 			Type(p.Type), nil
-	case "index":
+	case "index", "create":
 		return elastic.NewBulkIndexRequest().
+			OpType(p.Action).
 			Index(p.Index).
 			Pipeline(p.Pipeline).
 			Routing(p.Routing).
 			Type(p.Type).
-			Id(id).
+			Id(p.ID).
 			Doc(p.Doc), nil
 	default:
 		return nil, fmt.Errorf("elasticsearch action '%s' is not allowed", p.Action)
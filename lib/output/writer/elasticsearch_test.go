@@ -0,0 +1,245 @@
+package writer
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	ibatch "github.com/Jeffail/benthos/v3/internal/batch"
+	"github.com/Jeffail/benthos/v3/lib/log"
+	"github.com/Jeffail/benthos/v3/lib/message"
+	"github.com/Jeffail/benthos/v3/lib/metrics"
+	"github.com/Jeffail/benthos/v3/lib/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type elasticBulkAction struct {
+	Op  string
+	ID  string
+	Doc string
+}
+
+// elasticBulkServer mocks the Elasticsearch bulk API, statuses are consumed by
+// documents in the order of the requests received.
+func elasticBulkServer(t *testing.T, statuses map[string][]int) (*httptest.Server, func() [][]elasticBulkAction) {
+	t.Helper()
+
+	var mut sync.Mutex
+	var requests [][]elasticBulkAction
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !strings.HasSuffix(r.URL.Path, "/_bulk") {
+			w.WriteHeader(http.StatusOK)
+			_, _ = w.Write([]byte(`{}`))
+			return
+		}
+
+		mut.Lock()
+		defer mut.Unlock()
+
+		var actions []elasticBulkAction
+		scanner := bufio.NewScanner(r.Body)
+		for scanner.Scan() {
+			var meta map[string]map[string]interface{}
+			require.NoError(t, json.Unmarshal(scanner.Bytes(), &meta))
+			for op, v := range meta {
+				id, _ := v["_id"].(string)
+				require.True(t, scanner.Scan())
+				actions = append(actions, elasticBulkAction{Op: op, ID: id, Doc: scanner.Text()})
+			}
+		}
+		requests = append(requests, actions)
+
+		var items []map[string]interface{}
+		hasErrors := false
+		for _, a := range actions {
+			status := 201
+			if s := statuses[a.Doc]; len(s) > 0 {
+				status, statuses[a.Doc] = s[0], s[1:]
+			}
+			item := map[string]interface{}{"_id": a.ID, "status": status}
+			if status > 299 {
+				hasErrors = true
+				item["error"] = map[string]interface{}{
+					"type":   "test_error",
+					"reason": fmt.Sprintf("status %v", status),
+				}
+			}
+			items = append(items, map[string]interface{}{a.Op: item})
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{
+			"took":   1,
+			"errors": hasErrors,
+			"items":  items,
+		})
+	}))
+	t.Cleanup(srv.Close)
+
+	return srv, func() [][]elasticBulkAction {
+		mut.Lock()
+		defer mut.Unlock()
+		return requests
+	}
+}
+
+func testElasticWriter(t *testing.T, url string, fn func(c *ElasticsearchConfig)) *Elasticsearch {
+	t.Helper()
+
+	conf := NewElasticsearchConfig()
+	conf.URLs = []string{url}
+	conf.Sniff = false
+	conf.Healthcheck = false
+	conf.Backoff.InitialInterval = "1ms"
+	conf.Backoff.MaxInterval = "1ms"
+	conf.Backoff.MaxElapsedTime = ""
+	conf.MaxRetries = 3
+	if fn != nil {
+		fn(&conf)
+	}
+
+	e, err := NewElasticsearch(conf, log.Noop(), metrics.Noop())
+	require.NoError(t, err)
+	require.NoError(t, e.Connect())
+	return e
+}
+
+func TestElasticCreateAction(t *testing.T) {
+	srv, getRequests := elasticBulkServer(t, nil)
+
+	e := testElasticWriter(t, srv.URL, func(c *ElasticsearchConfig) {
+		c.Action = "create"
+		c.Index = "logs-benthos-default"
+		c.Type = ""
+		c.ID = ""
+	})
+
+	require.NoError(t, e.Write(message.New([][]byte{
+		[]byte(`{"a":1}`),
+		[]byte(`{"a":2}`),
+	})))
+
+	assert.Equal(t, [][]elasticBulkAction{
+		{
+			{Op: "create", Doc: `{"a":1}`},
+			{Op: "create", Doc: `{"a":2}`},
+		},
+	}, getRequests())
+}
+
+func TestElasticRetriesOnlyFailedDocuments(t *testing.T) {
+	srv, getRequests := elasticBulkServer(t, map[string][]int{
+		`{"a":2}`: {429, 503},
+	})
+
+	e := testElasticWriter(t, srv.URL, func(c *ElasticsearchConfig) {
+		c.ID = `${! json("a") }`
+	})
+
+	require.NoError(t, e.Write(message.New([][]byte{
+		[]byte(`{"a":1}`),
+		[]byte(`{"a":2}`),
+		[]byte(`{"a":3}`),
+	})))
+
+	assert.Equal(t, [][]elasticBulkAction{
+		{
+			{Op: "index", ID: "1", Doc: `{"a":1}`},
+			{Op: "index", ID: "2", Doc: `{"a":2}`},
+			{Op: "index", ID: "3", Doc: `{"a":3}`},
+		},
+		{
+			{Op: "index", ID: "2", Doc: `{"a":2}`},
+		},
+		{
+			{Op: "index", ID: "2", Doc: `{"a":2}`},
+		},
+	}, getRequests())
+}
+
+func TestElasticRejectedDocumentsIndexed(t *testing.T) {
+	srv, getRequests := elasticBulkServer(t, map[string][]int{
+		`{"a":2}`: {409},
+		`{"a":3}`: {503},
+		`{"a":4}`: {400},
+	})
+
+	e := testElasticWriter(t, srv.URL, nil)
+
+	err := e.Write(message.New([][]byte{
+		[]byte(`{"a":1}`),
+		[]byte(`{"a":2}`),
+		[]byte(`{"a":3}`),
+		[]byte(`{"a":4}`),
+	}))
+	require.Error(t, err)
+
+	bErr, ok := err.(*ibatch.Error)
+	require.True(t, ok, "%T", err)
+
+	failed := map[int]string{}
+	bErr.WalkParts(func(i int, _ types.Part, err error) bool {
+		if err != nil {
+			failed[i] = err.Error()
+		}
+		return true
+	})
+	assert.Equal(t, map[int]string{
+		1: "[409]: status 409",
+		3: "[400]: status 400",
+	}, failed)
+
+	assert.Len(t, getRequests(), 2)
+}
+
+func TestElasticRetriesExhausted(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"took":1,"errors":true,"items":[{"index":{"_id":"1","status":503,"error":{"type":"test_error","reason":"unavailable"}}}]}`))
+	}))
+	t.Cleanup(srv.Close)
+
+	e := testElasticWriter(t, srv.URL, nil)
+
+	err := e.Write(message.New([][]byte{
+		[]byte(`{"a":1}`),
+	}))
+	require.Error(t, err)
+
+	bErr, ok := err.(*ibatch.Error)
+	require.True(t, ok, "%T", err)
+	assert.Equal(t, 1, bErr.IndexedErrors())
+	assert.EqualError(t, err, "[503]: unavailable")
+}
+
+func TestElasticAPIKey(t *testing.T) {
+	var mut sync.Mutex
+	var authHeaders []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mut.Lock()
+		authHeaders = append(authHeaders, r.Header.Get("Authorization"))
+		mut.Unlock()
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"took":1,"errors":false,"items":[{"index":{"_id":"1","status":201}}]}`))
+	}))
+	t.Cleanup(srv.Close)
+
+	e := testElasticWriter(t, srv.URL, func(c *ElasticsearchConfig) {
+		c.APIKey = "Zm9vOmJhcg=="
+	})
+	require.NoError(t, e.Write(message.New([][]byte{[]byte(`{"a":1}`)})))
+
+	mut.Lock()
+	defer mut.Unlock()
+	require.NotEmpty(t, authHeaders)
+	for _, h := range authHeaders {
+		assert.Equal(t, "ApiKey Zm9vOmJhcg==", h)
+	}
+}
@@ -96,6 +96,9 @@ pipeline:
 	Constructors[TypeLambda] = TypeSpec{
 		constructor: NewLambda,
 		Status:      docs.StatusDeprecated,
+		ReplacedBy: &docs.ComponentReplacement{
+			Name: TypeAWSLambda,
+		},
 		Categories: []Category{
 			CategoryIntegration,
 		},
@@ -4,10 +4,10 @@ import (
 	"fmt"
 	"time"
 
-	"github.com/Jeffail/benthos/v3/internal/bloblang"
 	"github.com/Jeffail/benthos/v3/internal/bloblang/mapping"
 	"github.com/Jeffail/benthos/v3/internal/bloblang/parser"
 	"github.com/Jeffail/benthos/v3/internal/docs"
+	"github.com/Jeffail/benthos/v3/internal/interop"
 	"github.com/Jeffail/benthos/v3/lib/log"
 	"github.com/Jeffail/benthos/v3/lib/message"
 	"github.com/Jeffail/benthos/v3/lib/message/tracing"
@@ -136,7 +136,8 @@ func NewBloblangConfig() BloblangConfig {
 
 // Bloblang is a processor that performs a Bloblang mapping.
 type Bloblang struct {
-	exec *mapping.Executor
+	exec    *mapping.Executor
+	release func()
 
 	log   log.Modular
 	stats metrics.Type
@@ -152,20 +153,27 @@ type Bloblang struct {
 func NewBloblang(
 	conf Config, mgr types.Manager, log log.Modular, stats metrics.Type,
 ) (Type, error) {
-	exec, err := bloblang.NewMapping("", string(conf.Bloblang))
+	// Each processing thread constructs its own processor, and so the mapping
+	// is shared between them in order to only parse it once.
+	exec, release, err := interop.BloblangEnvironment(mgr).NewSharedMapping("", string(conf.Bloblang))
 	if err != nil {
 		if perr, ok := err.(*parser.Error); ok {
 			return nil, fmt.Errorf("%v", perr.ErrorAtPosition([]rune(conf.Bloblang)))
 		}
 		return nil, err
 	}
-	return NewBloblangFromExecutor(exec, log, stats), nil
+	return newBloblang(exec, release, log, stats), nil
 }
 
 // NewBloblangFromExecutor returns a Bloblang processor.
 func NewBloblangFromExecutor(exec *mapping.Executor, log log.Modular, stats metrics.Type) Type {
+	return newBloblang(exec, nil, log, stats)
+}
+
+func newBloblang(exec *mapping.Executor, release func(), log log.Modular, stats metrics.Type) *Bloblang {
 	return &Bloblang{
-		exec: exec,
+		exec:    exec,
+		release: release,
 
 		log:   log,
 		stats: stats,
@@ -234,6 +242,9 @@ func (b *Bloblang) ProcessMessage(msg types.Message) ([]types.Message, types.Res
 
 // CloseAsync shuts down the processor and stops processing requests.
 func (b *Bloblang) CloseAsync() {
+	if b.release != nil {
+		b.release()
+	}
 }
 
 // WaitForClose blocks until the processor has closed down.
@@ -52,6 +52,10 @@ type TypeSpec struct {
 	config      docs.FieldSpec
 	FieldSpecs  docs.FieldSpecs
 	Examples    []docs.AnnotatedExample
+
+	// ReplacedBy describes the component that replaces this one when it
+	// is deprecated.
+	ReplacedBy *docs.ComponentReplacement
 }
 
 // ConstructorFunc is a func signature able to construct a processor.
@@ -77,6 +81,7 @@ func WalkConstructors(fn func(ConstructorFunc, docs.ComponentSpec)) {
 			Config:      conf,
 			Status:      v.Status,
 			Version:     v.Version,
+			ReplacedBy:  v.ReplacedBy,
 		}
 		if len(v.Categories) > 0 {
 			spec.Categories = make([]string, 0, len(v.Categories))
@@ -123,16 +123,22 @@ func ExecuteCatchAll(procs []types.Processor, msgs ...types.Message) ([]types.Me
 // be interpretted as having failed a processor step somewhere in the pipeline.
 var FailFlagKey = types.FailFlagKey
 
-// FlagFail marks a message part as having failed at a processing step.
+// FlagFail marks a message part as having failed at a processing step. Any
+// context of a previous failure is removed.
 func FlagFail(part types.Part) {
 	part.Metadata().Set(FailFlagKey, "true")
+	part.Metadata().Delete(types.FailLabelKey)
+	part.Metadata().Delete(types.FailPathKey)
 }
 
 // FlagErr marks a message part as having failed at a processing step with an
-// error message. If the error is nil the message part remains unchanged.
+// error message. If the error is nil the message part remains unchanged, and
+// otherwise any context of a previous failure is removed.
 func FlagErr(part types.Part, err error) {
 	if err != nil {
 		part.Metadata().Set(FailFlagKey, err.Error())
+		part.Metadata().Delete(types.FailLabelKey)
+		part.Metadata().Delete(types.FailPathKey)
 	}
 }
 
@@ -150,6 +156,8 @@ func HasFailed(part types.Part) bool {
 // ClearFail removes any existing failure flags from a message part.
 func ClearFail(part types.Part) {
 	part.Metadata().Delete(FailFlagKey)
+	part.Metadata().Delete(types.FailLabelKey)
+	part.Metadata().Delete(types.FailPathKey)
 }
 
 //------------------------------------------------------------------------------
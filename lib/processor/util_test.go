@@ -214,3 +214,31 @@ func TestExecuteAllErrored(t *testing.T) {
 }
 
 //------------------------------------------------------------------------------
+
+func TestFlagErrClearsContext(t *testing.T) {
+	part := message.NewPart(nil)
+	part.Metadata().Set(types.FailLabelKey, "foo")
+	part.Metadata().Set(types.FailPathKey, "pipeline.processors.0")
+
+	FlagErr(part, nil)
+	if exp, act := "pipeline.processors.0", part.Metadata().Get(types.FailPathKey); exp != act {
+		t.Errorf("Wrong fail path: %v != %v", act, exp)
+	}
+
+	FlagErr(part, errors.New("nope"))
+	if exp, act := "nope", GetFail(part); exp != act {
+		t.Errorf("Wrong fail flag: %v != %v", act, exp)
+	}
+	if act := part.Metadata().Get(types.FailLabelKey); act != "" {
+		t.Errorf("Fail label was not removed: %v", act)
+	}
+	if act := part.Metadata().Get(types.FailPathKey); act != "" {
+		t.Errorf("Fail path was not removed: %v", act)
+	}
+
+	part.Metadata().Set(types.FailPathKey, "pipeline.processors.0")
+	FlagFail(part)
+	if act := part.Metadata().Get(types.FailPathKey); act != "" {
+		t.Errorf("Fail path was not removed: %v", act)
+	}
+}
@@ -35,8 +35,11 @@ func Run() {
 	conf.Output.Type = serverless.ServerlessResponseType
 
 	if confStr := os.Getenv("BENTHOS_CONFIG"); len(confStr) > 0 {
-		confBytes := text.ReplaceEnvVariables([]byte(confStr))
-		if err := yaml.Unmarshal(confBytes, &conf); err != nil {
+		confBytes, err := text.ReplaceEnvVariablesAndSecrets([]byte(confStr))
+		if err == nil {
+			err = yaml.Unmarshal(confBytes, &conf)
+		}
+		if err != nil {
 			fmt.Fprintf(os.Stderr, "Configuration file read error: %v\n", err)
 			os.Exit(1)
 		}
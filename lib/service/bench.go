@@ -0,0 +1,351 @@
+package service
+
+import (
+	"bufio"
+	"fmt"
+	"math/rand"
+	"os"
+	"runtime"
+	"sort"
+	"text/tabwriter"
+	"time"
+
+	"github.com/Jeffail/benthos/v3/internal/interop"
+	"github.com/Jeffail/benthos/v3/lib/input"
+	"github.com/Jeffail/benthos/v3/lib/log"
+	"github.com/Jeffail/benthos/v3/lib/manager"
+	"github.com/Jeffail/benthos/v3/lib/message"
+	"github.com/Jeffail/benthos/v3/lib/metrics"
+	"github.com/Jeffail/benthos/v3/lib/processor"
+	"github.com/Jeffail/benthos/v3/lib/response"
+	"github.com/Jeffail/benthos/v3/lib/types"
+	"github.com/urfave/cli/v2"
+	"gopkg.in/yaml.v3"
+)
+
+//------------------------------------------------------------------------------
+
+// benchSampleSize is the maximum number of latency samples, and batches used
+// for measuring allocations, retained for each processor.
+const benchSampleSize = 10000
+
+// latencySampler aggregates durations, retaining a uniform random sample of
+// them for calculating percentiles.
+type latencySampler struct {
+	count   int64
+	total   time.Duration
+	max     time.Duration
+	samples []time.Duration
+}
+
+func (l *latencySampler) add(d time.Duration) {
+	l.count++
+	l.total += d
+	if d > l.max {
+		l.max = d
+	}
+	if len(l.samples) < benchSampleSize {
+		l.samples = append(l.samples, d)
+	} else if i := rand.Int63n(l.count); i < benchSampleSize {
+		l.samples[i] = d
+	}
+}
+
+func (l *latencySampler) mean() time.Duration {
+	if l.count == 0 {
+		return 0
+	}
+	return l.total / time.Duration(l.count)
+}
+
+func (l *latencySampler) percentiles(ps ...float64) []time.Duration {
+	sorted := make([]time.Duration, len(l.samples))
+	copy(sorted, l.samples)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+
+	results := make([]time.Duration, len(ps))
+	if len(sorted) == 0 {
+		return results
+	}
+	for i, p := range ps {
+		results[i] = sorted[int(p*float64(len(sorted)-1))]
+	}
+	return results
+}
+
+func (l *latencySampler) row(name string) string {
+	ps := l.percentiles(0.5, 0.9, 0.99)
+	return fmt.Sprintf("%v\t%v\t%v\t%v\t%v\t%v", name, l.mean(), ps[0], ps[1], ps[2], l.max)
+}
+
+//------------------------------------------------------------------------------
+
+type benchProcessor struct {
+	name    string
+	proc    types.Processor
+	latency latencySampler
+	errors  int64
+	samples []types.Message
+}
+
+// measureAllocs processes the sampled batches of a processor in isolation in
+// order to calculate the mean allocations per batch.
+func (b *benchProcessor) measureAllocs() (allocs, bytes uint64) {
+	if len(b.samples) == 0 {
+		return 0, 0
+	}
+
+	var before, after runtime.MemStats
+	runtime.GC()
+	runtime.ReadMemStats(&before)
+	for _, msg := range b.samples {
+		_, _ = b.proc.ProcessMessage(msg)
+	}
+	runtime.ReadMemStats(&after)
+
+	n := uint64(len(b.samples))
+	return (after.Mallocs - before.Mallocs) / n, (after.TotalAlloc - before.TotalAlloc) / n
+}
+
+// countFailed returns the number of messages within batches that are flagged
+// as having failed processing.
+func countFailed(batches ...types.Message) (n int64) {
+	for _, b := range batches {
+		_ = b.Iter(func(_ int, part types.Part) error {
+			if processor.HasFailed(part) {
+				n++
+			}
+			return nil
+		})
+	}
+	return
+}
+
+func readReplayLines(path string) ([][]byte, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var lines [][]byte
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(nil, 64*1024*1024)
+	for scanner.Scan() {
+		if len(scanner.Bytes()) == 0 {
+			continue
+		}
+		lines = append(lines, append([]byte(nil), scanner.Bytes()...))
+	}
+	if err = scanner.Err(); err != nil {
+		return nil, err
+	}
+	if len(lines) == 0 {
+		return nil, fmt.Errorf("file %v does not contain any messages", path)
+	}
+	return lines, nil
+}
+
+func cmdBench(duration time.Duration, inputOverride, replayPath string) int {
+	logger := log.Noop()
+	stats := metrics.Noop()
+
+	if inputOverride != "" {
+		conf.Input = input.NewConfig()
+		if err := yaml.Unmarshal([]byte(inputOverride), &conf.Input); err != nil {
+			fmt.Fprintf(os.Stderr, "Failed to parse input override: %v\n", err)
+			return 1
+		}
+	}
+
+	mgr, err := manager.NewV2(conf.ResourceConfig, types.DudMgr{}, logger, stats)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to create resources: %v\n", err)
+		return 1
+	}
+	defer func() {
+		mgr.CloseAsync()
+		_ = mgr.WaitForClose(time.Second * 10)
+	}()
+
+	// Batches are either replayed from a file or read from the input.
+	var nextBatch func() (types.Message, func(), bool)
+	var closeInput func()
+	source := conf.Input.Type
+	if replayPath != "" {
+		lines, err := readReplayLines(replayPath)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Failed to read replay file: %v\n", err)
+			return 1
+		}
+		source = replayPath
+		i := 0
+		nextBatch = func() (types.Message, func(), bool) {
+			msg := message.New([][]byte{lines[i%len(lines)]})
+			i++
+			return msg, func() {}, true
+		}
+		closeInput = func() {}
+	} else {
+		iMgr, iLog, iStats := interop.LabelChild("input", mgr, logger, stats)
+		in, err := input.New(conf.Input, iMgr, iLog, iStats)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Failed to create input: %v\n", err)
+			return 1
+		}
+		nextBatch = func() (types.Message, func(), bool) {
+			tran, open := <-in.TransactionChan()
+			if !open {
+				return nil, nil, false
+			}
+			return tran.Payload, func() {
+				tran.ResponseChan <- response.NewAck()
+			}, true
+		}
+		closeInput = func() {
+			in.CloseAsync()
+			_ = in.WaitForClose(time.Second * 10)
+		}
+	}
+
+	pMgr, pLog, pStats := interop.LabelChild("pipeline", mgr, logger, stats)
+	procs := make([]*benchProcessor, len(conf.Pipeline.Processors))
+	for i, pConf := range conf.Pipeline.Processors {
+		procMgr, procLog, procStats := interop.LabelChild(fmt.Sprintf("processor.%v", i), pMgr, pLog, pStats)
+		proc, err := processor.New(pConf, procMgr, procLog, procStats)
+		if err != nil {
+			closeInput()
+			fmt.Fprintf(os.Stderr, "Failed to create processor %v (%v): %v\n", i, pConf.Type, err)
+			return 1
+		}
+		defer func() {
+			proc.CloseAsync()
+			_ = proc.WaitForClose(time.Second * 10)
+		}()
+		procs[i] = &benchProcessor{
+			name: fmt.Sprintf("pipeline.processors.%v (%v)", i, pConf.Type),
+			proc: proc,
+		}
+	}
+
+	fmt.Printf("Benchmarking %v processors with messages from %v for %v...\n", len(procs), source, duration)
+
+	var total latencySampler
+	var messages int64
+	started := time.Now()
+	deadline := started.Add(duration)
+	for time.Now().Before(deadline) {
+		msg, ack, open := nextBatch()
+		if !open {
+			break
+		}
+		messages += int64(msg.Len())
+
+		// Processors are executed sequentially within a single goroutine in
+		// order to measure each of them in isolation.
+		batchStarted := time.Now()
+		batches := []types.Message{msg}
+		for _, p := range procs {
+			var nextBatches []types.Message
+			for _, b := range batches {
+				if len(p.samples) < benchSampleSize {
+					p.samples = append(p.samples, b.DeepCopy())
+				}
+				// Only count the errors introduced by this processor.
+				failedBefore := countFailed(b)
+				procStarted := time.Now()
+				results, _ := p.proc.ProcessMessage(b)
+				p.latency.add(time.Since(procStarted))
+				if failed := countFailed(results...) - failedBefore; failed > 0 {
+					p.errors += failed
+				}
+				nextBatches = append(nextBatches, results...)
+			}
+			batches = nextBatches
+		}
+		total.add(time.Since(batchStarted))
+		ack()
+	}
+	elapsed := time.Since(started)
+	closeInput()
+
+	fmt.Printf(
+		"Processed %v messages in %v batches over %v (%.1f msg/s)\n\n",
+		messages, total.count, elapsed.Round(time.Millisecond), float64(messages)/elapsed.Seconds(),
+	)
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(w, "PROCESSOR\tMEAN\tP50\tP90\tP99\tMAX\tERRORS\tALLOCS/OP\tBYTES/OP")
+	for _, p := range procs {
+		allocs, bytes := p.measureAllocs()
+		fmt.Fprintf(w, "%v\t%v\t%v\t%v\n", p.latency.row(p.name), p.errors, allocs, bytes)
+	}
+	fmt.Fprintf(w, "%v\t\t\t\n", total.row("total"))
+	w.Flush()
+	return 0
+}
+
+func benchCliCommand() *cli.Command {
+	return &cli.Command{
+		Name:  "bench",
+		Usage: "Benchmark the processors of a config",
+		Description: `
+   Executes the pipeline processors of a config with messages from its input
+   for a fixed duration, and then reports the throughput along with the latency
+   percentiles and allocations of each processor. The output of the config is
+   not executed.
+
+   benthos -c ./config.yaml bench --duration 30s
+
+   The input of the config can be replaced with the --input-override flag, or
+   messages can be replayed from the lines of a file with the --replay flag:
+
+   benthos -c ./config.yaml bench --input-override 'generate: { mapping: "root.id = uuid_v4()", interval: "" }'
+   benthos -c ./config.yaml bench --replay ./sample.jsonl
+
+   Processors are executed sequentially within a single thread regardless of
+   the configured number of pipeline threads.`[4:],
+		Flags: []cli.Flag{
+			&cli.StringFlag{
+				Name:  "duration",
+				Value: "10s",
+				Usage: "The period of time to execute the processors for.",
+			},
+			&cli.StringFlag{
+				Name:  "input-override",
+				Value: "",
+				Usage: "A YAML input config to consume messages from instead of the input of the config.",
+			},
+			&cli.StringFlag{
+				Name:  "replay",
+				Value: "",
+				Usage: "A path to a file where each line is a message to replay instead of consuming from the input of the config. The messages are repeated until the duration has elapsed.",
+			},
+		},
+		Action: func(c *cli.Context) error {
+			duration, err := time.ParseDuration(c.String("duration"))
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Failed to parse duration: %v\n", err)
+				os.Exit(1)
+			}
+			if c.String("input-override") != "" && c.String("replay") != "" {
+				fmt.Fprintln(os.Stderr, "The --input-override and --replay flags cannot be used together")
+				os.Exit(1)
+			}
+
+			lints := readConfig(c.StringSlice("config"), c.StringSlice("resources"), c.StringSlice("set"))
+			if len(lints) > 0 {
+				for _, lint := range lints {
+					fmt.Fprintln(os.Stderr, lint)
+				}
+				if !c.Bool("chilled") {
+					fmt.Println("Shutting down due to linter errors, to prevent shutdown run Benthos with --chilled")
+					os.Exit(1)
+				}
+			}
+
+			os.Exit(cmdBench(duration, c.String("input-override"), c.String("replay")))
+			return nil
+		},
+	}
+}
@@ -61,14 +61,120 @@ func CliCommand() *cli.Command {
 				Usage: "Set the buffer size for document lines.",
 				Value: bufio.MaxScanTokenSize,
 			},
+			&cli.BoolFlag{
+				Name:  "trace",
+				Usage: "print the value produced by each assignment and method of the mapping to stderr for each document, this forces a single processing thread.",
+			},
 		},
 		Action: run,
 		Subcommands: []*cli.Command{
 			{
-				Name:        "server",
-				Usage:       "EXPERIMENTAL: Run a web server that hosts a Bloblang app",
-				Description: "Run a web server that provides an interactive application for writing and testing Bloblang mappings.",
-				Action:      runServer,
+				Name:        "repl",
+				Usage:       "Run an interactive shell for executing Bloblang mappings",
+				Description: "Run an interactive shell where Bloblang queries and mappings can be executed against a loaded input document, with variables and metadata persisting between executions. Use :help within the shell to list available commands.",
+				Action:      runRepl,
+				Flags: []cli.Flag{
+					&cli.StringFlag{
+						Name:    "input-file",
+						Value:   "",
+						Aliases: []string{"i"},
+						Usage:   "an optional path to an input file to load as the initial input document.",
+					},
+					&cli.BoolFlag{
+						Name:    "raw",
+						Aliases: []string{"r"},
+						Usage:   "treat the input document as a raw string.",
+					},
+					&cli.IntFlag{
+						Name:  "max-token-length",
+						Usage: "Set the buffer size for lines read from stdin when it is not a terminal.",
+						Value: bufio.MaxScanTokenSize,
+					},
+				},
+			},
+			{
+				Name:  "fmt",
+				Usage: "Format Bloblang mapping files in the canonical style",
+				Description: `
+Formats Bloblang mappings in the canonical style, where lines are indented by
+two spaces for each level of nesting, tokens are consistently spaced and long
+chains of method calls are wrapped.
+
+When no files are specified a mapping is read from stdin and the formatted
+result is written to stdout:
+
+benthos blobl fmt < ./mapping.blobl
+
+When files are specified they are formatted to stdout unless --write is set,
+in which case the files are overwritten, or --check is set, in which case the
+paths of files that are not formatted are printed and the command exits with
+a status code of 1, which is useful within pre-commit hooks:
+
+benthos blobl fmt --check ./mappings/*.blobl`[1:],
+				Action: runFmt,
+				Flags: []cli.Flag{
+					&cli.BoolFlag{
+						Name:    "write",
+						Aliases: []string{"w"},
+						Usage:   "write the formatted result back to the source files.",
+					},
+					&cli.BoolFlag{
+						Name:    "check",
+						Aliases: []string{"c"},
+						Usage:   "list the files that are not formatted and exit with a status code of 1 if there are any.",
+					},
+				},
+			},
+			{
+				Name:  "get",
+				Usage: "Fetch versioned libraries of mapping files from git repositories",
+				Description: `
+Fetches libraries of mapping files from git repositories into a local modules
+directory, from which they can be imported by mappings. A version, which is a
+tag or branch of the repository, can be specified by adding it to the URL:
+
+benthos blobl get https://github.com/acme/mappings.git@v1.2.0
+
+Files of a fetched library are imported by prefixing their path within the
+repository with the path of the library, which is the URL of the repository
+without its scheme and .git suffix:
+
+import "github.com/acme/mappings/normalize.blobl"
+
+Imports that are not explicitly relative (prefixed with ./ or ../) and are not
+found relative to the importing mapping are resolved from the modules
+directory within the directory of the mapping, or the closest of its parents
+to have one.
+
+The version and commit of each library fetched are recorded within the modules
+directory, and running the command without arguments fetches all recorded
+libraries again at their recorded commits, which is useful when the modules
+directory isn't checked into version control.`[1:],
+				Action: runGet,
+				Flags: []cli.Flag{
+					&cli.StringFlag{
+						Name:  "dir",
+						Value: parser.ModulesDir,
+						Usage: "the modules directory to fetch libraries into.",
+					},
+				},
+			},
+			{
+				Name:  "server",
+				Usage: "EXPERIMENTAL: Run a web server that hosts a Bloblang app",
+				Description: `
+Run a web server that provides an interactive application for writing and
+testing Bloblang mappings.
+
+The server also exposes the endpoint POST /execute, which accepts a JSON
+object containing a mapping, an input document and optional metadata:
+
+{"mapping":"root = this.foo","input":"{\"foo\":\"bar\"}","metadata":{"a":"b"}}
+
+And responds with the result and resulting metadata, or an error object
+containing a type (parse or mapping), a message and, for parse errors, the
+line and column at which the error occurred.`[1:],
+				Action: runServer,
 				Flags: []cli.Flag{
 					&cli.StringFlag{
 						Name:  "host",
@@ -213,8 +319,6 @@ func run(c *cli.Context) error {
 	file := c.String("file")
 	m := c.Args().First()
 
-	execCache := newExecCache()
-
 	if len(file) > 0 {
 		if len(m) > 0 {
 			fmt.Fprintln(os.Stderr, red("invalid flags, unable to execute both a file mapping and an inline mapping"))
@@ -228,7 +332,20 @@ func run(c *cli.Context) error {
 		m = string(mappingBytes)
 	}
 
-	exec, err := bloblang.NewMapping(file, m)
+	var exec *mapping.Executor
+	var trace *parser.Trace
+	var err error
+	if c.Bool("trace") {
+		// Steps are recorded in the order they're executed, and therefore
+		// documents must be mapped one at a time.
+		t = 1
+		var perr *parser.Error
+		if exec, trace, perr = parser.ParseMappingWithTrace(parser.GlobalContext(), file, m); perr != nil {
+			err = perr
+		}
+	} else {
+		exec, err = bloblang.NewMapping(file, m)
+	}
 	if err != nil {
 		if perr, ok := err.(*parser.Error); ok {
 			fmt.Fprintf(os.Stderr, "%v %v\n", red("failed to parse mapping:"), perr.ErrorAtPositionStructured("", []rune(m)))
@@ -267,6 +384,9 @@ func run(c *cli.Context) error {
 		go func() {
 			defer wg.Done()
 
+			// The message and variables of a cache are reused for each
+			// document and therefore each thread requires its own.
+			execCache := newExecCache()
 			for {
 				input, open := <-inputsChan
 				if !open {
@@ -274,10 +394,19 @@ func run(c *cli.Context) error {
 				}
 
 				resultStr, err := execCache.executeMapping(exec, raw, pretty, input)
+				if trace != nil {
+					printTrace(os.Stderr, m, trace)
+					trace.Reset()
+				}
 				if err != nil {
 					fmt.Fprintln(os.Stderr, red(fmt.Sprintf("failed to execute map: %v", err)))
 					continue
 				}
+				if trace != nil {
+					// Print the result before the next document is traced.
+					fmt.Println(resultStr)
+					continue
+				}
 				resultsChan <- resultStr
 			}
 		}()
@@ -0,0 +1,92 @@
+package blobl
+
+import (
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+
+	"github.com/Jeffail/benthos/v3/internal/bloblang"
+	"github.com/Jeffail/benthos/v3/internal/bloblang/parser"
+	"github.com/urfave/cli/v2"
+)
+
+var errUnformatted = errors.New("mapping is not formatted")
+
+func formatMapping(path, m string) (string, error) {
+	formatted, err := bloblang.GlobalEnvironment().FormatMapping(path, m)
+	if err != nil {
+		if perr, ok := err.(*parser.Error); ok {
+			return "", errors.New(perr.ErrorAtPosition([]rune(m)))
+		}
+		return "", err
+	}
+	return formatted, nil
+}
+
+// formatFile formats a mapping file and either writes the result back to the
+// file, reports whether the file is already formatted, or prints the result.
+func formatFile(c *cli.Context, path string) error {
+	mappingBytes, err := ioutil.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("failed to read mapping file: %w", err)
+	}
+
+	formatted, err := formatMapping(path, string(mappingBytes))
+	if err != nil {
+		return err
+	}
+
+	switch {
+	case c.Bool("check"):
+		if formatted != string(mappingBytes) {
+			return errUnformatted
+		}
+	case c.Bool("write"):
+		if formatted != string(mappingBytes) {
+			if err := ioutil.WriteFile(path, []byte(formatted), 0644); err != nil {
+				return fmt.Errorf("failed to write mapping file: %w", err)
+			}
+		}
+	default:
+		fmt.Print(formatted)
+	}
+	return nil
+}
+
+func runFmt(c *cli.Context) error {
+	if c.Args().Len() == 0 {
+		if c.Bool("write") || c.Bool("check") {
+			fmt.Fprintln(os.Stderr, red("invalid flags, one or more mapping files must be specified in order to use --write or --check"))
+			os.Exit(1)
+		}
+		mappingBytes, err := ioutil.ReadAll(os.Stdin)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, red("failed to read mapping from stdin: %v\n"), err)
+			os.Exit(1)
+		}
+		formatted, err := formatMapping("", string(mappingBytes))
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "%v %v\n", red("failed to format mapping:"), err)
+			os.Exit(1)
+		}
+		fmt.Print(formatted)
+		return nil
+	}
+
+	failed := false
+	for _, path := range c.Args().Slice() {
+		if err := formatFile(c, path); err != nil {
+			failed = true
+			if errors.Is(err, errUnformatted) {
+				fmt.Println(path)
+			} else {
+				fmt.Fprintf(os.Stderr, "%v: %v %v\n", path, red("failed to format mapping:"), err)
+			}
+		}
+	}
+	if failed {
+		os.Exit(1)
+	}
+	return nil
+}
@@ -0,0 +1,107 @@
+package blobl
+
+import (
+	"flag"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/urfave/cli/v2"
+)
+
+func TestFormatMapping(t *testing.T) {
+	formatted, err := formatMapping("", "root.a   =    this.foo\nroot.b = this.bar.uppercase( )")
+	require.NoError(t, err)
+	assert.Equal(t, "root.a = this.foo\nroot.b = this.bar.uppercase()\n", formatted)
+
+	_, err = formatMapping("", "root.a = this.foo.")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "line 1 char")
+}
+
+func testFmtContext(t *testing.T, write, check bool) *cli.Context {
+	t.Helper()
+	set := flag.NewFlagSet("fmt", flag.ContinueOnError)
+	set.Bool("write", write, "")
+	set.Bool("check", check, "")
+	return cli.NewContext(cli.NewApp(), set, nil)
+}
+
+func TestFormatFile(t *testing.T) {
+	unformatted := "root.a   =    this.foo\n"
+	formatted := "root.a = this.foo\n"
+
+	tests := []struct {
+		name     string
+		write    bool
+		check    bool
+		content  string
+		expected string
+		err      error
+	}{
+		{
+			name:     "check unformatted",
+			check:    true,
+			content:  unformatted,
+			expected: unformatted,
+			err:      errUnformatted,
+		},
+		{
+			name:     "check formatted",
+			check:    true,
+			content:  formatted,
+			expected: formatted,
+		},
+		{
+			name:     "write unformatted",
+			write:    true,
+			content:  unformatted,
+			expected: formatted,
+		},
+		{
+			name:     "write formatted",
+			write:    true,
+			content:  formatted,
+			expected: formatted,
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "mapping.blobl")
+			require.NoError(t, os.WriteFile(path, []byte(test.content), 0644))
+
+			err := formatFile(testFmtContext(t, test.write, test.check), path)
+			if test.err != nil {
+				assert.ErrorIs(t, err, test.err)
+			} else {
+				assert.NoError(t, err)
+			}
+
+			b, err := os.ReadFile(path)
+			require.NoError(t, err)
+			assert.Equal(t, test.expected, string(b))
+		})
+	}
+}
+
+func TestFormatFileErrors(t *testing.T) {
+	dir := t.TempDir()
+
+	err := formatFile(testFmtContext(t, true, false), filepath.Join(dir, "nope.blobl"))
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "failed to read mapping file")
+
+	path := filepath.Join(dir, "bad.blobl")
+	require.NoError(t, os.WriteFile(path, []byte("root = this."), 0644))
+
+	err = formatFile(testFmtContext(t, true, false), path)
+	require.Error(t, err)
+
+	b, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, "root = this.", string(b))
+}
@@ -0,0 +1,287 @@
+package blobl
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/url"
+	"os"
+	"os/exec"
+	"path"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/urfave/cli/v2"
+)
+
+// modulesLockFile is the name of the file within the modules directory that
+// records the version of each module that has been fetched, allowing the
+// directory to be reproduced by running `benthos blobl get` without arguments.
+const modulesLockFile = "modules.txt"
+
+var (
+	scpLikeURLRegexp = regexp.MustCompile(`^[\w.-]+@[\w.-]+:`)
+	commitRegexp     = regexp.MustCompile(`^[0-9a-f]{40}$|^[0-9a-f]{64}$`)
+)
+
+// module is a versioned library of mapping files hosted within a git
+// repository.
+type module struct {
+	// The path of the module within the modules directory, which is also the
+	// prefix of import statements that reference files of the module.
+	Path string
+
+	// The URL of the git repository that hosts the module.
+	URL string
+
+	// The tag or branch of the repository to fetch, when empty the default
+	// branch is fetched.
+	Version string
+
+	// The commit that was fetched, when set the module is fetched at this
+	// commit rather than the latest commit of the version.
+	Commit string
+}
+
+func (m module) String() string {
+	if m.Version == "" {
+		return m.Path
+	}
+	return m.Path + "@" + m.Version
+}
+
+// parseModule parses a git URL with an optional version suffix, e.g.
+// `https://github.com/acme/mappings.git@v1.2.0`, into a module.
+func parseModule(ref string) (module, error) {
+	m := module{URL: ref}
+	if i := strings.LastIndex(ref, "@"); i > strings.LastIndex(ref, "/") {
+		m.URL, m.Version = ref[:i], ref[i+1:]
+	}
+
+	modPath := m.URL
+	switch {
+	case strings.Contains(m.URL, "://"):
+		u, err := url.Parse(m.URL)
+		if err != nil {
+			return m, fmt.Errorf("failed to parse module URL: %w", err)
+		}
+		modPath = u.Hostname() + u.Path
+	case scpLikeURLRegexp.MatchString(m.URL):
+		modPath = strings.Replace(m.URL[strings.Index(m.URL, "@")+1:], ":", "/", 1)
+	case !filepath.IsAbs(m.URL):
+		m.URL = "https://" + m.URL
+	}
+
+	modPath = path.Clean(strings.TrimSuffix(strings.Trim(filepath.ToSlash(modPath), "/"), ".git"))
+	if modPath == "." || modPath == ".." || strings.HasPrefix(modPath, "../") {
+		return m, fmt.Errorf("unable to infer a module path from URL: %v", m.URL)
+	}
+	m.Path = modPath
+	return m, nil
+}
+
+// readModulesLock reads the modules recorded within a modules directory.
+func readModulesLock(dir string) ([]module, error) {
+	f, err := os.Open(filepath.Join(dir, modulesLockFile))
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	defer f.Close()
+
+	var mods []module
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		fields := strings.Fields(line)
+		if len(fields) != 4 {
+			return nil, fmt.Errorf("malformed line in %v: %v", modulesLockFile, line)
+		}
+		m := module{Path: fields[0], URL: fields[1], Version: fields[2], Commit: fields[3]}
+		if m.Version == "-" {
+			m.Version = ""
+		}
+		mods = append(mods, m)
+	}
+	return mods, scanner.Err()
+}
+
+// writeModulesLock writes the modules of a modules directory, sorted by path.
+func writeModulesLock(dir string, mods []module) error {
+	sort.Slice(mods, func(i, j int) bool {
+		return mods[i].Path < mods[j].Path
+	})
+
+	var b strings.Builder
+	b.WriteString("# Mapping libraries fetched with `benthos blobl get`, do not edit.\n")
+	for _, m := range mods {
+		version := m.Version
+		if version == "" {
+			version = "-"
+		}
+		fmt.Fprintf(&b, "%v %v %v %v\n", m.Path, m.URL, version, m.Commit)
+	}
+	return ioutil.WriteFile(filepath.Join(dir, modulesLockFile), []byte(b.String()), 0644)
+}
+
+// moduleTarget returns the directory of a module within the modules directory,
+// or an error if the path of the module would resolve outside of it. The paths
+// of modules are read from the lock file, and therefore must be validated
+// before anything is written to or removed from them.
+func moduleTarget(dir, modPath string) (string, error) {
+	if modPath == "" || path.IsAbs(modPath) || filepath.IsAbs(modPath) || filepath.VolumeName(modPath) != "" {
+		return "", fmt.Errorf("module path %v must be relative", modPath)
+	}
+	for _, seg := range strings.Split(filepath.ToSlash(modPath), "/") {
+		if seg == "" || seg == "." || seg == ".." || strings.HasPrefix(seg, ".get-") {
+			return "", fmt.Errorf("module path %v is invalid", modPath)
+		}
+	}
+	if modPath == modulesLockFile {
+		return "", fmt.Errorf("module path %v is reserved", modPath)
+	}
+
+	target := filepath.Join(dir, filepath.FromSlash(modPath))
+	rel, err := filepath.Rel(dir, target)
+	if err != nil || rel == "." || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return "", fmt.Errorf("module path %v resolves outside of the modules directory", modPath)
+	}
+	return target, nil
+}
+
+// fetchModule clones a version of a module into the modules directory,
+// replacing any existing copy, and returns the commit that was fetched. When
+// the commit of the module is set then that commit is fetched, and otherwise
+// the latest commit of the version.
+func fetchModule(dir string, m module) (string, error) {
+	target, err := moduleTarget(dir, m.Path)
+	if err != nil {
+		return "", err
+	}
+	if m.Commit != "" && !commitRegexp.MatchString(m.Commit) {
+		return "", fmt.Errorf("commit %v is not a full commit hash", m.Commit)
+	}
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return "", err
+	}
+
+	// Clone within the modules directory so that the result can be moved
+	// into place without crossing file systems.
+	tmpDir, err := ioutil.TempDir(dir, ".get-")
+	if err != nil {
+		return "", err
+	}
+	defer os.RemoveAll(tmpDir)
+
+	args := []string{"clone", "--quiet"}
+	if m.Commit == "" {
+		args = append(args, "--depth", "1")
+		if m.Version != "" {
+			args = append(args, "--branch", m.Version)
+		}
+	} else {
+		// Servers do not generally allow fetching arbitrary commits, and
+		// therefore the history is cloned in order to check out the commit.
+		args = append(args, "--no-checkout")
+	}
+	args = append(args, "--", m.URL, tmpDir)
+	if out, err := exec.Command("git", args...).CombinedOutput(); err != nil {
+		return "", fmt.Errorf("failed to clone %v: %v", m.URL, strings.TrimSpace(string(out)))
+	}
+	if m.Commit != "" {
+		if out, err := exec.Command("git", "-C", tmpDir, "checkout", "--quiet", "--detach", m.Commit).CombinedOutput(); err != nil {
+			return "", fmt.Errorf("failed to check out commit %v of %v: %v", m.Commit, m.URL, strings.TrimSpace(string(out)))
+		}
+	}
+
+	out, err := exec.Command("git", "-C", tmpDir, "rev-parse", "HEAD").Output()
+	if err != nil {
+		return "", fmt.Errorf("failed to resolve commit of %v: %w", m.URL, err)
+	}
+	commit := strings.TrimSpace(string(out))
+	if m.Commit != "" && commit != m.Commit {
+		return "", fmt.Errorf("fetched commit %v of %v does not match %v", commit, m.URL, m.Commit)
+	}
+
+	if err := os.RemoveAll(filepath.Join(tmpDir, ".git")); err != nil {
+		return "", err
+	}
+	if err := os.Chmod(tmpDir, 0755); err != nil {
+		return "", err
+	}
+
+	if err := os.RemoveAll(target); err != nil {
+		return "", err
+	}
+	if err := os.MkdirAll(filepath.Dir(target), 0755); err != nil {
+		return "", err
+	}
+	if err := os.Rename(tmpDir, target); err != nil {
+		return "", err
+	}
+	return commit, nil
+}
+
+func runGet(c *cli.Context) error {
+	dir := c.String("dir")
+
+	locked, err := readModulesLock(dir)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, red("failed to read modules: %v\n"), err)
+		os.Exit(1)
+	}
+
+	mods := locked
+	if c.Args().Len() > 0 {
+		mods = nil
+		for _, ref := range c.Args().Slice() {
+			m, err := parseModule(ref)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "%v: %v\n", ref, red(err))
+				os.Exit(1)
+			}
+			mods = append(mods, m)
+		}
+	}
+	if len(mods) == 0 {
+		fmt.Fprintln(os.Stderr, red("no modules to fetch, specify one or more git URLs"))
+		os.Exit(1)
+	}
+
+	failed := false
+	for _, m := range mods {
+		if m.Commit, err = fetchModule(dir, m); err != nil {
+			fmt.Fprintf(os.Stderr, "%v: %v\n", m, red(err))
+			failed = true
+			continue
+		}
+		fmt.Printf("fetched %v (%v)\n", m, m.Commit)
+
+		replaced := false
+		for i, l := range locked {
+			if l.Path == m.Path {
+				locked[i], replaced = m, true
+			}
+		}
+		if !replaced {
+			locked = append(locked, m)
+		}
+	}
+
+	if err := writeModulesLock(dir, locked); err != nil {
+		fmt.Fprintf(os.Stderr, red("failed to write modules: %v\n"), err)
+		os.Exit(1)
+	}
+	if failed {
+		os.Exit(1)
+	}
+	return nil
+}
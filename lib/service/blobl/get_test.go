@@ -0,0 +1,192 @@
+package blobl
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseModule(t *testing.T) {
+	tests := []struct {
+		ref         string
+		expected    module
+		errContains string
+	}{
+		{
+			ref: "https://github.com/acme/mappings.git@v1.2.0",
+			expected: module{
+				Path:    "github.com/acme/mappings",
+				URL:     "https://github.com/acme/mappings.git",
+				Version: "v1.2.0",
+			},
+		},
+		{
+			ref: "github.com/acme/mappings",
+			expected: module{
+				Path: "github.com/acme/mappings",
+				URL:  "https://github.com/acme/mappings",
+			},
+		},
+		{
+			ref: "git@github.com:acme/mappings.git@main",
+			expected: module{
+				Path:    "github.com/acme/mappings",
+				URL:     "git@github.com:acme/mappings.git",
+				Version: "main",
+			},
+		},
+		{
+			ref:         "https://github.com/../../etc",
+			errContains: "unable to infer a module path",
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.ref, func(t *testing.T) {
+			m, err := parseModule(test.ref)
+			if test.errContains != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), test.errContains)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, test.expected, m)
+		})
+	}
+}
+
+func TestModulesLock(t *testing.T) {
+	dir := t.TempDir()
+
+	mods, err := readModulesLock(dir)
+	require.NoError(t, err)
+	assert.Empty(t, mods)
+
+	commit := strings.Repeat("a", 40)
+	require.NoError(t, writeModulesLock(dir, []module{
+		{Path: "github.com/b/c", URL: "https://github.com/b/c", Commit: commit},
+		{Path: "github.com/a/b", URL: "https://github.com/a/b.git", Version: "v1.0.0", Commit: commit},
+	}))
+
+	mods, err = readModulesLock(dir)
+	require.NoError(t, err)
+	assert.Equal(t, []module{
+		{Path: "github.com/a/b", URL: "https://github.com/a/b.git", Version: "v1.0.0", Commit: commit},
+		{Path: "github.com/b/c", URL: "https://github.com/b/c", Commit: commit},
+	}, mods)
+
+	require.NoError(t, os.WriteFile(filepath.Join(dir, modulesLockFile), []byte("github.com/a/b nope\n"), 0644))
+	_, err = readModulesLock(dir)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "malformed line")
+}
+
+func TestModuleTarget(t *testing.T) {
+	dir := t.TempDir()
+
+	target, err := moduleTarget(dir, "github.com/acme/mappings")
+	require.NoError(t, err)
+	assert.Equal(t, filepath.Join(dir, "github.com", "acme", "mappings"), target)
+
+	for _, p := range []string{
+		"",
+		".",
+		"..",
+		"../foo",
+		"foo/../../bar",
+		"foo/./bar",
+		"foo//bar",
+		"/etc/passwd",
+		".get-123",
+		modulesLockFile,
+	} {
+		_, err := moduleTarget(dir, p)
+		assert.Error(t, err, p)
+	}
+}
+
+func gitCmd(t *testing.T, dir string, args ...string) string {
+	t.Helper()
+	cmd := exec.Command("git", append([]string{"-C", dir, "-c", "user.name=test", "-c", "user.email=test@example.com"}, args...)...)
+	out, err := cmd.CombinedOutput()
+	require.NoError(t, err, string(out))
+	return strings.TrimSpace(string(out))
+}
+
+func TestFetchModule(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+
+	repoDir := t.TempDir()
+	gitCmd(t, repoDir, "init", "--quiet")
+
+	require.NoError(t, os.WriteFile(filepath.Join(repoDir, "foo.blobl"), []byte("root = \"first\"\n"), 0644))
+	gitCmd(t, repoDir, "add", "foo.blobl")
+	gitCmd(t, repoDir, "commit", "--quiet", "-m", "first")
+	gitCmd(t, repoDir, "tag", "v1.0.0")
+	firstCommit := gitCmd(t, repoDir, "rev-parse", "HEAD")
+
+	require.NoError(t, os.WriteFile(filepath.Join(repoDir, "foo.blobl"), []byte("root = \"second\"\n"), 0644))
+	gitCmd(t, repoDir, "commit", "--quiet", "-am", "second")
+	secondCommit := gitCmd(t, repoDir, "rev-parse", "HEAD")
+
+	// Moving the tag must not change what is fetched for a recorded commit.
+	gitCmd(t, repoDir, "tag", "-f", "v1.0.0")
+
+	modsDir := t.TempDir()
+	readFoo := func() string {
+		t.Helper()
+		b, err := os.ReadFile(filepath.Join(modsDir, "acme", "mappings", "foo.blobl"))
+		require.NoError(t, err)
+		return string(b)
+	}
+
+	m := module{Path: "acme/mappings", URL: repoDir, Version: "v1.0.0"}
+
+	commit, err := fetchModule(modsDir, m)
+	require.NoError(t, err)
+	assert.Equal(t, secondCommit, commit)
+	assert.Equal(t, "root = \"second\"\n", readFoo())
+
+	_, err = os.Stat(filepath.Join(modsDir, "acme", "mappings", ".git"))
+	assert.True(t, os.IsNotExist(err))
+
+	m.Commit = firstCommit
+	commit, err = fetchModule(modsDir, m)
+	require.NoError(t, err)
+	assert.Equal(t, firstCommit, commit)
+	assert.Equal(t, "root = \"first\"\n", readFoo())
+
+	// Unknown commits fail without removing the existing copy.
+	m.Commit = strings.Repeat("b", 40)
+	_, err = fetchModule(modsDir, m)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "failed to check out commit")
+	assert.Equal(t, "root = \"first\"\n", readFoo())
+
+	m.Commit = "--help"
+	_, err = fetchModule(modsDir, m)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "not a full commit hash")
+
+	// Paths read from the lock file must not escape the modules directory.
+	outside := filepath.Join(filepath.Dir(modsDir), "outside")
+	require.NoError(t, os.MkdirAll(outside, 0755))
+	_, err = fetchModule(modsDir, module{Path: "../outside", URL: repoDir})
+	require.Error(t, err)
+	_, err = os.Stat(outside)
+	assert.NoError(t, err)
+
+	entries, err := os.ReadDir(modsDir)
+	require.NoError(t, err)
+	for _, e := range entries {
+		assert.False(t, strings.HasPrefix(e.Name(), ".get-"), "temporary clone was not removed")
+	}
+}
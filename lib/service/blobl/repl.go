@@ -0,0 +1,309 @@
+package blobl
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/Jeffail/benthos/v3/internal/bloblang"
+	"github.com/Jeffail/benthos/v3/internal/bloblang/mapping"
+	"github.com/Jeffail/benthos/v3/internal/bloblang/parser"
+	"github.com/Jeffail/benthos/v3/internal/bloblang/query"
+	"github.com/Jeffail/benthos/v3/lib/message"
+	"github.com/Jeffail/benthos/v3/lib/types"
+	"github.com/Jeffail/gabs/v2"
+	"github.com/urfave/cli/v2"
+	"golang.org/x/term"
+)
+
+const replHelp = `Enter a Bloblang query or mapping in order to execute it against the loaded
+input document, e.g. 'this.foo.uppercase()' or 'root.bar = this.foo'.
+Variables declared with 'let' and metadata assigned with 'meta' persist
+between executions. A mapping that ends unfinished, such as an open bracket,
+continues on the next line.
+
+Commands:
+  :load <path>   Load an input document from a file.
+  :input <doc>   Set the input document inline.
+  :show          Print the current input document.
+  :vars          Print the current variables.
+  :reset         Clear all variables and metadata.
+  :help          Print this message.
+  :quit          Exit the REPL.`
+
+type repl struct {
+	raw  bool
+	out  io.Writer
+	msg  types.Message
+	vars map[string]interface{}
+
+	pending string
+}
+
+func newRepl(raw bool, out io.Writer) *repl {
+	return &repl{
+		raw:  raw,
+		out:  out,
+		msg:  message.New([][]byte{[]byte(nil)}),
+		vars: map[string]interface{}{},
+	}
+}
+
+func (r *repl) printValue(v interface{}) {
+	switch t := v.(type) {
+	case []byte:
+		fmt.Fprintln(r.out, string(t))
+	case query.Delete:
+		fmt.Fprintln(r.out, "deleted()")
+	default:
+		fmt.Fprintln(r.out, gabs.Wrap(v).StringIndent("", "  "))
+	}
+}
+
+func (r *repl) printErr(err error) {
+	fmt.Fprintln(r.out, red(err.Error()))
+}
+
+// prompt returns the prompt that should be shown for the next line of input,
+// which differs when a mapping is incomplete.
+func (r *repl) prompt() string {
+	if r.pending != "" {
+		return "... "
+	}
+	return "> "
+}
+
+// handleLine processes a single line of input and returns false when the REPL
+// should exit.
+func (r *repl) handleLine(line string) bool {
+	trimmed := strings.TrimSpace(line)
+	if strings.HasPrefix(trimmed, ":") {
+		// Commands abandon any unfinished mapping.
+		r.pending = ""
+		return r.handleCommand(trimmed)
+	}
+	if r.pending != "" {
+		line = r.pending + "\n" + line
+		r.pending = ""
+	} else if trimmed == "" {
+		return true
+	}
+
+	exec, err := bloblang.NewMapping("", line)
+	if err != nil {
+		var perr *parser.Error
+		if errors.As(err, &perr) {
+			if len(perr.Input) == 0 {
+				// The mapping ended prematurely, so we wait for more lines.
+				r.pending = line
+				return true
+			}
+			err = errors.New(perr.ErrorAtPosition([]rune(line)))
+		}
+		r.printErr(fmt.Errorf("failed to parse mapping: %w", err))
+		return true
+	}
+
+	res, err := r.execute(exec)
+	if err != nil {
+		r.printErr(fmt.Errorf("failed to execute mapping: %w", err))
+		return true
+	}
+	if _, isNothing := res.(query.Nothing); !isNothing {
+		r.printValue(res)
+	}
+	return true
+}
+
+func (r *repl) handleCommand(cmd string) bool {
+	arg := ""
+	if i := strings.IndexAny(cmd, " \t"); i > 0 {
+		cmd, arg = cmd[:i], strings.TrimSpace(cmd[i:])
+	}
+
+	switch cmd {
+	case ":quit", ":exit", ":q":
+		return false
+	case ":help", ":h":
+		fmt.Fprintln(r.out, replHelp)
+	case ":load":
+		if arg == "" {
+			r.printErr(errors.New("a path to an input document is required"))
+			break
+		}
+		inputBytes, err := ioutil.ReadFile(arg)
+		if err != nil {
+			r.printErr(fmt.Errorf("failed to read input file: %w", err))
+			break
+		}
+		r.msg.Get(0).Set(inputBytes)
+	case ":input":
+		r.msg.Get(0).Set([]byte(arg))
+	case ":show":
+		fmt.Fprintln(r.out, string(r.msg.Get(0).Get()))
+	case ":vars":
+		names := make([]string, 0, len(r.vars))
+		for k := range r.vars {
+			names = append(names, k)
+		}
+		sort.Strings(names)
+		for _, k := range names {
+			fmt.Fprintf(r.out, "$%v = %v\n", k, gabs.Wrap(r.vars[k]).String())
+		}
+	case ":reset":
+		r.vars = map[string]interface{}{}
+		r.msg.Get(0).SetMetadata(nil)
+	default:
+		r.printErr(fmt.Errorf("unrecognised command %v, use :help to list commands", cmd))
+	}
+	return true
+}
+
+func (r *repl) execute(exec *mapping.Executor) (interface{}, error) {
+	part := r.msg.Get(0)
+
+	var valuePtr *interface{}
+	var parseErr error
+
+	lazyValue := func() *interface{} {
+		if valuePtr == nil && parseErr == nil {
+			if r.raw {
+				var value interface{} = part.Get()
+				valuePtr = &value
+			} else if jObj, err := part.JSON(); err == nil {
+				valuePtr = &jObj
+			} else if errors.Is(err, message.ErrMessagePartNotExist) {
+				parseErr = errors.New("no input document has been loaded, use :load or :input")
+			} else {
+				parseErr = fmt.Errorf("parse as json: %w", err)
+			}
+		}
+		return valuePtr
+	}
+
+	var result interface{} = query.Nothing(nil)
+	err := exec.ExecOnto(query.FunctionContext{
+		Maps:     exec.Maps(),
+		Vars:     r.vars,
+		MsgBatch: r.msg,
+	}.WithValueFunc(lazyValue), mapping.AssignmentContext{
+		Vars:  r.vars,
+		Meta:  part.Metadata(),
+		Value: &result,
+	})
+	if err != nil && parseErr != nil && errors.Is(err, query.ErrNoContext) {
+		err = fmt.Errorf("unable to reference input document as structured (with 'this'): %w", parseErr)
+	}
+	return result, err
+}
+
+// complete provides tab completion of function and method names.
+func (r *repl) complete(line string, pos int, key rune) (string, int, bool) {
+	if key != '\t' {
+		return "", 0, false
+	}
+
+	start := pos
+	for start > 0 && strings.ContainsRune("abcdefghijklmnopqrstuvwxyz0123456789_", rune(line[start-1])) {
+		start--
+	}
+	word := line[start:pos]
+	if word == "" {
+		return "", 0, false
+	}
+
+	candidates := query.ListFunctions()
+	if start > 0 && line[start-1] == '.' {
+		candidates = query.ListMethods()
+	}
+
+	var match string
+	for _, c := range candidates {
+		if !strings.HasPrefix(c, word) {
+			continue
+		}
+		if match != "" {
+			// Ambiguous, so we only complete the common prefix.
+			for !strings.HasPrefix(c, match) {
+				match = match[:len(match)-1]
+			}
+			continue
+		}
+		match = c
+	}
+	if len(match) <= len(word) {
+		return "", 0, false
+	}
+	return line[:start] + match + line[pos:], start + len(match), true
+}
+
+func runRepl(c *cli.Context) error {
+	fd := int(os.Stdin.Fd())
+	if !term.IsTerminal(fd) {
+		// Without a terminal we simply read lines from stdin, which allows
+		// piping commands into the REPL.
+		r := newRepl(c.Bool("raw"), os.Stdout)
+		return replLoop(r, c.String("input-file"), scanLines(os.Stdin, c.Int("max-token-length")))
+	}
+
+	oldState, err := term.MakeRaw(fd)
+	if err != nil {
+		return fmt.Errorf("failed to initialise terminal: %w", err)
+	}
+	defer func() {
+		_ = term.Restore(fd, oldState)
+	}()
+
+	t := term.NewTerminal(struct {
+		io.Reader
+		io.Writer
+	}{os.Stdin, os.Stdout}, "")
+
+	r := newRepl(c.Bool("raw"), t)
+	t.AutoCompleteCallback = r.complete
+	return replLoop(r, c.String("input-file"), func(prompt string) (string, error) {
+		t.SetPrompt(prompt)
+		return t.ReadLine()
+	})
+}
+
+// scanLines returns a line reader for the REPL that ignores prompts, which is
+// used when input isn't a terminal.
+func scanLines(in io.Reader, maxTokenLen int) func(prompt string) (string, error) {
+	scanner := bufio.NewScanner(in)
+	scanner.Buffer(nil, maxTokenLen)
+	return func(string) (string, error) {
+		if !scanner.Scan() {
+			if err := scanner.Err(); err != nil {
+				return "", err
+			}
+			return "", io.EOF
+		}
+		return scanner.Text(), nil
+	}
+}
+
+func replLoop(r *repl, inputFile string, readLine func(prompt string) (string, error)) error {
+	if inputFile != "" {
+		r.handleCommand(":load " + inputFile)
+	}
+
+	fmt.Fprintln(r.out, "Bloblang REPL, use :help to list commands and :quit to exit.")
+	for {
+		line, err := readLine(r.prompt())
+		if err != nil {
+			if errors.Is(err, io.EOF) {
+				return nil
+			}
+			return err
+		}
+		if !r.handleLine(line) {
+			return nil
+		}
+	}
+}
@@ -0,0 +1,148 @@
+package blobl
+
+import (
+	"bufio"
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const replBanner = "Bloblang REPL, use :help to list commands and :quit to exit.\n"
+
+func TestReplLoop(t *testing.T) {
+	inputPath := filepath.Join(t.TempDir(), "input.json")
+	require.NoError(t, os.WriteFile(inputPath, []byte(`{"foo":"from file"}`), 0644))
+
+	tests := []struct {
+		name      string
+		raw       bool
+		inputFile string
+		input     string
+		expected  string
+	}{
+		{
+			name:     "query",
+			input:    ":input {\"foo\":\"bar\"}\nthis.foo.uppercase()\n",
+			expected: "\"BAR\"\n",
+		},
+		{
+			name:     "mapping",
+			input:    ":input {\"foo\":\"bar\"}\nroot.a = this.foo\nroot = this.foo + \"baz\"\n",
+			expected: "{\n  \"a\": \"bar\"\n}\n\"barbaz\"\n",
+		},
+		{
+			name:     "multi-line mapping",
+			input:    ":input {\"foo\":\"bar\"}\nroot = {\n  \"a\": this.foo,\n  \"b\": [\n    1\n  ]\n}\n",
+			expected: "{\n  \"a\": \"bar\",\n  \"b\": [\n    1\n  ]\n}\n",
+		},
+		{
+			name:     "command abandons unfinished mapping",
+			input:    ":input {\"foo\":\"bar\"}\nroot = [\n:show\nthis.foo\n",
+			expected: "{\"foo\":\"bar\"}\n\"bar\"\n",
+		},
+		{
+			name:     "parse error recovery",
+			input:    ":input {\"foo\":\"bar\"}\nthis.foo &\nthis.foo\n",
+			expected: "failed to parse mapping: line 1 char 10: expected =\n\"bar\"\n",
+		},
+		{
+			name:     "parse error after continuation",
+			input:    ":input {\"foo\":\"bar\"}\nthis.foo.\n&\nthis.foo\n",
+			expected: "failed to parse mapping: line 1 char 10: required: expected method or field path\n\"bar\"\n",
+		},
+		{
+			name:     "execution error recovery",
+			input:    ":input {\"foo\":\"bar\"}\nthis.foo.number()\nthis.foo\n",
+			expected: "failed to execute mapping: failed assignment (line 1): field `this.foo`: strconv.ParseFloat: parsing \"bar\": invalid syntax\n\"bar\"\n",
+		},
+		{
+			name:     "no input document",
+			input:    "this.foo\n\"foo\"\n",
+			expected: "failed to execute mapping: unable to reference input document as structured (with 'this'): no input document has been loaded, use :load or :input\n\"foo\"\n",
+		},
+		{
+			name:     "switching input documents",
+			input:    ":input {\"foo\":\"first\"}\nthis.foo\n:input {\"foo\":\"second\"}\nthis.foo\n:input not json\nthis.foo\ncontent()\n",
+			expected: "\"first\"\n\"second\"\nfailed to execute mapping: unable to reference input document as structured (with 'this'): parse as json: invalid character 'o' in literal null (expecting 'u')\nnot json\n",
+		},
+		{
+			name:      "load input file",
+			inputFile: inputPath,
+			input:     "this.foo\n:input {}\n:load " + inputPath + "\n:show\n:load\n:load " + inputPath + ".nope\n",
+			expected:  "\"from file\"\n{\"foo\":\"from file\"}\na path to an input document is required\nfailed to read input file: open " + inputPath + ".nope: no such file or directory\n",
+		},
+		{
+			name:     "raw input",
+			raw:      true,
+			input:    ":input foo bar\nthis.uppercase()\n",
+			expected: "FOO BAR\n",
+		},
+		{
+			name:     "variables and metadata persist",
+			input:    "let foo = \"bar\"\nmeta baz = \"buz\"\n$foo + meta(\"baz\")\n:vars\n:reset\n:vars\nmeta(\"baz\")\n",
+			expected: "\"barbuz\"\n$foo = \"bar\"\nfailed to execute mapping: failed assignment (line 1): metadata value 'baz' not found\n",
+		},
+		{
+			name:     "unknown command",
+			input:    ":nope\n",
+			expected: "unrecognised command :nope, use :help to list commands\n",
+		},
+		{
+			name:     "quit",
+			input:    "\"foo\"\n:quit\n\"bar\"\n",
+			expected: "\"foo\"\n",
+		},
+		{
+			name:     "help",
+			input:    ":help\n",
+			expected: replHelp + "\n",
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			var out bytes.Buffer
+			r := newRepl(test.raw, &out)
+			readLine := scanLines(strings.NewReader(test.input), bufio.MaxScanTokenSize)
+			require.NoError(t, replLoop(r, test.inputFile, readLine))
+			assert.Equal(t, replBanner+test.expected, out.String())
+		})
+	}
+}
+
+func TestReplPrompt(t *testing.T) {
+	r := newRepl(false, &bytes.Buffer{})
+	assert.Equal(t, "> ", r.prompt())
+
+	r.handleLine("root = [")
+	assert.Equal(t, "... ", r.prompt())
+
+	r.handleLine("]")
+	assert.Equal(t, "> ", r.prompt())
+}
+
+func TestReplComplete(t *testing.T) {
+	r := newRepl(false, &bytes.Buffer{})
+
+	line, pos, ok := r.complete("this.foo.uppe", 13, '\t')
+	require.True(t, ok)
+	assert.Equal(t, "this.foo.uppercase", line)
+	assert.Equal(t, 18, pos)
+
+	line, pos, ok = r.complete("root = hostn + 1", 12, '\t')
+	require.True(t, ok)
+	assert.Equal(t, "root = hostname + 1", line)
+	assert.Equal(t, 15, pos)
+
+	_, _, ok = r.complete("this.foo.uppe", 13, 'a')
+	assert.False(t, ok)
+
+	_, _, ok = r.complete("this.foo.nope", 13, '\t')
+	assert.False(t, ok)
+}
@@ -20,6 +20,7 @@ import (
 
 	"github.com/Jeffail/benthos/v3/internal/bloblang"
 	"github.com/Jeffail/benthos/v3/internal/bloblang/parser"
+	"github.com/Jeffail/benthos/v3/lib/message/metadata"
 	"github.com/urfave/cli/v2"
 )
 
@@ -327,17 +328,37 @@ func (f *fileSync) mapping() string {
 	return f.mappingString
 }
 
+// executeError is a structured description of an error returned by the execute
+// endpoint, where the line and column are only set for parse errors.
+type executeError struct {
+	Type    string `json:"type"`
+	Message string `json:"message"`
+	Line    int    `json:"line,omitempty"`
+	Column  int    `json:"column,omitempty"`
+}
+
+// executeResponse is the body returned by the execute endpoint. The fields
+// parse_error and mapping_error are human readable and shown by the app,
+// whereas error is intended for tools that need to locate the problem.
+type executeResponse struct {
+	ParseError   string            `json:"parse_error"`
+	MappingError string            `json:"mapping_error"`
+	Result       string            `json:"result"`
+	Metadata     map[string]string `json:"metadata,omitempty"`
+	Error        *executeError     `json:"error,omitempty"`
+}
+
 func runServer(c *cli.Context) error {
 	fSync := newFileSync(c.String("input-file"), c.String("mapping-file"), c.Bool("write"))
 	defer fSync.write()
 
 	mux := http.NewServeMux()
-	execCache := newExecCache()
 
 	mux.HandleFunc("/execute", func(w http.ResponseWriter, r *http.Request) {
 		req := struct {
-			Mapping string `json:"mapping"`
-			Input   string `json:"input"`
+			Mapping  string            `json:"mapping"`
+			Input    string            `json:"input"`
+			Metadata map[string]string `json:"metadata"`
 		}{}
 		dec := json.NewDecoder(r.Body)
 		if err := dec.Decode(&req); err != nil {
@@ -347,36 +368,52 @@ func runServer(c *cli.Context) error {
 
 		fSync.update(req.Input, req.Mapping)
 
-		res := struct {
-			ParseError   string `json:"parse_error"`
-			MappingError string `json:"mapping_error"`
-			Result       string `json:"result"`
-		}{}
+		res := executeResponse{}
 		defer func() {
 			resBytes, err := json.Marshal(res)
 			if err != nil {
 				http.Error(w, err.Error(), http.StatusBadGateway)
 				return
 			}
+			w.Header().Set("Content-Type", "application/json")
 			w.Write(resBytes)
 		}()
 
 		exec, err := bloblang.NewMapping("", req.Mapping)
 		if err != nil {
+			res.Error = &executeError{
+				Type:    "parse",
+				Message: err.Error(),
+			}
 			if perr, ok := err.(*parser.Error); ok {
 				res.ParseError = fmt.Sprintf("failed to parse mapping: %v\n", perr.ErrorAtPositionStructured("", []rune(req.Mapping)))
+				res.Error.Message = perr.ErrorAtPosition([]rune(req.Mapping))
+				res.Error.Line, res.Error.Column = parser.LineAndColOf([]rune(req.Mapping), perr.Input)
 			} else {
 				res.ParseError = err.Error()
 			}
 			return
 		}
 
+		execCache := newExecCache()
+		execCache.msg.Get(0).SetMetadata(metadata.New(req.Metadata))
+
 		output, err := execCache.executeMapping(exec, false, true, []byte(req.Input))
 		if err != nil {
 			res.MappingError = err.Error()
-		} else {
-			res.Result = output
+			res.Error = &executeError{
+				Type:    "mapping",
+				Message: err.Error(),
+			}
+			return
 		}
+
+		res.Result = output
+		res.Metadata = map[string]string{}
+		_ = execCache.msg.Get(0).Metadata().Iter(func(k, v string) error {
+			res.Metadata[k] = v
+			return nil
+		})
 	})
 
 	indexTemplate := template.Must(template.New("index").Parse(bloblangEditorPage))
@@ -0,0 +1,45 @@
+package blobl
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/Jeffail/benthos/v3/internal/bloblang/parser"
+	"github.com/Jeffail/benthos/v3/internal/bloblang/query"
+	"github.com/Jeffail/gabs/v2"
+)
+
+func traceValueStr(v interface{}) string {
+	switch t := v.(type) {
+	case []byte:
+		return strconv.Quote(string(t))
+	case query.Delete:
+		return "deleted()"
+	case query.Nothing:
+		return "nothing"
+	}
+	return gabs.Wrap(v).String()
+}
+
+// printTrace writes the steps of a trace in the order that they were executed,
+// where each step shows the position and expression of an assignment or method
+// along with the value it produced.
+func printTrace(w io.Writer, mapping string, trace *parser.Trace) {
+	fmt.Fprintln(w, "trace:")
+	for _, step := range trace.Steps() {
+		line, col := step.LineAndCol([]rune(mapping))
+
+		expr := strings.TrimSpace(step.Expression)
+		if i := strings.Index(expr, "\n"); i >= 0 {
+			expr = expr[:i] + " ..."
+		}
+
+		result := "-> " + traceValueStr(step.Value)
+		if step.Err != nil {
+			result = red("-> error: " + step.Err.Error())
+		}
+		fmt.Fprintf(w, "  line %v char %v: %v %v %v\n", line, col, step.Kind, expr, result)
+	}
+}
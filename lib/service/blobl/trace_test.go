@@ -0,0 +1,81 @@
+package blobl
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/Jeffail/benthos/v3/internal/bloblang/parser"
+	"github.com/Jeffail/benthos/v3/internal/bloblang/query"
+	"github.com/Jeffail/benthos/v3/lib/message"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTraceValueStr(t *testing.T) {
+	assert.Equal(t, `"foo\nbar"`, traceValueStr([]byte("foo\nbar")))
+	assert.Equal(t, "deleted()", traceValueStr(query.Delete(nil)))
+	assert.Equal(t, "nothing", traceValueStr(query.Nothing(nil)))
+	assert.Equal(t, `"foo"`, traceValueStr("foo"))
+	assert.Equal(t, `{"a":[1,2]}`, traceValueStr(map[string]interface{}{"a": []interface{}{1, 2}}))
+	assert.Equal(t, "null", traceValueStr(nil))
+}
+
+func TestPrintTrace(t *testing.T) {
+	tests := []struct {
+		name     string
+		mapping  string
+		input    string
+		expected string
+	}{
+		{
+			name:    "methods and assignments",
+			mapping: "root.a = this.foo.uppercase()\nroot.b = deleted()",
+			input:   `{"foo":"hello"}`,
+			expected: `trace:
+  line 1 char 19: method uppercase() -> "HELLO"
+  line 1 char 1: assignment root.a = this.foo.uppercase() -> "HELLO"
+  line 2 char 1: assignment root.b = deleted() -> deleted()
+`,
+		},
+		{
+			name:    "errors",
+			mapping: "root.a = this.foo.number()",
+			input:   `{"foo":"nah"}`,
+			expected: `trace:
+  line 1 char 19: method number() -> error: field ` + "`this.foo`" + `: strconv.ParseFloat: parsing "nah": invalid syntax
+  line 1 char 1: assignment root.a = this.foo.number() -> error: field ` + "`this.foo`" + `: strconv.ParseFloat: parsing "nah": invalid syntax
+`,
+		},
+		{
+			name: "multiple line expressions",
+			mapping: `root.a = if this.foo > 5 {
+  "big"
+} else {
+  "small"
+}`,
+			input: `{"foo":10}`,
+			expected: `trace:
+  line 1 char 1: assignment root.a = if this.foo > 5 { ... -> "big"
+`,
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			exec, trace, perr := parser.ParseMappingWithTrace(parser.GlobalContext(), "", test.mapping)
+			require.Nil(t, perr)
+
+			_, _ = exec.MapPart(0, message.New([][]byte{[]byte(test.input)}))
+
+			var buf bytes.Buffer
+			printTrace(&buf, test.mapping, trace)
+			assert.Equal(t, test.expected, buf.String())
+
+			trace.Reset()
+			buf.Reset()
+			printTrace(&buf, test.mapping, trace)
+			assert.Equal(t, "trace:\n", buf.String())
+		})
+	}
+}
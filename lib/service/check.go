@@ -0,0 +1,381 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Jeffail/benthos/v3/internal/interop"
+	"github.com/Jeffail/benthos/v3/lib/input"
+	"github.com/Jeffail/benthos/v3/lib/log"
+	"github.com/Jeffail/benthos/v3/lib/manager"
+	"github.com/Jeffail/benthos/v3/lib/metrics"
+	"github.com/Jeffail/benthos/v3/lib/output"
+	"github.com/Jeffail/benthos/v3/lib/processor"
+	"github.com/Jeffail/benthos/v3/lib/types"
+	"github.com/urfave/cli/v2"
+)
+
+//------------------------------------------------------------------------------
+
+// checkErrors records the errors logged by components during a check, keyed
+// by the logger path of the component.
+type checkErrors struct {
+	mut     sync.Mutex
+	entries []checkError
+}
+
+type checkError struct {
+	path    string
+	message string
+}
+
+func (e *checkErrors) add(path, message string) {
+	e.mut.Lock()
+	e.entries = append(e.entries, checkError{path: path, message: strings.TrimSpace(message)})
+	e.mut.Unlock()
+}
+
+// last returns the most recent error logged by a component or any of its
+// children.
+func (e *checkErrors) last(path string) string {
+	e.mut.Lock()
+	defer e.mut.Unlock()
+	for i := len(e.entries) - 1; i >= 0; i-- {
+		if p := e.entries[i].path; p == path || strings.HasPrefix(p, path+".") {
+			return e.entries[i].message
+		}
+	}
+	return ""
+}
+
+// checkLogger is a logger that records the errors of components by their
+// path, which is derived from child modules and component fields.
+type checkLogger struct {
+	log.Modular
+	path string
+	errs *checkErrors
+}
+
+func (l *checkLogger) NewModule(prefix string) log.Modular {
+	return &checkLogger{Modular: l.Modular.NewModule(prefix), path: l.path + prefix, errs: l.errs}
+}
+
+func (l *checkLogger) WithFields(fields map[string]string) log.Modular {
+	path := l.path
+	if c, exists := fields["component"]; exists {
+		path = c
+	}
+	return &checkLogger{Modular: l.Modular.WithFields(fields), path: path, errs: l.errs}
+}
+
+func (l *checkLogger) Fatalf(format string, v ...interface{}) {
+	l.errs.add(l.path, fmt.Sprintf(format, v...))
+}
+
+func (l *checkLogger) Errorf(format string, v ...interface{}) {
+	l.errs.add(l.path, fmt.Sprintf(format, v...))
+}
+
+func (l *checkLogger) Fatalln(message string) {
+	l.errs.add(l.path, message)
+}
+
+func (l *checkLogger) Errorln(message string) {
+	l.errs.add(l.path, message)
+}
+
+//------------------------------------------------------------------------------
+
+// checkTarget is a component of a config that has been constructed, and can
+// optionally report whether it has connected.
+type checkTarget struct {
+	name      string
+	logPath   string
+	connected func() bool
+}
+
+// checkInputs is the set of inputs to construct during a check, where the main
+// input has the name "input" and input resources are named by their label.
+type checkInputs map[string]struct{}
+
+func (c checkInputs) has(name string) bool {
+	_, exists := c[name]
+	return exists
+}
+
+type checkResult struct {
+	name   string
+	status string
+	err    error
+}
+
+func cmdCheck(connect bool, inputs checkInputs, timeout time.Duration) int {
+	errs := &checkErrors{}
+	logger := &checkLogger{Modular: log.Noop(), errs: errs}
+	stats := metrics.Noop()
+
+	var results []checkResult
+	failed := false
+	fail := func(name string, err error) {
+		results = append(results, checkResult{name: name, err: err})
+		failed = true
+	}
+
+	// Inputs begin reading messages as soon as they are constructed, and those
+	// without acknowledgements lose the messages read during a check, and so
+	// only inputs that have been explicitly listed are constructed.
+	var skipped []string
+	resConf := conf.ResourceConfig
+	resConf.ResourceInputs = nil
+	for _, c := range conf.ResourceInputs {
+		if inputs.has(c.Label) {
+			resConf.ResourceInputs = append(resConf.ResourceInputs, c)
+		} else {
+			skipped = append(skipped, fmt.Sprintf("input_resources.%v (%v)", c.Label, c.Type))
+		}
+	}
+
+	for name := range inputs {
+		if name == "input" {
+			continue
+		}
+		found := false
+		for _, c := range resConf.ResourceInputs {
+			if c.Label == name {
+				found = true
+				break
+			}
+		}
+		if !found {
+			fmt.Fprintf(os.Stderr, "input %v: failed: not found within the config\n", name)
+			return 1
+		}
+	}
+
+	mgr, err := manager.NewV2(resConf, types.DudMgr{}, logger, stats)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "resources: failed: %v\n", err)
+		return 1
+	}
+	defer func() {
+		mgr.CloseAsync()
+		_ = mgr.WaitForClose(timeout)
+	}()
+
+	var targets []checkTarget
+	for _, c := range resConf.ResourceInputs {
+		label := c.Label
+		targets = append(targets, checkTarget{
+			name:    fmt.Sprintf("input_resources.%v (%v)", label, c.Type),
+			logPath: label,
+			connected: func() (connected bool) {
+				_ = mgr.AccessInput(context.Background(), label, func(i types.Input) {
+					connected = i.Connected()
+				})
+				return
+			},
+		})
+	}
+	for _, c := range conf.ResourceOutputs {
+		label := c.Label
+		targets = append(targets, checkTarget{
+			name:    fmt.Sprintf("output_resources.%v (%v)", label, c.Type),
+			logPath: label,
+			connected: func() (connected bool) {
+				_ = mgr.AccessOutput(context.Background(), label, func(o types.OutputWriter) {
+					connected = o.Connected()
+				})
+				return
+			},
+		})
+	}
+	for _, c := range conf.ResourceCaches {
+		targets = append(targets, checkTarget{name: fmt.Sprintf("cache_resources.%v (%v)", c.Label, c.Type)})
+	}
+	for _, c := range conf.ResourceRateLimits {
+		targets = append(targets, checkTarget{name: fmt.Sprintf("rate_limit_resources.%v (%v)", c.Label, c.Type)})
+	}
+	for _, c := range conf.ResourceProcessors {
+		targets = append(targets, checkTarget{name: fmt.Sprintf("processor_resources.%v (%v)", c.Label, c.Type)})
+	}
+
+	// Messages read by the input are never acknowledged and the output is
+	// never sent any messages, and therefore no data is written.
+	iMgr, iLog, iStats := interop.LabelChild("input", mgr, logger, stats)
+	if !inputs.has("input") {
+		skipped = append(skipped, fmt.Sprintf("input (%v)", conf.Input.Type))
+	} else if in, err := input.New(conf.Input, iMgr, iLog, iStats); err != nil {
+		fail(fmt.Sprintf("input (%v)", conf.Input.Type), err)
+	} else {
+		defer func() {
+			in.CloseAsync()
+			_ = in.WaitForClose(timeout)
+		}()
+		targets = append(targets, checkTarget{
+			name:      fmt.Sprintf("input (%v)", conf.Input.Type),
+			logPath:   ".input",
+			connected: in.Connected,
+		})
+	}
+
+	pMgr, pLog, pStats := interop.LabelChild("pipeline", mgr, logger, stats)
+	for i, pConf := range conf.Pipeline.Processors {
+		name := fmt.Sprintf("pipeline.processors.%v (%v)", i, pConf.Type)
+		procMgr, procLog, procStats := interop.LabelChild(fmt.Sprintf("processor.%v", i), pMgr, pLog, pStats)
+		proc, err := processor.New(pConf, procMgr, procLog, procStats)
+		if err != nil {
+			fail(name, err)
+			continue
+		}
+		defer func() {
+			proc.CloseAsync()
+			_ = proc.WaitForClose(timeout)
+		}()
+		targets = append(targets, checkTarget{name: name})
+	}
+
+	oMgr, oLog, oStats := interop.LabelChild("output", mgr, logger, stats)
+	if out, err := output.New(conf.Output, oMgr, oLog, oStats); err != nil {
+		fail(fmt.Sprintf("output (%v)", conf.Output.Type), err)
+	} else {
+		tChan := make(chan types.Transaction)
+		if err := out.Consume(tChan); err != nil {
+			fail(fmt.Sprintf("output (%v)", conf.Output.Type), err)
+		} else {
+			defer func() {
+				close(tChan)
+				out.CloseAsync()
+				_ = out.WaitForClose(timeout)
+			}()
+			targets = append(targets, checkTarget{
+				name:      fmt.Sprintf("output (%v)", conf.Output.Type),
+				logPath:   ".output",
+				connected: out.Connected,
+			})
+		}
+	}
+
+	if connect {
+		deadline := time.Now().Add(timeout)
+		for {
+			pending := false
+			for _, t := range targets {
+				if t.connected != nil && !t.connected() {
+					pending = true
+					break
+				}
+			}
+			if !pending || time.Now().After(deadline) {
+				break
+			}
+			<-time.After(time.Millisecond * 50)
+		}
+	}
+
+	for _, t := range targets {
+		if !connect || t.connected == nil {
+			results = append(results, checkResult{name: t.name, status: "OK"})
+			continue
+		}
+		if t.connected() {
+			results = append(results, checkResult{name: t.name, status: "CONNECTED"})
+			continue
+		}
+		err := fmt.Errorf("not connected after %v", timeout)
+		if msg := errs.last(t.logPath); msg != "" {
+			err = fmt.Errorf("%v: %v", err, msg)
+		}
+		fail(t.name, err)
+	}
+
+	for _, name := range skipped {
+		results = append(results, checkResult{name: name, status: "SKIPPED"})
+	}
+
+	for _, r := range results {
+		if r.err != nil {
+			fmt.Printf("%v: %v\n", r.name, red("FAILED: "+r.err.Error()))
+		} else if r.status == "SKIPPED" {
+			fmt.Printf("%v: %v\n", r.name, yellow(r.status))
+		} else {
+			fmt.Printf("%v: %v\n", r.name, green(r.status))
+		}
+	}
+	if failed {
+		return 1
+	}
+	return 0
+}
+
+func checkCliCommand() *cli.Command {
+	return &cli.Command{
+		Name:  "check",
+		Usage: "Construct the components of a config in order to check that they are valid",
+		Description: `
+   Parses a config, constructs each of its components and resources, and then
+   shuts them down without producing any data. Each component is reported along
+   with whether it succeeded, and Benthos exits with a status code 1 if any of
+   them failed:
+
+   benthos -c ./config.yaml check
+
+   Inputs begin reading messages as soon as they are constructed, and inputs
+   that do not support acknowledgements lose any messages read during a check.
+   Therefore inputs are skipped unless they are listed with the --input flag,
+   where the main input is named "input" and input resources by their label:
+
+   benthos -c ./config.yaml check --input input --input foo
+
+   With the --connect flag Benthos also waits for the listed inputs and the
+   outputs of the config to establish their connections, which is useful as a
+   deployment gate for checking that credentials and network access are valid:
+
+   benthos -c ./config.yaml check --connect --input input --timeout 30s`[4:],
+		Flags: []cli.Flag{
+			&cli.BoolFlag{
+				Name:  "connect",
+				Value: false,
+				Usage: "Wait for inputs and outputs to connect to their targets and report any that fail to do so.",
+			},
+			&cli.StringSliceFlag{
+				Name:  "input",
+				Usage: "Construct an input, either the main input with the name \"input\" or an input resource by its label. Inputs read messages once constructed, which may be lost for inputs without acknowledgements, and are otherwise skipped.",
+			},
+			&cli.StringFlag{
+				Name:  "timeout",
+				Value: "10s",
+				Usage: "The maximum period to wait for components to connect.",
+			},
+		},
+		Action: func(c *cli.Context) error {
+			timeout, err := time.ParseDuration(c.String("timeout"))
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Failed to parse timeout: %v\n", err)
+				os.Exit(1)
+			}
+
+			lints := readConfig(c.StringSlice("config"), c.StringSlice("resources"), c.StringSlice("set"))
+			if len(lints) > 0 {
+				for _, lint := range lints {
+					fmt.Fprintln(os.Stderr, lint)
+				}
+				if !c.Bool("chilled") {
+					fmt.Println("Shutting down due to linter errors, to prevent shutdown run Benthos with --chilled")
+					os.Exit(1)
+				}
+			}
+
+			inputs := checkInputs{}
+			for _, name := range c.StringSlice("input") {
+				inputs[name] = struct{}{}
+			}
+
+			os.Exit(cmdCheck(c.Bool("connect"), inputs, timeout))
+			return nil
+		},
+	}
+}
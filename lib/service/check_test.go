@@ -0,0 +1,63 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"github.com/Jeffail/benthos/v3/lib/config"
+	"github.com/Jeffail/benthos/v3/lib/input"
+	"github.com/Jeffail/benthos/v3/lib/output"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCheckSkipsInputs(t *testing.T) {
+	ogConf := conf
+	t.Cleanup(func() {
+		conf = ogConf
+	})
+
+	// An invalid mapping fails when the input is constructed, and therefore
+	// the check only fails when the input is listed.
+	badInput := input.NewConfig()
+	badInput.Type = input.TypeGenerate
+	badInput.Generate.Mapping = `root = `
+
+	conf = config.New()
+	conf.Input = badInput
+	conf.Output.Type = output.TypeDrop
+
+	resInput := badInput
+	resInput.Label = "foo"
+	conf.ResourceInputs = append(conf.ResourceInputs, resInput)
+
+	assert.Equal(t, 0, cmdCheck(false, checkInputs{}, time.Second))
+	assert.Equal(t, 0, cmdCheck(true, checkInputs{}, time.Second))
+	assert.Equal(t, 1, cmdCheck(false, checkInputs{"input": {}}, time.Second))
+	assert.Equal(t, 1, cmdCheck(false, checkInputs{"foo": {}}, time.Second))
+}
+
+func TestCheckConnectsInputs(t *testing.T) {
+	ogConf := conf
+	t.Cleanup(func() {
+		conf = ogConf
+	})
+
+	conf = config.New()
+	conf.Input.Type = input.TypeGenerate
+	conf.Input.Generate.Mapping = `root = "foo"`
+	conf.Output.Type = output.TypeDrop
+
+	assert.Equal(t, 0, cmdCheck(true, checkInputs{"input": {}}, time.Second))
+}
+
+func TestCheckUnknownInput(t *testing.T) {
+	ogConf := conf
+	t.Cleanup(func() {
+		conf = ogConf
+	})
+
+	conf = config.New()
+	conf.Output.Type = output.TypeDrop
+
+	assert.Equal(t, 1, cmdCheck(false, checkInputs{"nope": {}}, time.Second))
+}
@@ -0,0 +1,405 @@
+package service
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/Jeffail/benthos/v3/internal/bloblang/query"
+	"github.com/Jeffail/benthos/v3/internal/bundle"
+	"github.com/Jeffail/benthos/v3/internal/docs"
+	"github.com/Jeffail/benthos/v3/lib/config"
+	"github.com/urfave/cli/v2"
+)
+
+//------------------------------------------------------------------------------
+
+// completeCommandName is the name of the hidden command that the completion
+// scripts call in order to obtain candidates for the word being completed.
+const completeCommandName = "__complete"
+
+// completeNoSpace is printed after the candidates of a completion when the
+// shell should not add a space after the completed word, as it is expected to
+// be continued.
+const completeNoSpace = ":nospace"
+
+var completionComponentDocs = map[docs.Type]func() []docs.ComponentSpec{
+	docs.TypeBuffer:    bundle.AllBuffers.Docs,
+	docs.TypeCache:     bundle.AllCaches.Docs,
+	docs.TypeInput:     bundle.AllInputs.Docs,
+	docs.TypeMetrics:   bundle.AllMetrics.Docs,
+	docs.TypeOutput:    bundle.AllOutputs.Docs,
+	docs.TypeProcessor: bundle.AllProcessors.Docs,
+	docs.TypeRateLimit: bundle.AllRateLimits.Docs,
+	docs.TypeTracer:    bundle.AllTracers.Docs,
+}
+
+// componentNames returns the names of all registered components of a type
+// that are not deprecated.
+func componentNames(t docs.Type) []string {
+	docsFn, exists := completionComponentDocs[t]
+	if !exists {
+		return nil
+	}
+	var names []string
+	for _, spec := range docsFn() {
+		if spec.Status != docs.StatusDeprecated {
+			names = append(names, spec.Name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+func filterPrefix(prefix string, candidates []string) []string {
+	var filtered []string
+	for _, c := range candidates {
+		if strings.HasPrefix(c, prefix) {
+			filtered = append(filtered, c)
+		}
+	}
+	return filtered
+}
+
+func findCommand(commands []*cli.Command, name string) *cli.Command {
+	for _, cmd := range commands {
+		if cmd.HasName(name) {
+			return cmd
+		}
+	}
+	return nil
+}
+
+func findFlag(flags []cli.Flag, arg string) cli.Flag {
+	name := strings.TrimLeft(arg, "-")
+	for _, f := range flags {
+		for _, n := range f.Names() {
+			if n == name {
+				return f
+			}
+		}
+	}
+	return nil
+}
+
+func flagTakesValue(flags []cli.Flag, arg string) bool {
+	if strings.Contains(arg, "=") {
+		return false
+	}
+	if f, ok := findFlag(flags, arg).(cli.DocGenerationFlag); ok {
+		return f.TakesValue()
+	}
+	return false
+}
+
+//------------------------------------------------------------------------------
+
+// completeArgs returns completion candidates for the last of a list of
+// command line arguments, which is the word being completed, and whether the
+// shell should refrain from adding a space after a completed candidate. When
+// no candidates are returned the shell falls back to completing file paths.
+func completeArgs(app *cli.App, args []string) (candidates []string, noSpace bool) {
+	if len(args) == 0 {
+		args = []string{""}
+	}
+	current, previous := args[len(args)-1], args[:len(args)-1]
+
+	var cmd *cli.Command
+	flags, commands := app.Flags, app.Commands
+	var positional []string
+	for i := 0; i < len(previous); i++ {
+		arg := previous[i]
+		if strings.HasPrefix(arg, "-") {
+			if flagTakesValue(flags, arg) {
+				if i == len(previous)-1 {
+					// The current word is the value of a flag.
+					if f := findFlag(flags, arg); f != nil && f.Names()[0] == "set" {
+						return completeFieldPath(current), true
+					}
+					return nil, false
+				}
+				i++
+			}
+			continue
+		}
+		if len(positional) == 0 {
+			if sub := findCommand(commands, arg); sub != nil {
+				cmd, flags, commands = sub, sub.Flags, sub.Subcommands
+				continue
+			}
+		}
+		positional = append(positional, arg)
+	}
+
+	if strings.HasPrefix(current, "-") {
+		if strings.Contains(current, "=") {
+			return nil, false
+		}
+		return completeFlags(flags, current), false
+	}
+
+	if cmd != nil && len(commands) == 0 {
+		switch cmd.Name {
+		case "create":
+			if len(positional) == 0 {
+				return completeCreateExpression(current), true
+			}
+		case "list":
+			return filterPrefix(current, listTypes), false
+		case "docs":
+			switch len(positional) {
+			case 0:
+				return filterPrefix(current, docsTypes), false
+			case 1:
+				return filterPrefix(current, docsNames(positional[0])), false
+			}
+		}
+		return nil, false
+	}
+
+	var names []string
+	for _, c := range commands {
+		if !c.Hidden {
+			names = append(names, c.Name)
+		}
+	}
+	return filterPrefix(current, names), false
+}
+
+func completeFlags(flags []cli.Flag, current string) []string {
+	candidates := []string{"--help"}
+	for _, f := range flags {
+		for _, n := range f.Names() {
+			if n == "help" {
+				continue
+			}
+			if len(n) == 1 {
+				candidates = append(candidates, "-"+n)
+			} else {
+				candidates = append(candidates, "--"+n)
+			}
+		}
+	}
+	sort.Strings(candidates)
+	return filterPrefix(current, candidates)
+}
+
+// completeCreateExpression completes the component names of an expression of
+// the create command, which has the form inputs/processors/outputs where each
+// section is a comma separated list.
+func completeCreateExpression(current string) []string {
+	sections := strings.Split(current, "/")
+	var t docs.Type
+	switch len(sections) {
+	case 1:
+		t = docs.TypeInput
+	case 2:
+		t = docs.TypeProcessor
+	case 3:
+		t = docs.TypeOutput
+	default:
+		return nil
+	}
+
+	lastSection := sections[len(sections)-1]
+	partial := lastSection[strings.LastIndex(lastSection, ",")+1:]
+	prefix := current[:len(current)-len(partial)]
+
+	var candidates []string
+	for _, name := range filterPrefix(partial, componentNames(t)) {
+		candidates = append(candidates, prefix+name)
+	}
+	return candidates
+}
+
+// completeFieldPath completes a dot separated path of the config spec for the
+// --set flag. Fields that have children are completed with a trailing dot and
+// all other fields are completed with a trailing equals sign.
+func completeFieldPath(current string) []string {
+	if strings.Contains(current, "=") {
+		return nil
+	}
+	path := strings.Split(current, ".")
+	partial := path[len(path)-1]
+	prefix := current[:len(current)-len(partial)]
+
+	fields, coreType, isCore := config.Spec().FieldsAtPath(nil, path[:len(path)-1]...)
+
+	var candidates []string
+	for _, f := range fields {
+		if f.IsDeprecated || !strings.HasPrefix(f.Name, partial) {
+			continue
+		}
+		_, fieldIsCore := f.Type.IsCoreComponent()
+		if len(f.Children) > 0 || fieldIsCore || f.Kind != docs.KindScalar {
+			candidates = append(candidates, prefix+f.Name+".")
+		} else {
+			candidates = append(candidates, prefix+f.Name+"=")
+		}
+	}
+	if isCore {
+		for _, name := range filterPrefix(partial, componentNames(coreType)) {
+			candidates = append(candidates, prefix+name+".")
+		}
+	}
+	return candidates
+}
+
+// docsNames returns the names of the features of a type that can be printed
+// with the docs command.
+func docsNames(docsType string) []string {
+	if !strings.HasSuffix(docsType, "s") {
+		docsType += "s"
+	}
+
+	var names []string
+	switch docsType {
+	case "bloblang-functions":
+		for _, spec := range query.FunctionDocs() {
+			if spec.Status != query.StatusHidden {
+				names = append(names, spec.Name)
+			}
+		}
+	case "bloblang-methods":
+		for _, spec := range query.MethodDocs() {
+			if spec.Status != query.StatusHidden {
+				names = append(names, spec.Name)
+			}
+		}
+	default:
+		cType, exists := docsComponentTypes[docsType]
+		if !exists {
+			return nil
+		}
+		for _, spec := range cType.docs() {
+			names = append(names, spec.Name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+//------------------------------------------------------------------------------
+
+var bashCompletionScript = `
+# bash completion for %[1]v
+
+_%[1]v_completions() {
+    local line="${COMP_LINE:0:$COMP_POINT}"
+    local -a words
+    read -ra words <<< "$line"
+    [[ "$line" == *" " ]] && words+=("")
+
+    local IFS=$'\n'
+    local -a candidates
+    candidates=($("${words[0]}" %[2]v "${words[@]:1}" 2>/dev/null))
+
+    local n=${#candidates[@]}
+    if (( n > 0 )) && [[ "${candidates[n-1]}" == "%[3]v" ]]; then
+        unset 'candidates[n-1]'
+        compopt -o nospace 2>/dev/null
+    fi
+    COMPREPLY=("${candidates[@]}")
+}
+
+complete -o default -F _%[1]v_completions %[1]v
+`[1:]
+
+var zshCompletionScript = `
+#compdef %[1]v
+
+_%[1]v() {
+    local -a candidates opts
+    candidates=("${(@f)$(${words[1]} %[2]v "${(@)words[2,$CURRENT]}" 2>/dev/null)}")
+    if [[ "${candidates[-1]}" == "%[3]v" ]]; then
+        candidates[-1]=()
+        opts=(-S '')
+    fi
+    candidates=(${candidates:#})
+
+    if (( ${#candidates} == 0 )); then
+        _files
+        return
+    fi
+    compadd "${opts[@]}" -- "${candidates[@]}"
+}
+
+compdef _%[1]v %[1]v
+`[1:]
+
+var fishCompletionScript = `
+# fish completion for %[1]v
+
+function __%[1]v_complete
+    set -l current (commandline -ct)
+    set -l args (commandline -opc) "$current"
+    set -l candidates ($args[1] %[2]v $args[2..-1] 2>/dev/null)
+    if test (count $candidates) -gt 0; and test "$candidates[-1]" = "%[3]v"
+        set -e candidates[-1]
+    end
+
+    if test (count $candidates) -eq 0
+        __fish_complete_path "$current"
+        return
+    end
+    printf '%%s\n' $candidates
+end
+
+complete -c %[1]v -f -a '(__%[1]v_complete)'
+`[1:]
+
+func completionCliCommand() *cli.Command {
+	return &cli.Command{
+		Name:  "completion",
+		Usage: "Print a shell completion script for bash, zsh or fish",
+		Description: `
+   Prints a script that enables the completion of commands, flags, component
+   names and config field paths for a shell. Completions are generated by
+   Benthos itself and therefore include any plugins of this build. Component
+   names are completed for the expressions of the create command, and config
+   field paths are completed for the --set flag.
+
+   source <(benthos completion bash)
+   benthos completion zsh > "${fpath[1]}/_benthos"
+   benthos completion fish > ~/.config/fish/completions/benthos.fish`[4:],
+		ArgsUsage: "bash|zsh|fish",
+		Action: func(c *cli.Context) error {
+			var script string
+			switch shell := c.Args().First(); shell {
+			case "bash":
+				script = bashCompletionScript
+			case "zsh":
+				script = zshCompletionScript
+			case "fish":
+				script = fishCompletionScript
+			default:
+				fmt.Fprintf(os.Stderr, "Unrecognised shell '%v', expected bash, zsh or fish\n", shell)
+				os.Exit(1)
+			}
+			fmt.Printf(script, c.App.Name, completeCommandName, completeNoSpace)
+			os.Exit(0)
+			return nil
+		},
+	}
+}
+
+func completeCliCommand() *cli.Command {
+	return &cli.Command{
+		Name:            completeCommandName,
+		Usage:           "Print the completion candidates of command line arguments",
+		Hidden:          true,
+		SkipFlagParsing: true,
+		Action: func(c *cli.Context) error {
+			candidates, noSpace := completeArgs(c.App, c.Args().Slice())
+			for _, candidate := range candidates {
+				fmt.Println(candidate)
+			}
+			if noSpace && len(candidates) > 0 {
+				fmt.Println(completeNoSpace)
+			}
+			os.Exit(0)
+			return nil
+		},
+	}
+}
@@ -113,7 +113,16 @@ func createCliCommand() *cli.Command {
    benthos create stdin/bloblang,awk/nats
    benthos create file,http_server/protobuf/http_client
 
-   If the expression is omitted a default config is created.`[4:],
+   If the expression is omitted a default config is created. Alternatively,
+   the flag --interactive prompts for the components of the config and the
+   values of their fields:
+
+   benthos create --interactive > ./config.yaml
+
+   The flags --all and --comments can be used in order to print every field
+   of the chosen components, each documented by a comment:
+
+   benthos create --all --comments kafka//http_client`[4:],
 		Flags: []cli.Flag{
 			&cli.BoolFlag{
 				Name:    "small",
@@ -121,9 +130,46 @@ func createCliCommand() *cli.Command {
 				Value:   false,
 				Usage:   "Print only the main components of a Benthos config (input, pipeline, output) and omit all fields marked as advanced.",
 			},
+			&cli.BoolFlag{
+				Name:    "interactive",
+				Aliases: []string{"i"},
+				Value:   false,
+				Usage:   "Prompt for the components of the config, their labels and the values of common fields, and optionally add a unit test skeleton.",
+			},
+			&cli.BoolFlag{
+				Name:  "all",
+				Value: false,
+				Usage: "Print every field of the config, including deprecated fields.",
+			},
+			&cli.BoolFlag{
+				Name:  "comments",
+				Value: false,
+				Usage: "Document each field of the config with a comment containing its description and default value.",
+			},
 		},
 		Action: func(c *cli.Context) error {
-			if expression := c.Args().First(); len(expression) > 0 {
+			if c.Bool("all") && c.Bool("small") {
+				fmt.Fprintln(os.Stderr, "Generate error: --all cannot be combined with --small")
+				os.Exit(1)
+			}
+
+			var wizard *createWizard
+			if c.Bool("interactive") {
+				if c.Args().Len() > 0 {
+					fmt.Fprintln(os.Stderr, "Generate error: an expression cannot be combined with --interactive")
+					os.Exit(1)
+				}
+				wizard = newCreateWizard(os.Stdin, os.Stderr)
+				expression, err := wizard.chooseComponents()
+				if err != nil {
+					fmt.Fprintf(os.Stderr, "Generate error: %v\n", err)
+					os.Exit(1)
+				}
+				if err = addExpression(&conf, expression); err != nil {
+					fmt.Fprintf(os.Stderr, "Generate error: %v\n", err)
+					os.Exit(1)
+				}
+			} else if expression := c.Args().First(); len(expression) > 0 {
 				if err := addExpression(&conf, expression); err != nil {
 					fmt.Fprintf(os.Stderr, "Generate error: %v\n", err)
 					os.Exit(1)
@@ -152,11 +198,15 @@ func createCliCommand() *cli.Command {
 			if err == nil {
 				err = config.Spec().SanitiseYAML(&node, docs.SanitiseConfig{
 					RemoveTypeField:  true,
-					RemoveDeprecated: true,
+					RemoveDeprecated: !c.Bool("all"),
 					ForExample:       true,
+					DocumentFields:   c.Bool("comments"),
 					Filter:           filter,
 				})
 			}
+			if err == nil && wizard != nil {
+				err = wizard.configure(&node)
+			}
 			if err == nil {
 				var configYAML []byte
 				if configYAML, err = uconfig.MarshalYAML(node); err == nil {
@@ -0,0 +1,416 @@
+package service
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/Jeffail/benthos/v3/internal/bundle"
+	"github.com/Jeffail/benthos/v3/internal/docs"
+	"github.com/Jeffail/benthos/v3/lib/service/test"
+	"gopkg.in/yaml.v3"
+)
+
+// createWizard prompts for the components of a new config and the values of
+// their fields. Prompts are written to a separate stream from the config so
+// that the config can be redirected to a file.
+type createWizard struct {
+	r *bufio.Reader
+	w io.Writer
+}
+
+func newCreateWizard(r io.Reader, w io.Writer) *createWizard {
+	return &createWizard{r: bufio.NewReader(r), w: w}
+}
+
+var errWizardInputEnded = errors.New("input ended before the config was completed")
+
+func (c *createWizard) ask(question string) (string, error) {
+	fmt.Fprintf(c.w, "%v: ", question)
+	line, err := c.r.ReadString('\n')
+	if err != nil {
+		if err == io.EOF && len(line) > 0 {
+			return strings.TrimSpace(line), nil
+		}
+		if err == io.EOF {
+			return "", errWizardInputEnded
+		}
+		return "", err
+	}
+	return strings.TrimSpace(line), nil
+}
+
+func (c *createWizard) confirm(question string) (bool, error) {
+	for {
+		answer, err := c.ask(question + " [y/N]")
+		if err != nil {
+			return false, err
+		}
+		switch strings.ToLower(answer) {
+		case "y", "yes":
+			return true, nil
+		case "", "n", "no":
+			return false, nil
+		}
+		fmt.Fprintln(c.w, "Please answer yes or no.")
+	}
+}
+
+func article(noun string) string {
+	if strings.ContainsAny(noun[:1], "aeiou") {
+		return "an"
+	}
+	return "a"
+}
+
+// firstSentence returns the first sentence of a markdown description.
+func firstSentence(s string) string {
+	s = strings.TrimSpace(s)
+	if i := strings.Index(s, "\n"); i >= 0 {
+		s = s[:i]
+	}
+	if i := strings.Index(s, ". "); i >= 0 {
+		s = s[:i+1]
+	}
+	return s
+}
+
+func exampleString(example interface{}) string {
+	switch t := example.(type) {
+	case []string:
+		return strings.Join(t, ", ")
+	case []interface{}:
+		strs := make([]string, len(t))
+		for i, v := range t {
+			strs[i] = fmt.Sprintf("%v", v)
+		}
+		return strings.Join(strs, ", ")
+	}
+	if exampleBytes, err := yaml.Marshal(example); err == nil {
+		return strings.TrimSpace(string(exampleBytes))
+	}
+	return fmt.Sprintf("%v", example)
+}
+
+// listComponents prints the names and summaries of components, optionally
+// filtered by a search term.
+func (c *createWizard) listComponents(specs []docs.ComponentSpec, search string) {
+	search = strings.ToLower(search)
+	matched := false
+	for _, spec := range specs {
+		if search != "" &&
+			!strings.Contains(spec.Name, search) &&
+			!strings.Contains(strings.ToLower(spec.Summary), search) {
+			continue
+		}
+		matched = true
+		fmt.Fprintf(c.w, "  %v: %v\n", spec.Name, firstSentence(spec.Summary))
+	}
+	if !matched {
+		fmt.Fprintf(c.w, "No components match the search term %v.\n", search)
+	}
+}
+
+// chooseComponent prompts for the name of a component of a type until a
+// recognised name is given. When optional an empty answer is accepted.
+func (c *createWizard) chooseComponent(cType docs.Type, specs []docs.ComponentSpec, optional bool) (string, error) {
+	var available []docs.ComponentSpec
+	for _, spec := range specs {
+		if spec.Status != docs.StatusDeprecated {
+			available = append(available, spec)
+		}
+	}
+	sort.Slice(available, func(i, j int) bool {
+		return available[i].Name < available[j].Name
+	})
+
+	question := fmt.Sprintf("Choose %v %v (enter ? to list them all, or ?term to search)", article(string(cType)), cType)
+	if optional {
+		question = fmt.Sprintf("Add %v %v (leave empty to finish, enter ? to list them all, or ?term to search)", article(string(cType)), cType)
+	}
+	for {
+		answer, err := c.ask(question)
+		if err != nil {
+			return "", err
+		}
+		if strings.HasPrefix(answer, "?") {
+			c.listComponents(available, strings.TrimPrefix(answer, "?"))
+			continue
+		}
+		if answer == "" {
+			if optional {
+				return "", nil
+			}
+			continue
+		}
+		for _, spec := range available {
+			if spec.Name == answer {
+				return answer, nil
+			}
+		}
+		fmt.Fprintf(c.w, "The %v %v was not recognised.\n", cType, answer)
+	}
+}
+
+// chooseComponents prompts for the input, processors and output of a config and
+// returns them as a create expression.
+func (c *createWizard) chooseComponents() (string, error) {
+	input, err := c.chooseComponent(docs.TypeInput, bundle.AllInputs.Docs(), false)
+	if err != nil {
+		return "", err
+	}
+	var processors []string
+	for {
+		proc, err := c.chooseComponent(docs.TypeProcessor, bundle.AllProcessors.Docs(), true)
+		if err != nil {
+			return "", err
+		}
+		if proc == "" {
+			break
+		}
+		processors = append(processors, proc)
+	}
+	output, err := c.chooseComponent(docs.TypeOutput, bundle.AllOutputs.Docs(), false)
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%v/%v/%v", input, strings.Join(processors, ","), output), nil
+}
+
+//------------------------------------------------------------------------------
+
+func getMapValue(node *yaml.Node, key string) *yaml.Node {
+	for i := 0; i < len(node.Content)-1; i += 2 {
+		if node.Content[i].Value == key {
+			return node.Content[i+1]
+		}
+	}
+	return nil
+}
+
+func setMapValue(node *yaml.Node, key string, value *yaml.Node) {
+	for i := 0; i < len(node.Content)-1; i += 2 {
+		if node.Content[i].Value == key {
+			node.Content[i+1] = value
+			return
+		}
+	}
+	node.Content = append(node.Content, &yaml.Node{Kind: yaml.ScalarNode, Value: key}, value)
+}
+
+func isEmptyValue(node *yaml.Node) bool {
+	if node == nil {
+		return true
+	}
+	switch node.Kind {
+	case yaml.ScalarNode:
+		return node.Value == ""
+	case yaml.SequenceNode, yaml.MappingNode:
+		return len(node.Content) == 0
+	}
+	return false
+}
+
+// shouldPrompt returns true if the wizard should ask for the value of a field,
+// which is the case for common scalar fields that are either empty or required.
+func shouldPrompt(spec docs.FieldSpec, value *yaml.Node) bool {
+	if spec.IsAdvanced || spec.IsDeprecated || len(spec.Children) > 0 {
+		return false
+	}
+	if _, isCore := spec.Type.IsCoreComponent(); isCore {
+		return false
+	}
+	if spec.Kind != docs.KindScalar && spec.Kind != docs.KindArray {
+		return false
+	}
+	if value == nil {
+		return !spec.IsOptional && spec.Default == nil
+	}
+	return isEmptyValue(value)
+}
+
+// scalarNode converts an answer into a scalar node of a field type, returning
+// an error if the answer is not a valid value of the type.
+func scalarNode(fieldType docs.FieldType, answer string) (*yaml.Node, error) {
+	node := &yaml.Node{Kind: yaml.ScalarNode, Value: answer, Tag: "!!str"}
+	switch fieldType {
+	case docs.FieldTypeInt:
+		if _, err := strconv.ParseInt(answer, 10, 64); err != nil {
+			return nil, fmt.Errorf("expected an integer value: %v", answer)
+		}
+		node.Tag = "!!int"
+	case docs.FieldTypeFloat:
+		if _, err := strconv.ParseFloat(answer, 64); err != nil {
+			return nil, fmt.Errorf("expected a number value: %v", answer)
+		}
+		node.Tag = "!!float"
+	case docs.FieldTypeBool:
+		if _, err := strconv.ParseBool(answer); err != nil {
+			return nil, fmt.Errorf("expected a boolean value: %v", answer)
+		}
+		node.Tag = "!!bool"
+	}
+	return node, nil
+}
+
+// promptField asks for the value of a field, returning nil if no value was
+// given.
+func (c *createWizard) promptField(name string, spec docs.FieldSpec) (*yaml.Node, error) {
+	fmt.Fprintln(c.w, "")
+	if desc := firstSentence(spec.Description); desc != "" {
+		fmt.Fprintln(c.w, desc)
+	}
+	if len(spec.Examples) > 0 {
+		fmt.Fprintf(c.w, "Example: %v\n", exampleString(spec.Examples[0]))
+	}
+	question := fmt.Sprintf("Value of %v (leave empty to skip)", name)
+	if spec.Kind == docs.KindArray {
+		question = fmt.Sprintf("Values of %v separated by commas (leave empty to skip)", name)
+	}
+	for {
+		answer, err := c.ask(question)
+		if err != nil || answer == "" {
+			return nil, err
+		}
+
+		if spec.Kind != docs.KindArray {
+			var node *yaml.Node
+			if node, err = scalarNode(spec.Type, answer); err != nil {
+				fmt.Fprintln(c.w, err)
+				continue
+			}
+			return node, nil
+		}
+
+		seq := &yaml.Node{Kind: yaml.SequenceNode}
+		for _, v := range strings.Split(answer, ",") {
+			var node *yaml.Node
+			if node, err = scalarNode(spec.Type, strings.TrimSpace(v)); err != nil {
+				break
+			}
+			seq.Content = append(seq.Content, node)
+		}
+		if err != nil {
+			fmt.Fprintln(c.w, err)
+			continue
+		}
+		return seq, nil
+	}
+}
+
+// configureComponent prompts for the label and field values of a component
+// within a sanitised config node.
+func (c *createWizard) configureComponent(path string, cType docs.Type, node *yaml.Node) error {
+	if node == nil || node.Kind != yaml.MappingNode {
+		return nil
+	}
+
+	var spec docs.ComponentSpec
+	var exists bool
+	var name string
+	for i := 0; i < len(node.Content)-1; i += 2 {
+		key := node.Content[i].Value
+		if key == "label" {
+			continue
+		}
+		if spec, exists = docs.GetDocs(nil, key, cType); exists {
+			name = key
+			break
+		}
+	}
+	if !exists {
+		return nil
+	}
+
+	fmt.Fprintf(c.w, "\nConfiguring %v %v at %v\n", cType, name, path)
+	for {
+		label, err := c.ask(fmt.Sprintf("Label for the %v (leave empty for none)", cType))
+		if err != nil {
+			return err
+		}
+		if label != "" {
+			if err = docs.ValidateLabel(label); err != nil {
+				fmt.Fprintf(c.w, "Invalid label: %v\n", err)
+				continue
+			}
+		}
+		setMapValue(node, "label", &yaml.Node{Kind: yaml.ScalarNode, Value: label, Tag: "!!str"})
+		break
+	}
+
+	compNode := getMapValue(node, name)
+	if len(spec.Config.Children) == 0 {
+		// Components such as bloblang are configured with a single value.
+		if shouldPrompt(spec.Config, compNode) {
+			fieldSpec := spec.Config
+			if fieldSpec.Description == "" {
+				fieldSpec.Description = spec.Summary
+			}
+			value, err := c.promptField(name, fieldSpec)
+			if err != nil {
+				return err
+			}
+			if value != nil {
+				setMapValue(node, name, value)
+			}
+		}
+		return nil
+	}
+
+	if compNode == nil || compNode.Kind != yaml.MappingNode {
+		return nil
+	}
+	for _, field := range spec.Config.Children {
+		if !shouldPrompt(field, getMapValue(compNode, field.Name)) {
+			continue
+		}
+		value, err := c.promptField(field.Name, field)
+		if err != nil {
+			return err
+		}
+		if value != nil {
+			setMapValue(compNode, field.Name, value)
+		}
+	}
+	return nil
+}
+
+// configure prompts for the labels and fields of the input, processors and
+// output of a sanitised config node, and optionally adds a test skeleton.
+func (c *createWizard) configure(root *yaml.Node) error {
+	if root.Kind == yaml.DocumentNode && len(root.Content) > 0 {
+		root = root.Content[0]
+	}
+
+	if err := c.configureComponent("input", docs.TypeInput, getMapValue(root, "input")); err != nil {
+		return err
+	}
+	if pipeline := getMapValue(root, "pipeline"); pipeline != nil {
+		if procs := getMapValue(pipeline, "processors"); procs != nil {
+			for i, proc := range procs.Content {
+				if err := c.configureComponent(fmt.Sprintf("pipeline.processors.%v", i), docs.TypeProcessor, proc); err != nil {
+					return err
+				}
+			}
+		}
+	}
+	if err := c.configureComponent("output", docs.TypeOutput, getMapValue(root, "output")); err != nil {
+		return err
+	}
+
+	fmt.Fprintln(c.w, "")
+	addTests, err := c.confirm("Add a skeleton unit test definition to the config?")
+	if err != nil || !addTests {
+		return err
+	}
+	var testsNode yaml.Node
+	if err := testsNode.Encode(test.ExampleDefinition().Cases); err != nil {
+		return err
+	}
+	setMapValue(root, "tests", &testsNode)
+	return nil
+}
@@ -0,0 +1,173 @@
+package service
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/Jeffail/benthos/v3/lib/stream"
+	"github.com/urfave/cli/v2"
+)
+
+//------------------------------------------------------------------------------
+
+// ctlRequest sends a request to the HTTP API of a running Benthos instance and
+// returns the response body.
+func ctlRequest(c *cli.Context, method, path string) ([]byte, error) {
+	base, err := url.Parse(c.String("address"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse address: %w", err)
+	}
+	base.Path = strings.TrimSuffix(base.Path, "/") + path
+
+	req, err := http.NewRequest(method, base.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+	if user := c.String("user"); user != "" {
+		colonIndex := strings.Index(user, ":")
+		if colonIndex == -1 {
+			return nil, errors.New("user must be of the form username:password")
+		}
+		req.SetBasicAuth(user[:colonIndex], user[colonIndex+1:])
+	}
+	if token := c.String("token"); token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+
+	client := http.Client{Timeout: time.Second * 30}
+	res, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+
+	resBytes, err := ioutil.ReadAll(res.Body)
+	if err != nil {
+		return nil, err
+	}
+	if res.StatusCode < 200 || res.StatusCode > 299 {
+		return nil, fmt.Errorf("request returned status %v: %s", res.StatusCode, strings.TrimSpace(string(resBytes)))
+	}
+	return resBytes, nil
+}
+
+func ctlAction(fn func(c *cli.Context) error) cli.ActionFunc {
+	return func(c *cli.Context) error {
+		if err := fn(c); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		return nil
+	}
+}
+
+func ctlComponentAction(action string) cli.ActionFunc {
+	return ctlAction(func(c *cli.Context) error {
+		if c.Args().Len() != 1 {
+			return fmt.Errorf("expected a single component label, got %v arguments", c.Args().Len())
+		}
+		label := c.Args().First()
+		if _, err := ctlRequest(c, "POST", "/components/"+url.PathEscape(label)+"/"+action); err != nil {
+			return err
+		}
+		if action == "pause" {
+			fmt.Printf("%v: %v\n", label, yellow("PAUSED"))
+		} else {
+			fmt.Printf("%v: %v\n", label, green("RUNNING"))
+		}
+		return nil
+	})
+}
+
+func ctlCliCommand() *cli.Command {
+	return &cli.Command{
+		Name:  "ctl",
+		Usage: "Control a running Benthos instance via its HTTP API",
+		Description: `
+   Sends commands to the HTTP API of a running Benthos instance, which allows
+   the labelled input and output of its pipeline to be paused and resumed:
+
+   benthos ctl components
+   benthos ctl pause kafka_in
+   benthos ctl resume kafka_in
+   benthos ctl --address https://benthos.example.com:4195 --token $TOKEN drain
+
+   When authentication is enabled for the HTTP API the credentials of a client
+   with the admin scope must be provided with either --user or --token.`[4:],
+		Flags: []cli.Flag{
+			&cli.StringFlag{
+				Name:  "address",
+				Value: "http://localhost:4195",
+				Usage: "The address of the HTTP API of the Benthos instance.",
+			},
+			&cli.StringFlag{
+				Name:  "user",
+				Value: "",
+				Usage: "Credentials of the form username:password for authenticating with basic auth.",
+			},
+			&cli.StringFlag{
+				Name:  "token",
+				Value: "",
+				Usage: "A token for authenticating with a bearer token.",
+			},
+		},
+		Subcommands: []*cli.Command{
+			{
+				Name:  "components",
+				Usage: "List the components that can be paused along with their states",
+				Action: ctlAction(func(c *cli.Context) error {
+					resBytes, err := ctlRequest(c, "GET", "/components")
+					if err != nil {
+						return err
+					}
+					var states []stream.ComponentState
+					if err = json.Unmarshal(resBytes, &states); err != nil {
+						return fmt.Errorf("failed to parse response: %w", err)
+					}
+					if len(states) == 0 {
+						fmt.Println("No components can be paused, set a label on the input or output in order to pause it")
+						return nil
+					}
+					for _, s := range states {
+						state := green("RUNNING")
+						if s.Paused {
+							state = yellow("PAUSED")
+						}
+						fmt.Printf("%v (%v): %v\n", s.Label, s.Kind, state)
+					}
+					return nil
+				}),
+			},
+			{
+				Name:      "pause",
+				Usage:     "Pause the flow of messages through a labelled input or output",
+				ArgsUsage: "<label>",
+				Action:    ctlComponentAction("pause"),
+			},
+			{
+				Name:      "resume",
+				Usage:     "Resume the flow of messages through a paused input or output",
+				ArgsUsage: "<label>",
+				Action:    ctlComponentAction("resume"),
+			},
+			{
+				Name:  "drain",
+				Usage: "Stop the pipeline from consuming messages, leaving the instance running",
+				Action: ctlAction(func(c *cli.Context) error {
+					if _, err := ctlRequest(c, "POST", "/drain"); err != nil {
+						return err
+					}
+					fmt.Println(green("DRAINED"))
+					return nil
+				}),
+			},
+		},
+	}
+}
@@ -0,0 +1,226 @@
+package service
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/Jeffail/benthos/v3/internal/bloblang/query"
+	"github.com/Jeffail/benthos/v3/internal/bundle"
+	"github.com/Jeffail/benthos/v3/internal/docs"
+	"github.com/Jeffail/benthos/v3/lib/buffer"
+	"github.com/Jeffail/benthos/v3/lib/cache"
+	"github.com/Jeffail/benthos/v3/lib/input"
+	"github.com/Jeffail/benthos/v3/lib/metrics"
+	"github.com/Jeffail/benthos/v3/lib/output"
+	"github.com/Jeffail/benthos/v3/lib/processor"
+	"github.com/Jeffail/benthos/v3/lib/ratelimit"
+	"github.com/Jeffail/benthos/v3/lib/tracer"
+	uconfig "github.com/Jeffail/benthos/v3/lib/util/config"
+	"github.com/urfave/cli/v2"
+)
+
+// docsComponentType describes how to obtain the documentation of each
+// component of a type, and how to generate the example configs of a component
+// of the type for its markdown document.
+type docsComponentType struct {
+	docs     func() []docs.ComponentSpec
+	nest     bool
+	sanitise func(name string) (interface{}, error)
+}
+
+var docsComponentTypes = map[string]docsComponentType{
+	"buffers": {bundle.AllBuffers.Docs, true, func(name string) (interface{}, error) {
+		conf := buffer.NewConfig()
+		conf.Type = name
+		return buffer.SanitiseConfig(conf)
+	}},
+	"caches": {bundle.AllCaches.Docs, false, func(name string) (interface{}, error) {
+		conf := cache.NewConfig()
+		conf.Type = name
+		return cache.SanitiseConfig(conf)
+	}},
+	"inputs": {bundle.AllInputs.Docs, true, func(name string) (interface{}, error) {
+		conf := input.NewConfig()
+		conf.Type = name
+		return input.SanitiseConfig(conf)
+	}},
+	"metrics": {bundle.AllMetrics.Docs, true, func(name string) (interface{}, error) {
+		conf := metrics.NewConfig()
+		conf.Type = name
+		return metrics.SanitiseConfig(conf)
+	}},
+	"outputs": {bundle.AllOutputs.Docs, true, func(name string) (interface{}, error) {
+		conf := output.NewConfig()
+		conf.Type = name
+		return output.SanitiseConfig(conf)
+	}},
+	"processors": {bundle.AllProcessors.Docs, false, func(name string) (interface{}, error) {
+		conf := processor.NewConfig()
+		conf.Type = name
+		return processor.SanitiseConfig(conf)
+	}},
+	"rate-limits": {bundle.AllRateLimits.Docs, false, func(name string) (interface{}, error) {
+		conf := ratelimit.NewConfig()
+		conf.Type = name
+		return ratelimit.SanitiseConfig(conf)
+	}},
+	"tracers": {bundle.AllTracers.Docs, true, func(name string) (interface{}, error) {
+		conf := tracer.NewConfig()
+		conf.Type = name
+		return tracer.SanitiseConfig(conf)
+	}},
+}
+
+var docsTypes = []string{
+	"inputs",
+	"processors",
+	"outputs",
+	"caches",
+	"rate-limits",
+	"buffers",
+	"metrics",
+	"tracers",
+	"bloblang-functions",
+	"bloblang-methods",
+}
+
+func docsCliCommand() *cli.Command {
+	return &cli.Command{
+		Name:  "docs",
+		Usage: "Print the documentation of components and Bloblang features",
+		Description: `
+   Prints the documentation of a component or Bloblang function or method as
+   it exists within this build of Benthos, including any plugins, to stdout.
+   When only a type is specified the names of each documented feature of that
+   type are listed, and when no type is specified the types are listed.
+
+   benthos docs inputs
+   benthos docs inputs kafka
+   benthos docs bloblang-methods uppercase
+   benthos docs --format json processors bloblang`[4:],
+		Flags: []cli.Flag{
+			&cli.StringFlag{
+				Name:  "format",
+				Value: "markdown",
+				Usage: "Print the documentation in a specific format. Options are markdown or json.",
+			},
+		},
+		Action: func(c *cli.Context) error {
+			if err := printDocs(c.String("format"), c.Args().Get(0), c.Args().Get(1)); err != nil {
+				fmt.Fprintf(os.Stderr, "Docs error: %v\n", err)
+				os.Exit(1)
+			}
+			os.Exit(0)
+			return nil
+		},
+	}
+}
+
+func printDocs(format, docsType, name string) error {
+	if format != "markdown" && format != "json" {
+		return fmt.Errorf("format not recognised: %v", format)
+	}
+	if docsType == "" {
+		for _, t := range docsTypes {
+			fmt.Println(t)
+		}
+		return nil
+	}
+
+	// Allow singular forms such as `input` and `rate-limit`.
+	requestedType := docsType
+	if !strings.HasSuffix(docsType, "s") {
+		docsType += "s"
+	}
+
+	var specs []interface{}
+	var names []string
+	var markdown func(i int) ([]byte, error)
+
+	switch docsType {
+	case "bloblang-functions":
+		fSpecs := query.FunctionDocs()
+		for _, spec := range fSpecs {
+			if spec.Status != query.StatusHidden {
+				specs = append(specs, spec)
+				names = append(names, spec.Name)
+			}
+		}
+		markdown = func(i int) ([]byte, error) {
+			return docs.BloblangFunctionMarkdown(specs[i].(query.FunctionSpec))
+		}
+	case "bloblang-methods":
+		mSpecs := query.MethodDocs()
+		for _, spec := range mSpecs {
+			if spec.Status != query.StatusHidden {
+				specs = append(specs, spec)
+				names = append(names, spec.Name)
+			}
+		}
+		markdown = func(i int) ([]byte, error) {
+			return docs.BloblangMethodMarkdown(specs[i].(query.MethodSpec))
+		}
+	default:
+		cType, exists := docsComponentTypes[docsType]
+		if !exists {
+			return fmt.Errorf("type not recognised: %v", requestedType)
+		}
+		cSpecs := cType.docs()
+		sort.Slice(cSpecs, func(i, j int) bool {
+			return cSpecs[i].Name < cSpecs[j].Name
+		})
+		for _, spec := range cSpecs {
+			specs = append(specs, spec)
+			names = append(names, spec.Name)
+		}
+		markdown = func(i int) ([]byte, error) {
+			spec := cSpecs[i]
+			conf, err := cType.sanitise(spec.Name)
+			if err != nil {
+				return nil, err
+			}
+			if s, ok := conf.(uconfig.Sanitised); ok {
+				conf = map[string]interface{}(s)
+			}
+			return spec.AsMarkdown(cType.nest, conf)
+		}
+	}
+
+	if name == "" {
+		if format == "json" {
+			return printDocsJSON(specs)
+		}
+		for _, n := range names {
+			fmt.Println(n)
+		}
+		return nil
+	}
+
+	for i, n := range names {
+		if n != name {
+			continue
+		}
+		if format == "json" {
+			return printDocsJSON(specs[i])
+		}
+		mdBytes, err := markdown(i)
+		if err != nil {
+			return err
+		}
+		_, err = os.Stdout.Write(mdBytes)
+		return err
+	}
+	return fmt.Errorf("%v not recognised: %v", strings.TrimSuffix(docsType, "s"), name)
+}
+
+func printDocsJSON(v interface{}) error {
+	jsonBytes, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	fmt.Println(string(jsonBytes))
+	return nil
+}
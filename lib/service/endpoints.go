@@ -0,0 +1,84 @@
+package service
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/Jeffail/benthos/v3/lib/api"
+	"github.com/Jeffail/benthos/v3/lib/log"
+	"github.com/Jeffail/benthos/v3/lib/stream"
+	"github.com/gorilla/mux"
+)
+
+//------------------------------------------------------------------------------
+
+// registerPipelineEndpoints registers the admin endpoints for controlling the
+// pipeline of a service that isn't running in streams mode.
+func registerPipelineEndpoints(httpServer *api.Type, strm *reloadableStream, logger log.Modular, drainTimeout time.Duration) {
+	httpServer.RegisterEndpoint(
+		"/drain",
+		"Stops the pipeline from consuming messages and waits for in-flight messages to be delivered, leaving the service running. Requires a POST request.",
+		httpServer.Auth().Wrap(func(w http.ResponseWriter, r *http.Request) {
+			if r.Method != http.MethodPost {
+				http.Error(w, "Method Not Allowed", http.StatusMethodNotAllowed)
+				return
+			}
+			logger.Infoln("Received drain request, the pipeline is stopping.")
+			if err := strm.Drain(drainTimeout); err != nil {
+				logger.Errorf("Failed to drain pipeline: %v\n", err)
+				http.Error(w, fmt.Sprintf("Failed to drain pipeline: %v", err), http.StatusInternalServerError)
+				return
+			}
+			logger.Infoln("Pipeline drained, the service remains running until it is stopped.")
+			w.Write([]byte("OK"))
+		}),
+	)
+	httpServer.RegisterEndpoint(
+		"/components",
+		"Returns a JSON array of the labelled input and output of the pipeline along with whether they are paused.",
+		httpServer.Auth().Wrap(func(w http.ResponseWriter, r *http.Request) {
+			resBytes, err := json.Marshal(strm.ComponentStates())
+			if err != nil {
+				http.Error(w, err.Error(), http.StatusBadGateway)
+				return
+			}
+			w.Header().Set("Content-Type", "application/json")
+			w.Write(resBytes)
+		}),
+	)
+	httpServer.RegisterEndpoint(
+		"/components/{label}/{action:pause|resume}",
+		"Pauses or resumes the flow of messages through the labelled input or output of the pipeline. Requires a POST request.",
+		httpServer.Auth().Wrap(func(w http.ResponseWriter, r *http.Request) {
+			if r.Method != http.MethodPost {
+				http.Error(w, "Method Not Allowed", http.StatusMethodNotAllowed)
+				return
+			}
+			vars := mux.Vars(r)
+			label, action := vars["label"], vars["action"]
+
+			var err error
+			if action == "pause" {
+				err = strm.Pause(label)
+			} else {
+				err = strm.Resume(label)
+			}
+			if err == stream.ErrComponentNotFound {
+				http.Error(w, fmt.Sprintf("Component '%v' not found", label), http.StatusNotFound)
+				return
+			}
+			if err != nil {
+				http.Error(w, err.Error(), http.StatusBadGateway)
+				return
+			}
+			if action == "pause" {
+				logger.Infof("Component '%v' paused.\n", label)
+			} else {
+				logger.Infof("Component '%v' resumed.\n", label)
+			}
+			w.Write([]byte("OK"))
+		}),
+	)
+}
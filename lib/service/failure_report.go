@@ -0,0 +1,257 @@
+package service
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/Jeffail/benthos/v3/internal/docs"
+	"github.com/Jeffail/benthos/v3/lib/stream"
+	"gopkg.in/yaml.v3"
+)
+
+//------------------------------------------------------------------------------
+
+// failureReportDest is where a report is written when the service fails to
+// start, which is either stderr or the path of a file. Reports are not written
+// when empty.
+var failureReportDest string
+
+// failureDetail describes a single problem that prevented the service from
+// starting.
+type failureDetail struct {
+	Message string `json:"message"`
+	File    string `json:"file,omitempty"`
+	Line    int    `json:"line,omitempty"`
+	Path    string `json:"path,omitempty"`
+	Hint    string `json:"hint,omitempty"`
+}
+
+// failureReport is a machine readable description of why the service failed
+// to start, intended for tools that wrap Benthos.
+type failureReport struct {
+	Stage       string          `json:"stage"`
+	Message     string          `json:"message"`
+	Hint        string          `json:"hint,omitempty"`
+	ConfigPaths []string        `json:"config_paths,omitempty"`
+	Errors      []failureDetail `json:"errors"`
+}
+
+var failureHints = []struct {
+	pattern *regexp.Regexp
+	hint    string
+}{
+	{regexp.MustCompile(`no such file or directory`), "Check that the path exists and is readable by Benthos."},
+	{regexp.MustCompile(`field \S+ not recognised`), "Check the spelling and indentation of the field, the fields of a component are printed with `benthos docs <type> <name>`."},
+	{regexp.MustCompile(`(unable to infer|type '\S*' was not recognised|unrecognised)`), "Check the name of the component, the components available within this build are printed with `benthos list`."},
+	{regexp.MustCompile(`line \d+ char \d+`), "The Bloblang mapping or interpolation could not be parsed, mappings can be tested with `benthos blobl server`."},
+	{regexp.MustCompile(`cannot unmarshal`), "A field has a value of the wrong type, the type of each field is printed with `benthos list --fields`."},
+	{regexp.MustCompile(`yaml:`), "The config is not valid YAML, check the indentation and quoting around the reported line."},
+	{regexp.MustCompile(`(?i)duration`), "Durations are a number followed by a unit, such as 500ms, 10s or 1m."},
+	{regexp.MustCompile(`(?i)address already in use`), "Another process is bound to the same address, change the address of the component or stop the other process."},
+}
+
+// failureHint returns a suggestion for fixing an error, if a common cause of
+// the error is known.
+func failureHint(msg string) string {
+	for _, h := range failureHints {
+		if h.pattern.MatchString(msg) {
+			return h.hint
+		}
+	}
+	return ""
+}
+
+//------------------------------------------------------------------------------
+
+// yamlPathAtLine returns the path of the deepest field of a YAML document that
+// is defined at or encloses a line.
+func yamlPathAtLine(node *yaml.Node, line int) []string {
+	if node.Kind == yaml.DocumentNode && len(node.Content) > 0 {
+		node = node.Content[0]
+	}
+	switch node.Kind {
+	case yaml.MappingNode:
+		index := -1
+		for i := 0; i < len(node.Content)-1; i += 2 {
+			if node.Content[i].Line <= line {
+				index = i
+			}
+		}
+		if index == -1 {
+			return nil
+		}
+		return append([]string{node.Content[index].Value}, yamlPathAtLine(node.Content[index+1], line)...)
+	case yaml.SequenceNode:
+		index := -1
+		for i, child := range node.Content {
+			if child.Line <= line {
+				index = i
+			}
+		}
+		if index == -1 {
+			return nil
+		}
+		return append([]string{strconv.Itoa(index)}, yamlPathAtLine(node.Content[index], line)...)
+	}
+	return nil
+}
+
+func readYAMLFile(path string) (*yaml.Node, error) {
+	fileBytes, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var node yaml.Node
+	if err = yaml.Unmarshal(fileBytes, &node); err != nil {
+		return nil, err
+	}
+	return &node, nil
+}
+
+var lintLineRegexp = regexp.MustCompile(`(?s)^(?:resource file )?(?:(.+?): )?line (\d+): (.*)$`)
+
+var yamlErrRegexp = regexp.MustCompile(`(?s)^(\S+?): yaml: .*?line (\d+)`)
+
+//------------------------------------------------------------------------------
+
+// startupReporter writes failure reports for the config files of a service.
+type startupReporter struct {
+	confPaths []string
+	files     map[string]*yaml.Node
+}
+
+func newStartupReporter(confPaths []string) *startupReporter {
+	return &startupReporter{
+		confPaths: confPaths,
+		files:     map[string]*yaml.Node{},
+	}
+}
+
+func (s *startupReporter) file(path string) *yaml.Node {
+	node, exists := s.files[path]
+	if !exists {
+		node, _ = readYAMLFile(path)
+		s.files[path] = node
+	}
+	return node
+}
+
+// lintDetail converts a lint of the form `file: line N: message` into a
+// failure detail, resolving the path of the field at that line.
+func (s *startupReporter) lintDetail(lint string) failureDetail {
+	d := failureDetail{Message: lint}
+	matches := lintLineRegexp.FindStringSubmatch(lint)
+	if matches == nil {
+		d.Hint = failureHint(lint)
+		return d
+	}
+
+	d.File, d.Message = matches[1], matches[3]
+	d.Line, _ = strconv.Atoi(matches[2])
+	d.Hint = failureHint(d.Message)
+	if d.File == "" && len(s.confPaths) == 1 {
+		d.File = s.confPaths[0]
+	}
+	if d.File != "" {
+		if node := s.file(d.File); node != nil {
+			d.Path = strings.Join(yamlPathAtLine(node, d.Line), ".")
+		}
+	}
+	return d
+}
+
+// errDetail converts an error into a failure detail. When the error was caused
+// by a component of a stream the path of the component is resolved, and the
+// path is prefixed with pathPrefix when set.
+func (s *startupReporter) errDetail(pathPrefix string, err error) failureDetail {
+	d := failureDetail{
+		Message: err.Error(),
+		Path:    pathPrefix,
+		Hint:    failureHint(err.Error()),
+	}
+
+	var cErr *stream.ComponentError
+	if !errors.As(err, &cErr) {
+		// Errors from parsing config files might contain a file and line.
+		if matches := yamlErrRegexp.FindStringSubmatch(d.Message); matches != nil {
+			d.File = matches[1]
+			d.Line, _ = strconv.Atoi(matches[2])
+			if node := s.file(d.File); node != nil {
+				d.Path = strings.Join(yamlPathAtLine(node, d.Line), ".")
+			}
+		}
+		return d
+	}
+	if d.Path == "" {
+		d.Path = cErr.Path
+	} else {
+		d.Path = d.Path + "." + cErr.Path
+	}
+	if d.Hint == "" {
+		d.Hint = fmt.Sprintf("Check the config of the component at %v against its documentation, which is printed with `benthos docs`.", d.Path)
+	}
+
+	// The location of a component is only known for the main config file.
+	if pathPrefix == "" && len(s.confPaths) == 1 {
+		if root := s.file(s.confPaths[0]); root != nil {
+			if node, err := docs.GetYAMLPath(root, strings.Split(cErr.Path, ".")...); err == nil {
+				d.File, d.Line = s.confPaths[0], node.Line
+			}
+		}
+	}
+	return d
+}
+
+func (s *startupReporter) write(r failureReport) {
+	if failureReportDest == "" {
+		return
+	}
+	r.ConfigPaths = s.confPaths
+	if r.Errors == nil {
+		r.Errors = []failureDetail{}
+	}
+
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	if err := enc.Encode(r); err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to marshal failure report: %v\n", err)
+		return
+	}
+	if failureReportDest == "stderr" {
+		os.Stderr.Write(buf.Bytes())
+		return
+	}
+	if err := ioutil.WriteFile(failureReportDest, buf.Bytes(), 0644); err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to write failure report: %v\n", err)
+	}
+}
+
+// fail writes a report for an error that occurred at a stage of startup.
+func (s *startupReporter) fail(stage, message, pathPrefix string, err error) {
+	s.write(failureReport{
+		Stage:   stage,
+		Message: message,
+		Errors:  []failureDetail{s.errDetail(pathPrefix, err)},
+	})
+}
+
+// failLints writes a report for a startup that was aborted due to lint errors.
+func (s *startupReporter) failLints(lints []string) {
+	r := failureReport{
+		Stage:   "lint",
+		Message: "Shutting down due to linter errors",
+		Hint:    "Fix the lint errors, or run Benthos with --chilled in order to ignore them.",
+	}
+	for _, lint := range lints {
+		r.Errors = append(r.Errors, s.lintDetail(lint))
+	}
+	s.write(r)
+}
@@ -2,15 +2,19 @@ package service
 
 import (
 	"bytes"
+	"encoding/json"
 	"fmt"
 	"io/ioutil"
 	"os"
 	"path"
 	"path/filepath"
 	"runtime"
+	"sort"
 	"strings"
 	"sync"
 
+	iconfig "github.com/Jeffail/benthos/v3/internal/config"
+	"github.com/Jeffail/benthos/v3/internal/docs"
 	"github.com/Jeffail/benthos/v3/lib/config"
 	"github.com/fatih/color"
 	"github.com/urfave/cli/v2"
@@ -19,6 +23,7 @@ import (
 
 var red = color.New(color.FgRed).SprintFunc()
 var yellow = color.New(color.FgYellow).SprintFunc()
+var green = color.New(color.FgGreen).SprintFunc()
 
 func resolveLintPath(path string) (string, bool) {
 	recurse := false
@@ -35,14 +40,58 @@ func resolveLintPath(path string) (string, bool) {
 
 type pathLint struct {
 	source string
-	line   int
-	lint   string
-	err    string
+
+	// The line of the markdown snippet that the lint was found within, or zero
+	// if the lint was found within a config file.
+	snippetLine int
+
+	lint docs.Lint
+	err  string
+}
+
+// line returns the line of the source file that the lint was found at.
+func (p pathLint) line() int {
+	if p.snippetLine > 0 && p.lint.Line > 0 {
+		return p.snippetLine + p.lint.Line - 1
+	}
+	if p.snippetLine > 0 {
+		return p.snippetLine
+	}
+	return p.lint.Line
+}
+
+func (p pathLint) level() docs.LintLevel {
+	if len(p.err) > 0 {
+		return docs.LintError
+	}
+	return p.lint.Level
+}
+
+func (p pathLint) ruleID() docs.LintType {
+	if len(p.err) > 0 {
+		return docs.LintFailedRead
+	}
+	if p.lint.Type == "" {
+		return docs.LintCustom
+	}
+	return p.lint.Type
+}
+
+func (p pathLint) message() string {
+	if len(p.err) > 0 {
+		return p.err
+	}
+	return p.lint.What
 }
 
-func lintFile(path string) (pathLints []pathLint) {
+func lintFile(path string, rules *docs.LintRules) (pathLints []pathLint) {
 	conf := config.New()
-	lints, err := config.Read(path, true, &conf)
+	lints, err := config.ReadLinted(path, true, &conf)
+	if err == nil && rules != nil {
+		var ruleLints []docs.Lint
+		ruleLints, err = config.LintRulesFile(rules, path)
+		lints = append(lints, ruleLints...)
+	}
 	if err != nil {
 		pathLints = append(pathLints, pathLint{
 			source: path,
@@ -59,7 +108,7 @@ func lintFile(path string) (pathLints []pathLint) {
 	return
 }
 
-func lintMDSnippets(path string) (pathLints []pathLint) {
+func lintMDSnippets(path string, rules *docs.LintRules) (pathLints []pathLint) {
 	rawBytes, err := ioutil.ReadFile(path)
 	if err != nil {
 		pathLints = append(pathLints, pathLint{
@@ -80,9 +129,9 @@ func lintMDSnippets(path string) (pathLints []pathLint) {
 		endOfSnippet := bytes.Index(rawBytes[nextSnippet:], endTag)
 		if endOfSnippet == -1 {
 			pathLints = append(pathLints, pathLint{
-				source: path,
-				line:   snippetLine,
-				err:    "markdown snippet not terminated",
+				source:      path,
+				snippetLine: snippetLine,
+				err:         "markdown snippet not terminated",
 			})
 			return
 		}
@@ -93,24 +142,29 @@ func lintMDSnippets(path string) (pathLints []pathLint) {
 
 		if err := yaml.Unmarshal(configBytes, &conf); err != nil {
 			pathLints = append(pathLints, pathLint{
-				source: path,
-				line:   snippetLine,
-				err:    err.Error(),
+				source:      path,
+				snippetLine: snippetLine,
+				err:         err.Error(),
 			})
 		} else {
-			lints, err := config.Lint(configBytes, conf)
+			lints, err := config.LintBytes(configBytes)
+			if err == nil && rules != nil {
+				var ruleLints []docs.Lint
+				ruleLints, err = config.LintRulesBytes(rules, configBytes)
+				lints = append(lints, ruleLints...)
+			}
 			if err != nil {
 				pathLints = append(pathLints, pathLint{
-					source: path,
-					line:   snippetLine,
-					err:    err.Error(),
+					source:      path,
+					snippetLine: snippetLine,
+					err:         err.Error(),
 				})
 			}
 			for _, l := range lints {
 				pathLints = append(pathLints, pathLint{
-					source: path,
-					line:   snippetLine,
-					lint:   l,
+					source:      path,
+					snippetLine: snippetLine,
+					lint:        l,
 				})
 			}
 		}
@@ -122,12 +176,194 @@ func lintMDSnippets(path string) (pathLints []pathLint) {
 	return
 }
 
+// lintsFailed returns true if any of the lints are errors, whereas warnings
+// are reported without failing the lint.
+func lintsFailed(pathLints []pathLint) bool {
+	for _, lint := range pathLints {
+		if lint.level() == docs.LintError {
+			return true
+		}
+	}
+	return false
+}
+
+//------------------------------------------------------------------------------
+
+func printLintsText(pathLints []pathLint) {
+	for _, lint := range pathLints {
+		what := lint.lint.What
+		if lint.lint.Level == docs.LintWarning {
+			what = "warning: " + what
+		}
+		var message string
+		switch {
+		case len(lint.err) > 0:
+			message = red(lint.err)
+		case lint.lint.Line > 0 && lint.lint.Column > 0:
+			message = yellow(fmt.Sprintf("line %v col %v: %v", lint.lint.Line, lint.lint.Column, what))
+		case lint.lint.Line > 0:
+			message = yellow(fmt.Sprintf("line %v: %v", lint.lint.Line, what))
+		default:
+			message = yellow(what)
+		}
+		if lint.snippetLine > 0 {
+			fmt.Fprintf(os.Stderr, "%v: from snippet at line %v: %v\n", lint.source, lint.snippetLine, message)
+		} else {
+			fmt.Fprintf(os.Stderr, "%v: %v\n", lint.source, message)
+		}
+	}
+}
+
+type jsonLint struct {
+	File     string `json:"file"`
+	Line     int    `json:"line,omitempty"`
+	Column   int    `json:"column,omitempty"`
+	Severity string `json:"severity"`
+	Rule     string `json:"rule"`
+	Message  string `json:"message"`
+}
+
+func printLintsJSON(pathLints []pathLint) error {
+	jLints := []jsonLint{}
+	for _, lint := range pathLints {
+		jLints = append(jLints, jsonLint{
+			File:     lint.source,
+			Line:     lint.line(),
+			Column:   lint.lint.Column,
+			Severity: lint.level().String(),
+			Rule:     string(lint.ruleID()),
+			Message:  lint.message(),
+		})
+	}
+	return printJSONIndented(jLints)
+}
+
+// sarifLog is a minimal subset of the Static Analysis Results Interchange
+// Format (SARIF) v2.1.0, as consumed by code review tools for annotating
+// findings: https://docs.oasis-open.org/sarif/sarif/v2.1.0/sarif-v2.1.0.html
+type sarifLog struct {
+	Schema  string     `json:"$schema"`
+	Version string     `json:"version"`
+	Runs    []sarifRun `json:"runs"`
+}
+
+type sarifRun struct {
+	Tool    sarifTool     `json:"tool"`
+	Results []sarifResult `json:"results"`
+}
+
+type sarifTool struct {
+	Driver sarifDriver `json:"driver"`
+}
+
+type sarifDriver struct {
+	Name           string      `json:"name"`
+	Version        string      `json:"version,omitempty"`
+	InformationURI string      `json:"informationUri"`
+	Rules          []sarifRule `json:"rules"`
+}
+
+type sarifRule struct {
+	ID string `json:"id"`
+}
+
+type sarifMessage struct {
+	Text string `json:"text"`
+}
+
+type sarifResult struct {
+	RuleID    string          `json:"ruleId"`
+	Level     string          `json:"level"`
+	Message   sarifMessage    `json:"message"`
+	Locations []sarifLocation `json:"locations"`
+}
+
+type sarifLocation struct {
+	PhysicalLocation sarifPhysicalLocation `json:"physicalLocation"`
+}
+
+type sarifPhysicalLocation struct {
+	ArtifactLocation sarifArtifactLocation `json:"artifactLocation"`
+	Region           *sarifRegion          `json:"region,omitempty"`
+}
+
+type sarifArtifactLocation struct {
+	URI string `json:"uri"`
+}
+
+type sarifRegion struct {
+	StartLine   int `json:"startLine"`
+	StartColumn int `json:"startColumn,omitempty"`
+}
+
+func printLintsSARIF(pathLints []pathLint) error {
+	run := sarifRun{
+		Tool: sarifTool{
+			Driver: sarifDriver{
+				Name:           "benthos",
+				Version:        Version,
+				InformationURI: "https://www.benthos.dev",
+				Rules:          []sarifRule{},
+			},
+		},
+		Results: []sarifResult{},
+	}
+
+	seenRules := map[docs.LintType]struct{}{}
+	for _, lint := range pathLints {
+		ruleID := lint.ruleID()
+		if _, seen := seenRules[ruleID]; !seen {
+			seenRules[ruleID] = struct{}{}
+			run.Tool.Driver.Rules = append(run.Tool.Driver.Rules, sarifRule{ID: string(ruleID)})
+		}
+
+		loc := sarifLocation{
+			PhysicalLocation: sarifPhysicalLocation{
+				ArtifactLocation: sarifArtifactLocation{
+					URI: filepath.ToSlash(lint.source),
+				},
+			},
+		}
+		if line := lint.line(); line > 0 {
+			loc.PhysicalLocation.Region = &sarifRegion{
+				StartLine:   line,
+				StartColumn: lint.lint.Column,
+			}
+		}
+		run.Results = append(run.Results, sarifResult{
+			RuleID:    string(ruleID),
+			Level:     lint.level().String(),
+			Message:   sarifMessage{Text: lint.message()},
+			Locations: []sarifLocation{loc},
+		})
+	}
+
+	return printJSONIndented(sarifLog{
+		Schema:  "https://json.schemastore.org/sarif-2.1.0.json",
+		Version: "2.1.0",
+		Runs:    []sarifRun{run},
+	})
+}
+
+func printJSONIndented(v interface{}) error {
+	jsonBytes, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return err
+	}
+	fmt.Println(string(jsonBytes))
+	return nil
+}
+
+//------------------------------------------------------------------------------
+
 func lintCliCommand() *cli.Command {
 	return &cli.Command{
 		Name:  "lint",
 		Usage: "Parse Benthos configs and report any linting errors",
 		Description: `
-   Exits with a status code 1 if any linting errors are detected:
+   Exits with a status code 1 if any linting errors are detected. Warnings,
+   such as unused variables within mappings, are printed but do not affect the
+   status code:
    
    benthos -c target.yaml lint
    benthos lint ./configs/*.yaml
@@ -135,8 +371,33 @@ func lintCliCommand() *cli.Command {
    benthos lint ./configs/...
    
    If a path ends with '...' then Benthos will walk the target and lint any
-   files with the .yaml or .yml extension.`[4:],
+   files with the .yaml or .yml extension.
+
+   The --format flag prints lints to stdout in a structured format including
+   the file, line, column, severity and rule of each lint. The json format
+   prints an array of lints, and the sarif format prints a SARIF v2.1.0 log
+   that can be uploaded to code review tools in order to annotate findings:
+
+   benthos lint --format sarif ./configs/... > lints.sarif
+
+   Custom lint rules can be enforced with the --lint-rules flag, which is also
+   enforced when running a config:
+
+   benthos --lint-rules ./rules.yaml lint ./configs/...`[4:],
+		Flags: []cli.Flag{
+			&cli.StringFlag{
+				Name:  "format",
+				Value: "text",
+				Usage: "Print lints in a specific format. Options are text, json or sarif.",
+			},
+		},
 		Action: func(c *cli.Context) error {
+			format := c.String("format")
+			if format != "text" && format != "json" && format != "sarif" {
+				fmt.Fprintf(os.Stderr, "Format not recognised: %v\n", format)
+				os.Exit(1)
+			}
+
 			var targets []string
 			for _, p := range c.Args().Slice() {
 				var recurse bool
@@ -161,8 +422,20 @@ func lintCliCommand() *cli.Command {
 					targets = append(targets, p)
 				}
 			}
-			if conf := c.String("config"); len(conf) > 0 {
-				targets = append(targets, conf)
+			confPaths, cerr := iconfig.NewReader(c.StringSlice("config"), nil).MainPaths()
+			if cerr != nil {
+				fmt.Fprintf(os.Stderr, "Failed to resolve config paths: %v\n", cerr)
+				os.Exit(1)
+			}
+			targets = append(targets, confPaths...)
+
+			var rules *docs.LintRules
+			if rulesPath := c.String("lint-rules"); len(rulesPath) > 0 {
+				var err error
+				if rules, err = config.ReadLintRules(rulesPath); err != nil {
+					fmt.Fprintf(os.Stderr, "Lint rules read error: %v\n", err)
+					os.Exit(1)
+				}
 			}
 
 			var pathLintMut sync.Mutex
@@ -182,9 +455,9 @@ func lintCliCommand() *cli.Command {
 						}
 						var lints []pathLint
 						if path.Ext(target) == ".md" {
-							lints = lintMDSnippets(target)
+							lints = lintMDSnippets(target, rules)
 						} else {
-							lints = lintFile(target)
+							lints = lintFile(target, rules)
 						}
 						if len(lints) > 0 {
 							pathLintMut.Lock()
@@ -195,21 +468,31 @@ func lintCliCommand() *cli.Command {
 				}(i)
 			}
 			wg.Wait()
-			if len(pathLints) == 0 {
-				os.Exit(0)
-			}
-			for _, lint := range pathLints {
-				message := yellow(lint.lint)
-				if len(lint.err) > 0 {
-					message = red(lint.err)
-				}
-				if lint.line > 0 {
-					fmt.Fprintf(os.Stderr, "%v: from snippet at line %v: %v\n", lint.source, lint.line, message)
-				} else {
-					fmt.Fprintf(os.Stderr, "%v: %v\n", lint.source, message)
+
+			sort.SliceStable(pathLints, func(i, j int) bool {
+				if pathLints[i].source != pathLints[j].source {
+					return pathLints[i].source < pathLints[j].source
 				}
+				return pathLints[i].line() < pathLints[j].line()
+			})
+
+			var err error
+			switch format {
+			case "json":
+				err = printLintsJSON(pathLints)
+			case "sarif":
+				err = printLintsSARIF(pathLints)
+			default:
+				printLintsText(pathLints)
+			}
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Failed to print lints: %v\n", err)
+				os.Exit(1)
+			}
+			if lintsFailed(pathLints) {
+				os.Exit(1)
 			}
-			os.Exit(1)
+			os.Exit(0)
 			return nil
 		},
 	}
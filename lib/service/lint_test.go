@@ -0,0 +1,43 @@
+package service
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/Jeffail/benthos/v3/internal/docs"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLintFileWarnings(t *testing.T) {
+	dir := t.TempDir()
+
+	warnPath := filepath.Join(dir, "warn.yaml")
+	require.NoError(t, os.WriteFile(warnPath, []byte(`pipeline:
+  processors:
+    - bloblang: |
+        let unused = "foo"
+        root = this
+`), 0644))
+
+	lints := lintFile(warnPath, nil)
+	require.Len(t, lints, 1)
+	assert.Equal(t, docs.LintWarning, lints[0].level())
+	assert.Equal(t, 4, lints[0].line())
+	assert.Equal(t, 9, lints[0].lint.Column)
+	assert.Equal(t, "variable $unused is declared but never used", lints[0].message())
+	assert.False(t, lintsFailed(lints))
+
+	errPath := filepath.Join(dir, "err.yaml")
+	require.NoError(t, os.WriteFile(errPath, []byte(`pipeline:
+  processors:
+    - bloblang: |
+        root = this.
+`), 0644))
+
+	lints = append(lints, lintFile(errPath, nil)...)
+	require.Len(t, lints, 2)
+	assert.Equal(t, docs.LintError, lints[1].level())
+	assert.True(t, lintsFailed(lints))
+}
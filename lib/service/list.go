@@ -3,6 +3,7 @@ package service
 import (
 	"encoding/json"
 	"fmt"
+	"os"
 	"sort"
 	"strings"
 
@@ -11,6 +12,7 @@ import (
 	"github.com/Jeffail/benthos/v3/internal/docs"
 	"github.com/Jeffail/benthos/v3/lib/condition"
 	"github.com/Jeffail/benthos/v3/lib/config"
+	"github.com/Jeffail/gabs/v2"
 	"github.com/urfave/cli/v2"
 )
 
@@ -29,11 +31,11 @@ type fullSchema struct {
 	BloblangMethods   []string `json:"bloblang-methods,omitempty"`
 }
 
-func (f *fullSchema) flattened() map[string][]string {
+func (f *fullSchema) flattened(includeDeprecated bool) map[string][]string {
 	justNames := func(components []docs.ComponentSpec) []string {
 		names := []string{}
 		for _, c := range components {
-			if c.Status != docs.StatusDeprecated {
+			if includeDeprecated || c.Status != docs.StatusDeprecated {
 				names = append(names, c.Name)
 			}
 		}
@@ -54,47 +56,241 @@ func (f *fullSchema) flattened() map[string][]string {
 	}
 }
 
+func (f *fullSchema) components() map[string][]docs.ComponentSpec {
+	return map[string][]docs.ComponentSpec{
+		"buffers":     f.Buffers,
+		"caches":      f.Caches,
+		"inputs":      f.Inputs,
+		"outputs":     f.Outputs,
+		"processors":  f.Processors,
+		"rate-limits": f.RateLimits,
+		"metrics":     f.Metrics,
+		"tracers":     f.Tracers,
+	}
+}
+
+//------------------------------------------------------------------------------
+
+// listFilter restricts the components that are listed to those of a set of
+// statuses and categories.
+type listFilter struct {
+	statuses   map[string]struct{}
+	categories map[string]struct{}
+}
+
+func newListFilter(statuses, categories []string) listFilter {
+	toSet := func(values []string) map[string]struct{} {
+		if len(values) == 0 {
+			return nil
+		}
+		m := map[string]struct{}{}
+		for _, v := range values {
+			for _, s := range strings.Split(v, ",") {
+				if s = strings.ToLower(strings.TrimSpace(s)); s != "" {
+					m[s] = struct{}{}
+				}
+			}
+		}
+		return m
+	}
+	return listFilter{
+		statuses:   toSet(statuses),
+		categories: toSet(categories),
+	}
+}
+
+func (l listFilter) active() bool {
+	return l.statuses != nil || l.categories != nil
+}
+
+func (l listFilter) matches(status string, categories []string) bool {
+	if l.statuses != nil {
+		if status == "" {
+			status = string(docs.StatusStable)
+		}
+		if _, exists := l.statuses[strings.ToLower(status)]; !exists {
+			return false
+		}
+	}
+	if l.categories != nil {
+		for _, c := range categories {
+			if _, exists := l.categories[strings.ToLower(c)]; exists {
+				return true
+			}
+		}
+		return false
+	}
+	return true
+}
+
+func (l listFilter) components(specs []docs.ComponentSpec) []docs.ComponentSpec {
+	if !l.active() {
+		return specs
+	}
+	filtered := []docs.ComponentSpec{}
+	for _, spec := range specs {
+		if l.matches(string(spec.Status), spec.Categories) {
+			filtered = append(filtered, spec)
+		}
+	}
+	return filtered
+}
+
+func (l listFilter) bloblangFunctions() []string {
+	names := []string{}
+	for _, spec := range query.FunctionDocs() {
+		if l.matches(string(spec.Status), []string{string(spec.Category)}) {
+			names = append(names, spec.Name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+func (l listFilter) bloblangMethods() []string {
+	names := []string{}
+	for _, spec := range query.MethodDocs() {
+		var categories []string
+		for _, c := range spec.Categories {
+			categories = append(categories, string(c.Category))
+		}
+		if l.matches(string(spec.Status), categories) {
+			names = append(names, spec.Name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+//------------------------------------------------------------------------------
+
+// listField describes a config field of a component for the --fields mode of
+// the list command.
+type listField struct {
+	Path     string      `json:"path"`
+	Type     string      `json:"type"`
+	Kind     string      `json:"kind"`
+	Default  interface{} `json:"default,omitempty"`
+	Advanced bool        `json:"advanced"`
+}
+
+type listComponent struct {
+	Name       string      `json:"name"`
+	Status     string      `json:"status"`
+	Categories []string    `json:"categories,omitempty"`
+	Fields     []listField `json:"fields"`
+}
+
+func listComponentFields(spec docs.ComponentSpec) listComponent {
+	status := string(spec.Status)
+	if status == "" {
+		status = string(docs.StatusStable)
+	}
+	c := listComponent{
+		Name:       spec.Name,
+		Status:     status,
+		Categories: spec.Categories,
+		Fields:     []listField{},
+	}
+	for _, f := range spec.Config.FlattenChildrenForDocs() {
+		field := listField{
+			Path:     f.FullName,
+			Type:     string(f.Spec.Type),
+			Kind:     string(f.Spec.Kind),
+			Advanced: f.Spec.IsAdvanced,
+		}
+		if f.Spec.Default != nil {
+			field.Default = *f.Spec.Default
+		}
+		c.Fields = append(c.Fields, field)
+	}
+	if len(c.Fields) == 0 && spec.Config.Type != "" && len(spec.Config.Children) == 0 {
+		// Components such as bloblang are configured with a single value.
+		field := listField{
+			Type: string(spec.Config.Type),
+			Kind: string(spec.Config.Kind),
+		}
+		if spec.Config.Default != nil {
+			field.Default = *spec.Config.Default
+		}
+		c.Fields = append(c.Fields, field)
+	}
+	return c
+}
+
+func (f listField) describe() string {
+	var typeStr string
+	switch f.Kind {
+	case string(docs.KindArray):
+		typeStr = "array of " + f.Type
+	case string(docs.Kind2DArray):
+		typeStr = "array of arrays of " + f.Type
+	case string(docs.KindMap):
+		typeStr = "map of " + f.Type
+	default:
+		typeStr = f.Type
+	}
+	if f.Default != nil {
+		typeStr += ", default: " + gabs.Wrap(f.Default).String()
+	}
+	if f.Advanced {
+		typeStr += ", advanced"
+	}
+	if f.Path == "" {
+		return typeStr
+	}
+	return fmt.Sprintf("%v (%v)", f.Path, typeStr)
+}
+
 func listComponents(c *cli.Context) {
 	ofTypes := map[string]struct{}{}
 	for _, k := range c.Args().Slice() {
 		ofTypes[k] = struct{}{}
 	}
+	filter := newListFilter(c.StringSlice("status"), c.StringSlice("category"))
 
 	schema := fullSchema{
 		Config:            config.Spec(),
-		Buffers:           bundle.AllBuffers.Docs(),
-		Caches:            bundle.AllCaches.Docs(),
-		Inputs:            bundle.AllInputs.Docs(),
-		Outputs:           bundle.AllOutputs.Docs(),
-		Processors:        bundle.AllProcessors.Docs(),
-		RateLimits:        bundle.AllRateLimits.Docs(),
-		Metrics:           bundle.AllMetrics.Docs(),
-		Tracers:           bundle.AllTracers.Docs(),
+		Buffers:           filter.components(bundle.AllBuffers.Docs()),
+		Caches:            filter.components(bundle.AllCaches.Docs()),
+		Inputs:            filter.components(bundle.AllInputs.Docs()),
+		Outputs:           filter.components(bundle.AllOutputs.Docs()),
+		Processors:        filter.components(bundle.AllProcessors.Docs()),
+		RateLimits:        filter.components(bundle.AllRateLimits.Docs()),
+		Metrics:           filter.components(bundle.AllMetrics.Docs()),
+		Tracers:           filter.components(bundle.AllTracers.Docs()),
 		BloblangFunctions: query.ListFunctions(),
 		BloblangMethods:   query.ListMethods(),
 	}
-	for t := range condition.Constructors {
-		schema.conditions = append(schema.conditions, t)
+	if filter.active() {
+		// Conditions are not documented with a status or categories and
+		// are therefore omitted when filtering.
+		schema.BloblangFunctions = filter.bloblangFunctions()
+		schema.BloblangMethods = filter.bloblangMethods()
+	} else {
+		for t := range condition.Constructors {
+			schema.conditions = append(schema.conditions, t)
+		}
+		sort.Strings(schema.conditions)
+	}
+
+	// Deprecated components are hidden from lists of names unless they are
+	// explicitly requested.
+	_, includeDeprecated := filter.statuses[string(docs.StatusDeprecated)]
+
+	if c.Bool("fields") {
+		if err := listComponentsWithFields(c.String("format"), ofTypes, &schema, includeDeprecated); err != nil {
+			fmt.Fprintf(os.Stderr, "List error: %v\n", err)
+			os.Exit(1)
+		}
+		return
 	}
-	sort.Strings(schema.conditions)
 
 	switch c.String("format") {
 	case "text":
-		flat := schema.flattened()
+		flat := schema.flattened(includeDeprecated)
 		i := 0
-		for _, k := range []string{
-			"inputs",
-			"processors",
-			"conditions",
-			"outputs",
-			"caches",
-			"rate-limits",
-			"buffers",
-			"metrics",
-			"tracers",
-			"bloblang-functions",
-			"bloblang-methods",
-		} {
+		for _, k := range listTypes {
 			if _, exists := ofTypes[k]; len(ofTypes) > 0 && !exists {
 				continue
 			}
@@ -109,7 +305,7 @@ func listComponents(c *cli.Context) {
 			}
 		}
 	case "json":
-		flat := schema.flattened()
+		flat := schema.flattened(includeDeprecated)
 		if len(ofTypes) > 0 {
 			for k := range flat {
 				if _, exists := ofTypes[k]; !exists {
@@ -122,6 +318,25 @@ func listComponents(c *cli.Context) {
 			panic(err)
 		}
 		fmt.Println(string(jsonBytes))
+	case "json-schema":
+		var components []docs.ComponentSpec
+		for _, specs := range [][]docs.ComponentSpec{
+			schema.Buffers,
+			schema.Caches,
+			schema.Inputs,
+			schema.Outputs,
+			schema.Processors,
+			schema.RateLimits,
+			schema.Metrics,
+			schema.Tracers,
+		} {
+			components = append(components, specs...)
+		}
+		jsonBytes, err := json.Marshal(docs.ConfigJSONSchema(schema.Config, components...))
+		if err != nil {
+			panic(err)
+		}
+		fmt.Println(string(jsonBytes))
 	case "json-full":
 		jsonBytes, err := json.Marshal(schema)
 		if err != nil {
@@ -130,3 +345,84 @@ func listComponents(c *cli.Context) {
 		fmt.Println(string(jsonBytes))
 	}
 }
+
+// listTypes is the order in which component types are listed in the text
+// format.
+var listTypes = []string{
+	"inputs",
+	"processors",
+	"conditions",
+	"outputs",
+	"caches",
+	"rate-limits",
+	"buffers",
+	"metrics",
+	"tracers",
+	"bloblang-functions",
+	"bloblang-methods",
+}
+
+// listComponentsWithFields prints the config fields of each listed component
+// along with their types and defaults. Types without config fields are listed
+// by name only.
+func listComponentsWithFields(format string, ofTypes map[string]struct{}, schema *fullSchema, includeDeprecated bool) error {
+	flat := schema.flattened(includeDeprecated)
+	components := schema.components()
+	detailed := func(t string) []listComponent {
+		details := []listComponent{}
+		for _, spec := range components[t] {
+			if includeDeprecated || spec.Status != docs.StatusDeprecated {
+				details = append(details, listComponentFields(spec))
+			}
+		}
+		return details
+	}
+
+	switch format {
+	case "text":
+		i := 0
+		for _, k := range listTypes {
+			if _, exists := ofTypes[k]; len(ofTypes) > 0 && !exists {
+				continue
+			}
+			if i > 0 {
+				fmt.Println("")
+			}
+			i++
+			title := strings.Title(strings.ReplaceAll(k, "-", " "))
+			fmt.Printf("%v:\n", title)
+			if _, hasFields := components[k]; !hasFields {
+				for _, t := range flat[k] {
+					fmt.Printf("  - %v\n", t)
+				}
+				continue
+			}
+			for _, comp := range detailed(k) {
+				fmt.Printf("  - %v\n", comp.Name)
+				for _, field := range comp.Fields {
+					fmt.Printf("      %v\n", field.describe())
+				}
+			}
+		}
+	case "json":
+		result := map[string]interface{}{}
+		for _, k := range listTypes {
+			if _, exists := ofTypes[k]; len(ofTypes) > 0 && !exists {
+				continue
+			}
+			if _, hasFields := components[k]; hasFields {
+				result[k] = detailed(k)
+			} else {
+				result[k] = flat[k]
+			}
+		}
+		jsonBytes, err := json.Marshal(result)
+		if err != nil {
+			return err
+		}
+		fmt.Println(string(jsonBytes))
+	default:
+		return fmt.Errorf("the --fields flag does not support the format %v, expected text or json", format)
+	}
+	return nil
+}
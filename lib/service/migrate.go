@@ -0,0 +1,315 @@
+package service
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/Jeffail/benthos/v3/internal/docs"
+	"github.com/Jeffail/benthos/v3/lib/config"
+	uconfig "github.com/Jeffail/benthos/v3/lib/util/config"
+	"github.com/urfave/cli/v2"
+	"gopkg.in/yaml.v3"
+)
+
+//------------------------------------------------------------------------------
+
+// migrateFile reads a config file and returns the migrations applied to it,
+// along with the migrated config, which is nil when no automatic migrations
+// were made.
+func migrateFile(path string) ([]docs.Migration, []byte, error) {
+	fileBytes, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var node yaml.Node
+	if err = yaml.Unmarshal(fileBytes, &node); err != nil {
+		return nil, nil, err
+	}
+	migrations := config.Spec().MigrateYAML(nil, "", &node)
+
+	automatic := false
+	for _, m := range migrations {
+		if !m.Manual {
+			automatic = true
+		}
+	}
+	if !automatic {
+		return migrations, nil, nil
+	}
+
+	migratedBytes, err := uconfig.MarshalYAML(&node)
+	if err != nil {
+		return nil, nil, err
+	}
+	return migrations, restoreBlankLines(fileBytes, migratedBytes), nil
+}
+
+//------------------------------------------------------------------------------
+
+type diffLine struct {
+	op   byte
+	text string
+
+	// The index of the line within each file at this point in the diff.
+	i, j int
+}
+
+func splitLines(b []byte) []string {
+	return strings.Split(strings.TrimSuffix(string(b), "\n"), "\n")
+}
+
+// diffOps returns the operations that convert the lines of a into b, based on
+// the longest common subsequence of lines.
+func diffOps(a, b []string) []diffLine {
+	lcs := make([][]int, len(a)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(b)+1)
+	}
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else if lcs[i+1][j] >= lcs[i][j+1] {
+				lcs[i][j] = lcs[i+1][j]
+			} else {
+				lcs[i][j] = lcs[i][j+1]
+			}
+		}
+	}
+
+	var ops []diffLine
+	i, j := 0, 0
+	for i < len(a) || j < len(b) {
+		switch {
+		case i < len(a) && j < len(b) && a[i] == b[j]:
+			ops = append(ops, diffLine{' ', a[i], i, j})
+			i++
+			j++
+		case i < len(a) && (j == len(b) || lcs[i+1][j] >= lcs[i][j+1]):
+			ops = append(ops, diffLine{'-', a[i], i, j})
+			i++
+		default:
+			ops = append(ops, diffLine{'+', b[j], i, j})
+			j++
+		}
+	}
+	return ops
+}
+
+// restoreBlankLines adds the blank lines of a config that were dropped when
+// the migrated config was encoded.
+func restoreBlankLines(from, to []byte) []byte {
+	var buf bytes.Buffer
+	blanks := 0
+	for _, op := range diffOps(splitLines(from), splitLines(to)) {
+		switch {
+		case op.op == '-':
+			// Blank lines are placed before the next unchanged line so that
+			// they follow any fields added to the end of a section.
+			if strings.TrimSpace(op.text) == "" {
+				blanks++
+			}
+			continue
+		case op.op == ' ':
+			for ; blanks > 0; blanks-- {
+				buf.WriteByte('\n')
+			}
+		}
+		buf.WriteString(op.text)
+		buf.WriteByte('\n')
+	}
+	return buf.Bytes()
+}
+
+// diffLines returns the lines of a unified diff between two files, with three
+// lines of context around each change.
+func diffLines(fromName, toName string, from, to []byte) []string {
+	ops := diffOps(splitLines(from), splitLines(to))
+
+	const context = 3
+	lines := []string{"--- " + fromName, "+++ " + toName}
+	for start := 0; start < len(ops); {
+		if ops[start].op == ' ' {
+			start++
+			continue
+		}
+
+		// Extend the hunk until there are more than two lots of context
+		// between changes.
+		end := start
+		for k := start; k < len(ops); k++ {
+			if ops[k].op != ' ' {
+				end = k
+			} else if k-end > context*2 {
+				break
+			}
+		}
+		hunkStart := start - context
+		if hunkStart < 0 {
+			hunkStart = 0
+		}
+		hunkEnd := end + context + 1
+		if hunkEnd > len(ops) {
+			hunkEnd = len(ops)
+		}
+
+		var fromCount, toCount int
+		var body []string
+		for _, op := range ops[hunkStart:hunkEnd] {
+			if op.op != '+' {
+				fromCount++
+			}
+			if op.op != '-' {
+				toCount++
+			}
+			body = append(body, string(op.op)+op.text)
+		}
+		lines = append(lines, fmt.Sprintf(
+			"@@ -%v,%v +%v,%v @@",
+			ops[hunkStart].i+1, fromCount, ops[hunkStart].j+1, toCount,
+		))
+		lines = append(lines, body...)
+		start = hunkEnd
+	}
+	return lines
+}
+
+//------------------------------------------------------------------------------
+
+func migrateCliCommand() *cli.Command {
+	return &cli.Command{
+		Name:  "migrate",
+		Usage: "Rewrite deprecated components and fields of configs",
+		Description: `
+   Migrates configs away from deprecated components and fields, printing the
+   changes made to each config as a diff:
+
+   benthos migrate -c ./old.yaml
+   benthos migrate ./configs/...
+
+   Deprecated components that have a direct replacement are renamed, and
+   deprecated fields that are set to their default values are removed. Any
+   deprecated components and fields that cannot be migrated automatically are
+   listed so that they can be migrated by hand. Use the --write flag in order
+   to apply changes to the config files:
+
+   benthos migrate --write ./configs/...
+
+   If a path ends with '...' then Benthos will walk the target and migrate any
+   files with the .yaml or .yml extension. Configs are rewritten in a
+   normalised format, where environment variable interpolations are
+   preserved.`[4:],
+		Flags: []cli.Flag{
+			&cli.StringSliceFlag{
+				Name:    "config",
+				Aliases: []string{"c"},
+				Usage:   "A path to a config file to migrate, can be specified multiple times.",
+			},
+			&cli.BoolFlag{
+				Name:    "write",
+				Aliases: []string{"w"},
+				Value:   false,
+				Usage:   "Write migrated configs back to their files rather than printing a diff.",
+			},
+		},
+		Action: func(c *cli.Context) error {
+			var targets []string
+			for _, p := range c.Args().Slice() {
+				var recurse bool
+				if p, recurse = resolveLintPath(p); recurse {
+					if err := filepath.Walk(p, func(path string, info os.FileInfo, werr error) error {
+						if werr != nil {
+							return werr
+						}
+						if !info.IsDir() && (strings.HasSuffix(path, ".yaml") || strings.HasSuffix(path, ".yml")) {
+							targets = append(targets, path)
+						}
+						return nil
+					}); err != nil {
+						fmt.Fprintf(os.Stderr, "Filesystem walk error: %v\n", err)
+						os.Exit(1)
+					}
+				} else {
+					targets = append(targets, p)
+				}
+			}
+			// Configs can be specified either before or after the command.
+			for _, ctx := range c.Lineage() {
+				targets = append(targets, ctx.StringSlice("config")...)
+			}
+			if len(targets) == 0 {
+				fmt.Fprintln(os.Stderr, "No config paths were provided.")
+				os.Exit(1)
+			}
+
+			seen := map[string]struct{}{}
+			failed, manual := false, 0
+			for _, target := range targets {
+				if _, exists := seen[target]; exists {
+					continue
+				}
+				seen[target] = struct{}{}
+
+				migrations, migrated, err := migrateFile(target)
+				if err != nil {
+					fmt.Fprintf(os.Stderr, "%v: %v\n", target, err)
+					failed = true
+					continue
+				}
+				if len(migrations) == 0 {
+					continue
+				}
+
+				fmt.Printf("%v:\n", target)
+				for _, m := range migrations {
+					if m.Manual {
+						manual++
+						fmt.Printf("  line %v: %v %v\n", m.Line, yellow("MANUAL"), m.What)
+					} else {
+						fmt.Printf("  line %v: %v\n", m.Line, m.What)
+					}
+				}
+				if migrated == nil {
+					continue
+				}
+
+				if c.Bool("write") {
+					if err := ioutil.WriteFile(target, migrated, 0644); err != nil {
+						fmt.Fprintf(os.Stderr, "%v: failed to write migrated config: %v\n", target, err)
+						failed = true
+					}
+					continue
+				}
+
+				fileBytes, _ := ioutil.ReadFile(target)
+				var diff bytes.Buffer
+				for _, line := range diffLines(target, target+" (migrated)", fileBytes, migrated) {
+					switch {
+					case strings.HasPrefix(line, "---"), strings.HasPrefix(line, "+++"):
+						diff.WriteString(line)
+					case strings.HasPrefix(line, "-"):
+						diff.WriteString(red(line))
+					case strings.HasPrefix(line, "+"):
+						diff.WriteString(green(line))
+					default:
+						diff.WriteString(line)
+					}
+					diff.WriteByte('\n')
+				}
+				fmt.Print(diff.String())
+			}
+			if manual > 0 {
+				fmt.Printf("%v deprecated components and fields must be migrated by hand.\n", manual)
+			}
+			if failed {
+				os.Exit(1)
+			}
+			return nil
+		},
+	}
+}
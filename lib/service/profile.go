@@ -0,0 +1,103 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"runtime"
+	"runtime/pprof"
+	"runtime/trace"
+	"strings"
+)
+
+//------------------------------------------------------------------------------
+
+// startProfiles begins the profiles requested with the --profile flag, each of
+// the form <type>=<path>, and returns a func that stops them and writes them
+// to their files.
+func startProfiles(specs []string) (func() error, error) {
+	var stopFuncs []func() error
+	stop := func() error {
+		var errs []string
+		for _, fn := range stopFuncs {
+			if err := fn(); err != nil {
+				errs = append(errs, err.Error())
+			}
+		}
+		if len(errs) > 0 {
+			return errors.New(strings.Join(errs, ", "))
+		}
+		return nil
+	}
+
+	for _, spec := range specs {
+		eqIndex := strings.Index(spec, "=")
+		if eqIndex <= 0 || eqIndex == len(spec)-1 {
+			_ = stop()
+			return nil, fmt.Errorf("profile '%v' is not of the form <type>=<path>", spec)
+		}
+		kind, path := spec[:eqIndex], spec[eqIndex+1:]
+
+		fn, err := startProfile(kind, path)
+		if err != nil {
+			_ = stop()
+			return nil, fmt.Errorf("failed to start %v profile: %w", kind, err)
+		}
+		stopFuncs = append(stopFuncs, fn)
+	}
+	return stop, nil
+}
+
+func startProfile(kind, path string) (func() error, error) {
+	switch kind {
+	case "cpu", "trace":
+	case "block":
+		runtime.SetBlockProfileRate(1)
+	case "mutex":
+		runtime.SetMutexProfileFraction(1)
+	case "allocs", "goroutine", "heap", "threadcreate":
+	default:
+		return nil, errors.New("profile type not recognised, expected one of: allocs, block, cpu, goroutine, heap, mutex, threadcreate, trace")
+	}
+
+	f, err := os.Create(path)
+	if err != nil {
+		return nil, err
+	}
+
+	switch kind {
+	case "cpu":
+		if err = pprof.StartCPUProfile(f); err != nil {
+			f.Close()
+			return nil, err
+		}
+		return func() error {
+			pprof.StopCPUProfile()
+			return f.Close()
+		}, nil
+	case "trace":
+		if err = trace.Start(f); err != nil {
+			f.Close()
+			return nil, err
+		}
+		return func() error {
+			trace.Stop()
+			return f.Close()
+		}, nil
+	}
+
+	// Snapshot profiles are written when the service shuts down.
+	return func() error {
+		if kind == "heap" {
+			runtime.GC()
+		}
+		err := pprof.Lookup(kind).WriteTo(f, 0)
+		if cerr := f.Close(); err == nil {
+			err = cerr
+		}
+		if err != nil {
+			return fmt.Errorf("failed to write %v profile: %w", kind, err)
+		}
+		return nil
+	}, nil
+}
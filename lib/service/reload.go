@@ -0,0 +1,250 @@
+package service
+
+import (
+	"fmt"
+	"os"
+	"reflect"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/Jeffail/benthos/v3/lib/stream"
+	"github.com/Jeffail/benthos/v3/lib/types"
+)
+
+//------------------------------------------------------------------------------
+
+type streamBuilder func(conf stream.Config, onClose func()) (*stream.Type, error)
+
+// reloadableStream wraps the stream of a single pipeline in order to allow it to
+// be replaced with a new version at runtime. The new version is started before
+// the old version is drained gracefully, and if the new version fails to start
+// the old version continues running.
+type reloadableStream struct {
+	mut     sync.Mutex
+	strm    *stream.Type
+	conf    stream.Config
+	stopped bool
+	drained bool
+	build   streamBuilder
+
+	// Labels of components that have been paused, which are paused again
+	// when the stream is replaced.
+	paused map[string]struct{}
+
+	// The generation of the current stream, which prevents the closure of
+	// replaced streams from being mistaken for the pipeline terminating.
+	gen       int64
+	closedGen int64
+	closed    chan struct{}
+	closeOnce sync.Once
+}
+
+func newReloadableStream(conf stream.Config, build streamBuilder) (*reloadableStream, error) {
+	r := &reloadableStream{
+		build:  build,
+		closed: make(chan struct{}),
+		paused: map[string]struct{}{},
+	}
+	if err := r.start(conf); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// start a new generation of the stream, must be called with the mutex held or
+// during construction. The current generation is only replaced when the new
+// stream is created successfully.
+func (r *reloadableStream) start(conf stream.Config) error {
+	prevGen := atomic.LoadInt64(&r.gen)
+	gen := prevGen + 1
+
+	// The generation is advanced before the stream is created so that a new
+	// stream terminating immediately is still observed.
+	atomic.StoreInt64(&r.gen, gen)
+	strm, err := r.build(conf, func() {
+		atomic.StoreInt64(&r.closedGen, gen)
+		if atomic.LoadInt64(&r.gen) == gen {
+			r.close()
+		}
+	})
+	if err != nil {
+		// The previous stream may have terminated whilst the new one was
+		// being created.
+		atomic.StoreInt64(&r.gen, prevGen)
+		if prevGen > 0 && atomic.LoadInt64(&r.closedGen) == prevGen {
+			r.close()
+		}
+		return err
+	}
+	r.strm, r.conf = strm, conf
+	for label := range r.paused {
+		if err := strm.Pause(label); err != nil {
+			delete(r.paused, label)
+		}
+	}
+	return nil
+}
+
+func (r *reloadableStream) close() {
+	r.closeOnce.Do(func() {
+		close(r.closed)
+	})
+}
+
+// ClosedChan returns a channel that is closed when the current stream
+// terminates by itself.
+func (r *reloadableStream) ClosedChan() <-chan struct{} {
+	return r.closed
+}
+
+// Reload replaces the stream with a new version when the config has changed,
+// or when force is true, returning a boolean indicating whether the stream was
+// replaced.
+func (r *reloadableStream) Reload(conf stream.Config, force bool, timeout time.Duration) (bool, error) {
+	r.mut.Lock()
+	defer r.mut.Unlock()
+
+	if r.stopped || r.drained {
+		return false, types.ErrTypeClosed
+	}
+	if !force && reflect.DeepEqual(r.conf, conf) {
+		return false, nil
+	}
+
+	// The new stream is started before the old one is stopped, and therefore
+	// if the new config fails the old stream continues uninterrupted.
+	oldStrm := r.strm
+	if err := r.start(conf); err != nil {
+		return false, fmt.Errorf("%v, the previous pipeline is still running", err)
+	}
+	if err := oldStrm.Stop(timeout); err != nil {
+		return true, fmt.Errorf("failed to stop the previous pipeline: %w", err)
+	}
+	return true, nil
+}
+
+// Pause the labelled component of the current stream.
+func (r *reloadableStream) Pause(label string) error {
+	r.mut.Lock()
+	defer r.mut.Unlock()
+
+	if err := r.strm.Pause(label); err != nil {
+		return err
+	}
+	r.paused[label] = struct{}{}
+	return nil
+}
+
+// Resume the labelled component of the current stream.
+func (r *reloadableStream) Resume(label string) error {
+	r.mut.Lock()
+	defer r.mut.Unlock()
+
+	if err := r.strm.Resume(label); err != nil {
+		return err
+	}
+	delete(r.paused, label)
+	return nil
+}
+
+// ComponentStates returns the state of each component of the current stream
+// that can be paused.
+func (r *reloadableStream) ComponentStates() []stream.ComponentState {
+	r.mut.Lock()
+	defer r.mut.Unlock()
+
+	return r.strm.ComponentStates()
+}
+
+// IsReady returns true when the inputs and outputs of the current stream are
+// connected.
+func (r *reloadableStream) IsReady() bool {
+	r.mut.Lock()
+	defer r.mut.Unlock()
+
+	return r.strm.IsReady()
+}
+
+// IsAlive returns true when neither the inputs or outputs of the current stream
+// have been disconnected for longer than the liveness grace period.
+func (r *reloadableStream) IsAlive() bool {
+	r.mut.Lock()
+	defer r.mut.Unlock()
+
+	return r.strm.IsAlive()
+}
+
+// Drain stops the current stream without it being treated as the pipeline
+// terminating, which leaves the service running until it is stopped.
+func (r *reloadableStream) Drain(timeout time.Duration) error {
+	r.mut.Lock()
+	defer r.mut.Unlock()
+
+	if r.stopped {
+		return types.ErrTypeClosed
+	}
+	if r.drained {
+		return nil
+	}
+
+	atomic.AddInt64(&r.gen, 1)
+	r.drained = true
+	return r.strm.Stop(timeout)
+}
+
+// Stop the current stream.
+func (r *reloadableStream) Stop(timeout time.Duration) error {
+	r.mut.Lock()
+	defer r.mut.Unlock()
+
+	r.stopped = true
+	if r.drained {
+		return nil
+	}
+	return r.strm.Stop(timeout)
+}
+
+//------------------------------------------------------------------------------
+
+// watchFiles polls a set of files at an interval and writes to the returned
+// channel when any of them have been modified, until the stop channel is
+// closed.
+func watchFiles(paths []string, interval time.Duration, stop <-chan struct{}) <-chan struct{} {
+	type fileState struct {
+		modTime time.Time
+		size    int64
+	}
+	getStates := func() map[string]fileState {
+		states := make(map[string]fileState, len(paths))
+		for _, p := range paths {
+			if info, err := os.Stat(p); err == nil {
+				states[p] = fileState{modTime: info.ModTime(), size: info.Size()}
+			}
+		}
+		return states
+	}
+
+	changed := make(chan struct{}, 1)
+	go func() {
+		states := getStates()
+
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+			case <-stop:
+				return
+			}
+			if newStates := getStates(); !reflect.DeepEqual(states, newStates) {
+				states = newStates
+				select {
+				case changed <- struct{}{}:
+				default:
+				}
+			}
+		}
+	}()
+	return changed
+}
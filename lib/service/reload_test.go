@@ -0,0 +1,221 @@
+package service
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/Jeffail/benthos/v3/lib/input"
+	"github.com/Jeffail/benthos/v3/lib/output"
+	"github.com/Jeffail/benthos/v3/lib/stream"
+	"github.com/Jeffail/benthos/v3/lib/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testReloadStreamConf(mapping string, count int) stream.Config {
+	conf := stream.NewConfig()
+	conf.Input.Type = input.TypeGenerate
+	conf.Input.Generate.Mapping = mapping
+	conf.Input.Generate.Interval = "1ms"
+	conf.Input.Generate.Count = count
+	conf.Output.Type = output.TypeDrop
+	return conf
+}
+
+type testStreamBuilder struct {
+	mut    sync.Mutex
+	built  []stream.Config
+	closed []stream.Config
+	err    error
+}
+
+func (b *testStreamBuilder) build(conf stream.Config, onClose func()) (*stream.Type, error) {
+	b.mut.Lock()
+	defer b.mut.Unlock()
+
+	if b.err != nil {
+		return nil, b.err
+	}
+	b.built = append(b.built, conf)
+	return stream.New(conf, stream.OptOnClose(func() {
+		b.mut.Lock()
+		b.closed = append(b.closed, conf)
+		b.mut.Unlock()
+		onClose()
+	}))
+}
+
+func (b *testStreamBuilder) setErr(err error) {
+	b.mut.Lock()
+	b.err = err
+	b.mut.Unlock()
+}
+
+func (b *testStreamBuilder) counts() (built, closed int) {
+	b.mut.Lock()
+	defer b.mut.Unlock()
+	return len(b.built), len(b.closed)
+}
+
+func assertCounts(t *testing.T, b *testStreamBuilder, built, closed int) {
+	t.Helper()
+	assert.Eventually(t, func() bool {
+		bb, bc := b.counts()
+		return bb == built && bc == closed
+	}, time.Second*5, time.Millisecond*10)
+}
+
+func assertStreamOpen(t *testing.T, r *reloadableStream) {
+	t.Helper()
+	select {
+	case <-r.ClosedChan():
+		t.Fatal("stream should not be closed")
+	case <-time.After(time.Millisecond * 50):
+	}
+}
+
+func TestReloadableStreamUnchanged(t *testing.T) {
+	b := &testStreamBuilder{}
+	r, err := newReloadableStream(testReloadStreamConf(`root = "foo"`, 0), b.build)
+	require.NoError(t, err)
+	defer func() {
+		assert.NoError(t, r.Stop(time.Second))
+	}()
+
+	reloaded, err := r.Reload(testReloadStreamConf(`root = "foo"`, 0), false, time.Second)
+	require.NoError(t, err)
+	assert.False(t, reloaded)
+
+	assertCounts(t, b, 1, 0)
+}
+
+func TestReloadableStreamForced(t *testing.T) {
+	b := &testStreamBuilder{}
+	r, err := newReloadableStream(testReloadStreamConf(`root = "foo"`, 0), b.build)
+	require.NoError(t, err)
+	defer func() {
+		assert.NoError(t, r.Stop(time.Second))
+	}()
+
+	reloaded, err := r.Reload(testReloadStreamConf(`root = "foo"`, 0), true, time.Second)
+	require.NoError(t, err)
+	assert.True(t, reloaded)
+
+	assertCounts(t, b, 2, 1)
+	assertStreamOpen(t, r)
+}
+
+func TestReloadableStreamChanged(t *testing.T) {
+	b := &testStreamBuilder{}
+	r, err := newReloadableStream(testReloadStreamConf(`root = "foo"`, 0), b.build)
+	require.NoError(t, err)
+	defer func() {
+		assert.NoError(t, r.Stop(time.Second))
+	}()
+
+	newConf := testReloadStreamConf(`root = "bar"`, 0)
+	reloaded, err := r.Reload(newConf, false, time.Second)
+	require.NoError(t, err)
+	assert.True(t, reloaded)
+
+	assertCounts(t, b, 2, 1)
+	assert.Equal(t, newConf, r.conf)
+
+	// The closure of the replaced stream must not be treated as the pipeline
+	// terminating.
+	assertStreamOpen(t, r)
+}
+
+func TestReloadableStreamBuildFailure(t *testing.T) {
+	b := &testStreamBuilder{}
+	oldConf := testReloadStreamConf(`root = "foo"`, 0)
+	r, err := newReloadableStream(oldConf, b.build)
+	require.NoError(t, err)
+	defer func() {
+		assert.NoError(t, r.Stop(time.Second))
+	}()
+
+	oldStrm := r.strm
+
+	b.setErr(errors.New("nope"))
+	reloaded, err := r.Reload(testReloadStreamConf(`root = "bar"`, 0), false, time.Second)
+	require.EqualError(t, err, "nope, the previous pipeline is still running")
+	assert.False(t, reloaded)
+
+	// The previous stream is never stopped.
+	assertCounts(t, b, 1, 0)
+	assert.Equal(t, oldStrm, r.strm)
+	assert.Equal(t, oldConf, r.conf)
+	assertStreamOpen(t, r)
+
+	// And the previous stream terminating is still observed.
+	b.setErr(nil)
+	reloaded, err = r.Reload(testReloadStreamConf(`root = "baz"`, 1), false, time.Second)
+	require.NoError(t, err)
+	assert.True(t, reloaded)
+
+	select {
+	case <-r.ClosedChan():
+	case <-time.After(time.Second * 5):
+		t.Fatal("timed out waiting for the stream to terminate")
+	}
+}
+
+func TestReloadableStreamTerminates(t *testing.T) {
+	b := &testStreamBuilder{}
+	r, err := newReloadableStream(testReloadStreamConf(`root = "foo"`, 1), b.build)
+	require.NoError(t, err)
+	defer func() {
+		assert.NoError(t, r.Stop(time.Second))
+	}()
+
+	select {
+	case <-r.ClosedChan():
+	case <-time.After(time.Second * 5):
+		t.Fatal("timed out waiting for the stream to terminate")
+	}
+}
+
+func TestReloadableStreamClosed(t *testing.T) {
+	b := &testStreamBuilder{}
+	r, err := newReloadableStream(testReloadStreamConf(`root = "foo"`, 0), b.build)
+	require.NoError(t, err)
+
+	require.NoError(t, r.Drain(time.Second))
+	assertStreamOpen(t, r)
+
+	_, err = r.Reload(testReloadStreamConf(`root = "bar"`, 0), false, time.Second)
+	assert.Equal(t, types.ErrTypeClosed, err)
+
+	require.NoError(t, r.Stop(time.Second))
+	_, err = r.Reload(testReloadStreamConf(`root = "bar"`, 0), true, time.Second)
+	assert.Equal(t, types.ErrTypeClosed, err)
+}
+
+func TestWatchFiles(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "config.yaml")
+	require.NoError(t, os.WriteFile(path, []byte("foo"), 0644))
+
+	stop := make(chan struct{})
+	defer close(stop)
+
+	changed := watchFiles([]string{path}, time.Millisecond*10, stop)
+
+	select {
+	case <-changed:
+		t.Fatal("unexpected change")
+	case <-time.After(time.Millisecond * 50):
+	}
+
+	require.NoError(t, os.WriteFile(path, []byte("foo bar"), 0644))
+	select {
+	case <-changed:
+	case <-time.After(time.Second * 5):
+		t.Fatal("timed out waiting for change")
+	}
+}
@@ -7,11 +7,14 @@ import (
 	"net/http"
 	"os"
 	"os/signal"
+	"path"
+	"reflect"
 	"runtime/pprof"
 	"strings"
 	"syscall"
 	"time"
 
+	"github.com/Jeffail/benthos/v3/internal/bloblang"
 	iconfig "github.com/Jeffail/benthos/v3/internal/config"
 	"github.com/Jeffail/benthos/v3/internal/docs"
 	"github.com/Jeffail/benthos/v3/internal/filepath"
@@ -24,6 +27,7 @@ import (
 	strmmgr "github.com/Jeffail/benthos/v3/lib/stream/manager"
 	"github.com/Jeffail/benthos/v3/lib/tracer"
 	"github.com/Jeffail/benthos/v3/lib/types"
+	"github.com/Jeffail/benthos/v3/lib/util/text"
 	"gopkg.in/yaml.v3"
 )
 
@@ -94,52 +98,108 @@ func OptOnManagerInit(fn ManagerInitFunc) func() {
 
 //------------------------------------------------------------------------------
 
-func readConfig(path string, resourcesPaths, overrides []string) (lints []string) {
-	if path == "" {
-		// Iterate default config paths
-		for _, dpath := range []string{
-			"/benthos.yaml",
-			"/etc/benthos/config.yaml",
-			"/etc/benthos.yaml",
-		} {
-			if _, err := os.Stat(dpath); err == nil {
-				fmt.Fprintf(os.Stderr, "Config file not specified, reading from %v\n", dpath)
-				path = dpath
-				break
-			}
+// resolveConfigPaths returns the paths of the main config files, which when
+// not specified is the first default config path that exists.
+func resolveConfigPaths(paths []string) []string {
+	var resolved []string
+	for _, p := range paths {
+		if p != "" {
+			resolved = append(resolved, p)
+		}
+	}
+	if len(resolved) > 0 {
+		return resolved
+	}
+	// Iterate default config paths
+	for _, dpath := range []string{
+		"/benthos.yaml",
+		"/etc/benthos/config.yaml",
+		"/etc/benthos.yaml",
+	} {
+		if _, err := os.Stat(dpath); err == nil {
+			fmt.Fprintf(os.Stderr, "Config file not specified, reading from %v\n", dpath)
+			return []string{dpath}
 		}
 	}
+	return nil
+}
+
+func readConfig(paths []string, resourcesPaths, overrides []string, opts ...iconfig.OptFunc) (lints []string) {
+	paths = resolveConfigPaths(paths)
 
 	var err error
-	if lints, err = iconfig.NewReader(path, resourcesPaths, iconfig.OptAddOverrides(overrides...)).Read(&conf); err != nil {
+	if lints, err = iconfig.NewReader(paths, resourcesPaths, append(opts, iconfig.OptAddOverrides(overrides...))...).Read(&conf); err != nil {
 		fmt.Fprintf(os.Stderr, "Configuration file read error: %v\n", err)
+		newStartupReporter(paths).fail("config", "Configuration file read error", "", err)
 		os.Exit(1)
 	}
 	return
 }
 
+// redactSecrets replaces the values of secrets resolved from secret references
+// within string fields of a config node with the references themselves.
+func redactSecrets(node *yaml.Node) {
+	if node.Kind == yaml.ScalarNode && node.Tag == "!!str" {
+		node.Value = text.RedactSecrets(node.Value)
+	}
+	for _, child := range node.Content {
+		redactSecrets(child)
+	}
+}
+
 //------------------------------------------------------------------------------
 
 func cmdService(
-	confPath string,
+	confPaths []string,
 	resourcesPaths []string,
 	confOverrides []string,
+	lintRulesPath string,
 	overrideLogLevel string,
 	strict bool,
 	streamsMode bool,
 	streamsConfigs []string,
+	streamsStore string,
+	streamsResume bool,
+	watch bool,
 ) int {
 	var err error
+	confPaths = resolveConfigPaths(confPaths)
+	reporter := newStartupReporter(confPaths)
 	if resourcesPaths, err = filepath.Globs(resourcesPaths); err != nil {
 		fmt.Printf("Failed to resolve resource glob pattern: %v\n", err)
+		reporter.fail("config", "Failed to resolve resource glob pattern", "", err)
 		return 1
 	}
-	lints := readConfig(confPath, resourcesPaths, confOverrides)
+	// Mappings of a single config file resolve relative paths from its
+	// directory, whereas streams may be spread across any number of files.
+	bloblEnv := bloblang.GlobalEnvironment()
+	if !streamsMode && len(confPaths) == 1 {
+		bloblEnv = bloblEnv.WithMappingDir(path.Dir(confPaths[0]))
+	}
+	readOpts := []iconfig.OptFunc{iconfig.OptSetBloblangEnvironment(bloblEnv)}
+	if lintRulesPath != "" {
+		rules, err := config.ReadLintRules(lintRulesPath)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Lint rules read error: %v\n", err)
+			reporter.fail("lint_rules", "Lint rules read error", "", err)
+			return 1
+		}
+		readOpts = append(readOpts, iconfig.OptSetLintRules(rules))
+	}
+	lints := readConfig(confPaths, resourcesPaths, confOverrides, readOpts...)
 	if strict && len(lints) > 0 {
 		for _, lint := range lints {
 			fmt.Fprintln(os.Stderr, lint)
 		}
 		fmt.Println("Shutting down due to linter errors, to prevent shutdown run Benthos with --chilled")
+		reporter.failLints(lints)
+		return 1
+	}
+
+	confHash, err := configHash()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to hash config: %v\n", err)
+		reporter.fail("config", "Failed to hash config", "", err)
 		return 1
 	}
 
@@ -159,6 +219,7 @@ func cmdService(
 	}
 	if err != nil {
 		fmt.Printf("Failed to create logger: %v\n", err)
+		reporter.fail("logger", "Failed to create logger", "logger", err)
 		return 1
 	}
 
@@ -187,6 +248,7 @@ func cmdService(
 	var trac tracer.Type
 	if trac, err = tracer.New(conf.Tracer); err != nil {
 		logger.Errorf("Failed to initialise tracer: %v\n", err)
+		reporter.fail("tracer", "Failed to initialise tracer", "tracer", err)
 		return 1
 	}
 	defer trac.Close()
@@ -197,30 +259,54 @@ func cmdService(
 	if err == nil {
 		err = config.Spec().SanitiseYAML(&sanitNode, docs.SanitiseConfig{
 			RemoveTypeField: true,
+			ScrubSecrets:    true,
 		})
 	}
 	if err != nil {
 		logger.Warnf("Failed to generate sanitised config: %v\n", err)
 	}
+	redactSecrets(&sanitNode)
 	var httpServer *api.Type
-	if httpServer, err = api.New(Version, DateBuilt, conf.HTTP, sanitNode, logger, stats, apiOpts...); err != nil {
+	if httpServer, err = api.New(Version, DateBuilt, conf.HTTP, sanitNode, logger, stats, append(apiOpts, api.OptWithVersionInfo(versionInfo(confHash)))...); err != nil {
 		logger.Errorf("Failed to initialise API: %v\n", err)
+		reporter.fail("http", "Failed to initialise API", "http", err)
 		return 1
 	}
 
 	// Create resource manager.
-	manager, err := manager.NewV2(conf.ResourceConfig, httpServer, logger, stats)
+	manager, err := manager.NewV2(conf.ResourceConfig, httpServer, logger, stats, manager.OptSetBloblangEnvironment(bloblEnv))
 	if err != nil {
 		logger.Errorf("Failed to create resource: %v\n", err)
+		reporter.fail("resources", "Failed to create resource", "", err)
 		return 1
 	}
 	if err = onManagerInit(manager, logger, stats); err != nil {
 		logger.Errorf("Failed to initialise manager: %v\n", err)
+		reporter.fail("resources", "Failed to initialise manager", "", err)
+		return 1
+	}
+
+	var exitTimeout time.Duration
+	if tout := conf.SystemCloseTimeout; len(tout) > 0 {
+		var err error
+		if exitTimeout, err = time.ParseDuration(tout); err != nil {
+			logger.Errorf("Failed to parse shutdown timeout period string: %v\n", err)
+			reporter.fail("config", "Failed to parse shutdown timeout period string", "shutdown_timeout", err)
+			return 1
+		}
+	}
+
+	readyGracePeriod, liveGracePeriod, err := conf.HTTP.Probes.GracePeriods()
+	if err != nil {
+		logger.Errorf("Failed to parse probes config: %v\n", err)
+		reporter.fail("config", "Failed to parse probes config", "http.probes", err)
 		return 1
 	}
 
 	var dataStream stoppableStreams
-	dataStreamClosedChan := make(chan struct{})
+	var dataStreamClosedChan <-chan struct{}
+	var reloadStream func()
+	var isReady, isAlive func() bool
 
 	strmAPITimeout := 5 * time.Second
 	if cTout := conf.HTTP.ReadTimeout; cTout != "" {
@@ -231,18 +317,37 @@ func cmdService(
 
 	// Create data streams.
 	if streamsMode {
-		streamMgr := strmmgr.New(
+		streamMgrOpts := []func(*strmmgr.Type){
 			strmmgr.OptSetAPITimeout(strmAPITimeout),
+			strmmgr.OptSetAPIAuth(httpServer.Auth()),
 			strmmgr.OptSetLogger(logger),
 			strmmgr.OptSetManager(manager),
 			strmmgr.OptSetStats(stats),
-		)
+			strmmgr.OptSetProbeGracePeriods(readyGracePeriod, liveGracePeriod),
+		}
+
+		var store strmmgr.Store
+		var storedConfs map[string]stream.Config
+		if streamsStore != "" {
+			if store, err = strmmgr.NewStore(streamsStore); err == nil {
+				storedConfs, err = store.Load()
+			}
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Failed to open stream store: %v\n", err)
+				reporter.fail("streams", "Failed to open stream store", "", err)
+				return 1
+			}
+			streamMgrOpts = append(streamMgrOpts, strmmgr.OptSetStore(store))
+		}
+
+		streamMgr := strmmgr.New(streamMgrOpts...)
 		streamConfs := map[string]stream.Config{}
 		var streamLints []string
 		for _, path := range streamsConfigs {
 			lints, err := strmmgr.LoadStreamConfigsFromPath(path, testSuffix, streamConfs)
 			if err != nil {
 				fmt.Fprintf(os.Stderr, "Failed to load stream configs: %v\n", err)
+				reporter.fail("streams", "Failed to load stream configs", "", err)
 				return 1
 			}
 			streamLints = append(streamLints, lints...)
@@ -253,6 +358,7 @@ func cmdService(
 				fmt.Fprintln(os.Stderr, lint)
 			}
 			fmt.Println("Shutting down due to linter errors, to prevent shutdown run Benthos with --chilled")
+			reporter.failLints(streamLints)
 			return 1
 		} else if len(streamLints) > 0 {
 			lintlog := logger.NewModule(".linter")
@@ -261,27 +367,117 @@ func cmdService(
 			}
 		}
 
+		if streamsResume {
+			// Persisted streams reflect changes made via the API since they were
+			// loaded, and therefore replace any loaded from paths.
+			for id, conf := range storedConfs {
+				streamConfs[id] = conf
+			}
+			logger.Infof("Resuming %v streams from store.\n", len(storedConfs))
+		} else {
+			for id := range storedConfs {
+				if err = store.Delete(id); err != nil {
+					fmt.Fprintf(os.Stderr, "Failed to remove stream from store: %v\n", err)
+					reporter.fail("streams", "Failed to remove stream from store", "", err)
+					return 1
+				}
+			}
+		}
+
 		dataStream = streamMgr
+		isAlive = streamMgr.IsAlive
 		for id, conf := range streamConfs {
 			if err = streamMgr.Create(id, conf); err != nil {
 				logger.Errorf("Failed to create stream (%v): %v\n", id, err)
+				reporter.fail("stream", fmt.Sprintf("Failed to create stream (%v)", id), id, err)
 				return 1
 			}
 		}
+		if watch {
+			fileConfs := map[string]stream.Config{}
+			for id, conf := range streamConfs {
+				if _, stored := storedConfs[id]; !stored || !streamsResume {
+					fileConfs[id] = conf
+				}
+			}
+			watcher := strmmgr.NewWatcher(streamMgr, streamsConfigs, testSuffix, strict, fileConfs, logger)
+			stopWatching := make(chan struct{})
+			defer close(stopWatching)
+			go watcher.Run(time.Second, stopWatching)
+			logger.Infof("Watching %v paths for changes to stream configs.\n", len(streamsConfigs))
+		}
 		logger.Infoln("Launching benthos in streams mode, use CTRL+C to close.")
 	} else {
-		if dataStream, err = stream.New(
-			conf.Config,
-			stream.OptSetLogger(logger),
-			stream.OptSetStats(stats),
-			stream.OptSetManager(manager),
-			stream.OptOnClose(func() {
-				close(dataStreamClosedChan)
-			}),
-		); err != nil {
+		strm, err := newReloadableStream(conf.Config, func(sConf stream.Config, onClose func()) (*stream.Type, error) {
+			return stream.New(
+				sConf,
+				stream.OptSetLogger(logger),
+				stream.OptSetStats(stats),
+				stream.OptSetManager(manager),
+				stream.OptSetProbeGracePeriods(readyGracePeriod, liveGracePeriod),
+				stream.OptOnClose(onClose),
+			)
+		})
+		if err != nil {
 			logger.Errorf("Service closing due to: %v\n", err)
+			reporter.fail("stream", "Failed to create stream", "", err)
 			return 1
 		}
+		dataStream = strm
+		dataStreamClosedChan = strm.ClosedChan()
+		isReady = strm.IsReady
+		isAlive = strm.IsAlive
+
+		reloadStream = func() {
+			newConf := config.New()
+			lints, err := iconfig.NewReader(confPaths, resourcesPaths, append(readOpts, iconfig.OptAddOverrides(confOverrides...))...).Read(&newConf)
+			if err != nil {
+				logger.Errorf("Failed to reload config, the running pipeline is unchanged: %v\n", err)
+				return
+			}
+
+			// Reading the config sets its constants, which are resolved when
+			// the new pipeline is created, and therefore changing them also
+			// requires the pipeline to be replaced. They are restored if the
+			// pipeline is not replaced.
+			reloaded := false
+			defer func() {
+				if reloaded {
+					return
+				}
+				if err := bloblang.SetConstants(conf.Constants); err != nil {
+					logger.Errorf("Failed to restore config constants: %v\n", err)
+				}
+			}()
+
+			if len(lints) > 0 {
+				lintlog := logger.NewModule(".linter")
+				for _, lint := range lints {
+					lintlog.Infoln(lint)
+				}
+				if strict {
+					logger.Errorln("Reloaded config contains linting errors, the running pipeline is unchanged. To ignore linting errors run Benthos with --chilled")
+					return
+				}
+			}
+			if !reflect.DeepEqual(newConf.ResourceConfig, conf.ResourceConfig) {
+				logger.Warnln("Changes to resources are not applied until Benthos is restarted, only the input, buffer, pipeline and output are reloaded.")
+			}
+			constantsChanged := !reflect.DeepEqual(newConf.Constants, conf.Constants)
+			if reloaded, err = strm.Reload(newConf.Config, constantsChanged, exitTimeout); reloaded {
+				conf.Constants = newConf.Constants
+			}
+			if err != nil {
+				logger.Errorf("Failed to reload pipeline: %v\n", err)
+				return
+			}
+			if reloaded {
+				logger.Infoln("Pipeline reloaded with the updated config.")
+			} else {
+				logger.Infoln("Config of the pipeline is unchanged, skipping reload.")
+			}
+		}
+		registerPipelineEndpoints(httpServer, strm, logger, exitTimeout)
 		logger.Infoln("Launching a benthos instance, use CTRL+C to close.")
 	}
 
@@ -295,15 +491,6 @@ func cmdService(
 		close(httpServerClosedChan)
 	}()
 
-	var exitTimeout time.Duration
-	if tout := conf.SystemCloseTimeout; len(tout) > 0 {
-		var err error
-		if exitTimeout, err = time.ParseDuration(tout); err != nil {
-			logger.Errorf("Failed to parse shutdown timeout period string: %v\n", err)
-			return 1
-		}
-	}
-
 	// Defer clean up.
 	defer func() {
 		go func() {
@@ -343,18 +530,107 @@ func cmdService(
 	sigChan := make(chan os.Signal, 1)
 	signal.Notify(sigChan, os.Interrupt, syscall.SIGTERM)
 
+	var hupChan chan os.Signal
+	var changedChan <-chan struct{}
+	if reloadStream != nil {
+		hupChan = make(chan os.Signal, 1)
+		signal.Notify(hupChan, syscall.SIGHUP)
+
+		if watch && len(confPaths) > 0 {
+			// Directories are watched along with the files within them so
+			// that added and removed files are also detected.
+			watchPaths, err := iconfig.NewReader(confPaths, nil).MainPaths()
+			if err != nil {
+				logger.Errorf("Failed to resolve config paths to watch: %v\n", err)
+				reporter.fail("config", "Failed to resolve config paths to watch", "", err)
+				return 1
+			}
+			for _, p := range confPaths {
+				if info, err := os.Stat(p); err == nil && info.IsDir() {
+					watchPaths = append(watchPaths, p)
+				}
+			}
+			stopWatching := make(chan struct{})
+			defer close(stopWatching)
+			changedChan = watchFiles(append(watchPaths, resourcesPaths...), time.Second, stopWatching)
+			logger.Infof("Watching %v for changes.\n", strings.Join(confPaths, ", "))
+		}
+	}
+
+	// When run by systemd the service is reported as ready once the pipeline
+	// is connected, and watchdog notifications are sent from the loop below
+	// whilst the pipeline is alive so that a stalled service is restarted.
+	notifier := newSystemdNotifier()
+	var readyTicker <-chan time.Time
+	var readyChan, watchdogChan <-chan time.Time
+	if notifier.enabled() {
+		ticker := time.NewTicker(100 * time.Millisecond)
+		defer ticker.Stop()
+		readyTicker = ticker.C
+		readyChan = readyTicker
+
+		watchdogInterval, err := notifier.watchdogInterval()
+		if err != nil {
+			logger.Errorf("Failed to configure systemd watchdog: %v\n", err)
+		} else if watchdogInterval > 0 {
+			watchdogTicker := time.NewTicker(watchdogInterval)
+			defer watchdogTicker.Stop()
+			watchdogChan = watchdogTicker.C
+			logger.Debugf("Sending systemd watchdog notifications every %v.\n", watchdogInterval)
+		}
+		defer notifier.stopping()
+	}
+	notifyReady := func() {
+		if isReady != nil && !isReady() {
+			return
+		}
+		if err := notifier.ready(); err != nil {
+			logger.Errorf("Failed to notify systemd of readiness: %v\n", err)
+		}
+		readyChan = nil
+	}
+	reload := func() {
+		if err := notifier.reloading(); err != nil {
+			logger.Errorf("Failed to notify systemd of reload: %v\n", err)
+		}
+		reloadStream()
+		if notifier.enabled() {
+			readyChan = readyTicker
+			notifyReady()
+		}
+	}
+
 	// Wait for termination signal
-	select {
-	case <-sigChan:
-		logger.Infoln("Received SIGTERM, the service is closing.")
-	case <-dataStreamClosedChan:
-		logger.Infoln("Pipeline has terminated. Shutting down the service.")
-	case <-httpServerClosedChan:
-		logger.Infoln("HTTP Server has terminated. Shutting down the service.")
-	case <-optContext.Done():
-		logger.Infoln("Run context was cancelled. Shutting down the service.")
-	}
-	return 0
+	for {
+		select {
+		case <-readyChan:
+			notifyReady()
+		case <-watchdogChan:
+			if sent, err := notifier.watchdogIfAlive(isAlive); err != nil {
+				logger.Errorf("Failed to send systemd watchdog notification: %v\n", err)
+			} else if !sent {
+				logger.Warnln("Pipeline is not alive, withholding systemd watchdog notification.")
+			}
+		case <-hupChan:
+			logger.Infoln("Received SIGHUP, reloading config.")
+			reload()
+		case <-changedChan:
+			logger.Infoln("Config file changes detected, reloading config.")
+			reload()
+		case <-sigChan:
+			logger.Infoln("Received SIGTERM, the service is closing.")
+			return 0
+		case <-dataStreamClosedChan:
+			logger.Infoln("Pipeline has terminated. Shutting down the service.")
+			return 0
+		case <-httpServerClosedChan:
+			logger.Infoln("HTTP Server has terminated. Shutting down the service.")
+			return 0
+		case <-optContext.Done():
+			logger.Infoln("Run context was cancelled. Shutting down the service.")
+			return 0
+		}
+	}
 }
 
 //------------------------------------------------------------------------------
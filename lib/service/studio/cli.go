@@ -0,0 +1,66 @@
+package studio
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/urfave/cli/v2"
+)
+
+// CliCommand is a cli.Command definition for running the studio.
+func CliCommand() *cli.Command {
+	return &cli.Command{
+		Name:  "studio",
+		Usage: "EXPERIMENTAL: Run a web app for editing a config",
+		Description: `
+   Runs a web server that hosts an app for editing a config, where the config
+   is linted as it changes and its components are visualised as a topology.
+   The app also provides documentation of each component within this build of
+   Benthos, including any plugins, and an editor for executing Bloblang
+   mappings against sample payloads.
+
+   benthos -c ./config.yaml studio --local
+
+   Changes are written back to the config file when saved within the app, and
+   if the file does not yet exist it is created. Custom lint rules can be
+   provided with the --lint-rules flag:
+
+   benthos --lint-rules ./rules.yaml -c ./config.yaml studio --local
+
+   The studio currently only runs locally and must be started with the --local
+   flag. Requests addressed to hosts other than the bind address, or its
+   loopback equivalents, are rejected, and changes are only saved when sent
+   from the app itself.`[4:],
+		Flags: []cli.Flag{
+			&cli.BoolFlag{
+				Name:  "local",
+				Value: false,
+				Usage: "Run the studio locally, which is currently the only supported mode.",
+			},
+			&cli.StringFlag{
+				Name:  "host",
+				Value: "localhost",
+				Usage: "The host to bind to.",
+			},
+			&cli.StringFlag{
+				Name:    "port",
+				Value:   "4196",
+				Aliases: []string{"p"},
+				Usage:   "The port to bind to.",
+			},
+			&cli.BoolFlag{
+				Name:    "no-open",
+				Value:   false,
+				Aliases: []string{"n"},
+				Usage:   "Do not open the app in the browser automatically.",
+			},
+		},
+		Action: func(c *cli.Context) error {
+			if err := runStudio(c); err != nil {
+				fmt.Fprintf(os.Stderr, "Studio error: %v\n", err)
+				os.Exit(1)
+			}
+			return nil
+		},
+	}
+}
@@ -0,0 +1,474 @@
+package studio
+
+import (
+	"context"
+	_ "embed" // Required for embedding the studio page.
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"mime"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"os/exec"
+	"os/signal"
+	"runtime"
+	"strings"
+	"sync"
+	"syscall"
+
+	"github.com/Jeffail/benthos/v3/internal/bloblang"
+	"github.com/Jeffail/benthos/v3/internal/bloblang/parser"
+	"github.com/Jeffail/benthos/v3/internal/bundle"
+	"github.com/Jeffail/benthos/v3/internal/docs"
+	"github.com/Jeffail/benthos/v3/lib/config"
+	"github.com/Jeffail/benthos/v3/lib/message"
+	"github.com/Jeffail/benthos/v3/lib/message/metadata"
+	"github.com/Jeffail/gabs/v2"
+	"github.com/urfave/cli/v2"
+	"gopkg.in/yaml.v3"
+)
+
+//go:embed resources/studio.html
+var studioPage []byte
+
+const defaultConfig = `input:
+  stdin: {}
+
+pipeline:
+  processors:
+    - bloblang: root = this
+
+output:
+  stdout: {}
+`
+
+// componentDocs are the documented component types that can be browsed within
+// the studio.
+var componentDocs = map[string]func() []docs.ComponentSpec{
+	"inputs":      bundle.AllInputs.Docs,
+	"processors":  bundle.AllProcessors.Docs,
+	"outputs":     bundle.AllOutputs.Docs,
+	"caches":      bundle.AllCaches.Docs,
+	"rate-limits": bundle.AllRateLimits.Docs,
+	"buffers":     bundle.AllBuffers.Docs,
+	"metrics":     bundle.AllMetrics.Docs,
+	"tracers":     bundle.AllTracers.Docs,
+}
+
+func openBrowserAt(url string) {
+	switch runtime.GOOS {
+	case "linux":
+		_ = exec.Command("xdg-open", url).Start()
+	case "windows":
+		_ = exec.Command("rundll32", "url.dll,FileProtocolHandler", url).Start()
+	case "darwin":
+		_ = exec.Command("open", url).Start()
+	}
+}
+
+func writeJSON(w http.ResponseWriter, v interface{}) {
+	resBytes, err := json.Marshal(v)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadGateway)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.Write(resBytes)
+}
+
+//------------------------------------------------------------------------------
+
+// configFile holds the config being edited, which is written back to its
+// source file when saved.
+type configFile struct {
+	mut  sync.Mutex
+	path string
+	body string
+}
+
+func newConfigFile(path string) (*configFile, error) {
+	f := &configFile{path: path, body: defaultConfig}
+	if path == "" {
+		return f, nil
+	}
+	confBytes, err := ioutil.ReadFile(path)
+	if err != nil {
+		if !errors.Is(err, os.ErrNotExist) {
+			return nil, err
+		}
+		return f, nil
+	}
+	f.body = string(confBytes)
+	return f, nil
+}
+
+func (f *configFile) get() (path, body string) {
+	f.mut.Lock()
+	defer f.mut.Unlock()
+	return f.path, f.body
+}
+
+func (f *configFile) save(body string) error {
+	f.mut.Lock()
+	defer f.mut.Unlock()
+	if f.path == "" {
+		return errors.New("no config file was provided, run the studio with a config file (-c) in order to save changes")
+	}
+	if err := ioutil.WriteFile(f.path, []byte(body), 0644); err != nil {
+		return err
+	}
+	f.body = body
+	return nil
+}
+
+//------------------------------------------------------------------------------
+
+type lintResult struct {
+	Line    int    `json:"line"`
+	Column  int    `json:"column,omitempty"`
+	Level   string `json:"level"`
+	Message string `json:"message"`
+}
+
+// lintConfig lints a config along with any custom lint rules, and attempts to
+// parse it in order to catch errors that are not found by the linter.
+func lintConfig(configYAML []byte, rules *docs.LintRules) []lintResult {
+	results := []lintResult{}
+
+	var node yaml.Node
+	if err := yaml.Unmarshal(configYAML, &node); err != nil {
+		return append(results, lintResult{Level: "error", Message: err.Error()})
+	}
+
+	lints := config.Spec().LintYAML(docs.NewLintContext(), &node)
+	if rules != nil {
+		lints = append(lints, rules.LintYAML(docs.NewLintContext(), config.Spec(), &node)...)
+	}
+	for _, l := range lints {
+		level := "warning"
+		if l.Level == docs.LintError {
+			level = "error"
+		}
+		results = append(results, lintResult{
+			Line:    l.Line,
+			Column:  l.Column,
+			Level:   level,
+			Message: l.What,
+		})
+	}
+
+	conf := config.New()
+	if err := node.Decode(&conf); err != nil {
+		results = append(results, lintResult{Level: "error", Message: err.Error()})
+	}
+	return results
+}
+
+type executeError struct {
+	Type    string `json:"type"`
+	Message string `json:"message"`
+	Line    int    `json:"line,omitempty"`
+	Column  int    `json:"column,omitempty"`
+}
+
+type executeResponse struct {
+	Result   string            `json:"result"`
+	Metadata map[string]string `json:"metadata,omitempty"`
+	Error    *executeError     `json:"error,omitempty"`
+}
+
+// executeMapping executes a Bloblang mapping against a sample payload.
+func executeMapping(mapping, input string, meta map[string]string) executeResponse {
+	exec, err := bloblang.NewMapping("", mapping)
+	if err != nil {
+		res := executeResponse{Error: &executeError{Type: "parse", Message: err.Error()}}
+		if perr, ok := err.(*parser.Error); ok {
+			res.Error.Message = perr.ErrorAtPosition([]rune(mapping))
+			res.Error.Line, res.Error.Column = parser.LineAndColOf([]rune(mapping), perr.Input)
+		}
+		return res
+	}
+
+	msg := message.New([][]byte{[]byte(input)})
+	msg.Get(0).SetMetadata(metadata.New(meta))
+
+	part, err := exec.MapPart(0, msg)
+	if err != nil {
+		return executeResponse{Error: &executeError{Type: "mapping", Message: err.Error()}}
+	}
+
+	res := executeResponse{Metadata: map[string]string{}}
+	if part == nil {
+		res.Result = "<Message deleted>"
+		return res
+	}
+	res.Result = string(part.Get())
+	_ = part.Metadata().Iter(func(k, v string) error {
+		res.Metadata[k] = v
+		return nil
+	})
+	return res
+}
+
+type componentSummary struct {
+	Name    string `json:"name"`
+	Summary string `json:"summary"`
+	Status  string `json:"status"`
+}
+
+type componentField struct {
+	Path        string `json:"path"`
+	Type        string `json:"type"`
+	Kind        string `json:"kind"`
+	Description string `json:"description"`
+	Default     string `json:"default,omitempty"`
+	Advanced    bool   `json:"advanced"`
+}
+
+type componentDetails struct {
+	componentSummary
+	Description string           `json:"description"`
+	Fields      []componentField `json:"fields"`
+}
+
+//------------------------------------------------------------------------------
+
+func runStudio(c *cli.Context) error {
+	if !c.Bool("local") {
+		return errors.New("the studio currently only supports local mode, run it with the --local flag")
+	}
+
+	var confPath string
+	if paths := c.StringSlice("config"); len(paths) > 0 {
+		confPath = paths[0]
+	}
+	confFile, err := newConfigFile(confPath)
+	if err != nil {
+		return fmt.Errorf("failed to read config: %w", err)
+	}
+
+	var rules *docs.LintRules
+	if rulesPath := c.String("lint-rules"); rulesPath != "" {
+		if rules, err = config.ReadLintRules(rulesPath); err != nil {
+			return fmt.Errorf("failed to read lint rules: %w", err)
+		}
+	}
+
+	host, port := c.String("host"), c.String("port")
+	bindAddress := host + ":" + port
+
+	if !c.Bool("no-open") {
+		openBrowserAt("http://localhost:" + port)
+	}
+
+	log.Printf("Serving studio at: http://%v\n", bindAddress)
+
+	server := http.Server{
+		Addr:    bindAddress,
+		Handler: newHandler(confFile, rules, studioHosts(host, port)),
+	}
+
+	go func() {
+		sigChan := make(chan os.Signal, 1)
+		signal.Notify(sigChan, os.Interrupt, syscall.SIGTERM)
+
+		// Wait for termination signal
+		<-sigChan
+		_ = server.Shutdown(context.Background())
+	}()
+
+	if err := server.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+		return fmt.Errorf("failed to listen and serve: %w", err)
+	}
+	return nil
+}
+
+// studioHosts returns the values of the Host header accepted by the studio for
+// a bind address. Requests addressed to other hosts are rejected, which
+// prevents the studio from being reached through DNS rebinding.
+func studioHosts(host, port string) map[string]struct{} {
+	hosts := map[string]struct{}{
+		net.JoinHostPort(host, port): {},
+	}
+	switch host {
+	case "", "localhost", "0.0.0.0", "::", "127.0.0.1", "::1":
+		for _, h := range []string{"localhost", "127.0.0.1", "::1"} {
+			hosts[net.JoinHostPort(h, port)] = struct{}{}
+		}
+	}
+	return hosts
+}
+
+// sameOrigin returns whether the Origin header of a request, when present,
+// refers to a host accepted by the studio.
+func sameOrigin(r *http.Request, hosts map[string]struct{}) bool {
+	origin := r.Header.Get("Origin")
+	if origin == "" {
+		return true
+	}
+	u, err := url.Parse(origin)
+	if err != nil || u.Scheme != "http" {
+		return false
+	}
+	_, exists := hosts[u.Host]
+	return exists
+}
+
+func newHandler(confFile *configFile, rules *docs.LintRules, hosts map[string]struct{}) http.Handler {
+	mux := http.NewServeMux()
+
+	mux.HandleFunc("/config", func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case "GET":
+			path, body := confFile.get()
+			writeJSON(w, map[string]string{
+				"path":   path,
+				"config": body,
+			})
+		case "POST":
+			// Saving writes to the file system, and therefore requests must
+			// originate from the studio itself. Requiring a JSON body also
+			// means that cross origin requests from browsers are preflighted.
+			if !sameOrigin(r, hosts) {
+				http.Error(w, "Origin not allowed", http.StatusForbidden)
+				return
+			}
+			if mediaType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type")); mediaType != "application/json" {
+				http.Error(w, "Content-Type must be application/json", http.StatusUnsupportedMediaType)
+				return
+			}
+			req := struct {
+				Config string `json:"config"`
+			}{}
+			if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+			if err := confFile.save(req.Config); err != nil {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+			w.Write([]byte("OK"))
+		default:
+			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		}
+	})
+
+	mux.HandleFunc("/lint", func(w http.ResponseWriter, r *http.Request) {
+		reqBytes, err := ioutil.ReadAll(r.Body)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		writeJSON(w, lintConfig(reqBytes, rules))
+	})
+
+	mux.HandleFunc("/topology", func(w http.ResponseWriter, r *http.Request) {
+		reqBytes, err := ioutil.ReadAll(r.Body)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		sections, err := topologyOf(reqBytes)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		writeJSON(w, sections)
+	})
+
+	mux.HandleFunc("/execute", func(w http.ResponseWriter, r *http.Request) {
+		req := struct {
+			Mapping  string            `json:"mapping"`
+			Input    string            `json:"input"`
+			Metadata map[string]string `json:"metadata"`
+		}{}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		writeJSON(w, executeMapping(req.Mapping, req.Input, req.Metadata))
+	})
+
+	mux.HandleFunc("/components", func(w http.ResponseWriter, r *http.Request) {
+		summaries := map[string][]componentSummary{}
+		for t, docsFn := range componentDocs {
+			summaries[t] = []componentSummary{}
+			for _, spec := range docsFn() {
+				if spec.Status == docs.StatusDeprecated {
+					continue
+				}
+				summaries[t] = append(summaries[t], componentSummary{
+					Name:    spec.Name,
+					Summary: strings.TrimSpace(spec.Summary),
+					Status:  string(spec.Status),
+				})
+			}
+		}
+		writeJSON(w, summaries)
+	})
+
+	mux.HandleFunc("/components/", func(w http.ResponseWriter, r *http.Request) {
+		typeAndName := strings.Split(strings.TrimPrefix(r.URL.Path, "/components/"), "/")
+		if len(typeAndName) != 2 {
+			http.Error(w, "Expected a path of the form /components/{type}/{name}", http.StatusBadRequest)
+			return
+		}
+		docsFn, exists := componentDocs[typeAndName[0]]
+		if !exists {
+			http.Error(w, "Component type not recognised", http.StatusNotFound)
+			return
+		}
+		for _, spec := range docsFn() {
+			if spec.Name != typeAndName[1] {
+				continue
+			}
+			details := componentDetails{
+				componentSummary: componentSummary{
+					Name:    spec.Name,
+					Summary: strings.TrimSpace(spec.Summary),
+					Status:  string(spec.Status),
+				},
+				Description: strings.TrimSpace(spec.Description),
+				Fields:      []componentField{},
+			}
+			for _, f := range spec.Config.FlattenChildrenForDocs() {
+				field := componentField{
+					Path:        f.FullName,
+					Type:        string(f.Spec.Type),
+					Kind:        string(f.Spec.Kind),
+					Description: f.Spec.Description,
+					Advanced:    f.Spec.IsAdvanced,
+				}
+				if f.Spec.Default != nil {
+					field.Default = gabs.Wrap(*f.Spec.Default).String()
+				}
+				details.Fields = append(details.Fields, field)
+			}
+			writeJSON(w, details)
+			return
+		}
+		http.Error(w, "Component not found", http.StatusNotFound)
+	})
+
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/" {
+			http.NotFound(w, r)
+			return
+		}
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		w.Write(studioPage)
+	})
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if _, exists := hosts[r.Host]; !exists {
+			http.Error(w, "Host not allowed", http.StatusForbidden)
+			return
+		}
+		mux.ServeHTTP(w, r)
+	})
+}
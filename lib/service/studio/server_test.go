@@ -0,0 +1,227 @@
+package studio
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStudioHosts(t *testing.T) {
+	tests := []struct {
+		host     string
+		expected []string
+	}{
+		{
+			host:     "localhost",
+			expected: []string{"localhost:4196", "127.0.0.1:4196", "[::1]:4196"},
+		},
+		{
+			host:     "0.0.0.0",
+			expected: []string{"0.0.0.0:4196", "localhost:4196", "127.0.0.1:4196", "[::1]:4196"},
+		},
+		{
+			host:     "studio.example.com",
+			expected: []string{"studio.example.com:4196"},
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.host, func(t *testing.T) {
+			expected := map[string]struct{}{}
+			for _, h := range test.expected {
+				expected[h] = struct{}{}
+			}
+			assert.Equal(t, expected, studioHosts(test.host, "4196"))
+		})
+	}
+}
+
+func TestHandlerSaveConfig(t *testing.T) {
+	confPath := filepath.Join(t.TempDir(), "config.yaml")
+	confFile, err := newConfigFile(confPath)
+	require.NoError(t, err)
+
+	handler := newHandler(confFile, nil, studioHosts("localhost", "4196"))
+
+	tests := []struct {
+		name        string
+		host        string
+		origin      string
+		contentType string
+		body        string
+		status      int
+		saved       string
+	}{
+		{
+			name:        "same origin",
+			host:        "localhost:4196",
+			origin:      "http://localhost:4196",
+			contentType: "application/json",
+			body:        `{"config":"input:\n  stdin: {}\n"}`,
+			status:      http.StatusOK,
+			saved:       "input:\n  stdin: {}\n",
+		},
+		{
+			name:        "no origin with charset",
+			host:        "127.0.0.1:4196",
+			contentType: "application/json; charset=utf-8",
+			body:        `{"config":"output:\n  stdout: {}\n"}`,
+			status:      http.StatusOK,
+			saved:       "output:\n  stdout: {}\n",
+		},
+		{
+			name:        "rebound host",
+			host:        "evil.example.com:4196",
+			origin:      "http://evil.example.com:4196",
+			contentType: "application/json",
+			body:        `{"config":"nope"}`,
+			status:      http.StatusForbidden,
+		},
+		{
+			name:        "cross origin",
+			host:        "localhost:4196",
+			origin:      "http://evil.example.com",
+			contentType: "application/json",
+			body:        `{"config":"nope"}`,
+			status:      http.StatusForbidden,
+		},
+		{
+			name:        "cross origin different port",
+			host:        "localhost:4196",
+			origin:      "http://localhost:8080",
+			contentType: "application/json",
+			body:        `{"config":"nope"}`,
+			status:      http.StatusForbidden,
+		},
+		{
+			name:        "form content type",
+			host:        "localhost:4196",
+			origin:      "http://localhost:4196",
+			contentType: "text/plain",
+			body:        `{"config":"nope"}`,
+			status:      http.StatusUnsupportedMediaType,
+		},
+		{
+			name:   "no content type",
+			host:   "localhost:4196",
+			body:   `{"config":"nope"}`,
+			status: http.StatusUnsupportedMediaType,
+		},
+		{
+			name:        "bad json",
+			host:        "localhost:4196",
+			contentType: "application/json",
+			body:        `not json`,
+			status:      http.StatusBadRequest,
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			_, before := confFile.get()
+
+			req := httptest.NewRequest("POST", "/config", strings.NewReader(test.body))
+			req.Host = test.host
+			if test.origin != "" {
+				req.Header.Set("Origin", test.origin)
+			}
+			if test.contentType != "" {
+				req.Header.Set("Content-Type", test.contentType)
+			}
+
+			res := httptest.NewRecorder()
+			handler.ServeHTTP(res, req)
+			assert.Equal(t, test.status, res.Code, res.Body.String())
+
+			_, body := confFile.get()
+			if test.status != http.StatusOK {
+				assert.Equal(t, before, body)
+				return
+			}
+
+			assert.Equal(t, test.saved, body)
+			fileBytes, err := ioutil.ReadFile(confPath)
+			require.NoError(t, err)
+			assert.Equal(t, test.saved, string(fileBytes))
+		})
+	}
+}
+
+func TestHandlerHost(t *testing.T) {
+	confFile, err := newConfigFile("")
+	require.NoError(t, err)
+
+	handler := newHandler(confFile, nil, studioHosts("localhost", "4196"))
+
+	for _, path := range []string{"/", "/config", "/components"} {
+		req := httptest.NewRequest("GET", path, nil)
+		req.Host = "localhost:4196"
+		res := httptest.NewRecorder()
+		handler.ServeHTTP(res, req)
+		assert.Equal(t, http.StatusOK, res.Code, path)
+
+		req = httptest.NewRequest("GET", path, nil)
+		req.Host = "evil.example.com"
+		res = httptest.NewRecorder()
+		handler.ServeHTTP(res, req)
+		assert.Equal(t, http.StatusForbidden, res.Code, path)
+	}
+}
+
+func TestHandlerGetConfig(t *testing.T) {
+	confFile, err := newConfigFile("")
+	require.NoError(t, err)
+
+	handler := newHandler(confFile, nil, studioHosts("localhost", "4196"))
+
+	req := httptest.NewRequest("GET", "/config", nil)
+	req.Host = "localhost:4196"
+	res := httptest.NewRecorder()
+	handler.ServeHTTP(res, req)
+
+	assert.Equal(t, http.StatusOK, res.Code)
+	assert.Equal(t, "application/json", res.Header().Get("Content-Type"))
+	confBytes, err := json.Marshal(defaultConfig)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"path":"","config":`+string(confBytes)+`}`, res.Body.String())
+}
+
+func TestHandlerSaveWithoutPath(t *testing.T) {
+	confFile, err := newConfigFile("")
+	require.NoError(t, err)
+
+	handler := newHandler(confFile, nil, studioHosts("localhost", "4196"))
+
+	req := httptest.NewRequest("POST", "/config", strings.NewReader(`{"config":"foo"}`))
+	req.Host = "localhost:4196"
+	req.Header.Set("Content-Type", "application/json")
+	res := httptest.NewRecorder()
+	handler.ServeHTTP(res, req)
+
+	assert.Equal(t, http.StatusBadRequest, res.Code)
+	assert.Contains(t, res.Body.String(), "no config file was provided")
+}
+
+func TestHandlerExecute(t *testing.T) {
+	confFile, err := newConfigFile("")
+	require.NoError(t, err)
+
+	handler := newHandler(confFile, nil, studioHosts("localhost", "4196"))
+
+	req := httptest.NewRequest("POST", "/execute", strings.NewReader(`{"mapping":"root = content().uppercase()","input":"foo"}`))
+	req.Host = "localhost:4196"
+	res := httptest.NewRecorder()
+	handler.ServeHTTP(res, req)
+
+	assert.Equal(t, http.StatusOK, res.Code)
+	assert.JSONEq(t, `{"result":"FOO"}`, res.Body.String())
+}
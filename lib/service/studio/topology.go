@@ -0,0 +1,138 @@
+package studio
+
+import (
+	"errors"
+	"strconv"
+
+	"github.com/Jeffail/benthos/v3/internal/docs"
+	"github.com/Jeffail/benthos/v3/lib/config"
+	"gopkg.in/yaml.v3"
+)
+
+// topologyNode describes a component of a config and the components nested
+// within it, such as the inputs of a broker or the processors of a switch.
+type topologyNode struct {
+	Kind     string          `json:"kind"`
+	Type     string          `json:"type"`
+	Label    string          `json:"label,omitempty"`
+	Path     string          `json:"path"`
+	Children []*topologyNode `json:"children,omitempty"`
+}
+
+// topologySection is a top level field of a config and the components that it
+// contains.
+type topologySection struct {
+	Name       string          `json:"name"`
+	Components []*topologyNode `json:"components"`
+}
+
+func mappingValue(node *yaml.Node, key string) *yaml.Node {
+	if node == nil || node.Kind != yaml.MappingNode {
+		return nil
+	}
+	for i := 0; i < len(node.Content)-1; i += 2 {
+		if node.Content[i].Value == key {
+			return node.Content[i+1]
+		}
+	}
+	return nil
+}
+
+func joinPath(path, key string) string {
+	if path == "" {
+		return key
+	}
+	return path + "." + key
+}
+
+// topologyOf walks a YAML config using the config spec in order to find each
+// component, and returns the sections of the config that contain components.
+func topologyOf(configYAML []byte) ([]topologySection, error) {
+	var root yaml.Node
+	if err := yaml.Unmarshal(configYAML, &root); err != nil {
+		return nil, err
+	}
+	if len(root.Content) == 0 {
+		return []topologySection{}, nil
+	}
+	if root.Content[0].Kind != yaml.MappingNode {
+		return nil, errors.New("expected config to be an object")
+	}
+
+	sections := []topologySection{}
+	for _, field := range config.Spec() {
+		value := mappingValue(root.Content[0], field.Name)
+		if value == nil {
+			continue
+		}
+		if components := componentsOf(field, value, field.Name); len(components) > 0 {
+			sections = append(sections, topologySection{
+				Name:       field.Name,
+				Components: components,
+			})
+		}
+	}
+	return sections, nil
+}
+
+// componentsOf returns the components found within the value of a field.
+func componentsOf(field docs.FieldSpec, node *yaml.Node, path string) []*topologyNode {
+	var nodes []*topologyNode
+	switch field.Kind {
+	case docs.Kind2DArray:
+		for i, child := range node.Content {
+			nodes = append(nodes, componentsOf(field.Array(), child, joinPath(path, strconv.Itoa(i)))...)
+		}
+		return nodes
+	case docs.KindArray:
+		for i, child := range node.Content {
+			nodes = append(nodes, componentsOf(field.Scalar(), child, joinPath(path, strconv.Itoa(i)))...)
+		}
+		return nodes
+	case docs.KindMap:
+		for i := 0; i < len(node.Content)-1; i += 2 {
+			nodes = append(nodes, componentsOf(field.Scalar(), node.Content[i+1], joinPath(path, node.Content[i].Value))...)
+		}
+		return nodes
+	}
+
+	if coreType, isCore := field.Type.IsCoreComponent(); isCore {
+		return []*topologyNode{componentOf(coreType, node, path)}
+	}
+	for _, child := range field.Children {
+		if value := mappingValue(node, child.Name); value != nil {
+			nodes = append(nodes, componentsOf(child, value, joinPath(path, child.Name))...)
+		}
+	}
+	return nodes
+}
+
+// componentOf describes a component and walks its config for any nested
+// components.
+func componentOf(coreType docs.Type, node *yaml.Node, path string) *topologyNode {
+	n := &topologyNode{
+		Kind: string(coreType),
+		Path: path,
+	}
+	if label := mappingValue(node, "label"); label != nil {
+		n.Label = label.Value
+	}
+
+	name, spec, err := docs.GetInferenceCandidateFromYAML(nil, coreType, "", node)
+	if err != nil {
+		n.Type = "unknown"
+		return n
+	}
+	n.Type = name
+
+	if coreType == docs.TypeInput || coreType == docs.TypeOutput {
+		if procs := mappingValue(node, "processors"); procs != nil {
+			procsField := docs.FieldCommon("processors", "").Array().HasType(docs.FieldTypeProcessor)
+			n.Children = append(n.Children, componentsOf(procsField, procs, joinPath(path, "processors"))...)
+		}
+	}
+	if conf := mappingValue(node, name); conf != nil {
+		n.Children = append(n.Children, componentsOf(spec.Config, conf, joinPath(path, name))...)
+	}
+	return n
+}
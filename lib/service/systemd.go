@@ -0,0 +1,121 @@
+package service
+
+import (
+	"fmt"
+	"net"
+	"os"
+	"strconv"
+	"time"
+)
+
+//------------------------------------------------------------------------------
+
+// systemdNotifier sends the state of the service to systemd when Benthos is
+// run by a unit with Type=notify or Type=notify-reload, and does nothing
+// otherwise.
+//
+// See https://www.freedesktop.org/software/systemd/man/sd_notify.html
+type systemdNotifier struct {
+	addr *net.UnixAddr
+}
+
+func newSystemdNotifier() *systemdNotifier {
+	n := &systemdNotifier{}
+	if socket := os.Getenv("NOTIFY_SOCKET"); socket != "" {
+		// Abstract sockets are prefixed with @ by systemd, which is replaced
+		// with a null byte.
+		if socket[0] == '@' {
+			socket = "\x00" + socket[1:]
+		}
+		n.addr = &net.UnixAddr{Name: socket, Net: "unixgram"}
+	}
+	return n
+}
+
+// enabled returns true if the service is being run by systemd with
+// notifications enabled.
+func (n *systemdNotifier) enabled() bool {
+	return n.addr != nil
+}
+
+func (n *systemdNotifier) notify(state string) error {
+	if n.addr == nil {
+		return nil
+	}
+	conn, err := net.DialUnix(n.addr.Net, nil, n.addr)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+	_, err = conn.Write([]byte(state))
+	return err
+}
+
+// ready notifies systemd that startup, or a reload, has completed.
+func (n *systemdNotifier) ready() error {
+	return n.notify("READY=1")
+}
+
+// reloading notifies systemd that the config of the service is being
+// reloaded, systemd requires the current monotonic time to accompany this
+// state.
+func (n *systemdNotifier) reloading() error {
+	state := "RELOADING=1"
+	if usec, ok := monotonicUsec(); ok {
+		state += "\nMONOTONIC_USEC=" + strconv.FormatInt(usec, 10)
+	}
+	return n.notify(state)
+}
+
+// stopping notifies systemd that the service is shutting down.
+func (n *systemdNotifier) stopping() error {
+	return n.notify("STOPPING=1")
+}
+
+// watchdog notifies systemd that the service is still healthy, which must be
+// sent within the WatchdogSec interval of the unit otherwise the service is
+// restarted.
+func (n *systemdNotifier) watchdog() error {
+	return n.notify("WATCHDOG=1")
+}
+
+// watchdogIfAlive sends a watchdog notification only when the pipeline is
+// alive, and returns false when the notification was withheld. Withholding
+// notifications from a pipeline that has been disconnected for longer than its
+// liveness grace period allows systemd to restart the service.
+func (n *systemdNotifier) watchdogIfAlive(isAlive func() bool) (bool, error) {
+	if isAlive != nil && !isAlive() {
+		return false, nil
+	}
+	return true, n.watchdog()
+}
+
+// watchdogInterval returns the interval at which watchdog notifications should
+// be sent, which is half of the timeout configured for the unit, or zero if the
+// watchdog is disabled.
+func (n *systemdNotifier) watchdogInterval() (time.Duration, error) {
+	if n.addr == nil {
+		return 0, nil
+	}
+	usecStr := os.Getenv("WATCHDOG_USEC")
+	if usecStr == "" {
+		return 0, nil
+	}
+	if pidStr := os.Getenv("WATCHDOG_PID"); pidStr != "" {
+		pid, err := strconv.Atoi(pidStr)
+		if err != nil {
+			return 0, fmt.Errorf("failed to parse WATCHDOG_PID: %v", err)
+		}
+		if pid != os.Getpid() {
+			return 0, nil
+		}
+	}
+	usec, err := strconv.ParseInt(usecStr, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("failed to parse WATCHDOG_USEC: %v", err)
+	}
+	if usec <= 0 {
+		return 0, fmt.Errorf("WATCHDOG_USEC must be positive, got %v", usec)
+	}
+	return time.Duration(usec) * time.Microsecond / 2, nil
+}
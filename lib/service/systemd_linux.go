@@ -0,0 +1,20 @@
+//go:build linux
+// +build linux
+
+package service
+
+import (
+	"syscall"
+	"unsafe"
+)
+
+const clockMonotonic = 1
+
+// monotonicUsec returns the current time of CLOCK_MONOTONIC in microseconds.
+func monotonicUsec() (int64, bool) {
+	var ts syscall.Timespec
+	if _, _, errno := syscall.Syscall(syscall.SYS_CLOCK_GETTIME, clockMonotonic, uintptr(unsafe.Pointer(&ts)), 0); errno != 0 {
+		return 0, false
+	}
+	return ts.Nano() / 1000, true
+}
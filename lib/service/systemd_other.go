@@ -0,0 +1,10 @@
+//go:build !linux
+// +build !linux
+
+package service
+
+// monotonicUsec is only supported on Linux, which is the only platform that
+// runs systemd.
+func monotonicUsec() (int64, bool) {
+	return 0, false
+}
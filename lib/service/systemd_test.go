@@ -0,0 +1,55 @@
+package service
+
+import (
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSystemdWatchdogIfAlive(t *testing.T) {
+	addr := &net.UnixAddr{Name: filepath.Join(t.TempDir(), "notify.sock"), Net: "unixgram"}
+	conn, err := net.ListenUnixgram(addr.Net, addr)
+	require.NoError(t, err)
+	defer conn.Close()
+
+	require.NoError(t, os.Setenv("NOTIFY_SOCKET", addr.Name))
+	defer os.Unsetenv("NOTIFY_SOCKET")
+	n := newSystemdNotifier()
+	require.True(t, n.enabled())
+
+	readState := func() string {
+		t.Helper()
+		require.NoError(t, conn.SetReadDeadline(time.Now().Add(time.Second)))
+		buf := make([]byte, 64)
+		l, err := conn.Read(buf)
+		require.NoError(t, err)
+		return string(buf[:l])
+	}
+
+	alive := true
+	isAlive := func() bool { return alive }
+
+	sent, err := n.watchdogIfAlive(isAlive)
+	require.NoError(t, err)
+	assert.True(t, sent)
+	assert.Equal(t, "WATCHDOG=1", readState())
+
+	alive = false
+	sent, err = n.watchdogIfAlive(isAlive)
+	require.NoError(t, err)
+	assert.False(t, sent)
+
+	// Nothing is sent whilst the pipeline is not alive.
+	require.NoError(t, n.ready())
+	assert.Equal(t, "READY=1", readState())
+
+	sent, err = n.watchdogIfAlive(nil)
+	require.NoError(t, err)
+	assert.True(t, sent)
+	assert.Equal(t, "WATCHDOG=1", readState())
+}
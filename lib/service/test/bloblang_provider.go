@@ -0,0 +1,127 @@
+package test
+
+import (
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"time"
+
+	"github.com/Jeffail/benthos/v3/internal/bloblang/mapping"
+	"github.com/Jeffail/benthos/v3/internal/bloblang/parser"
+	"github.com/Jeffail/benthos/v3/lib/log"
+	"github.com/Jeffail/benthos/v3/lib/message"
+	"github.com/Jeffail/benthos/v3/lib/processor"
+	"github.com/Jeffail/benthos/v3/lib/response"
+	"github.com/Jeffail/benthos/v3/lib/types"
+)
+
+//------------------------------------------------------------------------------
+
+// BloblangProvider provides a processor that executes a standalone Bloblang
+// mapping file, and records which statements and branches of the mapping have
+// been executed by the processors provided.
+type BloblangProvider struct {
+	content  []rune
+	exec     *mapping.Executor
+	coverage *parser.Coverage
+	logger   log.Modular
+}
+
+// NewBloblangProvider parses a Bloblang mapping file and returns a provider of
+// processors that execute it.
+func NewBloblangProvider(targetPath string, logger log.Modular) (*BloblangProvider, error) {
+	mappingBytes, err := ioutil.ReadFile(targetPath)
+	if err != nil {
+		return nil, err
+	}
+
+	content := string(mappingBytes)
+	exec, coverage, perr := parser.ParseMappingWithCoverage(parser.GlobalContext(), targetPath, content)
+	if perr != nil {
+		return nil, fmt.Errorf("failed to parse mapping: %v", perr.ErrorAtPositionStructured("", []rune(content)))
+	}
+
+	return &BloblangProvider{
+		content:  []rune(content),
+		exec:     exec,
+		coverage: coverage,
+		logger:   logger,
+	}, nil
+}
+
+// Provide returns a processor that executes the mapping. Since the mapping is
+// the target of all test cases the JSON Pointer and environment are ignored.
+func (b *BloblangProvider) Provide(jsonPtr string, environment map[string]string) ([]types.Processor, error) {
+	return []types.Processor{&mappingProcessor{exec: b.exec, logger: b.logger}}, nil
+}
+
+// ProvideBloblang returns a processor that executes the mapping. Since the
+// mapping is the target of all test cases the path is ignored.
+func (b *BloblangProvider) ProvideBloblang(path string) ([]types.Processor, error) {
+	return b.Provide("", nil)
+}
+
+// Uncovered returns a description of each statement and branch of the mapping
+// that has not yet been executed, with its line and column.
+func (b *BloblangProvider) Uncovered() []string {
+	var uncovered []string
+	for _, p := range b.coverage.Uncovered() {
+		line, col := p.LineAndCol(b.content)
+		uncovered = append(uncovered, fmt.Sprintf("line %v char %v: %v never executed", line, col, p.Kind))
+	}
+	return uncovered
+}
+
+// Coverage returns the number of statements and branches of the mapping that
+// have been executed and the total number of them.
+func (b *BloblangProvider) Coverage() (covered, total int) {
+	points := b.coverage.Points()
+	for _, p := range points {
+		if p.Hits() > 0 {
+			covered++
+		}
+	}
+	return covered, len(points)
+}
+
+//------------------------------------------------------------------------------
+
+// mappingProcessor executes a mapping in the same way as a bloblang processor,
+// but messages that fail are flagged with an error that shows where within the
+// mapping the failure occurred.
+type mappingProcessor struct {
+	exec   *mapping.Executor
+	logger log.Modular
+}
+
+func (m *mappingProcessor) ProcessMessage(msg types.Message) ([]types.Message, types.Response) {
+	newParts := make([]types.Part, 0, msg.Len())
+	_ = msg.Iter(func(i int, part types.Part) error {
+		p, err := m.exec.MapPart(i, msg)
+		if err != nil {
+			var sErr *mapping.StatementError
+			if errors.As(err, &sErr) {
+				err = errors.New(sErr.ErrorAtPositionStructured(""))
+			}
+			m.logger.Errorf("%v\n", err)
+			p = part.Copy()
+			processor.FlagErr(p, err)
+		}
+		if p != nil {
+			newParts = append(newParts, p)
+		}
+		return nil
+	})
+	if len(newParts) == 0 {
+		return nil, response.NewAck()
+	}
+	newMsg := message.New(nil)
+	newMsg.SetAll(newParts)
+	return []types.Message{newMsg}, nil
+}
+
+func (m *mappingProcessor) CloseAsync() {}
+
+func (m *mappingProcessor) WaitForClose(timeout time.Duration) error {
+	return nil
+}
@@ -0,0 +1,72 @@
+package test_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/Jeffail/benthos/v3/lib/log"
+	"github.com/Jeffail/benthos/v3/lib/service/test"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBloblangProviderCoverage(t *testing.T) {
+	testDir, err := initTestFiles(map[string]string{
+		"foo.blobl": `root.name = this.name.uppercase()
+root.kind = match this.age {
+  this > 18 => "adult"
+  _ => "child"
+}
+root.checked = true`,
+	})
+	require.NoError(t, err)
+	defer os.RemoveAll(testDir)
+
+	provider, err := test.NewBloblangProvider(filepath.Join(testDir, "foo.blobl"), log.Noop())
+	require.NoError(t, err)
+
+	covered, total := provider.Coverage()
+	assert.Equal(t, 0, covered)
+	assert.Equal(t, 5, total)
+
+	c := test.NewCase()
+	c.InputBatch = []test.InputPart{{Content: `{"name":"foo","age":30}`}}
+	c.OutputBatches = [][]test.ConditionsMap{{{
+		"json_equals": test.ContentJSONEqualsCondition(`{"name":"FOO","kind":"adult","checked":true}`),
+	}}}
+
+	failures, err := c.Execute(provider)
+	require.NoError(t, err)
+	assert.Empty(t, failures)
+
+	covered, total = provider.Coverage()
+	assert.Equal(t, 4, covered)
+	assert.Equal(t, 5, total)
+	assert.Equal(t, []string{
+		"line 4 char 8: branch never executed",
+	}, provider.Uncovered())
+
+	c.InputBatch = []test.InputPart{{Content: `{"name":"bar","age":10}`}}
+	c.OutputBatches = [][]test.ConditionsMap{{{
+		"json_equals": test.ContentJSONEqualsCondition(`{"name":"BAR","kind":"child","checked":true}`),
+	}}}
+
+	failures, err = c.Execute(provider)
+	require.NoError(t, err)
+	assert.Empty(t, failures)
+	assert.Empty(t, provider.Uncovered())
+}
+
+func TestBloblangProviderParseError(t *testing.T) {
+	testDir, err := initTestFiles(map[string]string{
+		"foo.blobl": `root.name = this.name.uppercase(
+root.foo = "bar"`,
+	})
+	require.NoError(t, err)
+	defer os.RemoveAll(testDir)
+
+	_, err = test.NewBloblangProvider(filepath.Join(testDir, "foo.blobl"), log.Noop())
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "failed to parse mapping: line 2 char 1: unable to reference the `root`")
+}
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"time"
 
 	"github.com/Jeffail/benthos/v3/lib/message"
 	"github.com/Jeffail/benthos/v3/lib/message/metadata"
@@ -126,6 +127,23 @@ func (c CaseFailure) String() string {
 	return fmt.Sprintf("%v [line %v]: %v", c.Name, c.TestLine, c.Reason)
 }
 
+// CaseResult describes the outcome of executing a test case, including how
+// long it took to execute.
+type CaseResult struct {
+	Name     string
+	TestLine int
+	Duration time.Duration
+	Failures []CaseFailure
+}
+
+func caseResultFailures(results []CaseResult) []CaseFailure {
+	var failures []CaseFailure
+	for _, r := range results {
+		failures = append(failures, r.Failures...)
+	}
+	return failures
+}
+
 // ProcProvider returns compiled processors extracted from a Benthos config
 // using a JSON Pointer.
 type ProcProvider interface {
@@ -3,8 +3,11 @@ package test
 import (
 	"fmt"
 	"os"
+	"regexp"
+	"time"
 
 	"github.com/Jeffail/benthos/v3/lib/log"
+	"github.com/fatih/color"
 	"github.com/urfave/cli/v2"
 )
 
@@ -20,6 +23,9 @@ func CliCommand(testSuffix string) *cli.Command {
    benthos test ./path/to/configs/...
    benthos test ./foo_configs ./bar_configs
    benthos test ./foo.yaml
+   benthos test --format junit ./... > report.xml
+   benthos test --watch ./...
+   benthos test --run 'foo.*' --parallel 8 ./...
 
    For more information check out the docs at:
    https://benthos.dev/docs/configuration/unit_testing`[4:],
@@ -32,7 +38,27 @@ func CliCommand(testSuffix string) *cli.Command {
 			&cli.StringFlag{
 				Name:  "log",
 				Value: "",
-				Usage: "allow components to write logs at a provided level to stdout.",
+				Usage: "allow components to write logs at a provided level to stdout, or stderr when a format other than text is used.",
+			},
+			&cli.StringFlag{
+				Name:  "format",
+				Value: "text",
+				Usage: "print test results in a specific format. Options are text, junit or tap.",
+			},
+			&cli.BoolFlag{
+				Name:  "watch",
+				Value: false,
+				Usage: "watch test targets and their definitions for changes, and re-run the tests of affected targets.",
+			},
+			&cli.StringFlag{
+				Name:  "run",
+				Value: "",
+				Usage: "only execute test cases with a name that matches a regular expression.",
+			},
+			&cli.IntFlag{
+				Name:  "parallel",
+				Value: 1,
+				Usage: "the maximum number of test targets to execute concurrently.",
 			},
 		},
 		Action: func(c *cli.Context) error {
@@ -49,14 +75,51 @@ func CliCommand(testSuffix string) *cli.Command {
 				fmt.Fprintln(os.Stderr, "Cannot override fields with --set (-s) during unit tests")
 				os.Exit(1)
 			}
+			format := c.String("format")
+			switch format {
+			case formatText, formatJUnit, formatTAP:
+			default:
+				fmt.Fprintf(os.Stderr, "Format not recognised: %v\n", format)
+				os.Exit(1)
+			}
+			watch := c.Bool("watch")
+			if watch && format != formatText {
+				fmt.Fprintln(os.Stderr, "Cannot watch for changes with a format other than text")
+				os.Exit(1)
+			}
+			if format != formatText {
+				// Failure diffs must not contain escape sequences in reports.
+				color.NoColor = true
+			}
+			opts := newRunOptions(testSuffix, true, log.Noop())
+			opts.resourcesPaths = c.StringSlice("resources")
+			opts.format = format
+			if opts.parallel = c.Int("parallel"); opts.parallel < 1 {
+				fmt.Fprintln(os.Stderr, "The number of parallel test targets must be at least 1")
+				os.Exit(1)
+			}
+			if runExpr := c.String("run"); len(runExpr) > 0 {
+				var err error
+				if opts.filter, err = regexp.Compile(runExpr); err != nil {
+					fmt.Fprintf(os.Stderr, "Failed to parse run expression: %v\n", err)
+					os.Exit(1)
+				}
+			}
 			if logLevel := c.String("log"); len(logLevel) > 0 {
 				logConf := log.NewConfig()
 				logConf.LogLevel = logLevel
-				logger := log.New(os.Stdout, logConf)
-				if runAll(c.Args().Slice(), testSuffix, true, logger, c.StringSlice("resources")) {
-					os.Exit(0)
+				logWriter := os.Stdout
+				if format != formatText {
+					// Keep logs out of structured reports.
+					logWriter = os.Stderr
 				}
-			} else if runAll(c.Args().Slice(), testSuffix, true, log.Noop(), c.StringSlice("resources")) {
+				opts.logger = log.New(logWriter, logConf)
+			}
+			if watch {
+				watchAll(c.Args().Slice(), opts, time.Second, nil)
+				os.Exit(0)
+			}
+			if runAll(c.Args().Slice(), opts) {
 				os.Exit(0)
 			}
 			os.Exit(1)
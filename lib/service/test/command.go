@@ -5,9 +5,13 @@ import (
 	"io/ioutil"
 	"os"
 	"path/filepath"
+	"regexp"
 	"sort"
 	"strings"
+	"sync"
+	"sync/atomic"
 
+	"github.com/Jeffail/benthos/v3/internal/bloblang/parser"
 	"github.com/Jeffail/benthos/v3/lib/config"
 	"github.com/Jeffail/benthos/v3/lib/log"
 	"github.com/fatih/color"
@@ -34,11 +38,34 @@ func GetPathPair(fullPath, testSuffix string) (configPath, definitionPath string
 		configPath = filepath.Join(path, strings.TrimSuffix(filename, testSuffix)+ext)
 	} else {
 		configPath = filepath.Clean(fullPath)
+		if isBloblangTarget(configPath) {
+			// Definitions for mapping tests are always YAML.
+			ext = ".yaml"
+		}
 		definitionPath = filepath.Join(path, filename+testSuffix+ext)
 	}
 	return
 }
 
+func isBloblangTarget(path string) bool {
+	return filepath.Ext(path) == ".blobl"
+}
+
+// resolvePathPair returns the config path and test definition path for a given
+// path, where a test definition without an accompanying YAML config instead
+// targets a Bloblang mapping file of the same name if it exists.
+func resolvePathPair(fullPath, testSuffix string) (configPath, definitionPath string) {
+	configPath, definitionPath = GetPathPair(fullPath, testSuffix)
+	if _, err := os.Stat(configPath); err == nil || isBloblangTarget(configPath) {
+		return
+	}
+	bloblPath := strings.TrimSuffix(configPath, filepath.Ext(configPath)) + ".blobl"
+	if _, err := os.Stat(bloblPath); err == nil {
+		configPath = bloblPath
+	}
+	return
+}
+
 func getDefinition(targetPath, definitionPath string) (*Definition, error) {
 	if _, err := os.Stat(targetPath); err != nil {
 		return nil, fmt.Errorf("unable to access target config file '%v': %v", targetPath, err)
@@ -72,7 +99,7 @@ func GetTestTargets(targetPath, testSuffix string, recurse bool) (map[string]Def
 		return nil, err
 	}
 	if !info.IsDir() {
-		configPath, definitionPath := GetPathPair(targetPath, testSuffix)
+		configPath, definitionPath := resolvePathPair(targetPath, testSuffix)
 		def, err := getDefinition(configPath, definitionPath)
 		if err != nil {
 			return nil, err
@@ -96,7 +123,7 @@ func GetTestTargets(targetPath, testSuffix string, recurse bool) (map[string]Def
 			}
 			return filepath.SkipDir
 		}
-		configPath, definitionPath := GetPathPair(path, testSuffix)
+		configPath, definitionPath := resolvePathPair(path, testSuffix)
 		if _, exists := pathMap[configPath]; exists {
 			return nil
 		}
@@ -123,7 +150,10 @@ func GetTestTargets(targetPath, testSuffix string, recurse bool) (map[string]Def
 // Lints the config target of a test definition and either returns linting
 // errors (false for failed) or returns an error.
 func lintTarget(path, testSuffix string) ([]string, error) {
-	confPath, _ := GetPathPair(path, testSuffix)
+	confPath, _ := resolvePathPair(path, testSuffix)
+	if isBloblangTarget(confPath) {
+		return lintBloblangTarget(confPath)
+	}
 	dummyConf := config.New()
 	lints, err := config.Read(confPath, true, &dummyConf)
 	if err != nil {
@@ -132,6 +162,26 @@ func lintTarget(path, testSuffix string) ([]string, error) {
 	return lints, nil
 }
 
+// Lints a Bloblang mapping file, where warnings found within the mapping are
+// returned as lints.
+func lintBloblangTarget(path string) ([]string, error) {
+	mappingBytes, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	content := []rune(string(mappingBytes))
+	_, warnings, perr := parser.ParseMappingWithWarnings(parser.GlobalContext(), path, string(content))
+	if perr != nil {
+		return nil, fmt.Errorf("failed to parse mapping: %v", perr.ErrorAtPositionStructured("", content))
+	}
+	var lints []string
+	for _, w := range warnings {
+		line, col := w.LineAndCol(content)
+		lints = append(lints, fmt.Sprintf("line %v char %v: %v", line, col, w.Message))
+	}
+	return lints, nil
+}
+
 //------------------------------------------------------------------------------
 
 func resolveTestPath(path string) (string, bool) {
@@ -158,43 +208,114 @@ func Run(path, testSuffix string, lint bool) bool {
 // a config file, a config files test definition file, a directory, or the
 // wildcard pattern './...'.
 func RunAll(paths []string, testSuffix string, lint bool) bool {
-	return runAll(paths, testSuffix, lint, log.Noop(), nil)
+	return runAll(paths, newRunOptions(testSuffix, lint, log.Noop()))
 }
 
 // RunAllWithLogger executes the test command for a slice of paths. The path can
 // either be a config file, a config files test definition file, a directory, or
 // the wildcard pattern './...'.
 func RunAllWithLogger(paths []string, testSuffix string, lint bool, logger log.Modular) bool {
-	return runAll(paths, testSuffix, lint, logger, nil)
+	return runAll(paths, newRunOptions(testSuffix, lint, logger))
 }
 
-func runAll(paths []string, testSuffix string, lint bool, logger log.Modular, resourcesPaths []string) bool {
-	targets := map[string]Definition{}
+// runOptions configures how test targets are executed and reported.
+type runOptions struct {
+	testSuffix     string
+	lint           bool
+	logger         log.Modular
+	resourcesPaths []string
+	format         string
 
+	// When set only test cases with a name matching the filter are executed.
+	filter *regexp.Regexp
+
+	// The maximum number of test targets to execute concurrently.
+	parallel int
+}
+
+func newRunOptions(testSuffix string, lint bool, logger log.Modular) runOptions {
+	return runOptions{
+		testSuffix: testSuffix,
+		lint:       lint,
+		logger:     logger,
+		format:     formatText,
+		parallel:   1,
+	}
+}
+
+// targetResult describes the outcome of testing a target config or mapping.
+type targetResult struct {
+	target string
+	lints  []string
+	cases  []CaseResult
+}
+
+func (t targetResult) failed() bool {
+	if len(t.lints) > 0 {
+		return true
+	}
+	for _, c := range t.cases {
+		if len(c.Failures) > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+func getAllTargets(paths []string, testSuffix string) (map[string]Definition, error) {
+	targets := map[string]Definition{}
 	for _, path := range paths {
 		var recurse bool
 		path, recurse = resolveTestPath(path)
 		lTargets, err := GetTestTargets(path, testSuffix, recurse)
 		if err != nil {
-			fmt.Fprintf(os.Stderr, "Failed to obtain test targets: %v\n", err)
-			return false
+			return nil, err
 		}
 		for k, v := range lTargets {
 			targets[k] = v
 		}
 	}
+	return targets, nil
+}
 
-	if len(targets) == 0 {
-		fmt.Printf("%v\n", yellow("No tests were found"))
+// filterTargets removes test cases with names that do not match a filter, and
+// then removes any targets left without test cases.
+func filterTargets(targets map[string]Definition, filter *regexp.Regexp) map[string]Definition {
+	if filter == nil {
+		return targets
+	}
+	filtered := make(map[string]Definition, len(targets))
+	for k, def := range targets {
+		var cases []Case
+		for _, c := range def.Cases {
+			if filter.MatchString(c.Name) {
+				cases = append(cases, c)
+			}
+		}
+		if len(cases) > 0 {
+			def.Cases = cases
+			filtered[k] = def
+		}
+	}
+	return filtered
+}
+
+func runAll(paths []string, opts runOptions) bool {
+	targets, err := getAllTargets(paths, opts.testSuffix)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to obtain test targets: %v\n", err)
 		return false
 	}
+	targets = filterTargets(targets, opts.filter)
 
-	type failedTarget struct {
-		target string
-		lints  []string
-		cases  []CaseFailure
+	if len(targets) == 0 {
+		if opts.format == formatText {
+			fmt.Printf("%v\n", yellow("No tests were found"))
+		} else {
+			fmt.Fprintln(os.Stderr, "No tests were found")
+		}
+		return false
 	}
-	fails := []failedTarget{}
 
 	targetPaths := make([]string, 0, len(targets))
 	for k := range targets {
@@ -202,61 +323,144 @@ func runAll(paths []string, testSuffix string, lint bool, logger log.Modular, re
 	}
 	sort.Strings(targetPaths)
 
-	var err error
-	for _, target := range targetPaths {
-		var lints []string
-		var failCases []CaseFailure
-		if lint {
-			if lints, err = lintTarget(target, testSuffix); err != nil {
-				fmt.Fprintf(os.Stderr, "Failed to execute test target '%v': %v\n", target, err)
-				return false
+	return runTargets(targets, targetPaths, opts)
+}
+
+// usesEnvironment returns true if any test case of a definition sets
+// environment variables.
+func (d Definition) usesEnvironment() bool {
+	for _, c := range d.Cases {
+		if len(c.Environment) > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+type targetRun struct {
+	res           targetResult
+	bloblProvider *BloblangProvider
+	err           error
+	done          chan struct{}
+}
+
+func executeTarget(target string, def Definition, opts runOptions) (res targetResult, bloblProvider *BloblangProvider, err error) {
+	res.target = target
+	if opts.lint {
+		if res.lints, err = lintTarget(target, opts.testSuffix); err != nil {
+			return
+		}
+	}
+	if isBloblangTarget(target) {
+		if bloblProvider, err = NewBloblangProvider(target, opts.logger); err == nil {
+			res.cases, err = def.executeCasesWith(target, bloblProvider)
+		}
+	} else {
+		res.cases, err = def.executeCases(target, opts.resourcesPaths, opts.logger)
+	}
+	return
+}
+
+// runTargets executes the tests of a sorted list of targets and prints the
+// results in the given format, returning false if any target failed. Targets
+// are executed concurrently up to the configured parallelism, but results are
+// always printed in the order of the list.
+func runTargets(targets map[string]Definition, targetPaths []string, opts runOptions) bool {
+	parallel := opts.parallel
+	if parallel < 1 {
+		parallel = 1
+	}
+
+	// Environment variables are process wide, therefore targets that set them
+	// must be executed in isolation from all other targets.
+	var envMut sync.RWMutex
+	var aborted int32
+
+	runs := make([]*targetRun, len(targetPaths))
+	for i := range runs {
+		runs[i] = &targetRun{done: make(chan struct{})}
+	}
+
+	var wg sync.WaitGroup
+	pending := make(chan int)
+	for w := 0; w < parallel; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range pending {
+				run, target := runs[i], targetPaths[i]
+				if atomic.LoadInt32(&aborted) == 0 {
+					def := targets[target]
+					if def.usesEnvironment() {
+						envMut.Lock()
+					} else {
+						envMut.RLock()
+					}
+					run.res, run.bloblProvider, run.err = executeTarget(target, def, opts)
+					if def.usesEnvironment() {
+						envMut.Unlock()
+					} else {
+						envMut.RUnlock()
+					}
+				}
+				close(run.done)
 			}
+		}()
+	}
+	go func() {
+		for i := range targetPaths {
+			pending <- i
 		}
-		if failCases, err = targets[target].execute(target, resourcesPaths, logger); err != nil {
-			fmt.Fprintf(os.Stderr, "Failed to execute test target '%v': %v\n", target, err)
+		close(pending)
+	}()
+	defer wg.Wait()
+
+	results := make([]targetResult, 0, len(targetPaths))
+	failed := false
+
+	for i, target := range targetPaths {
+		run := runs[i]
+		<-run.done
+		if run.err != nil {
+			atomic.StoreInt32(&aborted, 1)
+			fmt.Fprintf(os.Stderr, "Failed to execute test target '%v': %v\n", target, run.err)
 			return false
 		}
-		if len(lints) > 0 || len(failCases) > 0 {
-			fails = append(fails, failedTarget{
-				target: target,
-				lints:  lints,
-				cases:  failCases,
-			})
+		results = append(results, run.res)
+		if run.res.failed() {
+			failed = true
+		}
+		if opts.format != formatText {
+			continue
+		}
+		if run.res.failed() {
 			fmt.Printf("Test '%v' %v\n", target, red("failed"))
 		} else {
 			fmt.Printf("Test '%v' %v\n", target, green("succeeded"))
 		}
-	}
-	if len(fails) > 0 {
-		fmt.Printf("\nFailures:\n\n")
-		for i, fail := range fails {
-			if i > 0 {
-				fmt.Println("")
-			}
-			fmt.Printf("--- %v ---\n\n", fail.target)
-			for _, lint := range fail.lints {
-				fmt.Printf("Lint: %v\n", lint)
-			}
-			if len(fail.cases) > 0 {
-				if len(fail.lints) > 0 {
-					fmt.Println("")
-				}
-				var namePrev string
-				for i, fail := range fail.cases {
-					if namePrev != fail.Name {
-						if i > 0 {
-							fmt.Println("")
-						}
-						fmt.Printf("%v [line %v]:\n", fail.Name, fail.TestLine)
-						namePrev = fail.Name
-					}
-					fmt.Println(fail.Reason)
-				}
+		if run.bloblProvider != nil {
+			covered, total := run.bloblProvider.Coverage()
+			fmt.Printf("  Coverage: %v of %v statements and branches executed\n", covered, total)
+			for _, u := range run.bloblProvider.Uncovered() {
+				fmt.Printf("  %v\n", yellow(u))
 			}
 		}
+	}
+
+	var err error
+	switch opts.format {
+	case formatJUnit:
+		err = printJUnit(os.Stdout, results)
+	case formatTAP:
+		printTAP(os.Stdout, results)
+	default:
+		printFailures(results)
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to print test results: %v\n", err)
 		return false
 	}
-	return true
+	return !failed
 }
 
 //------------------------------------------------------------------------------
@@ -72,6 +72,13 @@ func TestGetBothPaths(t *testing.T) {
 				"baz_benthos_test",
 			},
 		},
+		{
+			input: "/foo/bar/baz.blobl",
+			output: [2]string{
+				"/foo/bar/baz.blobl",
+				"/foo/bar/baz_benthos_test.yaml",
+			},
+		},
 	}
 
 	for i, testDef := range tests {
@@ -120,6 +127,47 @@ func TestGetTargetsSingle(t *testing.T) {
 	}
 }
 
+func TestGetTargetsBloblang(t *testing.T) {
+	testDir, err := initTestFiles(map[string]string{
+		"foo.blobl":                    `root = this`,
+		"foo_benthos_test.yaml":        `tests: [{}]`,
+		"bar.blobl":                    `root = this`,
+		"nested/baz.blobl":             `root = this`,
+		"nested/baz_benthos_test.yaml": `tests: [{}]`,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(testDir)
+
+	for _, target := range []string{"foo.blobl", "foo_benthos_test.yaml"} {
+		paths, err := test.GetTestTargets(filepath.Join(testDir, target), "_benthos_test", false)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if exp, act := 1, len(paths); exp != act {
+			t.Fatalf("Wrong count of paths: %v != %v", act, exp)
+		}
+		if _, exists := paths[filepath.Join(testDir, "foo.blobl")]; !exists {
+			t.Errorf("Wrong path returned: %v does not contain foo.blobl", paths)
+		}
+	}
+
+	paths, err := test.GetTestTargets(testDir, "_benthos_test", true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if exp, act := 2, len(paths); exp != act {
+		t.Fatalf("Wrong count of paths: %v != %v", act, exp)
+	}
+	if _, exists := paths[filepath.Join(testDir, "foo.blobl")]; !exists {
+		t.Errorf("Wrong path returned: %v does not contain foo.blobl", paths)
+	}
+	if _, exists := paths[filepath.Join(testDir, "nested", "baz.blobl")]; !exists {
+		t.Errorf("Wrong path returned: %v does not contain nested/baz.blobl", paths)
+	}
+}
+
 func TestGetTargetsSingleError(t *testing.T) {
 	testDir, err := initTestFiles(map[string]string{
 		"foo.yaml":              `foobar: {}`,
@@ -274,3 +322,45 @@ tests:
 		t.Error("Unexpected result")
 	}
 }
+
+func TestCommandRunBloblang(t *testing.T) {
+	testDir, err := initTestFiles(map[string]string{
+		"foo.blobl": `root.name = this.name.uppercase()
+root.kind = if this.age > 18 { "adult" } else { "child" }`,
+		"foo_benthos_test.yaml": `
+tests:
+  - name: adult
+    input_batch:
+      - content: '{"name":"foo","age":30}'
+    output_batches:
+      -
+        - json_equals: {"name":"FOO","kind":"adult"}
+  - name: bad name
+    input_batch:
+      - content: '{"age":30}'
+    output_batches:
+      -
+        - error_contains: expected string value`,
+		"bar.blobl": `root = this.uppercase()`,
+		"bar_benthos_test.yaml": `
+tests:
+  - name: example test
+    input_batch:
+      - content: '"example content"'
+    output_batches:
+      -
+        - content_equals: '"example content"'`,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(testDir)
+
+	if !test.Run(filepath.Join(testDir, "foo.blobl"), "_benthos_test", true) {
+		t.Error("Unexpected result")
+	}
+
+	if test.Run(filepath.Join(testDir, "bar.blobl"), "_benthos_test", true) {
+		t.Error("Unexpected result")
+	}
+}
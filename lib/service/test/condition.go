@@ -9,10 +9,12 @@ import (
 	"path/filepath"
 	"regexp"
 	"sort"
+	"strings"
 
 	"github.com/Jeffail/benthos/v3/internal/bloblang"
 	"github.com/Jeffail/benthos/v3/internal/bloblang/mapping"
 	"github.com/Jeffail/benthos/v3/lib/message"
+	"github.com/Jeffail/benthos/v3/lib/processor"
 	"github.com/Jeffail/benthos/v3/lib/types"
 	"github.com/nsf/jsondiff"
 	yaml "gopkg.in/yaml.v3"
@@ -84,6 +86,12 @@ func (c *ConditionsMap) UnmarshalYAML(value *yaml.Node) error {
 				return fmt.Errorf("line %v: %v", v.Line, err)
 			}
 			cond = val
+		case "error_contains":
+			val := ErrorContainsCondition("")
+			if err := v.Decode(&val); err != nil {
+				return fmt.Errorf("line %v: %v", v.Line, err)
+			}
+			cond = val
 		default:
 			return fmt.Errorf("line %v: message part condition type not recognised: %v", v.Line, k)
 		}
@@ -259,6 +267,24 @@ func (m MetadataEqualsCondition) Check(p types.Part) error {
 
 //------------------------------------------------------------------------------
 
+// ErrorContainsCondition checks that a message has been flagged as having
+// failed processing with an error that contains a string.
+type ErrorContainsCondition string
+
+// Check this condition against a message part.
+func (e ErrorContainsCondition) Check(p types.Part) error {
+	act := processor.GetFail(p)
+	if act == "" {
+		return fmt.Errorf("expected error containing %v, message was not flagged with an error", blue(string(e)))
+	}
+	if !strings.Contains(act, string(e)) {
+		return fmt.Errorf("error mismatch\n  expected to contain: %v\n  received: %v", blue(string(e)), red(act))
+	}
+	return nil
+}
+
+//------------------------------------------------------------------------------
+
 // Helper function for converting yaml.Node to a string
 // simple nodes are converted to their string equivalents
 // complex nodes are converted to a JSON representation
@@ -10,6 +10,7 @@ import (
 	"testing"
 
 	"github.com/Jeffail/benthos/v3/lib/message"
+	"github.com/Jeffail/benthos/v3/lib/processor"
 	"github.com/fatih/color"
 	"github.com/nsf/jsondiff"
 	"github.com/stretchr/testify/assert"
@@ -307,6 +308,21 @@ func TestMetadataEqualsCondition(t *testing.T) {
 	}
 }
 
+func TestErrorContainsCondition(t *testing.T) {
+	color.NoColor = true
+
+	cond := ErrorContainsCondition("bad thing")
+
+	part := message.NewPart(nil)
+	assert.EqualError(t, cond.Check(part), "expected error containing bad thing, message was not flagged with an error")
+
+	processor.FlagErr(part, errors.New("a bad thing happened"))
+	assert.NoError(t, cond.Check(part))
+
+	processor.FlagErr(part, errors.New("a good thing happened"))
+	assert.EqualError(t, cond.Check(part), "error mismatch\n  expected to contain: bad thing\n  received: a good thing happened")
+}
+
 func TestJSONEqualsCondition(t *testing.T) {
 	color.NoColor = true
 
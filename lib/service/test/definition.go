@@ -3,6 +3,7 @@ package test
 import (
 	"fmt"
 	"path/filepath"
+	"time"
 
 	"github.com/Jeffail/benthos/v3/lib/log"
 	"golang.org/x/sync/errgroup"
@@ -58,6 +59,22 @@ func (d Definition) Execute(filepath string) ([]CaseFailure, error) {
 }
 
 func (d Definition) execute(testFilePath string, resourcesPaths []string, logger log.Modular) ([]CaseFailure, error) {
+	results, err := d.executeCases(testFilePath, resourcesPaths, logger)
+	if err != nil {
+		return nil, err
+	}
+	return caseResultFailures(results), nil
+}
+
+func (d Definition) executeCases(testFilePath string, resourcesPaths []string, logger log.Modular) ([]CaseResult, error) {
+	if isBloblangTarget(testFilePath) {
+		provider, err := NewBloblangProvider(testFilePath, logger)
+		if err != nil {
+			return nil, err
+		}
+		return d.executeCasesWith(testFilePath, provider)
+	}
+
 	procsProvider := NewProcessorsProvider(
 		testFilePath,
 		OptAddResourcesPaths(resourcesPaths),
@@ -71,35 +88,52 @@ func (d Definition) execute(testFilePath string, resourcesPaths []string, logger
 			}
 		}
 	}
+	return d.executeCasesWith(testFilePath, procsProvider)
+}
+
+func (d Definition) executeWith(testFilePath string, procsProvider ProcProvider) ([]CaseFailure, error) {
+	results, err := d.executeCasesWith(testFilePath, procsProvider)
+	if err != nil {
+		return nil, err
+	}
+	return caseResultFailures(results), nil
+}
 
+func (d Definition) executeCasesWith(testFilePath string, procsProvider ProcProvider) ([]CaseResult, error) {
 	dir := filepath.Dir(testFilePath)
 
-	var totalFailures []CaseFailure
+	results := make([]CaseResult, len(d.Cases))
+	executeCase := func(i int, c Case) error {
+		started := time.Now()
+		failures, err := c.executeFrom(dir, procsProvider)
+		if err != nil {
+			return fmt.Errorf("test case %v failed: %v", i, err)
+		}
+		results[i] = CaseResult{
+			Name:     c.Name,
+			TestLine: c.line,
+			Duration: time.Since(started),
+			Failures: failures,
+		}
+		return nil
+	}
+
 	if !d.Parallel {
 		for i, c := range d.Cases {
 			cleanupEnv := setEnvironment(c.Environment)
-			failures, err := c.executeFrom(dir, procsProvider)
+			err := executeCase(i, c)
+			cleanupEnv()
 			if err != nil {
-				cleanupEnv()
-				return nil, fmt.Errorf("test case %v failed: %v", i, err)
+				return nil, err
 			}
-			totalFailures = append(totalFailures, failures...)
-			cleanupEnv()
 		}
 	} else {
 		var g errgroup.Group
-
-		failureSlices := make([][]CaseFailure, len(d.Cases))
 		for i, c := range d.Cases {
 			i := i
 			c := c
 			g.Go(func() error {
-				failures, err := c.executeFrom(dir, procsProvider)
-				if err != nil {
-					return fmt.Errorf("test case %v failed: %v", i, err)
-				}
-				failureSlices[i] = failures
-				return nil
+				return executeCase(i, c)
 			})
 		}
 
@@ -107,13 +141,9 @@ func (d Definition) execute(testFilePath string, resourcesPaths []string, logger
 		if err := g.Wait(); err != nil {
 			return nil, err
 		}
-
-		for _, fs := range failureSlices {
-			totalFailures = append(totalFailures, fs...)
-		}
 	}
 
-	return totalFailures, nil
+	return results, nil
 }
 
 //------------------------------------------------------------------------------
@@ -0,0 +1,102 @@
+package test
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	yaml "gopkg.in/yaml.v3"
+)
+
+// mockResponse describes a canned response that a mocked processor returns in
+// place of every message it receives.
+type mockResponse struct {
+	content  *string
+	metadata map[string]string
+	err      string
+}
+
+func (m *mockResponse) fromYAML(value *yaml.Node) error {
+	rawMap := map[string]yaml.Node{}
+	if err := value.Decode(&rawMap); err != nil {
+		return fmt.Errorf("line %v: %v", value.Line, err)
+	}
+	for k, v := range rawMap {
+		switch k {
+		case "content":
+			var content string
+			if err := v.Decode(&content); err != nil {
+				return fmt.Errorf("line %v: %v", v.Line, err)
+			}
+			m.content = &content
+		case "json_content":
+			var content string
+			if err := yamlNodeToTestString(&v, &content); err != nil {
+				return fmt.Errorf("line %v: %v", v.Line, err)
+			}
+			m.content = &content
+		case "metadata":
+			if err := v.Decode(&m.metadata); err != nil {
+				return fmt.Errorf("line %v: %v", v.Line, err)
+			}
+		case "error":
+			if err := v.Decode(&m.err); err != nil {
+				return fmt.Errorf("line %v: %v", v.Line, err)
+			}
+		default:
+			return fmt.Errorf("line %v: mock response field not recognised: %v", v.Line, k)
+		}
+	}
+	if m.err == "" && m.content == nil && len(m.metadata) == 0 {
+		return fmt.Errorf("line %v: mock response must set content, metadata or an error", value.Line)
+	}
+	if m.err != "" && (m.content != nil || len(m.metadata) > 0) {
+		return fmt.Errorf("line %v: mock response error cannot be combined with content or metadata", value.Line)
+	}
+	return nil
+}
+
+// mapping returns a Bloblang mapping that produces the canned response.
+func (m *mockResponse) mapping() string {
+	if m.err != "" {
+		return fmt.Sprintf("root = throw(%v)", strconv.Quote(m.err))
+	}
+
+	var lines []string
+	if m.content != nil {
+		lines = append(lines, fmt.Sprintf("root = %v", strconv.Quote(*m.content)))
+	}
+
+	keys := make([]string, 0, len(m.metadata))
+	for k := range m.metadata {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		lines = append(lines, fmt.Sprintf("meta %v = %v", strconv.Quote(k), strconv.Quote(m.metadata[k])))
+	}
+	return strings.Join(lines, "\n")
+}
+
+// resolveMock converts a mock defined with the `mock_response` shorthand into
+// the config of a processor that produces the canned response, any other mock
+// is returned unchanged.
+func resolveMock(v yaml.Node) (yaml.Node, error) {
+	if v.Kind != yaml.MappingNode || len(v.Content) != 2 || v.Content[0].Value != "mock_response" {
+		return v, nil
+	}
+
+	var res mockResponse
+	if err := res.fromYAML(v.Content[1]); err != nil {
+		return v, err
+	}
+
+	var procNode yaml.Node
+	if err := procNode.Encode(map[string]string{
+		"bloblang": res.mapping(),
+	}); err != nil {
+		return v, err
+	}
+	return procNode, nil
+}
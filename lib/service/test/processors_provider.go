@@ -7,7 +7,9 @@ import (
 	"os"
 	"path/filepath"
 	"strings"
+	"sync"
 
+	"github.com/Jeffail/benthos/v3/internal/bloblang"
 	"github.com/Jeffail/benthos/v3/internal/bloblang/parser"
 	"github.com/Jeffail/benthos/v3/internal/docs"
 	"github.com/Jeffail/benthos/v3/lib/config"
@@ -23,10 +25,16 @@ import (
 //------------------------------------------------------------------------------
 
 type cachedConfig struct {
-	mgr   manager.ResourceConfig
-	procs []processor.Config
+	mgr       manager.ResourceConfig
+	procs     []processor.Config
+	constants map[string]string
+	dir       string
 }
 
+// Constants are global and their values are captured by mappings when they're
+// parsed, therefore processors of test targets are initialised one at a time.
+var constantsMut sync.Mutex
+
 // ProcessorsProvider consumes a Benthos config and, given a JSON Pointer,
 // extracts and constructs the target processors from the config file.
 type ProcessorsProvider struct {
@@ -112,7 +120,18 @@ func (p *ProcessorsProvider) ProvideBloblang(path string) ([]types.Processor, er
 //------------------------------------------------------------------------------
 
 func (p *ProcessorsProvider) initProcs(confs cachedConfig) ([]types.Processor, error) {
-	mgr, err := manager.NewV2(confs.mgr, types.NoopMgr(), p.logger, metrics.Noop())
+	constantsMut.Lock()
+	defer constantsMut.Unlock()
+
+	if err := bloblang.SetConstants(confs.constants); err != nil {
+		return nil, fmt.Errorf("failed to set constants: %v", err)
+	}
+
+	// Mappings of the config resolve relative paths from its directory.
+	mgr, err := manager.NewV2(
+		confs.mgr, types.NoopMgr(), p.logger, metrics.Noop(),
+		manager.OptSetBloblangEnvironment(bloblang.GlobalEnvironment().WithMappingDir(confs.dir)),
+	)
 	if err != nil {
 		return nil, fmt.Errorf("failed to initialise resources: %v", err)
 	}
@@ -221,6 +240,15 @@ func (p *ProcessorsProvider) getConfs(jsonPtr string, environment map[string]str
 		return confs, fmt.Errorf("failed to parse config file '%v': %v", targetPath, err)
 	}
 
+	var constantsWrapper struct {
+		Constants map[string]string `yaml:"constants"`
+	}
+	if err = yaml.Unmarshal(configBytes, &constantsWrapper); err != nil {
+		return confs, fmt.Errorf("failed to parse config file '%v': %v", targetPath, err)
+	}
+	confs.constants = constantsWrapper.Constants
+	confs.dir = filepath.Dir(targetPath)
+
 	for _, path := range p.resourcesPaths {
 		resourceBytes, err := config.ReadWithJSONPointers(path, true)
 		if err != nil {
@@ -253,6 +281,9 @@ func (p *ProcessorsProvider) getConfs(jsonPtr string, environment map[string]str
 		if err != nil {
 			return confs, fmt.Errorf("failed to parse mock path '%v': %w", k, err)
 		}
+		if v, err = resolveMock(v); err != nil {
+			return confs, fmt.Errorf("failed to resolve mock '%v': %w", k, err)
+		}
 		if err = confSpec.SetYAMLPath(nil, root, &v, mockPathSlice...); err != nil {
 			return confs, fmt.Errorf("failed to set mock '%v': %w", k, err)
 		}
@@ -267,6 +298,9 @@ func (p *ProcessorsProvider) getConfs(jsonPtr string, environment map[string]str
 			if !exists {
 				return confs, fmt.Errorf("mock for label '%v' could not be applied as the label was not found in the test target file, it is not currently possible to mock resources imported separate to the test file", k)
 			}
+			if v, err = resolveMock(v); err != nil {
+				return confs, fmt.Errorf("failed to resolve mock '%v': %w", k, err)
+			}
 			if err = confSpec.SetYAMLPath(nil, root, &v, mockPathSlice...); err != nil {
 				return confs, fmt.Errorf("failed to set mock '%v': %w", k, err)
 			}
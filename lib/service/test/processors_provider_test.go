@@ -523,6 +523,124 @@ pipeline:
 	assert.Equal(t, "starts with first mock first proc second mock second proc", string(msgs[0].Get(0).Get()))
 }
 
+func TestProcessorsProviderMockResponses(t *testing.T) {
+	files := map[string]string{
+		"config1.yaml": `
+pipeline:
+  processors:
+    - label: fetch_user
+      http:
+        url: http://example.com/users
+        verb: GET
+    - label: tag_user
+      cache:
+        resource: foocache
+        operator: get
+        key: ${! content() }
+    - label: store_user
+      http:
+        url: http://example.com/users
+        verb: POST
+`,
+	}
+
+	testDir, err := initTestFiles(files)
+	require.NoError(t, err)
+
+	t.Cleanup(func() {
+		os.RemoveAll(testDir)
+	})
+
+	mocks := map[string]yaml.Node{}
+	require.NoError(t, yaml.Unmarshal([]byte(`
+fetch_user:
+  mock_response:
+    json_content:
+      name: "foo \"bar\""
+    metadata:
+      http_status_code: "200"
+tag_user:
+  mock_response:
+    metadata:
+      tag: first
+/pipeline/processors/2:
+  mock_response:
+    error: service unavailable
+`), &mocks))
+
+	provider := test.NewProcessorsProvider(filepath.Join(testDir, "config1.yaml"))
+	procs, err := provider.ProvideMocked("/pipeline/processors", nil, mocks)
+	require.NoError(t, err)
+
+	require.Len(t, procs, 3)
+
+	msgs, res := processor.ExecuteAll(procs[:2], message.New([][]byte{[]byte("starts with")}))
+	require.Nil(t, res)
+	require.Len(t, msgs, 1)
+	require.Equal(t, 1, msgs[0].Len())
+
+	part := msgs[0].Get(0)
+	assert.Equal(t, `{"name":"foo \"bar\""}`, string(part.Get()))
+	assert.Equal(t, "200", part.Metadata().Get("http_status_code"))
+	assert.Equal(t, "first", part.Metadata().Get("tag"))
+
+	msgs, res = processor.ExecuteAll(procs[2:], message.New([][]byte{[]byte("starts with")}))
+	require.Nil(t, res)
+	require.Len(t, msgs, 1)
+	assert.Contains(t, processor.GetFail(msgs[0].Get(0)), "service unavailable")
+}
+
+func TestProcessorsProviderMockResponsesErrors(t *testing.T) {
+	files := map[string]string{
+		"config1.yaml": `
+pipeline:
+  processors:
+    - label: fetch_user
+      http:
+        url: http://example.com/users
+        verb: GET
+`,
+	}
+
+	testDir, err := initTestFiles(files)
+	require.NoError(t, err)
+
+	t.Cleanup(func() {
+		os.RemoveAll(testDir)
+	})
+
+	tests := map[string]string{
+		"empty response": `
+fetch_user:
+  mock_response: {}
+`,
+		"unknown field": `
+fetch_user:
+  mock_response:
+    nope: foo
+`,
+		"error with content": `
+fetch_user:
+  mock_response:
+    content: foo
+    error: bar
+`,
+	}
+
+	for name, mocksStr := range tests {
+		mocksStr := mocksStr
+		t.Run(name, func(t *testing.T) {
+			mocks := map[string]yaml.Node{}
+			require.NoError(t, yaml.Unmarshal([]byte(mocksStr), &mocks))
+
+			provider := test.NewProcessorsProvider(filepath.Join(testDir, "config1.yaml"))
+			_, err := provider.ProvideMocked("/pipeline/processors", nil, mocks)
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), "failed to resolve mock 'fetch_user'")
+		})
+	}
+}
+
 func TestProcessorsExtraResources(t *testing.T) {
 	files := map[string]string{
 		"resources1.yaml": `
@@ -622,3 +740,40 @@ pipeline:
 	_, err = provider.Provide("/pipeline/processors", nil)
 	require.EqualError(t, err, "failed to initialise resources: cache resource label 'barcache' collides with a previously defined resource")
 }
+
+func TestProcessorsProviderMappingDir(t *testing.T) {
+	files := map[string]string{
+		"nested/config1.yaml": `
+pipeline:
+  processors:
+    - bloblang: 'root = content().string() + file(path: "suffix.txt", relative_to_mapping: true).string()'
+    - branch:
+        processors:
+          - bloblang: 'root = "ignored"'
+        result_map: 'root.suffix = file(path: "suffix.txt", relative_to_mapping: true).string()'
+`,
+		"nested/suffix.txt": " with suffix",
+	}
+
+	testDir, err := initTestFiles(files)
+	require.NoError(t, err)
+
+	t.Cleanup(func() {
+		os.RemoveAll(testDir)
+	})
+
+	provider := test.NewProcessorsProvider(filepath.Join(testDir, "nested", "config1.yaml"))
+	procs, err := provider.Provide("/pipeline/processors", nil)
+	require.NoError(t, err)
+	require.Len(t, procs, 2)
+
+	msgs, res := processor.ExecuteAll(procs[:1], message.New([][]byte{[]byte("starts")}))
+	require.Nil(t, res)
+	require.Len(t, msgs, 1)
+	assert.Equal(t, "starts with suffix", string(msgs[0].Get(0).Get()))
+
+	msgs, res = processor.ExecuteAll(procs[1:], message.New([][]byte{[]byte(`{}`)}))
+	require.Nil(t, res)
+	require.Len(t, msgs, 1)
+	assert.Equal(t, `{"suffix":" with suffix"}`, string(msgs[0].Get(0).Get()))
+}
@@ -0,0 +1,216 @@
+package test
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+)
+
+// Formats that test results can be reported in.
+const (
+	formatText  = "text"
+	formatJUnit = "junit"
+	formatTAP   = "tap"
+)
+
+func printFailures(results []targetResult) {
+	var fails []targetResult
+	for _, r := range results {
+		if r.failed() {
+			fails = append(fails, r)
+		}
+	}
+	if len(fails) == 0 {
+		return
+	}
+
+	fmt.Printf("\nFailures:\n\n")
+	for i, fail := range fails {
+		if i > 0 {
+			fmt.Println("")
+		}
+		fmt.Printf("--- %v ---\n\n", fail.target)
+		for _, lint := range fail.lints {
+			fmt.Printf("Lint: %v\n", lint)
+		}
+		caseFails := caseResultFailures(fail.cases)
+		if len(caseFails) > 0 {
+			if len(fail.lints) > 0 {
+				fmt.Println("")
+			}
+			var namePrev string
+			for i, fail := range caseFails {
+				if namePrev != fail.Name {
+					if i > 0 {
+						fmt.Println("")
+					}
+					fmt.Printf("%v [line %v]:\n", fail.Name, fail.TestLine)
+					namePrev = fail.Name
+				}
+				fmt.Println(fail.Reason)
+			}
+		}
+	}
+}
+
+//------------------------------------------------------------------------------
+
+type junitTestSuites struct {
+	XMLName  xml.Name         `xml:"testsuites"`
+	Tests    int              `xml:"tests,attr"`
+	Failures int              `xml:"failures,attr"`
+	Time     string           `xml:"time,attr"`
+	Suites   []junitTestSuite `xml:"testsuite"`
+}
+
+type junitTestSuite struct {
+	Name     string          `xml:"name,attr"`
+	Tests    int             `xml:"tests,attr"`
+	Failures int             `xml:"failures,attr"`
+	Time     string          `xml:"time,attr"`
+	Cases    []junitTestCase `xml:"testcase"`
+}
+
+type junitTestCase struct {
+	Name      string        `xml:"name,attr"`
+	ClassName string        `xml:"classname,attr"`
+	File      string        `xml:"file,attr"`
+	Line      int           `xml:"line,attr,omitempty"`
+	Time      string        `xml:"time,attr"`
+	Failure   *junitFailure `xml:"failure,omitempty"`
+}
+
+type junitFailure struct {
+	Message  string `xml:"message,attr"`
+	Contents string `xml:",chardata"`
+}
+
+func junitSeconds(d time.Duration) string {
+	return fmt.Sprintf("%.3f", d.Seconds())
+}
+
+// printJUnit writes test results as a JUnit XML report, where each target is a
+// test suite and lints of a target are reported as a failed test case.
+func printJUnit(w io.Writer, results []targetResult) error {
+	var suites junitTestSuites
+	var totalTime time.Duration
+	for _, r := range results {
+		suite := junitTestSuite{Name: r.target}
+		var suiteTime time.Duration
+
+		if len(r.lints) > 0 {
+			suite.Cases = append(suite.Cases, junitTestCase{
+				Name:      "lint",
+				ClassName: r.target,
+				File:      r.target,
+				Time:      junitSeconds(0),
+				Failure: &junitFailure{
+					Message:  fmt.Sprintf("%v linting errors", len(r.lints)),
+					Contents: strings.Join(r.lints, "\n"),
+				},
+			})
+		}
+
+		for _, c := range r.cases {
+			tc := junitTestCase{
+				Name:      c.Name,
+				ClassName: r.target,
+				File:      r.target,
+				Line:      c.TestLine,
+				Time:      junitSeconds(c.Duration),
+			}
+			if len(c.Failures) > 0 {
+				reasons := make([]string, len(c.Failures))
+				for i, f := range c.Failures {
+					reasons[i] = f.Reason
+				}
+				tc.Failure = &junitFailure{
+					Message:  fmt.Sprintf("%v failed assertions", len(c.Failures)),
+					Contents: strings.Join(reasons, "\n"),
+				}
+			}
+			suite.Cases = append(suite.Cases, tc)
+			suiteTime += c.Duration
+		}
+
+		for _, c := range suite.Cases {
+			if c.Failure != nil {
+				suite.Failures++
+			}
+		}
+		suite.Tests = len(suite.Cases)
+		suite.Time = junitSeconds(suiteTime)
+
+		suites.Tests += suite.Tests
+		suites.Failures += suite.Failures
+		suites.Suites = append(suites.Suites, suite)
+		totalTime += suiteTime
+	}
+	suites.Time = junitSeconds(totalTime)
+
+	xmlBytes, err := xml.MarshalIndent(suites, "", "  ")
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(w, "%v%s\n", xml.Header, xmlBytes)
+	return err
+}
+
+//------------------------------------------------------------------------------
+
+// printTAP writes test results in the Test Anything Protocol (version 13),
+// where each test case and the lints of each target are a test point.
+func printTAP(w io.Writer, results []targetResult) {
+	type testPoint struct {
+		desc     string
+		line     int
+		duration time.Duration
+		failures []string
+	}
+
+	var points []testPoint
+	for _, r := range results {
+		if len(r.lints) > 0 {
+			points = append(points, testPoint{
+				desc:     r.target + ": lint",
+				failures: r.lints,
+			})
+		}
+		for _, c := range r.cases {
+			p := testPoint{
+				desc:     r.target + ": " + c.Name,
+				line:     c.TestLine,
+				duration: c.Duration,
+			}
+			for _, f := range c.Failures {
+				p.failures = append(p.failures, f.Reason)
+			}
+			points = append(points, p)
+		}
+	}
+
+	fmt.Fprintln(w, "TAP version 13")
+	fmt.Fprintf(w, "1..%v\n", len(points))
+	for i, p := range points {
+		// Hashes within the description would otherwise begin a directive.
+		desc := strings.ReplaceAll(p.desc, "#", "\\#")
+		if len(p.failures) == 0 {
+			fmt.Fprintf(w, "ok %v - %v # time=%.3fms\n", i+1, desc, float64(p.duration)/float64(time.Millisecond))
+			continue
+		}
+		fmt.Fprintf(w, "not ok %v - %v # time=%.3fms\n", i+1, desc, float64(p.duration)/float64(time.Millisecond))
+		fmt.Fprintln(w, "  ---")
+		if p.line > 0 {
+			fmt.Fprintf(w, "  line: %v\n", p.line)
+		}
+		fmt.Fprintln(w, "  message: |")
+		for _, f := range p.failures {
+			for _, l := range strings.Split(f, "\n") {
+				fmt.Fprintf(w, "    %v\n", l)
+			}
+		}
+		fmt.Fprintln(w, "  ...")
+	}
+}
@@ -0,0 +1,75 @@
+package test
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testReportResults() []targetResult {
+	return []targetResult{
+		{
+			target: "foo.yaml",
+			cases: []CaseResult{
+				{Name: "passes", TestLine: 3, Duration: 1500 * time.Microsecond},
+				{
+					Name: "fails", TestLine: 12, Duration: 2 * time.Millisecond,
+					Failures: []CaseFailure{
+						{Name: "fails", TestLine: 12, Reason: "batch 0 message 0: content mismatch\n  expected: bar\n  received: baz"},
+					},
+				},
+			},
+		},
+		{
+			target: "bar.yaml",
+			lints:  []string{"line 4: field nope not recognised"},
+		},
+	}
+}
+
+func TestPrintJUnit(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, printJUnit(&buf, testReportResults()))
+
+	assert.Equal(t, `<?xml version="1.0" encoding="UTF-8"?>
+<testsuites tests="3" failures="2" time="0.004">
+  <testsuite name="foo.yaml" tests="2" failures="1" time="0.004">
+    <testcase name="passes" classname="foo.yaml" file="foo.yaml" line="3" time="0.002"></testcase>
+    <testcase name="fails" classname="foo.yaml" file="foo.yaml" line="12" time="0.002">
+      <failure message="1 failed assertions">batch 0 message 0: content mismatch&#xA;  expected: bar&#xA;  received: baz</failure>
+    </testcase>
+  </testsuite>
+  <testsuite name="bar.yaml" tests="1" failures="1" time="0.000">
+    <testcase name="lint" classname="bar.yaml" file="bar.yaml" time="0.000">
+      <failure message="1 linting errors">line 4: field nope not recognised</failure>
+    </testcase>
+  </testsuite>
+</testsuites>
+`, buf.String())
+}
+
+func TestPrintTAP(t *testing.T) {
+	var buf bytes.Buffer
+	printTAP(&buf, testReportResults())
+
+	assert.Equal(t, `TAP version 13
+1..3
+ok 1 - foo.yaml: passes # time=1.500ms
+not ok 2 - foo.yaml: fails # time=2.000ms
+  ---
+  line: 12
+  message: |
+    batch 0 message 0: content mismatch
+      expected: bar
+      received: baz
+  ...
+not ok 3 - bar.yaml: lint # time=0.000ms
+  ---
+  message: |
+    line 4: field nope not recognised
+  ...
+`, buf.String())
+}
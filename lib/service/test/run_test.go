@@ -0,0 +1,131 @@
+package test
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"testing"
+
+	"github.com/Jeffail/benthos/v3/lib/log"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFilterTargets(t *testing.T) {
+	targets := map[string]Definition{
+		"foo.yaml": {Cases: []Case{{Name: "foo first"}, {Name: "foo second"}}},
+		"bar.yaml": {Cases: []Case{{Name: "bar first"}}},
+	}
+
+	assert.Equal(t, targets, filterTargets(targets, nil))
+
+	assert.Equal(t, map[string]Definition{
+		"foo.yaml": {Cases: []Case{{Name: "foo second"}}},
+	}, filterTargets(targets, regexp.MustCompile("second")))
+
+	assert.Equal(t, map[string]Definition{
+		"foo.yaml": {Cases: []Case{{Name: "foo first"}}},
+		"bar.yaml": {Cases: []Case{{Name: "bar first"}}},
+	}, filterTargets(targets, regexp.MustCompile("^[a-z]+ first$")))
+
+	assert.Empty(t, filterTargets(targets, regexp.MustCompile("nope")))
+}
+
+func TestRunAllParallel(t *testing.T) {
+	dir := t.TempDir()
+
+	for i := 0; i < 10; i++ {
+		conf := fmt.Sprintf(`
+pipeline:
+  processors:
+    - bloblang: 'root = content().uppercase() + "${SUFFIX:%v}"'
+
+tests:
+  - name: default suffix
+    input_batch: [ { content: foo } ]
+    output_batches: [ [ { content_equals: FOO%v } ] ]
+`, i, i)
+		if i%3 == 0 {
+			conf += fmt.Sprintf(`
+  - name: custom suffix
+    environment:
+      SUFFIX: custom%v
+    input_batch: [ { content: foo } ]
+    output_batches: [ [ { content_equals: FOOcustom%v } ] ]
+`, i, i)
+		}
+		require.NoError(t, os.WriteFile(filepath.Join(dir, fmt.Sprintf("conf%v.yaml", i)), []byte(conf), 0644))
+	}
+
+	opts := newRunOptions("_benthos_test", false, log.Noop())
+	opts.parallel = 4
+	assert.True(t, runAll([]string{dir}, opts))
+
+	opts.filter = regexp.MustCompile("custom")
+	assert.True(t, runAll([]string{dir}, opts))
+
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "conf5.yaml"), []byte(`
+pipeline:
+  processors:
+    - bloblang: 'root = content().uppercase()'
+
+tests:
+  - name: default suffix
+    input_batch: [ { content: foo } ]
+    output_batches: [ [ { content_equals: bar } ] ]
+`), 0644))
+
+	opts.filter = nil
+	assert.False(t, runAll([]string{dir}, opts))
+
+	// The failing case is filtered out.
+	opts.filter = regexp.MustCompile("custom")
+	assert.True(t, runAll([]string{dir}, opts))
+}
+
+func TestRunAllConstants(t *testing.T) {
+	dir := t.TempDir()
+
+	for i := 0; i < 10; i++ {
+		require.NoError(t, os.WriteFile(filepath.Join(dir, fmt.Sprintf("conf%v.yaml", i)), []byte(fmt.Sprintf(`
+constants:
+  suffix: '"${SUFFIX:%v}"'
+
+pipeline:
+  processors:
+    - bloblang: 'root = content().uppercase() + constant("suffix")'
+
+tests:
+  - name: default suffix
+    input_batch: [ { content: foo } ]
+    output_batches: [ [ { content_equals: FOO%v } ] ]
+  - name: custom suffix
+    environment:
+      SUFFIX: custom%v
+    input_batch: [ { content: foo } ]
+    output_batches: [ [ { content_equals: FOOcustom%v } ] ]
+`, i, i, i, i)), 0644))
+	}
+
+	opts := newRunOptions("_benthos_test", false, log.Noop())
+	assert.True(t, runAll([]string{dir}, opts))
+
+	opts.parallel = 4
+	assert.True(t, runAll([]string{dir}, opts))
+
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "conf5.yaml"), []byte(`
+constants:
+  suffix: '"bar"'
+
+pipeline:
+  processors:
+    - bloblang: 'root = content().uppercase() + constant("suffix")'
+
+tests:
+  - name: wrong suffix
+    input_batch: [ { content: foo } ]
+    output_batches: [ [ { content_equals: FOObaz } ] ]
+`), 0644))
+	assert.False(t, runAll([]string{dir}, opts))
+}
@@ -0,0 +1,137 @@
+package test
+
+import (
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+)
+
+// fileState describes the state of a file at the time it was last polled,
+// where a file that could not be accessed has a zero state.
+type fileState struct {
+	modTime time.Time
+	size    int64
+}
+
+func getFileState(path string) fileState {
+	info, err := os.Stat(path)
+	if err != nil {
+		return fileState{}
+	}
+	return fileState{modTime: info.ModTime(), size: info.Size()}
+}
+
+// testWatcher tracks the files that the tests of each target depend on in
+// order to detect which targets are affected by changes.
+type testWatcher struct {
+	testSuffix     string
+	resourcesPaths []string
+	states         map[string]map[string]fileState
+}
+
+func newTestWatcher(testSuffix string, resourcesPaths []string) *testWatcher {
+	return &testWatcher{
+		testSuffix:     testSuffix,
+		resourcesPaths: resourcesPaths,
+		states:         map[string]map[string]fileState{},
+	}
+}
+
+// targetFiles returns the paths of files that the tests of a target depend on,
+// which are the target itself, its test definition, any resources, and any
+// config files referenced by cases in order to obtain processors.
+func (w *testWatcher) targetFiles(target string, def Definition) []string {
+	_, definitionPath := GetPathPair(target, w.testSuffix)
+	files := []string{target, definitionPath}
+	files = append(files, w.resourcesPaths...)
+	for _, c := range def.Cases {
+		if c.TargetProcessors == "" {
+			continue
+		}
+		if u, err := url.Parse(c.TargetProcessors); err == nil && len(u.Fragment) > 0 && u.Path != "" {
+			files = append(files, filepath.Join(filepath.Dir(target), u.Path))
+		}
+	}
+	return files
+}
+
+// changedTargets returns a sorted list of targets that are either new or where
+// a file they depend on has changed since the last call.
+func (w *testWatcher) changedTargets(targets map[string]Definition) []string {
+	var changed []string
+	states := make(map[string]map[string]fileState, len(targets))
+	for target, def := range targets {
+		targetStates := map[string]fileState{}
+		for _, f := range w.targetFiles(target, def) {
+			targetStates[f] = getFileState(f)
+		}
+		states[target] = targetStates
+
+		prevStates, exists := w.states[target]
+		if !exists || len(prevStates) != len(targetStates) {
+			changed = append(changed, target)
+			continue
+		}
+		for f, s := range targetStates {
+			if prev, exists := prevStates[f]; !exists || !prev.modTime.Equal(s.modTime) || prev.size != s.size {
+				changed = append(changed, target)
+				break
+			}
+		}
+	}
+	w.states = states
+	sort.Strings(changed)
+	return changed
+}
+
+// watchAll executes the tests of a slice of paths and then continuously polls
+// for changes to the files of test targets, executing the tests of any targets
+// that are affected by a change, until the stop channel is closed.
+func watchAll(paths []string, opts runOptions, interval time.Duration, stop <-chan struct{}) {
+	watcher := newTestWatcher(opts.testSuffix, opts.resourcesPaths)
+
+	var lastErr string
+	poll := func(initial bool) {
+		targets, err := getAllTargets(paths, opts.testSuffix)
+		if err != nil {
+			// Files are often invalid part way through being edited, so errors
+			// are only reported once until they change.
+			if errStr := err.Error(); errStr != lastErr {
+				fmt.Fprintf(os.Stderr, "Failed to obtain test targets: %v\n", err)
+				lastErr = errStr
+			}
+			return
+		}
+		lastErr = ""
+		targets = filterTargets(targets, opts.filter)
+
+		changed := watcher.changedTargets(targets)
+		if len(changed) == 0 {
+			if initial {
+				fmt.Printf("%v\n", yellow("No tests were found"))
+			}
+			return
+		}
+		if !initial {
+			fmt.Printf("\nChanges detected, running %v affected test targets\n\n", len(changed))
+		}
+		runTargets(targets, changed, opts)
+		fmt.Printf("\n%v\n", blue("Watching for changes..."))
+	}
+
+	poll(true)
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			poll(false)
+		case <-stop:
+			return
+		}
+	}
+}
@@ -0,0 +1,59 @@
+package test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTestWatcherChangedTargets(t *testing.T) {
+	dir := t.TempDir()
+
+	files := map[string]string{
+		"foo.yaml":              `pipeline: { processors: [ { bloblang: 'root = "foo"' } ] }`,
+		"foo_benthos_test.yaml": `tests: [ { name: foo, input_batch: [ { content: a } ] } ]`,
+		"bar.yaml":              `pipeline: { processors: [] }`,
+		"bar_benthos_test.yaml": `tests: [ { name: bar, target_processors: './shared.yaml#/pipeline/processors', input_batch: [ { content: a } ] } ]`,
+		"shared.yaml":           `pipeline: { processors: [ { bloblang: 'root = "bar"' } ] }`,
+	}
+	for k, v := range files {
+		require.NoError(t, os.WriteFile(filepath.Join(dir, k), []byte(v), 0644))
+	}
+
+	fooPath, barPath := filepath.Join(dir, "foo.yaml"), filepath.Join(dir, "bar.yaml")
+
+	getTargets := func() map[string]Definition {
+		t.Helper()
+		targets, err := getAllTargets([]string{dir}, "_benthos_test")
+		require.NoError(t, err)
+		return targets
+	}
+
+	touch := func(name string, offset time.Duration) {
+		t.Helper()
+		modTime := time.Now().Add(offset)
+		require.NoError(t, os.Chtimes(filepath.Join(dir, name), modTime, modTime))
+	}
+
+	w := newTestWatcher("_benthos_test", nil)
+
+	assert.Equal(t, []string{barPath, fooPath}, w.changedTargets(getTargets()))
+	assert.Empty(t, w.changedTargets(getTargets()))
+
+	touch("foo_benthos_test.yaml", time.Hour)
+	assert.Equal(t, []string{fooPath}, w.changedTargets(getTargets()))
+	assert.Empty(t, w.changedTargets(getTargets()))
+
+	touch("shared.yaml", 2*time.Hour)
+	assert.Equal(t, []string{barPath}, w.changedTargets(getTargets()))
+
+	require.NoError(t, os.Remove(filepath.Join(dir, "bar_benthos_test.yaml")))
+	assert.Empty(t, w.changedTargets(getTargets()))
+
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "bar_benthos_test.yaml"), []byte(files["bar_benthos_test.yaml"]), 0644))
+	assert.Equal(t, []string{barPath}, w.changedTargets(getTargets()))
+}
@@ -0,0 +1,106 @@
+package service
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"runtime"
+	"runtime/debug"
+	"sort"
+
+	"github.com/Jeffail/benthos/v3/internal/bundle"
+	"github.com/Jeffail/benthos/v3/internal/docs"
+	"github.com/Jeffail/benthos/v3/lib/config"
+	uconfig "github.com/Jeffail/benthos/v3/lib/util/config"
+	"gopkg.in/yaml.v3"
+)
+
+//------------------------------------------------------------------------------
+
+// resolveVersion returns the version and date built stamps of the binary,
+// falling back to the version of the Benthos module when it is a dependency
+// of a custom build.
+func resolveVersion() (version, dateBuilt string) {
+	version, dateBuilt = Version, DateBuilt
+	if version == "" {
+		info, ok := debug.ReadBuildInfo()
+		if ok {
+			for _, mod := range info.Deps {
+				if mod.Path == "github.com/Jeffail/benthos/v3" {
+					version = mod.Version
+				}
+			}
+		}
+	}
+	return
+}
+
+// pluginNames returns the names of all registered plugin components, sorted
+// and keyed by their component type.
+func pluginNames() map[string][]string {
+	plugins := map[string][]string{}
+	for t, specs := range map[string][]docs.ComponentSpec{
+		"buffers":     bundle.AllBuffers.Docs(),
+		"caches":      bundle.AllCaches.Docs(),
+		"inputs":      bundle.AllInputs.Docs(),
+		"outputs":     bundle.AllOutputs.Docs(),
+		"processors":  bundle.AllProcessors.Docs(),
+		"rate-limits": bundle.AllRateLimits.Docs(),
+		"metrics":     bundle.AllMetrics.Docs(),
+		"tracers":     bundle.AllTracers.Docs(),
+	} {
+		for _, spec := range specs {
+			if spec.Plugin {
+				plugins[t] = append(plugins[t], spec.Name)
+			}
+		}
+		sort.Strings(plugins[t])
+	}
+	for t, names := range plugins {
+		if len(names) == 0 {
+			delete(plugins, t)
+		}
+	}
+	return plugins
+}
+
+func sortedKeys(m map[string][]string) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// configHash returns a SHA-256 hash of the normalised form of the loaded
+// config, with the values of secret fields removed. This must be called before
+// the config is used to construct components, as some of them modify it.
+func configHash() (string, error) {
+	var node yaml.Node
+	if err := node.Encode(conf); err != nil {
+		return "", err
+	}
+	if err := config.Spec().SanitiseYAML(&node, docs.SanitiseConfig{
+		RemoveTypeField: true,
+		ScrubSecrets:    true,
+	}); err != nil {
+		return "", err
+	}
+	redactSecrets(&node)
+
+	confBytes, err := uconfig.MarshalYAML(node)
+	if err != nil {
+		return "", err
+	}
+	hash := sha256.Sum256(confBytes)
+	return hex.EncodeToString(hash[:]), nil
+}
+
+// versionInfo returns the build information added to the /version endpoint.
+func versionInfo(confHash string) map[string]interface{} {
+	return map[string]interface{}{
+		"go_version":  runtime.Version(),
+		"plugins":     pluginNames(),
+		"config_hash": confHash,
+	}
+}
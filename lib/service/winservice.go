@@ -0,0 +1,190 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/urfave/cli/v2"
+)
+
+//------------------------------------------------------------------------------
+
+var errWindowsServiceUnsupported = errors.New("windows services are only supported on Windows")
+
+// winServicePathFlags are the root flags that refer to paths, which are made
+// absolute when a service is installed as services are started within the
+// system directory.
+var winServicePathFlags = []string{"config", "resources", "templates"}
+
+// winServiceArgs returns the root flags that a service should be run with,
+// reconstructed from the flags that were set when it was installed.
+func winServiceArgs(c *cli.Context) ([]string, error) {
+	var args []string
+	for _, name := range winServicePathFlags {
+		for _, p := range c.StringSlice(name) {
+			absPath, err := filepath.Abs(p)
+			if err != nil {
+				return nil, fmt.Errorf("failed to resolve %v path '%v': %w", name, p, err)
+			}
+			args = append(args, "--"+name, absPath)
+		}
+	}
+	for _, name := range []string{"env-file", "lint-rules", "failure-report"} {
+		v := c.String(name)
+		if v == "" {
+			continue
+		}
+		if v != "stderr" {
+			absPath, err := filepath.Abs(v)
+			if err != nil {
+				return nil, fmt.Errorf("failed to resolve %v path '%v': %w", name, v, err)
+			}
+			v = absPath
+		}
+		args = append(args, "--"+name, v)
+	}
+	if v := c.String("log.level"); v != "" {
+		args = append(args, "--log.level", v)
+	}
+	for _, v := range c.StringSlice("set") {
+		args = append(args, "--set", v)
+	}
+	for _, name := range []string{"chilled", "watch"} {
+		if c.Bool(name) {
+			args = append(args, "--"+name)
+		}
+	}
+	return args, nil
+}
+
+func winServiceCliCommand() *cli.Command {
+	nameFlag := &cli.StringFlag{
+		Name:    "name",
+		Aliases: []string{"n"},
+		Value:   "benthos",
+		Usage:   "The name of the service.",
+	}
+	logFileFlag := &cli.StringFlag{
+		Name:  "log-file",
+		Value: "",
+		Usage: "A file to append the logs and output of the service to.",
+	}
+	return &cli.Command{
+		Name:  "service",
+		Usage: "Install, remove and run Benthos as a Windows service",
+		Description: `
+   Registers Benthos as a native Windows service that is started and stopped by
+   the service control manager, which allows Benthos to run in the background
+   and start with the system without any third party wrappers. The flags
+   provided to Benthos when installing, such as config paths, are used each
+   time the service is run:
+
+   benthos -c ./config.yaml service install --name benthos
+   sc.exe start benthos
+   benthos service uninstall --name benthos
+
+   When the service is stopped, or the system is shutting down, the pipeline is
+   drained gracefully within the shutdown_timeout of the config. Services have
+   no console, and therefore logs are discarded unless a file is specified with
+   the --log-file flag:
+
+   benthos -c ./config.yaml service install --log-file ./benthos.log`[4:],
+		Subcommands: []*cli.Command{
+			{
+				Name:  "install",
+				Usage: "Install Benthos as a Windows service",
+				Flags: []cli.Flag{
+					nameFlag,
+					logFileFlag,
+					&cli.StringFlag{
+						Name:  "display-name",
+						Value: "Benthos",
+						Usage: "The display name of the service.",
+					},
+					&cli.StringFlag{
+						Name:  "description",
+						Value: "A stream processor for mundane tasks.",
+						Usage: "A description of the service.",
+					},
+					&cli.BoolFlag{
+						Name:  "manual",
+						Value: false,
+						Usage: "Require the service to be started manually rather than with the system.",
+					},
+				},
+				Action: func(c *cli.Context) error {
+					args, err := winServiceArgs(c)
+					var logPath string
+					if err == nil && c.String("log-file") != "" {
+						logPath, err = filepath.Abs(c.String("log-file"))
+					}
+					if err == nil {
+						err = installWindowsService(winServiceConfig{
+							name:        c.String("name"),
+							displayName: c.String("display-name"),
+							description: c.String("description"),
+							manualStart: c.Bool("manual"),
+							logFile:     logPath,
+							args:        args,
+						})
+					}
+					if err != nil {
+						fmt.Fprintf(os.Stderr, "Service install error: %v\n", err)
+						os.Exit(1)
+					}
+					fmt.Printf("Installed service '%v'.\n", c.String("name"))
+					return nil
+				},
+			},
+			{
+				Name:  "uninstall",
+				Usage: "Remove a Windows service installed by Benthos",
+				Flags: []cli.Flag{nameFlag},
+				Action: func(c *cli.Context) error {
+					if err := uninstallWindowsService(c.String("name")); err != nil {
+						fmt.Fprintf(os.Stderr, "Service uninstall error: %v\n", err)
+						os.Exit(1)
+					}
+					fmt.Printf("Removed service '%v'.\n", c.String("name"))
+					return nil
+				},
+			},
+			{
+				Name:  "run",
+				Usage: "Run Benthos as a Windows service, which is called by the service control manager",
+				Flags: []cli.Flag{nameFlag, logFileFlag},
+				Action: func(c *cli.Context) error {
+					exitCode, err := runWindowsService(c.String("name"), func() int {
+						if logPath := c.String("log-file"); logPath != "" {
+							logFile, err := os.OpenFile(logPath, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+							if err != nil {
+								return 1
+							}
+							defer logFile.Close()
+							os.Stdout, os.Stderr = logFile, logFile
+						}
+						return cmdRun(c)
+					})
+					if err != nil {
+						fmt.Fprintf(os.Stderr, "Service run error: %v\n", err)
+						os.Exit(1)
+					}
+					os.Exit(exitCode)
+					return nil
+				},
+			},
+		},
+	}
+}
+
+// winServiceConfig describes a Windows service to install.
+type winServiceConfig struct {
+	name        string
+	displayName string
+	description string
+	manualStart bool
+	logFile     string
+	args        []string
+}
@@ -0,0 +1,16 @@
+//go:build !windows
+// +build !windows
+
+package service
+
+func installWindowsService(conf winServiceConfig) error {
+	return errWindowsServiceUnsupported
+}
+
+func uninstallWindowsService(name string) error {
+	return errWindowsServiceUnsupported
+}
+
+func runWindowsService(name string, run func() int) (int, error) {
+	return 1, errWindowsServiceUnsupported
+}
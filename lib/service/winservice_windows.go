@@ -0,0 +1,149 @@
+//go:build windows
+// +build windows
+
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"time"
+
+	"golang.org/x/sys/windows/svc"
+	"golang.org/x/sys/windows/svc/mgr"
+)
+
+//------------------------------------------------------------------------------
+
+func installWindowsService(conf winServiceConfig) error {
+	exePath, err := os.Executable()
+	if err != nil {
+		return fmt.Errorf("failed to resolve path of executable: %w", err)
+	}
+
+	m, err := mgr.Connect()
+	if err != nil {
+		return fmt.Errorf("failed to connect to service manager: %w", err)
+	}
+	defer m.Disconnect()
+
+	if s, err := m.OpenService(conf.name); err == nil {
+		s.Close()
+		return fmt.Errorf("service '%v' already exists", conf.name)
+	}
+
+	startType := uint32(mgr.StartAutomatic)
+	if conf.manualStart {
+		startType = mgr.StartManual
+	}
+	args := append(conf.args, "service", "run", "--name", conf.name)
+	if conf.logFile != "" {
+		args = append(args, "--log-file", conf.logFile)
+	}
+	s, err := m.CreateService(conf.name, exePath, mgr.Config{
+		DisplayName: conf.displayName,
+		Description: conf.description,
+		StartType:   startType,
+	}, args...)
+	if err != nil {
+		return fmt.Errorf("failed to create service: %w", err)
+	}
+	defer s.Close()
+
+	// Restart the service when it fails, with the failure count reset after a
+	// day without failures.
+	if err = s.SetRecoveryActions([]mgr.RecoveryAction{
+		{Type: mgr.ServiceRestart, Delay: 5 * time.Second},
+		{Type: mgr.ServiceRestart, Delay: 30 * time.Second},
+		{Type: mgr.ServiceRestart, Delay: time.Minute},
+	}, uint32((24 * time.Hour).Seconds())); err != nil {
+		s.Delete()
+		return fmt.Errorf("failed to set recovery actions of service: %w", err)
+	}
+	return nil
+}
+
+func uninstallWindowsService(name string) error {
+	m, err := mgr.Connect()
+	if err != nil {
+		return fmt.Errorf("failed to connect to service manager: %w", err)
+	}
+	defer m.Disconnect()
+
+	s, err := m.OpenService(name)
+	if err != nil {
+		return fmt.Errorf("service '%v' is not installed: %w", name, err)
+	}
+	defer s.Close()
+
+	if err = s.Delete(); err != nil {
+		return fmt.Errorf("failed to delete service: %w", err)
+	}
+	return nil
+}
+
+func runWindowsService(name string, run func() int) (int, error) {
+	isService, err := svc.IsWindowsService()
+	if err != nil {
+		return 1, fmt.Errorf("failed to determine whether running as a service: %w", err)
+	}
+	if !isService {
+		return 1, errors.New("not running as a service, the service should be started with the service control manager, e.g. sc.exe start " + name)
+	}
+
+	ctx, cancel := context.WithCancel(optContext)
+	defer cancel()
+	optContext = ctx
+
+	h := &winServiceHandler{run: run, stop: cancel}
+	if err := svc.Run(name, h); err != nil {
+		return 1, err
+	}
+	return h.exitCode, nil
+}
+
+//------------------------------------------------------------------------------
+
+// winServiceHandler runs the service in response to the service control
+// manager, where stop and shutdown requests cancel the run context of the
+// service in order to drain the pipeline gracefully.
+type winServiceHandler struct {
+	run      func() int
+	stop     func()
+	exitCode int
+}
+
+func (h *winServiceHandler) Execute(args []string, reqs <-chan svc.ChangeRequest, status chan<- svc.Status) (bool, uint32) {
+	status <- svc.Status{State: svc.StartPending}
+
+	exitChan := make(chan int, 1)
+	go func() {
+		exitChan <- h.run()
+	}()
+	status <- svc.Status{
+		State:   svc.Running,
+		Accepts: svc.AcceptStop | svc.AcceptShutdown,
+	}
+
+	for {
+		select {
+		case h.exitCode = <-exitChan:
+			status <- svc.Status{State: svc.StopPending}
+			if h.exitCode != 0 {
+				// Report a service specific exit code so that the recovery
+				// actions of the service are triggered.
+				return true, uint32(h.exitCode)
+			}
+			return false, 0
+		case req := <-reqs:
+			switch req.Cmd {
+			case svc.Interrogate:
+				status <- req.CurrentStatus
+			case svc.Stop, svc.Shutdown:
+				status <- svc.Status{State: svc.StopPending}
+				h.stop()
+			}
+		}
+	}
+}
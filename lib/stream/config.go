@@ -18,6 +18,7 @@ type Config struct {
 	Buffer   buffer.Config   `json:"buffer" yaml:"buffer"`
 	Pipeline pipeline.Config `json:"pipeline" yaml:"pipeline"`
 	Output   output.Config   `json:"output" yaml:"output"`
+	Drain    DrainConfig     `json:"drain" yaml:"drain"`
 }
 
 // NewConfig returns a new configuration with default values.
@@ -27,12 +28,27 @@ func NewConfig() Config {
 		Buffer:   buffer.NewConfig(),
 		Pipeline: pipeline.NewConfig(),
 		Output:   output.NewConfig(),
+		Drain:    NewDrainConfig(),
+	}
+}
+
+// DrainConfig contains configuration fields for how a stream is shut down.
+type DrainConfig struct {
+	Timeout string `json:"timeout" yaml:"timeout"`
+	Ordered bool   `json:"ordered" yaml:"ordered"`
+}
+
+// NewDrainConfig returns a DrainConfig with default values.
+func NewDrainConfig() DrainConfig {
+	return DrainConfig{
+		Timeout: "",
+		Ordered: true,
 	}
 }
 
 // Sanitised returns a sanitised copy of the Benthos configuration, meaning
 // fields of no consequence (unused inputs, outputs, processors etc) are
-// excluded.
+// excluded, and the values of secret fields are scrubbed.
 func (c Config) Sanitised() (interface{}, error) {
 	var node yaml.Node
 	if err := node.Encode(c); err != nil {
@@ -41,6 +57,7 @@ func (c Config) Sanitised() (interface{}, error) {
 
 	if err := Spec().SanitiseYAML(&node, docs.SanitiseConfig{
 		RemoveTypeField: true,
+		ScrubSecrets:    true,
 	}); err != nil {
 		return nil, err
 	}
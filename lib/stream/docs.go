@@ -14,5 +14,9 @@ func Spec() docs.FieldSpecs {
 			docs.FieldCommon("processors", "A list of processors to apply to messages.").Array().HasType(docs.FieldTypeProcessor),
 		),
 		docs.FieldCommon("output", "An output to sink messages to.").HasType(docs.FieldTypeOutput),
+		docs.FieldAdvanced("drain", "Describes how the stream is shut down.").WithChildren(
+			docs.FieldString("timeout", "The maximum period of time to wait, once the input has stopped consuming, for in-flight and buffered messages to be delivered and acknowledged before the remaining components are closed forcefully. When empty three quarters of the shutdown timeout is used. This period is capped by the shutdown timeout.", "10s", "1m").HasDefault(""),
+			docs.FieldBool("ordered", "Whether to stop components in order, where the input stops consuming first, in-flight messages are delivered and acknowledged, and then the output is closed. When disabled all components are closed at once, which is faster but causes in-flight messages to be rejected, and therefore redelivered by inputs that support it.").HasDefault(true),
+		),
 	}
 }
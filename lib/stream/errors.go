@@ -0,0 +1,26 @@
+package stream
+
+//------------------------------------------------------------------------------
+
+// ComponentError is returned when a component of a stream fails to be created,
+// and identifies the path of the component within the stream config.
+type ComponentError struct {
+	Path string
+	Err  error
+}
+
+func (e *ComponentError) Error() string {
+	return e.Err.Error()
+}
+
+// Unwrap returns the underlying error.
+func (e *ComponentError) Unwrap() error {
+	return e.Err
+}
+
+func wrapComponentErr(path string, err error) error {
+	if err == nil {
+		return nil
+	}
+	return &ComponentError{Path: path, Err: err}
+}
@@ -0,0 +1,136 @@
+package stream
+
+import (
+	"errors"
+	"sync"
+
+	"github.com/Jeffail/benthos/v3/lib/types"
+)
+
+//------------------------------------------------------------------------------
+
+// ErrComponentNotFound is returned when attempting to pause or resume a
+// component by a label that does not exist within a stream.
+var ErrComponentNotFound = errors.New("component not found")
+
+// ComponentState describes a component of a stream that can be paused.
+type ComponentState struct {
+	Label  string `json:"label"`
+	Kind   string `json:"kind"`
+	Paused bool   `json:"paused"`
+}
+
+// gate relays transactions between two layers of a stream, and can be paused
+// in order to hold back transactions until it is resumed.
+type gate struct {
+	label string
+	kind  string
+
+	mut     sync.Mutex
+	paused  bool
+	resumed chan struct{}
+	stopped chan struct{}
+	closed  bool
+}
+
+func newGate(label, kind string) *gate {
+	g := &gate{
+		label:   label,
+		kind:    kind,
+		resumed: make(chan struct{}),
+		stopped: make(chan struct{}),
+	}
+	close(g.resumed)
+	return g
+}
+
+func (g *gate) state() ComponentState {
+	g.mut.Lock()
+	defer g.mut.Unlock()
+	return ComponentState{Label: g.label, Kind: g.kind, Paused: g.paused}
+}
+
+func (g *gate) pause() {
+	g.mut.Lock()
+	defer g.mut.Unlock()
+	if g.paused || g.closed {
+		return
+	}
+	g.paused = true
+	g.resumed = make(chan struct{})
+}
+
+func (g *gate) resume() {
+	g.mut.Lock()
+	defer g.mut.Unlock()
+	if g.paused {
+		g.paused = false
+		close(g.resumed)
+	}
+}
+
+// release resumes the gate permanently, which allows a paused stream to be
+// shut down gracefully.
+func (g *gate) release() {
+	g.mut.Lock()
+	g.closed = true
+	g.mut.Unlock()
+	g.resume()
+}
+
+// stop terminates the relay of the gate regardless of pending transactions.
+func (g *gate) stop() {
+	g.release()
+	g.mut.Lock()
+	defer g.mut.Unlock()
+	select {
+	case <-g.stopped:
+	default:
+		close(g.stopped)
+	}
+}
+
+func (g *gate) wait() bool {
+	g.mut.Lock()
+	resumed := g.resumed
+	g.mut.Unlock()
+	select {
+	case <-resumed:
+		return true
+	case <-g.stopped:
+		return false
+	}
+}
+
+func (g *gate) relay(in <-chan types.Transaction) <-chan types.Transaction {
+	out := make(chan types.Transaction)
+	go func() {
+		defer close(out)
+		for {
+			if !g.wait() {
+				return
+			}
+			var tran types.Transaction
+			var open bool
+			select {
+			case tran, open = <-in:
+				if !open {
+					return
+				}
+			case <-g.stopped:
+				return
+			}
+			// A transaction read before the gate was paused is held back
+			// until it is resumed.
+			if !g.wait() {
+				return
+			}
+			select {
+			case out <- tran:
+			case <-g.stopped:
+				return
+			}
+		}
+	}()
+	return out
+}
@@ -0,0 +1,103 @@
+package stream
+
+import (
+	"testing"
+	"time"
+
+	"github.com/Jeffail/benthos/v3/lib/input"
+	"github.com/Jeffail/benthos/v3/lib/message"
+	"github.com/Jeffail/benthos/v3/lib/output"
+	"github.com/Jeffail/benthos/v3/lib/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGatePauseResume(t *testing.T) {
+	g := newGate("foo", "input")
+	in := make(chan types.Transaction)
+	out := g.relay(in)
+
+	resChan := make(chan types.Response)
+	send := func(content string) {
+		in <- types.NewTransaction(message.New([][]byte{[]byte(content)}), resChan)
+	}
+
+	go send("first")
+	select {
+	case tran := <-out:
+		assert.Equal(t, "first", string(tran.Payload.Get(0).Get()))
+	case <-time.After(time.Second):
+		t.Fatal("timed out")
+	}
+
+	g.pause()
+	assert.Equal(t, ComponentState{Label: "foo", Kind: "input", Paused: true}, g.state())
+
+	go send("second")
+	select {
+	case <-out:
+		t.Fatal("received transaction whilst paused")
+	case <-time.After(time.Millisecond * 100):
+	}
+
+	g.resume()
+	assert.False(t, g.state().Paused)
+	select {
+	case tran := <-out:
+		assert.Equal(t, "second", string(tran.Payload.Get(0).Get()))
+	case <-time.After(time.Second):
+		t.Fatal("timed out")
+	}
+
+	// A released gate cannot be paused again.
+	g.release()
+	g.pause()
+	assert.False(t, g.state().Paused)
+
+	close(in)
+	select {
+	case _, open := <-out:
+		assert.False(t, open)
+	case <-time.After(time.Second):
+		t.Fatal("timed out")
+	}
+}
+
+func TestGateStop(t *testing.T) {
+	g := newGate("foo", "output")
+	out := g.relay(make(chan types.Transaction))
+
+	g.pause()
+	g.stop()
+	g.stop()
+
+	select {
+	case _, open := <-out:
+		assert.False(t, open)
+	case <-time.After(time.Second):
+		t.Fatal("timed out")
+	}
+}
+
+func TestTypePauseByLabel(t *testing.T) {
+	conf := NewConfig()
+	conf.Input.Type = input.TypeHTTPServer
+	conf.Output.Type = output.TypeHTTPServer
+	conf.Output.Label = "foo"
+
+	strm, err := New(conf)
+	require.NoError(t, err)
+
+	assert.Equal(t, []ComponentState{{Label: "foo", Kind: "output"}}, strm.ComponentStates())
+	assert.Equal(t, ErrComponentNotFound, strm.Pause("bar"))
+	assert.Equal(t, ErrComponentNotFound, strm.Resume("bar"))
+
+	require.NoError(t, strm.Pause("foo"))
+	assert.Equal(t, []ComponentState{{Label: "foo", Kind: "output", Paused: true}}, strm.ComponentStates())
+
+	require.NoError(t, strm.Resume("foo"))
+	assert.Equal(t, []ComponentState{{Label: "foo", Kind: "output"}}, strm.ComponentStates())
+
+	require.NoError(t, strm.Pause("foo"))
+	assert.NoError(t, strm.Stop(time.Minute))
+}
@@ -36,30 +36,35 @@ func (m *Type) registerEndpoints() {
 		"GET: List all streams along with their status and uptimes."+
 			" POST: Post an object of stream ids to stream configs, all"+
 			" streams will be replaced by this new set.",
-		m.HandleStreamsCRUD,
+		m.apiAuth.Wrap(m.HandleStreamsCRUD),
 	)
 	m.manager.RegisterEndpoint(
 		"/streams/{id}",
 		"Perform CRUD operations on streams, supporting POST (Create),"+
 			" GET (Read), PUT (Update), PATCH (Patch update)"+
 			" and DELETE (Delete).",
-		m.HandleStreamCRUD,
+		m.apiAuth.Wrap(m.HandleStreamCRUD),
 	)
 	m.manager.RegisterEndpoint(
 		"/streams/{id}/stats",
 		"GET a structured JSON object containing metrics for the stream.",
-		m.HandleStreamStats,
+		m.apiAuth.Wrap(m.HandleStreamStats),
 	)
 	m.manager.RegisterEndpoint(
 		"/resources/{type}/{id}",
 		"POST: Create or replace a given resource configuration of a specified type. Types supported are `cache`, `input`, `output`, `processor` and `rate_limit`.",
-		m.HandleResourceCRUD,
+		m.apiAuth.Wrap(m.HandleResourceCRUD),
 	)
 	m.manager.RegisterEndpoint(
 		"/ready",
 		"Returns 200 OK if the inputs and outputs of all running streams are connected, otherwise a 503 is returned. If there are no active streams 200 is returned.",
 		m.HandleStreamReady,
 	)
+	m.manager.RegisterEndpoint(
+		"/live",
+		"Returns 200 OK unless the input or output of a running stream has been disconnected for longer than the liveness grace period, in which case a 503 is returned.",
+		m.HandleStreamLive,
+	)
 }
 
 // ConfigSet is a map of stream configurations mapped by ID, which can be YAML
@@ -285,7 +290,9 @@ func (m *Type) HandleStreamCRUD(w http.ResponseWriter, r *http.Request) {
 		if confBytes, err = ioutil.ReadAll(r.Body); err != nil {
 			return
 		}
-		confBytes = text.ReplaceEnvVariables(confBytes)
+		if confBytes, err = text.ReplaceEnvVariablesAndSecrets(confBytes); err != nil {
+			return
+		}
 
 		if r.URL.Query().Get("chilled") != "true" {
 			var node yaml.Node
@@ -314,15 +321,17 @@ func (m *Type) HandleStreamCRUD(w http.ResponseWriter, r *http.Request) {
 		type aliasedOut output.Config
 
 		aliasedConf := struct {
-			Input    aliasedIn   `json:"input"`
-			Buffer   aliasedBuf  `json:"buffer"`
-			Pipeline aliasedPipe `json:"pipeline"`
-			Output   aliasedOut  `json:"output"`
+			Input    aliasedIn          `json:"input"`
+			Buffer   aliasedBuf         `json:"buffer"`
+			Pipeline aliasedPipe        `json:"pipeline"`
+			Output   aliasedOut         `json:"output"`
+			Drain    stream.DrainConfig `json:"drain"`
 		}{
 			Input:    aliasedIn(confIn.Input),
 			Buffer:   aliasedBuf(confIn.Buffer),
 			Pipeline: aliasedPipe(confIn.Pipeline),
 			Output:   aliasedOut(confIn.Output),
+			Drain:    confIn.Drain,
 		}
 		if err = yaml.Unmarshal(patchBytes, &aliasedConf); err != nil {
 			return
@@ -332,6 +341,7 @@ func (m *Type) HandleStreamCRUD(w http.ResponseWriter, r *http.Request) {
 			Buffer:   buffer.Config(aliasedConf.Buffer),
 			Pipeline: pipeline.Config(aliasedConf.Pipeline),
 			Output:   output.Config(aliasedConf.Output),
+			Drain:    aliasedConf.Drain,
 		}
 		return
 	}
@@ -515,7 +525,9 @@ func (m *Type) HandleResourceCRUD(w http.ResponseWriter, r *http.Request) {
 		if confBytes, requestErr = ioutil.ReadAll(r.Body); requestErr != nil {
 			return
 		}
-		confBytes = text.ReplaceEnvVariables(confBytes)
+		if confBytes, requestErr = text.ReplaceEnvVariablesAndSecrets(confBytes); requestErr != nil {
+			return
+		}
 
 		var node yaml.Node
 		if requestErr = yaml.Unmarshal(confBytes, &node); requestErr != nil {
@@ -586,6 +598,23 @@ func (m *Type) HandleStreamStats(w http.ResponseWriter, r *http.Request) {
 				obj.SetP(time.Duration(v).String(), k+"_readable")
 			}
 			obj.SetP(fmt.Sprintf("%v", uptime), "uptime")
+			obj.SetP(info.IsRunning(), "active")
+			obj.SetP(info.IsReady(), "connected")
+
+			var errCount int64
+			for k, v := range counters {
+				if strings.HasSuffix(k, ".error") {
+					errCount += v
+				}
+			}
+			obj.SetP(counters["input.received"], "messages.received")
+			obj.SetP(counters["output.sent"], "messages.sent")
+			obj.SetP(errCount, "messages.errors")
+
+			if errMsg, errAt := info.LastError(); errMsg != "" {
+				obj.SetP(errMsg, "last_error.message")
+				obj.SetP(errAt.Format(time.RFC3339), "last_error.time")
+			}
 			w.Header().Set("Content-Type", "application/json")
 			w.Write(obj.Bytes())
 		}
@@ -620,4 +649,17 @@ func (m *Type) HandleStreamReady(w http.ResponseWriter, r *http.Request) {
 	w.Write([]byte(fmt.Sprintf("streams %v are not connected\n", strings.Join(notReady, ", "))))
 }
 
+// HandleStreamLive is an http.HandleFunc for providing a liveness check across
+// all streams.
+func (m *Type) HandleStreamLive(w http.ResponseWriter, r *http.Request) {
+	notAlive := m.notAlive()
+	if len(notAlive) == 0 {
+		w.Write([]byte("OK"))
+		return
+	}
+
+	w.WriteHeader(http.StatusServiceUnavailable)
+	w.Write([]byte(fmt.Sprintf("streams %v have been disconnected for longer than the grace period\n", strings.Join(notAlive, ", "))))
+}
+
 //------------------------------------------------------------------------------
@@ -802,6 +802,65 @@ func TestTypeAPIGetStats(t *testing.T) {
 	assert.Equal(t, 1.0, stats.S("input", "running").Data(), response.Body.String())
 }
 
+func TestTypeAPIGetStatsSummary(t *testing.T) {
+	smgr := manager.New(
+		manager.OptSetLogger(log.Noop()),
+		manager.OptSetStats(metrics.Noop()),
+		manager.OptSetAPITimeout(time.Millisecond*100),
+	)
+	defer smgr.Stop(time.Second)
+
+	r := router(smgr)
+
+	conf := stream.NewConfig()
+	require.NoError(t, yaml.Unmarshal([]byte(`
+input:
+  generate:
+    count: 4
+    interval: ""
+    mapping: root = count("stats_summary_test")
+pipeline:
+  processors:
+    - bloblang: 'root = if this % 2 == 0 { throw("nope") } else { this }'
+    - catch:
+        - log:
+            level: ERROR
+            message: 'failed: ${! error() }'
+output:
+  drop: {}
+`), &conf))
+	require.NoError(t, smgr.Create("foo", conf))
+
+	assert.Eventually(t, func() bool {
+		request := genRequest("GET", "/streams/foo/stats", nil)
+		response := httptest.NewRecorder()
+		r.ServeHTTP(response, request)
+		if response.Code != http.StatusOK {
+			return false
+		}
+		stats, err := gabs.ParseJSON(response.Body.Bytes())
+		if err != nil {
+			return false
+		}
+		return stats.S("messages", "sent").Data() == 4.0
+	}, time.Second*5, time.Millisecond*50)
+
+	request := genRequest("GET", "/streams/foo/stats", nil)
+	response := httptest.NewRecorder()
+	r.ServeHTTP(response, request)
+	require.Equal(t, http.StatusOK, response.Code)
+
+	stats, err := gabs.ParseJSON(response.Body.Bytes())
+	require.NoError(t, err)
+
+	assert.Equal(t, 4.0, stats.S("messages", "received").Data(), response.Body.String())
+	assert.Equal(t, 2.0, stats.S("messages", "errors").Data(), response.Body.String())
+	assert.Equal(t, "failed: failed assignment (line 1): nope", stats.S("last_error", "message").Data(), response.Body.String())
+	assert.NotEmpty(t, stats.S("last_error", "time").Data(), response.Body.String())
+	assert.True(t, stats.Exists("active"), response.Body.String())
+	assert.True(t, stats.Exists("connected"), response.Body.String())
+}
+
 func TestTypeAPISetResources(t *testing.T) {
 	bmgr, err := bmanager.NewV2(bmanager.NewResourceConfig(), types.DudMgr{}, log.Noop(), metrics.Noop())
 	require.NoError(t, err)
@@ -0,0 +1,71 @@
+package manager
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Jeffail/benthos/v3/lib/log"
+)
+
+//------------------------------------------------------------------------------
+
+// lastError records the most recent error logged by a stream.
+type lastError struct {
+	mut     sync.Mutex
+	message string
+	at      time.Time
+}
+
+func (e *lastError) set(message string) {
+	e.mut.Lock()
+	e.message = strings.TrimSpace(message)
+	e.at = time.Now()
+	e.mut.Unlock()
+}
+
+func (e *lastError) get() (string, time.Time) {
+	e.mut.Lock()
+	defer e.mut.Unlock()
+	return e.message, e.at
+}
+
+// errorTrackingLogger wraps the logger of a stream in order to record the last
+// error or fatal message logged by any of its components.
+type errorTrackingLogger struct {
+	log.Modular
+	last *lastError
+}
+
+func trackErrors(l log.Modular, last *lastError) log.Modular {
+	return &errorTrackingLogger{Modular: l, last: last}
+}
+
+func (l *errorTrackingLogger) NewModule(prefix string) log.Modular {
+	return trackErrors(l.Modular.NewModule(prefix), l.last)
+}
+
+func (l *errorTrackingLogger) WithFields(fields map[string]string) log.Modular {
+	return trackErrors(l.Modular.WithFields(fields), l.last)
+}
+
+func (l *errorTrackingLogger) Fatalf(format string, v ...interface{}) {
+	l.last.set(fmt.Sprintf(format, v...))
+	l.Modular.Fatalf(format, v...)
+}
+
+func (l *errorTrackingLogger) Errorf(format string, v ...interface{}) {
+	l.last.set(fmt.Sprintf(format, v...))
+	l.Modular.Errorf(format, v...)
+}
+
+func (l *errorTrackingLogger) Fatalln(message string) {
+	l.last.set(message)
+	l.Modular.Fatalln(message)
+}
+
+func (l *errorTrackingLogger) Errorln(message string) {
+	l.last.set(message)
+	l.Modular.Errorln(message)
+}
@@ -0,0 +1,279 @@
+package manager
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/Jeffail/benthos/v3/internal/docs"
+	"github.com/Jeffail/benthos/v3/lib/stream"
+	uconfig "github.com/Jeffail/benthos/v3/lib/util/config"
+	"gopkg.in/yaml.v3"
+
+	// SQL drivers supported by the SQL store.
+	_ "github.com/denisenkom/go-mssqldb"
+	_ "github.com/go-sql-driver/mysql"
+	_ "github.com/lib/pq"
+)
+
+//------------------------------------------------------------------------------
+
+// Store persists the configs of streams so that they survive restarts of the
+// process.
+type Store interface {
+	// Set stores the config of a stream, replacing any existing config of
+	// the same ID.
+	Set(id string, conf stream.Config) error
+
+	// Delete removes the config of a stream, it is not an error if the
+	// stream does not exist.
+	Delete(id string) error
+
+	// Load returns the configs of all stored streams.
+	Load() (map[string]stream.Config, error)
+}
+
+// NewStore creates a store from a target, which is either a path to a directory
+// or a URL to a database with the scheme postgres, mysql or sqlserver.
+func NewStore(target string) (Store, error) {
+	if i := strings.Index(target, "://"); i > 0 {
+		switch scheme := target[:i]; scheme {
+		case "file":
+			return NewDirectoryStore(target[i+3:])
+		case "postgres", "postgresql":
+			return NewSQLStore("postgres", target)
+		case "mysql":
+			// The MySQL driver does not accept URLs.
+			return NewSQLStore("mysql", target[i+3:])
+		case "sqlserver":
+			return NewSQLStore("sqlserver", target)
+		default:
+			return nil, fmt.Errorf("store scheme not recognised: %v", scheme)
+		}
+	}
+	return NewDirectoryStore(target)
+}
+
+// ErrBadStreamID is returned when attempting to store a stream with an ID that
+// cannot be persisted.
+var ErrBadStreamID = errors.New("stream id must not be empty, begin with a dot or contain path separators")
+
+func marshalStreamConfig(conf stream.Config) ([]byte, error) {
+	var node yaml.Node
+	if err := node.Encode(conf); err != nil {
+		return nil, err
+	}
+	if err := stream.Spec().SanitiseYAML(&node, docs.SanitiseConfig{
+		RemoveTypeField: true,
+	}); err != nil {
+		return nil, err
+	}
+	return uconfig.MarshalYAML(node)
+}
+
+func unmarshalStreamConfig(confBytes []byte) (stream.Config, error) {
+	conf := stream.NewConfig()
+	err := yaml.Unmarshal(confBytes, &conf)
+	return conf, err
+}
+
+//------------------------------------------------------------------------------
+
+// DirectoryStore persists the config of each stream as a YAML file within a
+// directory, where the name of the file is the ID of the stream.
+type DirectoryStore struct {
+	dir string
+}
+
+// NewDirectoryStore creates a store that persists streams within a directory,
+// which is created if it does not exist.
+func NewDirectoryStore(dir string) (*DirectoryStore, error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, err
+	}
+	return &DirectoryStore{dir: dir}, nil
+}
+
+func (d *DirectoryStore) pathFor(id string) (string, error) {
+	// Files beginning with a dot are hidden and ignored when loading, which
+	// includes the temporary files of writes in progress.
+	if id == "" || strings.HasPrefix(id, ".") || strings.ContainsAny(id, `/\`) {
+		return "", ErrBadStreamID
+	}
+	return filepath.Join(d.dir, id+".yaml"), nil
+}
+
+// Set stores the config of a stream.
+func (d *DirectoryStore) Set(id string, conf stream.Config) error {
+	path, err := d.pathFor(id)
+	if err != nil {
+		return err
+	}
+	confBytes, err := marshalStreamConfig(conf)
+	if err != nil {
+		return err
+	}
+
+	// Write to a temporary file first so that a crash never leaves a partially
+	// written config behind.
+	tmpFile, err := ioutil.TempFile(d.dir, ".tmp-"+id+"-")
+	if err != nil {
+		return err
+	}
+	if _, err = tmpFile.Write(confBytes); err == nil {
+		err = tmpFile.Sync()
+	}
+	if cerr := tmpFile.Close(); err == nil {
+		err = cerr
+	}
+	if err == nil {
+		err = os.Rename(tmpFile.Name(), path)
+	}
+	if err != nil {
+		_ = os.Remove(tmpFile.Name())
+	}
+	return err
+}
+
+// Delete removes the config of a stream.
+func (d *DirectoryStore) Delete(id string) error {
+	path, err := d.pathFor(id)
+	if err != nil {
+		return err
+	}
+	if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return nil
+}
+
+// Load returns the configs of all streams within the directory.
+func (d *DirectoryStore) Load() (map[string]stream.Config, error) {
+	infos, err := ioutil.ReadDir(d.dir)
+	if err != nil {
+		return nil, err
+	}
+	confs := map[string]stream.Config{}
+	for _, info := range infos {
+		name := info.Name()
+		if info.IsDir() || strings.HasPrefix(name, ".") || !strings.HasSuffix(name, ".yaml") {
+			continue
+		}
+		confBytes, err := ioutil.ReadFile(filepath.Join(d.dir, name))
+		if err != nil {
+			return nil, err
+		}
+		id := strings.TrimSuffix(name, ".yaml")
+		if confs[id], err = unmarshalStreamConfig(confBytes); err != nil {
+			return nil, fmt.Errorf("failed to parse stored stream '%v': %w", id, err)
+		}
+	}
+	return confs, nil
+}
+
+//------------------------------------------------------------------------------
+
+// sqlStoreDialect describes the queries of a SQL store for a specific driver.
+type sqlStoreDialect struct {
+	createTable string
+	upsert      string
+	delete      string
+	load        string
+}
+
+func getSQLStoreDialect(driver string) (sqlStoreDialect, error) {
+	switch driver {
+	case "postgres":
+		return sqlStoreDialect{
+			createTable: "CREATE TABLE IF NOT EXISTS benthos_streams (id VARCHAR(255) PRIMARY KEY, config TEXT NOT NULL)",
+			upsert:      "INSERT INTO benthos_streams (id, config) VALUES ($1, $2) ON CONFLICT (id) DO UPDATE SET config = EXCLUDED.config",
+			delete:      "DELETE FROM benthos_streams WHERE id = $1",
+			load:        "SELECT id, config FROM benthos_streams",
+		}, nil
+	case "mysql":
+		return sqlStoreDialect{
+			createTable: "CREATE TABLE IF NOT EXISTS benthos_streams (id VARCHAR(255) PRIMARY KEY, config TEXT NOT NULL)",
+			upsert:      "INSERT INTO benthos_streams (id, config) VALUES (?, ?) ON DUPLICATE KEY UPDATE config = VALUES(config)",
+			delete:      "DELETE FROM benthos_streams WHERE id = ?",
+			load:        "SELECT id, config FROM benthos_streams",
+		}, nil
+	case "sqlserver":
+		return sqlStoreDialect{
+			createTable: "IF OBJECT_ID('benthos_streams', 'U') IS NULL CREATE TABLE benthos_streams (id NVARCHAR(255) PRIMARY KEY, config NVARCHAR(MAX) NOT NULL)",
+			upsert:      "MERGE benthos_streams WITH (HOLDLOCK) AS t USING (SELECT @p1 AS id, @p2 AS config) AS s ON t.id = s.id WHEN MATCHED THEN UPDATE SET config = s.config WHEN NOT MATCHED THEN INSERT (id, config) VALUES (s.id, s.config);",
+			delete:      "DELETE FROM benthos_streams WHERE id = @p1",
+			load:        "SELECT id, config FROM benthos_streams",
+		}, nil
+	}
+	return sqlStoreDialect{}, fmt.Errorf("sql driver not supported: %v", driver)
+}
+
+// SQLStore persists the config of each stream as a row of the table
+// benthos_streams within a database, which is created if it does not exist.
+type SQLStore struct {
+	db      *sql.DB
+	dialect sqlStoreDialect
+}
+
+// NewSQLStore creates a store that persists streams within a database.
+func NewSQLStore(driver, dsn string) (*SQLStore, error) {
+	dialect, err := getSQLStoreDialect(driver)
+	if err != nil {
+		return nil, err
+	}
+	db, err := sql.Open(driver, dsn)
+	if err != nil {
+		return nil, err
+	}
+	if _, err = db.Exec(dialect.createTable); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("failed to create streams table: %w", err)
+	}
+	return &SQLStore{db: db, dialect: dialect}, nil
+}
+
+// Set stores the config of a stream.
+func (s *SQLStore) Set(id string, conf stream.Config) error {
+	confBytes, err := marshalStreamConfig(conf)
+	if err != nil {
+		return err
+	}
+	_, err = s.db.Exec(s.dialect.upsert, id, string(confBytes))
+	return err
+}
+
+// Delete removes the config of a stream.
+func (s *SQLStore) Delete(id string) error {
+	_, err := s.db.Exec(s.dialect.delete, id)
+	return err
+}
+
+// Load returns the configs of all streams within the table.
+func (s *SQLStore) Load() (map[string]stream.Config, error) {
+	rows, err := s.db.Query(s.dialect.load)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	confs := map[string]stream.Config{}
+	for rows.Next() {
+		var id, confStr string
+		if err := rows.Scan(&id, &confStr); err != nil {
+			return nil, err
+		}
+		if confs[id], err = unmarshalStreamConfig([]byte(confStr)); err != nil {
+			return nil, fmt.Errorf("failed to parse stored stream '%v': %w", id, err)
+		}
+	}
+	return confs, rows.Err()
+}
+
+// Close the connection to the database.
+func (s *SQLStore) Close() error {
+	return s.db.Close()
+}
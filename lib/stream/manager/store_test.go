@@ -0,0 +1,136 @@
+package manager
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/Jeffail/benthos/v3/lib/stream"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDirectoryStore(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "streams")
+
+	store, err := NewDirectoryStore(dir)
+	require.NoError(t, err)
+
+	confs, err := store.Load()
+	require.NoError(t, err)
+	assert.Empty(t, confs)
+
+	fooConf := harmlessConf()
+	fooConf.Input.HTTPServer.Path = "/foo"
+	barConf := harmlessConf()
+	barConf.Input.HTTPServer.Path = "/bar"
+
+	require.NoError(t, store.Set("foo", fooConf))
+	require.NoError(t, store.Set("bar", barConf))
+	assert.Equal(t, ErrBadStreamID, store.Set("baz/buz", barConf))
+	assert.Equal(t, ErrBadStreamID, store.Set("", barConf))
+	assert.Equal(t, ErrBadStreamID, store.Set(".hidden", barConf))
+	assert.Equal(t, ErrBadStreamID, store.Set("..", barConf))
+	assert.Equal(t, ErrBadStreamID, store.Delete(".hidden"))
+
+	// Ignored as it is not a config.
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "README.md"), []byte("nope"), 0644))
+
+	confs, err = store.Load()
+	require.NoError(t, err)
+	assert.Equal(t, map[string]stream.Config{
+		"foo": fooConf,
+		"bar": barConf,
+	}, confs)
+
+	barConf.Input.HTTPServer.Path = "/bar2"
+	require.NoError(t, store.Set("bar", barConf))
+	require.NoError(t, store.Delete("foo"))
+	require.NoError(t, store.Delete("does not exist"))
+
+	// A new store of the same directory sees the same streams.
+	store, err = NewDirectoryStore(dir)
+	require.NoError(t, err)
+
+	confs, err = store.Load()
+	require.NoError(t, err)
+	assert.Equal(t, map[string]stream.Config{
+		"bar": barConf,
+	}, confs)
+}
+
+func TestTypePersistence(t *testing.T) {
+	store, err := NewDirectoryStore(t.TempDir())
+	require.NoError(t, err)
+
+	mgr := New(OptSetStore(store))
+
+	fooConf := harmlessConf()
+	fooConf.Input.HTTPServer.Path = "/foo"
+	barConf := harmlessConf()
+	barConf.Input.HTTPServer.Path = "/bar"
+
+	require.NoError(t, mgr.Create("foo", fooConf))
+	require.NoError(t, mgr.Create("bar", barConf))
+
+	badConf := harmlessConf()
+	badConf.Input.Type = "does not exist"
+	require.Error(t, mgr.Create("baz", badConf))
+
+	// Streams that can't be persisted are not created.
+	require.True(t, errors.Is(mgr.Create(".baz", barConf), ErrBadStreamID))
+	_, err = mgr.Read(".baz")
+	require.Error(t, err)
+
+	confs, err := store.Load()
+	require.NoError(t, err)
+	assert.Equal(t, map[string]stream.Config{
+		"foo": fooConf,
+		"bar": barConf,
+	}, confs)
+
+	fooConf.Input.HTTPServer.Path = "/foo2"
+	require.NoError(t, mgr.Update("foo", fooConf, time.Second))
+	require.NoError(t, mgr.Delete("bar", time.Second))
+
+	confs, err = store.Load()
+	require.NoError(t, err)
+	assert.Equal(t, map[string]stream.Config{
+		"foo": fooConf,
+	}, confs)
+
+	// A failed update leaves no trace of the stream.
+	require.Error(t, mgr.Update("foo", badConf, time.Second))
+
+	confs, err = store.Load()
+	require.NoError(t, err)
+	assert.Empty(t, confs)
+
+	require.NoError(t, mgr.Create("bar", barConf))
+	require.NoError(t, mgr.Stop(time.Second))
+
+	// Streams remain persisted after the manager is stopped.
+	confs, err = store.Load()
+	require.NoError(t, err)
+	assert.Equal(t, map[string]stream.Config{
+		"bar": barConf,
+	}, confs)
+}
+
+func TestSQLStoreDialects(t *testing.T) {
+	for _, driver := range []string{"postgres", "mysql", "sqlserver"} {
+		dialect, err := getSQLStoreDialect(driver)
+		require.NoError(t, err, driver)
+		assert.Contains(t, dialect.createTable, "benthos_streams", driver)
+		assert.Contains(t, dialect.upsert, "benthos_streams", driver)
+		assert.Contains(t, dialect.delete, "benthos_streams", driver)
+	}
+
+	_, err := getSQLStoreDialect("sqlite3")
+	assert.EqualError(t, err, "sql driver not supported: sqlite3")
+
+	_, err = NewStore("redis://localhost:6379")
+	assert.EqualError(t, err, "store scheme not recognised: redis")
+}
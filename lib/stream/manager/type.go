@@ -4,11 +4,13 @@ import (
 	"errors"
 	"fmt"
 	"reflect"
+	"sort"
 	"sync"
 	"sync/atomic"
 	"time"
 
 	"github.com/Jeffail/benthos/v3/internal/interop"
+	"github.com/Jeffail/benthos/v3/lib/api"
 	"github.com/Jeffail/benthos/v3/lib/log"
 	"github.com/Jeffail/benthos/v3/lib/manager"
 	"github.com/Jeffail/benthos/v3/lib/metrics"
@@ -26,6 +28,7 @@ type StreamStatus struct {
 	logger       log.Modular
 	metrics      *metrics.Local
 	createdAt    time.Time
+	lastErr      *lastError
 }
 
 // NewStreamStatus creates a new StreamStatus.
@@ -41,6 +44,7 @@ func NewStreamStatus(
 		logger:    logger,
 		metrics:   stats,
 		createdAt: time.Now(),
+		lastErr:   &lastError{},
 	}
 }
 
@@ -56,6 +60,12 @@ func (s *StreamStatus) IsReady() bool {
 	return s.strm.IsReady()
 }
 
+// IsAlive returns a boolean indicating whether neither the input or output of
+// the stream have been disconnected for longer than the liveness grace period.
+func (s *StreamStatus) IsAlive() bool {
+	return s.strm.IsAlive()
+}
+
 // Uptime returns a time.Duration indicating the current uptime of the stream.
 func (s *StreamStatus) Uptime() time.Duration {
 	if stoppedAfter := atomic.LoadInt64(&s.stoppedAfter); stoppedAfter > 0 {
@@ -74,6 +84,13 @@ func (s *StreamStatus) Metrics() *metrics.Local {
 	return s.metrics
 }
 
+// LastError returns the most recent error message logged by the stream along
+// with the time at which it was logged, or an empty string if no errors have
+// been logged.
+func (s *StreamStatus) LastError() (string, time.Time) {
+	return s.lastErr.get()
+}
+
 // Logger returns the logger of the stream.
 func (s *StreamStatus) Logger() log.Modular {
 	return s.logger
@@ -102,6 +119,11 @@ type Type struct {
 	stats      metrics.Type
 	logger     log.Modular
 	apiTimeout time.Duration
+	apiAuth    *api.Authenticator
+	store      Store
+
+	readyGracePeriod time.Duration
+	liveGracePeriod  time.Duration
 
 	pipelineProcCtors []StreamProcConstructorFunc
 
@@ -158,6 +180,24 @@ func OptSetAPITimeout(tout time.Duration) func(*Type) {
 	}
 }
 
+// OptSetAPIAuth sets an authenticator that wraps the handlers of the HTTP API
+// endpoints of the manager, excluding the readiness endpoint.
+func OptSetAPIAuth(auth *api.Authenticator) func(*Type) {
+	return func(t *Type) {
+		t.apiAuth = auth
+	}
+}
+
+// OptSetProbeGracePeriods sets the periods of time that the inputs and outputs
+// of streams are allowed to be disconnected for before their readiness and
+// liveness probes fail.
+func OptSetProbeGracePeriods(ready, live time.Duration) func(*Type) {
+	return func(t *Type) {
+		t.readyGracePeriod = ready
+		t.liveGracePeriod = live
+	}
+}
+
 // OptAddProcessors adds processor constructors that will be called for every
 // new stream and attached to the processor pipelines. The constructor is given
 // the name of the stream as an argument.
@@ -167,6 +207,15 @@ func OptAddProcessors(procs ...StreamProcConstructorFunc) func(*Type) {
 	}
 }
 
+// OptSetStore sets a store used in order to persist the configs of streams as
+// they are created, updated and deleted. Stopping the manager does not remove
+// streams from the store.
+func OptSetStore(store Store) func(*Type) {
+	return func(t *Type) {
+		t.store = store
+	}
+}
+
 //------------------------------------------------------------------------------
 
 // Errors specifically returned by a stream manager.
@@ -211,13 +260,16 @@ func (m *Type) Create(id string, conf stream.Config) error {
 	sStats = metrics.Combine(sStats, strmFlatMetrics)
 	sMgr = manager.SwapMetrics(sMgr, sStats)
 
+	lastErr := &lastError{}
+
 	var wrapper *StreamStatus
 	strm, err := stream.New(
 		conf,
 		stream.OptAddProcessors(procCtors...),
-		stream.OptSetLogger(sLog),
+		stream.OptSetLogger(trackErrors(sLog, lastErr)),
 		stream.OptSetStats(sStats),
 		stream.OptSetManager(sMgr),
+		stream.OptSetProbeGracePeriods(m.readyGracePeriod, m.liveGracePeriod),
 		stream.OptOnClose(func() {
 			wrapper.setClosed()
 		}),
@@ -227,6 +279,13 @@ func (m *Type) Create(id string, conf stream.Config) error {
 	}
 
 	wrapper = NewStreamStatus(conf, strm, sLog, strmFlatMetrics)
+	wrapper.lastErr = lastErr
+	if m.store != nil {
+		if err = m.store.Set(id, conf); err != nil {
+			_ = strm.Stop(m.apiTimeout)
+			return fmt.Errorf("failed to persist stream: %w", err)
+		}
+	}
 	m.streams[id] = wrapper
 	return nil
 }
@@ -249,6 +308,28 @@ func (m *Type) Read(id string) (*StreamStatus, error) {
 	return wrapper, nil
 }
 
+// IsAlive returns a boolean indicating whether none of the running streams have
+// been disconnected for longer than the liveness grace period.
+func (m *Type) IsAlive() bool {
+	return len(m.notAlive()) == 0
+}
+
+// notAlive returns the sorted identifiers of running streams that have been
+// disconnected for longer than the liveness grace period.
+func (m *Type) notAlive() []string {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	var notAlive []string
+	for k, v := range m.streams {
+		if v.IsRunning() && !v.IsAlive() {
+			notAlive = append(notAlive, k)
+		}
+	}
+	sort.Strings(notAlive)
+	return notAlive
+}
+
 // Update attempts to stop an existing stream and replace it with a new version
 // of the same stream.
 func (m *Type) Update(id string, conf stream.Config, timeout time.Duration) error {
@@ -268,16 +349,30 @@ func (m *Type) Update(id string, conf stream.Config, timeout time.Duration) erro
 		return nil
 	}
 
-	if err := m.Delete(id, timeout); err != nil {
+	// The persisted config is left in place until the new version of the
+	// stream replaces it.
+	if err := m.delete(id, timeout, false); err != nil {
 		return err
 	}
-	return m.Create(id, conf)
+	if err := m.Create(id, conf); err != nil {
+		if m.store != nil {
+			if serr := m.store.Delete(id); serr != nil {
+				m.logger.Errorf("Failed to remove stream '%v' from store: %v\n", id, serr)
+			}
+		}
+		return err
+	}
+	return nil
 }
 
 // Delete attempts to stop and remove a stream by its ID. Returns an error if
 // the stream was not found, or if clean shutdown fails in the specified period
 // of time.
 func (m *Type) Delete(id string, timeout time.Duration) error {
+	return m.delete(id, timeout, true)
+}
+
+func (m *Type) delete(id string, timeout time.Duration, unpersist bool) error {
 	m.lock.Lock()
 	if m.closed {
 		m.lock.Unlock()
@@ -298,6 +393,11 @@ func (m *Type) Delete(id string, timeout time.Duration) error {
 	delete(m.streams, id)
 	m.lock.Unlock()
 
+	if unpersist && m.store != nil {
+		if err := m.store.Delete(id); err != nil {
+			return fmt.Errorf("failed to remove stream from store: %w", err)
+		}
+	}
 	return nil
 }
 
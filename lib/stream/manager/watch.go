@@ -0,0 +1,195 @@
+package manager
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/Jeffail/benthos/v3/lib/log"
+	"github.com/Jeffail/benthos/v3/lib/stream"
+)
+
+//------------------------------------------------------------------------------
+
+// pathsSnapshot describes the modification times and sizes of all stream
+// config files within a set of paths at a given time.
+type pathsSnapshot map[string]pathState
+
+type pathState struct {
+	modTime time.Time
+	size    int64
+}
+
+func takeSnapshot(paths []string) pathsSnapshot {
+	snapshot := pathsSnapshot{}
+	for _, target := range paths {
+		_ = filepath.Walk(filepath.Clean(target), func(path string, info os.FileInfo, err error) error {
+			if err != nil {
+				// Missing paths are simply absent from the snapshot.
+				return nil
+			}
+			if info.IsDir() ||
+				(path != filepath.Clean(target) &&
+					!strings.HasSuffix(info.Name(), ".yaml") &&
+					!strings.HasSuffix(info.Name(), ".yml")) {
+				return nil
+			}
+			snapshot[path] = pathState{modTime: info.ModTime(), size: info.Size()}
+			return nil
+		})
+	}
+	return snapshot
+}
+
+func (p pathsSnapshot) equals(other pathsSnapshot) bool {
+	if len(p) != len(other) {
+		return false
+	}
+	for path, state := range p {
+		otherState, exists := other[path]
+		if !exists || !otherState.modTime.Equal(state.modTime) || otherState.size != state.size {
+			return false
+		}
+	}
+	return true
+}
+
+//------------------------------------------------------------------------------
+
+// Watcher polls a set of paths containing stream configs and reconciles the
+// streams of a manager with them, creating, updating and deleting streams as
+// their config files are added, modified and removed. Streams that were not
+// loaded from the paths, such as those created via the REST API, are never
+// modified.
+type Watcher struct {
+	mgr        *Type
+	paths      []string
+	testSuffix string
+	strict     bool
+	logger     log.Modular
+
+	snapshot pathsSnapshot
+	known    map[string]stream.Config
+}
+
+// NewWatcher creates a watcher of stream config paths, where initial is the
+// set of stream configs already loaded from the paths and created within the
+// manager.
+func NewWatcher(
+	mgr *Type,
+	paths []string,
+	testSuffix string,
+	strict bool,
+	initial map[string]stream.Config,
+	logger log.Modular,
+) *Watcher {
+	known := make(map[string]stream.Config, len(initial))
+	for k, v := range initial {
+		known[k] = v
+	}
+	return &Watcher{
+		mgr:        mgr,
+		paths:      paths,
+		testSuffix: testSuffix,
+		strict:     strict,
+		logger:     logger,
+		snapshot:   takeSnapshot(paths),
+		known:      known,
+	}
+}
+
+// Sync checks the paths for changes and, if any are found, reloads all stream
+// configs and applies the differences to the manager. If the configs cannot
+// be loaded, or contain linting errors when strict, then the change is logged
+// and no streams are modified.
+func (w *Watcher) Sync() {
+	snapshot := takeSnapshot(w.paths)
+	if snapshot.equals(w.snapshot) {
+		return
+	}
+	w.snapshot = snapshot
+
+	confs := map[string]stream.Config{}
+	var lints []string
+	for _, path := range w.paths {
+		if _, err := os.Stat(path); os.IsNotExist(err) {
+			continue
+		}
+		pathLints, err := LoadStreamConfigsFromPath(path, w.testSuffix, confs)
+		if err != nil {
+			w.logger.Errorf("Failed to reload stream configs, existing streams are unchanged: %v\n", err)
+			return
+		}
+		lints = append(lints, pathLints...)
+	}
+	if len(lints) > 0 {
+		lintlog := w.logger.NewModule(".linter")
+		for _, lint := range lints {
+			lintlog.Infoln(lint)
+		}
+		if w.strict {
+			w.logger.Errorln("Stream configs contain linting errors, existing streams are unchanged. To ignore linting errors run Benthos with --chilled")
+			return
+		}
+	}
+
+	var removed []string
+	for id := range w.known {
+		if _, exists := confs[id]; !exists {
+			removed = append(removed, id)
+		}
+	}
+	sort.Strings(removed)
+	for _, id := range removed {
+		if err := w.mgr.Delete(id, w.mgr.apiTimeout); err != nil && err != ErrStreamDoesNotExist {
+			w.logger.Errorf("Failed to delete stream '%v': %v\n", id, err)
+			continue
+		}
+		w.logger.Infof("Deleted stream '%v' as its config was removed\n", id)
+		delete(w.known, id)
+	}
+
+	ids := make([]string, 0, len(confs))
+	for id := range confs {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	for _, id := range ids {
+		conf := confs[id]
+		if prev, exists := w.known[id]; exists && reflect.DeepEqual(prev, conf) {
+			continue
+		}
+		err := w.mgr.Update(id, conf, w.mgr.apiTimeout)
+		if err == ErrStreamDoesNotExist {
+			err = w.mgr.Create(id, conf)
+		}
+		if err != nil {
+			w.logger.Errorf("Failed to apply config of stream '%v': %v\n", id, err)
+			continue
+		}
+		if _, exists := w.known[id]; exists {
+			w.logger.Infof("Updated stream '%v' as its config changed\n", id)
+		} else {
+			w.logger.Infof("Created stream '%v' as its config was added\n", id)
+		}
+		w.known[id] = conf
+	}
+}
+
+// Run polls the paths for changes at an interval until the stop channel is
+// closed.
+func (w *Watcher) Run(interval time.Duration, stop <-chan struct{}) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			w.Sync()
+		case <-stop:
+			return
+		}
+	}
+}
@@ -0,0 +1,106 @@
+package manager
+
+import (
+	"os"
+	"path/filepath"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/Jeffail/benthos/v3/lib/log"
+	"github.com/Jeffail/benthos/v3/lib/stream"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWatcherSync(t *testing.T) {
+	dir := t.TempDir()
+
+	writeConf := func(name, path string) {
+		t.Helper()
+		require.NoError(t, os.WriteFile(filepath.Join(dir, name), []byte(`
+input:
+  http_server:
+    path: `+path+`
+output:
+  drop: {}
+`), 0644))
+	}
+
+	// Ensure that modification times differ between writes.
+	tick := func() {
+		time.Sleep(time.Millisecond * 10)
+	}
+
+	streamPaths := func(mgr *Type) map[string]string {
+		mgr.lock.Lock()
+		defer mgr.lock.Unlock()
+		paths := map[string]string{}
+		for id, s := range mgr.streams {
+			paths[id] = s.Config().Input.HTTPServer.Path
+		}
+		return paths
+	}
+
+	writeConf("foo.yaml", "/foo")
+	writeConf("bar.yaml", "/bar")
+
+	initial := map[string]stream.Config{}
+	_, err := LoadStreamConfigsFromPath(dir, "_benthos_test", initial)
+	require.NoError(t, err)
+
+	mgr := New()
+	defer mgr.Stop(time.Second)
+
+	ids := make([]string, 0, len(initial))
+	for id, conf := range initial {
+		require.NoError(t, mgr.Create(id, conf))
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	assert.Equal(t, []string{"bar", "foo"}, ids)
+
+	// A stream created via the API is not managed by the watcher.
+	require.NoError(t, mgr.Create("api", harmlessConf()))
+
+	w := NewWatcher(mgr, []string{dir}, "_benthos_test", true, initial, log.Noop())
+
+	w.Sync()
+	assert.Equal(t, map[string]string{"foo": "/foo", "bar": "/bar", "api": "/post"}, streamPaths(mgr))
+
+	tick()
+	writeConf("foo.yaml", "/foo2")
+	writeConf("baz.yaml", "/baz")
+	require.NoError(t, os.Remove(filepath.Join(dir, "bar.yaml")))
+
+	w.Sync()
+	assert.Equal(t, map[string]string{"foo": "/foo2", "baz": "/baz", "api": "/post"}, streamPaths(mgr))
+
+	// Configs that fail to parse leave existing streams unchanged.
+	tick()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "foo.yaml"), []byte(`input: [ nope`), 0644))
+	require.NoError(t, os.Remove(filepath.Join(dir, "baz.yaml")))
+
+	w.Sync()
+	assert.Equal(t, map[string]string{"foo": "/foo2", "baz": "/baz", "api": "/post"}, streamPaths(mgr))
+
+	// Configs with linting errors leave existing streams unchanged when strict.
+	tick()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "foo.yaml"), []byte(`
+input:
+  http_server:
+    path: /foo3
+    not_a_field: true
+output:
+  drop: {}
+`), 0644))
+
+	w.Sync()
+	assert.Equal(t, map[string]string{"foo": "/foo2", "baz": "/baz", "api": "/post"}, streamPaths(mgr))
+
+	tick()
+	writeConf("foo.yaml", "/foo3")
+
+	w.Sync()
+	assert.Equal(t, map[string]string{"foo": "/foo3", "api": "/post"}, streamPaths(mgr))
+}
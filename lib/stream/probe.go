@@ -0,0 +1,98 @@
+package stream
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+//------------------------------------------------------------------------------
+
+// connectionState tracks the period of time that a component has been
+// disconnected for, as observed by health probes.
+type connectionState struct {
+	name      string
+	connected func() bool
+
+	mut               sync.Mutex
+	hasConnected      bool
+	disconnectedSince time.Time
+}
+
+func newConnectionState(name string, connected func() bool) *connectionState {
+	return &connectionState{
+		name:              name,
+		connected:         connected,
+		disconnectedSince: time.Now(),
+	}
+}
+
+// check returns whether the component is connected and, if not, how long it
+// has been disconnected for and whether it has ever been connected.
+func (c *connectionState) check() (connected, hasConnected bool, disconnectedFor time.Duration) {
+	c.mut.Lock()
+	defer c.mut.Unlock()
+
+	if c.connected() {
+		c.hasConnected = true
+		c.disconnectedSince = time.Time{}
+		return true, true, 0
+	}
+	if c.disconnectedSince.IsZero() {
+		c.disconnectedSince = time.Now()
+	}
+	return false, c.hasConnected, time.Since(c.disconnectedSince)
+}
+
+//------------------------------------------------------------------------------
+
+// probeResult describes the outcome of a health probe of a stream, with the
+// connection state of each component.
+type probeResult struct {
+	ok      bool
+	details []string
+}
+
+// probe checks the connection state of each component of the stream. A
+// component passes when it is connected, or when pass returns true for the
+// period it has been disconnected for.
+func (t *Type) probe(pass func(hasConnected bool, disconnectedFor time.Duration) bool) probeResult {
+	res := probeResult{ok: true}
+	for _, c := range t.connStates {
+		connected, hasConnected, disconnectedFor := c.check()
+		if connected {
+			res.details = append(res.details, fmt.Sprintf("%v: connected", c.name))
+			continue
+		}
+		if !pass(hasConnected, disconnectedFor) {
+			res.ok = false
+		}
+		if hasConnected {
+			res.details = append(res.details, fmt.Sprintf("%v: disconnected for %v", c.name, disconnectedFor.Round(time.Second)))
+		} else {
+			res.details = append(res.details, fmt.Sprintf("%v: not yet connected", c.name))
+		}
+	}
+	return res
+}
+
+func (t *Type) readyResult() probeResult {
+	return t.probe(func(hasConnected bool, disconnectedFor time.Duration) bool {
+		return hasConnected && disconnectedFor < t.readyGracePeriod
+	})
+}
+
+func (t *Type) liveResult() probeResult {
+	return t.probe(func(_ bool, disconnectedFor time.Duration) bool {
+		return t.liveGracePeriod <= 0 || disconnectedFor < t.liveGracePeriod
+	})
+}
+
+func writeProbeResult(w http.ResponseWriter, res probeResult) {
+	if !res.ok {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}
+	w.Write([]byte(strings.Join(res.details, "\n") + "\n"))
+}
@@ -0,0 +1,71 @@
+package stream
+
+import (
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestProbeGracePeriods(t *testing.T) {
+	var inConnected, outConnected int32 = 1, 0
+	strm := &Type{
+		connStates: []*connectionState{
+			newConnectionState("input", func() bool { return atomic.LoadInt32(&inConnected) == 1 }),
+			newConnectionState("output", func() bool { return atomic.LoadInt32(&outConnected) == 1 }),
+		},
+		readyGracePeriod: time.Millisecond * 100,
+		liveGracePeriod:  time.Millisecond * 200,
+	}
+
+	// Components that have never connected fail readiness immediately.
+	assert.False(t, strm.IsReady())
+	assert.True(t, strm.IsAlive())
+
+	res := strm.readyResult()
+	assert.Equal(t, []string{"input: connected", "output: not yet connected"}, res.details)
+
+	atomic.StoreInt32(&outConnected, 1)
+	assert.True(t, strm.IsReady())
+	assert.True(t, strm.IsAlive())
+
+	// Disconnections within the grace periods are tolerated.
+	atomic.StoreInt32(&inConnected, 0)
+	assert.True(t, strm.IsReady())
+	assert.True(t, strm.IsAlive())
+
+	<-time.After(time.Millisecond * 150)
+	assert.False(t, strm.IsReady())
+	assert.True(t, strm.IsAlive())
+
+	<-time.After(time.Millisecond * 100)
+	assert.False(t, strm.IsReady())
+	assert.False(t, strm.IsAlive())
+
+	rec := httptest.NewRecorder()
+	writeProbeResult(rec, strm.liveResult())
+	assert.Equal(t, 503, rec.Code)
+	assert.Contains(t, rec.Body.String(), "input: disconnected for")
+	assert.Contains(t, rec.Body.String(), "output: connected")
+
+	atomic.StoreInt32(&inConnected, 1)
+	assert.True(t, strm.IsReady())
+	assert.True(t, strm.IsAlive())
+
+	rec = httptest.NewRecorder()
+	writeProbeResult(rec, strm.readyResult())
+	assert.Equal(t, 200, rec.Code)
+	assert.Equal(t, "input: connected\noutput: connected\n", rec.Body.String())
+}
+
+func TestProbeNoLiveGracePeriod(t *testing.T) {
+	strm := &Type{
+		connStates: []*connectionState{
+			newConnectionState("input", func() bool { return false }),
+		},
+	}
+	assert.False(t, strm.IsReady())
+	assert.True(t, strm.IsAlive())
+}
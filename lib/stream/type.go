@@ -2,6 +2,7 @@ package stream
 
 import (
 	"bytes"
+	"fmt"
 	"net/http"
 	"runtime/pprof"
 	"time"
@@ -33,6 +34,14 @@ type Type struct {
 
 	complementaryProcs []types.ProcessorConstructorFunc
 
+	drainTimeout time.Duration
+
+	gates []*gate
+
+	connStates       []*connectionState
+	readyGracePeriod time.Duration
+	liveGracePeriod  time.Duration
+
 	manager types.Manager
 	stats   metrics.Type
 	logger  log.Modular
@@ -56,26 +65,23 @@ func New(conf Config, opts ...func(*Type)) (*Type, error) {
 		return nil, err
 	}
 
-	healthCheck := func(w http.ResponseWriter, r *http.Request) {
-		connected := true
-		if !t.inputLayer.Connected() {
-			connected = false
-			w.WriteHeader(http.StatusServiceUnavailable)
-			w.Write([]byte("input not connected\n"))
-		}
-		if !t.outputLayer.Connected() {
-			connected = false
-			w.WriteHeader(http.StatusServiceUnavailable)
-			w.Write([]byte("output not connected\n"))
-		}
-		if connected {
-			w.Write([]byte("OK"))
-		}
+	t.connStates = []*connectionState{
+		newConnectionState("input", t.inputLayer.Connected),
+		newConnectionState("output", t.outputLayer.Connected),
 	}
 	t.manager.RegisterEndpoint(
 		"/ready",
-		"Returns 200 OK if all inputs and outputs are connected, otherwise a 503 is returned.",
-		healthCheck,
+		"Returns 200 OK if all inputs and outputs are connected, otherwise a 503 is returned. The response body describes the connection state of each component.",
+		func(w http.ResponseWriter, r *http.Request) {
+			writeProbeResult(w, t.readyResult())
+		},
+	)
+	t.manager.RegisterEndpoint(
+		"/live",
+		"Returns 200 OK unless an input or output has been disconnected for longer than the liveness grace period, in which case a 503 is returned.",
+		func(w http.ResponseWriter, r *http.Request) {
+			writeProbeResult(w, t.liveResult())
+		},
 	)
 	return t, nil
 }
@@ -123,6 +129,17 @@ func OptSetManager(mgr types.Manager) func(*Type) {
 	}
 }
 
+// OptSetProbeGracePeriods sets the periods of time that inputs and outputs are
+// allowed to be disconnected for before the readiness and liveness probes of
+// the stream fail. A liveness grace period of zero disables the liveness
+// probe, such that it always passes.
+func OptSetProbeGracePeriods(ready, live time.Duration) func(*Type) {
+	return func(t *Type) {
+		t.readyGracePeriod = ready
+		t.liveGracePeriod = live
+	}
+}
+
 // OptOnClose sets a closure to be called when the stream closes.
 func OptOnClose(onClose func()) func(*Type) {
 	return func(t *Type) {
@@ -133,38 +150,57 @@ func OptOnClose(onClose func()) func(*Type) {
 //------------------------------------------------------------------------------
 
 // IsReady returns a boolean indicating whether both the input and output layers
-// of the stream are connected.
+// of the stream are connected, or have been disconnected for less than the
+// readiness grace period.
 func (t *Type) IsReady() bool {
-	return t.inputLayer.Connected() && t.outputLayer.Connected()
+	return t.readyResult().ok
+}
+
+// IsAlive returns a boolean indicating whether neither the input or output
+// layers of the stream have been disconnected for longer than the liveness
+// grace period.
+func (t *Type) IsAlive() bool {
+	return t.liveResult().ok
 }
 
 func (t *Type) start() (err error) {
+	if tout := t.conf.Drain.Timeout; len(tout) > 0 {
+		if t.drainTimeout, err = time.ParseDuration(tout); err != nil {
+			return wrapComponentErr("drain.timeout", fmt.Errorf("failed to parse drain timeout string: %v", err))
+		}
+	}
+
 	// Constructors
 	iMgr, iLog, iStats := interop.LabelChild("input", t.manager, t.logger, t.stats)
 	if t.inputLayer, err = input.New(t.conf.Input, iMgr, iLog, iStats); err != nil {
-		return
+		return wrapComponentErr("input", err)
 	}
 	if t.conf.Buffer.Type != buffer.TypeNone {
 		bMgr, bLog, bStats := interop.LabelChild("buffer", t.manager, t.logger, t.stats)
 		if t.bufferLayer, err = buffer.New(t.conf.Buffer, bMgr, bLog, bStats); err != nil {
-			return
+			return wrapComponentErr("buffer", err)
 		}
 	}
 	if tLen := len(t.complementaryProcs) + len(t.conf.Pipeline.Processors); tLen > 0 {
 		pMgr, pLog, pStats := interop.LabelChild("pipeline", t.manager, t.logger, t.stats)
 		if t.pipelineLayer, err = pipeline.New(t.conf.Pipeline, pMgr, pLog, pStats, t.complementaryProcs...); err != nil {
-			return
+			return wrapComponentErr("pipeline.processors", err)
 		}
 	}
 	oMgr, oLog, oStats := interop.LabelChild("output", t.manager, t.logger, t.stats)
 	if t.outputLayer, err = output.New(t.conf.Output, oMgr, oLog, oStats); err != nil {
-		return
+		return wrapComponentErr("output", err)
 	}
 
 	// Start chaining components
 	var nextTranChan <-chan types.Transaction
 
 	nextTranChan = t.inputLayer.TransactionChan()
+	if label := t.conf.Input.Label; label != "" {
+		g := newGate(label, "input")
+		t.gates = append(t.gates, g)
+		nextTranChan = g.relay(nextTranChan)
+	}
 	if t.bufferLayer != nil {
 		if err = t.bufferLayer.Consume(nextTranChan); err != nil {
 			return
@@ -177,6 +213,11 @@ func (t *Type) start() (err error) {
 		}
 		nextTranChan = t.pipelineLayer.TransactionChan()
 	}
+	if label := t.conf.Output.Label; label != "" {
+		g := newGate(label, "output")
+		t.gates = append(t.gates, g)
+		nextTranChan = g.relay(nextTranChan)
+	}
 	if err = t.outputLayer.Consume(nextTranChan); err != nil {
 		return
 	}
@@ -193,6 +234,51 @@ func (t *Type) start() (err error) {
 	return nil
 }
 
+// Pause holds back messages from flowing through the labelled input or output
+// of the stream until it is resumed. A paused input stops consuming messages,
+// and a paused output stops receiving them, without either being closed.
+func (t *Type) Pause(label string) error {
+	found := false
+	for _, g := range t.gates {
+		if g.label == label {
+			g.pause()
+			found = true
+		}
+	}
+	if !found {
+		return ErrComponentNotFound
+	}
+	return nil
+}
+
+// Resume allows messages to flow through the labelled input or output of the
+// stream after it has been paused.
+func (t *Type) Resume(label string) error {
+	found := false
+	for _, g := range t.gates {
+		if g.label == label {
+			g.resume()
+			found = true
+		}
+	}
+	if !found {
+		return ErrComponentNotFound
+	}
+	return nil
+}
+
+// ComponentStates returns the state of each component of the stream that can
+// be paused, which are the input and output when they have a label.
+func (t *Type) ComponentStates() []ComponentState {
+	states := make([]ComponentState, 0, len(t.gates))
+	for _, g := range t.gates {
+		states = append(states, g.state())
+	}
+	return states
+}
+
+//------------------------------------------------------------------------------
+
 // stopGracefully attempts to close the stream in the most graceful way by only
 // closing the input layer and waiting for all other layers to terminate by
 // proxy. This should guarantee that all in-flight and buffered data is resolved
@@ -343,22 +429,42 @@ func (t *Type) stopUnordered(timeout time.Duration) (err error) {
 
 // Stop attempts to close the stream within the specified timeout period.
 // Initially the attempt is graceful, but as the timeout draws close the attempt
-// becomes progressively less graceful.
+// becomes progressively less graceful. The period of the graceful attempt can
+// be configured with a drain timeout, and if the drain is configured as
+// unordered then the graceful attempt is skipped entirely.
 func (t *Type) Stop(timeout time.Duration) error {
-	tOutUnordered := timeout / 4
-	tOutGraceful := timeout - tOutUnordered
-
-	err := t.stopGracefully(tOutGraceful)
-	if err == nil {
-		return nil
+	// Paused components are released once the input is closed so that
+	// in-flight messages can be resolved.
+	t.inputLayer.CloseAsync()
+	for _, g := range t.gates {
+		g.release()
 	}
-	if err == types.ErrTimeout {
-		t.logger.Infoln("Unable to fully drain buffered messages within target time.")
-	} else {
-		t.logger.Errorf("Encountered error whilst shutting down: %v\n", err)
+	defer func() {
+		for _, g := range t.gates {
+			g.stop()
+		}
+	}()
+
+	tOutUnordered := timeout
+	if t.conf.Drain.Ordered {
+		tOutGraceful := timeout - timeout/4
+		if t.drainTimeout > 0 && t.drainTimeout < timeout {
+			tOutGraceful = t.drainTimeout
+		}
+		tOutUnordered = timeout - tOutGraceful
+
+		err := t.stopGracefully(tOutGraceful)
+		if err == nil {
+			return nil
+		}
+		if err == types.ErrTimeout {
+			t.logger.Infoln("Unable to fully drain buffered messages within target time.")
+		} else {
+			t.logger.Errorf("Encountered error whilst shutting down: %v\n", err)
+		}
 	}
 
-	err = t.stopUnordered(tOutUnordered)
+	err := t.stopUnordered(tOutUnordered)
 	if err == nil {
 		return nil
 	}
@@ -1,6 +1,7 @@
 package stream
 
 import (
+	"errors"
 	"testing"
 	"time"
 
@@ -116,3 +117,80 @@ func TestTypeCloseUnordered(t *testing.T) {
 	require.NoError(t, err)
 	assert.NoError(t, strm.stopUnordered(time.Minute))
 }
+
+func TestTypeComponentErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		conf func(c *Config)
+		path string
+	}{
+		{
+			name: "bad input",
+			conf: func(c *Config) {
+				c.Input.Type = "not_exist"
+			},
+			path: "input",
+		},
+		{
+			name: "bad processor",
+			conf: func(c *Config) {
+				proc := processor.NewConfig()
+				proc.Type = "not_exist"
+				c.Pipeline.Processors = append(c.Pipeline.Processors, proc)
+			},
+			path: "pipeline.processors",
+		},
+		{
+			name: "bad output",
+			conf: func(c *Config) {
+				c.Output.Type = "not_exist"
+			},
+			path: "output",
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			conf := NewConfig()
+			conf.Input.Type = input.TypeHTTPServer
+			conf.Output.Type = output.TypeHTTPServer
+			test.conf(&conf)
+
+			_, err := New(conf)
+			require.Error(t, err)
+
+			var cErr *ComponentError
+			require.True(t, errors.As(err, &cErr))
+			assert.Equal(t, test.path, cErr.Path)
+		})
+	}
+}
+
+func TestTypeDrainConfig(t *testing.T) {
+	conf := NewConfig()
+	conf.Input.Type = input.TypeHTTPServer
+	conf.Output.Type = output.TypeHTTPServer
+	conf.Drain.Timeout = "nope"
+
+	_, err := New(conf)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "failed to parse drain timeout")
+
+	conf.Drain.Timeout = "1s"
+
+	strm, err := New(conf)
+	require.NoError(t, err)
+	assert.Equal(t, time.Second, strm.drainTimeout)
+	assert.NoError(t, strm.Stop(time.Minute))
+
+	conf.Drain.Ordered = false
+	conf.Buffer.Type = "memory"
+	conf.Pipeline.Processors = []processor.Config{
+		processor.NewConfig(),
+	}
+
+	strm, err = New(conf)
+	require.NoError(t, err)
+	assert.NoError(t, strm.Stop(time.Minute))
+}
@@ -1,13 +1,17 @@
 package integration
 
 import (
+	"context"
 	"fmt"
 	"testing"
 	"time"
 
+	"github.com/Jeffail/benthos/v3/lib/input/reader"
 	"github.com/Jeffail/benthos/v3/lib/log"
 	"github.com/Jeffail/benthos/v3/lib/metrics"
 	"github.com/Jeffail/benthos/v3/lib/output/writer"
+	"github.com/Jeffail/benthos/v3/lib/response"
+	"github.com/Jeffail/benthos/v3/lib/types"
 	"github.com/go-redis/redis/v7"
 	"github.com/ory/dockertest/v3"
 	"github.com/stretchr/testify/assert"
@@ -97,6 +101,100 @@ input:
 		})
 	})
 
+	t.Run("streams claim pending", func(t *testing.T) {
+		t.Parallel()
+
+		client := redis.NewClient(&redis.Options{
+			Addr: fmt.Sprintf("localhost:%v", resource.GetPort("6379/tcp")),
+		})
+		t.Cleanup(func() {
+			client.Close()
+		})
+
+		require.NoError(t, client.XGroupCreateMkStream("stream-claim", "group-claim", "0").Err())
+		require.NoError(t, client.XGroupCreateMkStream("stream-claim-dead", "group-claim", "0").Err())
+		for _, body := range []string{"foo", "bar"} {
+			require.NoError(t, client.XAdd(&redis.XAddArgs{
+				Stream: "stream-claim",
+				Values: map[string]interface{}{"body": body},
+			}).Err())
+		}
+		for _, body := range []string{"baz"} {
+			require.NoError(t, client.XAdd(&redis.XAddArgs{
+				Stream: "stream-claim-dead",
+				Values: map[string]interface{}{"body": body},
+			}).Err())
+		}
+
+		// Read the messages with a consumer that never acknowledges them.
+		for _, str := range []string{"stream-claim", "stream-claim-dead"} {
+			require.NoError(t, client.XReadGroup(&redis.XReadGroupArgs{
+				Group:    "group-claim",
+				Consumer: "crashed",
+				Streams:  []string{str, ">"},
+			}).Err())
+		}
+		// Bump the delivery count of the message that should be dead lettered.
+		res, err := client.XRange("stream-claim-dead", "-", "+").Result()
+		require.NoError(t, err)
+		require.Len(t, res, 1)
+		require.NoError(t, client.XClaim(&redis.XClaimArgs{
+			Stream:   "stream-claim-dead",
+			Group:    "group-claim",
+			Consumer: "crashed",
+			Messages: []string{res[0].ID},
+		}).Err())
+
+		conf := reader.NewRedisStreamsConfig()
+		conf.URL = fmt.Sprintf("tcp://localhost:%v", resource.GetPort("6379/tcp"))
+		conf.Streams = []string{"stream-claim", "stream-claim-dead"}
+		conf.ConsumerGroup = "group-claim"
+		conf.ClientID = "survivor"
+		conf.CommitPeriod = "10ms"
+		conf.Claim.Enabled = true
+		conf.Claim.Period = "50ms"
+		conf.Claim.MinIdle = "50ms"
+		conf.Claim.MaxDeliveries = 2
+		conf.Claim.DeadLetterStream = "stream-claim-dlq"
+
+		r, err := reader.NewRedisStreams(conf, log.Noop(), metrics.Noop())
+		require.NoError(t, err)
+		t.Cleanup(func() {
+			r.CloseAsync()
+			assert.NoError(t, r.WaitForClose(time.Second))
+		})
+
+		ctx, done := context.WithTimeout(context.Background(), time.Second*10)
+		defer done()
+		require.NoError(t, r.ConnectWithContext(ctx))
+
+		var bodies []string
+		for len(bodies) < 2 {
+			msg, ackFn, err := r.ReadWithContext(ctx)
+			if err == types.ErrTimeout {
+				continue
+			}
+			require.NoError(t, err)
+			bodies = append(bodies, string(msg.Get(0).Get()))
+			require.NoError(t, ackFn(ctx, response.NewAck()))
+		}
+		assert.ElementsMatch(t, []string{"foo", "bar"}, bodies)
+
+		assert.Eventually(t, func() bool {
+			dlq, err := client.XRange("stream-claim-dlq", "-", "+").Result()
+			if err != nil || len(dlq) != 1 {
+				return false
+			}
+			pending, err := client.XPending("stream-claim-dead", "group-claim").Result()
+			return err == nil && pending.Count == 0 && dlq[0].Values["body"] == "baz"
+		}, time.Second*5, time.Millisecond*50)
+
+		assert.Eventually(t, func() bool {
+			pending, err := client.XPending("stream-claim", "group-claim").Result()
+			return err == nil && pending.Count == 0
+		}, time.Second*5, time.Millisecond*50)
+	})
+
 	t.Run("pubsub", func(t *testing.T) {
 		t.Parallel()
 		template := `
@@ -102,4 +102,38 @@ resources:
 			testOptVarTwo("true"),
 		)
 	})
+
+	t.Run("sftp move on finish", func(t *testing.T) {
+		template := `
+output:
+  sftp:
+    address: localhost:$PORT
+    path: /upload/test-$ID/${!uuid_v4()}.txt
+    credentials:
+      username: foo
+      password: pass
+    codec: $VAR1
+    max_in_flight: 1
+
+input:
+  sftp:
+    address: localhost:$PORT
+    paths:
+      - /upload/test-$ID/*.txt
+    credentials:
+      username: foo
+      password: pass
+    codec: $VAR1
+    move_on_finish: /upload/test-$ID/processed
+`
+		suite := integrationTests(
+			integrationTestOpenCloseIsolated(),
+			integrationTestStreamIsolated(100),
+		)
+		suite.Run(
+			t, template,
+			testOptPort(resource.GetPort("22/tcp")),
+			testOptVarOne("all-bytes"),
+		)
+	})
 })
@@ -11,6 +11,14 @@ import (
 // be interpretted as having failed a processor step somewhere in the pipeline.
 var FailFlagKey = "benthos_processing_failed"
 
+// FailLabelKey is a metadata key used for recording the label of the processor
+// that flagged the error of a message part, if the processor has a label.
+var FailLabelKey = "benthos_processing_failed_label"
+
+// FailPathKey is a metadata key used for recording the path of the processor
+// that flagged the error of a message part within a config.
+var FailPathKey = "benthos_processing_failed_path"
+
 //------------------------------------------------------------------------------
 
 // Metadata is an interface representing the metadata of a message part within
@@ -10,8 +10,8 @@ func FieldSpecs() docs.FieldSpecs {
 		docs.FieldAdvanced("credentials", "Optional manual configuration of AWS credentials to use. More information can be found [in this document](/docs/guides/aws).").WithChildren(
 			docs.FieldAdvanced("profile", "A profile from `~/.aws/credentials` to use."),
 			docs.FieldAdvanced("id", "The ID of credentials to use."),
-			docs.FieldAdvanced("secret", "The secret for the credentials being used."),
-			docs.FieldAdvanced("token", "The token for the credentials being used, required when using short term credentials."),
+			docs.FieldAdvanced("secret", "The secret for the credentials being used.").Secret(),
+			docs.FieldAdvanced("token", "The token for the credentials being used, required when using short term credentials.").Secret(),
 			docs.FieldAdvanced("role", "A role ARN to assume."),
 			docs.FieldAdvanced("role_external_id", "An external ID to provide when assuming a role."),
 		),
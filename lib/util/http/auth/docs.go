@@ -12,7 +12,7 @@ func BasicAuthFieldSpec() docs.FieldSpec {
 		).HasType(docs.FieldTypeBool).HasDefault(false),
 
 		docs.FieldString("username", "A username to authenticate as.").HasDefault(""),
-		docs.FieldString("password", "A password to authenticate with.").HasDefault(""),
+		docs.FieldString("password", "A password to authenticate with.").Secret().HasDefault(""),
 	)
 }
 
@@ -30,15 +30,15 @@ func oAuthFieldSpec() docs.FieldSpec {
 
 		docs.FieldString(
 			"consumer_secret", "A secret used to establish ownership of the consumer key.",
-		).HasDefault(""),
+		).Secret().HasDefault(""),
 
 		docs.FieldString(
 			"access_token", "A value used to gain access to the protected resources on behalf of the user.",
-		).HasDefault(""),
+		).Secret().HasDefault(""),
 
 		docs.FieldString(
 			"access_token_secret", "A secret provided in order to establish ownership of a given access token.",
-		).HasDefault(""),
+		).Secret().HasDefault(""),
 
 		docs.FieldString(
 			"request_url", "The URL of the OAuth provider.",
@@ -60,7 +60,7 @@ func oAuth2FieldSpec() docs.FieldSpec {
 
 		docs.FieldString(
 			"client_secret", "A secret used to establish ownership of the client key.",
-		).HasDefault(""),
+		).Secret().HasDefault(""),
 
 		docs.FieldString(
 			"token_url", "The URL of the token provider.",
@@ -2,6 +2,7 @@ package text
 
 import (
 	"bytes"
+	"fmt"
 	"os"
 	"regexp"
 	"strings"
@@ -30,8 +31,28 @@ func ContainsEnvVariables(inBytes []byte) bool {
 // the environment variable is empty or does not exist then either the default
 // value is used or the field will be left empty.
 func ReplaceEnvVariables(inBytes []byte) []byte {
+	replaced, _ := replaceEnvVariables(inBytes, false)
+	return replaced
+}
+
+// ReplaceEnvVariablesAndSecrets performs the same replacements as
+// ReplaceEnvVariables, but also resolves secret references of the form
+// `${file:/path/to/secret}` and `${vault:path/to/secret#key}`. An error is
+// returned if any secret reference cannot be resolved.
+func ReplaceEnvVariablesAndSecrets(inBytes []byte) ([]byte, error) {
+	return replaceEnvVariables(inBytes, true)
+}
+
+func replaceEnvVariables(inBytes []byte, resolveSecrets bool) ([]byte, error) {
+	var secrets *secretResolver
+	if resolveSecrets {
+		secrets = newSecretResolver()
+	}
+
+	var err error
 	replaced := envRegex.ReplaceAllFunc(inBytes, func(content []byte) []byte {
 		var value string
+		var isSecret bool
 		if len(content) > 3 {
 			if colonIndex := bytes.IndexByte(content, ':'); colonIndex == -1 {
 				value = os.Getenv(string(content[2 : len(content)-1]))
@@ -39,19 +60,36 @@ func ReplaceEnvVariables(inBytes []byte) []byte {
 				targetVar := content[2:colonIndex]
 				defaultVal := content[colonIndex+1 : len(content)-1]
 
-				value = os.Getenv(string(targetVar))
-				if value == "" {
-					value = string(defaultVal)
+				if secrets != nil && secrets.handles(string(targetVar)) {
+					var rerr error
+					if value, rerr = secrets.resolve(string(targetVar), string(defaultVal)); rerr != nil {
+						if err == nil {
+							err = fmt.Errorf("failed to resolve secret reference '%s': %w", content, rerr)
+						}
+						return nil
+					}
+					isSecret = true
+				} else {
+					value = os.Getenv(string(targetVar))
+					if value == "" {
+						value = string(defaultVal)
+					}
 				}
 			}
 			// Escape newlines, otherwise there's no way that they would work
 			// within a config.
 			value = strings.ReplaceAll(value, "\n", "\\n")
+			if isSecret {
+				registerSecret(value, string(content))
+			}
 		}
 		return []byte(value)
 	})
+	if err != nil {
+		return nil, err
+	}
 	replaced = escapedEnvRegex.ReplaceAll(replaced, []byte("$$$1"))
-	return replaced
+	return replaced, nil
 }
 
 //------------------------------------------------------------------------------
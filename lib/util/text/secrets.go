@@ -0,0 +1,173 @@
+package text
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+//------------------------------------------------------------------------------
+
+var (
+	resolvedSecrets    = map[string]string{}
+	resolvedSecretsMut sync.RWMutex
+)
+
+func registerSecret(value, reference string) {
+	if value == "" {
+		return
+	}
+	resolvedSecretsMut.Lock()
+	resolvedSecrets[value] = reference
+	resolvedSecretsMut.Unlock()
+}
+
+// RedactSecrets replaces any secret values resolved by
+// ReplaceEnvVariablesAndSecrets that occur within a string with the
+// references they were resolved from, which prevents secrets from being
+// exposed when a config is printed.
+func RedactSecrets(s string) string {
+	resolvedSecretsMut.RLock()
+	defer resolvedSecretsMut.RUnlock()
+
+	if len(resolvedSecrets) == 0 {
+		return s
+	}
+
+	// Replace longer values first in case a secret contains another.
+	values := make([]string, 0, len(resolvedSecrets))
+	for v := range resolvedSecrets {
+		values = append(values, v)
+	}
+	sort.Slice(values, func(i, j int) bool {
+		return len(values[i]) > len(values[j])
+	})
+	for _, v := range values {
+		s = strings.ReplaceAll(s, v, resolvedSecrets[v])
+	}
+	return s
+}
+
+//------------------------------------------------------------------------------
+
+// secretResolver resolves secret references from files and external stores,
+// caching the secrets read from vault so that multiple keys of the same
+// secret are obtained with a single request.
+type secretResolver struct {
+	vaultCache map[string]map[string]interface{}
+}
+
+func newSecretResolver() *secretResolver {
+	return &secretResolver{
+		vaultCache: map[string]map[string]interface{}{},
+	}
+}
+
+func (s *secretResolver) handles(kind string) bool {
+	return kind == "file" || kind == "vault"
+}
+
+func (s *secretResolver) resolve(kind, ref string) (string, error) {
+	switch kind {
+	case "file":
+		return s.resolveFile(ref)
+	case "vault":
+		return s.resolveVault(ref)
+	}
+	return "", fmt.Errorf("secret store %v not recognised", kind)
+}
+
+func (s *secretResolver) resolveFile(path string) (string, error) {
+	secretBytes, err := ioutil.ReadFile(path)
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimRight(string(secretBytes), "\r\n"), nil
+}
+
+func (s *secretResolver) resolveVault(ref string) (string, error) {
+	hashIndex := strings.LastIndex(ref, "#")
+	if hashIndex == -1 || hashIndex == len(ref)-1 {
+		return "", errors.New("expected a reference of the form path#key")
+	}
+	path, key := strings.Trim(ref[:hashIndex], "/"), ref[hashIndex+1:]
+
+	data, exists := s.vaultCache[path]
+	if !exists {
+		var err error
+		if data, err = readVaultSecret(path); err != nil {
+			return "", err
+		}
+		s.vaultCache[path] = data
+	}
+
+	v, exists := data[key]
+	if !exists {
+		return "", fmt.Errorf("key %v not found in secret", key)
+	}
+	if str, ok := v.(string); ok {
+		return str, nil
+	}
+	vBytes, err := json.Marshal(v)
+	if err != nil {
+		return "", err
+	}
+	return string(vBytes), nil
+}
+
+// readVaultSecret reads a secret from the vault server identified by the
+// standard VAULT_ADDR, VAULT_TOKEN and VAULT_NAMESPACE environment variables.
+// Secrets of both version 1 and version 2 key/value engines are supported.
+func readVaultSecret(path string) (map[string]interface{}, error) {
+	addr := os.Getenv("VAULT_ADDR")
+	if addr == "" {
+		addr = "https://127.0.0.1:8200"
+	}
+	token := os.Getenv("VAULT_TOKEN")
+	if token == "" {
+		return nil, errors.New("environment variable VAULT_TOKEN must be set")
+	}
+
+	req, err := http.NewRequest("GET", strings.TrimSuffix(addr, "/")+"/v1/"+path, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("X-Vault-Token", token)
+	if ns := os.Getenv("VAULT_NAMESPACE"); ns != "" {
+		req.Header.Set("X-Vault-Namespace", ns)
+	}
+
+	client := http.Client{Timeout: time.Second * 10}
+	res, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("vault returned unexpected status code: %v", res.StatusCode)
+	}
+
+	var body struct {
+		Data map[string]interface{} `json:"data"`
+	}
+	if err = json.NewDecoder(res.Body).Decode(&body); err != nil {
+		return nil, fmt.Errorf("failed to parse vault response: %w", err)
+	}
+
+	// Version 2 key/value secrets are nested within a data field alongside
+	// metadata.
+	if inner, ok := body.Data["data"].(map[string]interface{}); ok {
+		if _, hasMeta := body.Data["metadata"]; hasMeta {
+			return inner, nil
+		}
+	}
+	return body.Data, nil
+}
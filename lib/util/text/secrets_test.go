@@ -0,0 +1,88 @@
+package text
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSecretsFromFiles(t *testing.T) {
+	dir := t.TempDir()
+
+	fooPath := filepath.Join(dir, "foo")
+	require.NoError(t, os.WriteFile(fooPath, []byte("foo secret value\n"), 0644))
+
+	barPath := filepath.Join(dir, "bar")
+	require.NoError(t, os.WriteFile(barPath, []byte("bar\nsecret"), 0644))
+
+	out, err := ReplaceEnvVariablesAndSecrets([]byte(`
+a: ${file:` + fooPath + `}
+b: "${file:` + barPath + `}"
+c: ${{file:` + fooPath + `}}
+`))
+	require.NoError(t, err)
+	assert.Equal(t, `
+a: foo secret value
+b: "bar\nsecret"
+c: ${file:`+fooPath+`}
+`, string(out))
+
+	assert.Equal(t, "a: ${file:"+fooPath+"}", RedactSecrets("a: foo secret value"))
+	assert.Equal(t, `b: "${file:`+barPath+`}"`, RedactSecrets(`b: "bar\nsecret"`))
+
+	// Secrets are not resolved by plain env variable replacement.
+	assert.Equal(t, "a: "+fooPath, string(ReplaceEnvVariables([]byte("a: ${file:"+fooPath+"}"))))
+
+	_, err = ReplaceEnvVariablesAndSecrets([]byte(`a: ${file:` + filepath.Join(dir, "nope") + `}`))
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "failed to resolve secret reference")
+}
+
+func TestSecretsFromVault(t *testing.T) {
+	var requests int
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if r.Header.Get("X-Vault-Token") != "footoken" {
+			http.Error(w, "nope", http.StatusForbidden)
+			return
+		}
+		switch r.URL.Path {
+		case "/v1/secret/data/foo":
+			w.Write([]byte(`{"data":{"data":{"user":"foouser","password":"foopass","port":5432},"metadata":{"version":1}}}`))
+		case "/v1/kv/bar":
+			w.Write([]byte(`{"data":{"token":"bartoken"}}`))
+		default:
+			http.Error(w, "not found", http.StatusNotFound)
+		}
+	}))
+	defer ts.Close()
+
+	t.Setenv("VAULT_ADDR", ts.URL)
+	t.Setenv("VAULT_TOKEN", "footoken")
+
+	out, err := ReplaceEnvVariablesAndSecrets([]byte(`dsn: ${vault:secret/data/foo#user}:${vault:secret/data/foo#password}@localhost:${vault:secret/data/foo#port}
+token: ${vault:kv/bar#token}`))
+	require.NoError(t, err)
+	assert.Equal(t, `dsn: foouser:foopass@localhost:5432
+token: bartoken`, string(out))
+	assert.Equal(t, 2, requests)
+
+	for _, ref := range []string{
+		"${vault:secret/data/foo}",
+		"${vault:secret/data/foo#nope}",
+		"${vault:secret/data/nope#user}",
+	} {
+		_, err = ReplaceEnvVariablesAndSecrets([]byte(ref))
+		assert.Error(t, err, ref)
+	}
+
+	t.Setenv("VAULT_TOKEN", "")
+	_, err = ReplaceEnvVariablesAndSecrets([]byte(`${vault:kv/bar#token}`))
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "VAULT_TOKEN")
+}
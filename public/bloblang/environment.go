@@ -62,6 +62,23 @@ func (e *Environment) Parse(blobl string) (*Executor, error) {
 	return newExecutor(exec), nil
 }
 
+// Format a Bloblang mapping in the canonical style, where lines are indented by
+// two spaces for each level of nesting, tokens are consistently spaced and long
+// chains of method calls are wrapped. The mapping is parsed using the
+// Environment before being formatted.
+//
+// When a parsing error occurs the error will be the type *ParseError.
+func (e *Environment) Format(blobl string) (string, error) {
+	formatted, err := e.env.FormatMapping("", blobl)
+	if err != nil {
+		if pErr, ok := err.(*parser.Error); ok {
+			return "", internalToPublicParserError([]rune(blobl), pErr)
+		}
+		return "", err
+	}
+	return formatted, nil
+}
+
 // RegisterMethod adds a new Bloblang method to the environment. All method
 // names must match the regular expression /^[a-z0-9]+(_[a-z0-9]+)*$/ (snake
 // case).
@@ -136,6 +153,59 @@ func (e *Environment) RegisterFunctionV2(spec ParamsSpec, ctor FunctionConstruct
 	})
 }
 
+// Namespace returns a handle for registering functions and methods to the
+// environment under a namespace, where each plugin is referenced within a
+// mapping by its name prefixed with the namespace and a dot, e.g. a function
+// `lookup_sku` registered under the namespace `acme` would be called with
+// `acme.lookup_sku()`.
+//
+// Namespaces make it possible for plugins from different sources to share an
+// environment without their names colliding with each other or with any
+// functions and methods added to Bloblang in the future. Namespace names must
+// match the regular expression /^[a-z0-9]+(_[a-z0-9]+)*$/ (snake case).
+func (e *Environment) Namespace(name string) *Namespace {
+	return &Namespace{env: e, name: name}
+}
+
+// Namespace provides APIs for registering functions and methods to an
+// environment under a namespace.
+type Namespace struct {
+	env  *Environment
+	name string
+}
+
+// RegisterMethodV2 adds a new Bloblang method to the environment under the
+// namespace, using a provided ParamsSpec to define the name of the method and
+// its parameters.
+func (n *Namespace) RegisterMethodV2(spec ParamsSpec, ctor MethodConstructorV2) error {
+	spec.name = n.name + "." + spec.name
+	return n.env.RegisterMethodV2(spec, ctor)
+}
+
+// RegisterFunctionV2 adds a new Bloblang function to the environment under the
+// namespace, using a provided ParamsSpec to define the name of the function
+// and its parameters.
+func (n *Namespace) RegisterFunctionV2(spec ParamsSpec, ctor FunctionConstructorV2) error {
+	spec.name = n.name + "." + spec.name
+	return n.env.RegisterFunctionV2(spec, ctor)
+}
+
+// RegisterMethodAlias adds a deprecated alias to the environment that forwards
+// to an existing method, which allows a method to be renamed without breaking
+// mappings that use the old name. Mappings that use the alias are flagged by
+// the linter with a suggestion to use the new name.
+func (e *Environment) RegisterMethodAlias(alias, target string) error {
+	return e.env.RegisterMethodAlias(alias, target)
+}
+
+// RegisterFunctionAlias adds a deprecated alias to the environment that
+// forwards to an existing function, which allows a function to be renamed
+// without breaking mappings that use the old name. Mappings that use the alias
+// are flagged by the linter with a suggestion to use the new name.
+func (e *Environment) RegisterFunctionAlias(alias, target string) error {
+	return e.env.RegisterFunctionAlias(alias, target)
+}
+
 // WithoutMethods returns a copy of the environment but with a variadic list of
 // method names removed. Instantiation of these removed methods within a mapping
 // will cause errors at parse time.
@@ -170,6 +240,20 @@ func Parse(blobl string) (*Executor, error) {
 	return newExecutor(exec), nil
 }
 
+// Format a Bloblang mapping in the canonical style, where lines are indented by
+// two spaces for each level of nesting, tokens are consistently spaced and long
+// chains of method calls are wrapped. The mapping is parsed using the globally
+// accessible range of features before being formatted.
+//
+// When a parsing error occurs the error will be the type *ParseError.
+func Format(blobl string) (string, error) {
+	formatted, err := parser.FormatMapping(parser.GlobalContext(), "", blobl)
+	if err != nil {
+		return "", internalToPublicParserError([]rune(blobl), err)
+	}
+	return formatted, nil
+}
+
 // RegisterMethod adds a new Bloblang method to the global environment. All
 // method names must match the regular expression /^[a-z0-9]+(_[a-z0-9]+)*$/
 // (snake case).
@@ -98,3 +98,105 @@ func TestEmptyEnvironment(t *testing.T) {
 	require.NoError(t, err)
 	assert.Equal(t, "foo:hello world", v)
 }
+
+func TestEnvironmentNamespace(t *testing.T) {
+	env := NewEnvironment()
+	acme := env.Namespace("acme")
+
+	require.NoError(t, acme.RegisterFunctionV2(
+		NewParamsSpec("lookup_sku", "").Add(ParamString("id", "")),
+		func(args *ParsedParams) (Function, error) {
+			id, err := args.FieldString("id")
+			if err != nil {
+				return nil, err
+			}
+			return func() (interface{}, error) {
+				return "sku:" + id, nil
+			}, nil
+		},
+	))
+
+	require.NoError(t, acme.RegisterMethodV2(NewParamsSpec("shout", ""), func(_ *ParsedParams) (Method, error) {
+		return StringMethod(func(s string) (interface{}, error) {
+			return s + "!", nil
+		}), nil
+	}))
+
+	assert.EqualError(t, env.Namespace("acme").RegisterMethodV2(NewParamsSpec("shout", ""), nil), "conflicting method name: acme.shout")
+	assert.Error(t, env.Namespace("not.valid").RegisterFunctionV2(NewParamsSpec("foo", ""), nil))
+
+	exe, err := env.Parse(`
+root.a = acme.lookup_sku(id: "foo")
+root.b = "hello".acme.shout().uppercase()
+root.c = this.acme.uppercase()
+root.d = acme.uppercase()
+`)
+	require.NoError(t, err)
+
+	v, err := exe.Query(map[string]interface{}{
+		"acme": "this is a field",
+	})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{
+		"a": "sku:foo",
+		"b": "HELLO!",
+		"c": "THIS IS A FIELD",
+		"d": "THIS IS A FIELD",
+	}, v)
+
+	_, err = env.Parse(`root = acme.nope()`)
+	assert.EqualError(t, err, "unrecognised method 'nope': nope(")
+}
+
+func TestEnvironmentAliases(t *testing.T) {
+	env := NewEnvironment()
+
+	require.NoError(t, env.RegisterMethodV2(NewParamsSpec("shout", ""), func(_ *ParsedParams) (Method, error) {
+		return StringMethod(func(s string) (interface{}, error) {
+			return s + "!", nil
+		}), nil
+	}))
+	require.NoError(t, env.RegisterMethodAlias("yell", "shout"))
+	require.NoError(t, env.RegisterFunctionAlias("hostname_v1", "hostname"))
+
+	assert.EqualError(t, env.RegisterMethodAlias("yell", "uppercase"), "conflicting method name: yell")
+	assert.EqualError(t, env.RegisterFunctionAlias("foo", "nope"), "function 'nope' cannot be aliased as it does not exist")
+
+	exe, err := env.Parse(`root = this.yell()`)
+	require.NoError(t, err)
+
+	v, err := exe.Query("hello")
+	require.NoError(t, err)
+	assert.Equal(t, "hello!", v)
+
+	_, err = NewEnvironment().Parse(`root = this.yell()`)
+	assert.Error(t, err)
+
+	_, err = env.WithoutFunctions("hostname").Parse(`root = hostname_v1()`)
+	assert.Error(t, err)
+}
+
+func TestEnvironmentFormat(t *testing.T) {
+	env := NewEmptyEnvironment()
+
+	require.NoError(t, env.RegisterMethod("foo", func(_ ...interface{}) (Method, error) {
+		return StringMethod(func(s string) (interface{}, error) {
+			return "foo:" + s, nil
+		}), nil
+	}))
+
+	formatted, err := env.Format(`root   = {"a":this.a.foo(),"b":[1,2]}`)
+	require.NoError(t, err)
+	assert.Equal(t, "root = {\"a\": this.a.foo(), \"b\": [1, 2]}\n", formatted)
+
+	_, err = env.Format(`root = this.a.uppercase()`)
+	require.Error(t, err)
+	pErr, ok := err.(*ParseError)
+	require.True(t, ok)
+	assert.Equal(t, 1, pErr.Line)
+	assert.Equal(t, 15, pErr.Column)
+
+	formatted, err = Format(`root = this.a.uppercase( )`)
+	require.NoError(t, err)
+	assert.Equal(t, "root = this.a.uppercase()\n", formatted)
+}
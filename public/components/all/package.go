@@ -15,6 +15,8 @@ import (
 	_ "github.com/Jeffail/benthos/v3/internal/impl/mongodb"
 	_ "github.com/Jeffail/benthos/v3/internal/impl/nats"
 	_ "github.com/Jeffail/benthos/v3/internal/impl/pulsar"
+	_ "github.com/Jeffail/benthos/v3/internal/impl/sql"
+	_ "github.com/Jeffail/benthos/v3/internal/impl/vault"
 	"github.com/Jeffail/benthos/v3/internal/template"
 )
 
@@ -90,6 +90,14 @@ func (c *ConfigField) Advanced() *ConfigField {
 	return c
 }
 
+// Secret marks a config field as containing a secret, such as a password or an
+// access token, and therefore its value will be scrubbed when the config is
+// printed.
+func (c *ConfigField) Secret() *ConfigField {
+	c.field = c.field.Secret()
+	return c
+}
+
 // Default specifies a default value that this field will assume if it is
 // omitted from a provided config. Fields that do not have a default value are
 // considered mandatory, and so parsing a config will fail in their absence.
@@ -75,7 +75,7 @@ c:
     e: evalue
 `,
 			lints: []docs.Lint{
-				docs.NewLintError(2, "field not_real not recognised"),
+				{Line: 2, Column: 1, Level: docs.LintError, Type: docs.LintUnknown, What: "field not_real not recognised"},
 			},
 		},
 		{
@@ -92,7 +92,7 @@ c:
     e: evalue
 `,
 			lints: []docs.Lint{
-				docs.NewLintError(4, "field not_real not recognised"),
+				{Line: 4, Column: 3, Level: docs.LintError, Type: docs.LintUnknown, What: "field not_real not recognised"},
 			},
 		},
 	}
@@ -176,7 +176,7 @@ c:
     e: evalue
 `,
 			lints: []docs.Lint{
-				docs.NewLintError(2, "field not_real not recognised"),
+				{Line: 2, Column: 1, Level: docs.LintError, Type: docs.LintUnknown, What: "field not_real not recognised"},
 			},
 		},
 		{
@@ -193,7 +193,7 @@ c:
     e: evalue
 `,
 			lints: []docs.Lint{
-				docs.NewLintError(4, "field not_real not recognised"),
+				{Line: 4, Column: 3, Level: docs.LintError, Type: docs.LintUnknown, What: "field not_real not recognised"},
 			},
 		},
 	}
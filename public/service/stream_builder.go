@@ -689,6 +689,7 @@ func (s *StreamBuilder) Build() (*Stream, error) {
 		if err == nil {
 			_ = config.Spec().SanitiseYAML(&sanitNode, docs.SanitiseConfig{
 				RemoveTypeField: true,
+				ScrubSecrets:    true,
 				DocsProvider:    s.env.internal,
 			})
 		}